@@ -0,0 +1,282 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Policy to the Hub version (v1beta1).
+func (src *Policy) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Policy)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.PolicySpec{
+		IsDefault:               src.Spec.IsDefault,
+		RiskIndex:               src.Spec.RiskIndex,
+		MinReplicaPercentageCut: src.Spec.MinReplicaPercentageCut,
+		TargetUtilization:       src.Spec.TargetUtilization,
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *Policy) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Policy)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = PolicySpec{
+		IsDefault:               src.Spec.IsDefault,
+		RiskIndex:               src.Spec.RiskIndex,
+		MinReplicaPercentageCut: src.Spec.MinReplicaPercentageCut,
+		TargetUtilization:       src.Spec.TargetUtilization,
+	}
+	return nil
+}
+
+// ConvertTo converts this PolicyRecommendation to the Hub version (v1beta1).
+func (src *PolicyRecommendation) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.PolicyRecommendation)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.PolicyRecommendationSpec{
+		WorkloadMeta: v1beta1.WorkloadMeta{
+			TypeMeta: src.Spec.WorkloadMeta.TypeMeta,
+			Name:     src.Spec.WorkloadMeta.Name,
+		},
+		TargetHPAConfiguration:  hpaConfigurationToHub(src.Spec.TargetHPAConfiguration),
+		CurrentHPAConfiguration: hpaConfigurationToHub(src.Spec.CurrentHPAConfiguration),
+		Policy:                  src.Spec.Policy,
+		GeneratedAt:             src.Spec.GeneratedAt,
+		TransitionedAt:          src.Spec.TransitionedAt,
+		QueuedForExecution:      src.Spec.QueuedForExecution,
+		QueuedForExecutionAt:    src.Spec.QueuedForExecutionAt,
+		MinReplicaFloor:         src.Spec.MinReplicaFloor,
+		MaxReplicaCeiling:       src.Spec.MaxReplicaCeiling,
+		MaxTargetUtilization:    src.Spec.MaxTargetUtilization,
+		CronTriggers:            cronTriggersToHub(src.Spec.CronTriggers),
+		IdleWindow:              idleWindowToHub(src.Spec.IdleWindow),
+		PinnedHPAConfiguration:  pinnedHPAConfigurationToHub(src.Spec.PinnedHPAConfiguration),
+	}
+	dst.Status = v1beta1.PolicyRecommendationStatus{
+		Conditions:                src.Status.Conditions,
+		GeneratedAt:               src.Status.GeneratedAt,
+		MetricsWindowStart:        src.Status.MetricsWindowStart,
+		MetricsWindowEnd:          src.Status.MetricsWindowEnd,
+		DataPointsCoveragePercent: src.Status.DataPointsCoveragePercent,
+		ProjectedSavingsPercent:   src.Status.ProjectedSavingsPercent,
+		ConfidencePercent:         src.Status.ConfidencePercent,
+		BreachFreeMaxReplicas:     src.Status.BreachFreeMaxReplicas,
+		SearchSpace:               searchSpaceToHub(src.Status.SearchSpace),
+		StaleDataSince:            src.Status.StaleDataSince,
+		StaleRecommendations:      src.Status.StaleRecommendations,
+		ChangeRequest:             changeRequestToHub(src.Status.ChangeRequest),
+		ShadowAdoptionSince:       src.Status.ShadowAdoptionSince,
+		ShadowComparison:          shadowComparisonToHub(src.Status.ShadowComparison),
+		EnforcementPreview:        enforcementPreviewToHub(src.Status.EnforcementPreview),
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *PolicyRecommendation) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.PolicyRecommendation)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = PolicyRecommendationSpec{
+		WorkloadMeta: WorkloadMeta{
+			TypeMeta: src.Spec.WorkloadMeta.TypeMeta,
+			Name:     src.Spec.WorkloadMeta.Name,
+		},
+		TargetHPAConfiguration:  hpaConfigurationFromHub(src.Spec.TargetHPAConfiguration),
+		CurrentHPAConfiguration: hpaConfigurationFromHub(src.Spec.CurrentHPAConfiguration),
+		Policy:                  src.Spec.Policy,
+		GeneratedAt:             src.Spec.GeneratedAt,
+		TransitionedAt:          src.Spec.TransitionedAt,
+		QueuedForExecution:      src.Spec.QueuedForExecution,
+		QueuedForExecutionAt:    src.Spec.QueuedForExecutionAt,
+		MinReplicaFloor:         src.Spec.MinReplicaFloor,
+		MaxReplicaCeiling:       src.Spec.MaxReplicaCeiling,
+		MaxTargetUtilization:    src.Spec.MaxTargetUtilization,
+		CronTriggers:            cronTriggersFromHub(src.Spec.CronTriggers),
+		IdleWindow:              idleWindowFromHub(src.Spec.IdleWindow),
+		PinnedHPAConfiguration:  pinnedHPAConfigurationFromHub(src.Spec.PinnedHPAConfiguration),
+	}
+	dst.Status = PolicyRecommendationStatus{
+		Conditions:                src.Status.Conditions,
+		GeneratedAt:               src.Status.GeneratedAt,
+		MetricsWindowStart:        src.Status.MetricsWindowStart,
+		MetricsWindowEnd:          src.Status.MetricsWindowEnd,
+		DataPointsCoveragePercent: src.Status.DataPointsCoveragePercent,
+		ProjectedSavingsPercent:   src.Status.ProjectedSavingsPercent,
+		ConfidencePercent:         src.Status.ConfidencePercent,
+		BreachFreeMaxReplicas:     src.Status.BreachFreeMaxReplicas,
+		SearchSpace:               searchSpaceFromHub(src.Status.SearchSpace),
+		StaleDataSince:            src.Status.StaleDataSince,
+		StaleRecommendations:      src.Status.StaleRecommendations,
+		ChangeRequest:             changeRequestFromHub(src.Status.ChangeRequest),
+		ShadowAdoptionSince:       src.Status.ShadowAdoptionSince,
+		ShadowComparison:          shadowComparisonFromHub(src.Status.ShadowComparison),
+		EnforcementPreview:        enforcementPreviewFromHub(src.Status.EnforcementPreview),
+	}
+	return nil
+}
+
+func hpaConfigurationToHub(h HPAConfiguration) v1beta1.HPAConfiguration {
+	return v1beta1.HPAConfiguration{
+		Min:               h.Min,
+		Max:               h.Max,
+		TargetMetricValue: h.TargetMetricValue,
+		MetricName:        h.MetricName,
+		TargetMetricType:  v1beta1.MetricTargetType(h.TargetMetricType),
+	}
+}
+
+func hpaConfigurationFromHub(h v1beta1.HPAConfiguration) HPAConfiguration {
+	return HPAConfiguration{
+		Min:               h.Min,
+		Max:               h.Max,
+		TargetMetricValue: h.TargetMetricValue,
+		MetricName:        h.MetricName,
+		TargetMetricType:  MetricTargetType(h.TargetMetricType),
+	}
+}
+
+func pinnedHPAConfigurationToHub(h *HPAConfiguration) *v1beta1.HPAConfiguration {
+	if h == nil {
+		return nil
+	}
+	hub := hpaConfigurationToHub(*h)
+	return &hub
+}
+
+func pinnedHPAConfigurationFromHub(h *v1beta1.HPAConfiguration) *HPAConfiguration {
+	if h == nil {
+		return nil
+	}
+	config := hpaConfigurationFromHub(*h)
+	return &config
+}
+
+func cronTriggersToHub(triggers []CronTrigger) []v1beta1.CronTrigger {
+	if triggers == nil {
+		return nil
+	}
+	hubTriggers := make([]v1beta1.CronTrigger, len(triggers))
+	for i, t := range triggers {
+		hubTriggers[i] = v1beta1.CronTrigger{Start: t.Start, End: t.End, Timezone: t.Timezone, DesiredReplicas: t.DesiredReplicas}
+	}
+	return hubTriggers
+}
+
+func cronTriggersFromHub(hubTriggers []v1beta1.CronTrigger) []CronTrigger {
+	if hubTriggers == nil {
+		return nil
+	}
+	triggers := make([]CronTrigger, len(hubTriggers))
+	for i, t := range hubTriggers {
+		triggers[i] = CronTrigger{Start: t.Start, End: t.End, Timezone: t.Timezone, DesiredReplicas: t.DesiredReplicas}
+	}
+	return triggers
+}
+
+func idleWindowToHub(window *IdleWindow) *v1beta1.IdleWindow {
+	if window == nil {
+		return nil
+	}
+	return &v1beta1.IdleWindow{Start: window.Start, End: window.End, Timezone: window.Timezone, MinReplicas: window.MinReplicas}
+}
+
+func idleWindowFromHub(hubWindow *v1beta1.IdleWindow) *IdleWindow {
+	if hubWindow == nil {
+		return nil
+	}
+	return &IdleWindow{Start: hubWindow.Start, End: hubWindow.End, Timezone: hubWindow.Timezone, MinReplicas: hubWindow.MinReplicas}
+}
+
+func searchSpaceToHub(points []SearchSpacePoint) []v1beta1.SearchSpacePoint {
+	if points == nil {
+		return nil
+	}
+	hubPoints := make([]v1beta1.SearchSpacePoint, len(points))
+	for i, p := range points {
+		hubPoints[i] = v1beta1.SearchSpacePoint{MinReplicas: p.MinReplicas, TargetUtilization: p.TargetUtilization,
+			ProjectedSavingsPercent: p.ProjectedSavingsPercent}
+	}
+	return hubPoints
+}
+
+func searchSpaceFromHub(hubPoints []v1beta1.SearchSpacePoint) []SearchSpacePoint {
+	if hubPoints == nil {
+		return nil
+	}
+	points := make([]SearchSpacePoint, len(hubPoints))
+	for i, p := range hubPoints {
+		points[i] = SearchSpacePoint{MinReplicas: p.MinReplicas, TargetUtilization: p.TargetUtilization,
+			ProjectedSavingsPercent: p.ProjectedSavingsPercent}
+	}
+	return points
+}
+
+func changeRequestToHub(changeRequest *ChangeRequest) *v1beta1.ChangeRequest {
+	if changeRequest == nil {
+		return nil
+	}
+	return &v1beta1.ChangeRequest{TicketID: changeRequest.TicketID, Policy: changeRequest.Policy,
+		Status: v1beta1.ChangeRequestStatus(changeRequest.Status), SubmittedAt: changeRequest.SubmittedAt,
+		EnforcedAt: changeRequest.EnforcedAt}
+}
+
+func changeRequestFromHub(hubChangeRequest *v1beta1.ChangeRequest) *ChangeRequest {
+	if hubChangeRequest == nil {
+		return nil
+	}
+	return &ChangeRequest{TicketID: hubChangeRequest.TicketID, Policy: hubChangeRequest.Policy,
+		Status: ChangeRequestStatus(hubChangeRequest.Status), SubmittedAt: hubChangeRequest.SubmittedAt,
+		EnforcedAt: hubChangeRequest.EnforcedAt}
+}
+
+func shadowComparisonToHub(comparison *ShadowComparison) *v1beta1.ShadowComparison {
+	if comparison == nil {
+		return nil
+	}
+	return &v1beta1.ShadowComparison{WindowStart: comparison.WindowStart, WindowEnd: comparison.WindowEnd,
+		HPAReplicaHours: comparison.HPAReplicaHours, ShadowReplicaHours: comparison.ShadowReplicaHours,
+		HPABreaches: comparison.HPABreaches, ShadowBreaches: comparison.ShadowBreaches}
+}
+
+func shadowComparisonFromHub(hubComparison *v1beta1.ShadowComparison) *ShadowComparison {
+	if hubComparison == nil {
+		return nil
+	}
+	return &ShadowComparison{WindowStart: hubComparison.WindowStart, WindowEnd: hubComparison.WindowEnd,
+		HPAReplicaHours: hubComparison.HPAReplicaHours, ShadowReplicaHours: hubComparison.ShadowReplicaHours,
+		HPABreaches: hubComparison.HPABreaches, ShadowBreaches: hubComparison.ShadowBreaches}
+}
+
+func enforcementPreviewToHub(preview *EnforcementPreview) *v1beta1.EnforcementPreview {
+	if preview == nil {
+		return nil
+	}
+	return &v1beta1.EnforcementPreview{Autoscaler: preview.Autoscaler, Patch: preview.Patch,
+		PreviewedAt: preview.PreviewedAt}
+}
+
+func enforcementPreviewFromHub(hubPreview *v1beta1.EnforcementPreview) *EnforcementPreview {
+	if hubPreview == nil {
+		return nil
+	}
+	return &EnforcementPreview{Autoscaler: hubPreview.Autoscaler, Patch: hubPreview.Patch,
+		PreviewedAt: hubPreview.PreviewedAt}
+}
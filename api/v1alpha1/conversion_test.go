@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Conversion", func() {
+	trueBool := true
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+
+	Context("Policy", func() {
+		It("Should round trip through the hub version", func() {
+			policy := &Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: "safest-policy"},
+				Spec: PolicySpec{
+					IsDefault:               true,
+					RiskIndex:               1,
+					MinReplicaPercentageCut: 80,
+					TargetUtilization:       10,
+				},
+			}
+
+			Expect(unsetFields(reflect.ValueOf(policy.Spec), "spec")).To(BeEmpty())
+
+			hub := &v1beta1.Policy{}
+			Expect(policy.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Name).To(Equal("safest-policy"))
+			Expect(hub.Spec.IsDefault).To(BeTrue())
+			Expect(hub.Spec.RiskIndex).To(Equal(1))
+			Expect(hub.Spec.MinReplicaPercentageCut).To(Equal(80))
+			Expect(hub.Spec.TargetUtilization).To(Equal(10))
+
+			converted := &Policy{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(policy))
+		})
+	})
+
+	Context("PolicyRecommendation", func() {
+		It("Should round trip through the hub version", func() {
+			floor, ceiling, maxUtil := 2, 30, 70
+			coverage, savings, confidence, stale := 95, 40, 72, 2
+			policyreco := &PolicyRecommendation{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+				Spec: PolicyRecommendationSpec{
+					WorkloadMeta: WorkloadMeta{
+						TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+						Name:     "test-deployment",
+					},
+					TargetHPAConfiguration:  HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 60, MetricName: "cpu", TargetMetricType: UtilizationMetricTarget},
+					CurrentHPAConfiguration: HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 40, MetricName: "http_requests", TargetMetricType: AverageValueMetricTarget},
+					Policy:                  "safest-policy",
+					GeneratedAt:             &now,
+					TransitionedAt:          &now,
+					QueuedForExecution:      &trueBool,
+					QueuedForExecutionAt:    &now,
+					MinReplicaFloor:         &floor,
+					MaxReplicaCeiling:       &ceiling,
+					MaxTargetUtilization:    &maxUtil,
+					CronTriggers: []CronTrigger{
+						{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "Asia/Kolkata", DesiredReplicas: 15},
+					},
+					IdleWindow:             &IdleWindow{Start: "0 1 * * *", End: "0 6 * * *", Timezone: "Asia/Kolkata", MinReplicas: 3},
+					PinnedHPAConfiguration: &HPAConfiguration{Min: 8, Max: 20, TargetMetricValue: 50, MetricName: "cpu", TargetMetricType: UtilizationMetricTarget},
+				},
+				Status: PolicyRecommendationStatus{
+					Conditions: []metav1.Condition{{
+						Type:               string(Initialized),
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: now,
+						Reason:             "PolicyRecommendationCreated",
+					}},
+					GeneratedAt:               &now,
+					MetricsWindowStart:        &now,
+					MetricsWindowEnd:          &now,
+					DataPointsCoveragePercent: &coverage,
+					ProjectedSavingsPercent:   &savings,
+					ConfidencePercent:         &confidence,
+					BreachFreeMaxReplicas:     &ceiling,
+					StaleDataSince:            &now,
+					StaleRecommendations:      &stale,
+					ChangeRequest: &ChangeRequest{TicketID: "CHG0012345", Policy: "aggressive-policy",
+						Status: ChangeRequestApproved, SubmittedAt: now, EnforcedAt: &now},
+					ShadowAdoptionSince: &now,
+					ShadowComparison: &ShadowComparison{WindowStart: now, WindowEnd: now, HPAReplicaHours: 1680,
+						ShadowReplicaHours: 1120, HPABreaches: 3, ShadowBreaches: 1},
+					EnforcementPreview: &EnforcementPreview{Autoscaler: "ScaledObject/test-deployment",
+						Patch: `{"spec":{"minReplicaCount":4}}`, PreviewedAt: now},
+					SearchSpace: []SearchSpacePoint{{MinReplicas: 4, TargetUtilization: 55, ProjectedSavingsPercent: 38},
+						{MinReplicas: 5, TargetUtilization: 60, ProjectedSavingsPercent: 40}},
+				},
+			}
+
+			// the fields missing from the fixture would go unnoticed if the conversion dropped them
+			Expect(unsetFields(reflect.ValueOf(policyreco.Spec), "spec")).To(BeEmpty())
+			Expect(unsetFields(reflect.ValueOf(policyreco.Status), "status")).To(BeEmpty())
+
+			hub := &v1beta1.PolicyRecommendation{}
+			Expect(policyreco.ConvertTo(hub)).To(Succeed())
+			Expect(unsetFields(reflect.ValueOf(hub.Spec), "spec")).To(BeEmpty())
+			Expect(unsetFields(reflect.ValueOf(hub.Status), "status")).To(BeEmpty())
+			Expect(hub.Spec.WorkloadMeta.Name).To(Equal("test-deployment"))
+			Expect(hub.Spec.WorkloadMeta.Kind).To(Equal("Deployment"))
+			Expect(hub.Spec.TargetHPAConfiguration).To(Equal(v1beta1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 60, MetricName: "cpu", TargetMetricType: v1beta1.UtilizationMetricTarget}))
+			Expect(hub.Spec.CurrentHPAConfiguration).To(Equal(v1beta1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 40, MetricName: "http_requests", TargetMetricType: v1beta1.AverageValueMetricTarget}))
+			Expect(*hub.Spec.MinReplicaFloor).To(Equal(2))
+			Expect(*hub.Spec.MaxReplicaCeiling).To(Equal(30))
+			Expect(*hub.Spec.MaxTargetUtilization).To(Equal(70))
+			Expect(hub.Spec.CronTriggers).To(Equal([]v1beta1.CronTrigger{
+				{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "Asia/Kolkata", DesiredReplicas: 15},
+			}))
+			Expect(*hub.Spec.IdleWindow).To(Equal(v1beta1.IdleWindow{Start: "0 1 * * *", End: "0 6 * * *", Timezone: "Asia/Kolkata", MinReplicas: 3}))
+			Expect(*hub.Spec.PinnedHPAConfiguration).To(Equal(v1beta1.HPAConfiguration{Min: 8, Max: 20, TargetMetricValue: 50, MetricName: "cpu", TargetMetricType: v1beta1.UtilizationMetricTarget}))
+			Expect(hub.Status.Conditions).To(HaveLen(1))
+			Expect(*hub.Status.DataPointsCoveragePercent).To(Equal(95))
+			Expect(*hub.Status.ProjectedSavingsPercent).To(Equal(40))
+			Expect(*hub.Status.ConfidencePercent).To(Equal(72))
+			Expect(*hub.Status.BreachFreeMaxReplicas).To(Equal(30))
+			Expect(hub.Status.SearchSpace).To(Equal([]v1beta1.SearchSpacePoint{
+				{MinReplicas: 4, TargetUtilization: 55, ProjectedSavingsPercent: 38},
+				{MinReplicas: 5, TargetUtilization: 60, ProjectedSavingsPercent: 40},
+			}))
+			Expect(*hub.Status.StaleRecommendations).To(Equal(2))
+			Expect(hub.Status.ChangeRequest.TicketID).To(Equal("CHG0012345"))
+			Expect(hub.Status.ChangeRequest.EnforcedAt).To(Equal(&now))
+			Expect(hub.Status.ShadowComparison.ShadowReplicaHours).To(Equal(int64(1120)))
+			Expect(hub.Status.EnforcementPreview.Patch).To(Equal(`{"spec":{"minReplicaCount":4}}`))
+
+			converted := &PolicyRecommendation{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(policyreco))
+
+			convertedHub := &v1beta1.PolicyRecommendation{}
+			Expect(converted.ConvertTo(convertedHub)).To(Succeed())
+			Expect(convertedHub).To(Equal(hub))
+		})
+	})
+})
+
+// unsetFields returns the paths of the fields of the API types of v1alpha1 and v1beta1 which are left unset, walking
+// into their structs, pointers and the first items of their slices.
+func unsetFields(v reflect.Value, path string) []string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return []string{path}
+		}
+		return unsetFields(v.Elem(), path)
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return []string{path}
+		}
+		return unsetFields(v.Index(0), path+"[0]")
+	case reflect.Struct:
+		if pkg := v.Type().PkgPath(); pkg != reflect.TypeOf(Policy{}).PkgPath() &&
+			pkg != reflect.TypeOf(v1beta1.Policy{}).PkgPath() {
+			if v.IsZero() {
+				return []string{path}
+			}
+			return nil
+		}
+		var unset []string
+		for i := 0; i < v.NumField(); i++ {
+			unset = append(unset, unsetFields(v.Field(i), path+"."+v.Type().Field(i).Name)...)
+		}
+		return unset
+	default:
+		if v.IsZero() {
+			return []string{path}
+		}
+		return nil
+	}
+}
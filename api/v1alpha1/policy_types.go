@@ -42,6 +42,7 @@ type PolicyStatus struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 
 // Policy is the Schema for the policies API
 // +kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=`.spec.isDefault`
@@ -26,6 +26,57 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeRequest) DeepCopyInto(out *ChangeRequest) {
+	*out = *in
+	in.SubmittedAt.DeepCopyInto(&out.SubmittedAt)
+	if in.EnforcedAt != nil {
+		in, out := &in.EnforcedAt, &out.EnforcedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeRequest.
+func (in *ChangeRequest) DeepCopy() *ChangeRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronTrigger) DeepCopyInto(out *CronTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronTrigger.
+func (in *CronTrigger) DeepCopy() *CronTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CronTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnforcementPreview) DeepCopyInto(out *EnforcementPreview) {
+	*out = *in
+	in.PreviewedAt.DeepCopyInto(&out.PreviewedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnforcementPreview.
+func (in *EnforcementPreview) DeepCopy() *EnforcementPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(EnforcementPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HPAConfiguration) DeepCopyInto(out *HPAConfiguration) {
 	*out = *in
@@ -41,6 +92,21 @@ func (in *HPAConfiguration) DeepCopy() *HPAConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleWindow) DeepCopyInto(out *IdleWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleWindow.
+func (in *IdleWindow) DeepCopy() *IdleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Policy) DeepCopyInto(out *Policy) {
 	*out = *in
@@ -182,6 +248,36 @@ func (in *PolicyRecommendationSpec) DeepCopyInto(out *PolicyRecommendationSpec)
 		in, out := &in.QueuedForExecutionAt, &out.QueuedForExecutionAt
 		*out = (*in).DeepCopy()
 	}
+	if in.MinReplicaFloor != nil {
+		in, out := &in.MinReplicaFloor, &out.MinReplicaFloor
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxReplicaCeiling != nil {
+		in, out := &in.MaxReplicaCeiling, &out.MaxReplicaCeiling
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxTargetUtilization != nil {
+		in, out := &in.MaxTargetUtilization, &out.MaxTargetUtilization
+		*out = new(int)
+		**out = **in
+	}
+	if in.CronTriggers != nil {
+		in, out := &in.CronTriggers, &out.CronTriggers
+		*out = make([]CronTrigger, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdleWindow != nil {
+		in, out := &in.IdleWindow, &out.IdleWindow
+		*out = new(IdleWindow)
+		**out = **in
+	}
+	if in.PinnedHPAConfiguration != nil {
+		in, out := &in.PinnedHPAConfiguration, &out.PinnedHPAConfiguration
+		*out = new(HPAConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationSpec.
@@ -204,6 +300,71 @@ func (in *PolicyRecommendationStatus) DeepCopyInto(out *PolicyRecommendationStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.GeneratedAt != nil {
+		in, out := &in.GeneratedAt, &out.GeneratedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.MetricsWindowStart != nil {
+		in, out := &in.MetricsWindowStart, &out.MetricsWindowStart
+		*out = (*in).DeepCopy()
+	}
+	if in.MetricsWindowEnd != nil {
+		in, out := &in.MetricsWindowEnd, &out.MetricsWindowEnd
+		*out = (*in).DeepCopy()
+	}
+	if in.DataPointsCoveragePercent != nil {
+		in, out := &in.DataPointsCoveragePercent, &out.DataPointsCoveragePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.ProjectedSavingsPercent != nil {
+		in, out := &in.ProjectedSavingsPercent, &out.ProjectedSavingsPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConfidencePercent != nil {
+		in, out := &in.ConfidencePercent, &out.ConfidencePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.BreachFreeMaxReplicas != nil {
+		in, out := &in.BreachFreeMaxReplicas, &out.BreachFreeMaxReplicas
+		*out = new(int)
+		**out = **in
+	}
+	if in.SearchSpace != nil {
+		in, out := &in.SearchSpace, &out.SearchSpace
+		*out = make([]SearchSpacePoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.StaleDataSince != nil {
+		in, out := &in.StaleDataSince, &out.StaleDataSince
+		*out = (*in).DeepCopy()
+	}
+	if in.StaleRecommendations != nil {
+		in, out := &in.StaleRecommendations, &out.StaleRecommendations
+		*out = new(int)
+		**out = **in
+	}
+	if in.ChangeRequest != nil {
+		in, out := &in.ChangeRequest, &out.ChangeRequest
+		*out = new(ChangeRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShadowAdoptionSince != nil {
+		in, out := &in.ShadowAdoptionSince, &out.ShadowAdoptionSince
+		*out = (*in).DeepCopy()
+	}
+	if in.ShadowComparison != nil {
+		in, out := &in.ShadowComparison, &out.ShadowComparison
+		*out = new(ShadowComparison)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnforcementPreview != nil {
+		in, out := &in.EnforcementPreview, &out.EnforcementPreview
+		*out = new(EnforcementPreview)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationStatus.
@@ -246,6 +407,38 @@ func (in *PolicyStatus) DeepCopy() *PolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchSpacePoint) DeepCopyInto(out *SearchSpacePoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchSpacePoint.
+func (in *SearchSpacePoint) DeepCopy() *SearchSpacePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchSpacePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowComparison) DeepCopyInto(out *ShadowComparison) {
+	*out = *in
+	in.WindowStart.DeepCopyInto(&out.WindowStart)
+	in.WindowEnd.DeepCopyInto(&out.WindowEnd)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowComparison.
+func (in *ShadowComparison) DeepCopy() *ShadowComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadMeta) DeepCopyInto(out *WorkloadMeta) {
 	*out = *in
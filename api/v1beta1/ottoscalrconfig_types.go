@@ -0,0 +1,131 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnforcementMode is how the HPA enforcer enforces the recommendations of the workloads in a namespace.
+// +kubebuilder:validation:Enum=Enforce;DryRun;Disabled
+type EnforcementMode string
+
+const (
+	// EnforcementModeEnforce creates the autoscalers of the workloads even if the enforcer runs in dry run.
+	EnforcementModeEnforce EnforcementMode = "Enforce"
+	// EnforcementModeDryRun generates the recommendations without creating the autoscalers of the workloads.
+	EnforcementModeDryRun EnforcementMode = "DryRun"
+	// EnforcementModeDisabled deletes the autoscalers created for the workloads.
+	EnforcementModeDisabled EnforcementMode = "Disabled"
+)
+
+// OttoscalrConfigSpec defines the defaults of the controller overridden for the workloads in the namespace of the
+// config. The unset fields keep the defaults of the controller.
+type OttoscalrConfigSpec struct {
+	// MetricWindowInDays is how many days of the metrics of the workloads the recommendations are generated from.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MetricWindowInDays *int `json:"metricWindowInDays,omitempty"`
+	// MinTarget is the least target utilization the workloads are recommended.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MinTarget *int `json:"minTarget,omitempty"`
+	// MaxTarget is the highest target utilization the workloads are recommended.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxTarget *int `json:"maxTarget,omitempty"`
+	// RedLineUtilizationPercent is the utilization the simulated replicas of the workloads must stay under.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RedLineUtilizationPercent *int `json:"redLineUtilizationPercent,omitempty"`
+	// MinRequiredReplicas is the least min replicas the workloads are recommended and autoscaled with.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinRequiredReplicas *int `json:"minRequiredReplicas,omitempty"`
+	// EnforcementMode is how the recommendations of the workloads are enforced.
+	// +optional
+	EnforcementMode EnforcementMode `json:"enforcementMode,omitempty"`
+	// MinCapacityReservation is the least aggregate cpu capacity, the sum of the min replicas of the workloads times
+	// the cpu limits of their pods, the enforced configurations must keep in the namespace.
+	// +optional
+	MinCapacityReservation *resource.Quantity `json:"minCapacityReservation,omitempty"`
+	// ScaledObject overrides the fields of the ScaledObjects the HPA enforcer generates for the workloads, which are
+	// otherwise templated by the controller or defaulted by KEDA.
+	// +optional
+	ScaledObject *ScaledObjectTemplate `json:"scaledObject,omitempty"`
+}
+
+// ScaledObjectTemplate are the fields of the generated ScaledObjects KEDA otherwise defaults.
+type ScaledObjectTemplate struct {
+	// PollingInterval is the interval in seconds the triggers are checked at.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// CooldownPeriod is how many seconds after the last active trigger the workload is scaled to the idle replicas.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+	// IdleReplicaCount is the replicas of the workload while none of its triggers is active. It's only set if it's
+	// below the min replicas of the ScaledObject.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IdleReplicaCount *int32 `json:"idleReplicaCount,omitempty"`
+	// Fallback is the replicas the workload is scaled to once its triggers fail. It's only set on the ScaledObjects
+	// without cpu or memory triggers, which KEDA doesn't fall back.
+	// +optional
+	Fallback *ScaledObjectFallback `json:"fallback,omitempty"`
+}
+
+// ScaledObjectFallback is the replicas the workload is scaled to once its triggers fail FailureThreshold times in a row.
+type ScaledObjectFallback struct {
+	// +kubebuilder:validation:Minimum=0
+	FailureThreshold int32 `json:"failureThreshold"`
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
+//+kubebuilder:object:root=true
+
+// OttoscalrConfig is the Schema for the ottoscalrconfigs API. It lets the tenants of a namespace tune how the
+// workloads in it are recommended and autoscaled without redeploying the controller. A namespace is expected to have
+// a single config; the oldest one is used if it has more.
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.enforcementMode`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=ottoconfig
+type OttoscalrConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OttoscalrConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OttoscalrConfigList contains a list of OttoscalrConfig
+type OttoscalrConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OttoscalrConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OttoscalrConfig{}, &OttoscalrConfigList{})
+}
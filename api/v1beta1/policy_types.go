@@ -0,0 +1,77 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicySpec defines the desired state of Policy
+type PolicySpec struct {
+	// IsDefault marks the policy that workloads are moved to by the default policy iterator.
+	// +optional
+	IsDefault bool `json:"isDefault,omitempty"`
+
+	// RiskIndex orders the policies from the safest (lowest) to the most aggressive (highest).
+	// +kubebuilder:validation:Minimum=0
+	RiskIndex int `json:"riskIndex"`
+
+	// MinReplicaPercentageCut is the percentage of the (max - min) replica gap that is cut from the max replicas.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MinReplicaPercentageCut int `json:"minReplicaPercentageCut"`
+
+	// TargetUtilization is the target metric value enforced while the workload is at this policy.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetUtilization int `json:"targetUtilization"`
+}
+
+// PolicyStatus defines the observed state of Policy
+type PolicyStatus struct {
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// Policy is the Schema for the policies API
+// +kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=`.spec.isDefault`
+// +kubebuilder:printcolumn:name="RiskIndex",type=integer,JSONPath=`.spec.riskIndex`
+// +kubebuilder:printcolumn:name="ReplicaPercCut",type=integer,JSONPath=`.spec.minReplicaPercentageCut`
+// +kubebuilder:printcolumn:name="TargetUtil",type=integer,JSONPath=`.spec.targetUtilization`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=opolicy,scope=Cluster
+type Policy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PolicySpec   `json:"spec,omitempty"`
+	Status PolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PolicyList contains a list of Policy
+type PolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Policy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Policy{}, &PolicyList{})
+}
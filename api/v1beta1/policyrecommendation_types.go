@@ -0,0 +1,370 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyRecommendationSpec defines the desired state of PolicyRecommendation
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicaFloor) || !has(self.maxReplicaCeiling) || self.minReplicaFloor <= self.maxReplicaCeiling",message="minReplicaFloor must not exceed maxReplicaCeiling"
+type PolicyRecommendationSpec struct {
+	WorkloadMeta            WorkloadMeta     `json:"workload,omitempty"`
+	TargetHPAConfiguration  HPAConfiguration `json:"targetHPAConfig,omitempty"`
+	CurrentHPAConfiguration HPAConfiguration `json:"currentHPAConfig,omitempty"`
+	Policy                  string           `json:"policy,omitempty"`
+	GeneratedAt             *metav1.Time     `json:"generatedAt,omitempty"`
+	TransitionedAt          *metav1.Time     `json:"transitionedAt,omitempty"`
+	QueuedForExecution      *bool            `json:"queuedForExecution,omitempty"`
+	QueuedForExecutionAt    *metav1.Time     `json:"queuedForExecutionAt,omitempty"`
+
+	// MinReplicaFloor, MaxReplicaCeiling and MaxTargetUtilization are hard constraints set by the workload
+	// owners. They clamp the HPA configurations produced by the recommendation workflow.
+	// +kubebuilder:validation:Minimum=1
+	MinReplicaFloor *int `json:"minReplicaFloor,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicaCeiling *int `json:"maxReplicaCeiling,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxTargetUtilization *int `json:"maxTargetUtilization,omitempty"`
+
+	// CronTriggers pre-scale the workload ahead of its recurring daily peaks, on top of the HPA configuration.
+	// +optional
+	CronTriggers []CronTrigger `json:"cronTriggers,omitempty"`
+
+	// IdleWindow lowers the min replicas of the workload during its recurring daily window of low traffic.
+	// +optional
+	IdleWindow *IdleWindow `json:"idleWindow,omitempty"`
+
+	// PinnedHPAConfiguration pins the workload to the HPA configuration set by its owners. The recommendations are
+	// still generated into the TargetHPAConfiguration, along with the savings the pin forgoes, but ottoscalr doesn't
+	// enforce anything on the workload while it's pinned.
+	PinnedHPAConfiguration *HPAConfiguration `json:"pinnedHPAConfig,omitempty"`
+}
+
+// ChangeRequestStatus is the state of a policy transition submitted to the change management system.
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestPending  ChangeRequestStatus = "Pending"
+	ChangeRequestApproved ChangeRequestStatus = "Approved"
+	ChangeRequestRejected ChangeRequestStatus = "Rejected"
+	ChangeRequestTimedOut ChangeRequestStatus = "TimedOut"
+)
+
+// ChangeRequest is a policy transition of the workload submitted to the change management system, whose approval it
+// waits for before it's enforced.
+type ChangeRequest struct {
+	TicketID string `json:"ticketId"`
+	// Policy is the policy the workload transitions to once the change is approved.
+	Policy      string              `json:"policy"`
+	Status      ChangeRequestStatus `json:"status"`
+	SubmittedAt metav1.Time         `json:"submittedAt"`
+	// EnforcedAt is when the approved transition was enforced, after which the approval isn't reused for entering the
+	// policy again.
+	EnforcedAt *metav1.Time `json:"enforcedAt,omitempty"`
+}
+
+// SearchSpacePoint is the outcome of the search for the optimal HPA configuration at a min replicas: the highest
+// target utilization without breaches at it and the savings projected for them.
+type SearchSpacePoint struct {
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int `json:"minReplicas"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	TargetUtilization int `json:"targetUtilization"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ProjectedSavingsPercent int `json:"projectedSavingsPercent"`
+}
+
+// ShadowComparison compares what the HPA the owners of a workload created did over a window with what the recommended
+// HPA configuration of the workload is simulated to have done over it, before ottoscalr adopts the workload from the
+// HPA. The breaches are the datapoints at which the CPU utilization went beyond the redline.
+type ShadowComparison struct {
+	WindowStart metav1.Time `json:"windowStart"`
+	WindowEnd   metav1.Time `json:"windowEnd"`
+	// +kubebuilder:validation:Minimum=0
+	HPAReplicaHours int64 `json:"hpaReplicaHours"`
+	// +kubebuilder:validation:Minimum=0
+	ShadowReplicaHours int64 `json:"shadowReplicaHours"`
+	// +kubebuilder:validation:Minimum=0
+	HPABreaches int `json:"hpaBreaches"`
+	// +kubebuilder:validation:Minimum=0
+	ShadowBreaches int `json:"shadowBreaches"`
+}
+
+// EnforcementPreview is the change the HPA enforcer would apply to the autoscaler of the workload, recorded before it's
+// applied so that it can be reviewed.
+type EnforcementPreview struct {
+	// Autoscaler is the kind and the name of the autoscaler, e.g. ScaledObject/checkout.
+	Autoscaler string `json:"autoscaler"`
+	// Patch is the JSON merge patch of the autoscaler, or the whole autoscaler if it's to be created.
+	Patch string `json:"patch"`
+	// PreviewedAt is when the change was first previewed.
+	PreviewedAt metav1.Time `json:"previewedAt"`
+}
+
+// CronTrigger is a daily window in which the workload is kept at DesiredReplicas or more. Start and End are cron
+// expressions in the Timezone.
+type CronTrigger struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	// +kubebuilder:validation:Minimum=1
+	DesiredReplicas int `json:"desiredReplicas"`
+}
+
+// IdleWindow is a daily window of low traffic in which the workload is kept at MinReplicas or more instead of the min
+// replicas of its HPA configuration. Start and End are cron expressions in the Timezone.
+type IdleWindow struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int `json:"minReplicas"`
+}
+
+type WorkloadMeta struct {
+	metav1.TypeMeta `json:","`
+	Name            string `json:"name,omitempty"`
+}
+
+// HPAConfiguration is the autoscaler configuration recommended for (or applied on) a workload.
+// +kubebuilder:validation:XValidation:rule="self.min <= self.max",message="min must not exceed max"
+// +kubebuilder:validation:XValidation:rule="(has(self.targetMetricType) && self.targetMetricType != 'Utilization') || self.targetMetricValue <= 100",message="targetMetricValue must not exceed 100 for Utilization targets"
+type HPAConfiguration struct {
+	// +kubebuilder:validation:Minimum=0
+	Min int `json:"min"`
+	// +kubebuilder:validation:Minimum=0
+	Max int `json:"max"`
+	// +kubebuilder:validation:Minimum=0
+	TargetMetricValue int `json:"targetMetricValue"`
+
+	// MetricName is the metric the target applies to, e.g. cpu, memory or the name of a custom/external
+	// metric such as http_requests_per_second. Defaults to cpu.
+	// +optional
+	MetricName string `json:"metricName,omitempty"`
+	// TargetMetricType is how TargetMetricValue is interpreted. For Utilization it is a percentage of the
+	// resource requests, for AverageValue and Value it is an absolute value in the metric's unit.
+	// Defaults to Utilization.
+	// +kubebuilder:validation:Enum=Utilization;AverageValue;Value
+	// +optional
+	TargetMetricType MetricTargetType `json:"targetMetricType,omitempty"`
+}
+
+// MetricTargetType is the type of the target an autoscaler compares the observed metric value against.
+type MetricTargetType string
+
+const (
+	// UtilizationMetricTarget targets an average utilization percentage of the resource requests across pods.
+	UtilizationMetricTarget MetricTargetType = "Utilization"
+	// AverageValueMetricTarget targets an absolute metric value averaged across pods.
+	AverageValueMetricTarget MetricTargetType = "AverageValue"
+	// ValueMetricTarget targets an absolute value of the metric as a whole.
+	ValueMetricTarget MetricTargetType = "Value"
+
+	// DefaultMetricName is the metric HPA configurations target when no metric name is specified.
+	DefaultMetricName = "cpu"
+)
+
+// GetMetricName returns the metric name of the configuration, defaulting to cpu.
+func (h HPAConfiguration) GetMetricName() string {
+	if h.MetricName == "" {
+		return DefaultMetricName
+	}
+	return h.MetricName
+}
+
+// GetTargetMetricType returns the target type of the configuration, defaulting to Utilization.
+func (h HPAConfiguration) GetTargetMetricType() MetricTargetType {
+	if h.TargetMetricType == "" {
+		return UtilizationMetricTarget
+	}
+	return h.TargetMetricType
+}
+
+func (h HPAConfiguration) DeepEquals(h2 HPAConfiguration) bool {
+	if h.Min != h2.Min || h.Max != h2.Max || h.TargetMetricValue != h2.TargetMetricValue {
+		return false
+	}
+	if h.GetMetricName() != h2.GetMetricName() || h.GetTargetMetricType() != h2.GetTargetMetricType() {
+		return false
+	}
+	return true
+}
+
+// PolicyRecommendationStatus defines the observed state of PolicyRecommendation
+type PolicyRecommendationStatus struct {
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// GeneratedAt is the time the latest recommendation was generated at.
+	GeneratedAt *metav1.Time `json:"generatedAt,omitempty"`
+	// MetricsWindowStart and MetricsWindowEnd bound the metrics the latest recommendation was generated from.
+	MetricsWindowStart *metav1.Time `json:"metricsWindowStart,omitempty"`
+	MetricsWindowEnd   *metav1.Time `json:"metricsWindowEnd,omitempty"`
+	// DataPointsCoveragePercent is the percentage of the expected data points in the metrics window
+	// which were available to the recommender.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	DataPointsCoveragePercent *int `json:"dataPointsCoveragePercent,omitempty"`
+	// ProjectedSavingsPercent is the percentage of compute the recommended configuration is projected to
+	// save over the metrics window when compared to running at max replicas.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ProjectedSavingsPercent *int `json:"projectedSavingsPercent,omitempty"`
+	// ConfidencePercent is how far the latest recommendation can be relied upon, from the coverage and the length of
+	// its metrics window, the variance of the metrics and how close the recommended config came to the redline.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ConfidencePercent *int `json:"confidencePercent,omitempty"`
+	// BreachFreeMaxReplicas is the smallest max replicas which would have avoided the breaches of the workload over
+	// the metrics window at the recommended min replicas and target, for the teams to tune its ceiling.
+	// +kubebuilder:validation:Minimum=0
+	BreachFreeMaxReplicas *int `json:"breachFreeMaxReplicas,omitempty"`
+	// SearchSpace is the trade-off curve of the latest recommendation, the highest target without breaches and its
+	// savings at every min replicas the recommender found one for, for the users to pick another point of it.
+	SearchSpace []SearchSpacePoint `json:"searchSpace,omitempty"`
+	// StaleDataSince is when the metrics of the workload first fell short of the coverage threshold while its
+	// previous recommendation was kept, and StaleRecommendations is how many recommendations in a row kept it since.
+	StaleDataSince *metav1.Time `json:"staleDataSince,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	StaleRecommendations *int `json:"staleRecommendations,omitempty"`
+	// ChangeRequest is the latest policy transition of the workload submitted to the change management system.
+	ChangeRequest *ChangeRequest `json:"changeRequest,omitempty"`
+	// ShadowAdoptionSince is when ottoscalr started shadowing the HPA the owners of the workload created, before
+	// adopting the workload from it, and ShadowComparison is the latest comparison of the HPA with the recommendation.
+	ShadowAdoptionSince *metav1.Time      `json:"shadowAdoptionSince,omitempty"`
+	ShadowComparison    *ShadowComparison `json:"shadowComparison,omitempty"`
+	// EnforcementPreview is the latest change of the autoscaler of the workload the HPA enforcer previewed before
+	// applying it.
+	EnforcementPreview *EnforcementPreview `json:"enforcementPreview,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// PolicyRecommendation is the Schema for the policyrecommendations API
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.spec.workload.name`,priority=1
+// +kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.spec.policy`
+// +kubebuilder:printcolumn:name="Min",type=integer,JSONPath=`.spec.targetHPAConfig.min`
+// +kubebuilder:printcolumn:name="Max",type=integer,JSONPath=`.spec.targetHPAConfig.max`
+// +kubebuilder:printcolumn:name="Util",type=integer,JSONPath=`.spec.targetHPAConfig.targetMetricValue`
+// +kubebuilder:printcolumn:name="Current Min",type=integer,JSONPath=`.spec.currentHPAConfig.min`,priority=1
+// +kubebuilder:printcolumn:name="Current Max",type=integer,JSONPath=`.spec.currentHPAConfig.max`,priority=1
+// +kubebuilder:printcolumn:name="Current Util",type=integer,JSONPath=`.spec.currentHPAConfig.targetMetricValue`,priority=1
+// +kubebuilder:printcolumn:name="Savings%",type=integer,JSONPath=`.status.projectedSavingsPercent`
+// +kubebuilder:printcolumn:name="Achieved",type=string,JSONPath=`.status.conditions[?(@.type=="TargetRecoAchieved")].status`
+// +kubebuilder:printcolumn:name="NoOp",type=string,JSONPath=`.status.conditions[?(@.type=="NoOpRecommended")].status`,priority=1
+// +kubebuilder:printcolumn:name="Stale",type=string,JSONPath=`.status.conditions[?(@.type=="StaleData")].status`,priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName={policyreco,preco}
+type PolicyRecommendation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PolicyRecommendationSpec   `json:"spec,omitempty"`
+	Status PolicyRecommendationStatus `json:"status,omitempty"`
+}
+
+type PolicyRecommendationConditionType string
+
+// These are valid conditions of a policy recommendation.
+const (
+	// PolicyRecommendation is initialized post the creation of a workload
+	Initialized PolicyRecommendationConditionType = "Initialized"
+
+	//Recommendation is queued for execution
+	RecoTaskQueued PolicyRecommendationConditionType = "RecoTaskQueued"
+
+	// Recommendation WorkFlow Progress is captured in this condition
+	RecoTaskProgress PolicyRecommendationConditionType = "RecoTaskProgress"
+
+	//Target Reco is acheived
+	TargetRecoAchieved PolicyRecommendationConditionType = "TargetRecoAchieved"
+
+	// AutoscalingPolicySynced means there's corresponding ScaledObject or HPA reflects the desired state specified in the PolicyRecommendation
+	AutoscalingPolicySynced PolicyRecommendationConditionType = "AutoscalingPolicySynced"
+
+	//Breach Condition
+	HasBreached PolicyRecommendationConditionType = "HasBreached"
+
+	// HPA Enforced condition
+	HPAEnforced PolicyRecommendationConditionType = "HPAEnforced"
+
+	// WorkloadInactive means the workload is scaled to zero or paused and its recommendation is skipped until it's
+	// active again
+	WorkloadInactive PolicyRecommendationConditionType = "WorkloadInactive"
+
+	// InsufficientHistory means the workload is younger than the min workload age and is recommended the no-op
+	// configuration until it has enough history of its traffic
+	InsufficientHistory PolicyRecommendationConditionType = "InsufficientHistory"
+
+	// ResizeRecommended means the pods of the workload need right-sizing, e.g. by a VPA, as autoscaling their count
+	// can't serve the workload well
+	ResizeRecommended PolicyRecommendationConditionType = "ResizeRecommended"
+
+	// VPAConflict means a VerticalPodAutoscaler resizes the metric the workload is autoscaled on, so that the
+	// autoscaling isn't enforced until it stops
+	VPAConflict PolicyRecommendationConditionType = "VPAConflict"
+
+	// QuotaConstrained means the ResourceQuotas of the namespace don't leave the workload the room to scale up to its
+	// recommended max replicas, which is clamped to the replicas they allow
+	QuotaConstrained PolicyRecommendationConditionType = "QuotaConstrained"
+
+	// NoOpRecommended means the recommender couldn't recommend a config for the workload and recommended the no-op
+	// configuration, which keeps the workload at its max replicas, instead. The reason tells why it couldn't
+	NoOpRecommended PolicyRecommendationConditionType = "NoOpRecommended"
+
+	// StaleData means the metrics of the workload fell short of the coverage threshold and its previous
+	// recommendation is kept for a grace period, rather than recommending the no-op configuration right away
+	StaleData PolicyRecommendationConditionType = "StaleData"
+
+	// AnomalousRecommendation means the recommendation differs wildly from the previous one, e.g. its min replicas
+	// dropped by most of them, which usually comes of bad metric data rather than a real change of the traffic
+	AnomalousRecommendation PolicyRecommendationConditionType = "AnomalousRecommendation"
+
+	// RecommendationPinned means the workload is pinned to the HPA configuration of its owners, so that ottoscalr
+	// keeps recommending but doesn't enforce anything on it
+	RecommendationPinned PolicyRecommendationConditionType = "RecommendationPinned"
+
+	// ShadowAdoption means ottoscalr shadows the HPA the owners of the workload created, recording what the
+	// recommendation would have done, and holds the adoption of the workload until the recommendation performs on par
+	// with the HPA or better over the shadow period
+	ShadowAdoption PolicyRecommendationConditionType = "ShadowAdoption"
+
+	// Skipped means the workload is owned by a kind, e.g. a Job or a CronJob, or carries a label which makes
+	// autoscaling it meaningless, so that it isn't recommended at all
+	Skipped PolicyRecommendationConditionType = "Skipped"
+)
+
+//+kubebuilder:object:root=true
+
+// PolicyRecommendationList contains a list of PolicyRecommendation
+type PolicyRecommendationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyRecommendation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyRecommendation{}, &PolicyRecommendationList{})
+}
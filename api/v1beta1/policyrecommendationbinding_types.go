@@ -0,0 +1,71 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyRecommendationBindingSpec defines the desired state of PolicyRecommendationBinding
+type PolicyRecommendationBindingSpec struct {
+	// WorkloadSelector selects the workloads in the namespace of the binding which are managed by ottoscalr.
+	// All the workloads in the namespace are selected when it's empty.
+	// +optional
+	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+	// Policy is the policy the PolicyRecommendations of the selected workloads are moved to.
+	// Defaults to the safest policy.
+	// +optional
+	Policy string `json:"policy,omitempty"`
+}
+
+// PolicyRecommendationBindingStatus defines the observed state of PolicyRecommendationBinding
+type PolicyRecommendationBindingStatus struct {
+	// MatchedWorkloads is the number of workloads selected by the binding during the last sync.
+	MatchedWorkloads int `json:"matchedWorkloads,omitempty"`
+	// LastSyncedAt is the time the binding was last synced with the workloads in the namespace.
+	LastSyncedAt *metav1.Time `json:"lastSyncedAt,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// PolicyRecommendationBinding is the Schema for the policyrecommendationbindings API. It makes ottoscalr
+// manage all the matching workloads in a namespace without requiring per workload PolicyRecommendations.
+// +kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.spec.policy`
+// +kubebuilder:printcolumn:name="Workloads",type=integer,JSONPath=`.status.matchedWorkloads`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=policybinding
+type PolicyRecommendationBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PolicyRecommendationBindingSpec   `json:"spec,omitempty"`
+	Status PolicyRecommendationBindingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PolicyRecommendationBindingList contains a list of PolicyRecommendationBinding
+type PolicyRecommendationBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyRecommendationBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyRecommendationBinding{}, &PolicyRecommendationBindingList{})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SavingsReportSpec defines how the recommendations of the fleet are aggregated by the report.
+type SavingsReportSpec struct {
+	// TeamLabel is the label of the workloads the recommendations are aggregated by besides their namespaces, e.g.
+	// team. The teams aren't aggregated when it's empty.
+	// +optional
+	TeamLabel string `json:"teamLabel,omitempty"`
+}
+
+// Savings are the simulated annual savings of the recommendations of a set of workloads. The replica-hours are the
+// ones of running the workloads for a year, at the max replicas of their recommendations for the baseline and at the
+// replicas their recommendations are simulated to scale to otherwise. The costs are set if a pricing provider is
+// configured, as decimals in the currency of the report.
+type Savings struct {
+	Workloads               int   `json:"workloads"`
+	EnforcedWorkloads       int   `json:"enforcedWorkloads"`
+	BaselineReplicaHours    int64 `json:"baselineReplicaHours"`
+	RecommendedReplicaHours int64 `json:"recommendedReplicaHours"`
+	SavedReplicaHours       int64 `json:"savedReplicaHours"`
+	// +optional
+	BaselineCost string `json:"baselineCost,omitempty"`
+	// +optional
+	SavedCost string `json:"savedCost,omitempty"`
+}
+
+// NamespaceSavings are the savings of the workloads in a namespace.
+type NamespaceSavings struct {
+	Namespace string `json:"namespace"`
+	Savings   `json:",inline"`
+}
+
+// TeamSavings are the savings of the workloads labeled with a team.
+type TeamSavings struct {
+	Team    string `json:"team"`
+	Savings `json:",inline"`
+}
+
+// SavingsReportStatus defines the savings of the fleet as of the last regeneration of the report.
+type SavingsReportStatus struct {
+	// GeneratedAt is the time the report was last regenerated at.
+	GeneratedAt *metav1.Time `json:"generatedAt,omitempty"`
+	// Currency is the currency of the costs, set if a pricing provider is configured.
+	// +optional
+	Currency string `json:"currency,omitempty"`
+	// Total are the savings of all the workloads.
+	Total Savings `json:"total,omitempty"`
+	// +optional
+	Namespaces []NamespaceSavings `json:"namespaces,omitempty"`
+	// Teams are the savings of the workloads by the values of the team label. The workloads without it are left out.
+	// +optional
+	Teams []TeamSavings `json:"teams,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// SavingsReport is the Schema for the savingsreports API. It holds the simulated annual savings of the recommendations
+// of the fleet by namespace and team, regenerated daily by the controller, for the external reporting to consume.
+// +kubebuilder:printcolumn:name="Workloads",type=integer,JSONPath=`.status.total.workloads`
+// +kubebuilder:printcolumn:name="Saved Replica-Hours",type=integer,JSONPath=`.status.total.savedReplicaHours`
+// +kubebuilder:printcolumn:name="Saved Cost",type=string,JSONPath=`.status.total.savedCost`
+// +kubebuilder:printcolumn:name="Generated",type="date",JSONPath=".status.generatedAt"
+// +kubebuilder:resource:scope=Cluster,shortName=savings
+type SavingsReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SavingsReportSpec   `json:"spec,omitempty"`
+	Status SavingsReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SavingsReportList contains a list of SavingsReport
+type SavingsReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SavingsReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SavingsReport{}, &SavingsReportList{})
+}
@@ -0,0 +1,863 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeRequest) DeepCopyInto(out *ChangeRequest) {
+	*out = *in
+	in.SubmittedAt.DeepCopyInto(&out.SubmittedAt)
+	if in.EnforcedAt != nil {
+		in, out := &in.EnforcedAt, &out.EnforcedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeRequest.
+func (in *ChangeRequest) DeepCopy() *ChangeRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronTrigger) DeepCopyInto(out *CronTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronTrigger.
+func (in *CronTrigger) DeepCopy() *CronTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CronTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnforcementPreview) DeepCopyInto(out *EnforcementPreview) {
+	*out = *in
+	in.PreviewedAt.DeepCopyInto(&out.PreviewedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnforcementPreview.
+func (in *EnforcementPreview) DeepCopy() *EnforcementPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(EnforcementPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HPAConfiguration) DeepCopyInto(out *HPAConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HPAConfiguration.
+func (in *HPAConfiguration) DeepCopy() *HPAConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HPAConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleWindow) DeepCopyInto(out *IdleWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleWindow.
+func (in *IdleWindow) DeepCopy() *IdleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSavings) DeepCopyInto(out *NamespaceSavings) {
+	*out = *in
+	out.Savings = in.Savings
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSavings.
+func (in *NamespaceSavings) DeepCopy() *NamespaceSavings {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSavings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OttoscalrConfig) DeepCopyInto(out *OttoscalrConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OttoscalrConfig.
+func (in *OttoscalrConfig) DeepCopy() *OttoscalrConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OttoscalrConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OttoscalrConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OttoscalrConfigList) DeepCopyInto(out *OttoscalrConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OttoscalrConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OttoscalrConfigList.
+func (in *OttoscalrConfigList) DeepCopy() *OttoscalrConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OttoscalrConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OttoscalrConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OttoscalrConfigSpec) DeepCopyInto(out *OttoscalrConfigSpec) {
+	*out = *in
+	if in.MetricWindowInDays != nil {
+		in, out := &in.MetricWindowInDays, &out.MetricWindowInDays
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinTarget != nil {
+		in, out := &in.MinTarget, &out.MinTarget
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxTarget != nil {
+		in, out := &in.MaxTarget, &out.MaxTarget
+		*out = new(int)
+		**out = **in
+	}
+	if in.RedLineUtilizationPercent != nil {
+		in, out := &in.RedLineUtilizationPercent, &out.RedLineUtilizationPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinRequiredReplicas != nil {
+		in, out := &in.MinRequiredReplicas, &out.MinRequiredReplicas
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinCapacityReservation != nil {
+		in, out := &in.MinCapacityReservation, &out.MinCapacityReservation
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ScaledObject != nil {
+		in, out := &in.ScaledObject, &out.ScaledObject
+		*out = new(ScaledObjectTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OttoscalrConfigSpec.
+func (in *OttoscalrConfigSpec) DeepCopy() *OttoscalrConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OttoscalrConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Policy) DeepCopyInto(out *Policy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
+func (in *Policy) DeepCopy() *Policy {
+	if in == nil {
+		return nil
+	}
+	out := new(Policy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Policy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyList) DeepCopyInto(out *PolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Policy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyList.
+func (in *PolicyList) DeepCopy() *PolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendation) DeepCopyInto(out *PolicyRecommendation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendation.
+func (in *PolicyRecommendation) DeepCopy() *PolicyRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyRecommendation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationBinding) DeepCopyInto(out *PolicyRecommendationBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationBinding.
+func (in *PolicyRecommendationBinding) DeepCopy() *PolicyRecommendationBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyRecommendationBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationBindingList) DeepCopyInto(out *PolicyRecommendationBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PolicyRecommendationBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationBindingList.
+func (in *PolicyRecommendationBindingList) DeepCopy() *PolicyRecommendationBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyRecommendationBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationBindingSpec) DeepCopyInto(out *PolicyRecommendationBindingSpec) {
+	*out = *in
+	if in.WorkloadSelector != nil {
+		in, out := &in.WorkloadSelector, &out.WorkloadSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationBindingSpec.
+func (in *PolicyRecommendationBindingSpec) DeepCopy() *PolicyRecommendationBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationBindingStatus) DeepCopyInto(out *PolicyRecommendationBindingStatus) {
+	*out = *in
+	if in.LastSyncedAt != nil {
+		in, out := &in.LastSyncedAt, &out.LastSyncedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationBindingStatus.
+func (in *PolicyRecommendationBindingStatus) DeepCopy() *PolicyRecommendationBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationList) DeepCopyInto(out *PolicyRecommendationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PolicyRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationList.
+func (in *PolicyRecommendationList) DeepCopy() *PolicyRecommendationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyRecommendationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationSpec) DeepCopyInto(out *PolicyRecommendationSpec) {
+	*out = *in
+	out.WorkloadMeta = in.WorkloadMeta
+	out.TargetHPAConfiguration = in.TargetHPAConfiguration
+	out.CurrentHPAConfiguration = in.CurrentHPAConfiguration
+	if in.GeneratedAt != nil {
+		in, out := &in.GeneratedAt, &out.GeneratedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.TransitionedAt != nil {
+		in, out := &in.TransitionedAt, &out.TransitionedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.QueuedForExecution != nil {
+		in, out := &in.QueuedForExecution, &out.QueuedForExecution
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QueuedForExecutionAt != nil {
+		in, out := &in.QueuedForExecutionAt, &out.QueuedForExecutionAt
+		*out = (*in).DeepCopy()
+	}
+	if in.MinReplicaFloor != nil {
+		in, out := &in.MinReplicaFloor, &out.MinReplicaFloor
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxReplicaCeiling != nil {
+		in, out := &in.MaxReplicaCeiling, &out.MaxReplicaCeiling
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxTargetUtilization != nil {
+		in, out := &in.MaxTargetUtilization, &out.MaxTargetUtilization
+		*out = new(int)
+		**out = **in
+	}
+	if in.CronTriggers != nil {
+		in, out := &in.CronTriggers, &out.CronTriggers
+		*out = make([]CronTrigger, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdleWindow != nil {
+		in, out := &in.IdleWindow, &out.IdleWindow
+		*out = new(IdleWindow)
+		**out = **in
+	}
+	if in.PinnedHPAConfiguration != nil {
+		in, out := &in.PinnedHPAConfiguration, &out.PinnedHPAConfiguration
+		*out = new(HPAConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationSpec.
+func (in *PolicyRecommendationSpec) DeepCopy() *PolicyRecommendationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationStatus) DeepCopyInto(out *PolicyRecommendationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GeneratedAt != nil {
+		in, out := &in.GeneratedAt, &out.GeneratedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.MetricsWindowStart != nil {
+		in, out := &in.MetricsWindowStart, &out.MetricsWindowStart
+		*out = (*in).DeepCopy()
+	}
+	if in.MetricsWindowEnd != nil {
+		in, out := &in.MetricsWindowEnd, &out.MetricsWindowEnd
+		*out = (*in).DeepCopy()
+	}
+	if in.DataPointsCoveragePercent != nil {
+		in, out := &in.DataPointsCoveragePercent, &out.DataPointsCoveragePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.ProjectedSavingsPercent != nil {
+		in, out := &in.ProjectedSavingsPercent, &out.ProjectedSavingsPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConfidencePercent != nil {
+		in, out := &in.ConfidencePercent, &out.ConfidencePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.BreachFreeMaxReplicas != nil {
+		in, out := &in.BreachFreeMaxReplicas, &out.BreachFreeMaxReplicas
+		*out = new(int)
+		**out = **in
+	}
+	if in.SearchSpace != nil {
+		in, out := &in.SearchSpace, &out.SearchSpace
+		*out = make([]SearchSpacePoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.StaleDataSince != nil {
+		in, out := &in.StaleDataSince, &out.StaleDataSince
+		*out = (*in).DeepCopy()
+	}
+	if in.StaleRecommendations != nil {
+		in, out := &in.StaleRecommendations, &out.StaleRecommendations
+		*out = new(int)
+		**out = **in
+	}
+	if in.ChangeRequest != nil {
+		in, out := &in.ChangeRequest, &out.ChangeRequest
+		*out = new(ChangeRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShadowAdoptionSince != nil {
+		in, out := &in.ShadowAdoptionSince, &out.ShadowAdoptionSince
+		*out = (*in).DeepCopy()
+	}
+	if in.ShadowComparison != nil {
+		in, out := &in.ShadowComparison, &out.ShadowComparison
+		*out = new(ShadowComparison)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnforcementPreview != nil {
+		in, out := &in.EnforcementPreview, &out.EnforcementPreview
+		*out = new(EnforcementPreview)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationStatus.
+func (in *PolicyRecommendationStatus) DeepCopy() *PolicyRecommendationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySpec.
+func (in *PolicySpec) DeepCopy() *PolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyStatus) DeepCopyInto(out *PolicyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyStatus.
+func (in *PolicyStatus) DeepCopy() *PolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Savings) DeepCopyInto(out *Savings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Savings.
+func (in *Savings) DeepCopy() *Savings {
+	if in == nil {
+		return nil
+	}
+	out := new(Savings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavingsReport) DeepCopyInto(out *SavingsReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavingsReport.
+func (in *SavingsReport) DeepCopy() *SavingsReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SavingsReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SavingsReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavingsReportList) DeepCopyInto(out *SavingsReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SavingsReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavingsReportList.
+func (in *SavingsReportList) DeepCopy() *SavingsReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(SavingsReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SavingsReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavingsReportSpec) DeepCopyInto(out *SavingsReportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavingsReportSpec.
+func (in *SavingsReportSpec) DeepCopy() *SavingsReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SavingsReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavingsReportStatus) DeepCopyInto(out *SavingsReportStatus) {
+	*out = *in
+	if in.GeneratedAt != nil {
+		in, out := &in.GeneratedAt, &out.GeneratedAt
+		*out = (*in).DeepCopy()
+	}
+	out.Total = in.Total
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespaceSavings, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]TeamSavings, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavingsReportStatus.
+func (in *SavingsReportStatus) DeepCopy() *SavingsReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SavingsReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaledObjectFallback) DeepCopyInto(out *ScaledObjectFallback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledObjectFallback.
+func (in *ScaledObjectFallback) DeepCopy() *ScaledObjectFallback {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaledObjectFallback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaledObjectTemplate) DeepCopyInto(out *ScaledObjectTemplate) {
+	*out = *in
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleReplicaCount != nil {
+		in, out := &in.IdleReplicaCount, &out.IdleReplicaCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Fallback != nil {
+		in, out := &in.Fallback, &out.Fallback
+		*out = new(ScaledObjectFallback)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledObjectTemplate.
+func (in *ScaledObjectTemplate) DeepCopy() *ScaledObjectTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaledObjectTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchSpacePoint) DeepCopyInto(out *SearchSpacePoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchSpacePoint.
+func (in *SearchSpacePoint) DeepCopy() *SearchSpacePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchSpacePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowComparison) DeepCopyInto(out *ShadowComparison) {
+	*out = *in
+	in.WindowStart.DeepCopyInto(&out.WindowStart)
+	in.WindowEnd.DeepCopyInto(&out.WindowEnd)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowComparison.
+func (in *ShadowComparison) DeepCopy() *ShadowComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamSavings) DeepCopyInto(out *TeamSavings) {
+	*out = *in
+	out.Savings = in.Savings
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSavings.
+func (in *TeamSavings) DeepCopy() *TeamSavings {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamSavings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadMeta) DeepCopyInto(out *WorkloadMeta) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadMeta.
+func (in *WorkloadMeta) DeepCopy() *WorkloadMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadMeta)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// backtest replays a candidate HPA configuration of a workload on its historical CPU utilization and reports the
+// breaches and the savings, the same way the recommender simulates the HPA.
+//
+//	backtest --datapoints file.json|file.csv --min 5 --max 20 --target 50 --per-pod-resources 2 [--acl 5m]
+//	backtest --prometheus-url url -n namespace --workload name --min 5 --max 20 --target 50 [--export file.csv]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/backtest"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const usage = `Replay a candidate HPA configuration on the historical CPU utilization of a workload.
+
+Usage:
+  backtest --datapoints <file.json|file.csv> --min <n> --max <n> --target <percent> --per-pod-resources <cores> [--acl duration]
+  backtest --prometheus-url <url> -n <namespace> --workload <name> [--kind Deployment] --min <n> --max <n> --target <percent>
+
+Flags:
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(argov1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	dataPointsFile := flag.String("datapoints", "", "exported datapoints to replay the configuration on, as JSON or CSV")
+	prometheusURL := flag.String("prometheus-url", "", "prometheus to fetch the CPU utilization of the workload from")
+	namespace := flag.String("n", "default", "namespace of the workload")
+	workload := flag.String("workload", "", "name of the workload")
+	kind := flag.String("kind", "Deployment", "kind of the workload, Deployment or Rollout, to read its per pod resources")
+	exportFile := flag.String("export", "", "file to export the fetched datapoints to, as JSON or CSV, for later replays")
+	minReplicas := flag.Int("min", 0, "min replicas of the candidate configuration")
+	maxReplicas := flag.Int("max", 0, "max replicas of the candidate configuration")
+	target := flag.Int("target", 0, "target CPU utilization percent of the candidate configuration")
+	perPodResources := flag.Float64("per-pod-resources", 0, "CPU cores of a pod, read from the workload if not set")
+	acl := flag.Duration("acl", 0, "autoscaling cycle lag of the workload, fetched from prometheus if not set")
+	from := flag.String("from", "", "RFC3339 start of the window, defaults to window-days before the end or the whole datapoints file")
+	to := flag.String("to", "", "RFC3339 end of the window, defaults to now or the last datapoint")
+	windowDays := flag.Int("window-days", 28, "days of history to replay on when from isn't set")
+	step := flag.Duration("step", 30*time.Second, "resolution of the fetched datapoints")
+	redLine := flag.Float64("red-line", 0.85, "CPU utilization of the available resources beyond which it's a breach")
+	output := flag.String("o", string(backtest.TextFormat), "output format, text or json")
+	failOnBreach := flag.Bool("fail-on-breach", false, "exit with a non zero status if the configuration breaches")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	start, end, err := parseWindow(*from, *to, *windowDays)
+	if err != nil {
+		fail(err)
+	}
+	input := backtest.Input{
+		ACL:             *acl,
+		PerPodResources: *perPodResources,
+		RedLineUtil:     *redLine,
+		Config:          v1alpha1.HPAConfiguration{Min: *minReplicas, Max: *maxReplicas, TargetMetricValue: *target},
+	}
+
+	switch {
+	case len(*dataPointsFile) > 0:
+		file, err := os.Open(*dataPointsFile)
+		if err != nil {
+			fail(err)
+		}
+		dataPoints, err := metrics.ReadDataPoints(file, metrics.IsCSV(*dataPointsFile))
+		file.Close()
+		if err != nil {
+			fail(fmt.Errorf("unable to read %s: %v", *dataPointsFile, err))
+		}
+		if len(*from) == 0 {
+			start = time.Time{}
+		}
+		if len(*to) == 0 {
+			end = time.Time{}
+		}
+		input.DataPoints = backtest.Window(dataPoints, start, end)
+		if input.PerPodResources <= 0 {
+			fail(errors.New("--per-pod-resources is required with --datapoints"))
+		}
+	case len(*prometheusURL) > 0 && len(*workload) > 0:
+		scraper, err := metrics.NewPrometheusScraper([]string{*prometheusURL}, 5*time.Minute, 24*time.Hour, 15, 15, logr.Discard())
+		if err != nil {
+			fail(err)
+		}
+		input.DataPoints, err = scraper.GetAverageCPUUtilizationByWorkload(*namespace, *workload, start, end, *step)
+		if err != nil {
+			fail(fmt.Errorf("unable to fetch the CPU utilization: %v", err))
+		}
+		if input.ACL == 0 {
+			if input.ACL, err = scraper.GetACLByWorkload(*namespace, *workload); err != nil {
+				fail(fmt.Errorf("unable to fetch the ACL: %v", err))
+			}
+		}
+		if input.PerPodResources <= 0 {
+			if input.PerPodResources, err = getPerPodResources(*namespace, *kind, *workload); err != nil {
+				fail(fmt.Errorf("unable to read the per pod resources, set --per-pod-resources: %v", err))
+			}
+		}
+		if len(*exportFile) > 0 {
+			if err := exportDataPoints(*exportFile, input.DataPoints); err != nil {
+				fail(err)
+			}
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	result, err := backtest.Run(input)
+	if err != nil {
+		fail(err)
+	}
+	if err := backtest.Write(os.Stdout, result, backtest.Format(*output)); err != nil {
+		fail(err)
+	}
+	if *failOnBreach && !result.Survived() {
+		os.Exit(1)
+	}
+}
+
+func parseWindow(from, to string, windowDays int) (time.Time, time.Time, error) {
+	end := time.Now()
+	if len(to) > 0 {
+		var err error
+		if end, err = time.Parse(time.RFC3339, to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %v", err)
+		}
+	}
+	start := end.Add(-time.Duration(windowDays) * 24 * time.Hour)
+	if len(from) > 0 {
+		var err error
+		if start, err = time.Parse(time.RFC3339, from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %v", err)
+		}
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, errors.New("the window should end after it starts")
+	}
+	return start, end, nil
+}
+
+// getPerPodResources reads the CPU limits of a pod of the workload from the cluster of the current kubeconfig.
+func getPerPodResources(namespace, kind, workload string) (float64, error) {
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return 0, err
+	}
+	clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+		WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).
+		WithCustomDeploymentClient(registry.NewRolloutClient(k8sClient)).Build()
+	objectClient, err := clientsRegistry.GetObjectClient(kind)
+	if err != nil {
+		return 0, err
+	}
+	return objectClient.GetContainerResourceLimits(namespace, workload)
+}
+
+func exportDataPoints(path string, dataPoints []metrics.DataPoint) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := metrics.WriteDataPoints(file, dataPoints, metrics.IsCSV(path)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,640 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-ottoscalr is a kubectl plugin to inspect the PolicyRecommendations generated by ottoscalr.
+//
+//	kubectl ottoscalr get reco [workload] [-n namespace]
+//	kubectl ottoscalr explain <workload> [-n namespace] [--debug-url http://localhost:8080]
+//	kubectl ottoscalr diff <workload> [-n namespace]
+//	kubectl ottoscalr freeze|unfreeze <workload> [-n namespace]
+//	kubectl ottoscalr pick <workload> <min-replicas> [-n namespace]
+//	kubectl ottoscalr retrigger [-n namespace] [-l selector]
+//	kubectl ottoscalr project-policy <policy-file> [-n namespace | -A]
+//	kubectl ottoscalr export [-n namespace | -A]
+//	kubectl ottoscalr import <snapshot-file> [-n namespace | -A] [--dry-run]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+const usage = `Inspect the HPA recommendations generated by ottoscalr.
+
+Usage:
+  kubectl ottoscalr get reco [workload] [-n namespace]
+  kubectl ottoscalr explain <workload> [-n namespace] [--debug-url url]
+  kubectl ottoscalr diff <workload> [-n namespace]
+  kubectl ottoscalr freeze <workload> [-n namespace]
+  kubectl ottoscalr unfreeze <workload> [-n namespace]
+  kubectl ottoscalr pick <workload> <min-replicas> [-n namespace]
+  kubectl ottoscalr retrigger [-n namespace] [-l selector]
+  kubectl ottoscalr project-policy <policy-file> [-n namespace | -A]
+  kubectl ottoscalr export [-n namespace | -A]
+  kubectl ottoscalr import <snapshot-file> [-n namespace | -A] [--dry-run]
+
+Flags:
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+}
+
+func main() {
+	namespace := flag.String("n", "default", "namespace of the workload")
+	selector := flag.String("l", "", "label selector of the workloads to re-trigger the recommendations of")
+	allNamespaces := flag.Bool("A", false, "project the policy, export or import across all the namespaces")
+	dryRun := flag.Bool("dry-run", false, "report what the import would change without changing it")
+	debugURL := flag.String("debug-url", "", "base url of the ottoscalr metrics server serving /debug/explanations, e.g. via kubectl port-forward")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// flags are allowed after the positional args as well, e.g. kubectl ottoscalr explain app -n ns
+	var args []string
+	for remaining := flag.Args(); len(remaining) > 0; remaining = flag.Args() {
+		args = append(args, remaining[0])
+		if err := flag.CommandLine.Parse(remaining[1:]); err != nil {
+			os.Exit(2)
+		}
+	}
+
+	if *allNamespaces {
+		*namespace = ""
+	}
+	if err := run(context.Background(), os.Stdout, *namespace, *selector, *debugURL, *dryRun, args); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, out io.Writer, namespace, selector, debugURL string, dryRun bool, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("no command specified")
+	}
+
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	command, args := args[0], args[1:]
+	if command == "get" {
+		if len(args) == 0 || (args[0] != "reco" && args[0] != "recos" && args[0] != "policyreco") {
+			return fmt.Errorf("usage: kubectl ottoscalr get reco [workload]")
+		}
+		return getRecos(ctx, out, k8sClient, namespace, args[1:])
+	}
+	if command == "retrigger" {
+		return retrigger(ctx, out, k8sClient, namespace, selector)
+	}
+	if command == "project-policy" {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: kubectl ottoscalr project-policy <policy-file>")
+		}
+		return projectPolicy(ctx, out, k8sClient, namespace, args[0])
+	}
+	if command == "export" {
+		return exportSnapshot(ctx, out, k8sClient, namespace)
+	}
+	if command == "import" {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: kubectl ottoscalr import <snapshot-file>")
+		}
+		return importSnapshot(ctx, out, k8sClient, namespace, args[0], dryRun)
+	}
+
+	if command == "pick" {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: kubectl ottoscalr pick <workload> <min-replicas>")
+		}
+		minReplicas, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid min replicas %q", args[1])
+		}
+		policyreco := &v1alpha1.PolicyRecommendation{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: args[0]}, policyreco); err != nil {
+			return err
+		}
+		return pick(ctx, out, k8sClient, policyreco, minReplicas)
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: kubectl ottoscalr %s <workload>", command)
+	}
+	policyreco := &v1alpha1.PolicyRecommendation{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: args[0]}, policyreco); err != nil {
+		return err
+	}
+
+	switch command {
+	case "explain":
+		return explain(out, policyreco, debugURL)
+	case "diff":
+		return diff(out, policyreco)
+	case "freeze":
+		return setFrozen(ctx, out, k8sClient, policyreco, true)
+	case "unfreeze":
+		return setFrozen(ctx, out, k8sClient, policyreco, false)
+	default:
+		return fmt.Errorf("unknown command %s", command)
+	}
+}
+
+func getRecos(ctx context.Context, out io.Writer, k8sClient client.Client, namespace string, args []string) error {
+	var policyrecos []v1alpha1.PolicyRecommendation
+	if len(args) > 0 {
+		policyreco := v1alpha1.PolicyRecommendation{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: args[0]}, &policyreco); err != nil {
+			return err
+		}
+		policyrecos = append(policyrecos, policyreco)
+	} else {
+		policyrecoList := &v1alpha1.PolicyRecommendationList{}
+		if err := k8sClient.List(ctx, policyrecoList, client.InNamespace(namespace)); err != nil {
+			return err
+		}
+		policyrecos = policyrecoList.Items
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKLOAD\tKIND\tPOLICY\tCURRENT\tTARGET\tSAVINGS\tFROZEN\tGENERATED")
+	for _, policyreco := range policyrecos {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+			policyreco.Spec.WorkloadMeta.Name,
+			policyreco.Spec.WorkloadMeta.Kind,
+			policyreco.Spec.Policy,
+			formatHPAConfig(policyreco.Spec.CurrentHPAConfiguration),
+			formatHPAConfig(policyreco.Spec.TargetHPAConfiguration),
+			formatPercent(policyreco.Status.ProjectedSavingsPercent),
+			isFrozen(policyreco),
+			formatAge(policyreco.Spec.GeneratedAt))
+	}
+	return w.Flush()
+}
+
+func explain(out io.Writer, policyreco *v1alpha1.PolicyRecommendation, debugURL string) error {
+	fmt.Fprintf(out, "Workload:\t%s/%s (%s)\n", policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind)
+	fmt.Fprintf(out, "Policy:\t\t%s\n", policyreco.Spec.Policy)
+	fmt.Fprintf(out, "Current:\t%s\n", formatHPAConfig(policyreco.Spec.CurrentHPAConfiguration))
+	fmt.Fprintf(out, "Target:\t\t%s\n", formatHPAConfig(policyreco.Spec.TargetHPAConfiguration))
+	if policyreco.Spec.PinnedHPAConfiguration != nil {
+		fmt.Fprintf(out, "Pinned:\t\t%s\n", formatHPAConfig(*policyreco.Spec.PinnedHPAConfiguration))
+	}
+	fmt.Fprintf(out, "Generated:\t%s ago\n", formatAge(policyreco.Spec.GeneratedAt))
+	if policyreco.Status.MetricsWindowStart != nil && policyreco.Status.MetricsWindowEnd != nil {
+		fmt.Fprintf(out, "Metrics:\t%s - %s, %s of the data points present\n",
+			policyreco.Status.MetricsWindowStart.Format(time.RFC3339), policyreco.Status.MetricsWindowEnd.Format(time.RFC3339),
+			formatPercent(policyreco.Status.DataPointsCoveragePercent))
+	}
+	fmt.Fprintf(out, "Savings:\t%s projected\n", formatPercent(policyreco.Status.ProjectedSavingsPercent))
+	fmt.Fprintf(out, "Confidence:\t%s\n", formatPercent(policyreco.Status.ConfidencePercent))
+
+	fmt.Fprintln(out, "\nWhy the current config differs from the target:")
+	for _, reason := range explainDrift(policyreco) {
+		fmt.Fprintf(out, "  - %s\n", reason)
+	}
+
+	fmt.Fprintln(out, "\nConditions:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tAGE\tMESSAGE")
+	for _, condition := range policyreco.Status.Conditions {
+		lastTransitionTime := condition.LastTransitionTime
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason,
+			formatAge(&lastTransitionTime), condition.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(policyreco.Status.SearchSpace) > 0 {
+		fmt.Fprintln(out, "\nSearch space, pick another point with kubectl ottoscalr pick <workload> <min-replicas>:")
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  MIN\tMAX SAFE TARGET\tSAVINGS\t")
+		for _, point := range policyreco.Status.SearchSpace {
+			chosen := ""
+			if point.MinReplicas == policyreco.Spec.TargetHPAConfiguration.Min &&
+				point.TargetUtilization == policyreco.Spec.TargetHPAConfiguration.TargetMetricValue {
+				chosen = "(target)"
+			}
+			fmt.Fprintf(w, "  %d\t%d\t%d%%\t%s\n", point.MinReplicas, point.TargetUtilization, point.ProjectedSavingsPercent,
+				chosen)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(debugURL) == 0 {
+		return nil
+	}
+	explanation, err := fetchExplanation(debugURL, policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the explanation: %w", err)
+	}
+	decision := explanation.PolicyDecision
+	fmt.Fprintf(out, "\nLast recommendation generated at %s:\n", explanation.GeneratedAt.Format(time.RFC3339))
+	if len(explanation.Error) > 0 {
+		fmt.Fprintf(out, "  error: %s\n", explanation.Error)
+	}
+	fmt.Fprintf(out, "  data points coverage: %d%%, transformers: %v\n", explanation.DataPointsCoveragePercent, explanation.TransformersApplied)
+	for _, audit := range explanation.TransformerAudits {
+		fmt.Fprintf(out, "  transformer %s removed %d and modified %d data points\n", audit.Transformer,
+			audit.RemovedDataPoints, audit.ModifiedDataPoints)
+		for _, interval := range audit.ExcludedIntervals {
+			fmt.Fprintf(out, "    excluded %s - %s %s\n", interval.Start.Format(time.RFC3339),
+				interval.End.Format(time.RFC3339), interval.Reason)
+		}
+	}
+	if explanation.MetricStepSeconds > 0 {
+		fmt.Fprintf(out, "  metric step: %s\n", time.Duration(explanation.MetricStepSeconds)*time.Second)
+	}
+	if explanation.ExcludedDowntimeSeconds > 0 {
+		fmt.Fprintf(out, "  excluded downtime: %s\n", time.Duration(explanation.ExcludedDowntimeSeconds)*time.Second)
+	}
+	if explanation.BreachFreeMaxReplicas > 0 {
+		fmt.Fprintf(out, "  breach free max replicas: %d\n", explanation.BreachFreeMaxReplicas)
+	}
+	if explanation.DerivedMaxReplicas > 0 {
+		fmt.Fprintf(out, "  derived max replicas: %d\n", explanation.DerivedMaxReplicas)
+	}
+	if len(explanation.Config) > 0 {
+		fmt.Fprintf(out, "  config: %s\n", explanation.Config)
+	}
+	if len(explanation.RedLineTier) > 0 {
+		fmt.Fprintf(out, "  redline utilization: %v of the tier %q\n", explanation.RedLineUtilization, explanation.RedLineTier)
+	}
+	for _, iteratorPolicy := range decision.IteratorPolicies {
+		fmt.Fprintf(out, "  policy iterator %s recommended %q\n", iteratorPolicy.Iterator, iteratorPolicy.Policy)
+	}
+	fmt.Fprintf(out, "  next policy: %q, target reco applied: %t, applied policy: %q\n", decision.NextPolicy,
+		decision.TargetRecoApplied, decision.AppliedPolicy)
+	if len(explanation.PodDisruptionBudgets) > 0 {
+		fmt.Fprintf(out, "  pod disruption budgets: %v, min replicas required: %d\n", explanation.PodDisruptionBudgets,
+			explanation.PodDisruptionBudgetMinReplicas)
+	}
+	if len(explanation.ResourceQuotas) > 0 {
+		fmt.Fprintf(out, "  resource quotas: %v\n", explanation.ResourceQuotas)
+	}
+	for _, warning := range explanation.Warnings {
+		fmt.Fprintf(out, "  warning: %s\n", warning)
+	}
+
+	details := explanation.Simulation
+	if details == nil {
+		return nil
+	}
+	fmt.Fprintf(out, "\nLast simulation at %s:\n", details.SimulatedAt.Format(time.RFC3339))
+	fmt.Fprintf(out, "  data points: %d, acl: %s, per pod resources: %.2f, max replicas: %d, targets: %d-%d\n",
+		details.DataPoints, details.ACL, details.PerPodResources, details.MaxReplicas, details.MinTarget, details.MaxTarget)
+	if len(details.Error) > 0 {
+		fmt.Fprintf(out, "  error: %s\n", details.Error)
+	}
+	fmt.Fprintf(out, "  chosen: min %d, target %d\n", details.ChosenMinReplicas, details.ChosenTarget)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  MIN\tMAX SAFE TARGET\tQUALIFIED\tSAVINGS\tTRIALS")
+	for _, candidate := range details.Candidates {
+		trials := ""
+		if candidate.Pruned {
+			trials = "pruned"
+		}
+		for _, trial := range candidate.Trials {
+			result := "ok"
+			if trial.Breached {
+				result = "breach"
+			}
+			trials += fmt.Sprintf("%d:%s ", trial.TargetUtilization, result)
+		}
+		fmt.Fprintf(w, "  %d\t%d\t%t\t%.2f%%\t%s\n", candidate.MinReplicas, candidate.TargetUtilization,
+			candidate.Qualified, candidate.Savings, trials)
+	}
+	return w.Flush()
+}
+
+// explainDrift lists the reasons for the current HPA config of the policyreco to differ from the target reco.
+func explainDrift(policyreco *v1alpha1.PolicyRecommendation) []string {
+	var reasons []string
+	if isFrozen(*policyreco) {
+		reasons = append(reasons, fmt.Sprintf("The recommendation is frozen with the %s annotation.", v1alpha1.FreezeRecommendationAnnotation))
+	}
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.NoOpRecommended) && condition.Status == metav1.ConditionTrue {
+			reasons = append(reasons, fmt.Sprintf("The workload couldn't be recommended a config (%s: %s), so the target is the no-op configuration keeping it at its max replicas.",
+				condition.Reason, condition.Message))
+		}
+	}
+	if policyreco.Spec.PinnedHPAConfiguration != nil {
+		reasons = append(reasons, "The workload is pinned to an HPA configuration with the pinnedHPAConfig of its policyreco, so the current config stays the pinned one and its autoscaler isn't enforced.")
+	}
+	if policyreco.Spec.CurrentHPAConfiguration.DeepEquals(policyreco.Spec.TargetHPAConfiguration) {
+		return append(reasons, "The current config is at the target recommendation.")
+	}
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.HasBreached) && condition.Status == metav1.ConditionTrue {
+			reasons = append(reasons, fmt.Sprintf("A breach of the redline utilization was detected %s ago, so the workload was moved to a safer policy.",
+				formatAge(&condition.LastTransitionTime)))
+		}
+	}
+	if len(policyreco.Spec.Policy) > 0 {
+		reasons = append(reasons, fmt.Sprintf("The workload is at the policy %s. Policies are relaxed one step at a time towards the target "+
+			"recommendation once the current policy ages beyond the policy expiry age (last transitioned %s ago).",
+			policyreco.Spec.Policy, formatAge(policyreco.Spec.TransitionedAt)))
+	}
+	if policyreco.Spec.MinReplicaFloor != nil || policyreco.Spec.MaxReplicaCeiling != nil || policyreco.Spec.MaxTargetUtilization != nil {
+		reasons = append(reasons, fmt.Sprintf("The workload owners have overridden the configs with min replica floor %s, max replica ceiling %s and max target utilization %s.",
+			formatOptional(policyreco.Spec.MinReplicaFloor), formatOptional(policyreco.Spec.MaxReplicaCeiling), formatOptional(policyreco.Spec.MaxTargetUtilization)))
+	}
+	return reasons
+}
+
+func diff(out io.Writer, policyreco *v1alpha1.PolicyRecommendation) error {
+	current := policyreco.Spec.CurrentHPAConfiguration
+	target := policyreco.Spec.TargetHPAConfiguration
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tCURRENT\tTARGET\tDELTA")
+	fmt.Fprintf(w, "min\t%d\t%d\t%+d\n", current.Min, target.Min, target.Min-current.Min)
+	fmt.Fprintf(w, "max\t%d\t%d\t%+d\n", current.Max, target.Max, target.Max-current.Max)
+	fmt.Fprintf(w, "targetMetricValue\t%d\t%d\t%+d\n", current.TargetMetricValue, target.TargetMetricValue,
+		target.TargetMetricValue-current.TargetMetricValue)
+	if current.GetMetricName() != target.GetMetricName() || current.GetTargetMetricType() != target.GetTargetMetricType() {
+		fmt.Fprintf(w, "metric\t%s/%s\t%s/%s\t\n", current.GetMetricName(), current.GetTargetMetricType(),
+			target.GetMetricName(), target.GetTargetMetricType())
+	}
+	return w.Flush()
+}
+
+func setFrozen(ctx context.Context, out io.Writer, k8sClient client.Client, policyreco *v1alpha1.PolicyRecommendation, frozen bool) error {
+	patch := client.MergeFrom(policyreco.DeepCopy())
+	annotations := policyreco.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if frozen {
+		annotations[v1alpha1.FreezeRecommendationAnnotation] = strconv.FormatBool(true)
+	} else {
+		delete(annotations, v1alpha1.FreezeRecommendationAnnotation)
+	}
+	policyreco.SetAnnotations(annotations)
+	if err := k8sClient.Patch(ctx, policyreco, patch); err != nil {
+		return err
+	}
+	if frozen {
+		fmt.Fprintf(out, "policyrecommendation %s/%s frozen\n", policyreco.Namespace, policyreco.Name)
+	} else {
+		fmt.Fprintf(out, "policyrecommendation %s/%s unfrozen, it will be picked up by the next recommendation trigger\n",
+			policyreco.Namespace, policyreco.Name)
+	}
+	return nil
+}
+
+// pick applies the point of the search space of the policyreco at the min replicas as both its target and current
+// HPA configurations, and freezes it so that the next recommendations don't override the choice.
+func pick(ctx context.Context, out io.Writer, k8sClient client.Client, policyreco *v1alpha1.PolicyRecommendation, minReplicas int) error {
+	var picked *v1alpha1.SearchSpacePoint
+	for i := range policyreco.Status.SearchSpace {
+		if policyreco.Status.SearchSpace[i].MinReplicas == minReplicas {
+			picked = &policyreco.Status.SearchSpace[i]
+		}
+	}
+	if picked == nil {
+		return fmt.Errorf("min replicas %d isn't in the search space of policyrecommendation %s/%s", minReplicas,
+			policyreco.Namespace, policyreco.Name)
+	}
+
+	patch := client.MergeFrom(policyreco.DeepCopy())
+	config := policyreco.Spec.TargetHPAConfiguration
+	config.Min, config.TargetMetricValue = picked.MinReplicas, picked.TargetUtilization
+	if config.Max < config.Min {
+		config.Max = config.Min
+	}
+	policyreco.Spec.TargetHPAConfiguration = config
+	policyreco.Spec.CurrentHPAConfiguration = config
+	annotations := policyreco.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1alpha1.FreezeRecommendationAnnotation] = strconv.FormatBool(true)
+	policyreco.SetAnnotations(annotations)
+	if err := k8sClient.Patch(ctx, policyreco, patch); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "policyrecommendation %s/%s set to %s, projected to save %d%%, and frozen\n", policyreco.Namespace,
+		policyreco.Name, formatHPAConfig(config), picked.ProjectedSavingsPercent)
+	return nil
+}
+
+// retrigger annotates the namespace for ottoscalr to queue the recommendations of the workloads matching the
+// selector for a fresh recommendation.
+func retrigger(ctx context.Context, out io.Writer, k8sClient client.Client, namespace, selector string) error {
+	if _, err := labels.Parse(selector); err != nil {
+		return err
+	}
+	ns := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(ns.DeepCopy())
+	annotations := ns.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1alpha1.RetriggerRecommendationsAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if len(selector) > 0 {
+		annotations[v1alpha1.RetriggerSelectorAnnotation] = selector
+	} else {
+		delete(annotations, v1alpha1.RetriggerSelectorAnnotation)
+	}
+	ns.SetAnnotations(annotations)
+	if err := k8sClient.Patch(ctx, ns, patch); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "recommendations of the workloads in namespace %s queued for re-generation\n", namespace)
+	return nil
+}
+
+// projectPolicy reports how applying the Policy in the file would change the configs of the workloads, without
+// applying it.
+func projectPolicy(ctx context.Context, out io.Writer, k8sClient client.Client, namespace, policyFile string) error {
+	content, err := os.ReadFile(policyFile)
+	if err != nil {
+		return err
+	}
+	candidate := v1alpha1.Policy{}
+	if err := yaml.Unmarshal(content, &candidate); err != nil {
+		return err
+	}
+	projection, err := reco.NewPolicyProjector(k8sClient, policy.NewPolicyStore(k8sClient)).Project(ctx, candidate, namespace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Policy:\t\t\t%s\n", projection.Policy)
+	fmt.Fprintf(out, "Workloads:\t\t%d\n", projection.Workloads)
+	fmt.Fprintf(out, "Changed workloads:\t%d\n", projection.ChangedWorkloads)
+	fmt.Fprintf(out, "Min replicas delta:\t%+d\n", projection.MinReplicasDelta)
+	fmt.Fprintf(out, "Projected savings:\t%.1f%%\n", projection.ProjectedSavingsPercent)
+	if len(projection.Changes) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tWORKLOAD\tKIND\tCURRENT\tPROJECTED")
+	for _, change := range projection.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", change.Namespace, change.Workload, change.Kind,
+			formatHPAConfig(change.Current), formatHPAConfig(change.Projected))
+	}
+	return w.Flush()
+}
+
+// exportSnapshot writes the snapshot of the policies and the policyrecos of the namespace as YAML, to be imported in
+// another cluster.
+func exportSnapshot(ctx context.Context, out io.Writer, k8sClient client.Client, namespace string) error {
+	snapshot, err := reco.NewPolicySnapshotter(k8sClient).Export(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(content)
+	return err
+}
+
+// importSnapshot restores the policyrecos of the namespace from the snapshot in the file, along with the policies
+// missing from the cluster when importing across all the namespaces.
+func importSnapshot(ctx context.Context, out io.Writer, k8sClient client.Client, namespace, snapshotFile string,
+	dryRun bool) error {
+	content, err := os.ReadFile(snapshotFile)
+	if err != nil {
+		return err
+	}
+	snapshot := reco.PolicySnapshot{}
+	if err := yaml.Unmarshal(content, &snapshot); err != nil {
+		return err
+	}
+	result, err := reco.NewPolicySnapshotter(k8sClient).Import(ctx, snapshot, namespace, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Fprintln(out, "Dry run, nothing was changed.")
+	}
+	for _, policy := range result.CreatedPolicies {
+		fmt.Fprintf(out, "policy %s created\n", policy)
+	}
+	for _, policy := range result.ConflictingPolicies {
+		fmt.Fprintf(out, "policy %s exists with another spec, left as it is\n", policy)
+	}
+	for _, policyreco := range result.RestoredPolicyRecommendations {
+		fmt.Fprintf(out, "policyrecommendation %s restored\n", policyreco)
+	}
+	for _, policyreco := range result.CreatedPolicyRecommendations {
+		fmt.Fprintf(out, "policyrecommendation %s created, it will be adopted by its workload once onboarded\n", policyreco)
+	}
+	skipped := make([]string, 0, len(result.SkippedPolicyRecommendations))
+	for policyreco := range result.SkippedPolicyRecommendations {
+		skipped = append(skipped, policyreco)
+	}
+	sort.Strings(skipped)
+	for _, policyreco := range skipped {
+		fmt.Fprintf(out, "policyrecommendation %s skipped: %s\n", policyreco, result.SkippedPolicyRecommendations[policyreco])
+	}
+	return nil
+}
+
+func fetchExplanation(debugURL, namespace, workload string) (*reco.Explanation, error) {
+	query := url.Values{}
+	query.Set("namespace", namespace)
+	query.Set("workload", workload)
+	resp, err := http.Get(fmt.Sprintf("%s/debug/explanations?%s", debugURL, query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("debug endpoint responded with status code %d", resp.StatusCode)
+	}
+	explanation := &reco.Explanation{}
+	if err := json.NewDecoder(resp.Body).Decode(explanation); err != nil {
+		return nil, err
+	}
+	return explanation, nil
+}
+
+func isFrozen(policyreco v1alpha1.PolicyRecommendation) bool {
+	frozen, _ := strconv.ParseBool(policyreco.GetAnnotations()[v1alpha1.FreezeRecommendationAnnotation])
+	return frozen
+}
+
+func formatHPAConfig(config v1alpha1.HPAConfiguration) string {
+	return fmt.Sprintf("min=%d max=%d %s=%d", config.Min, config.Max, config.GetMetricName(), config.TargetMetricValue)
+}
+
+func formatPercent(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", *value)
+}
+
+func formatOptional(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatAge(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return time.Since(t.Time).Round(time.Second).String()
+}
@@ -18,23 +18,38 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"flag"
+	"fmt"
 	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/apiserver"
+	"github.com/flipkart-incubator/ottoscalr/pkg/approval"
+	"github.com/flipkart-incubator/ottoscalr/pkg/audit"
 	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
 	"github.com/flipkart-incubator/ottoscalr/pkg/controller"
+	"github.com/flipkart-incubator/ottoscalr/pkg/fleet"
+	"github.com/flipkart-incubator/ottoscalr/pkg/health"
 	"github.com/flipkart-incubator/ottoscalr/pkg/integration"
 	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
 	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
 	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
 	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/report"
+	"github.com/flipkart-incubator/ottoscalr/pkg/silence"
+	"github.com/flipkart-incubator/ottoscalr/pkg/tracing"
 	"github.com/flipkart-incubator/ottoscalr/pkg/transformer"
 	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
 	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/viper"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"reflect"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"strings"
 	"syscall"
 	"time"
@@ -50,19 +65,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	ottoscaleriov1beta1 "github.com/flipkart-incubator/ottoscalr/api/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
 var (
-	scaledTargetName = "spec.scaleTargetRef.name"
-	scheme           = runtime.NewScheme()
-	setupLog         = ctrl.Log.WithName("setup")
+	scheme   = runtime.NewScheme()
+	setupLog = ctrl.Log.WithName("setup")
 )
 
+// savingsReportAuthTokenEnv holds the bearer token for uploading the savings reports to the object storage.
+const savingsReportAuthTokenEnv = "OTTOSCALR_SAVINGS_REPORT_AUTH_TOKEN"
+
+// idleWorkloadsReportAuthTokenEnv holds the bearer token for uploading the idle workloads reports to the object storage.
+const idleWorkloadsReportAuthTokenEnv = "OTTOSCALR_IDLE_WORKLOADS_REPORT_AUTH_TOKEN"
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(argov1alpha1.AddToScheme(scheme))
 	utilruntime.Must(ottoscaleriov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(ottoscaleriov1beta1.AddToScheme(scheme))
 	utilruntime.Must(kedaapi.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
@@ -77,8 +99,31 @@ type Config struct {
 		PrometheusUrl        string `yaml:"prometheusUrl"`
 		QueryTimeoutSec      int    `yaml:"queryTimeoutSec"`
 		QuerySplitIntervalHr int    `yaml:"querySplitIntervalHr"`
+		// BearerTokenFile, or Username and PasswordFile, hold the credentials the Prometheus instances are queried
+		// with.
+		BearerTokenFile string `yaml:"bearerTokenFile"`
+		Username        string `yaml:"username"`
+		PasswordFile    string `yaml:"passwordFile"`
+		// RemoteRead fetches the cpu utilization of the workloads over the windows of at least MinWindowHr with the
+		// remote read protocol instead of the range queries.
+		RemoteRead struct {
+			Enabled     bool `yaml:"enabled"`
+			MinWindowHr int  `yaml:"minWindowHr"`
+		} `yaml:"remoteRead"`
+		// QueryTemplates override the default queries of the scraper for the sites with custom relabeling.
+		QueryTemplates metrics.QueryTemplates `yaml:"queryTemplates"`
+		// LabelScheme picks the query templates of the label scheme of the cluster's metrics, or detects it if auto.
+		// The QueryTemplates override the ones of the scheme.
+		LabelScheme string `yaml:"labelScheme"`
 	} `yaml:"metricsScraper"`
 
+	MetricsRouting struct {
+		// Routes send the queries of the workloads whose namespace and name match their patterns to other Prometheus
+		// instances, e.g. a Thanos tenant, the first matching route winning. The other settings of the metricsScraper
+		// apply to them too.
+		Routes []MetricsRoute `yaml:"routes"`
+	} `yaml:"metricsRouting"`
+
 	BreachMonitor struct {
 		PollingIntervalSec   int     `yaml:"pollingIntervalSec"`
 		CpuRedLine           float64 `yaml:"cpuRedLine"`
@@ -87,13 +132,69 @@ type Config struct {
 	} `yaml:"breachMonitor"`
 
 	PeriodicTrigger struct {
-		PollingIntervalMin int `yaml:"pollingIntervalMin"`
+		PollingIntervalMin int    `yaml:"pollingIntervalMin"`
+		Schedule           string `yaml:"schedule"`
 	} `yaml:"periodicTrigger"`
 
 	PolicyRecommendationController struct {
 		MaxConcurrentReconciles int    `yaml:"maxConcurrentReconciles"`
 		MinRequiredReplicas     int    `yaml:"minRequiredReplicas"`
 		PolicyExpiryAge         string `yaml:"policyExpiryAge"`
+		WorkflowWorkers         int    `yaml:"workflowWorkers"`
+		WorkflowQueueLength     int    `yaml:"workflowQueueLength"`
+		// InteractiveMaxConcurrentReconciles are the reconciles of the queue of the policy recommendations the users
+		// queued, by re-triggering them or onboarding their workloads. They share the one queue if zero.
+		InteractiveMaxConcurrentReconciles int `yaml:"interactiveMaxConcurrentReconciles"`
+		// RespectPodDisruptionBudgets keeps the recommended min replicas high enough for the PodDisruptionBudgets of
+		// the workloads to allow evictions. Enabled unless set to false.
+		RespectPodDisruptionBudgets *bool `yaml:"respectPodDisruptionBudgets"`
+		// RespectResourceQuotas clamps the recommended max replicas to the replicas the ResourceQuotas of the
+		// namespaces leave the workloads the room to scale up to. Enabled unless set to false.
+		RespectResourceQuotas *bool `yaml:"respectResourceQuotas"`
+		// DiffThreshold is the least change of the recommended config which is enforced.
+		DiffThreshold struct {
+			TargetMetricValue int `yaml:"targetMetricValue"`
+			// TargetMetricValueRaise and TargetMetricValueLower override TargetMetricValue for the raises and the
+			// cuts of the target, if set.
+			TargetMetricValueRaise *int `yaml:"targetMetricValueRaise"`
+			TargetMetricValueLower *int `yaml:"targetMetricValueLower"`
+			MinReplicas            int  `yaml:"minReplicas"`
+		} `yaml:"diffThreshold"`
+		// StaleDataGrace keeps the previous recommendation of the workloads whose metrics fall short of the coverage
+		// threshold for the period and the recommendations in a row, before recommending the no-op configuration.
+		StaleDataGrace struct {
+			Period          string `yaml:"period"`
+			Recommendations int    `yaml:"recommendations"`
+		} `yaml:"staleDataGrace"`
+		// AnomalyGuard flags the recommendations whose min replicas drop by more than minDropPercent or whose target
+		// moves by targetJumpPoints or more from the previous recommendation, holding them back with block.
+		AnomalyGuard struct {
+			MinDropPercent   int  `yaml:"minDropPercent"`
+			TargetJumpPoints int  `yaml:"targetJumpPoints"`
+			Block            bool `yaml:"block"`
+		} `yaml:"anomalyGuard"`
+		// PolicySkipping promotes the workloads whose target recommendation is safe further up the policy ladder past
+		// up to maxSkip policies at once, when the confidence of their recommendation is at least minConfidencePercent.
+		PolicySkipping struct {
+			MaxSkip              int `yaml:"maxSkip"`
+			MinConfidencePercent int `yaml:"minConfidencePercent"`
+		} `yaml:"policySkipping"`
+		// WorkloadExclusion skips the recommendations of the workloads owned by the ownerKinds, Jobs and CronJobs by
+		// default, or matching any of the label selectors.
+		WorkloadExclusion struct {
+			OwnerKinds *[]string `yaml:"ownerKinds"`
+			Labels     []string  `yaml:"labels"`
+		} `yaml:"workloadExclusion"`
+		// ChangeApproval holds back the policy transitions of the workloads until the change management system,
+		// fronted by the webhookUrl, approves them. Only the promotions are held back unless the transitions are set.
+		ChangeApproval struct {
+			Enabled            *bool    `yaml:"enabled"`
+			WebhookUrl         string   `yaml:"webhookUrl"`
+			TimeoutSec         int      `yaml:"timeoutSec"`
+			ApprovalTimeoutMin int      `yaml:"approvalTimeoutMin"`
+			PollIntervalSec    int      `yaml:"pollIntervalSec"`
+			Transitions        []string `yaml:"transitions"`
+		} `yaml:"changeApproval"`
 	} `yaml:"policyRecommendationController"`
 
 	HPAEnforcer struct {
@@ -103,12 +204,59 @@ type Config struct {
 		IsDryRun                *bool  `yaml:"isDryRun"`
 		WhitelistMode           *bool  `yaml:"whitelistMode"`
 		MinRequiredReplicas     int    `yaml:"minRequiredReplicas"`
+		// VPAGuardrails checks the workloads for VerticalPodAutoscalers, holding the autoscaling of the workloads whose
+		// VPAs resize the metric they're autoscaled on. Needs the VPA CRD to be installed.
+		VPAGuardrails bool `yaml:"vpaGuardrails"`
+		// MinConfidencePercent holds back the cuts of the min replicas of the workloads whose recommendations are
+		// less confident than it. The default of 0 enforces every recommendation.
+		MinConfidencePercent int `yaml:"minConfidencePercent"`
+		// DependencyOrdering holds back the cuts of the min replicas of the workloads till their callers, declared with
+		// the ottoscalr.io/depends-on annotation, and their groups apply their recommendations.
+		DependencyOrdering bool `yaml:"dependencyOrdering"`
+		// EnforcementBudget creates the first autoscalers of the workloads in the descending order of their projected
+		// savings, no more than workloadsPerDay of them a day, when ottoscalr adopts a cluster.
+		EnforcementBudget struct {
+			Enabled         *bool `yaml:"enabled"`
+			WorkloadsPerDay int   `yaml:"workloadsPerDay"`
+		} `yaml:"enforcementBudget"`
+		// ShadowAdoption shadows the HPAs the owners of the workloads created for days before adopting the workloads
+		// from them, and adopts a workload only once its recommendation performs on par with its HPA or better.
+		ShadowAdoption struct {
+			Enabled *bool `yaml:"enabled"`
+			Days    int   `yaml:"days"`
+		} `yaml:"shadowAdoption"`
+		// EnforcementPreview previews every change of the autoscalers in the status of the policyrecos for minutes
+		// before applying it.
+		EnforcementPreview struct {
+			Enabled *bool `yaml:"enabled"`
+			Minutes int   `yaml:"minutes"`
+		} `yaml:"enforcementPreview"`
+		// IdleWindows lowers the min replicas of the ScaledObjects in the idle windows of their workloads, restoring
+		// them with a cron trigger outside of the windows.
+		IdleWindows bool `yaml:"idleWindows"`
+		// AlertSilences silences the capacity alerts of the workloads in Alertmanager for durationMin after their
+		// autoscalers change to drop their capacity. The alerts are matched by the namespace, the workloadLabel naming
+		// the workload and the alertNames, which are required.
+		AlertSilences struct {
+			Enabled         *bool    `yaml:"enabled"`
+			AlertmanagerUrl string   `yaml:"alertmanagerUrl"`
+			TimeoutSec      int      `yaml:"timeoutSec"`
+			DurationMin     int      `yaml:"durationMin"`
+			WorkloadLabel   string   `yaml:"workloadLabel"`
+			AlertNames      []string `yaml:"alertNames"`
+		} `yaml:"alertSilences"`
 	} `yaml:"hpaEnforcer"`
 
 	PolicyRecommendationRegistrar struct {
 		RequeueDelayMs     int    `yaml:"requeueDelayMs"`
 		ExcludedNamespaces string `yaml:"excludedNamespaces"`
 		IncludedNamespaces string `yaml:"includedNamespaces"`
+		// Criticality starts the workloads onboarded up the policy ladder by the steps of the value of their label,
+		// or annotation, at the key, instead of at the safest policy.
+		Criticality struct {
+			Key   string         `yaml:"key"`
+			Steps map[string]int `yaml:"steps"`
+		} `yaml:"criticality"`
 	} `yaml:"policyRecommendationRegistrar"`
 
 	CpuUtilizationBasedRecommender struct {
@@ -117,10 +265,139 @@ type Config struct {
 		MinTarget                  int `yaml:"minTarget"`
 		MaxTarget                  int `yaml:"minTarget"`
 		MetricsPercentageThreshold int `yaml:"metricsPercentageThreshold"`
+		MinWorkloadAgeDays         int `yaml:"minWorkloadAgeDays"`
+
+		// ACLStrategy aggregates the pod ready latencies of the workloads into their ACL by their p50, mean, p90 or
+		// p99. The workloads override it with the ottoscalr.io/acl-strategy annotation.
+		ACLStrategy string `yaml:"aclStrategy"`
+
+		// OversizedPodsUtilizationPercent flags the workloads peaking below the percent of the resources of their
+		// recommended min replicas as needing smaller pods.
+		OversizedPodsUtilizationPercent int `yaml:"oversizedPodsUtilizationPercent"`
+
+		// MaxReplicasAnalysis reports the smallest max replicas which would have avoided the breaches of every
+		// workload over its metrics window, without enforcing it.
+		MaxReplicasAnalysis *bool `yaml:"maxReplicasAnalysis"`
+
+		// SearchSpace attaches the highest target without breaches and its savings at every min replicas to the
+		// recommendations, for the users to pick another point of the trade-off than the one saving the most.
+		SearchSpace *bool `yaml:"searchSpace"`
+
+		// DerivedMaxPods derives the max replicas of the workloads without a max pods annotation or a ScaledObject
+		// from the replicas their peak utilization needs, plus the headroomPercent, instead of their current replicas.
+		DerivedMaxPods struct {
+			Enabled         *bool `yaml:"enabled"`
+			HeadroomPercent int   `yaml:"headroomPercent"`
+		} `yaml:"derivedMaxPods"`
+
+		// NetworkCeilingBytesPerSec keeps the network throughput of every pod under the ceiling along with its cpu
+		// utilization.
+		NetworkCeilingBytesPerSec float64 `yaml:"networkCeilingBytesPerSec"`
+
+		// BreachAssertions evaluates the PromQL assertions of the ottoscalr.io/breach-assertion annotations of the
+		// workloads, e.g. on their p99 latency or error rate, as their breaches along with the cpu redline.
+		BreachAssertions struct {
+			Enabled *bool `yaml:"enabled"`
+		} `yaml:"breachAssertions"`
+
+		// PodResizeNormalization carries the datapoints recorded before the pods of a workload were resized over to
+		// their current size.
+		PodResizeNormalization struct {
+			Enabled                  *bool `yaml:"enabled"`
+			MinWindowAfterResizeDays int   `yaml:"minWindowAfterResizeDays"`
+		} `yaml:"podResizeNormalization"`
+
+		// ContainerUtilization combines the utilization of the containers of the pods, in the weighted or the max mode,
+		// instead of dividing their aggregate cpu usage by the sum of their limits.
+		ContainerUtilization struct {
+			Mode    string             `yaml:"mode"`
+			Weights map[string]float64 `yaml:"weights"`
+		} `yaml:"containerUtilization"`
+
+		IncrementalReuse struct {
+			Enabled                 *bool `yaml:"enabled"`
+			MaxWindowDeltaHours     int   `yaml:"maxWindowDeltaHours"`
+			FullSearchIntervalHours int   `yaml:"fullSearchIntervalHours"`
+			MaxWorkloads            int   `yaml:"maxWorkloads"`
+		} `yaml:"incrementalReuse"`
+		CronTriggers struct {
+			Enabled              *bool   `yaml:"enabled"`
+			PreScaleMinutes      int     `yaml:"preScaleMinutes"`
+			PeakFactor           float64 `yaml:"peakFactor"`
+			MinRecurrencePercent int     `yaml:"minRecurrencePercent"`
+			MinDays              int     `yaml:"minDays"`
+		} `yaml:"cronTriggers"`
+		IdleWindows struct {
+			Enabled              *bool   `yaml:"enabled"`
+			IdleFactor           float64 `yaml:"idleFactor"`
+			MinRecurrencePercent int     `yaml:"minRecurrencePercent"`
+			MinDays              int     `yaml:"minDays"`
+			MinDurationMinutes   int     `yaml:"minDurationMinutes"`
+		} `yaml:"idleWindows"`
+		RecencyWeighting struct {
+			Enabled         *bool   `yaml:"enabled"`
+			HalfLifeDays    int     `yaml:"halfLifeDays"`
+			MinBreachWeight float64 `yaml:"minBreachWeight"`
+		} `yaml:"recencyWeighting"`
+		NodeHeadroom struct {
+			CPUs                   float64 `yaml:"cpus"`
+			ProvisioningPenaltySec int     `yaml:"provisioningPenaltySec"`
+		} `yaml:"nodeHeadroom"`
+		// RedLineTiers override the cpuRedLine of the breach monitor for the workloads whose annotation or label
+		// named by the key holds one of the tiers.
+		RedLineTiers struct {
+			Key      string             `yaml:"key"`
+			RedLines map[string]float64 `yaml:"redLines"`
+		} `yaml:"redLineTiers"`
+
+		// HPATolerance is the tolerance of the HPA controller of the cluster the HPA simulations model. It defaults
+		// to the 0.1 of the kube-controller-manager.
+		HPATolerance *float64 `yaml:"hpaTolerance"`
+
+		// KEDATimings simulates the workloads scaled by a ScaledObject with its pollingInterval and cooldownPeriod
+		// rather than rescaling them at every datapoint.
+		KEDATimings *bool `yaml:"kedaTimings"`
+
+		// MetricWindowBounds bound the metric windows the workloads can override with the ottoscalr.io/metric-window
+		// annotation.
+		MetricWindowBounds struct {
+			MinDays int `yaml:"minDays"`
+			MaxDays int `yaml:"maxDays"`
+		} `yaml:"metricWindowBounds"`
+
+		// AutoStep derives the step of the datapoints from the metric window of every workload, as the least multiple
+		// of minStepSec keeping the datapoints of the window within maxDataPoints, instead of scraping at stepSec.
+		AutoStep struct {
+			Enabled       *bool `yaml:"enabled"`
+			MinStepSec    int   `yaml:"minStepSec"`
+			MaxDataPoints int   `yaml:"maxDataPoints"`
+		} `yaml:"autoStep"`
+
+		// MultiResolution scans the metric windows at coarseFactor times the step and verifies the recommended
+		// configs at the step within peakWindowMin around the peak of every day.
+		MultiResolution struct {
+			Enabled       *bool `yaml:"enabled"`
+			CoarseFactor  int   `yaml:"coarseFactor"`
+			PeakWindowMin int   `yaml:"peakWindowMin"`
+		} `yaml:"multiResolution"`
 	} `yaml:"cpuUtilizationBasedRecommender"`
+	KafkaLagBasedRecommender struct {
+		Enabled            *bool `yaml:"enabled"`
+		MetricWindowInDays int   `yaml:"metricWindowInDays"`
+		StepSec            int   `yaml:"stepSec"`
+		TargetLag          int   `yaml:"targetLag"`
+	} `yaml:"kafkaLagBasedRecommender"`
+	QueueDepthBasedRecommender struct {
+		Enabled            *bool                           `yaml:"enabled"`
+		MetricWindowInDays int                             `yaml:"metricWindowInDays"`
+		StepSec            int                             `yaml:"stepSec"`
+		TargetDepth        int                             `yaml:"targetDepth"`
+		Queries            map[string]metrics.QueueQueries `yaml:"queries"`
+	} `yaml:"queueDepthBasedRecommender"`
 	MetricIngestionTime      float64 `yaml:"metricIngestionTime"`
 	MetricProbeTime          float64 `yaml:"metricProbeTime"`
 	EnableMetricsTransformer *bool   `yaml:"enableMetricsTransformation"`
+	Timezone                 string  `yaml:"timezone"`
 	EventCallIntegration     struct {
 		EventCalendarAPIEndpoint        string `yaml:"eventCalendarAPIEndpoint"`
 		NfrEventCompletedAPIEndpoint    string `yaml:"nfrEventCompletedAPIEndpoint"`
@@ -132,8 +409,126 @@ type Config struct {
 	AutoscalerClient struct {
 		EnableScaledObject *bool  `yaml:"enableScaledObject"`
 		HpaAPIVersion      string `yaml:"hpaAPIVersion"`
+		// HPABehaviorMergeStrategy is how the behavior of the HPAs already scaling the workloads is carried into their
+		// ScaledObjects, overridden per namespace by the ottoscalr.io/hpa-behavior-merge-strategy annotation.
+		HPABehaviorMergeStrategy string `yaml:"hpaBehaviorMergeStrategy"`
+		// ScaledObjectTemplate sets the fields of the ScaledObjects KEDA otherwise defaults, overridden per namespace by
+		// the scaledObject of their OttoscalrConfigs.
+		ScaledObjectTemplate autoscaler.ScaledObjectTemplate `yaml:"scaledObjectTemplate"`
 	} `yaml:"autoscalerClient"`
+	Audit struct {
+		Stdout            bool   `yaml:"stdout"`
+		FilePath          string `yaml:"filePath"`
+		WebhookUrl        string `yaml:"webhookUrl"`
+		WebhookTimeoutSec int    `yaml:"webhookTimeoutSec"`
+	} `yaml:"audit"`
+	Notifier struct {
+		RecommendationChangeThresholdPercent int    `yaml:"recommendationChangeThresholdPercent"`
+		RepeatIntervalMinutes                int    `yaml:"repeatIntervalMinutes"`
+		TimeoutSec                           int    `yaml:"timeoutSec"`
+		WebhookUrl                           string `yaml:"webhookUrl"`
+		SlackWebhookUrl                      string `yaml:"slackWebhookUrl"`
+		Routes                               []struct {
+			Namespaces      string `yaml:"namespaces"`
+			WebhookUrl      string `yaml:"webhookUrl"`
+			SlackWebhookUrl string `yaml:"slackWebhookUrl"`
+		} `yaml:"routes"`
+	} `yaml:"notifier"`
+	SavingsReport struct {
+		Enabled          *bool  `yaml:"enabled"`
+		IntervalHours    int    `yaml:"intervalHours"`
+		Format           string `yaml:"format"`
+		ObjectStorageUrl string `yaml:"objectStorageUrl"`
+		WebhookUrl       string `yaml:"webhookUrl"`
+		TimeoutSec       int    `yaml:"timeoutSec"`
+		// ReplicaTimelines measures the realized savings of the enforced workloads on the replicas they ran at over the
+		// window before their enforcement against the ones since.
+		ReplicaTimelines struct {
+			Enabled    *bool `yaml:"enabled"`
+			WindowDays int   `yaml:"windowDays"`
+			StepSec    int   `yaml:"stepSec"`
+		} `yaml:"replicaTimelines"`
+	} `yaml:"savingsReport"`
+	// SavingsReportObjects regenerates the status of the SavingsReport objects with the simulated annual savings of the
+	// recommendations. The SavingsReport CRD must be installed. The costs are reported if the currency is set.
+	SavingsReportObjects struct {
+		Enabled       *bool `yaml:"enabled"`
+		IntervalHours int   `yaml:"intervalHours"`
+		Pricing       struct {
+			Currency         string  `yaml:"currency"`
+			PricePerCoreHour float64 `yaml:"pricePerCoreHour"`
+			PricePerGiBHour  float64 `yaml:"pricePerGiBHour"`
+		} `yaml:"pricing"`
+	} `yaml:"savingsReportObjects"`
+	// IdleWorkloadsReport lists the workloads whose p99 cpu utilization over the window is below the threshold for
+	// decommissioning.
+	IdleWorkloadsReport struct {
+		Enabled          *bool   `yaml:"enabled"`
+		IntervalHours    int     `yaml:"intervalHours"`
+		WindowDays       int     `yaml:"windowDays"`
+		StepSec          int     `yaml:"stepSec"`
+		ThresholdPercent float64 `yaml:"thresholdPercent"`
+		Format           string  `yaml:"format"`
+		ObjectStorageUrl string  `yaml:"objectStorageUrl"`
+		WebhookUrl       string  `yaml:"webhookUrl"`
+		TimeoutSec       int     `yaml:"timeoutSec"`
+	} `yaml:"idleWorkloadsReport"`
+	MetricsCardinality struct {
+		DisabledMetrics   string `yaml:"disabledMetrics"`
+		AggregatedLabels  string `yaml:"aggregatedLabels"`
+		AggregatedMetrics string `yaml:"aggregatedMetrics"`
+	} `yaml:"metricsCardinality"`
+	Debug struct {
+		EnableSimulationDetails *bool `yaml:"enableSimulationDetails"`
+		LogQueries              *bool `yaml:"logQueries"`
+	} `yaml:"debug"`
+	Tracing struct {
+		Enabled      *bool  `yaml:"enabled"`
+		OtlpEndpoint string `yaml:"otlpEndpoint"`
+		Insecure     bool   `yaml:"insecure"`
+		// SamplingRatio is the ratio of the traces sampled, in (0, 1]. Defaults to 0.1.
+		SamplingRatio *float64 `yaml:"samplingRatio"`
+	} `yaml:"tracing"`
+	ApiServer struct {
+		Enabled     *bool  `yaml:"enabled"`
+		BindAddress string `yaml:"bindAddress"`
+		// Authorization scopes the requests to the namespaces the callers can access the PolicyRecommendations of.
+		Authorization struct {
+			Enabled     *bool `yaml:"enabled"`
+			CacheTTLSec int   `yaml:"cacheTTLSec"`
+		} `yaml:"authorization"`
+	} `yaml:"apiServer"`
+	Fleet struct {
+		Mode                  string `yaml:"mode"`
+		ClusterName           string `yaml:"clusterName"`
+		CentralUrl            string `yaml:"centralUrl"`
+		BindAddress           string `yaml:"bindAddress"`
+		TimeoutSec            int    `yaml:"timeoutSec"`
+		PolicySyncIntervalMin int    `yaml:"policySyncIntervalMin"`
+		// AuthTokenFile holds the token the agents authenticate to the central instance with.
+		AuthTokenFile string `yaml:"authTokenFile"`
+		// TLSCertFile and TLSKeyFile make the central instance serve the agents over TLS, and CAFile makes the agents
+		// verify it with the CA instead of the ones of the host.
+		TLSCertFile string `yaml:"tlsCertFile"`
+		TLSKeyFile  string `yaml:"tlsKeyFile"`
+		CAFile      string `yaml:"caFile"`
+	} `yaml:"fleet"`
+	HealthChecks struct {
+		// SubsystemReadiness fails the readiness of the manager while the metrics backend, the policy store or the
+		// enforcement backend is unhealthy.
+		SubsystemReadiness bool `yaml:"subsystemReadiness"`
+		IntervalSec        int  `yaml:"intervalSec"`
+	} `yaml:"healthChecks"`
 	EnableArgoRolloutsSupport *bool `yaml:"enableArgoRolloutsSupport"`
+	// EnableConversionWebhook serves the v1beta1 Policies and PolicyRecommendations by converting them from v1alpha1,
+	// which stays their storage version so that the objects stored can be read without the webhook.
+	EnableConversionWebhook *bool `yaml:"enableConversionWebhook"`
+	// EnableOttoscalrConfigs resolves the OttoscalrConfigs of the namespaces of the workloads when recommending and
+	// enforcing them. The OttoscalrConfig CRD must be installed.
+	EnableOttoscalrConfigs *bool `yaml:"enableOttoscalrConfigs"`
+	// EnableConfigHotReload watches the config file, e.g. mounted from a ConfigMap, and applies the changes of the
+	// scraper, the recommender and the workflow without restarting the manager.
+	EnableConfigHotReload *bool `yaml:"enableConfigHotReload"`
 }
 
 func main() {
@@ -166,6 +561,16 @@ func main() {
 	}
 	logger.Info("Loaded config", "config", config)
 
+	ctrlmetrics.Registry = metrics.NewCardinalityLimitingRegistry(ctrlmetrics.Registry, metrics.CardinalityConfig{
+		DisabledMetrics:   parseCommaSeparatedValues(config.MetricsCardinality.DisabledMetrics),
+		AggregatedLabels:  parseCommaSeparatedValues(config.MetricsCardinality.AggregatedLabels),
+		AggregatedMetrics: parseCommaSeparatedValues(config.MetricsCardinality.AggregatedMetrics),
+	})
+	if err := reco.RegisterMetrics(ctrlmetrics.Registry); err != nil {
+		setupLog.Error(err, "unable to register the recommendation metrics")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     config.MetricBindAddress,
@@ -191,25 +596,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	if config.Tracing.Enabled != nil && *config.Tracing.Enabled {
+		samplingRatio := tracing.DefaultSamplingRatio
+		if config.Tracing.SamplingRatio != nil {
+			samplingRatio = *config.Tracing.SamplingRatio
+		}
+		shutdownTracerProvider, err := tracing.InitTracerProvider(context.Background(), config.Tracing.OtlpEndpoint,
+			config.Tracing.Insecure, samplingRatio)
+		if err != nil {
+			setupLog.Error(err, "unable to initialize the tracer provider")
+			os.Exit(1)
+		}
+		defer func() {
+			if err := shutdownTracerProvider(context.Background()); err != nil {
+				setupLog.Error(err, "unable to shutdown the tracer provider")
+			}
+		}()
+	}
+
 	agingPolicyTTL, err := time.ParseDuration(config.PolicyRecommendationController.PolicyExpiryAge)
 	if err != nil {
 		logger.Error(err, "Failed to parse policyExpiryAge. Defaulting.")
 		agingPolicyTTL = 48 * time.Hour
 	}
 
-	prometheusInstances := parseCommaSeparatedValues(config.MetricsScraper.PrometheusUrl)
-
-	scraper, err := metrics.NewPrometheusScraper(prometheusInstances,
-		time.Duration(config.MetricsScraper.QueryTimeoutSec)*time.Second,
-		time.Duration(config.MetricsScraper.QuerySplitIntervalHr)*time.Hour,
-		config.MetricIngestionTime,
-		config.MetricProbeTime,
-		logger,
-	)
+	prometheusScraper, err := newPrometheusScraper(config, logger)
 	if err != nil {
 		setupLog.Error(err, "unable to start prometheus scraper")
 		os.Exit(1)
 	}
+	metricsRoutes, err := newMetricsRoutes(config, logger)
+	if err != nil {
+		setupLog.Error(err, "unable to start the prometheus scrapers of the metrics routes")
+		os.Exit(1)
+	}
+	scraper := metrics.NewReloadableScraper(prometheusScraper, metricsRoutes...)
 
 	var eventIntegrations []integration.EventIntegration
 	eventCalendarIntegration, err := integration.NewEventCalendarDataFetcher(config.EventCallIntegration.EventCalendarAPIEndpoint,
@@ -272,6 +693,268 @@ func main() {
 		*deploymentClientRegistry,
 		logger)
 
+	var location *time.Location
+	if len(config.Timezone) > 0 {
+		location, err = time.LoadLocation(config.Timezone)
+		if err != nil {
+			setupLog.Error(err, "Invalid timezone", "timezone", config.Timezone)
+			os.Exit(1)
+		}
+		cpuUtilizationBasedRecommender.WithLocation(location)
+	}
+
+	incrementalReuse := config.CpuUtilizationBasedRecommender.IncrementalReuse
+	if incrementalReuse.Enabled != nil && *incrementalReuse.Enabled {
+		cpuUtilizationBasedRecommender.WithIncrementalCache(reco.NewIncrementalCache(
+			time.Duration(incrementalReuse.MaxWindowDeltaHours)*time.Hour,
+			time.Duration(incrementalReuse.FullSearchIntervalHours)*time.Hour,
+			incrementalReuse.MaxWorkloads))
+	}
+
+	cronTriggers := config.CpuUtilizationBasedRecommender.CronTriggers
+	if cronTriggers.Enabled != nil && *cronTriggers.Enabled {
+		cpuUtilizationBasedRecommender.WithCronTriggers(reco.CronTriggerConfig{
+			PreScaleLead:         time.Duration(cronTriggers.PreScaleMinutes) * time.Minute,
+			PeakFactor:           cronTriggers.PeakFactor,
+			MinRecurrencePercent: cronTriggers.MinRecurrencePercent,
+			MinDays:              cronTriggers.MinDays,
+		})
+		// HPAs ignore the cron triggers, so the peaks are pre-scaled only with ScaledObjects
+		if config.AutoscalerClient.EnableScaledObject != nil && *config.AutoscalerClient.EnableScaledObject {
+			cpuUtilizationBasedRecommender.WithScaledObjectBackend()
+		}
+	}
+
+	idleWindows := config.CpuUtilizationBasedRecommender.IdleWindows
+	if idleWindows.Enabled != nil && *idleWindows.Enabled {
+		cpuUtilizationBasedRecommender.WithIdleWindows(reco.IdleWindowConfig{
+			IdleFactor:           idleWindows.IdleFactor,
+			MinRecurrencePercent: idleWindows.MinRecurrencePercent,
+			MinDays:              idleWindows.MinDays,
+			MinDuration:          time.Duration(idleWindows.MinDurationMinutes) * time.Minute,
+		})
+	}
+
+	recencyWeighting := config.CpuUtilizationBasedRecommender.RecencyWeighting
+	if recencyWeighting.Enabled != nil && *recencyWeighting.Enabled {
+		cpuUtilizationBasedRecommender.WithRecencyWeighting(reco.RecencyWeighting{
+			HalfLife:        time.Duration(recencyWeighting.HalfLifeDays) * 24 * time.Hour,
+			MinBreachWeight: recencyWeighting.MinBreachWeight,
+		})
+	}
+
+	nodeHeadroom := config.CpuUtilizationBasedRecommender.NodeHeadroom
+	if nodeHeadroom.ProvisioningPenaltySec > 0 {
+		cpuUtilizationBasedRecommender.WithNodeHeadroom(reco.NodeHeadroom{
+			CPUs:                nodeHeadroom.CPUs,
+			ProvisioningPenalty: time.Duration(nodeHeadroom.ProvisioningPenaltySec) * time.Second,
+		})
+	}
+
+	redLineTiers := config.CpuUtilizationBasedRecommender.RedLineTiers
+	if len(redLineTiers.Key) > 0 && len(redLineTiers.RedLines) > 0 {
+		cpuUtilizationBasedRecommender.WithRedLineTiers(reco.RedLineTiers{Key: redLineTiers.Key, RedLines: redLineTiers.RedLines})
+	}
+
+	if bounds := config.CpuUtilizationBasedRecommender.MetricWindowBounds; bounds.MinDays > 0 || bounds.MaxDays > 0 {
+		cpuUtilizationBasedRecommender.WithMetricWindowBounds(time.Duration(bounds.MinDays)*24*time.Hour,
+			time.Duration(bounds.MaxDays)*24*time.Hour)
+	}
+
+	if autoStep := config.CpuUtilizationBasedRecommender.AutoStep; autoStep.Enabled != nil && *autoStep.Enabled {
+		if autoStep.MinStepSec <= 0 || autoStep.MaxDataPoints <= 0 {
+			setupLog.Error(nil, "cpuUtilizationBasedRecommender.autoStep.minStepSec and maxDataPoints should be positive")
+			os.Exit(1)
+		}
+		cpuUtilizationBasedRecommender.WithAutoMetricStep(time.Duration(autoStep.MinStepSec)*time.Second,
+			autoStep.MaxDataPoints)
+	}
+
+	if multiResolution := config.CpuUtilizationBasedRecommender.MultiResolution; multiResolution.Enabled != nil && *multiResolution.Enabled {
+		if multiResolution.CoarseFactor < 2 || multiResolution.PeakWindowMin <= 0 {
+			setupLog.Error(nil, "cpuUtilizationBasedRecommender.multiResolution.coarseFactor should be at least 2 and peakWindowMin positive")
+			os.Exit(1)
+		}
+		cpuUtilizationBasedRecommender.WithMultiResolution(reco.MultiResolution{
+			CoarseFactor: multiResolution.CoarseFactor,
+			PeakWindow:   time.Duration(multiResolution.PeakWindowMin) * time.Minute,
+		})
+	}
+
+	if config.CpuUtilizationBasedRecommender.HPATolerance != nil {
+		cpuUtilizationBasedRecommender.WithHPATolerance(*config.CpuUtilizationBasedRecommender.HPATolerance)
+	}
+
+	if kedaTimings := config.CpuUtilizationBasedRecommender.KEDATimings; kedaTimings != nil && *kedaTimings {
+		cpuUtilizationBasedRecommender.WithKEDATimings()
+	}
+
+	if config.CpuUtilizationBasedRecommender.OversizedPodsUtilizationPercent > 0 {
+		cpuUtilizationBasedRecommender.WithOversizedPodsSignal(config.CpuUtilizationBasedRecommender.OversizedPodsUtilizationPercent)
+	}
+
+	if maxReplicasAnalysis := config.CpuUtilizationBasedRecommender.MaxReplicasAnalysis; maxReplicasAnalysis != nil && *maxReplicasAnalysis {
+		cpuUtilizationBasedRecommender.WithMaxReplicasAnalysis()
+	}
+
+	if searchSpace := config.CpuUtilizationBasedRecommender.SearchSpace; searchSpace != nil && *searchSpace {
+		cpuUtilizationBasedRecommender.WithSearchSpace()
+	}
+
+	if derivedMaxPods := config.CpuUtilizationBasedRecommender.DerivedMaxPods; derivedMaxPods.Enabled != nil && *derivedMaxPods.Enabled {
+		if derivedMaxPods.HeadroomPercent < 0 {
+			setupLog.Error(nil, "cpuUtilizationBasedRecommender.derivedMaxPods.headroomPercent should not be negative")
+			os.Exit(1)
+		}
+		cpuUtilizationBasedRecommender.WithDerivedMaxPods(derivedMaxPods.HeadroomPercent)
+	}
+
+	if config.CpuUtilizationBasedRecommender.NetworkCeilingBytesPerSec > 0 {
+		cpuUtilizationBasedRecommender.WithNetworkCeiling(scraper, config.CpuUtilizationBasedRecommender.NetworkCeilingBytesPerSec)
+	}
+
+	if breachAssertions := config.CpuUtilizationBasedRecommender.BreachAssertions; breachAssertions.Enabled != nil && *breachAssertions.Enabled {
+		cpuUtilizationBasedRecommender.WithBreachAssertions(scraper)
+	}
+
+	aclStrategy := config.CpuUtilizationBasedRecommender.ACLStrategy
+	if len(aclStrategy) == 0 {
+		aclStrategy = metrics.ACLStrategyMedian
+	}
+	if err := metrics.ValidateACLStrategy(aclStrategy); err != nil {
+		setupLog.Error(err, "unable to set up the ACL strategy")
+		os.Exit(1)
+	}
+	cpuUtilizationBasedRecommender.WithACLStrategy(scraper, aclStrategy)
+
+	containerUtilization := config.CpuUtilizationBasedRecommender.ContainerUtilization
+	switch containerUtilization.Mode {
+	case "":
+	case reco.ContainerUtilizationWeighted, reco.ContainerUtilizationMax:
+		cpuUtilizationBasedRecommender.WithContainerUtilization(scraper,
+			reco.ContainerUtilization{Mode: containerUtilization.Mode, Weights: containerUtilization.Weights})
+	default:
+		setupLog.Error(fmt.Errorf("unknown container utilization mode %s", containerUtilization.Mode),
+			"unable to set up the container utilization")
+		os.Exit(1)
+	}
+
+	podResizeNormalization := config.CpuUtilizationBasedRecommender.PodResizeNormalization
+	if podResizeNormalization.Enabled != nil && *podResizeNormalization.Enabled {
+		cpuUtilizationBasedRecommender.WithPodResizeNormalization(scraper,
+			time.Duration(podResizeNormalization.MinWindowAfterResizeDays)*24*time.Hour)
+	}
+
+	if config.CpuUtilizationBasedRecommender.MinWorkloadAgeDays > 0 {
+		cpuUtilizationBasedRecommender.WithMinWorkloadAge(time.Duration(config.CpuUtilizationBasedRecommender.MinWorkloadAgeDays) * 24 * time.Hour)
+	}
+
+	if config.Debug.EnableSimulationDetails != nil && *config.Debug.EnableSimulationDetails {
+		simulationDetailsStore := reco.NewSimulationDetailsStore()
+		cpuUtilizationBasedRecommender.WithSimulationDetailsStore(simulationDetailsStore)
+		if err := mgr.AddMetricsExtraHandler("/debug/simulations", simulationDetailsStore); err != nil {
+			setupLog.Error(err, "unable to add the simulation details debug endpoint")
+			os.Exit(1)
+		}
+	}
+
+	if config.Debug.LogQueries != nil && *config.Debug.LogQueries {
+		// the default metrics endpoint doesn't negotiate the OpenMetrics format, which the exemplars are only exposed in
+		if err := mgr.AddMetricsExtraHandler("/debug/openmetrics", promhttp.HandlerFor(ctrlmetrics.Registry,
+			promhttp.HandlerOpts{EnableOpenMetrics: true})); err != nil {
+			setupLog.Error(err, "unable to add the openmetrics debug endpoint")
+			os.Exit(1)
+		}
+	}
+
+	var recommender reco.Recommender = cpuUtilizationBasedRecommender
+	var fleetAuthToken string
+	if config.Fleet.Mode == fleet.ModeAgent || config.Fleet.Mode == fleet.ModeCentral {
+		token, err := os.ReadFile(config.Fleet.AuthTokenFile)
+		if err != nil || len(strings.TrimSpace(string(token))) == 0 {
+			setupLog.Error(err, "fleet.authTokenFile should hold the token of the fleet", "file", config.Fleet.AuthTokenFile)
+			os.Exit(1)
+		}
+		fleetAuthToken = strings.TrimSpace(string(token))
+	}
+	switch config.Fleet.Mode {
+	case "":
+	case fleet.ModeAgent:
+		if len(config.Fleet.CentralUrl) == 0 || len(config.Fleet.ClusterName) == 0 || config.Fleet.PolicySyncIntervalMin <= 0 {
+			setupLog.Error(nil, "fleet.centralUrl, fleet.clusterName and a positive fleet.policySyncIntervalMin are required for the agents")
+			os.Exit(1)
+		}
+		fleetClient := fleet.NewClient(config.Fleet.CentralUrl, config.Fleet.ClusterName, fleetAuthToken,
+			time.Duration(config.Fleet.TimeoutSec)*time.Second)
+		if len(config.Fleet.CAFile) > 0 {
+			ca, err := os.ReadFile(config.Fleet.CAFile)
+			rootCAs := x509.NewCertPool()
+			if err != nil || !rootCAs.AppendCertsFromPEM(ca) {
+				setupLog.Error(err, "unable to load the CA of the central instance", "file", config.Fleet.CAFile)
+				os.Exit(1)
+			}
+			fleetClient.WithRootCAs(rootCAs)
+		}
+		recommender = fleet.NewRemoteRecommender(cpuUtilizationBasedRecommender, fleetClient)
+		if err := mgr.Add(fleet.NewPolicySyncer(mgr.GetClient(), fleetClient,
+			time.Duration(config.Fleet.PolicySyncIntervalMin)*time.Minute, ctrl.Log)); err != nil {
+			setupLog.Error(err, "unable to add the fleet policy syncer")
+			os.Exit(1)
+		}
+	case fleet.ModeCentral:
+		// the summaries of the agents carry the metrics and the workloads the recommender reads, but for these
+		if features := cpuUtilizationBasedRecommender.UnsummarizedFeatures(); len(features) > 0 {
+			setupLog.Error(nil, "the features of the cpuUtilizationBasedRecommender read the clusters of the workloads and aren't supported by the central instance",
+				"features", features)
+			os.Exit(1)
+		}
+		if (len(config.Fleet.TLSCertFile) > 0) != (len(config.Fleet.TLSKeyFile) > 0) {
+			setupLog.Error(nil, "both fleet.tlsCertFile and fleet.tlsKeyFile are required to serve the agents over TLS")
+			os.Exit(1)
+		}
+		fleetServer := fleet.NewServer(mgr.GetClient(), cpuUtilizationBasedRecommender, config.Fleet.BindAddress,
+			fleetAuthToken, ctrl.Log).WithTLS(config.Fleet.TLSCertFile, config.Fleet.TLSKeyFile)
+		if err := mgr.Add(fleetServer); err != nil {
+			setupLog.Error(err, "unable to add the fleet server")
+			os.Exit(1)
+		}
+	default:
+		setupLog.Error(nil, "fleet.mode should be either agent or central", "mode", config.Fleet.Mode)
+		os.Exit(1)
+	}
+
+	queueDepthBasedRecommender := config.QueueDepthBasedRecommender
+	if queueDepthBasedRecommender.Enabled != nil && *queueDepthBasedRecommender.Enabled {
+		if queueDepthBasedRecommender.TargetDepth <= 0 || queueDepthBasedRecommender.StepSec <= 0 {
+			setupLog.Error(nil, "a positive queueDepthBasedRecommender.targetDepth and queueDepthBasedRecommender.stepSec are required")
+			os.Exit(1)
+		}
+		recommender = reco.NewQueueDepthBasedRecommender(recommender,
+			mgr.GetClient(),
+			scraper.WithQueueQueries(queueDepthBasedRecommender.Queries),
+			*deploymentClientRegistry,
+			time.Duration(queueDepthBasedRecommender.MetricWindowInDays)*24*time.Hour,
+			time.Duration(queueDepthBasedRecommender.StepSec)*time.Second,
+			queueDepthBasedRecommender.TargetDepth,
+			logger)
+	}
+
+	kafkaLagBasedRecommender := config.KafkaLagBasedRecommender
+	if kafkaLagBasedRecommender.Enabled != nil && *kafkaLagBasedRecommender.Enabled {
+		if kafkaLagBasedRecommender.TargetLag <= 0 || kafkaLagBasedRecommender.StepSec <= 0 {
+			setupLog.Error(nil, "a positive kafkaLagBasedRecommender.targetLag and kafkaLagBasedRecommender.stepSec are required")
+			os.Exit(1)
+		}
+		recommender = reco.NewKafkaLagBasedRecommender(recommender,
+			mgr.GetClient(),
+			scraper,
+			*deploymentClientRegistry,
+			time.Duration(kafkaLagBasedRecommender.MetricWindowInDays)*24*time.Hour,
+			time.Duration(kafkaLagBasedRecommender.StepSec)*time.Second,
+			kafkaLagBasedRecommender.TargetLag,
+			logger)
+	}
+
 	breachAnalyzer, err := reco.NewBreachAnalyzer(mgr.GetClient(), scraper, config.BreachMonitor.CpuRedLine, time.Duration(config.BreachMonitor.StepSec)*time.Second)
 	if err != nil {
 		setupLog.Error(err, "unable to initialize breach analyzer")
@@ -280,13 +963,125 @@ func main() {
 
 	policyStore := policy.NewPolicyStore(mgr.GetClient())
 
+	var auditSinks []audit.Sink
+	if config.Audit.Stdout {
+		auditSinks = append(auditSinks, audit.NewStdoutSink())
+	}
+	if len(config.Audit.FilePath) > 0 {
+		fileSink, err := audit.NewFileSink(config.Audit.FilePath)
+		if err != nil {
+			setupLog.Error(err, "unable to open the audit log file")
+			os.Exit(1)
+		}
+		// closed once the manager has stopped the controllers, so that their last records are synced to the disk
+		defer func() {
+			if err := fileSink.Close(); err != nil {
+				setupLog.Error(err, "unable to close the audit log file")
+			}
+		}()
+		auditSinks = append(auditSinks, fileSink)
+	}
+	if len(config.Audit.WebhookUrl) > 0 {
+		auditSinks = append(auditSinks, audit.NewWebhookSink(config.Audit.WebhookUrl,
+			time.Duration(config.Audit.WebhookTimeoutSec)*time.Second))
+	}
+	auditor := audit.NewAuditor(logger, auditSinks...)
+
+	notifierTimeout := time.Duration(config.Notifier.TimeoutSec) * time.Second
+	var notifierRoutes []notifier.Route
+	for _, route := range config.Notifier.Routes {
+		notifierRoutes = append(notifierRoutes, notifier.Route{
+			Namespaces: parseCommaSeparatedValues(route.Namespaces),
+			Channels:   notificationChannels(route.WebhookUrl, route.SlackWebhookUrl, notifierTimeout),
+		})
+	}
+	recoNotifier := notifier.NewRoutingNotifier(logger, config.Notifier.RecommendationChangeThresholdPercent,
+		time.Duration(config.Notifier.RepeatIntervalMinutes)*time.Minute,
+		notificationChannels(config.Notifier.WebhookUrl, config.Notifier.SlackWebhookUrl, notifierTimeout), notifierRoutes...)
+
+	var workflowWorkerPool *reco.WorkerPool
+	if config.PolicyRecommendationController.WorkflowWorkers > 0 {
+		workflowWorkerPool = reco.NewWorkerPool(config.PolicyRecommendationController.WorkflowWorkers,
+			config.PolicyRecommendationController.WorkflowQueueLength)
+	}
+
+	var pdbResolver *reco.PodDisruptionBudgetResolver
+	if respectPDBs := config.PolicyRecommendationController.RespectPodDisruptionBudgets; respectPDBs == nil || *respectPDBs {
+		pdbResolver = reco.NewPodDisruptionBudgetResolver(mgr.GetClient(), *deploymentClientRegistry)
+	}
+	var configResolver *reco.ConfigResolver
+	if config.EnableOttoscalrConfigs != nil && *config.EnableOttoscalrConfigs {
+		configResolver = reco.NewConfigResolver(mgr.GetClient())
+	}
+	var quotaResolver *reco.ResourceQuotaResolver
+	if respectQuotas := config.PolicyRecommendationController.RespectResourceQuotas; respectQuotas == nil || *respectQuotas {
+		quotaResolver = reco.NewResourceQuotaResolver(mgr.GetClient(), *deploymentClientRegistry)
+	}
+
+	agingPolicyIterator := reco.NewAgingPolicyIterator(mgr.GetClient(), agingPolicyTTL).WithPolicyGroups(*deploymentClientRegistry)
+	if policySkipping := config.PolicyRecommendationController.PolicySkipping; policySkipping.MaxSkip > 0 {
+		agingPolicyIterator = agingPolicyIterator.WithPolicySkipping(policySkipping.MaxSkip, policySkipping.MinConfidencePercent)
+	}
+
 	policyRecoReconciler, err := controller.NewPolicyRecommendationReconciler(mgr.GetClient(),
 		mgr.GetScheme(), mgr.GetEventRecorderFor(controller.PolicyRecoWorkflowCtrlName),
-		config.PolicyRecommendationController.MaxConcurrentReconciles, config.PolicyRecommendationController.MinRequiredReplicas, cpuUtilizationBasedRecommender, policyStore, reco.NewDefaultPolicyIterator(mgr.GetClient()), reco.NewAgingPolicyIterator(mgr.GetClient(), agingPolicyTTL), breachAnalyzer)
+		config.PolicyRecommendationController.MaxConcurrentReconciles, config.PolicyRecommendationController.MinRequiredReplicas, recommender, policyStore, auditor, recoNotifier, workflowWorkerPool, pdbResolver, quotaResolver, configResolver, deploymentClientRegistry, reco.NewDefaultPolicyIterator(mgr.GetClient()), agingPolicyIterator, breachAnalyzer)
 	if err != nil {
 		setupLog.Error(err, "Unable to initialize policy reco reconciler")
 		os.Exit(1)
 	}
+	policyRecoReconciler.DiffThreshold = controller.RecommendationDiffThreshold{
+		TargetMetricValue:      config.PolicyRecommendationController.DiffThreshold.TargetMetricValue,
+		TargetMetricValueRaise: config.PolicyRecommendationController.DiffThreshold.TargetMetricValueRaise,
+		TargetMetricValueLower: config.PolicyRecommendationController.DiffThreshold.TargetMetricValueLower,
+		MinReplicas:            config.PolicyRecommendationController.DiffThreshold.MinReplicas,
+	}
+	if len(config.PolicyRecommendationController.StaleDataGrace.Period) > 0 {
+		stalePeriod, err := time.ParseDuration(config.PolicyRecommendationController.StaleDataGrace.Period)
+		if err != nil {
+			setupLog.Error(err, "Invalid stale data grace period")
+			os.Exit(1)
+		}
+		policyRecoReconciler.StaleDataGrace.Period = stalePeriod
+	}
+	policyRecoReconciler.StaleDataGrace.Recommendations = config.PolicyRecommendationController.StaleDataGrace.Recommendations
+	policyRecoReconciler.AnomalyGuard = controller.AnomalyGuard{
+		MinDropPercent:   config.PolicyRecommendationController.AnomalyGuard.MinDropPercent,
+		TargetJumpPoints: config.PolicyRecommendationController.AnomalyGuard.TargetJumpPoints,
+		Block:            config.PolicyRecommendationController.AnomalyGuard.Block,
+	}
+	excludedOwnerKinds := controller.DefaultExcludedOwnerKinds
+	if ownerKinds := config.PolicyRecommendationController.WorkloadExclusion.OwnerKinds; ownerKinds != nil {
+		excludedOwnerKinds = *ownerKinds
+	}
+	policyRecoReconciler.WorkloadExclusion, err = controller.NewWorkloadExclusion(excludedOwnerKinds,
+		config.PolicyRecommendationController.WorkloadExclusion.Labels)
+	if err != nil {
+		setupLog.Error(err, "Invalid workload exclusion")
+		os.Exit(1)
+	}
+	if changeApproval := config.PolicyRecommendationController.ChangeApproval; changeApproval.Enabled != nil && *changeApproval.Enabled {
+		var transitions []notifier.EventType
+		for _, transition := range changeApproval.Transitions {
+			transitions = append(transitions, notifier.EventType(transition))
+		}
+		approver := approval.NewWebhookApprover(changeApproval.WebhookUrl, time.Duration(changeApproval.TimeoutSec)*time.Second)
+		policyRecoReconciler.ChangeApproval = controller.ChangeApproval{
+			Gate:         approval.NewGate(approver, time.Duration(changeApproval.ApprovalTimeoutMin)*time.Minute, transitions...),
+			PollInterval: time.Duration(changeApproval.PollIntervalSec) * time.Second,
+		}
+	}
+
+	if explainer, ok := policyRecoReconciler.RecoWorkflow.(reco.Explainer); ok {
+		if err := mgr.AddMetricsExtraHandler("/debug/explanations", reco.NewExplanationHandler(explainer)); err != nil {
+			setupLog.Error(err, "unable to add the recommendation explanations debug endpoint")
+			os.Exit(1)
+		}
+	}
+
+	policyRecoReconciler.InteractiveQueue = controller.InteractiveQueue{
+		MaxConcurrentReconciles: config.PolicyRecommendationController.InteractiveMaxConcurrentReconciles,
+	}
 
 	if err = policyRecoReconciler.
 		SetupWithManager(mgr); err != nil {
@@ -295,6 +1090,7 @@ func main() {
 	}
 
 	deploymentTriggerReconciler := controller.NewDeploymentTriggerController(mgr.GetClient(), mgr.GetScheme(), *deploymentClientRegistry)
+	deploymentTriggerReconciler.VPAGuardrails = config.HPAEnforcer.VPAGuardrails
 	if err = deploymentTriggerReconciler.
 		SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DeploymentController")
@@ -303,6 +1099,27 @@ func main() {
 
 	triggerHandler := trigger.NewK8sTriggerHandler(mgr.GetClient(), logger)
 	triggerHandler.Start()
+	batchTrigger := trigger.NewBatchTrigger(mgr.GetClient(), *deploymentClientRegistry, triggerHandler.QueueInteractiveForExecution)
+
+	if config.ApiServer.Enabled != nil && *config.ApiServer.Enabled {
+		explainer, _ := policyRecoReconciler.RecoWorkflow.(reco.Explainer)
+		apiServer := apiserver.NewServer(mgr.GetClient(), explainer, cpuUtilizationBasedRecommender, batchTrigger,
+			config.ApiServer.BindAddress, ctrl.Log).WithPolicyExpiryAge(agingPolicyTTL)
+		// the what-if recommendations look back no further than the recommendations of the workloads can
+		maxWhatIfWindowDays := config.CpuUtilizationBasedRecommender.MetricWindowInDays
+		if maxDays := config.CpuUtilizationBasedRecommender.MetricWindowBounds.MaxDays; maxDays > maxWhatIfWindowDays {
+			maxWhatIfWindowDays = maxDays
+		}
+		apiServer.WithMaxWhatIfWindowDays(maxWhatIfWindowDays)
+		if config.ApiServer.Authorization.Enabled != nil && *config.ApiServer.Authorization.Enabled {
+			apiServer.WithAuthorizer(apiserver.NewSubjectAccessReviewAuthorizer(mgr.GetClient(),
+				time.Duration(config.ApiServer.Authorization.CacheTTLSec)*time.Second))
+		}
+		if err := mgr.Add(apiServer); err != nil {
+			setupLog.Error(err, "unable to add the API server")
+			os.Exit(1)
+		}
+	}
 
 	monitorManager := trigger.NewPolicyRecommendationMonitorManager(mgr.GetClient(),
 		mgr.GetEventRecorderFor(trigger.BreachStatusManager),
@@ -313,7 +1130,15 @@ func main() {
 		triggerHandler.QueueForExecution,
 		config.BreachMonitor.StepSec,
 		config.BreachMonitor.CpuRedLine,
-		logger)
+		logger).WithLocation(location)
+	if len(config.PeriodicTrigger.Schedule) > 0 {
+		requeueSchedule, err := trigger.ParseSchedule(config.PeriodicTrigger.Schedule)
+		if err != nil {
+			setupLog.Error(err, "Invalid periodic trigger schedule", "schedule", config.PeriodicTrigger.Schedule)
+			os.Exit(1)
+		}
+		monitorManager.WithRequeueSchedule(requeueSchedule)
+	}
 
 	excludedNamespaces := parseCommaSeparatedValues(config.PolicyRecommendationRegistrar.ExcludedNamespaces)
 	includedNamespaces := parseCommaSeparatedValues(config.PolicyRecommendationRegistrar.IncludedNamespaces)
@@ -323,7 +1148,13 @@ func main() {
 
 	var autoscalerClient autoscaler.AutoscalerClient
 	if *config.AutoscalerClient.EnableScaledObject {
-		autoscalerClient = autoscaler.NewScaledobjectClient(mgr.GetClient())
+		behaviorMergeStrategy, err := autoscaler.ParseBehaviorMergeStrategy(config.AutoscalerClient.HPABehaviorMergeStrategy)
+		if err != nil {
+			setupLog.Error(err, "Invalid HPA behavior merge strategy", "strategy", config.AutoscalerClient.HPABehaviorMergeStrategy)
+			os.Exit(1)
+		}
+		autoscalerClient = autoscaler.NewScaledobjectClient(mgr.GetClient()).WithBehaviorMergeStrategy(behaviorMergeStrategy).
+			WithTemplate(config.AutoscalerClient.ScaledObjectTemplate)
 	} else {
 		if config.AutoscalerClient.HpaAPIVersion == "v2" {
 			autoscalerClient = autoscaler.NewHPAClientV2(mgr.GetClient())
@@ -332,12 +1163,43 @@ func main() {
 		}
 	}
 	hpaEnforcementController, err := controller.NewHPAEnforcementController(mgr.GetClient(),
-		mgr.GetScheme(),*deploymentClientRegistry, mgr.GetEventRecorderFor(controller.HPAEnforcementCtrlName),
-		config.HPAEnforcer.MaxConcurrentReconciles, config.HPAEnforcer.IsDryRun, &hpaEnforcerExcludedNamespaces, &hpaEnforcerIncludedNamespaces, config.HPAEnforcer.WhitelistMode, config.HPAEnforcer.MinRequiredReplicas, autoscalerClient)
+		mgr.GetScheme(), *deploymentClientRegistry, mgr.GetEventRecorderFor(controller.HPAEnforcementCtrlName),
+		config.HPAEnforcer.MaxConcurrentReconciles, config.HPAEnforcer.IsDryRun, &hpaEnforcerExcludedNamespaces, &hpaEnforcerIncludedNamespaces, config.HPAEnforcer.WhitelistMode, config.HPAEnforcer.MinRequiredReplicas, autoscalerClient, auditor, recoNotifier)
 	if err != nil {
 		setupLog.Error(err, "Unable to initialize HPA enforcement controller")
 		os.Exit(1)
 	}
+	hpaEnforcementController.VPAGuardrails = config.HPAEnforcer.VPAGuardrails
+	hpaEnforcementController.MinConfidencePercent = config.HPAEnforcer.MinConfidencePercent
+	hpaEnforcementController.DependencyOrdering = config.HPAEnforcer.DependencyOrdering
+	hpaEnforcementController.IdleWindows = config.HPAEnforcer.IdleWindows
+	if alertSilences := config.HPAEnforcer.AlertSilences; alertSilences.Enabled != nil && *alertSilences.Enabled {
+		alertSilencer, err := silence.NewAlertmanagerSilencer(alertSilences.AlertmanagerUrl,
+			time.Duration(alertSilences.TimeoutSec)*time.Second, time.Duration(alertSilences.DurationMin)*time.Minute,
+			alertSilences.WorkloadLabel, alertSilences.AlertNames...)
+		if err != nil {
+			setupLog.Error(err, "Invalid hpaEnforcer.alertSilences config")
+			os.Exit(1)
+		}
+		hpaEnforcementController.AlertSilencer = alertSilencer
+	}
+	if config.HPAEnforcer.EnforcementBudget.Enabled != nil && *config.HPAEnforcer.EnforcementBudget.Enabled {
+		hpaEnforcementController.EnforcementBudget = config.HPAEnforcer.EnforcementBudget.WorkloadsPerDay
+	}
+	if config.HPAEnforcer.ShadowAdoption.Enabled != nil && *config.HPAEnforcer.ShadowAdoption.Enabled {
+		hpaEnforcementController.ShadowAdoption = &controller.ShadowAdoption{
+			Period: time.Duration(config.HPAEnforcer.ShadowAdoption.Days) * 24 * time.Hour,
+			Comparator: reco.NewMetricsShadowComparator(scraper, *deploymentClientRegistry,
+				config.BreachMonitor.CpuRedLine, time.Duration(config.BreachMonitor.StepSec)*time.Second),
+		}
+	}
+	if config.HPAEnforcer.EnforcementPreview.Enabled != nil && *config.HPAEnforcer.EnforcementPreview.Enabled {
+		hpaEnforcementController.EnforcementPreview = time.Duration(config.HPAEnforcer.EnforcementPreview.Minutes) * time.Minute
+	}
+	hpaEnforcementController.ConfigResolver = configResolver
+	if policyRecoReconciler.ChangeApproval.Gate != nil {
+		policyRecoReconciler.ChangeApproval.Previewer = hpaEnforcementController
+	}
 
 	if err = hpaEnforcementController.
 		SetupWithManager(mgr); err != nil {
@@ -345,16 +1207,45 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = controller.NewPolicyRecommendationRegistrar(mgr.GetClient(),
+	policyRecoRegistrar := controller.NewPolicyRecommendationRegistrar(mgr.GetClient(),
 		mgr.GetScheme(),
 		config.PolicyRecommendationRegistrar.RequeueDelayMs,
 		monitorManager,
-		policyStore, *deploymentClientRegistry, excludedNamespaces, includedNamespaces).SetupWithManager(mgr); err != nil {
+		policyStore, *deploymentClientRegistry, excludedNamespaces, includedNamespaces)
+	policyRecoRegistrar.Criticality = controller.Criticality{
+		Key:   config.PolicyRecommendationRegistrar.Criticality.Key,
+		Steps: config.PolicyRecommendationRegistrar.Criticality.Steps,
+	}
+	if err = policyRecoRegistrar.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller",
 			"controller", "PolicyRecommendationRegistration")
 		os.Exit(1)
 	}
 
+	policyRecoBindingReconciler := controller.NewPolicyRecommendationBindingReconciler(mgr.GetClient(),
+		mgr.GetScheme(),
+		config.PolicyRecommendationRegistrar.RequeueDelayMs,
+		monitorManager,
+		policyStore, *deploymentClientRegistry)
+	policyRecoBindingReconciler.Criticality = policyRecoRegistrar.Criticality
+	if err = policyRecoBindingReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller",
+			"controller", "PolicyRecommendationBinding")
+		os.Exit(1)
+	}
+
+	if err = controller.NewNamespaceRetriggerController(mgr.GetClient(),
+		mgr.GetEventRecorderFor(controller.NamespaceRetriggerCtrlName),
+		batchTrigger).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespaceRetrigger")
+		os.Exit(1)
+	}
+
+	if err = controller.NewNamespaceTerminationController(mgr.GetClient(), monitorManager).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespaceTermination")
+		os.Exit(1)
+	}
+
 	if err = controller.NewPolicyWatcher(mgr.GetClient(),
 		mgr.GetScheme(),
 		triggerHandler.QueueAllForExecution,
@@ -362,6 +1253,91 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Policy")
 		os.Exit(1)
 	}
+
+	if config.SavingsReport.Enabled != nil && *config.SavingsReport.Enabled {
+		if config.SavingsReport.IntervalHours <= 0 {
+			setupLog.Error(nil, "savingsReport.intervalHours should be positive")
+			os.Exit(1)
+		}
+		reportTimeout := time.Duration(config.SavingsReport.TimeoutSec) * time.Second
+		var reportSinks []report.Sink
+		if len(config.SavingsReport.ObjectStorageUrl) > 0 {
+			reportSinks = append(reportSinks, report.NewObjectStorageSink(config.SavingsReport.ObjectStorageUrl,
+				os.Getenv(savingsReportAuthTokenEnv), reportTimeout))
+		}
+		if len(config.SavingsReport.WebhookUrl) > 0 {
+			reportSinks = append(reportSinks, report.NewWebhookSink(config.SavingsReport.WebhookUrl, reportTimeout))
+		}
+		generator := report.NewGenerator(mgr.GetClient(), *deploymentClientRegistry, logger)
+		if timelines := config.SavingsReport.ReplicaTimelines; timelines.Enabled != nil && *timelines.Enabled {
+			if timelines.WindowDays <= 0 || timelines.StepSec <= 0 {
+				setupLog.Error(nil, "savingsReport.replicaTimelines.windowDays and stepSec should be positive")
+				os.Exit(1)
+			}
+			generator.WithReplicaTimelines(report.NewReplicaTimelineAnalyzer(scraper, autoscalerClient,
+				time.Duration(timelines.WindowDays)*24*time.Hour, time.Duration(timelines.StepSec)*time.Second))
+		}
+		reporter := report.NewSavingsReporter(generator,
+			time.Duration(config.SavingsReport.IntervalHours)*time.Hour, report.Format(config.SavingsReport.Format),
+			logger, reportSinks...)
+		if err := mgr.Add(reporter); err != nil {
+			setupLog.Error(err, "unable to add the savings reporter")
+			os.Exit(1)
+		}
+	}
+
+	if config.SavingsReportObjects.Enabled != nil && *config.SavingsReportObjects.Enabled {
+		if config.SavingsReportObjects.IntervalHours <= 0 {
+			setupLog.Error(nil, "savingsReportObjects.intervalHours should be positive")
+			os.Exit(1)
+		}
+		regenerator := report.NewSavingsReportRegenerator(mgr.GetClient(), *deploymentClientRegistry,
+			time.Duration(config.SavingsReportObjects.IntervalHours)*time.Hour, logger)
+		if pricing := config.SavingsReportObjects.Pricing; len(pricing.Currency) > 0 {
+			regenerator.WithPricing(report.NewResourcePricing(pricing.Currency, pricing.PricePerCoreHour,
+				pricing.PricePerGiBHour))
+		}
+		if err := mgr.Add(regenerator); err != nil {
+			setupLog.Error(err, "unable to add the savings report regenerator")
+			os.Exit(1)
+		}
+	}
+
+	if config.IdleWorkloadsReport.Enabled != nil && *config.IdleWorkloadsReport.Enabled {
+		idleReport := config.IdleWorkloadsReport
+		if idleReport.IntervalHours <= 0 || idleReport.WindowDays <= 0 || idleReport.StepSec <= 0 {
+			setupLog.Error(nil, "idleWorkloadsReport.intervalHours, windowDays and stepSec should be positive")
+			os.Exit(1)
+		}
+		reportTimeout := time.Duration(idleReport.TimeoutSec) * time.Second
+		var reportSinks []report.Sink
+		if len(idleReport.ObjectStorageUrl) > 0 {
+			reportSinks = append(reportSinks, report.NewObjectStorageSink(idleReport.ObjectStorageUrl,
+				os.Getenv(idleWorkloadsReportAuthTokenEnv), reportTimeout))
+		}
+		if len(idleReport.WebhookUrl) > 0 {
+			reportSinks = append(reportSinks, report.NewWebhookSink(idleReport.WebhookUrl, reportTimeout))
+		}
+		analyzer := report.NewIdleWorkloadsAnalyzer(mgr.GetClient(), *deploymentClientRegistry, scraper,
+			time.Duration(idleReport.WindowDays)*24*time.Hour, time.Duration(idleReport.StepSec)*time.Second,
+			idleReport.ThresholdPercent, time.Duration(idleReport.IntervalHours)*time.Hour,
+			report.Format(idleReport.Format), logger, reportSinks...)
+		if err := mgr.Add(analyzer); err != nil {
+			setupLog.Error(err, "unable to add the idle workloads analyzer")
+			os.Exit(1)
+		}
+	}
+
+	if config.EnableConversionWebhook != nil && *config.EnableConversionWebhook {
+		if err = (&ottoscaleriov1beta1.Policy{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Policy")
+			os.Exit(1)
+		}
+		if err = (&ottoscaleriov1beta1.PolicyRecommendation{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PolicyRecommendation")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -372,18 +1348,34 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
-
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &kedaapi.ScaledObject{}, scaledTargetName, func(obj client.Object) []string {
-		scaledObject := obj.(*kedaapi.ScaledObject)
-		if scaledObject.Spec.ScaleTargetRef.Name == "" {
-			return nil
+	if config.HealthChecks.SubsystemReadiness {
+		interval := time.Duration(config.HealthChecks.IntervalSec) * time.Second
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		subsystemChecks := map[string]health.Check{
+			health.MetricsBackend:     health.MetricsBackendCheck(scraper),
+			health.PolicyStore:        health.PolicyStoreCheck(policyStore),
+			health.EnforcementBackend: health.EnforcementBackendCheck(mgr.GetClient(), autoscalerClient.GetType()),
+		}
+		for name, check := range subsystemChecks {
+			if err := mgr.AddReadyzCheck(name, health.NewSubsystemChecker(name, check, interval).Check); err != nil {
+				setupLog.Error(err, "unable to set up ready check", "subsystem", name)
+				os.Exit(1)
+			}
 		}
-		return []string{scaledObject.Spec.ScaleTargetRef.Name}
-	}); err != nil {
+	}
+
+	if err := reco.RegisterFieldIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "unable to index scaledobject")
 		os.Exit(1)
 	}
 
+	if config.EnableConfigHotReload != nil && *config.EnableConfigHotReload {
+		workflow, _ := policyRecoReconciler.RecoWorkflow.(*reco.RecommendationWorkflowImpl)
+		watchConfig(config, scraper, cpuUtilizationBasedRecommender, workflow, logger)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -401,6 +1393,154 @@ func main() {
 	}()
 }
 
+// MetricsRoute is a route of the workloads to the Prometheus instances of their metrics.
+type MetricsRoute struct {
+	Namespace       string `yaml:"namespace"`
+	Workload        string `yaml:"workload"`
+	PrometheusUrl   string `yaml:"prometheusUrl"`
+	BearerTokenFile string `yaml:"bearerTokenFile"`
+	Username        string `yaml:"username"`
+	PasswordFile    string `yaml:"passwordFile"`
+}
+
+func newPrometheusScraper(config Config, logger logr.Logger) (*metrics.PrometheusScraper, error) {
+	return newPrometheusScraperOf(config, config.MetricsScraper.PrometheusUrl, metrics.PrometheusAuth{
+		BearerTokenFile: config.MetricsScraper.BearerTokenFile,
+		Username:        config.MetricsScraper.Username,
+		PasswordFile:    config.MetricsScraper.PasswordFile,
+	}, logger)
+}
+
+// newMetricsRoutes returns the routes of the workloads to the scrapers of their Prometheus instances.
+func newMetricsRoutes(config Config, logger logr.Logger) ([]metrics.MetricsRoute, error) {
+	routes := make([]metrics.MetricsRoute, 0, len(config.MetricsRouting.Routes))
+	for _, route := range config.MetricsRouting.Routes {
+		scraper, err := newPrometheusScraperOf(config, route.PrometheusUrl, metrics.PrometheusAuth{
+			BearerTokenFile: route.BearerTokenFile,
+			Username:        route.Username,
+			PasswordFile:    route.PasswordFile,
+		}, logger.WithValues("prometheusUrl", route.PrometheusUrl))
+		if err != nil {
+			return nil, err
+		}
+		metricsRoute, err := metrics.NewMetricsRoute(route.Namespace, route.Workload, scraper)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, metricsRoute)
+	}
+	return routes, nil
+}
+
+// newPrometheusScraperOf returns the scraper of the Prometheus instances of the comma separated urls, with the
+// settings of the metricsScraper.
+func newPrometheusScraperOf(config Config, prometheusUrl string, auth metrics.PrometheusAuth,
+	logger logr.Logger) (*metrics.PrometheusScraper, error) {
+	scraper, err := metrics.NewPrometheusScraperWithAuth(parseCommaSeparatedValues(prometheusUrl), auth,
+		time.Duration(config.MetricsScraper.QueryTimeoutSec)*time.Second,
+		time.Duration(config.MetricsScraper.QuerySplitIntervalHr)*time.Hour,
+		config.MetricIngestionTime,
+		config.MetricProbeTime,
+		logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	scraper = scraper.WithQueryLogging(config.Debug.LogQueries != nil && *config.Debug.LogQueries)
+	if config.MetricsScraper.RemoteRead.Enabled {
+		scraper = scraper.WithRemoteRead(time.Duration(config.MetricsScraper.RemoteRead.MinWindowHr) * time.Hour)
+	}
+	templates := config.MetricsScraper.QueryTemplates
+	if config.MetricsScraper.LabelScheme != "" {
+		scheme, err := labelScheme(scraper, config.MetricsScraper.LabelScheme)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Querying the metrics by their label scheme", "labelScheme", scheme.Name)
+		templates = templates.WithDefaults(scheme.Templates)
+	}
+	return scraper.WithQueryTemplates(templates)
+}
+
+// labelScheme returns the label scheme of the name, or the one detected from the metrics of the Prometheus instances
+// if it's auto.
+func labelScheme(scraper *metrics.PrometheusScraper, name string) (metrics.LabelScheme, error) {
+	if name == metrics.LabelSchemeAuto {
+		return scraper.DetectLabelScheme(context.Background(), metrics.DefaultLabelSchemes)
+	}
+	return metrics.LabelSchemeByName(metrics.DefaultLabelSchemes, name)
+}
+
+func recommenderParams(config Config) reco.RecommenderParams {
+	return reco.RecommenderParams{
+		RedLineUtilization:         config.BreachMonitor.CpuRedLine,
+		MetricWindow:               time.Duration(config.CpuUtilizationBasedRecommender.MetricWindowInDays) * 24 * time.Hour,
+		MinTarget:                  config.CpuUtilizationBasedRecommender.MinTarget,
+		MaxTarget:                  config.CpuUtilizationBasedRecommender.MaxTarget,
+		MetricsPercentageThreshold: config.CpuUtilizationBasedRecommender.MetricsPercentageThreshold,
+	}
+}
+
+// watchConfig applies the changes of the config file to the scraper, the recommender and the workflow. The scraper is
+// rebuilt only if the Prometheus instances, their credentials or the metrics routes changed, and is kept if it can't be rebuilt. The
+// other changes are logged as applying on restart.
+func watchConfig(config Config, scraper *metrics.ReloadableScraper,
+	recommender *reco.CpuUtilizationBasedRecommender, workflow *reco.RecommendationWorkflowImpl, logger logr.Logger) {
+	logger = logger.WithName("config-reloader")
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		reloaded := Config{}
+		if err := viper.Unmarshal(&reloaded); err != nil {
+			logger.Error(err, "Unable to unmarshall the changed config file. Keeping the current config.")
+			return
+		}
+
+		if reloaded.MetricsScraper != config.MetricsScraper || reloaded.MetricIngestionTime != config.MetricIngestionTime ||
+			reloaded.MetricProbeTime != config.MetricProbeTime ||
+			!reflect.DeepEqual(reloaded.MetricsRouting, config.MetricsRouting) {
+			prometheusScraper, err := newPrometheusScraper(reloaded, logger)
+			var metricsRoutes []metrics.MetricsRoute
+			if err == nil {
+				metricsRoutes, err = newMetricsRoutes(reloaded, logger)
+			}
+			if err != nil {
+				logger.Error(err, "Unable to rebuild the prometheus scraper. Keeping the current scraper.")
+				reloaded.MetricsScraper = config.MetricsScraper
+				reloaded.MetricsRouting = config.MetricsRouting
+				reloaded.MetricIngestionTime, reloaded.MetricProbeTime = config.MetricIngestionTime, config.MetricProbeTime
+			} else {
+				scraper.Reload(prometheusScraper, metricsRoutes...)
+				logger.Info("Reloaded the prometheus scraper", "prometheusUrl", reloaded.MetricsScraper.PrometheusUrl)
+			}
+		}
+		if params := recommenderParams(reloaded); params != recommenderParams(config) {
+			recommender.Reload(params)
+			logger.Info("Reloaded the recommender", "params", params)
+		}
+		if workflow != nil && reloaded.PolicyRecommendationController.MinRequiredReplicas != config.PolicyRecommendationController.MinRequiredReplicas {
+			workflow.ReloadMinRequiredReplicas(reloaded.PolicyRecommendationController.MinRequiredReplicas)
+			logger.Info("Reloaded the workflow", "minRequiredReplicas",
+				reloaded.PolicyRecommendationController.MinRequiredReplicas)
+		}
+
+		// whatever else changed applies on restart
+		pending := reloaded
+		pending.MetricsScraper = config.MetricsScraper
+		pending.MetricsRouting = config.MetricsRouting
+		pending.MetricIngestionTime, pending.MetricProbeTime = config.MetricIngestionTime, config.MetricProbeTime
+		pending.BreachMonitor.CpuRedLine = config.BreachMonitor.CpuRedLine
+		pending.CpuUtilizationBasedRecommender.MetricWindowInDays = config.CpuUtilizationBasedRecommender.MetricWindowInDays
+		pending.CpuUtilizationBasedRecommender.MinTarget = config.CpuUtilizationBasedRecommender.MinTarget
+		pending.CpuUtilizationBasedRecommender.MaxTarget = config.CpuUtilizationBasedRecommender.MaxTarget
+		pending.CpuUtilizationBasedRecommender.MetricsPercentageThreshold = config.CpuUtilizationBasedRecommender.MetricsPercentageThreshold
+		pending.PolicyRecommendationController.MinRequiredReplicas = config.PolicyRecommendationController.MinRequiredReplicas
+		if !reflect.DeepEqual(pending, config) {
+			logger.Info("The config file changed the settings which apply on restart", "file", event.Name)
+		}
+		config = reloaded
+	})
+	viper.WatchConfig()
+}
+
 func parseCommaSeparatedValues(givenConfig string) []string {
 	if givenConfig == "" {
 		return nil
@@ -412,3 +1552,15 @@ func parseCommaSeparatedValues(givenConfig string) []string {
 	}
 	return parsedValues
 }
+
+// notificationChannels returns the channels for the configured webhook and Slack URLs.
+func notificationChannels(webhookUrl string, slackWebhookUrl string, timeout time.Duration) []notifier.Channel {
+	var channels []notifier.Channel
+	if len(webhookUrl) > 0 {
+		channels = append(channels, notifier.NewWebhookChannel(webhookUrl, timeout))
+	}
+	if len(slackWebhookUrl) > 0 {
+		channels = append(channels, notifier.NewSlackChannel(slackWebhookUrl, timeout))
+	}
+	return channels
+}
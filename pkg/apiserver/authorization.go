@@ -0,0 +1,230 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	policyRecommendationsResource = "policyrecommendations"
+	policiesResource              = "policies"
+)
+
+// ErrUnauthenticated is returned by the authorizers for the callers whose token isn't valid.
+var ErrUnauthenticated = errors.New("the bearer token of the request isn't valid")
+
+// Authorizer tells whether the caller with the bearer token can act on the resources of ottoscalr, e.g. the
+// PolicyRecommendations, of a namespace, or of all the namespaces or at the cluster scope for the empty namespace.
+type Authorizer interface {
+	Authorize(ctx context.Context, token, verb, resource, namespace string) (bool, error)
+}
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// SubjectAccessReviewAuthorizer authorizes the callers by the RBAC of the cluster. It reviews the token of the caller
+// with a TokenReview and checks whether its user can act on the PolicyRecommendations with a SubjectAccessReview, so
+// that the tenant teams can only query the recommendations of the workloads of their own namespaces. The decisions are
+// cached for the cacheTTL, so that listing the recommendations of the fleet doesn't review every namespace on every
+// request.
+type SubjectAccessReviewAuthorizer struct {
+	k8sClient client.Client
+	cacheTTL  time.Duration
+	now       func() time.Time
+
+	lock      sync.Mutex
+	decisions map[authorizationKey]authorizationDecision
+}
+
+type authorizationKey struct {
+	token     string
+	verb      string
+	resource  string
+	namespace string
+}
+
+type authorizationDecision struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+func NewSubjectAccessReviewAuthorizer(k8sClient client.Client, cacheTTL time.Duration) *SubjectAccessReviewAuthorizer {
+	return &SubjectAccessReviewAuthorizer{
+		k8sClient: k8sClient,
+		cacheTTL:  cacheTTL,
+		now:       time.Now,
+		decisions: map[authorizationKey]authorizationDecision{},
+	}
+}
+
+// Authorize reviews the token and the access of its user to the resource of the namespace. It returns
+// ErrUnauthenticated if the token isn't valid.
+func (a *SubjectAccessReviewAuthorizer) Authorize(ctx context.Context, token, verb, resource,
+	namespace string) (bool, error) {
+	key := authorizationKey{token: token, verb: verb, resource: resource, namespace: namespace}
+	a.lock.Lock()
+	decision, ok := a.decisions[key]
+	a.lock.Unlock()
+	if ok && a.now().Before(decision.expiresAt) {
+		return decision.allowed, nil
+	}
+
+	tokenReview := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := a.k8sClient.Create(ctx, tokenReview); err != nil {
+		return false, err
+	}
+	if !tokenReview.Status.Authenticated {
+		return false, ErrUnauthenticated
+	}
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	accessReview := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      verb,
+			Group:     v1alpha1.GroupVersion.Group,
+			Resource:  resource,
+		},
+	}}
+	if err := a.k8sClient.Create(ctx, accessReview); err != nil {
+		return false, err
+	}
+
+	a.lock.Lock()
+	now := a.now()
+	for cachedKey, cachedDecision := range a.decisions {
+		if !now.Before(cachedDecision.expiresAt) {
+			delete(a.decisions, cachedKey)
+		}
+	}
+	a.decisions[key] = authorizationDecision{allowed: accessReview.Status.Allowed, expiresAt: now.Add(a.cacheTTL)}
+	a.lock.Unlock()
+	return accessReview.Status.Allowed, nil
+}
+
+// authorize checks whether the caller of the request can act on the PolicyRecommendations of the namespace with the
+// verb, writing the error response if it can't. Every request is authorized without an authorizer.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, verb, namespace string) bool {
+	return s.authorizeResource(w, r, verb, policyRecommendationsResource, namespace)
+}
+
+// requireAuthorizer refuses the requests which change the state of the cluster unless the callers are authorized, as
+// anyone who can reach the API server could make them otherwise, writing the error response if it does.
+func (s *Server) requireAuthorizer(w http.ResponseWriter, operation string) bool {
+	if s.authorizer == nil {
+		http.Error(w, operation+" needs apiServer.authorization to be enabled", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// authorizeResource checks whether the caller of the request can act on the resource of the namespace with the verb,
+// like authorize.
+func (s *Server) authorizeResource(w http.ResponseWriter, r *http.Request, verb, resource, namespace string) bool {
+	if s.authorizer == nil {
+		return true
+	}
+	token, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+		return false
+	}
+	allowed, err := s.authorizer.Authorize(r.Context(), token, verb, resource, namespace)
+	if err != nil {
+		s.writeAuthorizationError(w, err)
+		return false
+	}
+	if !allowed {
+		http.Error(w, "not allowed to "+verb+" the "+resource+" of "+namespaceOrFleet(namespace), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// listAuthorizedPolicyRecommendations lists the PolicyRecommendations of the namespace, or of all the namespaces the
+// caller of the request can list them in for the empty namespace, writing the error response if it can't.
+func (s *Server) listAuthorizedPolicyRecommendations(w http.ResponseWriter, r *http.Request,
+	namespace string) ([]v1alpha1.PolicyRecommendation, bool) {
+	token, _ := bearerToken(r)
+	fleetWide := len(namespace) == 0 && s.authorizer != nil
+	if fleetWide {
+		if len(token) == 0 {
+			http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+			return nil, false
+		}
+		allowed, err := s.authorizer.Authorize(r.Context(), token, "list", policyRecommendationsResource, "")
+		if err != nil {
+			s.writeAuthorizationError(w, err)
+			return nil, false
+		}
+		fleetWide = !allowed
+	} else if !s.authorize(w, r, "list", namespace) {
+		return nil, false
+	}
+
+	policyrecos, err := s.listPolicyRecommendations(r.Context(), namespace)
+	if err != nil {
+		s.writeError(w, err)
+		return nil, false
+	}
+	if !fleetWide {
+		return policyrecos, true
+	}
+	// the caller can't list the recommendations of the fleet, so only the ones of its own namespaces are listed
+	allowedNamespaces := map[string]bool{}
+	authorized := make([]v1alpha1.PolicyRecommendation, 0, len(policyrecos))
+	for _, policyreco := range policyrecos {
+		allowed, ok := allowedNamespaces[policyreco.Namespace]
+		if !ok {
+			if allowed, err = s.authorizer.Authorize(r.Context(), token, "list", policyRecommendationsResource,
+				policyreco.Namespace); err != nil {
+				s.writeAuthorizationError(w, err)
+				return nil, false
+			}
+			allowedNamespaces[policyreco.Namespace] = allowed
+		}
+		if allowed {
+			authorized = append(authorized, policyreco)
+		}
+	}
+	return authorized, true
+}
+
+func (s *Server) writeAuthorizationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrUnauthenticated) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	s.logger.Error(err, "Error authorizing the request")
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func namespaceOrFleet(namespace string) string {
+	if len(namespace) == 0 {
+		return "all the namespaces"
+	}
+	return "the namespace " + namespace
+}
+
+// bearerToken returns the bearer token of the Authorization header of the request.
+func bearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && len(token) > 0
+}
@@ -0,0 +1,229 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// fakeAuthorizer authorizes the tokens for the verbs on the recommendations of the namespaces, with "*" for any verb.
+// The other resources are authorized on the namespaces prefixed with the resource, e.g. "policies/".
+type fakeAuthorizer map[string]map[string]string
+
+func (f fakeAuthorizer) Authorize(ctx context.Context, token, verb, resource, namespace string) (bool, error) {
+	namespaces, ok := f[token]
+	if !ok {
+		return false, ErrUnauthenticated
+	}
+	if resource != policyRecommendationsResource {
+		namespace = resource + "/" + namespace
+	}
+	allowedVerb, ok := namespaces[namespace]
+	return ok && (allowedVerb == "*" || allowedVerb == verb), nil
+}
+
+var _ = Describe("Authorization", func() {
+	var server *httptest.Server
+
+	get := func(path, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newPolicyReco("ns1", "app1", "safest-policy", intPtr(20), true),
+			newPolicyReco("ns1", "app2", "aggressive-policy", intPtr(40), false),
+			newPolicyReco("ns2", "app3", "safest-policy", nil, false),
+		).Build()
+		authorizer := fakeAuthorizer{
+			"tenant-token":       {"ns1": "*"},
+			"admin-token":        {"": "*", "ns1": "*", "ns2": "*"},
+			"policy-admin-token": {"": "*", "ns1": "*", "ns2": "*", policiesResource + "/": "create"},
+		}
+		server = httptest.NewServer(NewServer(k8sClient, nil, &fakeWhatIfRecommender{}, &fakeRetriggerer{}, "",
+			logr.Discard()).WithAuthorizer(authorizer).Handler())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should list only the recommendations of the namespaces of the tenant", func() {
+		resp := get(recommendationsPath, "tenant-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var summaries []RecommendationSummary
+		Expect(json.NewDecoder(resp.Body).Decode(&summaries)).To(Succeed())
+		Expect(summaries).To(HaveLen(2))
+		for _, summary := range summaries {
+			Expect(summary.Namespace).To(Equal("ns1"))
+		}
+
+		resp = get(recommendationsPath, "admin-token")
+		defer resp.Body.Close()
+		Expect(json.NewDecoder(resp.Body).Decode(&summaries)).To(Succeed())
+		Expect(summaries).To(HaveLen(3))
+
+		resp = get(savingsPath, "tenant-token")
+		defer resp.Body.Close()
+		fleet := FleetSavings{}
+		Expect(json.NewDecoder(resp.Body).Decode(&fleet)).To(Succeed())
+		Expect(fleet.Workloads).To(Equal(2))
+	})
+
+	It("should forbid the recommendations of the other namespaces", func() {
+		resp := get(recommendationsPath+"/ns2/app3", "tenant-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+
+		resp = get(recommendationsPath+"?namespace=ns2", "tenant-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+
+		resp = get(recommendationsPath+"/ns1/app1", "tenant-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		body, _ := json.Marshal(RetriggerRequest{Namespace: "ns2"})
+		req, _ := http.NewRequest(http.MethodPost, server.URL+retriggerPath, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer tenant-token")
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+	})
+
+	It("should import the policies of a snapshot only for the callers who can create the policies", func() {
+		importSnapshot := func(namespace, token string) *http.Response {
+			snapshot := reco.PolicySnapshot{Policies: []reco.PolicySnapshotEntry{{Name: "imported-policy",
+				Spec: v1alpha1.PolicySpec{RiskIndex: 10, MinReplicaPercentageCut: 80, TargetUtilization: 50}}}}
+			body, _ := json.Marshal(snapshot)
+			req, _ := http.NewRequest(http.MethodPost, server.URL+snapshotPath+"?dryRun=true&namespace="+namespace,
+				bytes.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		resp := importSnapshot("", "admin-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+
+		resp = importSnapshot("", "policy-admin-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		result := reco.PolicySnapshotImport{}
+		Expect(json.NewDecoder(resp.Body).Decode(&result)).To(Succeed())
+		Expect(result.CreatedPolicies).To(Equal([]string{"imported-policy"}))
+
+		// the policies aren't imported into a namespace
+		resp = importSnapshot("ns1", "tenant-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp = importSnapshot("ns2", "tenant-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+	})
+
+	It("should reject the requests without a valid token", func() {
+		resp := get(recommendationsPath, "")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+
+		resp = get(recommendationsPath+"/ns1/app1", "expired-token")
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("SubjectAccessReviewAuthorizer", func() {
+	var reviews []*authorizationv1.SubjectAccessReview
+	var authorizer *SubjectAccessReviewAuthorizer
+
+	BeforeEach(func() {
+		reviews = nil
+		k8sClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				switch review := obj.(type) {
+				case *authenticationv1.TokenReview:
+					if review.Spec.Token == "tenant-token" {
+						review.Status = authenticationv1.TokenReviewStatus{Authenticated: true,
+							User: authenticationv1.UserInfo{Username: "alice", Groups: []string{"payments"}}}
+					}
+				case *authorizationv1.SubjectAccessReview:
+					reviews = append(reviews, review)
+					review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "payments"
+				}
+				return nil
+			},
+		}).Build()
+		authorizer = NewSubjectAccessReviewAuthorizer(k8sClient, time.Minute)
+	})
+
+	It("should review the access of the user of the token to the recommendations of the namespace", func() {
+		allowed, err := authorizer.Authorize(context.TODO(), "tenant-token", "list", policyRecommendationsResource, "payments")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(reviews).To(HaveLen(1))
+		Expect(reviews[0].Spec.User).To(Equal("alice"))
+		Expect(reviews[0].Spec.Groups).To(Equal([]string{"payments"}))
+		Expect(*reviews[0].Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{Namespace: "payments",
+			Verb: "list", Group: "ottoscaler.io", Resource: "policyrecommendations"}))
+
+		allowed, err = authorizer.Authorize(context.TODO(), "tenant-token", "list", policyRecommendationsResource, "checkout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+
+		_, err = authorizer.Authorize(context.TODO(), "unknown-token", "list", policyRecommendationsResource, "payments")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+	})
+
+	It("should review the access of the user of the token to the cluster-scoped policies", func() {
+		allowed, err := authorizer.Authorize(context.TODO(), "tenant-token", "create", policiesResource, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(reviews).To(HaveLen(1))
+		Expect(*reviews[0].Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{Verb: "create",
+			Group: "ottoscaler.io", Resource: "policies"}))
+	})
+
+	It("should cache the decisions for the ttl", func() {
+		now := time.Now()
+		authorizer.now = func() time.Time { return now }
+		for i := 0; i < 3; i++ {
+			_, err := authorizer.Authorize(context.TODO(), "tenant-token", "list", policyRecommendationsResource, "payments")
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(reviews).To(HaveLen(1))
+
+		now = now.Add(2 * time.Minute)
+		_, err := authorizer.Authorize(context.TODO(), "tenant-token", "list", policyRecommendationsResource, "payments")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reviews).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,437 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	recommendationsPath = "/api/v1/recommendations"
+	savingsPath         = "/api/v1/savings"
+	whatIfPath          = "/api/v1/whatif"
+	retriggerPath       = "/api/v1/retrigger"
+	projectionPath      = "/api/v1/policies/projection"
+	ladderPath          = "/api/v1/policies/ladder"
+	snapshotPath        = "/api/v1/snapshot"
+
+	defaultWhatIfWindowDays = 28
+	// maxWhatIfRequestBytes bounds the bodies of the what-if requests, which only identify a workload
+	maxWhatIfRequestBytes = 1 << 20
+	shutdownTimeout       = 10 * time.Second
+)
+
+// WhatIfRecommender generates recommendations without persisting them.
+type WhatIfRecommender interface {
+	RecommendForWindow(ctx context.Context, wm reco.WorkloadMeta, metricWindow time.Duration) (*v1alpha1.HPAConfiguration,
+		*reco.RecommendationMetadata, error)
+}
+
+// Retriggerer queues the recommendations of the workloads matching a selector for a fresh recommendation.
+type Retriggerer interface {
+	QueueMatchingForExecution(ctx context.Context, namespace string, selector labels.Selector) ([]types.NamespacedName, error)
+}
+
+// Server exposes the recommendations to the systems outside the cluster over a REST API, along with a what-if
+// endpoint to generate recommendations on demand, a re-trigger endpoint to regenerate them en masse and a projection
+// endpoint to dry-run a Policy across the fleet, a ladder endpoint to follow the promotions of the workloads across the
+// policies and a snapshot endpoint to carry the policies of the workloads over to another cluster.
+type Server struct {
+	k8sClient   client.Client
+	explainer   reco.Explainer
+	recommender WhatIfRecommender
+	retriggerer Retriggerer
+	policyStore policy.Store
+	projector   *reco.PolicyProjector
+	snapshotter *reco.PolicySnapshotter
+	bindAddress string
+	logger      logr.Logger
+
+	authorizer          Authorizer
+	policyExpiryAge     time.Duration
+	maxWhatIfWindowDays int
+}
+
+func NewServer(k8sClient client.Client, explainer reco.Explainer, recommender WhatIfRecommender, retriggerer Retriggerer,
+	bindAddress string, logger logr.Logger) *Server {
+	policyStore := policy.NewPolicyStore(k8sClient)
+	return &Server{
+		k8sClient:   k8sClient,
+		explainer:   explainer,
+		recommender: recommender,
+		retriggerer: retriggerer,
+		policyStore: policyStore,
+		projector:   reco.NewPolicyProjector(k8sClient, policyStore),
+		snapshotter: reco.NewPolicySnapshotter(k8sClient),
+		bindAddress: bindAddress,
+		logger:      logger.WithName("APIServer"),
+
+		maxWhatIfWindowDays: defaultWhatIfWindowDays,
+	}
+}
+
+// WithAuthorizer scopes the requests to the namespaces the callers are authorized for, by the bearer tokens of the
+// requests. Without an authorizer every caller can query the recommendations of the whole fleet.
+func (s *Server) WithAuthorizer(authorizer Authorizer) *Server {
+	s.authorizer = authorizer
+	return s
+}
+
+// WithPolicyExpiryAge sets the age the policies of the workloads expire at, after which the aging iterator promotes
+// them to the next policy, for the next transitions of the policy ladder.
+func (s *Server) WithPolicyExpiryAge(age time.Duration) *Server {
+	s.policyExpiryAge = age
+	return s
+}
+
+// WithMaxWhatIfWindowDays bounds the days of metrics the what-if recommendations are generated from, as every day
+// widens the range queried from Prometheus and simulated. It defaults to 28 days.
+func (s *Server) WithMaxWhatIfWindowDays(days int) *Server {
+	if days > 0 {
+		s.maxWhatIfWindowDays = days
+	}
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(recommendationsPath, s.listRecommendations)
+	mux.HandleFunc(recommendationsPath+"/", s.getRecommendation)
+	mux.HandleFunc(savingsPath, s.getFleetSavings)
+	mux.HandleFunc(whatIfPath, s.whatIf)
+	mux.HandleFunc(retriggerPath, s.retrigger)
+	mux.HandleFunc(projectionPath, s.projectPolicy)
+	mux.HandleFunc(ladderPath, s.getPolicyLadder)
+	mux.HandleFunc(snapshotPath, s.snapshot)
+	return mux
+}
+
+// Start serves the API until the context is cancelled. It implements the manager.Runnable interface.
+func (s *Server) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.bindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error(err, "Error shutting down the API server")
+		}
+	}()
+
+	s.logger.Info("Starting the API server", "address", s.bindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection is false as the API server is served by all the replicas. Its reads are served to every caller,
+// while the re-triggers and the imports, which change the state of the cluster, only to the authorized callers.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) listRecommendations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	policyrecos, ok := s.listAuthorizedPolicyRecommendations(w, r, r.URL.Query().Get("namespace"))
+	if !ok {
+		return
+	}
+	summaries := make([]RecommendationSummary, 0, len(policyrecos))
+	for _, policyreco := range policyrecos {
+		summaries = append(summaries, NewRecommendationSummary(policyreco))
+	}
+	s.writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) getRecommendation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, recommendationsPath+"/"), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		http.Error(w, "expected "+recommendationsPath+"/<namespace>/<workload>", http.StatusBadRequest)
+		return
+	}
+
+	if !s.authorize(w, r, "get", parts[0]) {
+		return
+	}
+
+	policyreco := v1alpha1.PolicyRecommendation{}
+	if err := s.k8sClient.Get(r.Context(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, &policyreco); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	detail := RecommendationDetail{
+		RecommendationSummary:     NewRecommendationSummary(policyreco),
+		MetricsWindowStart:        policyreco.Status.MetricsWindowStart,
+		MetricsWindowEnd:          policyreco.Status.MetricsWindowEnd,
+		DataPointsCoveragePercent: policyreco.Status.DataPointsCoveragePercent,
+		Conditions:                policyreco.Status.Conditions,
+	}
+	if s.explainer != nil {
+		if explanation, ok := s.explainer.Explain(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name); ok {
+			detail.Explanation = explanation
+		}
+	}
+	s.writeJSON(w, http.StatusOK, detail)
+}
+
+func (s *Server) getFleetSavings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	policyrecos, ok := s.listAuthorizedPolicyRecommendations(w, r, "")
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, SummarizeFleet(policyrecos))
+}
+
+func (s *Server) whatIf(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.recommender == nil {
+		http.Error(w, "what-if recommendations are not supported", http.StatusNotImplemented)
+		return
+	}
+	request := WhatIfRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWhatIfRequestBytes)).Decode(&request); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(request.Namespace) == 0 || len(request.Kind) == 0 || len(request.Name) == 0 {
+		http.Error(w, "namespace, kind and name are required", http.StatusBadRequest)
+		return
+	}
+	if request.WindowDays > s.maxWhatIfWindowDays {
+		http.Error(w, "windowDays should be at most "+strconv.Itoa(s.maxWhatIfWindowDays), http.StatusBadRequest)
+		return
+	}
+	if request.WindowDays <= 0 {
+		request.WindowDays = defaultWhatIfWindowDays
+		if request.WindowDays > s.maxWhatIfWindowDays {
+			request.WindowDays = s.maxWhatIfWindowDays
+		}
+	}
+	if !s.authorize(w, r, "get", request.Namespace) {
+		return
+	}
+
+	recommendation, recoMetadata, err := s.recommender.RecommendForWindow(r.Context(), reco.WorkloadMeta{
+		TypeMeta:  metav1.TypeMeta{Kind: request.Kind, APIVersion: request.APIVersion},
+		Name:      request.Name,
+		Namespace: request.Namespace,
+	}, time.Duration(request.WindowDays)*24*time.Hour)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	response := WhatIfResponse{Recommendation: recommendation}
+	if recoMetadata != nil {
+		response.MetricsWindowStart = recoMetadata.MetricsWindowStart
+		response.MetricsWindowEnd = recoMetadata.MetricsWindowEnd
+		response.DataPointsCoveragePercent = recoMetadata.DataPointsCoveragePercent
+		response.ProjectedSavingsPercent = recoMetadata.ProjectedSavingsPercent
+		response.NoOp = recoMetadata.NoOp
+		response.Confidence = recoMetadata.Confidence
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) retrigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.retriggerer == nil {
+		http.Error(w, "re-triggering recommendations is not supported", http.StatusNotImplemented)
+		return
+	}
+	if !s.requireAuthorizer(w, "re-triggering recommendations") {
+		return
+	}
+	request := RetriggerRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	selector, err := labels.Parse(request.Selector)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !s.authorize(w, r, "update", request.Namespace) {
+		return
+	}
+
+	queued, err := s.retriggerer.QueueMatchingForExecution(r.Context(), request.Namespace, selector)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	response := RetriggerResponse{Queued: make([]string, 0, len(queued))}
+	for _, policyreco := range queued {
+		response.Queued = append(response.Queued, policyreco.String())
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// projectPolicy reports how the Policy in the request body would change the configs of the workloads, in the namespace
+// of the namespace query parameter or across the fleet, without applying it.
+func (s *Server) projectPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	candidate := v1alpha1.Policy{}
+	if err := json.NewDecoder(r.Body).Decode(&candidate); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(candidate.Name) == 0 {
+		http.Error(w, "metadata.name of the policy is required", http.StatusBadRequest)
+		return
+	}
+	if candidate.Spec.MinReplicaPercentageCut < 0 || candidate.Spec.MinReplicaPercentageCut > 100 ||
+		candidate.Spec.TargetUtilization <= 0 || candidate.Spec.TargetUtilization > 100 {
+		http.Error(w, "minReplicaPercentageCut should be within 0 and 100 and targetUtilization within 1 and 100",
+			http.StatusBadRequest)
+		return
+	}
+
+	if !s.authorize(w, r, "list", r.URL.Query().Get("namespace")) {
+		return
+	}
+
+	projection, err := s.projector.Project(r.Context(), candidate, r.URL.Query().Get("namespace"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, projection)
+}
+
+// getPolicyLadder reports the positions of the workloads on the ladder of the policies, in the namespace of the
+// namespace query parameter or across the fleet.
+func (s *Server) getPolicyLadder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	policyrecos, ok := s.listAuthorizedPolicyRecommendations(w, r, r.URL.Query().Get("namespace"))
+	if !ok {
+		return
+	}
+	policies, err := s.policyStore.GetSortedPolicies()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, reco.NewPolicyLadder(policies.Items, policyrecos, s.policyExpiryAge))
+}
+
+// snapshot exports the policies and the policyrecos of the namespace of the namespace query parameter, or of the
+// fleet, on a GET, and imports the snapshot in the request body on a POST, as a dry run with the dryRun query parameter.
+// The snapshots are imported only for the authorized callers.
+func (s *Server) snapshot(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	switch r.Method {
+	case http.MethodGet:
+		if !s.authorize(w, r, "list", namespace) {
+			return
+		}
+		snapshot, err := s.snapshotter.Export(r.Context(), namespace)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, snapshot)
+	case http.MethodPost:
+		if !s.requireAuthorizer(w, "importing a snapshot") {
+			return
+		}
+		snapshot := reco.PolicySnapshot{}
+		if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// the import creates the policyrecos missing and, across the fleet, the cluster-scoped policies missing
+		if !s.authorize(w, r, "create", namespace) || !s.authorize(w, r, "update", namespace) {
+			return
+		}
+		if len(namespace) == 0 && len(snapshot.Policies) > 0 && !s.authorizeResource(w, r, "create", policiesResource, "") {
+			return
+		}
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+		result, err := s.snapshotter.Import(r.Context(), snapshot, namespace, dryRun)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, result)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) listPolicyRecommendations(ctx context.Context, namespace string) ([]v1alpha1.PolicyRecommendation, error) {
+	policyrecos := &v1alpha1.PolicyRecommendationList{}
+	var opts []client.ListOption
+	if len(namespace) > 0 {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := s.k8sClient.List(ctx, policyrecos, opts...); err != nil {
+		return nil, err
+	}
+	return policyrecos.Items, nil
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Error(err, "Error writing the response")
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	if apierrors.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.logger.Error(err, "Error serving the request")
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,335 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeWhatIfRecommender struct {
+	workload     reco.WorkloadMeta
+	metricWindow time.Duration
+}
+
+func (f *fakeWhatIfRecommender) RecommendForWindow(ctx context.Context, wm reco.WorkloadMeta,
+	metricWindow time.Duration) (*v1alpha1.HPAConfiguration, *reco.RecommendationMetadata, error) {
+	f.workload = wm
+	f.metricWindow = metricWindow
+	return &v1alpha1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 60},
+		&reco.RecommendationMetadata{DataPointsCoveragePercent: 95, ProjectedSavingsPercent: 40}, nil
+}
+
+type fakeRetriggerer struct {
+	namespace string
+	selector  labels.Selector
+}
+
+func (f *fakeRetriggerer) QueueMatchingForExecution(ctx context.Context, namespace string,
+	selector labels.Selector) ([]types.NamespacedName, error) {
+	f.namespace = namespace
+	f.selector = selector
+	return []types.NamespacedName{{Namespace: namespace, Name: "app1"}}, nil
+}
+
+func newPolicyReco(namespace, name, policy string, savings *int, enforced bool) *v1alpha1.PolicyRecommendation {
+	policyreco := &v1alpha1.PolicyRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1alpha1.PolicyRecommendationSpec{
+			WorkloadMeta:           v1alpha1.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name},
+			TargetHPAConfiguration: v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50},
+			Policy:                 policy,
+		},
+		Status: v1alpha1.PolicyRecommendationStatus{ProjectedSavingsPercent: savings},
+	}
+	if enforced {
+		policyreco.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.HPAEnforced),
+			Status: metav1.ConditionTrue}}
+	}
+	return policyreco
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+var _ = Describe("Server", func() {
+	var (
+		server           *httptest.Server
+		authorizedServer *httptest.Server
+		recommender      *fakeWhatIfRecommender
+		retriggerer      *fakeRetriggerer
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newPolicyReco("ns1", "app1", "safest-policy", intPtr(20), true),
+			newPolicyReco("ns1", "app2", "aggressive-policy", intPtr(40), false),
+			newPolicyReco("ns2", "app3", "safest-policy", nil, false),
+			&v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "safest-policy"},
+				Spec: v1alpha1.PolicySpec{RiskIndex: 1, MinReplicaPercentageCut: 100, TargetUtilization: 40}},
+		).Build()
+		recommender = &fakeWhatIfRecommender{}
+		retriggerer = &fakeRetriggerer{}
+		server = httptest.NewServer(NewServer(k8sClient, nil, recommender, retriggerer, "", logr.Discard()).Handler())
+		// the callers with the admin-token are authorized for ns1
+		authorizedServer = httptest.NewServer(NewServer(k8sClient, nil, recommender, retriggerer, "", logr.Discard()).
+			WithAuthorizer(fakeAuthorizer{"admin-token": {"ns1": "*"}}).Handler())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		authorizedServer.Close()
+	})
+
+	postAuthorized := func(path string, body []byte) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, authorizedServer.URL+path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-token")
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+
+	It("should list the recommendations of a namespace", func() {
+		resp, err := http.Get(server.URL + recommendationsPath + "?namespace=ns1")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var summaries []RecommendationSummary
+		Expect(json.NewDecoder(resp.Body).Decode(&summaries)).To(Succeed())
+		Expect(summaries).To(HaveLen(2))
+		for _, summary := range summaries {
+			Expect(summary.Namespace).To(Equal("ns1"))
+		}
+	})
+
+	It("should get the recommendation of a workload", func() {
+		resp, err := http.Get(server.URL + recommendationsPath + "/ns1/app1")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		detail := RecommendationDetail{}
+		Expect(json.NewDecoder(resp.Body).Decode(&detail)).To(Succeed())
+		Expect(detail.Workload).To(Equal("app1"))
+		Expect(detail.Policy).To(Equal("safest-policy"))
+		Expect(detail.HPAEnforced).To(BeTrue())
+		Expect(detail.TargetHPAConfiguration.Min).To(Equal(10))
+		Expect(*detail.ProjectedSavingsPercent).To(Equal(20))
+	})
+
+	It("should return not found for a workload without a recommendation", func() {
+		resp, err := http.Get(server.URL + recommendationsPath + "/ns1/unknown")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("should summarize the savings across the fleet", func() {
+		resp, err := http.Get(server.URL + savingsPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		fleet := FleetSavings{}
+		Expect(json.NewDecoder(resp.Body).Decode(&fleet)).To(Succeed())
+		Expect(fleet.Workloads).To(Equal(3))
+		Expect(fleet.EnforcedWorkloads).To(Equal(1))
+		Expect(fleet.AvgProjectedSavingsPercent).To(Equal(30.0))
+		Expect(fleet.Namespaces).To(HaveLen(2))
+		Expect(fleet.Namespaces[0].Namespace).To(Equal("ns1"))
+		Expect(fleet.Namespaces[0].PolicyDistribution).To(Equal(map[string]int{"safest-policy": 1, "aggressive-policy": 1}))
+		Expect(fleet.Namespaces[1].AvgProjectedSavingsPercent).To(Equal(0.0))
+	})
+
+	It("should generate a what-if recommendation for the requested window", func() {
+		body, _ := json.Marshal(WhatIfRequest{Namespace: "ns1", Kind: "Deployment", Name: "app1", WindowDays: 7})
+		resp, err := http.Post(server.URL+whatIfPath, "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		response := WhatIfResponse{}
+		Expect(json.NewDecoder(resp.Body).Decode(&response)).To(Succeed())
+		Expect(response.Recommendation.Min).To(Equal(5))
+		Expect(response.ProjectedSavingsPercent).To(Equal(40))
+		Expect(recommender.workload.Name).To(Equal("app1"))
+		Expect(recommender.metricWindow).To(Equal(7 * 24 * time.Hour))
+	})
+
+	It("should bound the window of the what-if recommendations", func() {
+		whatIf := func(windowDays int) *http.Response {
+			body, _ := json.Marshal(WhatIfRequest{Namespace: "ns1", Kind: "Deployment", Name: "app1", WindowDays: windowDays})
+			resp, err := http.Post(server.URL+whatIfPath, "application/json", bytes.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		resp := whatIf(100000)
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(recommender.workload.Name).To(BeEmpty())
+
+		resp = whatIf(28)
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(recommender.metricWindow).To(Equal(28 * 24 * time.Hour))
+	})
+
+	It("should default the window of the what-if recommendations within the bound", func() {
+		boundedServer := httptest.NewServer(NewServer(fake.NewClientBuilder().Build(), nil, recommender, retriggerer, "",
+			logr.Discard()).WithMaxWhatIfWindowDays(7).Handler())
+		defer boundedServer.Close()
+		body, _ := json.Marshal(WhatIfRequest{Namespace: "ns1", Kind: "Deployment", Name: "app1"})
+		resp, err := http.Post(boundedServer.URL+whatIfPath, "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(recommender.metricWindow).To(Equal(7 * 24 * time.Hour))
+	})
+
+	It("should reject the what-if requests with an oversized body", func() {
+		body := append([]byte(`{"namespace": "ns1", "kind": "Deployment", "name": "app1", "apiVersion": "`),
+			bytes.Repeat([]byte("v"), maxWhatIfRequestBytes)...)
+		resp, err := http.Post(server.URL+whatIfPath, "application/json", bytes.NewReader(append(body, `"}`...)))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusRequestEntityTooLarge))
+		Expect(recommender.workload.Name).To(BeEmpty())
+	})
+
+	It("should reject a what-if request without a workload", func() {
+		body, _ := json.Marshal(WhatIfRequest{Namespace: "ns1", WindowDays: 7})
+		resp, err := http.Post(server.URL+whatIfPath, "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should re-trigger the recommendations of the workloads matching the selector", func() {
+		body, _ := json.Marshal(RetriggerRequest{Namespace: "ns1", Selector: "team=payments"})
+		resp := postAuthorized(retriggerPath, body)
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		response := RetriggerResponse{}
+		Expect(json.NewDecoder(resp.Body).Decode(&response)).To(Succeed())
+		Expect(response.Queued).To(Equal([]string{"ns1/app1"}))
+		Expect(retriggerer.namespace).To(Equal("ns1"))
+		Expect(retriggerer.selector.String()).To(Equal("team=payments"))
+	})
+
+	It("should reject a re-trigger request with an invalid selector", func() {
+		body, _ := json.Marshal(RetriggerRequest{Namespace: "ns1", Selector: "team in"})
+		resp := postAuthorized(retriggerPath, body)
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should re-trigger the recommendations only for the authorized callers", func() {
+		body, _ := json.Marshal(RetriggerRequest{})
+		resp, err := http.Post(server.URL+retriggerPath, "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(retriggerer.selector).To(BeNil())
+	})
+
+	It("should project a change of a policy across the fleet without applying it", func() {
+		body, _ := json.Marshal(v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "safest-policy"},
+			Spec: v1alpha1.PolicySpec{RiskIndex: 1, MinReplicaPercentageCut: 100, TargetUtilization: 45}})
+		resp, err := http.Post(server.URL+projectionPath, "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		projection := reco.PolicyProjection{}
+		Expect(json.NewDecoder(resp.Body).Decode(&projection)).To(Succeed())
+		Expect(projection.Workloads).To(Equal(2))
+		Expect(projection.ChangedWorkloads).To(Equal(2))
+		Expect(projection.Changes[0].Projected).To(Equal(v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 45}))
+	})
+
+	It("should report the positions of the workloads of a namespace on the policy ladder", func() {
+		resp, err := http.Get(server.URL + ladderPath + "?namespace=ns1")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		ladder := reco.PolicyLadder{}
+		Expect(json.NewDecoder(resp.Body).Decode(&ladder)).To(Succeed())
+		Expect(ladder.Steps).To(Equal([]reco.PolicyLadderStep{{Policy: "safest-policy", RiskIndex: 1, Workloads: 1,
+			TargetWorkloads: 2}}))
+		Expect(ladder.Workloads).To(HaveLen(2))
+		for _, position := range ladder.Workloads {
+			Expect(position.TargetPolicy).To(Equal("safest-policy"))
+		}
+	})
+
+	It("should export the policy state of a namespace and import it", func() {
+		resp, err := http.Get(server.URL + snapshotPath + "?namespace=ns1")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		snapshot := reco.PolicySnapshot{}
+		Expect(json.NewDecoder(resp.Body).Decode(&snapshot)).To(Succeed())
+		Expect(snapshot.Policies).To(HaveLen(1))
+		Expect(snapshot.PolicyRecommendations).To(HaveLen(2))
+
+		// the snapshots are only imported for the authorized callers
+		body, _ := json.Marshal(snapshot)
+		resp, err = http.Post(server.URL+snapshotPath+"?namespace=ns1&dryRun=true", "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+
+		resp = postAuthorized(snapshotPath+"?namespace=ns1&dryRun=true", body)
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		result := reco.PolicySnapshotImport{}
+		Expect(json.NewDecoder(resp.Body).Decode(&result)).To(Succeed())
+		Expect(result.DryRun).To(BeTrue())
+		Expect(result.RestoredPolicyRecommendations).To(Equal([]string{"ns1/app1"}))
+		Expect(result.SkippedPolicyRecommendations).To(HaveKey("ns1/app2"))
+	})
+
+	It("should reject a projection of a policy without a name", func() {
+		body, _ := json.Marshal(v1alpha1.Policy{Spec: v1alpha1.PolicySpec{MinReplicaPercentageCut: 50, TargetUtilization: 45}})
+		resp, err := http.Post(server.URL+projectionPath, "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("SummarizeFleet", func() {
+	It("should leave the workloads at the no-op configuration out of the projected savings", func() {
+		noOp := newPolicyReco("ns1", "app2", "safest-policy", intPtr(0), true)
+		noOp.Status.Conditions = append(noOp.Status.Conditions, metav1.Condition{Type: string(v1alpha1.NoOpRecommended),
+			Status: metav1.ConditionTrue, Reason: reco.InsufficientMetricsReason})
+		Expect(NewRecommendationSummary(*noOp).NoOpReason).To(Equal(reco.InsufficientMetricsReason))
+
+		fleet := SummarizeFleet([]v1alpha1.PolicyRecommendation{*newPolicyReco("ns1", "app1", "safest-policy", intPtr(20), true), *noOp})
+		Expect(fleet.Workloads).To(Equal(2))
+		Expect(fleet.EnforcedWorkloads).To(Equal(2))
+		Expect(fleet.AvgProjectedSavingsPercent).To(Equal(20.0))
+	})
+})
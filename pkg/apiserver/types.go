@@ -0,0 +1,172 @@
+package apiserver
+
+import (
+	"sort"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecommendationSummary is the gist of the PolicyRecommendation of a workload.
+type RecommendationSummary struct {
+	Namespace               string                    `json:"namespace"`
+	Kind                    string                    `json:"kind"`
+	Workload                string                    `json:"workload"`
+	Policy                  string                    `json:"policy"`
+	CurrentHPAConfiguration v1alpha1.HPAConfiguration `json:"currentHPAConfig"`
+	TargetHPAConfiguration  v1alpha1.HPAConfiguration `json:"targetHPAConfig"`
+	ProjectedSavingsPercent *int                      `json:"projectedSavingsPercent,omitempty"`
+	ConfidencePercent       *int                      `json:"confidencePercent,omitempty"`
+	HPAEnforced             bool                      `json:"hpaEnforced"`
+	GeneratedAt             *metav1.Time              `json:"generatedAt,omitempty"`
+	// NoOpReason is why the workload couldn't be recommended a config, if it's at the no-op configuration.
+	NoOpReason string `json:"noOpReason,omitempty"`
+}
+
+// RecommendationDetail is the PolicyRecommendation of a workload along with the reasoning behind it.
+type RecommendationDetail struct {
+	RecommendationSummary     `json:",inline"`
+	MetricsWindowStart        *metav1.Time       `json:"metricsWindowStart,omitempty"`
+	MetricsWindowEnd          *metav1.Time       `json:"metricsWindowEnd,omitempty"`
+	DataPointsCoveragePercent *int               `json:"dataPointsCoveragePercent,omitempty"`
+	Conditions                []metav1.Condition `json:"conditions,omitempty"`
+	Explanation               *reco.Explanation  `json:"explanation,omitempty"`
+}
+
+// FleetSavings summarizes the recommendations across all the namespaces.
+type FleetSavings struct {
+	Workloads                  int                `json:"workloads"`
+	EnforcedWorkloads          int                `json:"enforcedWorkloads"`
+	AvgProjectedSavingsPercent float64            `json:"avgProjectedSavingsPercent"`
+	Namespaces                 []NamespaceSavings `json:"namespaces"`
+}
+
+// NamespaceSavings summarizes the recommendations of the workloads in a namespace.
+type NamespaceSavings struct {
+	Namespace                  string         `json:"namespace"`
+	Workloads                  int            `json:"workloads"`
+	EnforcedWorkloads          int            `json:"enforcedWorkloads"`
+	AvgProjectedSavingsPercent float64        `json:"avgProjectedSavingsPercent"`
+	PolicyDistribution         map[string]int `json:"policyDistribution"`
+}
+
+// WhatIfRequest identifies the workload and the metrics window to generate a recommendation for.
+type WhatIfRequest struct {
+	Namespace  string `json:"namespace"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	// WindowDays is the number of days of metrics the recommendation is generated from.
+	WindowDays int `json:"windowDays"`
+}
+
+// WhatIfResponse is the recommendation generated for a WhatIfRequest. It isn't persisted.
+type WhatIfResponse struct {
+	Recommendation            *v1alpha1.HPAConfiguration     `json:"recommendation"`
+	MetricsWindowStart        time.Time                      `json:"metricsWindowStart"`
+	MetricsWindowEnd          time.Time                      `json:"metricsWindowEnd"`
+	DataPointsCoveragePercent int                            `json:"dataPointsCoveragePercent"`
+	ProjectedSavingsPercent   int                            `json:"projectedSavingsPercent"`
+	NoOp                      *reco.NoOpRecommendation       `json:"noOp,omitempty"`
+	Confidence                *reco.RecommendationConfidence `json:"confidence,omitempty"`
+}
+
+// RetriggerRequest selects the workloads to regenerate the recommendations of. An empty namespace selects the
+// workloads in all the namespaces and an empty selector all the workloads in the namespace.
+type RetriggerRequest struct {
+	Namespace string `json:"namespace"`
+	Selector  string `json:"selector"`
+}
+
+// RetriggerResponse lists the PolicyRecommendations queued for a fresh recommendation.
+type RetriggerResponse struct {
+	Queued []string `json:"queued"`
+}
+
+func isHPAEnforced(policyreco v1alpha1.PolicyRecommendation) bool {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.HPAEnforced) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// noOpReason returns the reason the policyreco was last marked with the NoOpRecommended condition for, or empty if
+// its workload was recommended a config.
+func noOpReason(policyreco v1alpha1.PolicyRecommendation) string {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.NoOpRecommended) && condition.Status == metav1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+func NewRecommendationSummary(policyreco v1alpha1.PolicyRecommendation) RecommendationSummary {
+	return RecommendationSummary{
+		Namespace:               policyreco.Namespace,
+		Kind:                    policyreco.Spec.WorkloadMeta.Kind,
+		Workload:                policyreco.Spec.WorkloadMeta.Name,
+		Policy:                  policyreco.Spec.Policy,
+		CurrentHPAConfiguration: policyreco.Spec.CurrentHPAConfiguration,
+		TargetHPAConfiguration:  policyreco.Spec.TargetHPAConfiguration,
+		ProjectedSavingsPercent: policyreco.Status.ProjectedSavingsPercent,
+		ConfidencePercent:       policyreco.Status.ConfidencePercent,
+		HPAEnforced:             isHPAEnforced(policyreco),
+		GeneratedAt:             policyreco.Spec.GeneratedAt,
+		NoOpReason:              noOpReason(policyreco),
+	}
+}
+
+// SummarizeFleet aggregates the recommendations per namespace. The average projected savings only account for
+// the workloads with a projection, which the workloads at the no-op configuration don't have.
+func SummarizeFleet(policyrecos []v1alpha1.PolicyRecommendation) FleetSavings {
+	type savingsAccumulator struct {
+		total float64
+		count int
+	}
+	namespaces := make(map[string]*NamespaceSavings)
+	namespaceSavings := make(map[string]*savingsAccumulator)
+	fleetSavings := &savingsAccumulator{}
+	fleet := FleetSavings{}
+
+	for _, policyreco := range policyrecos {
+		ns, ok := namespaces[policyreco.Namespace]
+		if !ok {
+			ns = &NamespaceSavings{Namespace: policyreco.Namespace, PolicyDistribution: make(map[string]int)}
+			namespaces[policyreco.Namespace] = ns
+			namespaceSavings[policyreco.Namespace] = &savingsAccumulator{}
+		}
+		ns.Workloads++
+		fleet.Workloads++
+		ns.PolicyDistribution[policyreco.Spec.Policy]++
+		if isHPAEnforced(policyreco) {
+			ns.EnforcedWorkloads++
+			fleet.EnforcedWorkloads++
+		}
+		if policyreco.Status.ProjectedSavingsPercent != nil && len(noOpReason(policyreco)) == 0 {
+			savings := float64(*policyreco.Status.ProjectedSavingsPercent)
+			namespaceSavings[policyreco.Namespace].total += savings
+			namespaceSavings[policyreco.Namespace].count++
+			fleetSavings.total += savings
+			fleetSavings.count++
+		}
+	}
+
+	for namespace, ns := range namespaces {
+		if accumulator := namespaceSavings[namespace]; accumulator.count > 0 {
+			ns.AvgProjectedSavingsPercent = accumulator.total / float64(accumulator.count)
+		}
+		fleet.Namespaces = append(fleet.Namespaces, *ns)
+	}
+	if fleetSavings.count > 0 {
+		fleet.AvgProjectedSavingsPercent = fleetSavings.total / float64(fleetSavings.count)
+	}
+	sort.Slice(fleet.Namespaces, func(i, j int) bool {
+		return fleet.Namespaces[i].Namespace < fleet.Namespaces[j].Namespace
+	})
+	return fleet
+}
@@ -0,0 +1,211 @@
+package approval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	changeRequestsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "approval_change_requests_count",
+			Help: "Number of change requests submitted for the policy transitions, as Pending, and decided by their status"}, []string{"namespace", "status"},
+	)
+
+	changeRequestErrorsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "approval_change_request_errors_count",
+			Help: "Number of calls to the change management system which failed"}, []string{"namespace"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(changeRequestsCounter, changeRequestErrorsCounter)
+}
+
+// Request is a policy transition of a workload to be approved by the change management system.
+type Request struct {
+	Transition   notifier.EventType         `json:"transition"`
+	Namespace    string                     `json:"namespace"`
+	WorkloadKind string                     `json:"workloadKind"`
+	Workload     string                     `json:"workload"`
+	OldPolicy    string                     `json:"oldPolicy"`
+	Policy       string                     `json:"policy"`
+	OldConfig    *v1alpha1.HPAConfiguration `json:"oldConfig,omitempty"`
+	NewConfig    *v1alpha1.HPAConfiguration `json:"newConfig,omitempty"`
+	// Preview is the change of the autoscaler of the workload the new config would be enforced with, if it's known.
+	Preview *v1alpha1.EnforcementPreview `json:"preview,omitempty"`
+}
+
+// Approver raises the change requests for the policy transitions in an external change management system, e.g.
+// ServiceNow or JIRA, and looks up their decisions.
+type Approver interface {
+	Submit(ctx context.Context, request Request) (string, v1alpha1.ChangeRequestStatus, error)
+	Status(ctx context.Context, ticketID string) (v1alpha1.ChangeRequestStatus, error)
+}
+
+// WebhookApprover raises the change requests through an HTTP endpoint fronting the change management system. The
+// requests are POSTed as JSON to the url, which responds with the ticketId and the status of the change request, and
+// the status of a ticket is looked up with a GET on the url suffixed with the ticketId.
+type WebhookApprover struct {
+	url        string
+	httpClient *http.Client
+}
+
+type changeRequestResponse struct {
+	TicketID string                       `json:"ticketId"`
+	Status   v1alpha1.ChangeRequestStatus `json:"status"`
+}
+
+func NewWebhookApprover(url string, timeout time.Duration) *WebhookApprover {
+	return &WebhookApprover{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *WebhookApprover) Submit(ctx context.Context, request Request) (string, v1alpha1.ChangeRequestStatus, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	response, err := a.do(req)
+	if err != nil {
+		return "", "", err
+	}
+	if len(response.TicketID) == 0 {
+		return "", "", fmt.Errorf("change management endpoint responded without a ticketId")
+	}
+	return response.TicketID, response.Status, nil
+}
+
+func (a *WebhookApprover) Status(ctx context.Context, ticketID string) (v1alpha1.ChangeRequestStatus, error) {
+	ticketURL, err := url.JoinPath(a.url, url.PathEscape(ticketID))
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ticketURL, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := a.do(req)
+	if err != nil {
+		return "", err
+	}
+	return response.Status, nil
+}
+
+func (a *WebhookApprover) do(req *http.Request) (*changeRequestResponse, error) {
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("change management endpoint responded with status code %d", resp.StatusCode)
+	}
+	response := &changeRequestResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	switch response.Status {
+	case v1alpha1.ChangeRequestPending, v1alpha1.ChangeRequestApproved, v1alpha1.ChangeRequestRejected:
+		return response, nil
+	}
+	return nil, fmt.Errorf("change management endpoint responded with an unknown status %q", response.Status)
+}
+
+// Gate holds back the policy transitions of the workloads until the change management system approves them. A
+// transition is submitted as a change request once and its status is looked up on every review until it's decided.
+// Change requests still pending after the timeout time out. The rejected and timed out transitions are submitted
+// again once the timeout has passed since they were submitted, so that they hold the workload back for that long. An
+// approval is spent once the transition is enforced, so entering the policy again, e.g. after a rollback, is submitted
+// anew.
+type Gate struct {
+	approver    Approver
+	timeout     time.Duration
+	transitions map[notifier.EventType]bool
+}
+
+// NewGate creates a gate holding back the transitions, or only the promotions if none are given. Rolling back a
+// workload on a breach isn't held back.
+func NewGate(approver Approver, timeout time.Duration, transitions ...notifier.EventType) *Gate {
+	if len(transitions) == 0 {
+		transitions = []notifier.EventType{notifier.PolicyPromoted}
+	}
+	gated := map[notifier.EventType]bool{}
+	for _, transition := range transitions {
+		gated[transition] = transition != notifier.PolicyRolledBack
+	}
+	return &Gate{approver: approver, timeout: timeout, transitions: gated}
+}
+
+// Gates tells whether the transition needs to be approved before it's enforced.
+func (g *Gate) Gates(transition notifier.EventType) bool {
+	return g.transitions[transition]
+}
+
+// Review returns the change request for the transition given the latest one of the workload, submitting the
+// transition if it wasn't submitted yet or looking up the decision on it otherwise.
+func (g *Gate) Review(ctx context.Context, current *v1alpha1.ChangeRequest, request Request,
+	now time.Time) (*v1alpha1.ChangeRequest, error) {
+	if current != nil && current.Policy == request.Policy {
+		expired := !now.Before(current.SubmittedAt.Add(g.timeout))
+		switch current.Status {
+		case v1alpha1.ChangeRequestApproved:
+			if current.EnforcedAt == nil {
+				return current, nil
+			}
+		case v1alpha1.ChangeRequestRejected, v1alpha1.ChangeRequestTimedOut:
+			if !expired {
+				return current, nil
+			}
+		case v1alpha1.ChangeRequestPending:
+			status, err := g.approver.Status(ctx, current.TicketID)
+			if err != nil {
+				changeRequestErrorsCounter.WithLabelValues(request.Namespace).Inc()
+				return nil, err
+			}
+			if status == v1alpha1.ChangeRequestPending && expired {
+				status = v1alpha1.ChangeRequestTimedOut
+			}
+			reviewed := current.DeepCopy()
+			reviewed.Status = status
+			if status != v1alpha1.ChangeRequestPending {
+				changeRequestsCounter.WithLabelValues(request.Namespace, string(status)).Inc()
+			}
+			return reviewed, nil
+		}
+	}
+
+	ticketID, status, err := g.approver.Submit(ctx, request)
+	if err != nil {
+		changeRequestErrorsCounter.WithLabelValues(request.Namespace).Inc()
+		return nil, err
+	}
+	changeRequestsCounter.WithLabelValues(request.Namespace, string(v1alpha1.ChangeRequestPending)).Inc()
+	if status != v1alpha1.ChangeRequestPending {
+		changeRequestsCounter.WithLabelValues(request.Namespace, string(status)).Inc()
+	}
+	return &v1alpha1.ChangeRequest{
+		TicketID:    ticketID,
+		Policy:      request.Policy,
+		Status:      status,
+		SubmittedAt: metav1.NewTime(now),
+	}, nil
+}
@@ -0,0 +1,154 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeApprover decides the change requests with the status of their ticket.
+type fakeApprover struct {
+	submitted []Request
+	statuses  map[string]v1alpha1.ChangeRequestStatus
+}
+
+func (f *fakeApprover) Submit(ctx context.Context, request Request) (string, v1alpha1.ChangeRequestStatus, error) {
+	f.submitted = append(f.submitted, request)
+	ticketID := "CHG" + request.Policy
+	f.statuses[ticketID] = v1alpha1.ChangeRequestPending
+	return ticketID, v1alpha1.ChangeRequestPending, nil
+}
+
+func (f *fakeApprover) Status(ctx context.Context, ticketID string) (v1alpha1.ChangeRequestStatus, error) {
+	return f.statuses[ticketID], nil
+}
+
+var _ = Describe("WebhookApprover", func() {
+	It("should submit the change requests and look up their status", func() {
+		var submitted Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/changes":
+				Expect(json.NewDecoder(r.Body).Decode(&submitted)).To(Succeed())
+				_, _ = w.Write([]byte(`{"ticketId":"CHG0012345","status":"Pending"}`))
+			case r.Method == http.MethodGet && r.URL.Path == "/changes/CHG0012345":
+				_, _ = w.Write([]byte(`{"ticketId":"CHG0012345","status":"Approved"}`))
+			case r.Method == http.MethodGet && r.URL.Path == "/changes/CHG0000000":
+				_, _ = w.Write([]byte(`{"ticketId":"CHG0000000","status":"Closed"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		approver := NewWebhookApprover(server.URL+"/changes", time.Second)
+		ticketID, status, err := approver.Submit(context.TODO(), Request{Transition: notifier.PolicyPromoted,
+			Namespace: "payments", Workload: "checkout", OldPolicy: "safest-policy", Policy: "aggressive-policy"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ticketID).To(Equal("CHG0012345"))
+		Expect(status).To(Equal(v1alpha1.ChangeRequestPending))
+		Expect(submitted.Policy).To(Equal("aggressive-policy"))
+		Expect(submitted.Transition).To(Equal(notifier.PolicyPromoted))
+
+		status, err = approver.Status(context.TODO(), ticketID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(v1alpha1.ChangeRequestApproved))
+
+		_, err = approver.Status(context.TODO(), "CHG0000000")
+		Expect(err).To(MatchError(ContainSubstring("unknown status")))
+		_, err = approver.Status(context.TODO(), "CHG9999999")
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+})
+
+var _ = Describe("Gate", func() {
+	var approver *fakeApprover
+	var gate *Gate
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	request := Request{Transition: notifier.PolicyPromoted, Namespace: "payments", Workload: "checkout",
+		OldPolicy: "safest-policy", Policy: "aggressive-policy"}
+
+	BeforeEach(func() {
+		approver = &fakeApprover{statuses: map[string]v1alpha1.ChangeRequestStatus{}}
+		gate = NewGate(approver, time.Hour)
+	})
+
+	It("should only gate the promotions by default and never the rollbacks", func() {
+		Expect(gate.Gates(notifier.PolicyPromoted)).To(BeTrue())
+		Expect(gate.Gates(notifier.PolicyDemoted)).To(BeFalse())
+		gate = NewGate(approver, time.Hour, notifier.PolicyPromoted, notifier.PolicyDemoted, notifier.PolicyRolledBack)
+		Expect(gate.Gates(notifier.PolicyDemoted)).To(BeTrue())
+		Expect(gate.Gates(notifier.PolicyRolledBack)).To(BeFalse())
+	})
+
+	It("should submit the transition once and wait for its approval", func() {
+		changeRequest, err := gate.Review(context.TODO(), nil, request, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*changeRequest).To(Equal(v1alpha1.ChangeRequest{TicketID: "CHGaggressive-policy", Policy: "aggressive-policy",
+			Status: v1alpha1.ChangeRequestPending, SubmittedAt: metav1.NewTime(now)}))
+
+		changeRequest, err = gate.Review(context.TODO(), changeRequest, request, now.Add(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest.Status).To(Equal(v1alpha1.ChangeRequestPending))
+
+		approver.statuses["CHGaggressive-policy"] = v1alpha1.ChangeRequestApproved
+		changeRequest, err = gate.Review(context.TODO(), changeRequest, request, now.Add(2*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest.Status).To(Equal(v1alpha1.ChangeRequestApproved))
+		Expect(changeRequest.SubmittedAt.Time).To(Equal(now))
+		Expect(approver.submitted).To(HaveLen(1))
+	})
+
+	It("should time out the pending transitions and submit them again after the timeout", func() {
+		changeRequest, err := gate.Review(context.TODO(), nil, request, now)
+		Expect(err).NotTo(HaveOccurred())
+		changeRequest, err = gate.Review(context.TODO(), changeRequest, request, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest.Status).To(Equal(v1alpha1.ChangeRequestTimedOut))
+		Expect(approver.submitted).To(HaveLen(1))
+
+		rejected := &v1alpha1.ChangeRequest{TicketID: "CHG1", Policy: "aggressive-policy",
+			Status: v1alpha1.ChangeRequestRejected, SubmittedAt: metav1.NewTime(now)}
+		changeRequest, err = gate.Review(context.TODO(), rejected, request, now.Add(30*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest).To(Equal(rejected))
+		changeRequest, err = gate.Review(context.TODO(), rejected, request, now.Add(2*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest.Status).To(Equal(v1alpha1.ChangeRequestPending))
+		Expect(approver.submitted).To(HaveLen(2))
+	})
+
+	It("should submit the transitions to another policy afresh", func() {
+		approved := &v1alpha1.ChangeRequest{TicketID: "CHG1", Policy: "moderate-policy",
+			Status: v1alpha1.ChangeRequestApproved, SubmittedAt: metav1.NewTime(now)}
+		changeRequest, err := gate.Review(context.TODO(), approved, request, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest.Policy).To(Equal("aggressive-policy"))
+		Expect(changeRequest.Status).To(Equal(v1alpha1.ChangeRequestPending))
+	})
+
+	It("should submit entering a policy again once its approval is enforced", func() {
+		approved := &v1alpha1.ChangeRequest{TicketID: "CHG1", Policy: "aggressive-policy",
+			Status: v1alpha1.ChangeRequestApproved, SubmittedAt: metav1.NewTime(now)}
+		changeRequest, err := gate.Review(context.TODO(), approved, request, now.Add(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest).To(Equal(approved))
+		Expect(approver.submitted).To(BeEmpty())
+
+		enforcedAt := metav1.NewTime(now.Add(time.Minute))
+		approved.EnforcedAt = &enforcedAt
+		changeRequest, err = gate.Review(context.TODO(), approved, request, now.Add(2*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeRequest.Status).To(Equal(v1alpha1.ChangeRequestPending))
+		Expect(changeRequest.EnforcedAt).To(BeNil())
+		Expect(approver.submitted).To(HaveLen(1))
+	})
+})
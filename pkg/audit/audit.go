@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	auditSinkErrorsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "audit_sink_errors_count",
+			Help: "Number of audit records which couldn't be written to a sink"}, []string{"sink", "action"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(auditSinkErrorsCounter)
+}
+
+// Action is a mutation performed by ottoscalr.
+type Action string
+
+const (
+	// RecommendationApplied is recorded when the HPA configuration to be enforced on a workload changes.
+	RecommendationApplied Action = "RecommendationApplied"
+	// AutoscalerEnforced is recorded when an autoscaler is created or updated for a workload.
+	AutoscalerEnforced Action = "AutoscalerEnforced"
+	// AutoscalerDeleted is recorded when an autoscaler managed by ottoscalr is deleted for a workload.
+	AutoscalerDeleted Action = "AutoscalerDeleted"
+)
+
+// Record is a single entry of the audit log.
+type Record struct {
+	Timestamp               time.Time                  `json:"timestamp"`
+	Action                  Action                     `json:"action"`
+	Controller              string                     `json:"controller"`
+	Namespace               string                     `json:"namespace"`
+	WorkloadKind            string                     `json:"workloadKind"`
+	Workload                string                     `json:"workload"`
+	OldConfig               *v1alpha1.HPAConfiguration `json:"oldConfig,omitempty"`
+	NewConfig               *v1alpha1.HPAConfiguration `json:"newConfig,omitempty"`
+	OldPolicy               string                     `json:"oldPolicy,omitempty"`
+	Policy                  string                     `json:"policy,omitempty"`
+	ProjectedSavingsPercent *int                       `json:"projectedSavingsPercent,omitempty"`
+	Reason                  string                     `json:"reason,omitempty"`
+	Result                  string                     `json:"result,omitempty"`
+	// ChangeTicketID is the ticket of the change management system which approved the policy transition.
+	ChangeTicketID string `json:"changeTicketId,omitempty"`
+}
+
+// Sink persists the audit records.
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+	GetName() string
+}
+
+// Auditor records the mutations performed by the controllers.
+type Auditor interface {
+	Audit(ctx context.Context, record Record)
+}
+
+// SinkAuditor writes every audit record to all of its sinks. Failing to write a record is logged and
+// doesn't fail the mutation being audited.
+type SinkAuditor struct {
+	sinks  []Sink
+	logger logr.Logger
+}
+
+func NewAuditor(logger logr.Logger, sinks ...Sink) *SinkAuditor {
+	return &SinkAuditor{
+		sinks:  sinks,
+		logger: logger.WithName("Auditor"),
+	}
+}
+
+func (a *SinkAuditor) Audit(ctx context.Context, record Record) {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	for _, sink := range a.sinks {
+		if err := sink.Write(ctx, record); err != nil {
+			a.logger.Error(err, "Error writing the audit record.", "sink", sink.GetName(), "record", record)
+			auditSinkErrorsCounter.WithLabelValues(sink.GetName(), string(record.Action)).Inc()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type failingSink struct{}
+
+func (f failingSink) Write(_ context.Context, _ Record) error {
+	return errors.New("sink unavailable")
+}
+
+func (f failingSink) GetName() string {
+	return "failing"
+}
+
+var _ = Describe("Auditor", func() {
+	var record Record
+
+	BeforeEach(func() {
+		record = Record{
+			Action:       RecommendationApplied,
+			Controller:   "RecoWorkflowController",
+			Namespace:    "default",
+			WorkloadKind: "Deployment",
+			Workload:     "test-app",
+			OldConfig:    &v1alpha1.HPAConfiguration{Min: 10, Max: 10, TargetMetricValue: 10},
+			NewConfig:    &v1alpha1.HPAConfiguration{Min: 5, Max: 10, TargetMetricValue: 40},
+			Policy:       "policy-1",
+			Reason:       "BreachDetected",
+		}
+	})
+
+	It("should write the records as JSON lines to the writer sink", func() {
+		buf := &bytes.Buffer{}
+		auditor := NewAuditor(logr.Discard(), NewWriterSink("buffer", buf))
+		auditor.Audit(context.TODO(), record)
+		auditor.Audit(context.TODO(), record)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		written := Record{}
+		Expect(json.Unmarshal([]byte(lines[0]), &written)).To(Succeed())
+		Expect(written.Timestamp.IsZero()).To(BeFalse())
+		Expect(written.Action).To(Equal(RecommendationApplied))
+		Expect(written.OldConfig.DeepEquals(*record.OldConfig)).To(BeTrue())
+		Expect(written.NewConfig.DeepEquals(*record.NewConfig)).To(BeTrue())
+		Expect(written.Policy).To(Equal("policy-1"))
+	})
+
+	It("should append the records to the file sink", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		Expect(os.WriteFile(path, []byte("{}\n"), 0644)).To(Succeed())
+
+		fileSink, err := NewFileSink(path)
+		Expect(err).NotTo(HaveOccurred())
+		NewAuditor(logr.Discard(), fileSink).Audit(context.TODO(), record)
+
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(Equal("{}"))
+		Expect(lines[1]).To(ContainSubstring(`"workload":"test-app"`))
+	})
+
+	It("should close the file sink and fail the records written after", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		fileSink, err := NewFileSink(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileSink.Write(context.TODO(), record)).To(Succeed())
+		Expect(fileSink.Close()).To(Succeed())
+
+		Expect(fileSink.Write(context.TODO(), record)).To(MatchError(os.ErrClosed))
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Split(strings.TrimSpace(string(content)), "\n")).To(HaveLen(1))
+	})
+
+	It("should post the records to the webhook sink", func() {
+		received := make(chan Record, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+			posted := Record{}
+			Expect(json.NewDecoder(r.Body).Decode(&posted)).To(Succeed())
+			received <- posted
+		}))
+		defer server.Close()
+
+		Expect(NewWebhookSink(server.URL, time.Second).Write(context.TODO(), record)).To(Succeed())
+		Eventually(received).Should(Receive(HaveField("Workload", "test-app")))
+	})
+
+	It("should fail the webhook sink on non 2xx responses", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		Expect(NewWebhookSink(server.URL, time.Second).Write(context.TODO(), record)).NotTo(Succeed())
+	})
+
+	It("should write to the remaining sinks when a sink fails", func() {
+		buf := &bytes.Buffer{}
+		auditor := NewAuditor(logr.Discard(), failingSink{}, NewWriterSink("buffer", buf))
+		auditor.Audit(context.TODO(), record)
+		Expect(buf.String()).To(ContainSubstring(`"action":"RecommendationApplied"`))
+	})
+})
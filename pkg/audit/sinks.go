@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// WriterSink appends the audit records as JSON lines to a writer, e.g. stdout or a file.
+type WriterSink struct {
+	name    string
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{
+		name:    name,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// NewStdoutSink returns a sink which writes the audit records to stdout.
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink("stdout", os.Stdout)
+}
+
+// FileSink appends the audit records as JSON lines to a file.
+type FileSink struct {
+	*WriterSink
+	file *os.File
+}
+
+// NewFileSink returns a sink which appends the audit records to the file at path, creating it if required.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{
+		WriterSink: NewWriterSink("file", file),
+		file:       file,
+	}, nil
+}
+
+// Close syncs the audit records written to the disk and closes the file. The records written after fail with
+// os.ErrClosed.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+func (s *WriterSink) Write(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(record)
+}
+
+func (s *WriterSink) GetName() string {
+	return s.name
+}
+
+// WebhookSink POSTs every audit record as JSON to an HTTP endpoint.
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Write(ctx context.Context, record Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook %s responded with status code %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *WebhookSink) GetName() string {
+	return "webhook"
+}
@@ -11,12 +11,23 @@ var (
 	trueBool  = true
 )
 
+// CronTrigger keeps the workload at DesiredReplicas or more between the cron expressions Start and End in the Timezone.
+type CronTrigger struct {
+	Start           string
+	End             string
+	Timezone        string
+	DesiredReplicas int32
+}
+
 type AutoscalerClient interface {
-	CreateOrUpdateAutoscaler(ctx context.Context, workload client.Object, labels map[string]string, max int32, min int32, targetCPUUtilization int32) (string, error)
+	// CreateOrUpdateAutoscaler scales the workload on the metric target along with the cron triggers. Only the
+	// ScaledObjects support cron triggers, the HPAs ignore them.
+	CreateOrUpdateAutoscaler(ctx context.Context, workload client.Object, labels map[string]string, max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (string, error)
 	DeleteAutoscaler(ctx context.Context, obj client.Object) error
 	GetType() client.Object
 	GetList(ctx context.Context, labelSelector labels.Selector, namespace string, fieldSelector fields.Selector) ([]client.Object, error)
 	GetMaxReplicaCount(obj client.Object) int32
+	GetMinReplicaCount(obj client.Object) int32
 	GetScaleTargetName(obj client.Object) string
 	GetName() string
 }
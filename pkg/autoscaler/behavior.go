@@ -0,0 +1,200 @@
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BehaviorMergeStrategy decides how the scaling behavior of an HPA already scaling a workload, e.g. its tuned scale up
+// policies, is carried into the ScaledObject adopting the workload.
+type BehaviorMergeStrategy string
+
+const (
+	// IgnoreBehavior drops the behavior of the HPA.
+	IgnoreBehavior BehaviorMergeStrategy = "Ignore"
+	// ReplaceBehavior carries the behavior of the HPA as is, replacing the behavior carried into the ScaledObject so far.
+	ReplaceBehavior BehaviorMergeStrategy = "Replace"
+	// MergeBehavior carries the scaling rules of the HPA only for the directions the ScaledObject has no rules for.
+	MergeBehavior BehaviorMergeStrategy = "Merge"
+)
+
+// BehaviorMergeStrategyAnnotation overrides the behavior merge strategy of the ScaledObjects in the annotated namespace.
+const BehaviorMergeStrategyAnnotation = "ottoscalr.io/hpa-behavior-merge-strategy"
+
+// ParseBehaviorMergeStrategy returns the behavior merge strategy named by s, IgnoreBehavior if it's empty.
+func ParseBehaviorMergeStrategy(s string) (BehaviorMergeStrategy, error) {
+	switch strategy := BehaviorMergeStrategy(s); strategy {
+	case "":
+		return IgnoreBehavior, nil
+	case IgnoreBehavior, ReplaceBehavior, MergeBehavior:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown HPA behavior merge strategy %q, expected one of %s, %s or %s", s,
+		IgnoreBehavior, ReplaceBehavior, MergeBehavior)
+}
+
+// behaviorMergeStrategy returns the behavior merge strategy of the namespace, which is the default strategy unless the
+// namespace is annotated with BehaviorMergeStrategyAnnotation.
+func (soc *ScaledobjectClient) behaviorMergeStrategy(ctx context.Context, namespace string) (BehaviorMergeStrategy, error) {
+	ns := &corev1.Namespace{}
+	if err := soc.k8sClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+		return soc.behaviorMergeStrategyDefault, nil
+	}
+	annotation, ok := ns.GetAnnotations()[BehaviorMergeStrategyAnnotation]
+	if !ok {
+		return soc.behaviorMergeStrategyDefault, nil
+	}
+	return ParseBehaviorMergeStrategy(annotation)
+}
+
+// FindAdoptedHPA returns the HPA scaling the workload which is neither created by ottoscalr, i.e. labelled with the
+// labels, nor by KEDA for a ScaledObject, i.e. the HPA of its owners a ScaledObject adopts the workload from. It returns
+// nil if there's no such HPA.
+func FindAdoptedHPA(ctx context.Context, k8sClient client.Client, workload client.Object,
+	labels map[string]string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := k8sClient.List(ctx, hpas, client.InNamespace(workload.GetNamespace())); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	kind := workload.GetObjectKind().GroupVersionKind().Kind
+	for i, hpa := range hpas.Items {
+		targetRef := hpa.Spec.ScaleTargetRef
+		if targetRef.Name != workload.GetName() || (kind != "" && targetRef.Kind != kind) ||
+			hasLabels(hpa.GetLabels(), labels) || isOwnedByScaledObject(hpa) {
+			continue
+		}
+		return &hpas.Items[i], nil
+	}
+	return nil, nil
+}
+
+// adoptedHPABehavior returns the behavior of the HPA the ScaledObject adopts the workload from, nil if there's none.
+func (soc *ScaledobjectClient) adoptedHPABehavior(ctx context.Context, workload client.Object,
+	labels map[string]string) (*autoscalingv2beta2.HorizontalPodAutoscalerBehavior, error) {
+	hpa, err := FindAdoptedHPA(ctx, soc.k8sClient, workload, labels)
+	if err != nil || hpa == nil {
+		return nil, err
+	}
+	return toV2beta2Behavior(hpa.Spec.Behavior), nil
+}
+
+func hasLabels(objectLabels, labels map[string]string) bool {
+	if len(labels) == 0 {
+		return false
+	}
+	for key, value := range labels {
+		if objectLabels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func isOwnedByScaledObject(hpa autoscalingv2.HorizontalPodAutoscaler) bool {
+	for _, ownerRef := range hpa.GetOwnerReferences() {
+		if ownerRef.Kind == "ScaledObject" && ownerRef.APIVersion == kedaapi.SchemeGroupVersion.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBehavior returns the behavior of the ScaledObject carrying the behavior of the adopted HPA into its current
+// behavior with the strategy. The current behavior is kept once the HPA is gone, unless the behavior is ignored.
+func mergeBehavior(strategy BehaviorMergeStrategy, hpaBehavior,
+	current *autoscalingv2beta2.HorizontalPodAutoscalerBehavior) *autoscalingv2beta2.HorizontalPodAutoscalerBehavior {
+	switch strategy {
+	case ReplaceBehavior:
+		if hpaBehavior != nil {
+			return hpaBehavior
+		}
+		return current
+	case MergeBehavior:
+		if current == nil {
+			return hpaBehavior
+		}
+		if hpaBehavior == nil {
+			return current
+		}
+		merged := current.DeepCopy()
+		if merged.ScaleUp == nil {
+			merged.ScaleUp = hpaBehavior.ScaleUp
+		}
+		if merged.ScaleDown == nil {
+			merged.ScaleDown = hpaBehavior.ScaleDown
+		}
+		return merged
+	}
+	return nil
+}
+
+// advancedConfig returns the advanced config of a ScaledObject with the behavior, keeping the rest of its current
+// advanced config. It's nil if there's neither.
+func advancedConfig(current *kedaapi.AdvancedConfig,
+	behavior *autoscalingv2beta2.HorizontalPodAutoscalerBehavior) *kedaapi.AdvancedConfig {
+	if current == nil {
+		if behavior == nil {
+			return nil
+		}
+		current = &kedaapi.AdvancedConfig{}
+	}
+	advanced := current.DeepCopy()
+	if advanced.HorizontalPodAutoscalerConfig == nil {
+		if behavior == nil {
+			return advanced
+		}
+		advanced.HorizontalPodAutoscalerConfig = &kedaapi.HorizontalPodAutoscalerConfig{}
+	}
+	advanced.HorizontalPodAutoscalerConfig.Behavior = behavior
+	return advanced
+}
+
+func currentBehavior(spec kedaapi.ScaledObjectSpec) *autoscalingv2beta2.HorizontalPodAutoscalerBehavior {
+	if spec.Advanced == nil || spec.Advanced.HorizontalPodAutoscalerConfig == nil {
+		return nil
+	}
+	return spec.Advanced.HorizontalPodAutoscalerConfig.Behavior
+}
+
+// toV2beta2Behavior converts the behavior of an autoscaling/v2 HPA to the autoscaling/v2beta2 behavior of the
+// ScaledObjects.
+func toV2beta2Behavior(behavior *autoscalingv2.HorizontalPodAutoscalerBehavior) *autoscalingv2beta2.HorizontalPodAutoscalerBehavior {
+	if behavior == nil {
+		return nil
+	}
+	return &autoscalingv2beta2.HorizontalPodAutoscalerBehavior{
+		ScaleUp:   toV2beta2ScalingRules(behavior.ScaleUp),
+		ScaleDown: toV2beta2ScalingRules(behavior.ScaleDown),
+	}
+}
+
+func toV2beta2ScalingRules(rules *autoscalingv2.HPAScalingRules) *autoscalingv2beta2.HPAScalingRules {
+	if rules == nil {
+		return nil
+	}
+	converted := &autoscalingv2beta2.HPAScalingRules{
+		StabilizationWindowSeconds: rules.StabilizationWindowSeconds,
+	}
+	if rules.SelectPolicy != nil {
+		selectPolicy := autoscalingv2beta2.ScalingPolicySelect(*rules.SelectPolicy)
+		converted.SelectPolicy = &selectPolicy
+	}
+	for _, policy := range rules.Policies {
+		converted.Policies = append(converted.Policies, autoscalingv2beta2.HPAScalingPolicy{
+			Type:          autoscalingv2beta2.HPAScalingPolicyType(policy.Type),
+			Value:         policy.Value,
+			PeriodSeconds: policy.PeriodSeconds,
+		})
+	}
+	return converted
+}
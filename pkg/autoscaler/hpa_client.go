@@ -2,6 +2,8 @@ package autoscaler
 
 import (
 	"context"
+	"fmt"
+
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -26,6 +28,16 @@ func (hc *HPAClient) GetMaxReplicaCount(obj client.Object) int32 {
 	return maxPods
 }
 
+func (hc *HPAClient) GetMinReplicaCount(obj client.Object) int32 {
+	hpa := obj.(*autoscalingv1.HorizontalPodAutoscaler)
+	// the min replicas of an HPA default to 1
+	minPods := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minPods = *hpa.Spec.MinReplicas
+	}
+	return minPods
+}
+
 func (hc *HPAClient) GetName() string {
 	return "HPA"
 }
@@ -42,8 +54,8 @@ func (hc *HPAClient) GetList(ctx context.Context, labelSelector labels.Selector,
 
 	var result []client.Object
 
-	for _, hpa := range hpas.Items {
-		result = append(result, &hpa)
+	for i := range hpas.Items {
+		result = append(result, &hpas.Items[i])
 	}
 
 	return result, nil
@@ -71,7 +83,11 @@ func (hc *HPAClient) GetScaleTargetName(obj client.Object) string {
 }
 
 func (hc *HPAClient) CreateOrUpdateAutoscaler(ctx context.Context, workload client.Object, labels map[string]string,
-	max int32, min int32, targetCPUUtilization int32) (string, error) {
+	max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (string, error) {
+	if !target.isCPUUtilization() {
+		return "", fmt.Errorf("autoscaling/v1 HPA only supports cpu Utilization targets, got %s %s", target.GetName(), target.GetType())
+	}
+	targetCPUUtilization := target.Value
 	hpa := autoscalingv1.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workload.GetName(),
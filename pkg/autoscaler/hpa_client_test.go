@@ -115,7 +115,7 @@ var _ = Describe("HPAClient", func() {
 			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, deployment)
 			Expect(err).ToNot(HaveOccurred())
 			op, err := hpaClient.CreateOrUpdateAutoscaler(ctx, deployment,
-				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), *int32Ptr(4))
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(4), nil)
 
 			Expect(err).ToNot(HaveOccurred())
 			time.Sleep(2 * time.Second)
@@ -136,7 +136,7 @@ var _ = Describe("HPAClient", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			op, err := hpaClient.CreateOrUpdateAutoscaler(ctx, deployment,
-				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), *int32Ptr(4))
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(4), nil)
 			Expect(err).ToNot(HaveOccurred())
 			time.Sleep(2 * time.Second)
 			Expect(op).To(Equal("created"))
@@ -149,7 +149,7 @@ var _ = Describe("HPAClient", func() {
 			Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal(deploymentName))
 
 			op, err = hpaClient.CreateOrUpdateAutoscaler(ctx, deployment,
-				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(8), *int32Ptr(5), *int32Ptr(10))
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(8), *int32Ptr(5), CPUUtilizationTarget(10), nil)
 			Expect(err).ToNot(HaveOccurred())
 			time.Sleep(2 * time.Second)
 			Expect(op).To(Equal("updated"))
@@ -27,6 +27,16 @@ func (hc *HPAClientV2) GetMaxReplicaCount(obj client.Object) int32 {
 	return maxPods
 }
 
+func (hc *HPAClientV2) GetMinReplicaCount(obj client.Object) int32 {
+	hpa := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	// the min replicas of an HPA default to 1
+	minPods := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minPods = *hpa.Spec.MinReplicas
+	}
+	return minPods
+}
+
 func (hc *HPAClientV2) GetName() string {
 	return "HPA"
 }
@@ -43,8 +53,8 @@ func (hc *HPAClientV2) GetList(ctx context.Context, labelSelector labels.Selecto
 
 	var result []client.Object
 
-	for _, hpa := range hpas.Items {
-		result = append(result, &hpa)
+	for i := range hpas.Items {
+		result = append(result, &hpas.Items[i])
 	}
 
 	return result, nil
@@ -72,7 +82,11 @@ func (hc *HPAClientV2) GetScaleTargetName(obj client.Object) string {
 }
 
 func (hc *HPAClientV2) CreateOrUpdateAutoscaler(ctx context.Context, workload client.Object, labels map[string]string,
-	max int32, min int32, targetCPUUtilization int32) (string, error) {
+	max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (string, error) {
+	metricSpec, err := target.toMetricSpec()
+	if err != nil {
+		return "", err
+	}
 	hpa := autoscalingv2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workload.GetName(),
@@ -87,18 +101,7 @@ func (hc *HPAClientV2) CreateOrUpdateAutoscaler(ctx context.Context, workload cl
 			},
 			MinReplicas: &min,
 			MaxReplicas: max,
-			Metrics: []autoscalingv2.MetricSpec{
-				{
-					Type: "Resource",
-					Resource: &autoscalingv2.ResourceMetricSource{
-						Name: "cpu",
-						Target: autoscalingv2.MetricTarget{
-							Type:               "Utilization",
-							AverageUtilization: &targetCPUUtilization, // Target CPU utilization percentage
-						},
-					},
-				},
-			},
+			Metrics:     []autoscalingv2.MetricSpec{metricSpec},
 		},
 	}
 
@@ -111,18 +114,7 @@ func (hc *HPAClientV2) CreateOrUpdateAutoscaler(ctx context.Context, workload cl
 			},
 			MinReplicas: &min,
 			MaxReplicas: max,
-			Metrics: []autoscalingv2.MetricSpec{
-				{
-					Type: "Resource",
-					Resource: &autoscalingv2.ResourceMetricSource{
-						Name: "cpu",
-						Target: autoscalingv2.MetricTarget{
-							Type:               "Utilization",
-							AverageUtilization: &targetCPUUtilization, // Target CPU utilization percentage
-						},
-					},
-				},
-			},
+			Metrics:     []autoscalingv2.MetricSpec{metricSpec},
 		}
 		return nil
 	})
@@ -0,0 +1,76 @@
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+const (
+	// KafkaLagMetricName is the metric of the targets scaling kafka consumers on the lag of their consumer group. The
+	// AverageValue of the target is the lag per replica, i.e. the lagThreshold of the KEDA kafka trigger.
+	KafkaLagMetricName = "kafka"
+
+	// KafkaConsumerGroupAnnotation marks a workload as a kafka consumer, which is recommended on the lag of the
+	// consumer group rather than its cpu utilization.
+	KafkaConsumerGroupAnnotation = "ottoscalr.io/kafka-consumer-group"
+	// KafkaTopicAnnotation is the topic the kafka consumer consumes.
+	KafkaTopicAnnotation = "ottoscalr.io/kafka-topic"
+	// KafkaBootstrapServersAnnotation is the comma separated bootstrap servers of the kafka cluster of the topic,
+	// required to enforce the kafka triggers.
+	KafkaBootstrapServersAnnotation = "ottoscalr.io/kafka-bootstrap-servers"
+	// KafkaTargetLagAnnotation overrides the lag of the consumer group the kafka consumer is recommended to stay under.
+	KafkaTargetLagAnnotation = "ottoscalr.io/kafka-target-lag"
+)
+
+// KafkaTrigger identifies the consumer group a kafka consumer is scaled on.
+type KafkaTrigger struct {
+	BootstrapServers string
+	ConsumerGroup    string
+	Topic            string
+	// TargetLag is the lag the consumer group is recommended to stay under, 0 unless overridden.
+	TargetLag int
+}
+
+// KafkaTriggerFromAnnotations returns the kafka trigger of a workload annotated as a kafka consumer, nil if it isn't
+// one. It fails if the consumer is annotated without its topic or with an invalid target lag.
+func KafkaTriggerFromAnnotations(annotations map[string]string) (*KafkaTrigger, error) {
+	consumerGroup, ok := annotations[KafkaConsumerGroupAnnotation]
+	if !ok || consumerGroup == "" {
+		return nil, nil
+	}
+	trigger := &KafkaTrigger{
+		BootstrapServers: annotations[KafkaBootstrapServersAnnotation],
+		ConsumerGroup:    consumerGroup,
+		Topic:            annotations[KafkaTopicAnnotation],
+	}
+	if trigger.Topic == "" {
+		return nil, fmt.Errorf("kafka consumer group %s is annotated without the %s annotation", consumerGroup, KafkaTopicAnnotation)
+	}
+	if targetLag, ok := annotations[KafkaTargetLagAnnotation]; ok {
+		lag, err := strconv.Atoi(targetLag)
+		if err != nil || lag <= 0 {
+			return nil, fmt.Errorf("invalid %s annotation %q, expected a positive number of messages", KafkaTargetLagAnnotation, targetLag)
+		}
+		trigger.TargetLag = lag
+	}
+	return trigger, nil
+}
+
+func (m MetricTarget) isKafkaLag() bool {
+	return m.GetName() == KafkaLagMetricName
+}
+
+// kafkaScaleTrigger returns the KEDA kafka trigger scaling the consumer group on the lag per replica of the target.
+func kafkaScaleTrigger(target MetricTarget, trigger KafkaTrigger) kedaapi.ScaleTriggers {
+	return kedaapi.ScaleTriggers{
+		Type: "kafka",
+		Metadata: map[string]string{
+			"bootstrapServers": trigger.BootstrapServers,
+			"consumerGroup":    trigger.ConsumerGroup,
+			"topic":            trigger.Topic,
+			"lagThreshold":     fmt.Sprint(target.Value),
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package autoscaler
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+var _ = Describe("Kafka triggers", func() {
+	annotations := map[string]string{
+		KafkaConsumerGroupAnnotation:    "orders-consumer",
+		KafkaTopicAnnotation:            "orders",
+		KafkaBootstrapServersAnnotation: "kafka-0:9092,kafka-1:9092",
+	}
+
+	It("should read the kafka trigger of the annotated consumers", func() {
+		trigger, err := KafkaTriggerFromAnnotations(annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trigger).To(Equal(&KafkaTrigger{
+			BootstrapServers: "kafka-0:9092,kafka-1:9092",
+			ConsumerGroup:    "orders-consumer",
+			Topic:            "orders",
+		}))
+
+		trigger, err = KafkaTriggerFromAnnotations(map[string]string{"app": "orders"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trigger).To(BeNil())
+	})
+
+	It("should reject the consumers without a topic or with an invalid target lag", func() {
+		_, err := KafkaTriggerFromAnnotations(map[string]string{KafkaConsumerGroupAnnotation: "orders-consumer"})
+		Expect(err).To(HaveOccurred())
+
+		_, err = KafkaTriggerFromAnnotations(map[string]string{
+			KafkaConsumerGroupAnnotation: "orders-consumer",
+			KafkaTopicAnnotation:         "orders",
+			KafkaTargetLagAnnotation:     "-5",
+		})
+		Expect(err).To(HaveOccurred())
+
+		trigger, err := KafkaTriggerFromAnnotations(map[string]string{
+			KafkaConsumerGroupAnnotation: "orders-consumer",
+			KafkaTopicAnnotation:         "orders",
+			KafkaTargetLagAnnotation:     "5000",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trigger.TargetLag).To(Equal(5000))
+	})
+
+	It("should scale the consumers on the lag per replica", func() {
+		trigger, _ := KafkaTriggerFromAnnotations(annotations)
+		target := MetricTarget{Name: KafkaLagMetricName, Type: AverageValueTargetType, Value: 250, Kafka: trigger}
+		triggers := setScaleTriggers(target, nil)
+		Expect(triggers[0].Type).To(Equal("kafka"))
+		Expect(triggers[0].Metadata).To(Equal(map[string]string{
+			"bootstrapServers": "kafka-0:9092,kafka-1:9092",
+			"consumerGroup":    "orders-consumer",
+			"topic":            "orders",
+			"lagThreshold":     "250",
+		}))
+	})
+
+	It("should need the bootstrap servers and an AverageValue target to enforce the kafka lag targets", func() {
+		workload := &appsv1.Deployment{}
+		workload.SetAnnotations(annotations)
+		_, err := kafkaTriggerOf(workload, MetricTarget{Name: KafkaLagMetricName, Type: AverageValueTargetType, Value: 250})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = kafkaTriggerOf(workload, MetricTarget{Name: KafkaLagMetricName, Type: ValueTargetType, Value: 250})
+		Expect(err).To(HaveOccurred())
+
+		workload.SetAnnotations(map[string]string{
+			KafkaConsumerGroupAnnotation: "orders-consumer",
+			KafkaTopicAnnotation:         "orders",
+		})
+		_, err = kafkaTriggerOf(workload, MetricTarget{Name: KafkaLagMetricName, Type: AverageValueTargetType, Value: 250})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not express the kafka lag targets as HPA metrics", func() {
+		_, err := MetricTarget{Name: KafkaLagMetricName, Type: AverageValueTargetType, Value: 250}.toMetricSpec()
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,71 @@
+package autoscaler
+
+import (
+	"fmt"
+	"strings"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+// ManagedFieldsAnnotation lists the fields of a ScaledObject ottoscalr manages, comma separated, e.g.
+// "minReplicaCount,maxReplicaCount" to leave the triggers and the behavior of an adopted ScaledObject to its owners.
+// ottoscalr manages all of them if the ScaledObject isn't annotated. The rest of the ScaledObject is never changed.
+const ManagedFieldsAnnotation = "ottoscalr.io/managed-fields"
+
+const (
+	MinReplicaCountField = "minReplicaCount"
+	MaxReplicaCountField = "maxReplicaCount"
+	// TriggersField manages the triggers of the types ottoscalr creates. The other triggers, e.g. the prometheus or
+	// the external triggers added by the owners of the ScaledObject, are kept.
+	TriggersField = "triggers"
+	BehaviorField = "behavior"
+	// TemplateField manages the fields of the ScaledObjectTemplate, e.g. the polling interval.
+	TemplateField = "template"
+)
+
+// managedTriggerTypes are the types of the triggers ottoscalr creates.
+var managedTriggerTypes = map[string]bool{
+	"cpu":                   true,
+	"memory":                true,
+	"kafka":                 true,
+	SQSQueueMetricName:      true,
+	RabbitMQQueueMetricName: true,
+	"cron":                  true,
+	"scheduled-event":       true,
+}
+
+type managedFields map[string]bool
+
+// managedFieldsOf returns the fields of the ScaledObject ottoscalr manages.
+func managedFieldsOf(scaledObject *kedaapi.ScaledObject) (managedFields, error) {
+	annotation, ok := scaledObject.GetAnnotations()[ManagedFieldsAnnotation]
+	if !ok {
+		return managedFields{MinReplicaCountField: true, MaxReplicaCountField: true, TriggersField: true,
+			BehaviorField: true, TemplateField: true}, nil
+	}
+	fields := managedFields{}
+	for _, field := range strings.Split(annotation, ",") {
+		switch field = strings.TrimSpace(field); field {
+		case "":
+		case MinReplicaCountField, MaxReplicaCountField, TriggersField, BehaviorField, TemplateField:
+			fields[field] = true
+		default:
+			return nil, fmt.Errorf("unknown field %q in the %s annotation of the ScaledObject %s/%s, expected %s, %s, %s, %s or %s",
+				field, ManagedFieldsAnnotation, scaledObject.Namespace, scaledObject.Name, MinReplicaCountField,
+				MaxReplicaCountField, TriggersField, BehaviorField, TemplateField)
+		}
+	}
+	return fields, nil
+}
+
+// mergeScaleTriggers returns the triggers of ottoscalr followed by the current triggers of the types ottoscalr doesn't
+// create, in their order.
+func mergeScaleTriggers(current, triggers []kedaapi.ScaleTriggers) []kedaapi.ScaleTriggers {
+	merged := append([]kedaapi.ScaleTriggers{}, triggers...)
+	for _, trigger := range current {
+		if !managedTriggerTypes[trigger.Type] {
+			merged = append(merged, trigger)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,68 @@
+package autoscaler
+
+import (
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ManagedFields", func() {
+	It("should manage all the fields of the ScaledObjects without the annotation", func() {
+		fields, err := managedFieldsOf(&kedaapi.ScaledObject{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fields).To(HaveLen(5))
+
+		fields, err = managedFieldsOf(&kedaapi.ScaledObject{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{ManagedFieldsAnnotation: "minReplicaCount, maxReplicaCount"}}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fields).To(Equal(managedFields{MinReplicaCountField: true, MaxReplicaCountField: true}))
+
+		_, err = managedFieldsOf(&kedaapi.ScaledObject{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{ManagedFieldsAnnotation: "replicas"}}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should replace only the triggers of the types ottoscalr creates", func() {
+		external := kedaapi.ScaleTriggers{Type: "external", Metadata: map[string]string{"scalerAddress": "scaler:9090"}}
+		merged := mergeScaleTriggers([]kedaapi.ScaleTriggers{
+			{Type: "cpu", Metadata: map[string]string{"type": "Utilization", "value": "50"}},
+			external,
+			{Type: "cron", Metadata: map[string]string{"start": "0 9 * * *"}},
+		}, []kedaapi.ScaleTriggers{{Type: "memory", Metadata: map[string]string{"type": "Utilization", "value": "70"}}})
+		Expect(merged).To(HaveLen(2))
+		Expect(merged[0].Type).To(Equal("memory"))
+		Expect(merged[1]).To(Equal(external))
+	})
+
+	It("should template the idle replica count below the min replicas and the fallback without resource triggers", func() {
+		template := ScaledObjectTemplate{IdleReplicaCount: int32Ptr(2),
+			Fallback: &ScaledObjectFallback{FailureThreshold: 3, Replicas: 8}}.
+			WithDefaults(ScaledObjectTemplate{IdleReplicaCount: int32Ptr(0), CooldownPeriod: int32Ptr(120)})
+		Expect(template.CooldownPeriod).To(Equal(int32Ptr(120)))
+
+		spec := kedaapi.ScaledObjectSpec{MinReplicaCount: int32Ptr(2), Triggers: []kedaapi.ScaleTriggers{{Type: "kafka"}}}
+		applyTemplate(&spec, template)
+		Expect(spec.IdleReplicaCount).To(BeNil())
+		Expect(spec.CooldownPeriod).To(Equal(int32Ptr(120)))
+		Expect(spec.Fallback).To(Equal(&kedaapi.Fallback{FailureThreshold: 3, Replicas: 8}))
+
+		spec = kedaapi.ScaledObjectSpec{MinReplicaCount: int32Ptr(3),
+			Triggers: []kedaapi.ScaleTriggers{{Type: "kafka"}, {Type: "memory"}}}
+		applyTemplate(&spec, template)
+		Expect(spec.IdleReplicaCount).To(Equal(int32Ptr(2)))
+		Expect(spec.Fallback).To(BeNil())
+	})
+
+	It("should keep the rest of the advanced config along with the behavior", func() {
+		restore := kedaapi.AdvancedConfig{RestoreToOriginalReplicaCount: true}
+		behavior := &autoscalingv2beta2.HorizontalPodAutoscalerBehavior{
+			ScaleDown: &autoscalingv2beta2.HPAScalingRules{StabilizationWindowSeconds: int32Ptr(600)}}
+		advanced := advancedConfig(&restore, behavior)
+		Expect(advanced.RestoreToOriginalReplicaCount).To(BeTrue())
+		Expect(advanced.HorizontalPodAutoscalerConfig.Behavior).To(Equal(behavior))
+		Expect(restore.HorizontalPodAutoscalerConfig).To(BeNil())
+		Expect(advancedConfig(nil, nil)).To(BeNil())
+	})
+})
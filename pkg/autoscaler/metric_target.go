@@ -0,0 +1,124 @@
+package autoscaler
+
+import (
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	UtilizationTargetType  = "Utilization"
+	AverageValueTargetType = "AverageValue"
+	ValueTargetType        = "Value"
+
+	cpuMetricName = "cpu"
+)
+
+// MetricTarget is the metric an autoscaler scales the workload on along with the target for it.
+type MetricTarget struct {
+	// Name is the name of the metric. Defaults to cpu.
+	Name string
+	// Type is one of Utilization, AverageValue or Value. Defaults to Utilization.
+	Type string
+	// Value is the utilization percentage for Utilization targets and the absolute value otherwise.
+	Value int32
+	// Kafka is the consumer group of the kafka lag targets, resolved from the annotations of the workload.
+	Kafka *KafkaTrigger
+	// Queue is the queue of the queue depth targets, resolved from the annotations of the workload.
+	Queue *QueueTrigger
+}
+
+// CPUUtilizationTarget returns a MetricTarget for the given cpu utilization percentage.
+func CPUUtilizationTarget(targetCPUUtilization int32) MetricTarget {
+	return MetricTarget{
+		Name:  cpuMetricName,
+		Type:  UtilizationTargetType,
+		Value: targetCPUUtilization,
+	}
+}
+
+func (m MetricTarget) GetName() string {
+	if m.Name == "" {
+		return cpuMetricName
+	}
+	return m.Name
+}
+
+func (m MetricTarget) GetType() string {
+	if m.Type == "" {
+		return UtilizationTargetType
+	}
+	return m.Type
+}
+
+func (m MetricTarget) isCPUUtilization() bool {
+	return m.GetName() == cpuMetricName && m.GetType() == UtilizationTargetType
+}
+
+func (m MetricTarget) isResourceMetric() bool {
+	return m.GetName() == string(corev1.ResourceCPU) || m.GetName() == string(corev1.ResourceMemory)
+}
+
+func (m MetricTarget) quantity() *resource.Quantity {
+	return resource.NewQuantity(int64(m.Value), resource.DecimalSI)
+}
+
+// toMetricSpec translates the target into an autoscaling/v2 MetricSpec. cpu and memory are expressed as
+// Resource metrics, AverageValue targets on any other metric as Pods metrics and Value targets as External metrics.
+func (m MetricTarget) toMetricSpec() (autoscalingv2.MetricSpec, error) {
+	if m.isKafkaLag() {
+		return autoscalingv2.MetricSpec{}, fmt.Errorf("kafka lag targets are only supported by ScaledObjects")
+	}
+	if m.isQueueDepth() {
+		return autoscalingv2.MetricSpec{}, fmt.Errorf("queue depth targets are only supported by ScaledObjects")
+	}
+	target := autoscalingv2.MetricTarget{
+		Type: autoscalingv2.MetricTargetType(m.GetType()),
+	}
+	switch m.GetType() {
+	case UtilizationTargetType:
+		value := m.Value
+		target.AverageUtilization = &value
+	case AverageValueTargetType:
+		target.AverageValue = m.quantity()
+	case ValueTargetType:
+		target.Value = m.quantity()
+	default:
+		return autoscalingv2.MetricSpec{}, fmt.Errorf("unsupported metric target type %s", m.GetType())
+	}
+
+	if m.isResourceMetric() {
+		if m.GetType() == ValueTargetType {
+			return autoscalingv2.MetricSpec{}, fmt.Errorf("resource metric %s does not support %s targets", m.GetName(), m.GetType())
+		}
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceName(m.GetName()),
+				Target: target,
+			},
+		}, nil
+	}
+
+	switch m.GetType() {
+	case AverageValueTargetType:
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: m.GetName()},
+				Target: target,
+			},
+		}, nil
+	case ValueTargetType:
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: m.GetName()},
+				Target: target,
+			},
+		}, nil
+	}
+	return autoscalingv2.MetricSpec{}, fmt.Errorf("metric %s does not support %s targets", m.GetName(), m.GetType())
+}
@@ -0,0 +1,43 @@
+package autoscaler
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("MetricTarget", func() {
+	It("should default to a cpu utilization resource metric", func() {
+		spec, err := MetricTarget{Value: 60}.toMetricSpec()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spec.Type).To(Equal(autoscalingv2.ResourceMetricSourceType))
+		Expect(spec.Resource.Name).To(Equal(corev1.ResourceCPU))
+		Expect(spec.Resource.Target.Type).To(Equal(autoscalingv2.UtilizationMetricType))
+		Expect(spec.Resource.Target.AverageUtilization).To(Equal(int32Ptr(60)))
+	})
+
+	It("should express AverageValue targets on custom metrics as pods metrics", func() {
+		spec, err := MetricTarget{Name: "http_requests_per_second", Type: AverageValueTargetType, Value: 100}.toMetricSpec()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spec.Type).To(Equal(autoscalingv2.PodsMetricSourceType))
+		Expect(spec.Pods.Metric.Name).To(Equal("http_requests_per_second"))
+		Expect(spec.Pods.Target.AverageValue.Cmp(resource.MustParse("100"))).To(Equal(0))
+	})
+
+	It("should express Value targets on custom metrics as external metrics", func() {
+		spec, err := MetricTarget{Name: "queue_depth", Type: ValueTargetType, Value: 500}.toMetricSpec()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spec.Type).To(Equal(autoscalingv2.ExternalMetricSourceType))
+		Expect(spec.External.Metric.Name).To(Equal("queue_depth"))
+		Expect(spec.External.Target.Value.Cmp(resource.MustParse("500"))).To(Equal(0))
+	})
+
+	It("should reject unsupported combinations", func() {
+		_, err := MetricTarget{Name: "cpu", Type: ValueTargetType, Value: 1}.toMetricSpec()
+		Expect(err).To(HaveOccurred())
+		_, err = MetricTarget{Name: "queue_depth", Type: UtilizationTargetType, Value: 1}.toMetricSpec()
+		Expect(err).To(HaveOccurred())
+	})
+})
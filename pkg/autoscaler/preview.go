@@ -0,0 +1,121 @@
+package autoscaler
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AutoscalerPreviewer previews the changes an AutoscalerClient would apply to the autoscalers of the workloads.
+type AutoscalerPreviewer interface {
+	// PreviewAutoscaler returns the autoscaler of the workload, or nil if there isn't one, along with what
+	// CreateOrUpdateAutoscaler would leave it at, without changing it.
+	PreviewAutoscaler(ctx context.Context, workload client.Object, labels map[string]string, max int32, min int32,
+		target MetricTarget, cronTriggers []CronTrigger) (client.Object, client.Object, error)
+}
+
+// PreviewPatch returns the JSON merge patch turning the current autoscaler into the desired one, or the whole desired
+// autoscaler if there isn't a current one. It returns an empty patch if the autoscaler doesn't change.
+func PreviewPatch(current, desired client.Object) (string, error) {
+	if current == nil {
+		data, err := json.Marshal(desired)
+		return string(data), err
+	}
+	data, err := client.MergeFrom(current).Data(desired)
+	if err != nil {
+		return "", err
+	}
+	if string(data) == "{}" {
+		return "", nil
+	}
+	return string(data), nil
+}
+
+// previewClient reads through the client it wraps but only records the autoscalers written to it, along with the
+// autoscaler read before, so that the changes of CreateOrUpdateAutoscaler can be previewed with the same code path
+// as they're applied.
+type previewClient struct {
+	client.Client
+	autoscalerType reflect.Type
+	current        client.Object
+	desired        client.Object
+}
+
+func newPreviewClient(k8sClient client.Client, autoscalerType client.Object) *previewClient {
+	return &previewClient{Client: k8sClient, autoscalerType: reflect.TypeOf(autoscalerType)}
+}
+
+func (c *previewClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	if reflect.TypeOf(obj) == c.autoscalerType {
+		c.current = obj.DeepCopyObject().(client.Object)
+	}
+	return nil
+}
+
+func (c *previewClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.desired = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *previewClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.desired = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *previewClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.desired = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *previewClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return nil
+}
+
+// preview returns the autoscaler read and the one written, which is the one read if none was written.
+func (c *previewClient) preview() (client.Object, client.Object) {
+	if c.desired == nil {
+		return c.current, c.current
+	}
+	return c.current, c.desired
+}
+
+func (hc *HPAClient) PreviewAutoscaler(ctx context.Context, workload client.Object, labels map[string]string,
+	max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (client.Object, client.Object, error) {
+	previewer := *hc
+	k8sClient := newPreviewClient(hc.k8sClient, hc.GetType())
+	previewer.k8sClient = k8sClient
+	if _, err := previewer.CreateOrUpdateAutoscaler(ctx, workload, labels, max, min, target, cronTriggers); err != nil {
+		return nil, nil, err
+	}
+	current, desired := k8sClient.preview()
+	return current, desired, nil
+}
+
+func (hc *HPAClientV2) PreviewAutoscaler(ctx context.Context, workload client.Object, labels map[string]string,
+	max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (client.Object, client.Object, error) {
+	previewer := *hc
+	k8sClient := newPreviewClient(hc.k8sClient, hc.GetType())
+	previewer.k8sClient = k8sClient
+	if _, err := previewer.CreateOrUpdateAutoscaler(ctx, workload, labels, max, min, target, cronTriggers); err != nil {
+		return nil, nil, err
+	}
+	current, desired := k8sClient.preview()
+	return current, desired, nil
+}
+
+func (soc *ScaledobjectClient) PreviewAutoscaler(ctx context.Context, workload client.Object, labels map[string]string,
+	max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (client.Object, client.Object, error) {
+	previewer := *soc
+	k8sClient := newPreviewClient(soc.k8sClient, soc.GetType())
+	previewer.k8sClient = k8sClient
+	if _, err := previewer.CreateOrUpdateAutoscaler(ctx, workload, labels, max, min, target, cronTriggers); err != nil {
+		return nil, nil, err
+	}
+	current, desired := k8sClient.preview()
+	return current, desired, nil
+}
@@ -0,0 +1,73 @@
+package autoscaler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("AutoscalerPreviewer", func() {
+	workload := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+	}
+	target := MetricTarget{Name: "cpu", Type: UtilizationTargetType, Value: 60}
+	labels := map[string]string{"created-by": "ottoscalr"}
+
+	newPreviewer := func(objects ...client.Object) (*HPAClientV2, client.Client) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		return NewHPAClientV2(fakeClient), fakeClient
+	}
+
+	It("should preview the patch of the autoscaler without applying it", func() {
+		previewer, fakeClient := newPreviewer()
+		_, err := previewer.CreateOrUpdateAutoscaler(context.TODO(), workload, labels, 10, 5, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		current, desired, err := previewer.PreviewAutoscaler(context.TODO(), workload, labels, 10, 3, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+		patch, err := PreviewPatch(current, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(Equal(`{"spec":{"minReplicas":3}}`))
+
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		Expect(fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app"}, hpa)).To(Succeed())
+		Expect(*hpa.Spec.MinReplicas).To(Equal(int32(5)))
+	})
+
+	It("should preview the whole autoscaler to be created", func() {
+		previewer, fakeClient := newPreviewer()
+		current, desired, err := previewer.PreviewAutoscaler(context.TODO(), workload, labels, 10, 3, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(BeNil())
+		patch, err := PreviewPatch(current, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(ContainSubstring(`"minReplicas":3`))
+
+		hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+		Expect(fakeClient.List(context.TODO(), hpas)).To(Succeed())
+		Expect(hpas.Items).To(BeEmpty())
+	})
+
+	It("should preview an empty patch if the autoscaler doesn't change", func() {
+		previewer, _ := newPreviewer()
+		_, err := previewer.CreateOrUpdateAutoscaler(context.TODO(), workload, labels, 10, 5, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+		current, desired, err := previewer.PreviewAutoscaler(context.TODO(), workload, labels, 10, 5, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+		patch, err := PreviewPatch(current, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(BeEmpty())
+	})
+})
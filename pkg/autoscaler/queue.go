@@ -0,0 +1,111 @@
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+const (
+	// SQSQueueMetricName is the metric of the targets scaling the consumers of an SQS queue on its depth. The
+	// AverageValue of the target is the depth per replica, i.e. the queueLength of the KEDA aws-sqs-queue trigger.
+	SQSQueueMetricName = "aws-sqs-queue"
+	// RabbitMQQueueMetricName is the metric of the targets scaling the consumers of a RabbitMQ queue on its depth. The
+	// AverageValue of the target is the depth per replica, i.e. the value of the KEDA rabbitmq trigger.
+	RabbitMQQueueMetricName = "rabbitmq"
+
+	// QueueTypeAnnotation marks a workload as a queue consumer, which is recommended on the depth of the queue rather
+	// than its cpu utilization. Either sqs or rabbitmq.
+	QueueTypeAnnotation = "ottoscalr.io/queue-type"
+	// QueueNameAnnotation is the name of the queue the consumer consumes.
+	QueueNameAnnotation = "ottoscalr.io/queue-name"
+	// QueueURLAnnotation is the url of the SQS queue, required to enforce the aws-sqs-queue triggers.
+	QueueURLAnnotation = "ottoscalr.io/queue-url"
+	// QueueRegionAnnotation is the aws region of the SQS queue, required to enforce the aws-sqs-queue triggers.
+	QueueRegionAnnotation = "ottoscalr.io/queue-region"
+	// QueueTriggerAuthenticationAnnotation is the KEDA TriggerAuthentication the queue triggers authenticate with,
+	// required to enforce the rabbitmq triggers, which read the host of the queue from it.
+	QueueTriggerAuthenticationAnnotation = "ottoscalr.io/queue-trigger-authentication"
+	// QueueTargetDepthAnnotation overrides the depth of the queue the consumer is recommended to stay under.
+	QueueTargetDepthAnnotation = "ottoscalr.io/queue-target-depth"
+)
+
+var queueMetricNames = map[string]string{
+	"sqs":      SQSQueueMetricName,
+	"rabbitmq": RabbitMQQueueMetricName,
+}
+
+// QueueTrigger identifies the queue a queue consumer is scaled on.
+type QueueTrigger struct {
+	// Type is the type of the queue, either sqs or rabbitmq.
+	Type string
+	// MetricName is the metric the consumer is scaled on, which is also the type of its KEDA trigger.
+	MetricName            string
+	Name                  string
+	URL                   string
+	Region                string
+	TriggerAuthentication string
+	// TargetDepth is the depth the queue is recommended to stay under, 0 unless overridden.
+	TargetDepth int
+}
+
+// QueueTriggerFromAnnotations returns the queue trigger of a workload annotated as a queue consumer, nil if it isn't
+// one. It fails if the consumer is annotated with an unknown queue type, without its queue or with an invalid target
+// depth.
+func QueueTriggerFromAnnotations(annotations map[string]string) (*QueueTrigger, error) {
+	queueType, ok := annotations[QueueTypeAnnotation]
+	if !ok || queueType == "" {
+		return nil, nil
+	}
+	metricName, ok := queueMetricNames[queueType]
+	if !ok {
+		return nil, fmt.Errorf("invalid %s annotation %q, expected either sqs or rabbitmq", QueueTypeAnnotation, queueType)
+	}
+	trigger := &QueueTrigger{
+		Type:                  queueType,
+		MetricName:            metricName,
+		Name:                  annotations[QueueNameAnnotation],
+		URL:                   annotations[QueueURLAnnotation],
+		Region:                annotations[QueueRegionAnnotation],
+		TriggerAuthentication: annotations[QueueTriggerAuthenticationAnnotation],
+	}
+	if trigger.Name == "" {
+		return nil, fmt.Errorf("%s queue consumer is annotated without the %s annotation", queueType, QueueNameAnnotation)
+	}
+	if targetDepth, ok := annotations[QueueTargetDepthAnnotation]; ok {
+		depth, err := strconv.Atoi(targetDepth)
+		if err != nil || depth <= 0 {
+			return nil, fmt.Errorf("invalid %s annotation %q, expected a positive number of messages", QueueTargetDepthAnnotation, targetDepth)
+		}
+		trigger.TargetDepth = depth
+	}
+	return trigger, nil
+}
+
+func (m MetricTarget) isQueueDepth() bool {
+	return m.GetName() == SQSQueueMetricName || m.GetName() == RabbitMQQueueMetricName
+}
+
+// queueScaleTrigger returns the KEDA trigger scaling the consumers of the queue on the depth per replica of the target.
+func queueScaleTrigger(target MetricTarget, trigger QueueTrigger) kedaapi.ScaleTriggers {
+	scaleTrigger := kedaapi.ScaleTriggers{Type: trigger.MetricName}
+	switch trigger.MetricName {
+	case SQSQueueMetricName:
+		scaleTrigger.Metadata = map[string]string{
+			"queueURL":    trigger.URL,
+			"awsRegion":   trigger.Region,
+			"queueLength": fmt.Sprint(target.Value),
+		}
+	case RabbitMQQueueMetricName:
+		scaleTrigger.Metadata = map[string]string{
+			"queueName": trigger.Name,
+			"mode":      "QueueLength",
+			"value":     fmt.Sprint(target.Value),
+		}
+	}
+	if trigger.TriggerAuthentication != "" {
+		scaleTrigger.AuthenticationRef = &kedaapi.ScaledObjectAuthRef{Name: trigger.TriggerAuthentication}
+	}
+	return scaleTrigger
+}
@@ -0,0 +1,85 @@
+package autoscaler
+
+import (
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+var _ = Describe("Queue triggers", func() {
+	sqsAnnotations := map[string]string{
+		QueueTypeAnnotation:   "sqs",
+		QueueNameAnnotation:   "orders",
+		QueueURLAnnotation:    "https://sqs.ap-south-1.amazonaws.com/123456789012/orders",
+		QueueRegionAnnotation: "ap-south-1",
+	}
+	rabbitMQAnnotations := map[string]string{
+		QueueTypeAnnotation:                  "rabbitmq",
+		QueueNameAnnotation:                  "orders",
+		QueueTriggerAuthenticationAnnotation: "rabbitmq-auth",
+		QueueTargetDepthAnnotation:           "500",
+	}
+
+	It("should read the queue trigger of the annotated consumers", func() {
+		trigger, err := QueueTriggerFromAnnotations(rabbitMQAnnotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trigger).To(Equal(&QueueTrigger{
+			Type:                  "rabbitmq",
+			MetricName:            RabbitMQQueueMetricName,
+			Name:                  "orders",
+			TriggerAuthentication: "rabbitmq-auth",
+			TargetDepth:           500,
+		}))
+
+		trigger, err = QueueTriggerFromAnnotations(map[string]string{"app": "orders"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trigger).To(BeNil())
+	})
+
+	It("should reject the consumers of unknown queues or without a queue", func() {
+		_, err := QueueTriggerFromAnnotations(map[string]string{QueueTypeAnnotation: "kinesis", QueueNameAnnotation: "orders"})
+		Expect(err).To(HaveOccurred())
+		_, err = QueueTriggerFromAnnotations(map[string]string{QueueTypeAnnotation: "sqs"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should scale the consumers on the depth per replica", func() {
+		trigger, _ := QueueTriggerFromAnnotations(sqsAnnotations)
+		triggers := setScaleTriggers(MetricTarget{Name: SQSQueueMetricName, Type: AverageValueTargetType, Value: 20, Queue: trigger}, nil)
+		Expect(triggers[0].Type).To(Equal(SQSQueueMetricName))
+		Expect(triggers[0].Metadata).To(Equal(map[string]string{
+			"queueURL":    "https://sqs.ap-south-1.amazonaws.com/123456789012/orders",
+			"awsRegion":   "ap-south-1",
+			"queueLength": "20",
+		}))
+		Expect(triggers[0].AuthenticationRef).To(BeNil())
+
+		trigger, _ = QueueTriggerFromAnnotations(rabbitMQAnnotations)
+		triggers = setScaleTriggers(MetricTarget{Name: RabbitMQQueueMetricName, Type: AverageValueTargetType, Value: 20, Queue: trigger}, nil)
+		Expect(triggers[0].Metadata).To(Equal(map[string]string{
+			"queueName": "orders",
+			"mode":      "QueueLength",
+			"value":     "20",
+		}))
+		Expect(triggers[0].AuthenticationRef).To(Equal(&kedaapi.ScaledObjectAuthRef{Name: "rabbitmq-auth"}))
+	})
+
+	It("should need the queue of the target to enforce the queue depth targets", func() {
+		workload := &appsv1.Deployment{}
+		workload.SetAnnotations(sqsAnnotations)
+		_, err := queueTriggerOf(workload, MetricTarget{Name: SQSQueueMetricName, Type: AverageValueTargetType, Value: 20})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = queueTriggerOf(workload, MetricTarget{Name: RabbitMQQueueMetricName, Type: AverageValueTargetType, Value: 20})
+		Expect(err).To(HaveOccurred())
+
+		workload.SetAnnotations(map[string]string{QueueTypeAnnotation: "rabbitmq", QueueNameAnnotation: "orders"})
+		_, err = queueTriggerOf(workload, MetricTarget{Name: RabbitMQQueueMetricName, Type: AverageValueTargetType, Value: 20})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not express the queue depth targets as HPA metrics", func() {
+		_, err := MetricTarget{Name: RabbitMQQueueMetricName, Type: AverageValueTargetType, Value: 20}.toMetricSpec()
+		Expect(err).To(HaveOccurred())
+	})
+})
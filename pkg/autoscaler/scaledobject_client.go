@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -12,15 +13,32 @@ import (
 )
 
 type ScaledobjectClient struct {
-	k8sClient client.Client
+	k8sClient                    client.Client
+	behaviorMergeStrategyDefault BehaviorMergeStrategy
+	template                     ScaledObjectTemplate
 }
 
 func NewScaledobjectClient(k8sClient client.Client) *ScaledobjectClient {
 	return &ScaledobjectClient{
-		k8sClient: k8sClient,
+		k8sClient:                    k8sClient,
+		behaviorMergeStrategyDefault: IgnoreBehavior,
 	}
 }
 
+// WithBehaviorMergeStrategy makes the client carry the scaling behavior of the HPAs already scaling the workloads into
+// their ScaledObjects with the strategy, unless their namespaces override it with BehaviorMergeStrategyAnnotation.
+func (soc *ScaledobjectClient) WithBehaviorMergeStrategy(strategy BehaviorMergeStrategy) *ScaledobjectClient {
+	soc.behaviorMergeStrategyDefault = strategy
+	return soc
+}
+
+// WithTemplate makes the client set the fields of the template on the ScaledObjects, unless the namespaces of their
+// workloads override them with ContextWithScaledObjectTemplate.
+func (soc *ScaledobjectClient) WithTemplate(template ScaledObjectTemplate) *ScaledobjectClient {
+	soc.template = template
+	return soc
+}
+
 func (soc *ScaledobjectClient) GetMaxReplicaCount(obj client.Object) int32 {
 	maxPods := int32(0)
 	scaledObject := obj.(*kedaapi.ScaledObject)
@@ -31,6 +49,16 @@ func (soc *ScaledobjectClient) GetMaxReplicaCount(obj client.Object) int32 {
 	return maxPods
 }
 
+func (soc *ScaledobjectClient) GetMinReplicaCount(obj client.Object) int32 {
+	minPods := int32(0)
+	scaledObject := obj.(*kedaapi.ScaledObject)
+	if scaledObject.Spec.MinReplicaCount != nil {
+		minPods = *scaledObject.Spec.MinReplicaCount
+	}
+
+	return minPods
+}
+
 func (soc *ScaledobjectClient) GetName() string {
 	return "ScaledObject"
 }
@@ -52,8 +80,8 @@ func (soc *ScaledobjectClient) GetList(ctx context.Context, labelSelector labels
 
 	var result []client.Object
 
-	for _, scaledObject := range scaledObjects.Items {
-		result = append(result, &scaledObject)
+	for i := range scaledObjects.Items {
+		result = append(result, &scaledObjects.Items[i])
 	}
 
 	return result, nil
@@ -76,7 +104,36 @@ func (soc *ScaledobjectClient) DeleteAutoscaler(ctx context.Context, obj client.
 }
 
 func (soc *ScaledobjectClient) CreateOrUpdateAutoscaler(ctx context.Context, workload client.Object, labels map[string]string,
-	max int32, min int32, targetCPUUtilization int32) (string, error) {
+	max int32, min int32, target MetricTarget, cronTriggers []CronTrigger) (string, error) {
+	if target.isKafkaLag() {
+		kafkaTrigger, err := kafkaTriggerOf(workload, target)
+		if err != nil {
+			return "", err
+		}
+		target.Kafka = kafkaTrigger
+	} else if target.isQueueDepth() {
+		queueTrigger, err := queueTriggerOf(workload, target)
+		if err != nil {
+			return "", err
+		}
+		target.Queue = queueTrigger
+	} else if !target.isResourceMetric() || target.GetType() == ValueTargetType {
+		return "", fmt.Errorf("ScaledObject enforcement only supports cpu and memory Utilization or AverageValue targets, got %s %s", target.GetName(), target.GetType())
+	}
+	strategy, err := soc.behaviorMergeStrategy(ctx, workload.GetNamespace())
+	if err != nil {
+		return "", err
+	}
+	var hpaBehavior *autoscalingv2beta2.HorizontalPodAutoscalerBehavior
+	if strategy != IgnoreBehavior {
+		if hpaBehavior, err = soc.adoptedHPABehavior(ctx, workload, labels); err != nil {
+			return "", err
+		}
+	}
+	template := soc.template
+	if namespaceTemplate := scaledObjectTemplateFromContext(ctx); namespaceTemplate != nil {
+		template = namespaceTemplate.WithDefaults(template)
+	}
 	scaledObj := kedaapi.ScaledObject{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workload.GetName(),
@@ -99,23 +156,37 @@ func (soc *ScaledobjectClient) CreateOrUpdateAutoscaler(ctx context.Context, wor
 			},
 			MinReplicaCount: &min,
 			MaxReplicaCount: &max,
-			Triggers:        setScaleTriggers(targetCPUUtilization),
+			Triggers:        setScaleTriggers(target, cronTriggers),
 		},
 	}
 
 	result, err := controllerutil.CreateOrUpdate(ctx, soc.k8sClient, &scaledObj, func() error {
-
-		scaledObj.Spec = kedaapi.ScaledObjectSpec{
-			ScaleTargetRef: &kedaapi.ScaleTarget{
-				Name:       workload.GetName(),
-				APIVersion: workload.GetObjectKind().GroupVersionKind().GroupVersion().String(),
-				Kind:       workload.GetObjectKind().GroupVersionKind().Kind,
-			},
-			MinReplicaCount: &min,
-			MaxReplicaCount: &max,
-			Triggers:        setScaleTriggers(targetCPUUtilization),
+		// only the fields managed by ottoscalr are updated, so that adopting a ScaledObject keeps its custom triggers
+		managed, err := managedFieldsOf(&scaledObj)
+		if err != nil {
+			return err
+		}
+		scaledObj.Spec.ScaleTargetRef = &kedaapi.ScaleTarget{
+			Name:       workload.GetName(),
+			APIVersion: workload.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       workload.GetObjectKind().GroupVersionKind().Kind,
+		}
+		if managed[MinReplicaCountField] || scaledObj.Spec.MinReplicaCount == nil {
+			scaledObj.Spec.MinReplicaCount = &min
+		}
+		if managed[MaxReplicaCountField] || scaledObj.Spec.MaxReplicaCount == nil {
+			scaledObj.Spec.MaxReplicaCount = &max
+		}
+		if managed[TriggersField] {
+			scaledObj.Spec.Triggers = mergeScaleTriggers(scaledObj.Spec.Triggers, setScaleTriggers(target, cronTriggers))
+		}
+		if managed[BehaviorField] {
+			behavior := mergeBehavior(strategy, hpaBehavior, currentBehavior(scaledObj.Spec))
+			scaledObj.Spec.Advanced = advancedConfig(scaledObj.Spec.Advanced, behavior)
+		}
+		if managed[TemplateField] {
+			applyTemplate(&scaledObj.Spec, template)
 		}
-
 		return nil
 	})
 	if err != nil {
@@ -124,16 +195,33 @@ func (soc *ScaledobjectClient) CreateOrUpdateAutoscaler(ctx context.Context, wor
 	return string(result), nil
 }
 
-func setScaleTriggers(targetCPUUtilization int32) []kedaapi.ScaleTriggers {
+func setScaleTriggers(target MetricTarget, cronTriggers []CronTrigger) []kedaapi.ScaleTriggers {
 	scaleTriggers := []kedaapi.ScaleTriggers{
 		{
-			Type: "cpu",
+			Type: target.GetName(),
 			Metadata: map[string]string{
-				"type":  "Utilization",
-				"value": fmt.Sprint(targetCPUUtilization),
+				"type":  target.GetType(),
+				"value": fmt.Sprint(target.Value),
 			},
 		},
 	}
+	if target.Kafka != nil {
+		scaleTriggers[0] = kafkaScaleTrigger(target, *target.Kafka)
+	}
+	if target.Queue != nil {
+		scaleTriggers[0] = queueScaleTrigger(target, *target.Queue)
+	}
+	for _, cronTrigger := range cronTriggers {
+		scaleTriggers = append(scaleTriggers, kedaapi.ScaleTriggers{
+			Type: "cron",
+			Metadata: map[string]string{
+				"timezone":        cronTrigger.Timezone,
+				"start":           cronTrigger.Start,
+				"end":             cronTrigger.End,
+				"desiredReplicas": fmt.Sprint(cronTrigger.DesiredReplicas),
+			},
+		})
+	}
 	if isEventScalerEnabled() {
 		scaleTriggers = append(scaleTriggers, kedaapi.ScaleTriggers{
 			Type: "scheduled-event",
@@ -149,3 +237,43 @@ func isEventScalerEnabled() bool {
 	//TODO: define based on annotation
 	return true
 }
+
+// kafkaTriggerOf returns the kafka trigger of the workload scaled on the kafka lag target.
+func kafkaTriggerOf(workload client.Object, target MetricTarget) (*KafkaTrigger, error) {
+	if target.GetType() != AverageValueTargetType {
+		return nil, fmt.Errorf("kafka lag targets only support AverageValue targets, got %s", target.GetType())
+	}
+	kafkaTrigger, err := KafkaTriggerFromAnnotations(workload.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+	if kafkaTrigger == nil || kafkaTrigger.BootstrapServers == "" {
+		return nil, fmt.Errorf("kafka lag targets need the workload to be annotated with %s, %s and %s",
+			KafkaConsumerGroupAnnotation, KafkaTopicAnnotation, KafkaBootstrapServersAnnotation)
+	}
+	return kafkaTrigger, nil
+}
+
+// queueTriggerOf returns the queue trigger of the workload scaled on the queue depth target.
+func queueTriggerOf(workload client.Object, target MetricTarget) (*QueueTrigger, error) {
+	if target.GetType() != AverageValueTargetType {
+		return nil, fmt.Errorf("queue depth targets only support AverageValue targets, got %s", target.GetType())
+	}
+	queueTrigger, err := QueueTriggerFromAnnotations(workload.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+	if queueTrigger == nil || queueTrigger.MetricName != target.GetName() {
+		return nil, fmt.Errorf("%s targets need the workload to be annotated as the consumer of such a queue with %s and %s",
+			target.GetName(), QueueTypeAnnotation, QueueNameAnnotation)
+	}
+	switch {
+	case queueTrigger.MetricName == SQSQueueMetricName && (queueTrigger.URL == "" || queueTrigger.Region == ""):
+		return nil, fmt.Errorf("%s targets need the workload to be annotated with %s and %s",
+			SQSQueueMetricName, QueueURLAnnotation, QueueRegionAnnotation)
+	case queueTrigger.MetricName == RabbitMQQueueMetricName && queueTrigger.TriggerAuthentication == "":
+		return nil, fmt.Errorf("%s targets need the workload to be annotated with %s, which provides the host of the queue",
+			RabbitMQQueueMetricName, QueueTriggerAuthenticationAnnotation)
+	}
+	return queueTrigger, nil
+}
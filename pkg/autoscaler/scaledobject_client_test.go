@@ -8,6 +8,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -120,7 +122,7 @@ var _ = Describe("ScaledObjectClient", func() {
 			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, deployment)
 			Expect(err).ToNot(HaveOccurred())
 			_, err = scaledObjectClient.CreateOrUpdateAutoscaler(ctx, deployment,
-				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), *int32Ptr(4))
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(4), nil)
 
 			Expect(err).ToNot(HaveOccurred())
 			time.Sleep(2 * time.Second)
@@ -143,7 +145,7 @@ var _ = Describe("ScaledObjectClient", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			op, err := scaledObjectClient.CreateOrUpdateAutoscaler(ctx, deployment,
-				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), *int32Ptr(4))
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(4), nil)
 			Expect(err).ToNot(HaveOccurred())
 			time.Sleep(2 * time.Second)
 			Expect(op).To(Equal("created"))
@@ -158,7 +160,7 @@ var _ = Describe("ScaledObjectClient", func() {
 			Expect(scaledObject.Spec.ScaleTargetRef.Name).To(Equal(deploymentName))
 
 			op, err = scaledObjectClient.CreateOrUpdateAutoscaler(ctx, deployment,
-				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(8), *int32Ptr(5), *int32Ptr(10))
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(8), *int32Ptr(5), CPUUtilizationTarget(10), nil)
 			Expect(err).ToNot(HaveOccurred())
 			time.Sleep(2 * time.Second)
 			Expect(op).To(Equal("updated"))
@@ -174,6 +176,167 @@ var _ = Describe("ScaledObjectClient", func() {
 			Expect(k8sClient.Delete(ctx, scaledObject)).To(Succeed())
 
 		})
+
+		It("should carry the behavior of the HPA already scaling the workload into the ScaledObject", func() {
+			deployment := &appsv1.Deployment{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, deployment)
+			Expect(err).ToNot(HaveOccurred())
+			deployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+
+			selectPolicy := autoscalingv2.MaxChangePolicySelect
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "tuned-hpa", Namespace: deploymentNamespace},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: deploymentName, APIVersion: "apps/v1"},
+					MinReplicas:    int32Ptr(5),
+					MaxReplicas:    10,
+					Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+						ScaleUp: &autoscalingv2.HPAScalingRules{
+							SelectPolicy: &selectPolicy,
+							Policies:     []autoscalingv2.HPAScalingPolicy{{Type: autoscalingv2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, hpa)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, hpa)).To(Succeed())
+			}()
+			time.Sleep(2 * time.Second)
+
+			behaviorClient := NewScaledobjectClient(k8sClient).WithBehaviorMergeStrategy(ReplaceBehavior)
+			_, err = behaviorClient.CreateOrUpdateAutoscaler(ctx, deployment,
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(40), nil)
+			Expect(err).ToNot(HaveOccurred())
+			time.Sleep(2 * time.Second)
+
+			scaledObject := &kedaapi.ScaledObject{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, scaledObject)
+			Expect(err).ToNot(HaveOccurred())
+			behavior := scaledObject.Spec.Advanced.HorizontalPodAutoscalerConfig.Behavior
+			Expect(*behavior.ScaleUp.SelectPolicy).To(Equal(autoscalingv2beta2.MaxPolicySelect))
+			Expect(behavior.ScaleUp.Policies).To(Equal([]autoscalingv2beta2.HPAScalingPolicy{
+				{Type: autoscalingv2beta2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15},
+			}))
+			Expect(behavior.ScaleDown).To(BeNil())
+			Expect(k8sClient.Delete(ctx, scaledObject)).To(Succeed())
+		})
+		It("should keep the custom triggers and the unmanaged fields of an adopted ScaledObject", func() {
+			deployment := &appsv1.Deployment{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, deployment)
+			Expect(err).ToNot(HaveOccurred())
+
+			pollingInterval := int32(15)
+			adopted := &kedaapi.ScaledObject{
+				ObjectMeta: metav1.ObjectMeta{Name: deploymentName, Namespace: deploymentNamespace,
+					Annotations: map[string]string{ManagedFieldsAnnotation: "maxReplicaCount,triggers"}},
+				Spec: kedaapi.ScaledObjectSpec{
+					ScaleTargetRef:  &kedaapi.ScaleTarget{Name: deploymentName},
+					PollingInterval: &pollingInterval,
+					MinReplicaCount: int32Ptr(3),
+					MaxReplicaCount: int32Ptr(6),
+					Triggers: []kedaapi.ScaleTriggers{
+						{Type: "cpu", Metadata: map[string]string{"type": "Utilization", "value": "50"}},
+						{Type: "prometheus", Metadata: map[string]string{"query": "sum(rate(http_requests_total[1m]))",
+							"serverAddress": "http://prometheus:9090", "threshold": "100"}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, adopted)).To(Succeed())
+			time.Sleep(2 * time.Second)
+
+			_, err = scaledObjectClient.CreateOrUpdateAutoscaler(ctx, deployment,
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(60), nil)
+			Expect(err).ToNot(HaveOccurred())
+			time.Sleep(2 * time.Second)
+
+			scaledObject := &kedaapi.ScaledObject{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, scaledObject)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scaledObject.Spec.MaxReplicaCount).To(Equal(int32Ptr(10)))
+			Expect(scaledObject.Spec.MinReplicaCount).To(Equal(int32Ptr(3)))
+			Expect(scaledObject.Spec.PollingInterval).To(Equal(&pollingInterval))
+			Expect(scaledObject.Spec.Triggers).To(HaveLen(3))
+			Expect(scaledObject.Spec.Triggers[0].Type).To(Equal("cpu"))
+			Expect(scaledObject.Spec.Triggers[0].Metadata["value"]).To(Equal("60"))
+			Expect(scaledObject.Spec.Triggers[1].Type).To(Equal("scheduled-event"))
+			Expect(scaledObject.Spec.Triggers[2].Type).To(Equal("prometheus"))
+			Expect(k8sClient.Delete(ctx, scaledObject)).To(Succeed())
+		})
+
+		It("should template the fields of the ScaledObject with the template of the namespace over the default one", func() {
+			deployment := &appsv1.Deployment{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, deployment)
+			Expect(err).ToNot(HaveOccurred())
+
+			templatedClient := NewScaledobjectClient(k8sClient).WithTemplate(ScaledObjectTemplate{
+				PollingInterval: int32Ptr(30), CooldownPeriod: int32Ptr(300), IdleReplicaCount: int32Ptr(0),
+				Fallback: &ScaledObjectFallback{FailureThreshold: 3, Replicas: 10}})
+			namespaceCtx := ContextWithScaledObjectTemplate(ctx, &ScaledObjectTemplate{PollingInterval: int32Ptr(10)})
+			_, err = templatedClient.CreateOrUpdateAutoscaler(namespaceCtx, deployment,
+				map[string]string{"created-by": "ottoscalr"}, *int32Ptr(10), *int32Ptr(5), CPUUtilizationTarget(60), nil)
+			Expect(err).ToNot(HaveOccurred())
+			time.Sleep(2 * time.Second)
+
+			scaledObject := &kedaapi.ScaledObject{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: deploymentNamespace, Name: deploymentName}, scaledObject)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scaledObject.Spec.PollingInterval).To(Equal(int32Ptr(10)))
+			Expect(scaledObject.Spec.CooldownPeriod).To(Equal(int32Ptr(300)))
+			Expect(scaledObject.Spec.IdleReplicaCount).To(Equal(int32Ptr(0)))
+			// KEDA doesn't fall back the cpu triggers
+			Expect(scaledObject.Spec.Fallback).To(BeNil())
+			Expect(k8sClient.Delete(ctx, scaledObject)).To(Succeed())
+		})
+	})
+
+	Describe("setScaleTriggers", func() {
+		It("should add a cron trigger per recommended cron trigger", func() {
+			triggers := setScaleTriggers(CPUUtilizationTarget(60), []CronTrigger{
+				{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "Asia/Kolkata", DesiredReplicas: 15},
+			})
+			Expect(triggers).To(HaveLen(3))
+			Expect(triggers[0].Type).To(Equal("cpu"))
+			Expect(triggers[1].Type).To(Equal("cron"))
+			Expect(triggers[1].Metadata).To(Equal(map[string]string{
+				"timezone":        "Asia/Kolkata",
+				"start":           "45 19 * * *",
+				"end":             "0 21 * * *",
+				"desiredReplicas": "15",
+			}))
+			Expect(triggers[2].Type).To(Equal("scheduled-event"))
+		})
+	})
+
+	Describe("HPA behavior merge strategies", func() {
+		scaleUp := &autoscalingv2beta2.HPAScalingRules{StabilizationWindowSeconds: int32Ptr(0)}
+		scaleDown := &autoscalingv2beta2.HPAScalingRules{StabilizationWindowSeconds: int32Ptr(300)}
+		tunedScaleDown := &autoscalingv2beta2.HPAScalingRules{StabilizationWindowSeconds: int32Ptr(600)}
+		hpaBehavior := &autoscalingv2beta2.HorizontalPodAutoscalerBehavior{ScaleUp: scaleUp, ScaleDown: scaleDown}
+		current := &autoscalingv2beta2.HorizontalPodAutoscalerBehavior{ScaleDown: tunedScaleDown}
+
+		It("should drop the behavior when it's ignored", func() {
+			Expect(mergeBehavior(IgnoreBehavior, hpaBehavior, current)).To(BeNil())
+		})
+
+		It("should replace the current behavior with the behavior of the HPA", func() {
+			Expect(mergeBehavior(ReplaceBehavior, hpaBehavior, current)).To(Equal(hpaBehavior))
+			Expect(mergeBehavior(ReplaceBehavior, nil, current)).To(Equal(current))
+		})
+
+		It("should fill only the directions without the rules from the behavior of the HPA", func() {
+			Expect(mergeBehavior(MergeBehavior, hpaBehavior, current)).To(Equal(
+				&autoscalingv2beta2.HorizontalPodAutoscalerBehavior{ScaleUp: scaleUp, ScaleDown: tunedScaleDown}))
+			Expect(mergeBehavior(MergeBehavior, hpaBehavior, nil)).To(Equal(hpaBehavior))
+			Expect(mergeBehavior(MergeBehavior, nil, current)).To(Equal(current))
+		})
+
+		It("should parse the strategies", func() {
+			Expect(ParseBehaviorMergeStrategy("")).To(Equal(IgnoreBehavior))
+			Expect(ParseBehaviorMergeStrategy("Merge")).To(Equal(MergeBehavior))
+			_, err := ParseBehaviorMergeStrategy("merge")
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
 
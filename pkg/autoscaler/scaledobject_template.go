@@ -0,0 +1,81 @@
+package autoscaler
+
+import (
+	"context"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+// ScaledObjectTemplate are the fields of the ScaledObjects which KEDA otherwise defaults. The unset fields are left to
+// KEDA, or to the owners of an adopted ScaledObject.
+type ScaledObjectTemplate struct {
+	PollingInterval  *int32                `yaml:"pollingInterval"`
+	CooldownPeriod   *int32                `yaml:"cooldownPeriod"`
+	IdleReplicaCount *int32                `yaml:"idleReplicaCount"`
+	Fallback         *ScaledObjectFallback `yaml:"fallback"`
+}
+
+// ScaledObjectFallback is the replicas the workload is scaled to once its scalers fail FailureThreshold times in a row.
+type ScaledObjectFallback struct {
+	FailureThreshold int32 `yaml:"failureThreshold"`
+	Replicas         int32 `yaml:"replicas"`
+}
+
+// WithDefaults returns the template with the unset fields taken from the defaults.
+func (t ScaledObjectTemplate) WithDefaults(defaults ScaledObjectTemplate) ScaledObjectTemplate {
+	if t.PollingInterval == nil {
+		t.PollingInterval = defaults.PollingInterval
+	}
+	if t.CooldownPeriod == nil {
+		t.CooldownPeriod = defaults.CooldownPeriod
+	}
+	if t.IdleReplicaCount == nil {
+		t.IdleReplicaCount = defaults.IdleReplicaCount
+	}
+	if t.Fallback == nil {
+		t.Fallback = defaults.Fallback
+	}
+	return t
+}
+
+type scaledObjectTemplateKey struct{}
+
+// ContextWithScaledObjectTemplate returns a copy of the context carrying the template of the namespace of the workload,
+// which overrides the default template of the client for the ScaledObject of the workload.
+func ContextWithScaledObjectTemplate(ctx context.Context, template *ScaledObjectTemplate) context.Context {
+	return context.WithValue(ctx, scaledObjectTemplateKey{}, template)
+}
+
+// scaledObjectTemplateFromContext returns the template carried by the context, or nil if there's none.
+func scaledObjectTemplateFromContext(ctx context.Context) *ScaledObjectTemplate {
+	template, _ := ctx.Value(scaledObjectTemplateKey{}).(*ScaledObjectTemplate)
+	return template
+}
+
+// applyTemplate sets the fields of the template on the spec. KEDA rejects an idle replica count which isn't below the
+// min replica count and doesn't fall back the cpu and memory triggers, so the idle replica count is left unchanged then
+// and the fallback is only set on the ScaledObjects without such triggers, e.g. the ones scaling on the kafka lag.
+func applyTemplate(spec *kedaapi.ScaledObjectSpec, template ScaledObjectTemplate) {
+	if template.PollingInterval != nil {
+		spec.PollingInterval = template.PollingInterval
+	}
+	if template.CooldownPeriod != nil {
+		spec.CooldownPeriod = template.CooldownPeriod
+	}
+	if template.IdleReplicaCount != nil && spec.MinReplicaCount != nil && *template.IdleReplicaCount < *spec.MinReplicaCount {
+		spec.IdleReplicaCount = template.IdleReplicaCount
+	}
+	if template.Fallback != nil && !hasResourceTrigger(spec.Triggers) {
+		spec.Fallback = &kedaapi.Fallback{FailureThreshold: template.Fallback.FailureThreshold,
+			Replicas: template.Fallback.Replicas}
+	}
+}
+
+func hasResourceTrigger(triggers []kedaapi.ScaleTriggers) bool {
+	for _, trigger := range triggers {
+		if trigger.Type == "cpu" || trigger.Type == "memory" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package autoscaler
+
+import (
+	"strconv"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TargetReader reads the metric targets the autoscalers scale the workloads on.
+type TargetReader interface {
+	// GetMetricTarget returns the metric target of the autoscaler, or false if it doesn't scale on one.
+	GetMetricTarget(obj client.Object) (MetricTarget, bool)
+}
+
+func (hc *HPAClient) GetMetricTarget(obj client.Object) (MetricTarget, bool) {
+	hpa := obj.(*autoscalingv1.HorizontalPodAutoscaler)
+	if hpa.Spec.TargetCPUUtilizationPercentage == nil {
+		return MetricTarget{}, false
+	}
+	return CPUUtilizationTarget(*hpa.Spec.TargetCPUUtilizationPercentage), true
+}
+
+func (hc *HPAClientV2) GetMetricTarget(obj client.Object) (MetricTarget, bool) {
+	hpa := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if len(hpa.Spec.Metrics) == 0 {
+		return MetricTarget{}, false
+	}
+	metric := hpa.Spec.Metrics[0]
+	var name string
+	var target autoscalingv2.MetricTarget
+	switch {
+	case metric.Resource != nil:
+		name, target = string(metric.Resource.Name), metric.Resource.Target
+	case metric.Pods != nil:
+		name, target = metric.Pods.Metric.Name, metric.Pods.Target
+	case metric.External != nil:
+		name, target = metric.External.Metric.Name, metric.External.Target
+	default:
+		return MetricTarget{}, false
+	}
+	metricTarget := MetricTarget{Name: name, Type: string(target.Type)}
+	switch {
+	case target.AverageUtilization != nil:
+		metricTarget.Value = *target.AverageUtilization
+	case target.AverageValue != nil:
+		metricTarget.Value = int32(target.AverageValue.Value())
+	case target.Value != nil:
+		metricTarget.Value = int32(target.Value.Value())
+	default:
+		return MetricTarget{}, false
+	}
+	return metricTarget, true
+}
+
+// GetMetricTarget reads the target of the first trigger of the ScaledObject, which is the one scaling it on the metric
+// target while the others are the cron and the scheduled event triggers.
+func (soc *ScaledobjectClient) GetMetricTarget(obj client.Object) (MetricTarget, bool) {
+	scaledObject := obj.(*kedaapi.ScaledObject)
+	if len(scaledObject.Spec.Triggers) == 0 {
+		return MetricTarget{}, false
+	}
+	trigger := scaledObject.Spec.Triggers[0]
+	metricTarget := MetricTarget{Name: trigger.Type, Type: trigger.Metadata["type"]}
+	valueKey := "value"
+	switch trigger.Type {
+	case KafkaLagMetricName:
+		metricTarget.Type, valueKey = AverageValueTargetType, "lagThreshold"
+	case SQSQueueMetricName:
+		metricTarget.Type, valueKey = AverageValueTargetType, "queueLength"
+	case RabbitMQQueueMetricName:
+		metricTarget.Type = AverageValueTargetType
+	}
+	value, err := strconv.ParseInt(trigger.Metadata[valueKey], 10, 32)
+	if err != nil {
+		return MetricTarget{}, false
+	}
+	metricTarget.Value = int32(value)
+	return metricTarget, true
+}
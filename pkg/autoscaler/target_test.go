@@ -0,0 +1,54 @@
+package autoscaler
+
+import (
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+var _ = Describe("TargetReader", func() {
+	It("should read the metric targets of the HPAs", func() {
+		utilization := int32(60)
+		target, ok := NewHPAClient(nil).GetMetricTarget(&autoscalingv1.HorizontalPodAutoscaler{
+			Spec: autoscalingv1.HorizontalPodAutoscalerSpec{TargetCPUUtilizationPercentage: &utilization}})
+		Expect(ok).To(BeTrue())
+		Expect(target).To(Equal(CPUUtilizationTarget(60)))
+
+		for _, expected := range []MetricTarget{
+			{Name: "cpu", Type: UtilizationTargetType, Value: 60},
+			{Name: "http_requests", Type: AverageValueTargetType, Value: 250},
+			{Name: "queue_messages", Type: ValueTargetType, Value: 1000},
+		} {
+			metricSpec, err := expected.toMetricSpec()
+			Expect(err).NotTo(HaveOccurred())
+			target, ok = NewHPAClientV2(nil).GetMetricTarget(&autoscalingv2.HorizontalPodAutoscaler{
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{metricSpec}}})
+			Expect(ok).To(BeTrue())
+			Expect(target).To(Equal(expected))
+		}
+
+		_, ok = NewHPAClientV2(nil).GetMetricTarget(&autoscalingv2.HorizontalPodAutoscaler{})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should read the metric targets of the ScaledObjects from their first trigger", func() {
+		for _, expected := range []MetricTarget{
+			{Name: "cpu", Type: UtilizationTargetType, Value: 60},
+			{Name: KafkaLagMetricName, Type: AverageValueTargetType, Value: 250,
+				Kafka: &KafkaTrigger{ConsumerGroup: "orders-consumer", Topic: "orders"}},
+			{Name: SQSQueueMetricName, Type: AverageValueTargetType, Value: 100,
+				Queue: &QueueTrigger{MetricName: SQSQueueMetricName}},
+		} {
+			cronTriggers := []CronTrigger{{Start: "0 9 * * *", End: "0 11 * * *", Timezone: "UTC", DesiredReplicas: 10}}
+			target, ok := NewScaledobjectClient(nil).GetMetricTarget(&kedaapi.ScaledObject{
+				Spec: kedaapi.ScaledObjectSpec{Triggers: setScaleTriggers(expected, cronTriggers)}})
+			Expect(ok).To(BeTrue())
+			Expect(target).To(Equal(MetricTarget{Name: expected.Name, Type: expected.Type, Value: expected.Value}))
+		}
+
+		_, ok := NewScaledobjectClient(nil).GetMetricTarget(&kedaapi.ScaledObject{})
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,150 @@
+package autoscaler
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VerticalPodAutoscalerGVK is the kind of the VPAs, which are read as unstructured objects so that ottoscalr neither
+// depends on the VPA api nor needs its CRD installed.
+var VerticalPodAutoscalerGVK = schema.GroupVersionKind{
+	Group:   "autoscaling.k8s.io",
+	Version: "v1",
+	Kind:    "VerticalPodAutoscaler",
+}
+
+const (
+	vpaDefaultUpdateMode = "Auto"
+	vpaUpdateModeOff     = "Off"
+	vpaUpdateModeInitial = "Initial"
+	vpaContainerModeOff  = "Off"
+	vpaAllContainersName = "*"
+)
+
+// VPACoexistence describes how a VerticalPodAutoscaler targeting a workload coexists with its horizontal autoscaling.
+type VPACoexistence struct {
+	// Name is the name of the VPA.
+	Name string
+	// Conflicting is set if the VPA resizes the resource the workload is horizontally autoscaled on, so that both the
+	// autoscalers react to the same utilization and amplify each other.
+	Conflicting bool
+	// Evicting is set if the VPA evicts the running pods of the workload to resize them.
+	Evicting bool
+}
+
+// FindVPA returns the coexistence of the VPA targeting the workload with its horizontal autoscaling on the resource,
+// nil if there's no such VPA or the VPA CRD isn't installed.
+func FindVPA(ctx context.Context, k8sClient client.Client, workload client.Object,
+	resource corev1.ResourceName) (*VPACoexistence, error) {
+	vpas := &unstructured.UnstructuredList{}
+	vpas.SetGroupVersionKind(VerticalPodAutoscalerGVK.GroupVersion().WithKind(VerticalPodAutoscalerGVK.Kind + "List"))
+	if err := k8sClient.List(ctx, vpas, client.InNamespace(workload.GetNamespace())); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, client.IgnoreNotFound(err)
+	}
+	kind := workload.GetObjectKind().GroupVersionKind().Kind
+	for _, vpa := range vpas.Items {
+		targetName, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+		targetKind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+		if targetName != workload.GetName() || (kind != "" && targetKind != kind) {
+			continue
+		}
+		coexistence := vpaCoexistence(vpa, resource)
+		return &coexistence, nil
+	}
+	return nil, nil
+}
+
+// vpaCoexistence returns the coexistence of the VPA with the horizontal autoscaling on the resource.
+func vpaCoexistence(vpa unstructured.Unstructured, resource corev1.ResourceName) VPACoexistence {
+	updateMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+	if updateMode == "" {
+		updateMode = vpaDefaultUpdateMode
+	}
+	coexistence := VPACoexistence{Name: vpa.GetName()}
+	if updateMode == vpaUpdateModeOff {
+		return coexistence
+	}
+	coexistence.Conflicting = vpaControlsResource(vpa, resource)
+	coexistence.Evicting = updateMode != vpaUpdateModeInitial
+	return coexistence
+}
+
+// vpaControlsResource returns whether the container policies of the VPA let it resize the resource of any container.
+// The containers without a policy, which is every container unless there's a policy for all of them, are resized on
+// both cpu and memory.
+func vpaControlsResource(vpa unstructured.Unstructured, resource corev1.ResourceName) bool {
+	// the VPAs resize only the cpu and memory of the containers
+	if resource != corev1.ResourceCPU && resource != corev1.ResourceMemory {
+		return false
+	}
+	policies, _, _ := unstructured.NestedSlice(vpa.Object, "spec", "resourcePolicy", "containerPolicies")
+	hasAllContainersPolicy := false
+	for _, p := range policies {
+		policy, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		containerName, _, _ := unstructured.NestedString(policy, "containerName")
+		if containerName == vpaAllContainersName {
+			hasAllContainersPolicy = true
+		}
+		if mode, _, _ := unstructured.NestedString(policy, "mode"); mode == vpaContainerModeOff {
+			continue
+		}
+		controlledResources, found, _ := unstructured.NestedStringSlice(policy, "controlledResources")
+		if !found {
+			return true
+		}
+		for _, controlled := range controlledResources {
+			if corev1.ResourceName(controlled) == resource {
+				return true
+			}
+		}
+	}
+	return !hasAllContainersPolicy
+}
+
+// NewVPAObject returns an empty VPA to watch the VPAs with.
+func NewVPAObject() client.Object {
+	vpa := &unstructured.Unstructured{}
+	vpa.SetGroupVersionKind(VerticalPodAutoscalerGVK)
+	return vpa
+}
+
+// VPATargetName returns the name of the workload targeted by the VPA.
+func VPATargetName(vpa client.Object) string {
+	u, ok := vpa.(*unstructured.Unstructured)
+	if !ok {
+		return ""
+	}
+	name, _, _ := unstructured.NestedString(u.Object, "spec", "targetRef", "name")
+	return name
+}
+
+// VPARecommendationChanged returns true if the VPA changed its recommendation while it applies its recommendations to
+// the pods, which changes the resources of the pods the horizontal autoscaling is recommended on.
+func VPARecommendationChanged(oldVPA, newVPA client.Object) bool {
+	oldU, ok := oldVPA.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	newU, ok := newVPA.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	if updateMode, _, _ := unstructured.NestedString(newU.Object, "spec", "updatePolicy", "updateMode"); updateMode == vpaUpdateModeOff {
+		return false
+	}
+	oldRecommendation, _, _ := unstructured.NestedFieldNoCopy(oldU.Object, "status", "recommendation")
+	newRecommendation, _, _ := unstructured.NestedFieldNoCopy(newU.Object, "status", "recommendation")
+	return !reflect.DeepEqual(oldRecommendation, newRecommendation)
+}
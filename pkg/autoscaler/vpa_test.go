@@ -0,0 +1,79 @@
+package autoscaler
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newVPA(updateMode string, containerPolicies ...interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"targetRef": map[string]interface{}{"kind": "Deployment", "name": "test-deployment"},
+	}
+	if updateMode != "" {
+		spec["updatePolicy"] = map[string]interface{}{"updateMode": updateMode}
+	}
+	if len(containerPolicies) > 0 {
+		spec["resourcePolicy"] = map[string]interface{}{"containerPolicies": containerPolicies}
+	}
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	vpa.SetGroupVersionKind(VerticalPodAutoscalerGVK)
+	vpa.SetName("test-vpa")
+	return vpa
+}
+
+var _ = Describe("VerticalPodAutoscaler coexistence", func() {
+	It("should conflict with the VPAs resizing the cpu of all the containers by default", func() {
+		coexistence := vpaCoexistence(*newVPA(""), corev1.ResourceCPU)
+		Expect(coexistence).To(Equal(VPACoexistence{Name: "test-vpa", Conflicting: true, Evicting: true}))
+	})
+
+	It("should not conflict with the VPAs which only recommend", func() {
+		Expect(vpaCoexistence(*newVPA("Off"), corev1.ResourceCPU)).To(Equal(VPACoexistence{Name: "test-vpa"}))
+	})
+
+	It("should conflict without evicting with the VPAs resizing the pods on creation", func() {
+		coexistence := vpaCoexistence(*newVPA("Initial"), corev1.ResourceCPU)
+		Expect(coexistence.Conflicting).To(BeTrue())
+		Expect(coexistence.Evicting).To(BeFalse())
+	})
+
+	It("should be compatible with the VPAs resizing only the memory", func() {
+		vpa := newVPA("Auto", map[string]interface{}{
+			"containerName":       "*",
+			"controlledResources": []interface{}{"memory"},
+		})
+		Expect(vpaCoexistence(*vpa, corev1.ResourceCPU)).To(Equal(VPACoexistence{Name: "test-vpa", Evicting: true}))
+		Expect(vpaCoexistence(*vpa, corev1.ResourceMemory).Conflicting).To(BeTrue())
+		Expect(vpaCoexistence(*vpa, "requests_per_second").Conflicting).To(BeFalse())
+	})
+
+	It("should conflict if a container policy resizes the cpu", func() {
+		vpa := newVPA("Recreate",
+			map[string]interface{}{"containerName": "*", "mode": "Off"},
+			map[string]interface{}{"containerName": "app"},
+		)
+		Expect(vpaCoexistence(*vpa, corev1.ResourceCPU).Conflicting).To(BeTrue())
+
+		vpa = newVPA("Recreate", map[string]interface{}{"containerName": "sidecar", "mode": "Off"})
+		Expect(vpaCoexistence(*vpa, corev1.ResourceCPU).Conflicting).To(BeTrue())
+	})
+
+	It("should detect the changes of the recommendations applied to the pods", func() {
+		oldVPA := newVPA("Auto")
+		newVPA := oldVPA.DeepCopy()
+		Expect(VPATargetName(newVPA)).To(Equal("test-deployment"))
+		Expect(VPARecommendationChanged(oldVPA, newVPA)).To(BeFalse())
+
+		Expect(unstructured.SetNestedField(newVPA.Object, map[string]interface{}{
+			"containerRecommendations": []interface{}{
+				map[string]interface{}{"containerName": "app", "target": map[string]interface{}{"cpu": "500m"}},
+			},
+		}, "status", "recommendation")).To(Succeed())
+		Expect(VPARecommendationChanged(oldVPA, newVPA)).To(BeTrue())
+
+		Expect(unstructured.SetNestedField(newVPA.Object, "Off", "spec", "updatePolicy", "updateMode")).To(Succeed())
+		Expect(VPARecommendationChanged(oldVPA, newVPA)).To(BeFalse())
+	})
+})
@@ -0,0 +1,142 @@
+package backtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+)
+
+// Input is a candidate HPA configuration of a workload along with the historical metrics to replay it on.
+type Input struct {
+	DataPoints      []metrics.DataPoint
+	ACL             time.Duration
+	PerPodResources float64
+	RedLineUtil     float64
+	Config          v1alpha1.HPAConfiguration
+}
+
+// Breach is a stretch of consecutive datapoints where the workload used more CPU than the simulated HPA made
+// available to it.
+type Breach struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// PeakDeficit is the most CPU, in cores, the workload was short of during the breach.
+	PeakDeficit float64 `json:"peakDeficit"`
+}
+
+// Result is the outcome of replaying a candidate HPA configuration on the historical metrics.
+type Result struct {
+	Config             v1alpha1.HPAConfiguration `json:"config"`
+	WindowStart        time.Time                 `json:"windowStart"`
+	WindowEnd          time.Time                 `json:"windowEnd"`
+	DataPoints         int                       `json:"dataPoints"`
+	BreachedDataPoints int                       `json:"breachedDataPoints"`
+	Breaches           []Breach                  `json:"breaches"`
+	SavingsPercent     float64                   `json:"savingsPercent"`
+	PeakUtilization    float64                   `json:"peakUtilization"`
+	PeakUtilizationAt  time.Time                 `json:"peakUtilizationAt"`
+}
+
+// Survived returns true if the configuration didn't breach anywhere in the window.
+func (r *Result) Survived() bool {
+	return r.BreachedDataPoints == 0
+}
+
+// Run replays the HPA simulation of the recommender with the candidate configuration on the datapoints and reports
+// the breaches along with the savings.
+func Run(input Input) (*Result, error) {
+	if len(input.DataPoints) == 0 {
+		return nil, errors.New("no datapoints to backtest on")
+	}
+	if input.PerPodResources <= 0 {
+		return nil, errors.New("per pod resources should be positive")
+	}
+	if input.Config.Min < 1 || input.Config.Max < input.Config.Min {
+		return nil, fmt.Errorf("invalid HPA configuration: min %d, max %d", input.Config.Min, input.Config.Max)
+	}
+	simulated, savings, err := reco.SimulateHPAConfiguration(input.RedLineUtil, input.DataPoints, input.ACL,
+		input.PerPodResources, input.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Config:         input.Config,
+		WindowStart:    input.DataPoints[0].Timestamp,
+		WindowEnd:      input.DataPoints[len(input.DataPoints)-1].Timestamp,
+		DataPoints:     len(input.DataPoints),
+		Breaches:       []Breach{},
+		SavingsPercent: savings,
+	}
+	var breach *Breach
+	for i, dp := range input.DataPoints {
+		if i == 0 || dp.Value > result.PeakUtilization {
+			result.PeakUtilization, result.PeakUtilizationAt = dp.Value, dp.Timestamp
+		}
+		deficit := dp.Value - simulated[i].Value
+		if deficit <= 0 {
+			breach = nil
+			continue
+		}
+		result.BreachedDataPoints++
+		if breach == nil {
+			result.Breaches = append(result.Breaches, Breach{Start: dp.Timestamp})
+			breach = &result.Breaches[len(result.Breaches)-1]
+		}
+		breach.End = dp.Timestamp
+		if deficit > breach.PeakDeficit {
+			breach.PeakDeficit = deficit
+		}
+	}
+	return result, nil
+}
+
+// Format is the encoding of the backtest results.
+type Format string
+
+const (
+	TextFormat Format = "text"
+	JSONFormat Format = "json"
+)
+
+// Write renders the result in the format.
+func Write(w io.Writer, result *Result, format Format) error {
+	switch format {
+	case JSONFormat:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case TextFormat:
+		return writeText(w, result)
+	}
+	return fmt.Errorf("unsupported backtest format %s", format)
+}
+
+func writeText(w io.Writer, result *Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	verdict := "SURVIVED"
+	if !result.Survived() {
+		verdict = "BREACHED"
+	}
+	fmt.Fprintf(tw, "Config:\tmin %d, max %d, target %d%%\n", result.Config.Min, result.Config.Max,
+		result.Config.TargetMetricValue)
+	fmt.Fprintf(tw, "Window:\t%s - %s (%d datapoints)\n", result.WindowStart.Format(time.RFC3339),
+		result.WindowEnd.Format(time.RFC3339), result.DataPoints)
+	fmt.Fprintf(tw, "Peak utilization:\t%.2f cores at %s\n", result.PeakUtilization,
+		result.PeakUtilizationAt.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Savings:\t%.2f%%\n", result.SavingsPercent)
+	fmt.Fprintf(tw, "Verdict:\t%s (%d breached datapoints in %d breaches)\n", verdict, result.BreachedDataPoints,
+		len(result.Breaches))
+	for _, breach := range result.Breaches {
+		fmt.Fprintf(tw, "  Breach:\t%s - %s, short of %.2f cores at the peak\n", breach.Start.Format(time.RFC3339),
+			breach.End.Format(time.RFC3339), breach.PeakDeficit)
+	}
+	return tw.Flush()
+}
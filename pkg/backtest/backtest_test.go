@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backtest", func() {
+	var dataPoints []metrics.DataPoint
+	start := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		// 10 cores for an hour with a peak of 30 cores from the 30th to the 39th minute
+		dataPoints = nil
+		for i := 0; i < 60; i++ {
+			value := 10.0
+			if i >= 30 && i < 40 {
+				value = 30
+			}
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+	})
+
+	newInput := func(config v1alpha1.HPAConfiguration) Input {
+		return Input{DataPoints: dataPoints, ACL: 5 * time.Minute, PerPodResources: 2, RedLineUtil: 0.85, Config: config}
+	}
+
+	It("should report the breaches while the scale up is catching up with the peak", func() {
+		result, err := Run(newInput(v1alpha1.HPAConfiguration{Min: 2, Max: 20, TargetMetricValue: 50}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Survived()).To(BeFalse())
+		Expect(result.DataPoints).To(Equal(60))
+		Expect(result.BreachedDataPoints).To(Equal(5))
+		// 10 replicas of 2 cores at the red line of 0.85 leave 17 cores for the 30 cores needed
+		Expect(result.Breaches).To(Equal([]Breach{{Start: start.Add(30 * time.Minute), End: start.Add(34 * time.Minute),
+			PeakDeficit: 13}}))
+		Expect(result.PeakUtilization).To(Equal(30.0))
+		Expect(result.PeakUtilizationAt).To(Equal(start.Add(30 * time.Minute)))
+		Expect(result.SavingsPercent).To(BeNumerically(">", 0))
+	})
+
+	It("should survive the peak with enough min replicas", func() {
+		result, err := Run(newInput(v1alpha1.HPAConfiguration{Min: 20, Max: 20, TargetMetricValue: 50}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Survived()).To(BeTrue())
+		Expect(result.Breaches).To(BeEmpty())
+		Expect(result.SavingsPercent).To(BeNumerically("~", 0, 1e-9))
+
+		out := &bytes.Buffer{}
+		Expect(Write(out, result, TextFormat)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("SURVIVED"))
+	})
+
+	It("should reject invalid configurations", func() {
+		_, err := Run(newInput(v1alpha1.HPAConfiguration{Min: 5, Max: 2, TargetMetricValue: 50}))
+		Expect(err).To(HaveOccurred())
+		_, err = Run(Input{Config: v1alpha1.HPAConfiguration{Min: 1, Max: 2, TargetMetricValue: 50}, PerPodResources: 1})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should window the datapoints", func() {
+		Expect(Window(dataPoints, start.Add(10*time.Minute), start.Add(19*time.Minute))).To(HaveLen(10))
+		Expect(Window(dataPoints, time.Time{}, start.Add(9*time.Minute))).To(HaveLen(10))
+		Expect(Window(dataPoints, start.Add(50*time.Minute), time.Time{})).To(HaveLen(10))
+		Expect(Window(dataPoints, start.Add(2*time.Hour), time.Time{})).To(BeEmpty())
+	})
+})
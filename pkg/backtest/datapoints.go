@@ -0,0 +1,22 @@
+package backtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+// Window returns the datapoints within [start, end] of the sorted datapoints. Zero times leave the window open.
+func Window(dataPoints []metrics.DataPoint, start, end time.Time) []metrics.DataPoint {
+	from := sort.Search(len(dataPoints), func(i int) bool {
+		return start.IsZero() || !dataPoints[i].Timestamp.Before(start)
+	})
+	to := sort.Search(len(dataPoints), func(i int) bool {
+		return !end.IsZero() && dataPoints[i].Timestamp.After(end)
+	})
+	if to < from {
+		return nil
+	}
+	return dataPoints[from:to]
+}
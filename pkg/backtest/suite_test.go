@@ -0,0 +1,13 @@
+package backtest
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBacktest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Backtest Suite")
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"math"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	hpaenforcerCapacityReservationCutsHeldCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_capacity_reservation_cuts_held_count",
+			Help: "Number of min replicas cuts held back to keep the capacity reservation of the namespace"}, []string{"namespace", "policyreco"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(hpaenforcerCapacityReservationCutsHeldCounter)
+}
+
+// reservedMinReplicas returns the least min replicas, no more than managedMin, which keep the capacity of the namespace
+// at the reservation, given the capacity of its other workloads and the cpu per pod of the workload. It returns min if
+// min keeps the reservation already.
+func reservedMinReplicas(reservation, otherCapacity, cpuPerPod float64, min, managedMin int32) int32 {
+	if cpuPerPod <= 0 || otherCapacity+float64(min)*cpuPerPod >= reservation {
+		return min
+	}
+	reserved := int32(math.Ceil((reservation - otherCapacity) / cpuPerPod))
+	if reserved > managedMin {
+		reserved = managedMin
+	}
+	if reserved < min {
+		return min
+	}
+	return reserved
+}
+
+// capacityReservationMinReplicas returns the min replicas of the workload which keep the capacity of its namespace,
+// the sum of the min replicas of its workloads times the cpu per pod, at the reservation. It only holds back the cuts
+// of the min replicas of the autoscaler managed for the workload and never raises them. The workloads without an
+// autoscaler managed by ottoscalr count with their replicas.
+func (r *HPAEnforcementController) capacityReservationMinReplicas(ctx context.Context, object registry.ObjectClient,
+	workload client.Object, min int32, reservation float64) (int32, error) {
+	managedMin, err := r.managedMinReplicas(ctx, workload, min)
+	if err != nil || managedMin == min {
+		return min, err
+	}
+	cpuPerPod, err := object.GetContainerResourceLimits(workload.GetNamespace(), workload.GetName())
+	if err != nil {
+		return min, err
+	}
+
+	workloads, err := r.namespaceWorkloads(workload.GetNamespace())
+	if err != nil {
+		return min, err
+	}
+	otherCapacity := 0.0
+	for _, other := range workloads {
+		kind := other.GetObjectKind().GroupVersionKind().Kind
+		if other.GetName() == workload.GetName() && kind == object.GetKind() {
+			continue
+		}
+		otherObject, err := r.clientsRegistry.GetObjectClient(kind)
+		if err != nil {
+			return min, err
+		}
+		replicas, err := r.managedMinReplicas(ctx, other, 0)
+		if err != nil {
+			return min, err
+		}
+		if replicas == 0 {
+			current, err := otherObject.GetReplicaCount(other.GetNamespace(), other.GetName())
+			if err != nil {
+				return min, err
+			}
+			replicas = int32(current)
+		}
+		if replicas == 0 {
+			continue
+		}
+		// the workloads without any pods have no capacity to count
+		otherCPUPerPod, err := otherObject.GetContainerResourceLimits(other.GetNamespace(), other.GetName())
+		if err != nil {
+			continue
+		}
+		otherCapacity += float64(replicas) * otherCPUPerPod
+	}
+	return reservedMinReplicas(reservation, otherCapacity, cpuPerPod, min, managedMin), nil
+}
@@ -0,0 +1,20 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capacity reservation", func() {
+	It("should hold back the cuts of the min replicas which break the reservation of the namespace", func() {
+		// 30 cores of the other workloads and 2 cores per pod need 5 min replicas to keep a reservation of 40 cores
+		Expect(reservedMinReplicas(40, 30, 2, 3, 8)).Should(Equal(int32(5)))
+		Expect(reservedMinReplicas(40, 30, 2, 6, 8)).Should(Equal(int32(6)))
+		Expect(reservedMinReplicas(40, 31, 2, 3, 8)).Should(Equal(int32(5)))
+	})
+
+	It("should never raise the min replicas above the ones of the managed autoscaler", func() {
+		Expect(reservedMinReplicas(40, 10, 2, 3, 8)).Should(Equal(int32(8)))
+		Expect(reservedMinReplicas(40, 30, 0, 3, 8)).Should(Equal(int32(3)))
+	})
+})
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Criticality starts the workloads onboarded by the registrar up the ladder of the policies by their criticality,
+// instead of at the safest policy, e.g. the batch workloads three steps up. The criticality of a workload is the value
+// of its label at the key, or of its annotation at the key if it has no such label.
+type Criticality struct {
+	Key string
+	// Steps are how many steps up from the safest policy the workloads of every criticality start at. The workloads
+	// of the criticalities missing start at the safest policy, and the steps past the riskiest policy at it.
+	Steps map[string]int
+}
+
+// initialPolicy returns the policy the workload starts at by its criticality, or the empty string for the safest.
+func (c Criticality) initialPolicy(policyStore policy.Store, workload client.Object) (string, error) {
+	if len(c.Key) == 0 || len(c.Steps) == 0 {
+		return "", nil
+	}
+	criticality, ok := workload.GetLabels()[c.Key]
+	if !ok {
+		criticality = workload.GetAnnotations()[c.Key]
+	}
+	steps := c.Steps[criticality]
+	if steps <= 0 {
+		return "", nil
+	}
+	policies, err := policyStore.GetSortedPolicies()
+	if err != nil {
+		return "", err
+	}
+	if len(policies.Items) == 0 {
+		return "", nil
+	}
+	if steps >= len(policies.Items) {
+		steps = len(policies.Items) - 1
+	}
+	return policies.Items[steps].Name, nil
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Criticality", func() {
+	var policyStore policy.Store
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		var objects []v1alpha1.Policy
+		for i, name := range []string{"safest", "safe", "balanced", "aggressive"} {
+			objects = append(objects, v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: v1alpha1.PolicySpec{RiskIndex: (i + 1) * 10}})
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(&objects[2], &objects[0], &objects[3], &objects[1]).Build()
+		policyStore = policy.NewPolicyStore(k8sClient)
+	})
+
+	workload := func(labels, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Labels: labels,
+			Annotations: annotations}}
+	}
+
+	It("should start the workloads up the ladder by their criticality", func() {
+		criticality := Criticality{Key: "tier", Steps: map[string]int{"batch": 2, "internal": 1, "offline": 10}}
+		for _, tc := range []struct {
+			workload *appsv1.Deployment
+			policy   string
+		}{
+			{workload(map[string]string{"tier": "batch"}, nil), "balanced"},
+			{workload(nil, map[string]string{"tier": "internal"}), "safe"},
+			{workload(map[string]string{"tier": "offline"}, nil), "aggressive"},
+			{workload(map[string]string{"tier": "critical"}, nil), ""},
+			{workload(nil, nil), ""},
+		} {
+			initialPolicy, err := criticality.initialPolicy(policyStore, tc.workload)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(initialPolicy).To(Equal(tc.policy))
+		}
+	})
+
+	It("should start the workloads at the safest policy without a criticality key", func() {
+		initialPolicy, err := Criticality{Steps: map[string]int{"batch": 2}}.initialPolicy(policyStore,
+			workload(map[string]string{"tier": "batch"}, nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(initialPolicy).To(BeEmpty())
+	})
+})
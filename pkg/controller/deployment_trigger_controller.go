@@ -5,8 +5,10 @@ import (
 	"time"
 
 	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
 	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
 	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +32,9 @@ type DeploymentTriggerController struct {
 	Client          client.Client
 	Scheme          *runtime.Scheme
 	ClientsRegistry registry.DeploymentClientRegistry
+	// VPAGuardrails makes the controller requeue the recommendations of the workloads whose VerticalPodAutoscalers
+	// change their recommendations, which resize the pods the recommendations are based on.
+	VPAGuardrails bool
 }
 
 func NewDeploymentTriggerController(client client.Client,
@@ -46,6 +51,7 @@ func NewDeploymentTriggerController(client client.Client,
 // +kubebuilder:rbac:groups=your-group.io,resources=policyrecommendations,verbs=create;get;list;watch;update;delete
 //+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch
 
 func (r *DeploymentTriggerController) Reconcile(ctx context.Context,
 	request ctrl.Request) (ctrl.Result, error) {
@@ -87,6 +93,7 @@ func (r *DeploymentTriggerController) requeuePolicyRecommendation(ctx context.Co
 
 	policyRecommendation.Spec.QueuedForExecution = &trueBool
 	policyRecommendation.Spec.QueuedForExecutionAt = &now
+	trigger.SetQueuePriority(policyRecommendation, false)
 
 	err = r.Client.Update(context.Background(), policyRecommendation, client.FieldOwner(DeploymentTriggerCtrlName))
 	if err != nil {
@@ -118,6 +125,11 @@ func (r *DeploymentTriggerController) SetupWithManager(mgr ctrl.Manager) error {
 				return true
 			}
 
+			// the recommendation of a workload is skipped while it's inactive, so requeue it once it's active again
+			if registry.WorkloadInactiveReason(oldObj) != "" && registry.WorkloadInactiveReason(newObj) == "" {
+				return true
+			}
+
 			return false
 		},
 		GenericFunc: func(e event.GenericEvent) bool {
@@ -141,5 +153,35 @@ func (r *DeploymentTriggerController) SetupWithManager(mgr ctrl.Manager) error {
 		)
 	}
 
+	if r.VPAGuardrails {
+		vpaRecommendationPredicate := predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return false
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return autoscaler.VPARecommendationChanged(e.ObjectOld, e.ObjectNew)
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		}
+		vpaEnqueueFunc := func(ctx context.Context, obj client.Object) []reconcile.Request {
+			targetName := autoscaler.VPATargetName(obj)
+			if targetName == "" {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: targetName,
+				Namespace: obj.GetNamespace()}}}
+		}
+		controllerBuilder.Watches(
+			autoscaler.NewVPAObject(),
+			handler.EnqueueRequestsFromMapFunc(vpaEnqueueFunc),
+			builder.WithPredicates(vpaRecommendationPredicate),
+		)
+	}
+
 	return controllerBuilder.Complete(r)
 }
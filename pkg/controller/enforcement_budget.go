@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// EnforcementBudgetExhaustedReason marks the policyrecos whose first enforcement is held back as the enforcement
+	// budget of the day is spent on the workloads projected to save more.
+	EnforcementBudgetExhaustedReason = "EnforcementBudgetExhausted"
+	// enforcementBudgetWindow is the window the enforcement budget is spent over.
+	enforcementBudgetWindow = 24 * time.Hour
+	// enforcementBudgetRequeueInterval is how often a workload held back while the budget isn't spent yet, i.e. for
+	// the workloads ranked above it, is reconciled again.
+	enforcementBudgetRequeueInterval = time.Minute
+)
+
+var (
+	hpaenforcerBudgetHeldCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_enforcement_budget_held_count",
+			Help: "Number of first enforcements held back by the daily enforcement budget"}, []string{"namespace", "policyreco"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(hpaenforcerBudgetHeldCounter)
+}
+
+// projectedSavings returns the projected savings percent of the policyreco, 0 if it isn't projected.
+func projectedSavings(policyreco v1alpha1.PolicyRecommendation) int {
+	if policyreco.Status.ProjectedSavingsPercent == nil {
+		return 0
+	}
+	return *policyreco.Status.ProjectedSavingsPercent
+}
+
+// budgetRank returns the rank of the policyreco among the candidates waiting for their first enforcement, ordered by
+// their projected savings, descending, and then by their namespace and name so that the order is stable.
+func budgetRank(policyreco v1alpha1.PolicyRecommendation, candidates []v1alpha1.PolicyRecommendation) int {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if si, sj := projectedSavings(candidates[i]), projectedSavings(candidates[j]); si != sj {
+			return si > sj
+		}
+		if candidates[i].Namespace != candidates[j].Namespace {
+			return candidates[i].Namespace < candidates[j].Namespace
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	for i, candidate := range candidates {
+		if candidate.Namespace == policyreco.Namespace && candidate.Name == policyreco.Name {
+			return i
+		}
+	}
+	return len(candidates)
+}
+
+// isBudgetCandidate tells whether the policyreco waits for its first enforcement, i.e. its recommendation is
+// generated and the enforcer didn't skip its workload for a reason other than the budget.
+func (r *HPAEnforcementController) isBudgetCandidate(policyreco v1alpha1.PolicyRecommendation) bool {
+	if !r.isWhitelistedNamespace(policyreco.Namespace) || !isRecoGenerated(policyreco.Status.Conditions) {
+		return false
+	}
+	enforced := findCondition(policyreco.Status.Conditions, v1alpha1.HPAEnforced)
+	return len(enforced.Type) == 0 || enforced.Reason == EnforcementBudgetExhaustedReason
+}
+
+// holdForEnforcementBudget tells whether the first enforcement of the workload is to be held back by the enforcement
+// budget: the workloads get their first autoscaler managed by ottoscalr in the descending order of their projected
+// savings, no more than EnforcementBudget of them in any day. The workloads which already have one are never held. It
+// also returns when the workload is to be reconciled again.
+func (r *HPAEnforcementController) holdForEnforcementBudget(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	workload client.Object, now time.Time) (bool, time.Duration, error) {
+	labelSelector, err := labels.Parse(fmt.Sprintf("%s=%s", createdByLabelKey, createdByLabelValue))
+	if err != nil {
+		return false, 0, err
+	}
+	autoscalerObjects, err := r.autoscalerClient.GetList(ctx, labelSelector, "", nil)
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return false, 0, err
+	}
+
+	managed := map[string]bool{}
+	enforcedToday := 0
+	requeueAfter := enforcementBudgetWindow
+	for _, autoscalerObject := range autoscalerObjects {
+		managed[autoscalerObject.GetNamespace()+"/"+r.autoscalerClient.GetScaleTargetName(autoscalerObject)] = true
+		if spentUntil := autoscalerObject.GetCreationTimestamp().Add(enforcementBudgetWindow); spentUntil.After(now) {
+			enforcedToday++
+			if spentUntil.Sub(now) < requeueAfter {
+				requeueAfter = spentUntil.Sub(now)
+			}
+		}
+	}
+	if managed[workload.GetNamespace()+"/"+workload.GetName()] {
+		return false, 0, nil
+	}
+
+	remaining := r.EnforcementBudget - enforcedToday
+	if remaining <= 0 {
+		return true, requeueAfter, nil
+	}
+
+	policyrecos := v1alpha1.PolicyRecommendationList{}
+	if err := r.List(ctx, &policyrecos); err != nil {
+		return false, 0, err
+	}
+	var candidates []v1alpha1.PolicyRecommendation
+	for _, candidate := range policyrecos.Items {
+		if !managed[candidate.Namespace+"/"+candidate.Spec.WorkloadMeta.Name] && r.isBudgetCandidate(candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if budgetRank(policyreco, candidates) < remaining {
+		return false, 0, nil
+	}
+	return true, enforcementBudgetRequeueInterval, nil
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Enforcement budget", func() {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	generated := []metav1.Condition{{Type: string(v1alpha1.RecoTaskProgress), Status: metav1.ConditionTrue,
+		Reason: RecoTaskRecommendationGenerated}}
+
+	policyreco := func(name string, savings int) *v1alpha1.PolicyRecommendation {
+		return &v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.PolicyRecommendationSpec{WorkloadMeta: v1alpha1.WorkloadMeta{Name: name}},
+			Status:     v1alpha1.PolicyRecommendationStatus{Conditions: generated, ProjectedSavingsPercent: &savings},
+		}
+	}
+	managedHPA := func(name string, createdAt time.Time) *autoscalingv1.HorizontalPodAutoscaler {
+		return &autoscalingv1.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(createdAt),
+				Labels: map[string]string{createdByLabelKey: createdByLabelValue}},
+			Spec: autoscalingv1.HorizontalPodAutoscalerSpec{ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{Name: name}},
+		}
+	}
+	newController := func(budget int, objects ...client.Object) *HPAEnforcementController {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		return &HPAEnforcementController{Client: k8sClient, autoscalerClient: autoscaler.NewHPAClient(k8sClient),
+			EnforcementBudget: budget}
+	}
+	workload := func(name string) client.Object {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	It("should rank the workloads by their projected savings", func() {
+		candidates := []v1alpha1.PolicyRecommendation{*policyreco("small", 10), *policyreco("big", 60),
+			*policyreco("b-medium", 30), *policyreco("a-medium", 30)}
+		Expect(budgetRank(*policyreco("big", 60), candidates)).To(Equal(0))
+		Expect(budgetRank(*policyreco("a-medium", 30), candidates)).To(Equal(1))
+		Expect(budgetRank(*policyreco("small", 10), candidates)).To(Equal(3))
+		Expect(budgetRank(*policyreco("unknown", 90), candidates)).To(Equal(4))
+	})
+
+	It("should only count the workloads waiting for their first enforcement", func() {
+		r := &HPAEnforcementController{}
+		Expect(r.isBudgetCandidate(*policyreco("waiting", 10))).To(BeTrue())
+		held := policyreco("held", 10)
+		held.Status.Conditions = append(held.Status.Conditions, metav1.Condition{Type: string(v1alpha1.HPAEnforced),
+			Status: metav1.ConditionFalse, Reason: EnforcementBudgetExhaustedReason})
+		Expect(r.isBudgetCandidate(*held)).To(BeTrue())
+		disabled := policyreco("disabled", 10)
+		disabled.Status.Conditions = append(disabled.Status.Conditions, metav1.Condition{Type: string(v1alpha1.HPAEnforced),
+			Status: metav1.ConditionFalse, Reason: HPAEnforcementDisabledReason})
+		Expect(r.isBudgetCandidate(*disabled)).To(BeFalse())
+		ungenerated := policyreco("ungenerated", 10)
+		ungenerated.Status.Conditions = nil
+		Expect(r.isBudgetCandidate(*ungenerated)).To(BeFalse())
+	})
+
+	It("should enforce the workloads projected to save the most within the budget of the day", func() {
+		r := newController(2, policyreco("small", 10), policyreco("big", 60), policyreco("medium", 30))
+		held, _, err := r.holdForEnforcementBudget(context.TODO(), *policyreco("big", 60), workload("big"), now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+		held, _, err = r.holdForEnforcementBudget(context.TODO(), *policyreco("medium", 30), workload("medium"), now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+		held, requeueAfter, err := r.holdForEnforcementBudget(context.TODO(), *policyreco("small", 10), workload("small"), now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeTrue())
+		Expect(requeueAfter).To(Equal(enforcementBudgetRequeueInterval))
+	})
+
+	It("should hold the first enforcements once the budget of the day is spent", func() {
+		r := newController(2, policyreco("small", 10), policyreco("big", 60), policyreco("medium", 30),
+			managedHPA("big", now.Add(-2*time.Hour)), managedHPA("medium", now.Add(-time.Hour)))
+		held, _, err := r.holdForEnforcementBudget(context.TODO(), *policyreco("big", 60), workload("big"), now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+		held, requeueAfter, err := r.holdForEnforcementBudget(context.TODO(), *policyreco("small", 10), workload("small"), now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeTrue())
+		Expect(requeueAfter).To(Equal(22 * time.Hour))
+
+		held, _, err = r.holdForEnforcementBudget(context.TODO(), *policyreco("small", 10), workload("small"), now.Add(23*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+	})
+})
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	EnforcementPreviewStatusManager = "EnforcementPreviewStatusManager"
+	// EnforcementPreviewedReason marks the policyrecos whose change of the autoscaler is held back while it's previewed
+	// in their status.
+	EnforcementPreviewedReason = "EnforcementPreviewed"
+)
+
+var (
+	hpaenforcerEnforcementPreviewedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_enforcement_previewed_count",
+			Help: "Number of changes of the autoscalers previewed in the status of the policyrecos before applying them"}, []string{"namespace", "policyreco"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(hpaenforcerEnforcementPreviewedCounter)
+}
+
+// EnforcementPreviewer previews the change the HPA enforcer would apply to the autoscaler of the workload of a
+// policyreco to enforce a config.
+type EnforcementPreviewer interface {
+	PreviewEnforcement(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+		config v1alpha1.HPAConfiguration) (*v1alpha1.EnforcementPreview, error)
+}
+
+// PreviewEnforcement previews the change of the autoscaler of the workload of the policyreco to enforce the config,
+// as is, i.e. without the holds of its min replicas. It returns nil if the autoscaler doesn't change or the autoscaler
+// client can't preview it.
+func (r *HPAEnforcementController) PreviewEnforcement(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	config v1alpha1.HPAConfiguration) (*v1alpha1.EnforcementPreview, error) {
+	object, err := r.clientsRegistry.GetObjectClient(policyreco.Spec.WorkloadMeta.Kind)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := object.GetObject(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	minRequiredReplicas := r.MinRequiredReplicas
+	if r.ConfigResolver != nil {
+		workloadConfig, err := r.ConfigResolver.Resolve(ctx, policyreco.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if workloadConfig != nil && workloadConfig.ScaledObjectTemplate != nil {
+			ctx = autoscaler.ContextWithScaledObjectTemplate(ctx, workloadConfig.ScaledObjectTemplate)
+		}
+		if workloadConfig != nil && workloadConfig.MinRequiredReplicas != nil {
+			minRequiredReplicas = *workloadConfig.MinRequiredReplicas
+		}
+	}
+	target := autoscaler.MetricTarget{
+		Name:  config.GetMetricName(),
+		Type:  string(config.GetTargetMetricType()),
+		Value: int32(config.TargetMetricValue),
+	}
+	min, triggers := r.applyIdleWindow(policyreco, int32(config.Min), minRequiredReplicas, cronTriggers(policyreco.Spec.CronTriggers))
+	return r.previewEnforcement(ctx, workload, map[string]string{createdByLabelKey: createdByLabelValue},
+		int32(config.Max), min, target, triggers, time.Now())
+}
+
+// previewEnforcement previews the change CreateOrUpdateAutoscaler would apply to the autoscaler of the workload. It
+// returns nil if the autoscaler doesn't change or the autoscaler client can't preview it.
+func (r *HPAEnforcementController) previewEnforcement(ctx context.Context, workload client.Object,
+	labels map[string]string, max int32, min int32, target autoscaler.MetricTarget,
+	cronTriggers []autoscaler.CronTrigger, now time.Time) (*v1alpha1.EnforcementPreview, error) {
+	previewer, ok := r.autoscalerClient.(autoscaler.AutoscalerPreviewer)
+	if !ok {
+		return nil, nil
+	}
+	current, desired, err := previewer.PreviewAutoscaler(ctx, workload, labels, max, min, target, cronTriggers)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := autoscaler.PreviewPatch(current, desired)
+	if err != nil || len(patch) == 0 {
+		return nil, err
+	}
+	return &v1alpha1.EnforcementPreview{
+		Autoscaler:  fmt.Sprintf("%s/%s", r.autoscalerClient.GetName(), workload.GetName()),
+		Patch:       patch,
+		PreviewedAt: metav1.NewTime(now),
+	}, nil
+}
+
+// holdForEnforcementPreview tells whether the change of the autoscaler of the workload is to be held back while it's
+// previewed in the status of the policyreco. A change is recorded in the status the first time it's previewed and
+// applied once it's been previewed for EnforcementPreview, so that it can be reviewed, and vetoed, before it's applied.
+// A change which changes while it's held back is previewed afresh. It also returns when the workload is to be
+// reconciled again.
+func (r *HPAEnforcementController) holdForEnforcementPreview(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	workload client.Object, labels map[string]string, max int32, min int32, target autoscaler.MetricTarget,
+	cronTriggers []autoscaler.CronTrigger, now time.Time) (bool, time.Duration, error) {
+	preview, err := r.previewEnforcement(ctx, workload, labels, max, min, target, cronTriggers, now)
+	if err != nil || preview == nil {
+		return false, 0, err
+	}
+	if previewed := policyreco.Status.EnforcementPreview; previewed != nil &&
+		previewed.Autoscaler == preview.Autoscaler && previewed.Patch == preview.Patch {
+		applyAt := previewed.PreviewedAt.Add(r.EnforcementPreview)
+		if now.Before(applyAt) {
+			return true, applyAt.Sub(now), nil
+		}
+		return false, 0, nil
+	}
+	if err := r.Status().Patch(ctx, createEnforcementPreviewPatch(policyreco, preview), client.Apply,
+		getSubresourcePatchOptions(EnforcementPreviewStatusManager)); err != nil {
+		return false, 0, err
+	}
+	hpaenforcerEnforcementPreviewedCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+	r.Recorder.Event(&policyreco, eventTypeNormal, EnforcementPreviewedReason,
+		fmt.Sprintf("The %s is changed by %s after %s", preview.Autoscaler, preview.Patch, r.EnforcementPreview))
+	return true, r.EnforcementPreview, nil
+}
+
+// createEnforcementPreviewPatch creates a status patch recording the preview of the change of the autoscaler of the
+// policyreco.
+func createEnforcementPreviewPatch(policyreco v1alpha1.PolicyRecommendation, preview *v1alpha1.EnforcementPreview) *v1alpha1.PolicyRecommendation {
+	return &v1alpha1.PolicyRecommendation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "PolicyRecommendation",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyreco.Name,
+			Namespace: policyreco.Namespace,
+		},
+		Status: v1alpha1.PolicyRecommendationStatus{EnforcementPreview: preview},
+	}
+}
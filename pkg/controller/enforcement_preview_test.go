@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Enforcement preview", func() {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	preview := 30 * time.Minute
+	workload := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+	}
+	labels := map[string]string{createdByLabelKey: createdByLabelValue}
+	target := autoscaler.MetricTarget{Name: "cpu", Type: autoscaler.UtilizationTargetType, Value: 60}
+	minCutPatch := `{"spec":{"minReplicas":3}}`
+
+	previewed := func(patch string, previewedAt time.Time) v1alpha1.PolicyRecommendation {
+		return v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{WorkloadMeta: v1alpha1.WorkloadMeta{
+				TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "app"}},
+			Status: v1alpha1.PolicyRecommendationStatus{EnforcementPreview: &v1alpha1.EnforcementPreview{
+				Autoscaler: "HPA/app", Patch: patch, PreviewedAt: metav1.NewTime(previewedAt)}},
+		}
+	}
+	newController := func() *HPAEnforcementController {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workload.DeepCopy()).Build()
+		autoscalerClient := autoscaler.NewHPAClient(k8sClient)
+		_, err := autoscalerClient.CreateOrUpdateAutoscaler(context.TODO(), workload, labels, 10, 5, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		return &HPAEnforcementController{Client: k8sClient, autoscalerClient: autoscalerClient,
+			clientsRegistry: *clientsRegistry, EnforcementPreview: preview}
+	}
+
+	It("should preview the change of the autoscaler of a config", func() {
+		r := newController()
+		enforcementPreview, err := r.PreviewEnforcement(context.TODO(), previewed("", now),
+			v1alpha1.HPAConfiguration{Min: 3, Max: 10, TargetMetricValue: 60})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enforcementPreview.Autoscaler).To(Equal("HPA/app"))
+		Expect(enforcementPreview.Patch).To(Equal(minCutPatch))
+	})
+
+	It("should hold the change till it's been previewed long enough", func() {
+		r := newController()
+		held, requeueAfter, err := r.holdForEnforcementPreview(context.TODO(), previewed(minCutPatch, now.Add(-10*time.Minute)),
+			workload, labels, 10, 3, target, nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeTrue())
+		Expect(requeueAfter).To(Equal(20 * time.Minute))
+	})
+
+	It("should apply the change once it's been previewed long enough", func() {
+		r := newController()
+		held, _, err := r.holdForEnforcementPreview(context.TODO(), previewed(minCutPatch, now.Add(-preview)),
+			workload, labels, 10, 3, target, nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+	})
+
+	It("should not hold the autoscalers which don't change", func() {
+		r := newController()
+		held, _, err := r.holdForEnforcementPreview(context.TODO(), previewed(minCutPatch, now), workload, labels,
+			10, 5, target, nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+	})
+})
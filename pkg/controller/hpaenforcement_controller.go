@@ -20,28 +20,41 @@ import (
 	"context"
 	"fmt"
 	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	ottoscaleriov1beta1 "github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/audit"
 	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
 	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
 	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/silence"
+	"github.com/flipkart-incubator/ottoscalr/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"math"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"strconv"
+	"time"
 )
 
 const (
@@ -51,6 +64,10 @@ const (
 	hpaEnforcementDisabledAnnotation = "ottoscalr.io/skip-hpa-enforcement"
 	hpaEnforcementEnabledAnnotation  = "ottoscalr.io/enable-hpa-enforcement"
 	rolloutWaveAnnotation            = "ottoscalr.io/rollout-wave"
+	// baselineReplicasAnnotation records on the policyreco the replicas the workload ran at before its autoscaler was
+	// created, which the realized savings are measured against.
+	baselineReplicasAnnotation = "ottoscalr.io/baseline-replicas"
+	VPAConflictStatusManager   = "VPAConflictStatusManager"
 )
 
 var (
@@ -58,12 +75,19 @@ var (
 	policyRecoOwnerField          = ".spec.workloadOwner"
 	HPAEnforcedReason             = "ScaledObjectIsCreated"
 	HPAEnforcedMessage            = "ScaledObject has been created."
+	NoOpEnforcedReason            = "NoOpConfigEnforced"
+	NoOpEnforcedMessage           = "ScaledObject has been created with the no-op configuration as the workload couldn't be recommended a config."
 	AutoscalerExistsReason        = "UserCreatedScaledObjectAlreadyExists"
 	AutoscalerExistsMessage       = "User managed ScaledObject already exists for this workload."
 	InvalidPolicyRecoReason       = "InvalidPolicyRecoConfig"
 	InvalidPolicyRecoMessage      = "HPA config in the PolicyRecommendation doesn't qualify for the ScaledObject creation criteria."
 	HPAEnforcementDisabledReason  = "HPAEnforcementDisabled"
 	HPAEnforcementDisabledMessage = "HPA enforcement disabled for this workload"
+	VPAConflictReason             = "VPAConflict"
+	VPACompatibleReason           = "VPACompatible"
+	VPACompatibleMessage          = "No VerticalPodAutoscaler resizes the metric the workload is autoscaled on"
+	RecommendationPinnedReason    = "RecommendationPinned"
+	RecommendationPinnedMessage   = "HPA enforcement skipped as the workload is pinned to an HPA configuration"
 )
 
 var (
@@ -81,10 +105,31 @@ var (
 		prometheus.CounterOpts{Name: "hpaenforcer_autoscaler_deleted_count",
 			Help: "Number of scaled objects created/updated by HPAEnforcer"}, []string{"namespace", "policyreco", "autoscaler"},
 	)
+
+	hpaenforcerRealizedSavings = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "hpaenforcer_realized_savings_percent",
+			Help: "Savings percentage of the current replicas of an autoscaled workload over the replicas it ran at before it was autoscaled"}, []string{"namespace", "workload", "kind"},
+	)
+
+	hpaenforcerVPAConflicts = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "hpaenforcer_vpa_conflict",
+			Help: "Boolean to show if a VerticalPodAutoscaler resizes the metric the workload is autoscaled on"}, []string{"namespace", "workload", "kind"},
+	)
+
+	hpaenforcerMinCutsHeldCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_min_cuts_held_count",
+			Help: "Number of min replicas cuts held back while a VerticalPodAutoscaler evicts the pods of the workload"}, []string{"namespace", "policyreco"},
+	)
+
+	hpaenforcerLowConfidenceCutsHeldCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_low_confidence_cuts_held_count",
+			Help: "Number of min replicas cuts held back as the confidence of the recommendation is below the min confidence"}, []string{"namespace", "policyreco"},
+	)
 )
 
 func init() {
-	metrics.Registry.MustRegister(hpaenforcerAutoscalerObjectUpdatedCounter, hpaenforcerAutoscalerObjectDeletedCounter, hpaenforcerReconcileCounter)
+	metrics.Registry.MustRegister(hpaenforcerAutoscalerObjectUpdatedCounter, hpaenforcerAutoscalerObjectDeletedCounter, hpaenforcerReconcileCounter,
+		hpaenforcerRealizedSavings, hpaenforcerVPAConflicts, hpaenforcerMinCutsHeldCounter, hpaenforcerLowConfidenceCutsHeldCounter)
 }
 
 type HPAEnforcementController struct {
@@ -99,14 +144,43 @@ type HPAEnforcementController struct {
 	WhitelistMode           *bool
 	MinRequiredReplicas     int
 	autoscalerClient        autoscaler.AutoscalerClient
+	auditor                 audit.Auditor
+	notifier                notifier.Notifier
+
+	// VPAGuardrails makes the controller check the workloads for VerticalPodAutoscalers before autoscaling them.
+	VPAGuardrails bool
+	// ConfigResolver makes the controller enforce the recommendations with the OttoscalrConfigs of the namespaces.
+	ConfigResolver *reco.ConfigResolver
+	// MinConfidencePercent makes the controller hold back the cuts of the min replicas of the autoscalers it manages
+	// while the confidence of the recommendations of their workloads is below it.
+	MinConfidencePercent int
+	// DependencyOrdering makes the controller hold back the cuts of the min replicas of the workloads till their callers
+	// and their groups apply their recommendations.
+	DependencyOrdering bool
+	// EnforcementBudget makes the controller create the first autoscalers of no more than this many workloads a day,
+	// in the descending order of their projected savings. The default of 0 doesn't limit them.
+	EnforcementBudget int
+	// ShadowAdoption makes the controller shadow the HPAs the owners of the workloads created before adopting the
+	// workloads from them.
+	ShadowAdoption *ShadowAdoption
+	// EnforcementPreview makes the controller preview every change of the autoscalers in the status of the policyrecos
+	// for this long before applying it. The default of 0 applies the changes right away.
+	EnforcementPreview time.Duration
+	// IdleWindows makes the controller lower the min replicas of the ScaledObjects in the idle windows of the
+	// policyrecos of their workloads.
+	IdleWindows bool
+	// AlertSilencer makes the controller silence the capacity alerts of the workloads for a while after changing their
+	// autoscalers, so that the expected drop of their replicas doesn't page the on-call.
+	AlertSilencer silence.Silencer
 }
 
 func NewHPAEnforcementController(client client.Client,
-	scheme *runtime.Scheme,clientsRegistry registry.DeploymentClientRegistry, recorder record.EventRecorder,
-	maxConcurrentReconciles int, isDryRun *bool, excludedNamespaces *[]string, includedNamespaces *[]string, whitelistMode *bool, minRequiredReplicas int, autoscalerClient autoscaler.AutoscalerClient) (*HPAEnforcementController, error) {
+	scheme *runtime.Scheme, clientsRegistry registry.DeploymentClientRegistry, recorder record.EventRecorder,
+	maxConcurrentReconciles int, isDryRun *bool, excludedNamespaces *[]string, includedNamespaces *[]string, whitelistMode *bool, minRequiredReplicas int, autoscalerClient autoscaler.AutoscalerClient, auditor audit.Auditor, notifier notifier.Notifier) (*HPAEnforcementController, error) {
 
 	HPAEnforcedReason = fmt.Sprintf("%sIsCreated", autoscalerClient.GetName())
 	HPAEnforcedMessage = fmt.Sprintf("%s has been created.", autoscalerClient.GetName())
+	NoOpEnforcedMessage = fmt.Sprintf("%s has been created with the no-op configuration as the workload couldn't be recommended a config.", autoscalerClient.GetName())
 	AutoscalerExistsReason = fmt.Sprintf("UserCreated%sAlreadyExists", autoscalerClient.GetName())
 	AutoscalerExistsMessage = fmt.Sprintf("User managed %s already exists for this workload.", autoscalerClient.GetName())
 	InvalidPolicyRecoMessage = fmt.Sprintf("HPA config in the PolicyRecommendation doesn't qualify for the %s creation criteria.", autoscalerClient.GetName())
@@ -123,6 +197,8 @@ func NewHPAEnforcementController(client client.Client,
 		WhitelistMode:           whitelistMode,
 		MinRequiredReplicas:     minRequiredReplicas,
 		autoscalerClient:        autoscalerClient,
+		auditor:                 auditor,
+		notifier:                notifier,
 	}, nil
 }
 
@@ -130,8 +206,16 @@ func NewHPAEnforcementController(client client.Client,
 //+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=ottoscalrconfigs,verbs=get;list;watch
 
-func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "HPAEnforcementController.Reconcile",
+		trace.WithAttributes(attribute.String("ottoscalr.policyreco", req.NamespacedName.String())))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
 
 	logger := ctrl.LoggerFrom(ctx).WithName(HPAEnforcementCtrlName)
 
@@ -147,15 +231,38 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 	policyreco := v1alpha1.PolicyRecommendation{}
 	if err := r.Get(ctx, req.NamespacedName, &policyreco); err != nil {
 		logger.V(0).Error(err, "Error fetching PolicyRecommendation resource.")
+		if errors.IsNotFound(err) {
+			// the policyreco is named after its workload
+			hpaenforcerRealizedSavings.DeletePartialMatch(prometheus.Labels{"namespace": req.Namespace, "workload": req.Name})
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	hpaenforcerReconcileCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
 
+	// the autoscalers of the namespace go along with it, creating or updating them only fails
+	if terminating, err := skipsTerminatingNamespace(ctx, r.Client, policyreco.Namespace, HPAEnforcementCtrlName); err != nil || terminating {
+		if terminating {
+			logger.V(1).Info("Skipping policy enforcement as the namespace is terminating.")
+		}
+		return ctrl.Result{}, err
+	}
+
 	if !isInitialized(policyreco.Status.Conditions) || !isRecoGenerated(policyreco.Status.Conditions) {
 		logger.V(0).Info("Skipping policy enforcement as the policy recommendation is not initialized.")
 		return ctrl.Result{}, nil
 	}
 
+	// the autoscaler of a pinned workload is left as it is, whoever manages it
+	if policyreco.Spec.PinnedHPAConfiguration != nil {
+		logger.V(0).Info("Skipping policy enforcement as the workload is pinned to an HPA configuration.")
+		statusPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.HPAEnforced, metav1.ConditionFalse, RecommendationPinnedReason, RecommendationPinnedMessage)
+		if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		return ctrl.Result{}, nil
+	}
+
 	object, err := r.clientsRegistry.GetObjectClient(policyreco.Spec.WorkloadMeta.Kind)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -191,9 +298,44 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
-	if policyreco.Spec.CurrentHPAConfiguration.Max <= r.MinRequiredReplicas || policyreco.Spec.CurrentHPAConfiguration.Min <= r.MinRequiredReplicas || policyreco.Spec.CurrentHPAConfiguration.Min > policyreco.Spec.CurrentHPAConfiguration.Max {
+	minRequiredReplicas, isDryRun := r.MinRequiredReplicas, *r.isDryRun
+	var config *reco.WorkloadConfig
+	if r.ConfigResolver != nil {
+		if config, err = r.ConfigResolver.Resolve(ctx, policyreco.Namespace); err != nil {
+			logger.V(0).Error(err, "Error resolving the OttoscalrConfig of the namespace.")
+			return ctrl.Result{}, err
+		}
+	}
+	if config != nil {
+		if config.ScaledObjectTemplate != nil {
+			ctx = autoscaler.ContextWithScaledObjectTemplate(ctx, config.ScaledObjectTemplate)
+		}
+		if config.MinRequiredReplicas != nil {
+			minRequiredReplicas = *config.MinRequiredReplicas
+		}
+		switch config.EnforcementMode {
+		case ottoscaleriov1beta1.EnforcementModeEnforce:
+			isDryRun = false
+		case ottoscaleriov1beta1.EnforcementModeDryRun:
+			isDryRun = true
+		case ottoscaleriov1beta1.EnforcementModeDisabled:
+			logger.V(0).Info("HPA enforcement is disabled for the workloads in the namespace by its OttoscalrConfig. Skipping.", "workload", workload.GetName(), "namespace", workload.GetNamespace(), "config", config.Name)
+			if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, HPAEnforcementDisabledReason, logger); err != nil {
+				return ctrl.Result{}, err
+			}
+			message := fmt.Sprintf("HPA enforcement disabled for the workloads in this namespace by the OttoscalrConfig %s", config.Name)
+			statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, HPAEnforcementDisabledReason, message)
+			if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
+				logger.Error(err, "Error updating the status of the policy reco object")
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if policyreco.Spec.CurrentHPAConfiguration.Max <= minRequiredReplicas || policyreco.Spec.CurrentHPAConfiguration.Min <= minRequiredReplicas || policyreco.Spec.CurrentHPAConfiguration.Min > policyreco.Spec.CurrentHPAConfiguration.Max {
 		logger.V(0).Info("Skipping enforcing autoscaling policy due to less max/min pods in the target reco generated.", "workload", workload, "namespace", workload.GetNamespace(), "kind", workload.GetObjectKind())
-		if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, logger); err != nil {
+		if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, InvalidPolicyRecoReason, logger); err != nil {
 			return ctrl.Result{}, err
 		}
 		statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, InvalidPolicyRecoReason, InvalidPolicyRecoMessage)
@@ -210,7 +352,7 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 		if v, ok := workload.GetAnnotations()[hpaEnforcementEnabledAnnotation]; ok {
 			if allow, _ := strconv.ParseBool(v); !allow {
 				logger.V(0).Info("HPA enforcement is disabled for this workload as it's not marked with ottoscalr.io/enable-hpa-enforcement: true . Skipping.", "workload", workload, "namespace", workload.GetNamespace(), "kind", workload.GetObjectKind())
-				if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, logger); err != nil {
+				if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, HPAEnforcementDisabledReason, logger); err != nil {
 					return ctrl.Result{}, err
 				}
 				statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, HPAEnforcementDisabledReason, HPAEnforcementDisabledMessage)
@@ -223,7 +365,7 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 			// else continue with autoscaler creation
 		} else {
 			logger.V(0).Info("HPA enforcement is disabled for this workload as it's not marked with ottoscalr.io/enable-hpa-enforcement: true . Skipping.", "workload", workload, "namespace", workload.GetNamespace(), "kind", workload.GetObjectKind())
-			if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, logger); err != nil {
+			if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, HPAEnforcementDisabledReason, logger); err != nil {
 				return ctrl.Result{}, err
 			}
 			statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, HPAEnforcementDisabledReason, HPAEnforcementDisabledMessage)
@@ -237,7 +379,7 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 		if v, ok := workload.GetAnnotations()[hpaEnforcementDisabledAnnotation]; ok {
 			if disallow, _ := strconv.ParseBool(v); disallow {
 				logger.V(0).Info("HPA enforcement is disabled for this workload as it's marked with ottoscalr.io/skip-hpa-enforcement: true . Skipping.", "workload", workload, "namespace", workload.GetNamespace(), "kind", workload.GetObjectKind())
-				if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, logger); err != nil {
+				if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, HPAEnforcementDisabledReason, logger); err != nil {
 					return ctrl.Result{}, err
 				}
 				statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, HPAEnforcementDisabledReason, HPAEnforcementDisabledMessage)
@@ -258,18 +400,207 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 
 	min := int32(policyreco.Spec.CurrentHPAConfiguration.Min)
 	max := int32(policyreco.Spec.CurrentHPAConfiguration.Max)
-	targetCPU := int32(policyreco.Spec.CurrentHPAConfiguration.TargetMetricValue)
+	target := autoscaler.MetricTarget{
+		Name:  policyreco.Spec.CurrentHPAConfiguration.GetMetricName(),
+		Type:  string(policyreco.Spec.CurrentHPAConfiguration.GetTargetMetricType()),
+		Value: int32(policyreco.Spec.CurrentHPAConfiguration.TargetMetricValue),
+	}
+
+	if r.VPAGuardrails {
+		vpa, err := autoscaler.FindVPA(ctx, r.Client, workload, corev1.ResourceName(target.GetName()))
+		if err != nil {
+			logger.V(0).Error(err, "Error finding the VerticalPodAutoscaler of the workload.")
+			return ctrl.Result{}, err
+		}
+		if vpaPatch := createVPAConflictPatch(policyreco, vpa, target.GetName()); vpaPatch != nil {
+			if err := r.Status().Patch(ctx, vpaPatch, client.Apply, getSubresourcePatchOptions(VPAConflictStatusManager)); err != nil {
+				logger.Error(err, "Error updating the status of the policy reco object")
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+		}
+		if vpa != nil && vpa.Conflicting {
+			hpaenforcerVPAConflicts.WithLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind).Set(1)
+			message := vpaConflictMessage(vpa, target.GetName())
+			logger.V(0).Info("Skipping enforcing autoscaling policy as a VerticalPodAutoscaler resizes the metric the workload is autoscaled on.", "workload", workload.GetName(), "namespace", workload.GetNamespace(), "vpa", vpa.Name)
+			if err := r.deleteControllerManagedAutoscaler(ctx, policyreco, workload, VPAConflictReason, logger); err != nil {
+				return ctrl.Result{}, err
+			}
+			statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, VPAConflictReason, message)
+			if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
+				logger.Error(err, "Error updating the status of the policy reco object")
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+			r.Recorder.Event(&policyreco, eventTypeWarning, VPAConflictReason, message)
+			reco.CountNoOpRecommendation(policyreco.Namespace, reco.NoOpConflicted)
+			return ctrl.Result{}, nil
+		}
+		hpaenforcerVPAConflicts.DeleteLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind)
+		if vpa != nil && vpa.Evicting {
+			heldMin, err := r.holdMinReplicasCut(ctx, workload, min)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if heldMin != min {
+				logger.V(0).Info("Holding back the cut of the min replicas while the VerticalPodAutoscaler evicts the pods of the workload.", "workload", workload.GetName(), "vpa", vpa.Name, "min", heldMin, "recommendedMin", min)
+				hpaenforcerMinCutsHeldCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+				min = heldMin
+			}
+		}
+	}
+
+	if confidence := policyreco.Status.ConfidencePercent; confidence != nil && *confidence < r.MinConfidencePercent {
+		heldMin, err := r.managedMinReplicas(ctx, workload, min)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if heldMin > max {
+			heldMin = max
+		}
+		if heldMin != min {
+			logger.V(0).Info("Holding back the cut of the min replicas as the confidence of the recommendation is below the min confidence.", "workload", workload.GetName(), "confidence", *confidence, "minConfidence", r.MinConfidencePercent, "min", heldMin, "recommendedMin", min)
+			hpaenforcerLowConfidenceCutsHeldCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+			min = heldMin
+		}
+	}
+
+	var requeueAfter time.Duration
+	if r.DependencyOrdering {
+		heldMin, err := r.managedMinReplicas(ctx, workload, min)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if heldMin > max {
+			heldMin = max
+		}
+		if heldMin != min {
+			pending, err := r.pendingCoordinatedWorkload(ctx, workload)
+			if err != nil {
+				logger.V(0).Error(err, "Error checking the callers and the group of the workload.")
+				return ctrl.Result{}, err
+			}
+			if pending != "" {
+				logger.V(0).Info("Holding back the cut of the min replicas till the callers and the group of the workload apply their recommendations.", "workload", workload.GetName(), "pending", pending, "min", heldMin, "recommendedMin", min)
+				hpaenforcerDependencyCutsHeldCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+				min = heldMin
+				requeueAfter = dependencyRequeueInterval
+			}
+		}
+	}
+
+	if config != nil && config.MinCapacityReservation > 0 {
+		reservedMin, err := r.capacityReservationMinReplicas(ctx, object, workload, min, config.MinCapacityReservation)
+		if err != nil {
+			logger.V(0).Error(err, "Error computing the capacity of the namespace for its capacity reservation.")
+			return ctrl.Result{}, err
+		}
+		if reservedMin != min {
+			logger.V(0).Info("Holding back the cut of the min replicas to keep the capacity reservation of the namespace.", "workload", workload.GetName(), "reservation", config.MinCapacityReservation, "config", config.Name, "min", reservedMin, "recommendedMin", min)
+			hpaenforcerCapacityReservationCutsHeldCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+			min = reservedMin
+		}
+	}
+
+	if !isDryRun && r.ShadowAdoption != nil {
+		held, message, shadowRequeueAfter, err := r.holdForShadowAdoption(ctx, policyreco, workload, time.Now())
+		if err != nil {
+			logger.V(0).Error(err, "Error shadowing the HPA of the workload.")
+			return ctrl.Result{}, err
+		}
+		if held {
+			logger.V(0).Info("Holding back the adoption of the workload while shadowing the HPA its owners created.", "workload", workload.GetName(), "reason", message)
+			hpaenforcerShadowAdoptionHeldCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+			statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, ShadowAdoptionReason, message)
+			if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
+				logger.Error(err, "Error updating the status of the policy reco object")
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+			return ctrl.Result{RequeueAfter: shadowRequeueAfter}, nil
+		}
+	}
+
+	if !isDryRun && r.EnforcementBudget > 0 {
+		held, budgetRequeueAfter, err := r.holdForEnforcementBudget(ctx, policyreco, workload, time.Now())
+		if err != nil {
+			logger.V(0).Error(err, "Error checking the enforcement budget.")
+			return ctrl.Result{}, err
+		}
+		if held {
+			logger.V(0).Info("Holding back the enforcement of the workload as the enforcement budget of the day is spent on the workloads projected to save more.", "workload", workload.GetName(), "budget", r.EnforcementBudget)
+			hpaenforcerBudgetHeldCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+			message := fmt.Sprintf("Enforcement held back as the budget of %d workloads a day is spent on the workloads projected to save more.", r.EnforcementBudget)
+			statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, EnforcementBudgetExhaustedReason, message)
+			if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
+				logger.Error(err, "Error updating the status of the policy reco object")
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+			return ctrl.Result{RequeueAfter: budgetRequeueAfter}, nil
+		}
+	}
 
-	if !*r.isDryRun {
+	min, triggers := r.applyIdleWindow(policyreco, min, minRequiredReplicas, cronTriggers(policyreco.Spec.CronTriggers))
+
+	if !isDryRun && r.EnforcementPreview > 0 {
+		held, previewRequeueAfter, err := r.holdForEnforcementPreview(ctx, policyreco, workload, labels, max, min, target,
+			triggers, time.Now())
+		if err != nil {
+			logger.V(0).Error(err, "Error previewing the change of the "+r.autoscalerClient.GetName())
+			return ctrl.Result{}, err
+		}
+		if held {
+			logger.V(0).Info("Holding back the change of the "+r.autoscalerClient.GetName()+" while it's previewed.", "workload", workload.GetName(), "preview", r.EnforcementPreview)
+			message := fmt.Sprintf("The change of the %s is previewed in the status till %s before it's applied.", r.autoscalerClient.GetName(),
+				time.Now().Add(previewRequeueAfter).Format(time.RFC3339))
+			statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionFalse, EnforcementPreviewedReason, message)
+			if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
+				logger.Error(err, "Error updating the status of the policy reco object")
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+			return ctrl.Result{RequeueAfter: previewRequeueAfter}, nil
+		}
+	}
+
+	if !isDryRun {
 
 		logger.V(0).Info("Creating/Updating "+r.autoscalerClient.GetName()+" for workload.", "workload", workload.GetName())
 
-		result, err := r.autoscalerClient.CreateOrUpdateAutoscaler(ctx, workload, labels, max, min, targetCPU)
+		// the capacity alerts are silenced only for the changes dropping the capacity, which are read before them
+		dropsCapacity := false
+		if r.AlertSilencer != nil {
+			if dropsCapacity, err = r.dropsCapacity(ctx, object, workload, max, min, target); err != nil {
+				logger.V(0).Error(err, "Error comparing the change of the "+r.autoscalerClient.GetName()+" with the current one. Silencing the capacity alerts of the workload.")
+				dropsCapacity = true
+			}
+		}
+
+		// the replicas of the workload are the baseline of the realized savings if its autoscaler is created now
+		preEnforcementReplicas, err := object.GetReplicaCount(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name)
+		if err != nil {
+			logger.V(0).Error(err, "Unable to fetch the replica count of the workload for the baseline of the realized savings.")
+		}
+
+		enforceCtx, enforceSpan := tracing.Tracer().Start(ctx, "AutoscalerClient.CreateOrUpdateAutoscaler",
+			trace.WithAttributes(tracing.WorkloadAttributes(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Kind, workload.GetName())...))
+		enforceSpan.SetAttributes(attribute.String("ottoscalr.autoscaler", r.autoscalerClient.GetName()))
+		result, err := r.autoscalerClient.CreateOrUpdateAutoscaler(enforceCtx, workload, labels, max, min, target, triggers)
+		tracing.RecordError(enforceSpan, err)
+		enforceSpan.End()
 		if err != nil {
 			logger.V(0).Error(err, "Error creating or updating "+r.autoscalerClient.GetName())
+			r.notifier.Notify(ctx, createEnforcementFailedEvent(policyreco, r.autoscalerClient.GetName(), err))
 			return ctrl.Result{}, err
 		} else {
 			hpaenforcerAutoscalerObjectUpdatedCounter.WithLabelValues(policyreco.Namespace, policyreco.Name, workload.GetName(), result).Inc()
+			if result == string(controllerutil.OperationResultCreated) && preEnforcementReplicas > 0 {
+				if err := r.recordBaselineReplicas(ctx, &policyreco, preEnforcementReplicas); err != nil {
+					logger.V(0).Error(err, "Error recording the baseline replicas of the realized savings.")
+				}
+			}
+			if result != string(controllerutil.OperationResultNone) {
+				r.auditor.Audit(ctx, createAutoscalerEnforcedAuditRecord(policyreco, result, r.autoscalerClient.GetName()))
+				if dropsCapacity {
+					r.silenceAlerts(ctx, policyreco, workload, result, logger)
+				}
+			}
 			logger.V(0).Info(fmt.Sprintf("Result of the create or update operation is '%s\n'", result))
 		}
 
@@ -278,14 +609,97 @@ func (r *HPAEnforcementController) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
-	statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionTrue, HPAEnforcedReason, HPAEnforcedMessage)
+	r.logRealizedSavings(policyreco, object, logger)
+
+	// the no-op configuration keeps the workload at its max replicas rather than autoscaling it
+	enforcedReason, enforcedMessage := HPAEnforcedReason, HPAEnforcedMessage
+	if hasCondition(policyreco, v1alpha1.NoOpRecommended) {
+		enforcedReason, enforcedMessage = NoOpEnforcedReason, NoOpEnforcedMessage
+	}
+	statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.HPAEnforced, metav1.ConditionTrue, enforcedReason, enforcedMessage)
 	if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(HPAEnforcementCtrlName)); err != nil {
 		logger.Error(err, "Error updating the status of the policy reco object")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	r.Recorder.Event(&policyreco, eventTypeNormal, r.autoscalerClient.GetName()+"Created", fmt.Sprintf("The %s has been created successfully.", r.autoscalerClient.GetName()))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// createVPAConflictPatch creates a status patch marking the policyreco with the VPAConflict condition while a VPA
+// resizes the metric its workload is autoscaled on, and unmarking it once none does. It returns nil if the condition
+// doesn't change, so that the patches don't requeue the policyreco.
+func createVPAConflictPatch(policyreco v1alpha1.PolicyRecommendation, vpa *autoscaler.VPACoexistence, metric string) *v1alpha1.PolicyRecommendation {
+	current := findCondition(policyreco.Status.Conditions, v1alpha1.VPAConflict)
+	if vpa != nil && vpa.Conflicting {
+		message := vpaConflictMessage(vpa, metric)
+		if current.Status == metav1.ConditionTrue && current.Message == message {
+			return nil
+		}
+		vpaPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.VPAConflict, metav1.ConditionTrue, VPAConflictReason, message)
+		return vpaPatch
+	}
+	if current.Status == metav1.ConditionTrue {
+		vpaPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.VPAConflict, metav1.ConditionFalse, VPACompatibleReason, VPACompatibleMessage)
+		return vpaPatch
+	}
+	return nil
+}
+
+func vpaConflictMessage(vpa *autoscaler.VPACoexistence, metric string) string {
+	return fmt.Sprintf("The VerticalPodAutoscaler %s resizes the %s of the pods the workload is autoscaled on. Autoscaling is not enforced until the VerticalPodAutoscaler stops resizing it.", vpa.Name, metric)
+}
+
+// holdMinReplicasCut returns the min replicas of the autoscaler managed for the workload if the recommended min cuts
+// it while the pods of the workload are unavailable, e.g. evicted by a VPA, so that the capacity of the workload isn't
+// cut twice at once. It returns the recommended min otherwise.
+func (r *HPAEnforcementController) holdMinReplicasCut(ctx context.Context, workload client.Object, min int32) (int32, error) {
+	unavailable, err := unavailableReplicas(workload)
+	if err != nil || unavailable <= 0 {
+		return min, err
+	}
+	return r.managedMinReplicas(ctx, workload, min)
+}
+
+// managedMinReplicas returns the min replicas of the autoscaler managed for the workload if it's more than min, so
+// that enforcing min doesn't cut it. It returns min otherwise.
+func (r *HPAEnforcementController) managedMinReplicas(ctx context.Context, workload client.Object, min int32) (int32, error) {
+	labelSelector, err := labels.Parse(fmt.Sprintf("%s=%s", createdByLabelKey, createdByLabelValue))
+	if err != nil {
+		return min, err
+	}
+	autoscalerObjects, err := r.autoscalerClient.GetList(ctx, labelSelector, workload.GetNamespace(), fields.OneTermEqualSelector(autoscalerField, workload.GetName()))
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return min, err
+	}
+	held := min
+	for _, autoscalerObject := range autoscalerObjects {
+		if current := r.autoscalerClient.GetMinReplicaCount(autoscalerObject); current > held {
+			held = current
+		}
+	}
+	return held, nil
+}
+
+// unavailableReplicas returns the replicas of the workload which aren't available, read from the status common to the
+// Deployments and the Rollouts.
+func unavailableReplicas(workload client.Object) (int64, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workload)
+	if err != nil {
+		return 0, err
+	}
+	replicas, _, _ := unstructured.NestedInt64(object, "status", "replicas")
+	available, _, _ := unstructured.NestedInt64(object, "status", "availableReplicas")
+	return replicas - available, nil
+}
+
+func findCondition(conditions []metav1.Condition, condType v1alpha1.PolicyRecommendationConditionType) metav1.Condition {
+	for _, condition := range conditions {
+		if condition.Type == string(condType) {
+			return condition
+		}
+	}
+	return metav1.Condition{}
 }
 
 func isRecoGenerated(conditions []metav1.Condition) bool {
@@ -364,6 +778,9 @@ func (r *HPAEnforcementController) SetupWithManager(mgr ctrl.Manager) error {
 			if conditionChanged(oldHPAEnforcedCondition, newHPAEnforcedCondition) {
 				return false
 			}
+			if conditionChanged(findCondition(oldObj.Status.Conditions, v1alpha1.VPAConflict), findCondition(newObj.Status.Conditions, v1alpha1.VPAConflict)) {
+				return false
+			}
 			return true
 		},
 		GenericFunc: func(e event.GenericEvent) bool {
@@ -429,7 +846,7 @@ func (r *HPAEnforcementController) SetupWithManager(mgr ctrl.Manager) error {
 		},
 	}
 
-	enqueueFunc := func(ctx context.Context,obj client.Object) []reconcile.Request {
+	enqueueFunc := func(ctx context.Context, obj client.Object) []reconcile.Request {
 		object := r.autoscalerClient.GetType()
 		if len(object.GetOwnerReferences()) == 0 {
 			return nil
@@ -514,6 +931,20 @@ func (r *HPAEnforcementController) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// cronTriggers translates the recommended cron triggers of a PolicyRecommendation for the autoscaler client.
+func cronTriggers(recommended []v1alpha1.CronTrigger) []autoscaler.CronTrigger {
+	var triggers []autoscaler.CronTrigger
+	for _, trigger := range recommended {
+		triggers = append(triggers, autoscaler.CronTrigger{
+			Start:           trigger.Start,
+			End:             trigger.End,
+			Timezone:        trigger.Timezone,
+			DesiredReplicas: int32(trigger.DesiredReplicas),
+		})
+	}
+	return triggers
+}
+
 func conditionChanged(oldCond metav1.Condition, newCond metav1.Condition) bool {
 	if oldCond.Type != newCond.Type || oldCond.Status != newCond.Status || oldCond.Reason != newCond.Reason ||
 		!oldCond.LastTransitionTime.Equal(&newCond.LastTransitionTime) || oldCond.Message != newCond.Message ||
@@ -545,12 +976,125 @@ func (r *HPAEnforcementController) isWhitelistedNamespace(namespace string) bool
 	return true
 }
 
-func (r *HPAEnforcementController) deleteControllerManagedAutoscaler(ctx context.Context, policyreco v1alpha1.PolicyRecommendation, workload client.Object, logger logr.Logger) error {
+// recordBaselineReplicas annotates the policyreco with the replicas the workload ran at before its autoscaler was
+// created.
+func (r *HPAEnforcementController) recordBaselineReplicas(ctx context.Context, policyreco *v1alpha1.PolicyRecommendation, replicas int) error {
+	patch := client.MergeFrom(policyreco.DeepCopy())
+	if policyreco.Annotations == nil {
+		policyreco.Annotations = map[string]string{}
+	}
+	policyreco.Annotations[baselineReplicasAnnotation] = strconv.Itoa(replicas)
+	return client.IgnoreNotFound(r.Patch(ctx, policyreco, patch))
+}
+
+// logRealizedSavings records the savings of the current replicas of the workload over the baseline of the replicas it
+// ran at before its autoscaler was created. The workloads autoscaled before the baseline was recorded have no savings.
+func (r *HPAEnforcementController) logRealizedSavings(policyreco v1alpha1.PolicyRecommendation, object registry.ObjectClient, logger logr.Logger) {
+	baselineReplicas, err := strconv.Atoi(policyreco.Annotations[baselineReplicasAnnotation])
+	if err != nil || baselineReplicas <= 0 {
+		hpaenforcerRealizedSavings.DeleteLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind)
+		return
+	}
+	currentReplicas, err := object.GetReplicaCount(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name)
+	if err != nil {
+		logger.V(0).Error(err, "Unable to fetch the replica count of the workload for the realized savings.")
+		return
+	}
+	realizedSavings := float64(baselineReplicas-currentReplicas) / float64(baselineReplicas) * 100
+	hpaenforcerRealizedSavings.WithLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name,
+		policyreco.Spec.WorkloadMeta.Kind).Set(math.Max(realizedSavings, 0))
+}
+
+// createEnforcementFailedEvent notifies the failure to create or update the autoscaler for the workload.
+// dropsCapacity tells whether enforcing the config drops the capacity of the workload, i.e. cuts the min or the max
+// replicas of the autoscaler managed for it or raises its target, or creates one with min replicas below the replicas
+// of the workload. A change of the metric the workload is scaled on counts as a drop, as the targets can't be compared.
+func (r *HPAEnforcementController) dropsCapacity(ctx context.Context, object registry.ObjectClient, workload client.Object,
+	max int32, min int32, target autoscaler.MetricTarget) (bool, error) {
+	labelSelector, err := labels.Parse(fmt.Sprintf("%s=%s", createdByLabelKey, createdByLabelValue))
+	if err != nil {
+		return false, err
+	}
+	autoscalerObjects, err := r.autoscalerClient.GetList(ctx, labelSelector, workload.GetNamespace(), fields.OneTermEqualSelector(autoscalerField, workload.GetName()))
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+	if len(autoscalerObjects) == 0 {
+		replicas, err := object.GetReplicaCount(workload.GetNamespace(), workload.GetName())
+		if err != nil {
+			return false, err
+		}
+		return int(min) < replicas, nil
+	}
+	current := autoscalerObjects[0]
+	if min < r.autoscalerClient.GetMinReplicaCount(current) || max < r.autoscalerClient.GetMaxReplicaCount(current) {
+		return true, nil
+	}
+	targetReader, ok := r.autoscalerClient.(autoscaler.TargetReader)
+	if !ok {
+		return false, nil
+	}
+	currentTarget, ok := targetReader.GetMetricTarget(current)
+	if !ok || currentTarget.GetName() != target.GetName() || currentTarget.GetType() != target.GetType() {
+		return true, nil
+	}
+	return target.Value > currentTarget.Value, nil
+}
+
+// silenceAlerts silences the capacity alerts of the workload after its autoscaler was changed, if alerts are silenced.
+// The change is enforced regardless of the silence.
+func (r *HPAEnforcementController) silenceAlerts(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	workload client.Object, result string, logger logr.Logger) {
+	if r.AlertSilencer == nil {
+		return
+	}
+	err := r.AlertSilencer.Silence(ctx, silence.Request{
+		Namespace:    policyreco.Namespace,
+		WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+		Workload:     workload.GetName(),
+		Reason:       fmt.Sprintf("its %s was %s", r.autoscalerClient.GetName(), result),
+	})
+	if err != nil {
+		logger.V(0).Error(err, "Error silencing the capacity alerts of the workload", "workload", workload.GetName())
+	}
+}
+
+func createEnforcementFailedEvent(policyreco v1alpha1.PolicyRecommendation, autoscalerName string, err error) notifier.Event {
+	newConfig := policyreco.Spec.CurrentHPAConfiguration
+	return notifier.Event{
+		Type:         notifier.EnforcementFailed,
+		Namespace:    policyreco.Namespace,
+		WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+		Workload:     policyreco.Spec.WorkloadMeta.Name,
+		Policy:       policyreco.Spec.Policy,
+		NewConfig:    &newConfig,
+		Message:      fmt.Sprintf("Error creating or updating the %s: %s", autoscalerName, err.Error()),
+	}
+}
+
+func createAutoscalerEnforcedAuditRecord(policyreco v1alpha1.PolicyRecommendation, result string, autoscalerName string) audit.Record {
+	newConfig := policyreco.Spec.CurrentHPAConfiguration
+	return audit.Record{
+		Action:       audit.AutoscalerEnforced,
+		Controller:   HPAEnforcementCtrlName,
+		Namespace:    policyreco.Namespace,
+		WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+		Workload:     policyreco.Spec.WorkloadMeta.Name,
+		NewConfig:    &newConfig,
+		Policy:       policyreco.Spec.Policy,
+		Reason:       recoTriggerReason(policyreco),
+		Result:       fmt.Sprintf("%s %s", autoscalerName, result),
+	}
+}
+
+func (r *HPAEnforcementController) deleteControllerManagedAutoscaler(ctx context.Context, policyreco v1alpha1.PolicyRecommendation, workload client.Object, reason string, logger logr.Logger) error {
 	labelSelector, err := labels.Parse(fmt.Sprintf("%s=%s", createdByLabelKey, createdByLabelValue))
 	if err != nil {
 		logger.V(0).Error(err, "Unable to parse label selector string.")
 		return err
 	}
+	// the workload isn't autoscaled by ottoscalr anymore, whether it was or not
+	hpaenforcerRealizedSavings.DeleteLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind)
 	var maxPods int32
 	// List only autoscalerObjects created by this controller
 
@@ -573,6 +1117,16 @@ func (r *HPAEnforcementController) deleteControllerManagedAutoscaler(ctx context
 		}
 		r.Recorder.Event(&policyreco, eventTypeNormal, r.autoscalerClient.GetName()+"Deleted", fmt.Sprintf("The %s '%s' has been deleted.", r.autoscalerClient.GetName(), autoscalerObject.GetName()))
 		hpaenforcerAutoscalerObjectDeletedCounter.WithLabelValues(policyreco.Namespace, policyreco.Name, autoscalerObject.GetName()).Inc()
+		r.auditor.Audit(ctx, audit.Record{
+			Action:       audit.AutoscalerDeleted,
+			Controller:   HPAEnforcementCtrlName,
+			Namespace:    policyreco.Namespace,
+			WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+			Workload:     workload.GetName(),
+			Policy:       policyreco.Spec.Policy,
+			Reason:       reason,
+			Result:       fmt.Sprintf("%s %s deleted", r.autoscalerClient.GetName(), autoscalerObject.GetName()),
+		})
 		logger.V(0).Info("Deleted "+r.autoscalerClient.GetName()+" for the policyreco.", "policyreco.name", policyreco.GetName(), "policyreco.namespace", policyreco.GetNamespace(), "autoscaler.name", autoscalerObject.GetName(), "autoscaler.namespace", autoscalerObject.GetNamespace(), "maxReplicas", maxPods)
 	}
 
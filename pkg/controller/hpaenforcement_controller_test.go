@@ -6,13 +6,22 @@ import (
 	"fmt"
 	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
 	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"strconv"
 	"time"
 )
@@ -4952,3 +4961,139 @@ var _ = Describe("Test ScaledObject enforcer", func() {
 	})
 
 })
+
+var _ = Describe("VPA guardrails", func() {
+	It("should mark the policyrecos of the workloads with conflicting VPAs till they're compatible", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createVPAConflictPatch(policyreco, nil, "cpu")).Should(BeNil())
+		Expect(createVPAConflictPatch(policyreco, &autoscaler.VPACoexistence{Name: "test-vpa", Evicting: true}, "cpu")).Should(BeNil())
+
+		vpa := &autoscaler.VPACoexistence{Name: "test-vpa", Conflicting: true, Evicting: true}
+		vpaPatch := createVPAConflictPatch(policyreco, vpa, "cpu")
+		Expect(vpaPatch.Status.Conditions).Should(HaveLen(1))
+		Expect(vpaPatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.VPAConflict)))
+		Expect(vpaPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(vpaPatch.Status.Conditions[0].Message).Should(ContainSubstring("test-vpa"))
+
+		policyreco.Status.Conditions = vpaPatch.Status.Conditions
+		Expect(createVPAConflictPatch(policyreco, vpa, "cpu")).Should(BeNil())
+
+		vpaPatch = createVPAConflictPatch(policyreco, nil, "cpu")
+		Expect(vpaPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(vpaPatch.Status.Conditions[0].Reason).Should(Equal(VPACompatibleReason))
+	})
+
+	It("should count the unavailable replicas of the workloads", func() {
+		deployment := &appsv1.Deployment{Status: appsv1.DeploymentStatus{Replicas: 5, AvailableReplicas: 3}}
+		Expect(unavailableReplicas(deployment)).Should(Equal(int64(2)))
+		Expect(unavailableReplicas(&argov1alpha1.Rollout{})).Should(Equal(int64(0)))
+	})
+})
+
+var _ = Describe("Alert silences", func() {
+	replicas := int32(8)
+	workload := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	labels := map[string]string{createdByLabelKey: createdByLabelValue}
+	target := autoscaler.CPUUtilizationTarget(60)
+
+	newController := func() (*HPAEnforcementController, registry.ObjectClient) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workload.DeepCopy()).
+			WithIndex(&autoscalingv2.HorizontalPodAutoscaler{}, autoscalerField, func(obj client.Object) []string {
+				return []string{obj.(*autoscalingv2.HorizontalPodAutoscaler).Spec.ScaleTargetRef.Name}
+			}).Build()
+		return &HPAEnforcementController{Client: k8sClient, autoscalerClient: autoscaler.NewHPAClientV2(k8sClient)},
+			registry.NewDeploymentClient(k8sClient)
+	}
+
+	It("should tell the autoscalers created below the replicas of the workload apart", func() {
+		r, object := newController()
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 20, 5, target)).Should(BeTrue())
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 20, 8, target)).Should(BeFalse())
+	})
+
+	It("should only tell the changes cutting the replicas or raising the target as drops", func() {
+		r, object := newController()
+		_, err := r.autoscalerClient.CreateOrUpdateAutoscaler(context.TODO(), workload, labels, 20, 5, target, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 20, 4, target)).Should(BeTrue())
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 15, 5, target)).Should(BeTrue())
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 20, 5, autoscaler.CPUUtilizationTarget(70))).Should(BeTrue())
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 20, 5,
+			autoscaler.MetricTarget{Name: "http_requests", Type: autoscaler.AverageValueTargetType, Value: 10})).Should(BeTrue())
+
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 20, 5, target)).Should(BeFalse())
+		Expect(r.dropsCapacity(context.TODO(), object, workload, 25, 6, autoscaler.CPUUtilizationTarget(50))).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Realized savings", func() {
+	replicas := int32(4)
+	workload := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "savings-app", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	newPolicyReco := func() *v1alpha1.PolicyRecommendation {
+		return &v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: "savings-app", Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{
+				WorkloadMeta: v1alpha1.WorkloadMeta{Name: "savings-app",
+					TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}},
+				CurrentHPAConfiguration: v1alpha1.HPAConfiguration{Min: 2, Max: 20, TargetMetricValue: 60},
+			},
+		}
+	}
+	// deleting the savings of the workload tells whether they were recorded
+	savingsRecorded := func() bool {
+		return hpaenforcerRealizedSavings.DeleteLabelValues("default", "savings-app", "Deployment")
+	}
+
+	newController := func() (*HPAEnforcementController, registry.ObjectClient) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workload.DeepCopy(), newPolicyReco()).
+			WithIndex(&autoscalingv2.HorizontalPodAutoscaler{}, autoscalerField, func(obj client.Object) []string {
+				return []string{obj.(*autoscalingv2.HorizontalPodAutoscaler).Spec.ScaleTargetRef.Name}
+			}).Build()
+		return &HPAEnforcementController{Client: k8sClient, autoscalerClient: autoscaler.NewHPAClientV2(k8sClient)},
+			registry.NewDeploymentClient(k8sClient)
+	}
+
+	It("should measure the savings against the replicas recorded before the autoscaler was created", func() {
+		r, object := newController()
+		policyreco := newPolicyReco()
+		Expect(r.recordBaselineReplicas(context.TODO(), policyreco, 10)).To(Succeed())
+
+		recorded := &v1alpha1.PolicyRecommendation{}
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(policyreco), recorded)).To(Succeed())
+		Expect(recorded.Annotations).To(HaveKeyWithValue(baselineReplicasAnnotation, "10"))
+
+		r.logRealizedSavings(*recorded, object, logr.Discard())
+		Expect(testutil.ToFloat64(hpaenforcerRealizedSavings.WithLabelValues("default", "savings-app", "Deployment"))).
+			To(BeNumerically("==", 60))
+		Expect(savingsRecorded()).To(BeTrue())
+	})
+
+	It("should not record the savings without a baseline", func() {
+		r, object := newController()
+		hpaenforcerRealizedSavings.WithLabelValues("default", "savings-app", "Deployment").Set(50)
+		r.logRealizedSavings(*newPolicyReco(), object, logr.Discard())
+		Expect(savingsRecorded()).To(BeFalse())
+	})
+
+	It("should delete the savings once the workload opts out of the enforcement", func() {
+		r, _ := newController()
+		hpaenforcerRealizedSavings.WithLabelValues("default", "savings-app", "Deployment").Set(50)
+		Expect(r.deleteControllerManagedAutoscaler(context.TODO(), *newPolicyReco(), workload, HPAEnforcementDisabledReason,
+			logr.Discard())).To(Succeed())
+		Expect(savingsRecorded()).To(BeFalse())
+	})
+})
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	hpaenforcerIdleWindowMinReplicas = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "hpaenforcer_idle_window_min_replicas",
+			Help: "Min replicas the workloads are lowered to in their idle windows"}, []string{"namespace", "policyreco"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(hpaenforcerIdleWindowMinReplicas)
+}
+
+// applyIdleWindow lowers the min replicas of the workload of the policyreco to the ones of its idle window and adds a
+// cron trigger keeping the workload at the min replicas from the end of the window till its next start, so that the
+// min replicas are restored in the morning. Only the ScaledObjects support cron triggers, so the min replicas of the
+// HPAs aren't lowered. The min replicas are never lowered to the min required replicas or below.
+func (r *HPAEnforcementController) applyIdleWindow(policyreco v1alpha1.PolicyRecommendation, min int32,
+	minRequiredReplicas int, triggers []autoscaler.CronTrigger) (int32, []autoscaler.CronTrigger) {
+	idleWindow := policyreco.Spec.IdleWindow
+	if _, ok := r.autoscalerClient.(*autoscaler.ScaledobjectClient); !ok || !r.IdleWindows || idleWindow == nil {
+		hpaenforcerIdleWindowMinReplicas.DeleteLabelValues(policyreco.Namespace, policyreco.Name)
+		return min, triggers
+	}
+	idleMin := int32(idleWindow.MinReplicas)
+	if idleMin <= int32(minRequiredReplicas) {
+		idleMin = int32(minRequiredReplicas) + 1
+	}
+	if idleMin >= min {
+		hpaenforcerIdleWindowMinReplicas.DeleteLabelValues(policyreco.Namespace, policyreco.Name)
+		return min, triggers
+	}
+	hpaenforcerIdleWindowMinReplicas.WithLabelValues(policyreco.Namespace, policyreco.Name).Set(float64(idleMin))
+	restore := autoscaler.CronTrigger{
+		Start:           idleWindow.End,
+		End:             idleWindow.Start,
+		Timezone:        idleWindow.Timezone,
+		DesiredReplicas: min,
+	}
+	return idleMin, append(triggers, restore)
+}
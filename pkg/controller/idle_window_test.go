@@ -0,0 +1,39 @@
+package controller
+
+import (
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Idle windows", func() {
+	peak := autoscaler.CronTrigger{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "Asia/Kolkata", DesiredReplicas: 15}
+	policyreco := v1alpha1.PolicyRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1alpha1.PolicyRecommendationSpec{IdleWindow: &v1alpha1.IdleWindow{Start: "0 1 * * *", End: "0 6 * * *",
+			Timezone: "Asia/Kolkata", MinReplicas: 2}},
+	}
+
+	It("should lower the min replicas of the ScaledObjects in the idle window and restore them outside of it", func() {
+		r := &HPAEnforcementController{autoscalerClient: autoscaler.NewScaledobjectClient(nil), IdleWindows: true}
+		min, triggers := r.applyIdleWindow(policyreco, 6, 0, []autoscaler.CronTrigger{peak})
+		Expect(min).To(Equal(int32(2)))
+		Expect(triggers).To(Equal([]autoscaler.CronTrigger{peak,
+			{Start: "0 6 * * *", End: "0 1 * * *", Timezone: "Asia/Kolkata", DesiredReplicas: 6}}))
+
+		min, _ = r.applyIdleWindow(policyreco, 6, 2, nil)
+		Expect(min).To(Equal(int32(3)))
+		min, triggers = r.applyIdleWindow(policyreco, 3, 2, nil)
+		Expect(min).To(Equal(int32(3)))
+		Expect(triggers).To(BeEmpty())
+	})
+
+	It("should keep the min replicas of the HPAs", func() {
+		r := &HPAEnforcementController{autoscalerClient: autoscaler.NewHPAClient(nil), IdleWindows: true}
+		min, triggers := r.applyIdleWindow(policyreco, 6, 0, []autoscaler.CronTrigger{peak})
+		Expect(min).To(Equal(int32(6)))
+		Expect(triggers).To(Equal([]autoscaler.CronTrigger{peak}))
+	})
+})
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const NamespaceRetriggerCtrlName = "NamespaceRetriggerController"
+
+// NamespaceRetriggerController queues the PolicyRecommendations of the workloads in a namespace for a fresh
+// recommendation when the namespace is annotated with the RetriggerRecommendationsAnnotation.
+type NamespaceRetriggerController struct {
+	Client       client.Client
+	Recorder     record.EventRecorder
+	BatchTrigger *trigger.BatchTrigger
+}
+
+func NewNamespaceRetriggerController(client client.Client, recorder record.EventRecorder,
+	batchTrigger *trigger.BatchTrigger) *NamespaceRetriggerController {
+	return &NamespaceRetriggerController{
+		Client:       client,
+		Recorder:     recorder,
+		BatchTrigger: batchTrigger,
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;patch
+
+func (r *NamespaceRetriggerController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName(NamespaceRetriggerCtrlName)
+
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !hasRetriggerAnnotation(namespace) {
+		return ctrl.Result{}, nil
+	}
+
+	selector, err := labels.Parse(namespace.GetAnnotations()[ottoscaleriov1alpha1.RetriggerSelectorAnnotation])
+	if err != nil {
+		logger.Error(err, "Invalid selector in the re-trigger annotation. Skipping.")
+		r.Recorder.Event(namespace, eventTypeWarning, "RecommendationsRetriggerFailed",
+			fmt.Sprintf("Invalid selector in the %s annotation: %s", ottoscaleriov1alpha1.RetriggerSelectorAnnotation, err.Error()))
+		return ctrl.Result{}, r.removeRetriggerAnnotations(ctx, namespace)
+	}
+
+	queued, err := r.BatchTrigger.QueueMatchingForExecution(ctx, namespace.Name, selector)
+	if err != nil {
+		logger.Error(err, "Error queuing the recommendations of the namespace. Requeuing.", "queued", len(queued))
+		return ctrl.Result{}, err
+	}
+	logger.V(0).Info("Queued the recommendations of the namespace for execution.", "queued", len(queued), "selector", selector.String())
+	r.Recorder.Event(namespace, eventTypeNormal, "RecommendationsRetriggered",
+		fmt.Sprintf("%d workloads have been queued for a fresh HPA recommendation.", len(queued)))
+	return ctrl.Result{}, r.removeRetriggerAnnotations(ctx, namespace)
+}
+
+func hasRetriggerAnnotation(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[ottoscaleriov1alpha1.RetriggerRecommendationsAnnotation]
+	return ok
+}
+
+func (r *NamespaceRetriggerController) removeRetriggerAnnotations(ctx context.Context, namespace *corev1.Namespace) error {
+	patch := client.MergeFrom(namespace.DeepCopy())
+	delete(namespace.Annotations, ottoscaleriov1alpha1.RetriggerRecommendationsAnnotation)
+	delete(namespace.Annotations, ottoscaleriov1alpha1.RetriggerSelectorAnnotation)
+	return client.IgnoreNotFound(r.Client.Patch(ctx, namespace, patch))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespaceRetriggerController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(NamespaceRetriggerCtrlName).
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.NewPredicateFuncs(hasRetriggerAnnotation))).
+		Complete(r)
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	NamespaceTerminationCtrlName = "NamespaceTerminationController"
+
+	// ottoscalrFinalizerDomain is the domain of the finalizers of ottoscalr, which nothing processes once the
+	// namespace of their object is terminating.
+	ottoscalrFinalizerDomain = "ottoscaler.io"
+)
+
+var (
+	terminatingNamespaceSkippedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "terminating_namespace_skipped_reconcile_count",
+			Help: "Number of reconciles skipped as the namespace of the object is terminating"}, []string{"controller", "namespace"},
+	)
+
+	terminatingNamespaceDeregisteredCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "terminating_namespace_deregistered_workloads_count",
+			Help: "Number of workloads de-registered as their namespace is terminating"}, []string{"namespace"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(terminatingNamespaceSkippedCounter, terminatingNamespaceDeregisteredCounter)
+}
+
+// NamespaceTerminationController de-registers the workloads of a namespace once it starts terminating: it stops the
+// breach monitors of their PolicyRecommendations and strips the ottoscalr finalizers off them, so that nothing keeps
+// updating the objects of the namespace or holds up its deletion.
+type NamespaceTerminationController struct {
+	Client         client.Client
+	MonitorManager trigger.MonitorManager
+}
+
+func NewNamespaceTerminationController(client client.Client,
+	monitorManager trigger.MonitorManager) *NamespaceTerminationController {
+	return &NamespaceTerminationController{
+		Client:         client,
+		MonitorManager: monitorManager,
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations,verbs=get;list;watch;patch
+
+func (r *NamespaceTerminationController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName(NamespaceTerminationCtrlName)
+
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !isTerminating(namespace) {
+		return ctrl.Result{}, nil
+	}
+
+	policyrecos := &ottoscaleriov1alpha1.PolicyRecommendationList{}
+	if err := r.Client.List(ctx, policyrecos, client.InNamespace(namespace.Name)); err != nil {
+		logger.Error(err, "Error listing the policy recos of the terminating namespace. Requeuing.")
+		return ctrl.Result{}, err
+	}
+	for i := range policyrecos.Items {
+		policyreco := &policyrecos.Items[i]
+		r.MonitorManager.DeregisterMonitor(types.NamespacedName{Namespace: policyreco.Namespace, Name: policyreco.Name})
+		if err := r.removeOttoscalrFinalizers(ctx, policyreco); err != nil {
+			logger.Error(err, "Error removing the finalizers of the policy reco. Requeuing.", "policyreco", policyreco.Name)
+			return ctrl.Result{}, err
+		}
+	}
+	policyRecoWorkloadGauge.DeletePartialMatch(prometheus.Labels{"namespace": namespace.Name})
+	terminatingNamespaceDeregisteredCounter.WithLabelValues(namespace.Name).Add(float64(len(policyrecos.Items)))
+	logger.V(0).Info("De-registered the workloads of the terminating namespace.", "namespace", namespace.Name,
+		"workloads", len(policyrecos.Items))
+	return ctrl.Result{}, nil
+}
+
+func (r *NamespaceTerminationController) removeOttoscalrFinalizers(ctx context.Context,
+	policyreco *ottoscaleriov1alpha1.PolicyRecommendation) error {
+	var finalizers []string
+	for _, finalizer := range policyreco.Finalizers {
+		if !isOttoscalrFinalizer(finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	if len(finalizers) == len(policyreco.Finalizers) {
+		return nil
+	}
+	patch := client.MergeFrom(policyreco.DeepCopy())
+	policyreco.Finalizers = finalizers
+	return client.IgnoreNotFound(r.Client.Patch(ctx, policyreco, patch))
+}
+
+func isOttoscalrFinalizer(finalizer string) bool {
+	domain, _, _ := strings.Cut(finalizer, "/")
+	return domain == ottoscalrFinalizerDomain || strings.HasSuffix(domain, "."+ottoscalrFinalizerDomain)
+}
+
+func isTerminating(obj client.Object) bool {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return true
+	}
+	namespace, ok := obj.(*corev1.Namespace)
+	return ok && namespace.Status.Phase == corev1.NamespaceTerminating
+}
+
+// skipsTerminatingNamespace returns whether the controller should skip reconciling an object of the namespace as it's
+// terminating, in which case the updates of its objects only fail. The namespaces which aren't found aren't skipped.
+func skipsTerminatingNamespace(ctx context.Context, reader client.Reader, namespace, controllerName string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if !isTerminating(ns) {
+		return false, nil
+	}
+	terminatingNamespaceSkippedCounter.WithLabelValues(controllerName, namespace).Inc()
+	return true, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespaceTerminationController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(NamespaceTerminationCtrlName).
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.NewPredicateFuncs(isTerminating))).
+		Complete(r)
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type deregisteringMonitorManager struct {
+	deregistered []types.NamespacedName
+}
+
+func (m *deregisteringMonitorManager) RegisterMonitor(string, types.NamespacedName) *trigger.Monitor {
+	return nil
+}
+
+func (m *deregisteringMonitorManager) DeregisterMonitor(workload types.NamespacedName) {
+	m.deregistered = append(m.deregistered, workload)
+}
+
+func (m *deregisteringMonitorManager) Shutdown() {}
+
+var _ = Describe("NamespaceTerminationController", func() {
+	var (
+		k8sClient      client.Client
+		monitorManager *deregisteringMonitorManager
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "retired"},
+				Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "live"}},
+			&v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "retired",
+				Finalizers: []string{policyFinalizerName, "example.com/keep"}}},
+			&v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "live"}},
+		).Build()
+		monitorManager = &deregisteringMonitorManager{}
+	})
+
+	It("should de-register the workloads of the terminating namespaces and strip the ottoscalr finalizers", func() {
+		r := NewNamespaceTerminationController(k8sClient, monitorManager)
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "retired"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(monitorManager.deregistered).To(ConsistOf(types.NamespacedName{Namespace: "retired", Name: "checkout"}))
+
+		policyreco := &v1alpha1.PolicyRecommendation{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "retired", Name: "checkout"}, policyreco)).To(Succeed())
+		Expect(policyreco.Finalizers).To(Equal([]string{"example.com/keep"}))
+
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "live"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(monitorManager.deregistered).To(HaveLen(1))
+	})
+
+	It("should skip the reconciles of the objects of the terminating namespaces", func() {
+		for namespace, terminating := range map[string]bool{"retired": true, "live": false, "deleted": false} {
+			skipped, err := skipsTerminatingNamespace(context.TODO(), k8sClient, namespace, PolicyRecoWorkflowCtrlName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skipped).To(Equal(terminating), namespace)
+		}
+	})
+})
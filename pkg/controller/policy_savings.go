@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"math"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	policyRecoPolicySavings = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_policy_savings_percent",
+			Help: "Savings percentage of the min replicas of the current policy config over its max replicas, by the applied policy"}, []string{"namespace", "workload", "kind", "policy"})
+
+	policyRecoNextPolicyUnlockedSavings = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_next_policy_unlocked_savings_percent",
+			Help: "Additional savings percentage of the min replicas the next policy in the ladder would unlock over the current policy config"}, []string{"namespace", "workload", "kind", "policy", "next_policy"})
+
+	policyRecoPinnedSavingsForgone = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_pinned_savings_forgone_percent",
+			Help: "Savings percentage of the min replicas the pinned config of the workload forgoes over its target recommendation"}, []string{"namespace", "workload", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(policyRecoPolicySavings, policyRecoNextPolicyUnlockedSavings, policyRecoPinnedSavingsForgone)
+}
+
+// minReplicasSavingsPercent returns the savings percentage of the min replicas of the config over its max replicas.
+func minReplicasSavingsPercent(config v1alpha1.HPAConfiguration) float64 {
+	if config.Max <= 0 {
+		return 0
+	}
+	return math.Max(float64(config.Max-config.Min)*100/float64(config.Max), 0)
+}
+
+// unlockedSavingsPercent returns the additional savings percentage of the min replicas the next policy would unlock
+// over the current config. The workflow never moves a workload past its target recommendation, so neither does the
+// next policy.
+func unlockedSavingsPercent(current, target v1alpha1.HPAConfiguration, nextPolicy *v1alpha1.Policy) float64 {
+	if nextPolicy == nil || current.Max <= 0 {
+		return 0
+	}
+	nextConfig, err := reco.PolicyConfig(&reco.Policy{
+		Name:                    nextPolicy.Name,
+		RiskIndex:               nextPolicy.Spec.RiskIndex,
+		MinReplicaPercentageCut: nextPolicy.Spec.MinReplicaPercentageCut,
+		TargetUtilization:       nextPolicy.Spec.TargetUtilization,
+	}, &target)
+	if err != nil {
+		return 0
+	}
+	nextMin := nextConfig.Min
+	if nextMin < target.Min {
+		nextMin = target.Min
+	}
+	return math.Max(float64(current.Min-nextMin)*100/float64(current.Max), 0)
+}
+
+// logPolicySavings attributes the savings of the current policy config of the workload to its policy, along with the
+// savings the next policy in the ladder would unlock, so that the workloads worth promoting stand out.
+func (r *PolicyRecommendationReconciler) logPolicySavings(policyreco v1alpha1.PolicyRecommendation, policyName string,
+	current, target v1alpha1.HPAConfiguration) {
+	deletePolicySavings(policyreco)
+	if policyName == "" {
+		return
+	}
+	workload, kind := policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind
+	policyRecoPolicySavings.WithLabelValues(policyreco.Namespace, workload, kind, policyName).
+		Set(minReplicasSavingsPercent(current))
+
+	// the policies ladder the target utilization, which doesn't apply to the configs targeting other metric values
+	if r.PolicyStore == nil || current.GetTargetMetricType() != v1alpha1.UtilizationMetricTarget {
+		return
+	}
+	nextPolicy, err := r.PolicyStore.GetNextPolicyByName(policyName)
+	if err != nil {
+		return
+	}
+	policyRecoNextPolicyUnlockedSavings.WithLabelValues(policyreco.Namespace, workload, kind, policyName, nextPolicy.Name).
+		Set(unlockedSavingsPercent(current, target, nextPolicy))
+}
+
+func deletePolicySavings(policyreco v1alpha1.PolicyRecommendation) {
+	labels := prometheus.Labels{"namespace": policyreco.Namespace, "workload": policyreco.Spec.WorkloadMeta.Name,
+		"kind": policyreco.Spec.WorkloadMeta.Kind}
+	policyRecoPolicySavings.DeletePartialMatch(labels)
+	policyRecoNextPolicyUnlockedSavings.DeletePartialMatch(labels)
+}
+
+// pinnedSavingsForgonePercent returns the savings percentage of the min replicas the pinned config forgoes over the
+// target recommendation, relative to the max replicas of the pinned config.
+func pinnedSavingsForgonePercent(pinned, target v1alpha1.HPAConfiguration) float64 {
+	if pinned.Max <= 0 {
+		return 0
+	}
+	return math.Max(float64(pinned.Min-target.Min)*100/float64(pinned.Max), 0)
+}
+
+// logPinnedSavingsForgone exports the savings the pinned config of the workload forgoes, if it's pinned.
+func logPinnedSavingsForgone(policyreco v1alpha1.PolicyRecommendation, target v1alpha1.HPAConfiguration) {
+	workload, kind := policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.WorkloadMeta.Kind
+	if policyreco.Spec.PinnedHPAConfiguration == nil {
+		policyRecoPinnedSavingsForgone.DeleteLabelValues(policyreco.Namespace, workload, kind)
+		return
+	}
+	policyRecoPinnedSavingsForgone.WithLabelValues(policyreco.Namespace, workload, kind).
+		Set(pinnedSavingsForgonePercent(*policyreco.Spec.PinnedHPAConfiguration, target))
+}
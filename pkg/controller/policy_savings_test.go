@@ -0,0 +1,30 @@
+package controller
+
+import (
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy savings", func() {
+	nextPolicy := &v1alpha1.Policy{Spec: v1alpha1.PolicySpec{RiskIndex: 2, MinReplicaPercentageCut: 50, TargetUtilization: 50}}
+
+	It("should compute the savings of the min replicas over the max replicas", func() {
+		Expect(minReplicasSavingsPercent(v1alpha1.HPAConfiguration{Min: 15, Max: 20})).Should(Equal(25.0))
+		Expect(minReplicasSavingsPercent(v1alpha1.HPAConfiguration{})).Should(Equal(0.0))
+	})
+
+	It("should compute the savings the next policy would unlock over the current config", func() {
+		current := v1alpha1.HPAConfiguration{Min: 18, Max: 20, TargetMetricValue: 40}
+		// the next policy cuts half of the 16 replicas between the min and the max of the target recommendation
+		Expect(unlockedSavingsPercent(current, v1alpha1.HPAConfiguration{Min: 4, Max: 20, TargetMetricValue: 60},
+			nextPolicy)).Should(Equal(30.0))
+		Expect(unlockedSavingsPercent(current, v1alpha1.HPAConfiguration{Min: 4, Max: 20}, nil)).Should(Equal(0.0))
+	})
+
+	It("should not unlock savings past the target recommendation", func() {
+		current := v1alpha1.HPAConfiguration{Min: 16, Max: 20, TargetMetricValue: 50}
+		Expect(unlockedSavingsPercent(current, v1alpha1.HPAConfiguration{Min: 16, Max: 20, TargetMetricValue: 50},
+			nextPolicy)).Should(Equal(0.0))
+	})
+})
@@ -18,9 +18,15 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/flipkart-incubator/ottoscalr/pkg/approval"
+	"github.com/flipkart-incubator/ottoscalr/pkg/audit"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
 	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
 	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
 	"github.com/prometheus/client_golang/prometheus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"strconv"
+	"strings"
 	"time"
 
 	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
@@ -39,10 +46,20 @@ import (
 )
 
 const (
-	PolicyRecoWorkflowCtrlName = "RecoWorkflowController"
-	RecoQueuedStatusManager    = "RecoQueuedStatusManager"
-	eventTypeNormal            = "Normal"
-	eventTypeWarning           = "Warning"
+	PolicyRecoWorkflowCtrlName    = "RecoWorkflowController"
+	RecoQueuedStatusManager       = "RecoQueuedStatusManager"
+	RecoMetadataStatusManager     = "RecoMetadataStatusManager"
+	WorkloadActivityStatusManager = "WorkloadActivityStatusManager"
+	WorkloadHistoryStatusManager  = "WorkloadHistoryStatusManager"
+	WorkloadResizeStatusManager   = "WorkloadResizeStatusManager"
+	WorkloadQuotaStatusManager    = "WorkloadQuotaStatusManager"
+	NoOpRecommendedStatusManager  = "NoOpRecommendedStatusManager"
+	StaleDataStatusManager        = "StaleDataStatusManager"
+	ChangeRequestStatusManager    = "ChangeRequestStatusManager"
+	AnomalyStatusManager          = "AnomalyStatusManager"
+	PinnedStatusManager           = "PinnedStatusManager"
+	eventTypeNormal               = "Normal"
+	eventTypeWarning              = "Warning"
 )
 
 var (
@@ -92,12 +109,38 @@ var (
 	policyRecoCurrentUtil = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{Name: "policyreco_current_policy_utilization",
 			Help: "PolicyReco Current Policy Utilization"}, []string{"namespace", "policyreco"})
+
+	policyRecoUtilDrift = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_target_util_drift",
+			Help: "Difference between the target utilization of the target reco and the current policy config"}, []string{"namespace", "policyreco"})
+
+	policyRecoMinReplicasDrift = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_min_replicas_drift",
+			Help: "Difference between the min replicas of the current policy config and the target reco"}, []string{"namespace", "policyreco"})
+
+	policyRecoProjectedSavings = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_projected_savings_percent",
+			Help: "Projected savings percentage of the current recommendation over running at max replicas"}, []string{"namespace", "workload", "kind"})
+
+	policyRecoConfidence = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_confidence_percent",
+			Help: "Confidence percentage of the current recommendation"}, []string{"namespace", "workload", "kind"})
+
+	policyRecoUpdatesSuppressedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "policyreco_updates_suppressed_count",
+			Help: "Number of recommendations not enforced as they don't differ materially from the current policy config"}, []string{"namespace", "policyreco"})
+
+	policyRecoAnomaliesCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "policyreco_anomalous_recommendations_count",
+			Help: "Number of recommendations differing wildly from the previous recommendation, by whether they were flagged, blocked or accepted"}, []string{"namespace", "policyreco", "reason"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(reconcileCounter, reconcileErroredCounter, targetRecoSLI,
 		policyRecoConditionsGauge, policyRecoTaskProgressReasonsGauge, policyRecoTargetMin, policyRecoTargetMax, policyRecoTargetUtil,
-		policyRecoCurrentMin, policyRecoCurrentMax, policyRecoCurrentUtil)
+		policyRecoCurrentMin, policyRecoCurrentMax, policyRecoCurrentUtil, policyRecoProjectedSavings,
+		policyRecoUtilDrift, policyRecoMinReplicasDrift, policyRecoUpdatesSuppressedCounter, policyRecoConfidence,
+		policyRecoAnomaliesCounter)
 }
 
 // PolicyRecommendationReconciler reconciles a PolicyRecommendation object
@@ -107,14 +150,27 @@ type PolicyRecommendationReconciler struct {
 	Recorder                record.EventRecorder
 	MaxConcurrentReconciles int
 	PolicyExpiryAge         time.Duration
+	DiffThreshold           RecommendationDiffThreshold
+	StaleDataGrace          StaleDataGrace
+	ChangeApproval          ChangeApproval
+	AnomalyGuard            AnomalyGuard
+	WorkloadExclusion       WorkloadExclusion
+	InteractiveQueue        InteractiveQueue
 	RecoWorkflow            reco.RecommendationWorkflow
+	Auditor                 audit.Auditor
+	Notifier                notifier.Notifier
+	PolicyStore             policy.Store
+
+	inFlight inFlightRecos
 }
 
 func NewPolicyRecommendationReconciler(client client.Client,
 	scheme *runtime.Scheme, recorder record.EventRecorder,
-	maxConcurrentReconciles int, minRequiredReplicas int, recommender reco.Recommender, policyStore policy.Store, policyIterators ...reco.PolicyIterator) (*PolicyRecommendationReconciler, error) {
+	maxConcurrentReconciles int, minRequiredReplicas int, recommender reco.Recommender, policyStore policy.Store, auditor audit.Auditor, notifier notifier.Notifier, workerPool *reco.WorkerPool, pdbResolver *reco.PodDisruptionBudgetResolver, quotaResolver *reco.ResourceQuotaResolver, configResolver *reco.ConfigResolver, clientsRegistry *registry.DeploymentClientRegistry, policyIterators ...reco.PolicyIterator) (*PolicyRecommendationReconciler, error) {
 	recoWfBuilder := reco.NewRecommendationWorkflowBuilder().
-		WithRecommender(recommender).WithMinRequiredReplicas(minRequiredReplicas).WithPolicyStore(policyStore).WithK8sClient(client)
+		WithRecommender(recommender).WithMinRequiredReplicas(minRequiredReplicas).WithPolicyStore(policyStore).WithK8sClient(client).WithWorkerPool(workerPool).
+		WithPodDisruptionBudgetResolver(pdbResolver).WithResourceQuotaResolver(quotaResolver).WithConfigResolver(configResolver).
+		WithClientsRegistry(clientsRegistry)
 	for _, pi := range policyIterators {
 		recoWfBuilder = recoWfBuilder.WithPolicyIterator(pi)
 	}
@@ -128,6 +184,9 @@ func NewPolicyRecommendationReconciler(client client.Client,
 		MaxConcurrentReconciles: maxConcurrentReconciles,
 		Recorder:                recorder,
 		RecoWorkflow:            recoWorkflow,
+		Auditor:                 auditor,
+		Notifier:                notifier,
+		PolicyStore:             policyStore,
 	}, nil
 }
 
@@ -135,11 +194,20 @@ func NewPolicyRecommendationReconciler(client client.Client,
 //+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendations/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=ottoscalrconfigs,verbs=get;list;watch
 
 func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 
 	logger := ctrl.LoggerFrom(ctx).WithName(PolicyRecoWorkflowCtrlName)
 
+	if !r.inFlight.acquire(req.NamespacedName) {
+		logger.V(1).Info("Requeuing as the policy reco is being recommended by the other queue.")
+		return ctrl.Result{RequeueAfter: inFlightRequeueDelay}, nil
+	}
+	defer r.inFlight.release(req.NamespacedName)
+
 	// Keeping this here to consider the generatedAt timestamp to be the beginning of the reconcile op
 	generatedAt := metav1.Now()
 
@@ -148,11 +216,50 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
 		// on deleted requests.
+		if client.IgnoreNotFound(err) == nil {
+			deleteRecoDrift(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	logger.V(2).Info("PolicyRecomemndation retrieved", "policyreco", policyreco)
 
+	if terminating, err := skipsTerminatingNamespace(ctx, r.Client, policyreco.Namespace, PolicyRecoWorkflowCtrlName); err != nil || terminating {
+		if terminating {
+			logger.V(1).Info("Skipping the recommendation workflow as the namespace is terminating.")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if isRecommendationFrozen(policyreco) {
+		logger.V(0).Info("Skipping the recommendation workflow as the recommendation is frozen.")
+		statusPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.RecoTaskQueued, metav1.ConditionFalse, RecoTaskFrozen, RecoTaskFrozenMessage)
+		if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(RecoQueuedStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		r.Recorder.Event(&policyreco, eventTypeNormal, "HPARecommendationFrozen", "The HPA recommendation is frozen. Skipping the recommendation workflow.")
+		reco.CountNoOpRecommendation(policyreco.Namespace, reco.NoOpFrozen)
+		return ctrl.Result{}, nil
+	}
+
+	excludedReason, excludedMessage, err := r.excludedReason(ctx, policyreco)
+	if err != nil {
+		logger.Error(err, "Error fetching the workload of the policy reco object")
+		return ctrl.Result{}, err
+	}
+	if len(excludedReason) > 0 {
+		return r.skipExcludedWorkload(ctx, policyreco, excludedReason, excludedMessage)
+	}
+	if isWorkloadSkipped(policyreco) {
+		exclusionPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.Skipped, metav1.ConditionFalse, WorkloadIncluded, WorkloadIncludedMessage)
+		if err := r.Status().Patch(ctx, exclusionPatch, client.Apply, getSubresourcePatchOptions(WorkloadExclusionStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.Skipped, metav1.ConditionFalse)
+	}
+
 	r.Recorder.Event(&policyreco, eventTypeNormal, "HPARecoQueuedForExecution", "This workload has been queued for a fresh HPA recommendation.")
 
 	policyRecoWorkloadGauge.WithLabelValues(policyreco.Namespace, policyreco.Name, policyreco.Spec.WorkloadMeta.TypeMeta.Kind, policyreco.Spec.WorkloadMeta.Name).Set(1)
@@ -166,11 +273,15 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 	}
 	logPolicyRecoGaugeMetric(policyreco, v1alpha1.RecoTaskProgress, metav1.ConditionTrue)
 
-	hpaConfigToBeApplied, targetHPAReco, policy, err := r.RecoWorkflow.Execute(ctx, reco.WorkloadMeta{
+	hpaConfigToBeApplied, targetHPAReco, policy, recoMetadata, err := r.RecoWorkflow.Execute(ctx, reco.WorkloadMeta{
 		TypeMeta:  policyreco.Spec.WorkloadMeta.TypeMeta,
 		Name:      policyreco.Spec.WorkloadMeta.Name,
 		Namespace: policyreco.Namespace,
 	})
+	var inactiveErr *reco.WorkloadInactiveError
+	if errors.As(err, &inactiveErr) {
+		return r.skipInactiveWorkload(ctx, policyreco, conditions, inactiveErr)
+	}
 	if err != nil {
 		statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.RecoTaskProgress, metav1.ConditionFalse, RecoTaskErrored, err.Error())
 		if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(PolicyRecoWorkflowCtrlName)); err != nil {
@@ -213,6 +324,53 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 		}, nil
 	}
 
+	staleData, staleSince, staleRecommendations := r.StaleDataGrace.keepsPreviousRecommendation(policyreco, recoMetadata, generatedAt.Time)
+	schedule := scalingScheduleForConfig(recoMetadata, hpaConfigToBeApplied)
+	// keepsSchedule is set wherever the recommended HPA config gives way to the current one, whose scaling schedule is
+	// then kept with it
+	keepsSchedule := false
+	if staleData {
+		logger.V(0).Info("Keeping the previous recommendation as the metrics of the workload are insufficient.",
+			"staleSince", staleSince, "staleRecommendations", staleRecommendations)
+		previousTarget, previousConfig := policyreco.Spec.TargetHPAConfiguration, policyreco.Spec.CurrentHPAConfiguration
+		targetHPAReco, hpaConfigToBeApplied, policy = &previousTarget, &previousConfig, nil
+		keepsSchedule = true
+	}
+
+	targetHPAReco = applyWorkloadOverrides(targetHPAReco, policyreco.Spec)
+	hpaConfigToBeApplied = applyWorkloadOverrides(hpaConfigToBeApplied, policyreco.Spec)
+
+	// raising the replicas to the no-op configuration is never an anomaly, nor is moving off it
+	var anomaly, anomalyReason string
+	if !staleData && (recoMetadata == nil || recoMetadata.NoOp == nil) && !hasCondition(policyreco, v1alpha1.NoOpRecommended) {
+		anomaly = r.AnomalyGuard.detect(policyreco.Spec.TargetHPAConfiguration, *targetHPAReco)
+	}
+	if len(anomaly) > 0 {
+		switch {
+		case isRecommendationAccepted(policyreco):
+			anomalyReason = AnomalousRecommendationAccepted
+		case r.AnomalyGuard.Block:
+			anomalyReason = AnomalousRecommendationBlocked
+			logger.V(0).Info("Keeping the previous recommendation as the recommendation differs wildly from it.",
+				"anomaly", anomaly, "previous", policyreco.Spec.TargetHPAConfiguration, "recommended", *targetHPAReco)
+			previousTarget, previousConfig := policyreco.Spec.TargetHPAConfiguration, policyreco.Spec.CurrentHPAConfiguration
+			targetHPAReco, hpaConfigToBeApplied, policy = &previousTarget, &previousConfig, nil
+			keepsSchedule = true
+		default:
+			anomalyReason = AnomalousRecommendationFlagged
+		}
+		policyRecoAnomaliesCounter.WithLabelValues(policyreco.Namespace, policyreco.Name, anomalyReason).Inc()
+	}
+
+	// the workload runs the config it's pinned to, while the target recommendation keeps tracking its traffic
+	pinned := policyreco.Spec.PinnedHPAConfiguration
+	if pinned != nil {
+		logger.V(0).Info("Keeping the pinned HPA config as the current one.", "pinned", *pinned, "recommended", *targetHPAReco)
+		pinnedConfig := *pinned
+		hpaConfigToBeApplied, policy = &pinnedConfig, nil
+		keepsSchedule = true
+	}
+
 	var policyName string
 
 	if policy != nil {
@@ -221,6 +379,27 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 		policyName = policyreco.Spec.Policy
 	}
 
+	if pinned == nil && r.DiffThreshold.suppressesUpdate(policyreco, policyName, *hpaConfigToBeApplied) {
+		logger.V(0).Info("Keeping the current HPA config as the recommended config doesn't differ materially from it.",
+			"current", policyreco.Spec.CurrentHPAConfiguration, "recommended", *hpaConfigToBeApplied)
+		policyRecoUpdatesSuppressedCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
+		currentConfig := policyreco.Spec.CurrentHPAConfiguration
+		hpaConfigToBeApplied = &currentConfig
+	}
+
+	changeRequest, approved := r.reviewPolicyTransition(ctx, policyreco, hpaConfigToBeApplied, policyName, generatedAt.Time)
+	if !approved {
+		logger.V(0).Info("Keeping the current policy as its transition isn't approved by the change management system.",
+			"current", policyreco.Spec.Policy, "recommended", policyName, "changeRequest", changeRequest)
+		currentConfig := policyreco.Spec.CurrentHPAConfiguration
+		hpaConfigToBeApplied = &currentConfig
+		policyName = policyreco.Spec.Policy
+		keepsSchedule = true
+	}
+	if keepsSchedule {
+		schedule = scalingScheduleOf(policyreco.Spec)
+	}
+
 	transitionedAt := retrieveTransitionTime(hpaConfigToBeApplied, &policyreco, generatedAt)
 	policyRecoPatch := &v1alpha1.PolicyRecommendation{
 		TypeMeta: policyreco.TypeMeta,
@@ -237,6 +416,7 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 			GeneratedAt:             &generatedAt,
 		},
 	}
+	schedule.applyTo(&policyRecoPatch.Spec)
 	logger.V(0).Info("Policy Patch", "PolicyReco", *policyRecoPatch)
 	if err := r.Patch(ctx, policyRecoPatch, client.Apply, client.ForceOwnership, client.FieldOwner(PolicyRecoWorkflowCtrlName)); err != nil {
 		logger.Error(err, "Error patching the policy reco object")
@@ -246,6 +426,16 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 
 	logTargetHPAConfiguration(policyreco, targetHPAReco)
 	logCurrentHPAConfiguration(policyreco, hpaConfigToBeApplied)
+	logRecoDrift(policyreco, targetHPAReco, hpaConfigToBeApplied)
+
+	if !policyreco.Spec.CurrentHPAConfiguration.DeepEquals(*hpaConfigToBeApplied) || policyreco.Spec.Policy != policyName {
+		auditRecord := createRecommendationAppliedAuditRecord(policyreco, hpaConfigToBeApplied, policyName, recoMetadata)
+		if changeRequest != nil && policyreco.Spec.Policy != policyName {
+			auditRecord.ChangeTicketID = changeRequest.TicketID
+		}
+		r.Auditor.Audit(ctx, auditRecord)
+		r.notifyTransitions(ctx, policyreco, hpaConfigToBeApplied, policyName)
+	}
 
 	initializedTime := fetchInitializedTime(&policyreco)
 	targetAchievedAlready := fetchTargetAchieved(&policyreco)
@@ -269,6 +459,139 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 	logPolicyRecoGaugeMetric(policyreco, v1alpha1.RecoTaskProgress, metav1.ConditionFalse)
 	logRecoTaskProgressReasonGaugeMetric(policyreco, v1alpha1.RecoTaskProgress, RecoTaskRecommendationGenerated)
 
+	if recoMetadata != nil && recoMetadata.NoOp == nil {
+		policyRecoProjectedSavings.WithLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name,
+			policyreco.Spec.WorkloadMeta.Kind).Set(float64(recoMetadata.ProjectedSavingsPercent))
+		r.logPolicySavings(policyreco, policyName, *hpaConfigToBeApplied, *targetHPAReco)
+	} else {
+		policyRecoProjectedSavings.DeleteLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name,
+			policyreco.Spec.WorkloadMeta.Kind)
+		deletePolicySavings(policyreco)
+	}
+	if recoMetadata != nil && recoMetadata.Confidence != nil {
+		policyRecoConfidence.WithLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name,
+			policyreco.Spec.WorkloadMeta.Kind).Set(float64(recoMetadata.Confidence.Score))
+	} else {
+		policyRecoConfidence.DeleteLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name,
+			policyreco.Spec.WorkloadMeta.Kind)
+	}
+
+	metadataPatch := CreateRecoMetadataPatch(policyreco, generatedAt, recoMetadata)
+	if err := r.Status().Patch(ctx, metadataPatch, client.Apply, getSubresourcePatchOptions(RecoMetadataStatusManager)); err != nil {
+		logger.Error(err, "Error updating the recommendation metadata in the status of the policy reco object")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if isWorkloadInactive(policyreco) {
+		activityPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.WorkloadInactive, metav1.ConditionFalse, WorkloadActive, WorkloadActiveMessage)
+		if err := r.Status().Patch(ctx, activityPatch, client.Apply, getSubresourcePatchOptions(WorkloadActivityStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.WorkloadInactive, metav1.ConditionFalse)
+	}
+
+	if historyPatch := createWorkloadHistoryPatch(policyreco, recoMetadata); historyPatch != nil {
+		if err := r.Status().Patch(ctx, historyPatch, client.Apply, getSubresourcePatchOptions(WorkloadHistoryStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		historyCondition := historyPatch.Status.Conditions[0]
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.InsufficientHistory, historyCondition.Status)
+		if historyCondition.Status == metav1.ConditionTrue {
+			r.Recorder.Event(&policyreco, eventTypeNormal, "InsufficientHistory", historyCondition.Message)
+		}
+	}
+
+	if stalePatch := createStaleDataPatch(policyreco, recoMetadata, staleData, staleSince, staleRecommendations); stalePatch != nil {
+		if err := r.Status().Patch(ctx, stalePatch, client.Apply, getSubresourcePatchOptions(StaleDataStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		staleCondition := stalePatch.Status.Conditions[0]
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.StaleData, staleCondition.Status)
+		if staleCondition.Status == metav1.ConditionTrue {
+			r.Recorder.Event(&policyreco, eventTypeWarning, "StaleData", staleCondition.Message)
+		}
+	}
+
+	// the previous recommendation kept for the stale data isn't the no-op configuration
+	if noOpPatch := createNoOpPatch(policyreco, recoMetadata); !staleData && noOpPatch != nil {
+		if err := r.Status().Patch(ctx, noOpPatch, client.Apply, getSubresourcePatchOptions(NoOpRecommendedStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		noOpCondition := noOpPatch.Status.Conditions[0]
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.NoOpRecommended, noOpCondition.Status)
+		if noOpCondition.Status == metav1.ConditionTrue {
+			r.Recorder.Event(&policyreco, eventTypeWarning, "NoOpRecommended", noOpCondition.Message)
+		}
+	}
+
+	if resizePatch := createResizePatch(policyreco, recoMetadata); resizePatch != nil {
+		if err := r.Status().Patch(ctx, resizePatch, client.Apply, getSubresourcePatchOptions(WorkloadResizeStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		resizeCondition := resizePatch.Status.Conditions[0]
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.ResizeRecommended, resizeCondition.Status)
+		if resizeCondition.Status == metav1.ConditionTrue {
+			r.Recorder.Event(&policyreco, eventTypeWarning, "ResizeRecommended", resizeCondition.Message)
+		}
+	}
+
+	if quotaPatch := createQuotaPatch(policyreco, recoMetadata); quotaPatch != nil {
+		if err := r.Status().Patch(ctx, quotaPatch, client.Apply, getSubresourcePatchOptions(WorkloadQuotaStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		quotaCondition := quotaPatch.Status.Conditions[0]
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.QuotaConstrained, quotaCondition.Status)
+		if quotaCondition.Status == metav1.ConditionTrue {
+			r.Recorder.Event(&policyreco, eventTypeWarning, "QuotaConstrained", quotaCondition.Message)
+		}
+	}
+
+	if anomalyPatch := createAnomalyPatch(policyreco, anomaly, anomalyReason); anomalyPatch != nil {
+		if err := r.Status().Patch(ctx, anomalyPatch, client.Apply, getSubresourcePatchOptions(AnomalyStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		anomalyCondition := anomalyPatch.Status.Conditions[0]
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.AnomalousRecommendation, anomalyCondition.Status)
+		if anomalyCondition.Status == metav1.ConditionTrue {
+			r.Recorder.Event(&policyreco, eventTypeWarning, anomalyReason, anomalyCondition.Message)
+		}
+	}
+	if pinnedPatch := createPinnedPatch(policyreco, *targetHPAReco); pinnedPatch != nil {
+		if err := r.Status().Patch(ctx, pinnedPatch, client.Apply, getSubresourcePatchOptions(PinnedStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logPolicyRecoGaugeMetric(policyreco, v1alpha1.RecommendationPinned, pinnedPatch.Status.Conditions[0].Status)
+	}
+	logPinnedSavingsForgone(policyreco, *targetHPAReco)
+	if anomalyReason == AnomalousRecommendationAccepted {
+		if err := r.removeAcceptAnnotation(ctx, policyreco); err != nil {
+			logger.Error(err, "Error removing the accept annotation of the policy reco object")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if changeRequestPatch := createChangeRequestPatch(policyreco, changeRequest); changeRequestPatch != nil {
+		if err := r.Status().Patch(ctx, changeRequestPatch, client.Apply, getSubresourcePatchOptions(ChangeRequestStatusManager)); err != nil {
+			logger.Error(err, "Error updating the status of the policy reco object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		eventType := eventTypeNormal
+		if changeRequest.Status == v1alpha1.ChangeRequestRejected || changeRequest.Status == v1alpha1.ChangeRequestTimedOut {
+			eventType = eventTypeWarning
+		}
+		r.Recorder.Event(&policyreco, eventType, "ChangeRequest"+string(changeRequest.Status),
+			fmt.Sprintf("The transition to the policy %s is %s in the change management system as %s.",
+				changeRequest.Policy, strings.ToLower(string(changeRequest.Status)), changeRequest.TicketID))
+	}
+
 	statusPatch, conditions = CreatePolicyPatch(policyreco, conditions, v1alpha1.RecoTaskQueued, metav1.ConditionFalse, RecoTaskExecutionDone, RecoTaskExecutionDoneMessage)
 	if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(RecoQueuedStatusManager)); err != nil {
 		logger.Error(err, "Error updating the status of the policy reco object")
@@ -280,9 +603,352 @@ func (r *PolicyRecommendationReconciler) Reconcile(ctx context.Context, req ctrl
 	reconcileCounter.WithLabelValues(policyreco.Namespace, policyreco.Name).Inc()
 	r.Recorder.Event(&policyreco, eventTypeNormal, "HPARecommendationGenerated", fmt.Sprintf("The HPA recommendation has been generated successfully. The current policy this workload is at %s.", policyName))
 	logger.V(1).Info("Successfully generated HPA Recommendation.")
+	if changeRequest != nil && changeRequest.Status == v1alpha1.ChangeRequestPending && r.ChangeApproval.PollInterval > 0 {
+		return ctrl.Result{RequeueAfter: r.ChangeApproval.PollInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// skipInactiveWorkload marks the workload of the policyreco as inactive and completes the recommendation task
+// without changing its HPA configurations.
+func (r *PolicyRecommendationReconciler) skipInactiveWorkload(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	conditions []metav1.Condition, inactiveErr *reco.WorkloadInactiveError) (ctrl.Result, error) {
+	logger := ctrl.LoggerFrom(ctx).WithName(PolicyRecoWorkflowCtrlName)
+	message := fmt.Sprintf("The recommendation is skipped until the workload is active again: %s", inactiveErr.Error())
+
+	activityPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.WorkloadInactive, metav1.ConditionTrue, inactiveErr.Reason, message)
+	if err := r.Status().Patch(ctx, activityPatch, client.Apply, getSubresourcePatchOptions(WorkloadActivityStatusManager)); err != nil {
+		logger.Error(err, "Error updating the status of the policy reco object")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logPolicyRecoGaugeMetric(policyreco, v1alpha1.WorkloadInactive, metav1.ConditionTrue)
+
+	statusPatch, _ := CreatePolicyPatch(policyreco, conditions, v1alpha1.RecoTaskProgress, metav1.ConditionFalse, RecoTaskSkipped, RecoTaskSkippedMessage)
+	if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(PolicyRecoWorkflowCtrlName)); err != nil {
+		logger.Error(err, "Error updating the status of the policy reco object")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logPolicyRecoGaugeMetric(policyreco, v1alpha1.RecoTaskProgress, metav1.ConditionFalse)
+	logRecoTaskProgressReasonGaugeMetric(policyreco, v1alpha1.RecoTaskProgress, RecoTaskSkipped)
+
+	statusPatch, _ = CreatePolicyPatch(policyreco, nil, v1alpha1.RecoTaskQueued, metav1.ConditionFalse, RecoTaskExecutionDone, RecoTaskExecutionDoneMessage)
+	if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(RecoQueuedStatusManager)); err != nil {
+		logger.Error(err, "Error updating the status of the policy reco object")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	r.Recorder.Event(&policyreco, eventTypeNormal, "WorkloadInactive", message)
 	return ctrl.Result{}, nil
 }
 
+// createWorkloadHistoryPatch creates a status patch marking the workload of the policyreco with the
+// InsufficientHistory condition while it's too young for a recommendation, and unmarking it once it isn't. It returns
+// nil if the condition doesn't change.
+func createWorkloadHistoryPatch(policyreco v1alpha1.PolicyRecommendation, recoMetadata *reco.RecommendationMetadata) *v1alpha1.PolicyRecommendation {
+	if recoMetadata != nil && recoMetadata.InsufficientHistory {
+		message := fmt.Sprintf("The workload was created %s ago. %s", recoMetadata.WorkloadAge.Round(time.Minute), InsufficientHistoryMessage)
+		historyPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.InsufficientHistory, metav1.ConditionTrue, WorkloadBootstrapping, message)
+		return historyPatch
+	}
+	if hasCondition(policyreco, v1alpha1.InsufficientHistory) {
+		historyPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.InsufficientHistory, metav1.ConditionFalse, SufficientHistory, SufficientHistoryMessage)
+		return historyPatch
+	}
+	return nil
+}
+
+// createNoOpPatch creates a status patch marking the policyreco with the NoOpRecommended condition while its workload
+// can't be recommended a config and is recommended the no-op configuration instead, and unmarking it once it can. It
+// returns nil if the condition doesn't change.
+func createNoOpPatch(policyreco v1alpha1.PolicyRecommendation, recoMetadata *reco.RecommendationMetadata) *v1alpha1.PolicyRecommendation {
+	if recoMetadata != nil && recoMetadata.NoOp != nil {
+		noOpPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.NoOpRecommended, metav1.ConditionTrue, recoMetadata.NoOp.Reason, recoMetadata.NoOp.Message)
+		return noOpPatch
+	}
+	if hasCondition(policyreco, v1alpha1.NoOpRecommended) {
+		noOpPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.NoOpRecommended, metav1.ConditionFalse, WorkloadRecommended, WorkloadRecommendedMessage)
+		return noOpPatch
+	}
+	return nil
+}
+
+// createStaleDataPatch creates a status patch marking the policyreco with the StaleData condition while the previous
+// recommendation is kept as the metrics of its workload fall short, and unmarking it once they don't or once the grace
+// period expires. It returns nil if the condition doesn't change.
+func createStaleDataPatch(policyreco v1alpha1.PolicyRecommendation, recoMetadata *reco.RecommendationMetadata,
+	staleData bool, staleSince time.Time, staleRecommendations int) *v1alpha1.PolicyRecommendation {
+	if staleData {
+		message := fmt.Sprintf("%s. The previous recommendation is kept since %s, for %d recommendations in a row",
+			recoMetadata.NoOp.Message, staleSince.Format(time.RFC3339), staleRecommendations)
+		stalePatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.StaleData, metav1.ConditionTrue, PreviousRecommendationKept, message)
+		since := metav1.NewTime(staleSince)
+		stalePatch.Status.StaleDataSince = &since
+		stalePatch.Status.StaleRecommendations = &staleRecommendations
+		return stalePatch
+	}
+	if !hasCondition(policyreco, v1alpha1.StaleData) {
+		return nil
+	}
+	if recoMetadata != nil && recoMetadata.NoOp != nil {
+		stalePatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.StaleData, metav1.ConditionFalse, StaleDataGraceExpired,
+			"The metrics of the workload fell short for longer than the grace period, the no-op configuration is recommended")
+		return stalePatch
+	}
+	stalePatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.StaleData, metav1.ConditionFalse, FreshData, FreshDataMessage)
+	return stalePatch
+}
+
+// createAnomalyPatch creates a status patch marking the policyreco with the AnomalousRecommendation condition while
+// its recommendation differs wildly from the previous one, and unmarking it once it doesn't. It returns nil if the
+// condition doesn't change.
+func createAnomalyPatch(policyreco v1alpha1.PolicyRecommendation, anomaly, reason string) *v1alpha1.PolicyRecommendation {
+	if len(anomaly) > 0 {
+		message := fmt.Sprintf("The recommendation differs wildly from the previous one as %s", anomaly)
+		switch reason {
+		case AnomalousRecommendationBlocked:
+			message = fmt.Sprintf("%s. The previous recommendation is kept until it's accepted with the %s annotation",
+				message, v1alpha1.AcceptRecommendationAnnotation)
+		case AnomalousRecommendationAccepted:
+			message = fmt.Sprintf("%s. It has been accepted with the %s annotation", message, v1alpha1.AcceptRecommendationAnnotation)
+		}
+		anomalyPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.AnomalousRecommendation, metav1.ConditionTrue, reason, message)
+		return anomalyPatch
+	}
+	if hasCondition(policyreco, v1alpha1.AnomalousRecommendation) {
+		anomalyPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.AnomalousRecommendation, metav1.ConditionFalse, ConsistentRecommendation, ConsistentRecommendationMessage)
+		return anomalyPatch
+	}
+	return nil
+}
+
+// createPinnedPatch creates a status patch marking the policyreco with the RecommendationPinned condition while its
+// workload is pinned to an HPA configuration, telling how far the target recommendation is from it, and unmarking it
+// once it isn't. It returns nil if the condition doesn't change.
+func createPinnedPatch(policyreco v1alpha1.PolicyRecommendation, target v1alpha1.HPAConfiguration) *v1alpha1.PolicyRecommendation {
+	if pinned := policyreco.Spec.PinnedHPAConfiguration; pinned != nil {
+		message := fmt.Sprintf("The workload is pinned to min %d, max %d and target %d while min %d, max %d and target %d "+
+			"is recommended, forgoing %.0f%% of its max replicas", pinned.Min, pinned.Max, pinned.TargetMetricValue,
+			target.Min, target.Max, target.TargetMetricValue, pinnedSavingsForgonePercent(*pinned, target))
+		pinnedPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.RecommendationPinned, metav1.ConditionTrue, WorkloadPinned, message)
+		return pinnedPatch
+	}
+	if hasCondition(policyreco, v1alpha1.RecommendationPinned) {
+		pinnedPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.RecommendationPinned, metav1.ConditionFalse, WorkloadUnpinned, WorkloadUnpinnedMessage)
+		return pinnedPatch
+	}
+	return nil
+}
+
+// createResizePatch creates a status patch marking the policyreco with the ResizeRecommended condition while the pods
+// of its workload need right-sizing rather than autoscaling, and unmarking it once they don't. It returns nil if the
+// condition doesn't change.
+func createResizePatch(policyreco v1alpha1.PolicyRecommendation, recoMetadata *reco.RecommendationMetadata) *v1alpha1.PolicyRecommendation {
+	if recoMetadata != nil && recoMetadata.Resize != nil {
+		resizePatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.ResizeRecommended, metav1.ConditionTrue, recoMetadata.Resize.Reason, recoMetadata.Resize.Message)
+		return resizePatch
+	}
+	if hasCondition(policyreco, v1alpha1.ResizeRecommended) {
+		resizePatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.ResizeRecommended, metav1.ConditionFalse, ResizeNotRequired, ResizeNotRequiredMessage)
+		return resizePatch
+	}
+	return nil
+}
+
+// createQuotaPatch creates a status patch marking the policyreco with the QuotaConstrained condition while the
+// ResourceQuotas of the namespace don't leave its workload the room to scale up to its recommended max replicas, and
+// unmarking it once they do. It returns nil if the condition doesn't change.
+func createQuotaPatch(policyreco v1alpha1.PolicyRecommendation, recoMetadata *reco.RecommendationMetadata) *v1alpha1.PolicyRecommendation {
+	if recoMetadata != nil && recoMetadata.QuotaConstraint != nil {
+		constraint := recoMetadata.QuotaConstraint
+		message := fmt.Sprintf("The ResourceQuotas %v allow the workload to scale up to %d replicas, so the max replicas is clamped from %d",
+			constraint.ResourceQuotas, constraint.MaxReplicas, constraint.RecommendedMaxReplicas)
+		quotaPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.QuotaConstrained, metav1.ConditionTrue, QuotaExceeded, message)
+		return quotaPatch
+	}
+	if hasCondition(policyreco, v1alpha1.QuotaConstrained) {
+		quotaPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.QuotaConstrained, metav1.ConditionFalse, QuotaSufficient, QuotaSufficientMessage)
+		return quotaPatch
+	}
+	return nil
+}
+
+// hasCondition returns true if the policyreco was last marked with the condition.
+func hasCondition(policyreco v1alpha1.PolicyRecommendation, condType v1alpha1.PolicyRecommendationConditionType) bool {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(condType) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isWorkloadInactive returns true if the policyreco was last marked with the WorkloadInactive condition.
+func isWorkloadInactive(policyreco v1alpha1.PolicyRecommendation) bool {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.WorkloadInactive) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// createRecommendationAppliedAuditRecord captures the change in the HPA configuration to be enforced on the workload.
+func createRecommendationAppliedAuditRecord(policyreco v1alpha1.PolicyRecommendation, hpaConfigToBeApplied *v1alpha1.HPAConfiguration,
+	policyName string, recoMetadata *reco.RecommendationMetadata) audit.Record {
+	oldConfig := policyreco.Spec.CurrentHPAConfiguration
+	record := audit.Record{
+		Action:       audit.RecommendationApplied,
+		Controller:   PolicyRecoWorkflowCtrlName,
+		Namespace:    policyreco.Namespace,
+		WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+		Workload:     policyreco.Spec.WorkloadMeta.Name,
+		OldConfig:    &oldConfig,
+		NewConfig:    hpaConfigToBeApplied,
+		OldPolicy:    policyreco.Spec.Policy,
+		Policy:       policyName,
+		Reason:       recoTriggerReason(policyreco),
+	}
+	if recoMetadata != nil {
+		record.ProjectedSavingsPercent = &recoMetadata.ProjectedSavingsPercent
+	}
+	return record
+}
+
+// notifyTransitions notifies the change in the HPA configuration to be enforced on the workload along with the
+// change in its policy, if any.
+func (r *PolicyRecommendationReconciler) notifyTransitions(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	hpaConfigToBeApplied *v1alpha1.HPAConfiguration, policyName string) {
+	oldConfig := policyreco.Spec.CurrentHPAConfiguration
+	event := notifier.Event{
+		Type:         notifier.RecommendationChanged,
+		Namespace:    policyreco.Namespace,
+		WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+		Workload:     policyreco.Spec.WorkloadMeta.Name,
+		OldPolicy:    policyreco.Spec.Policy,
+		Policy:       policyName,
+		OldConfig:    &oldConfig,
+		NewConfig:    hpaConfigToBeApplied,
+	}
+	if !oldConfig.DeepEquals(*hpaConfigToBeApplied) {
+		r.Notifier.Notify(ctx, event)
+	}
+
+	if len(policyreco.Spec.Policy) == 0 || policyreco.Spec.Policy == policyName {
+		return
+	}
+	eventType, err := r.policyTransitionType(policyreco, policyName)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).WithName(PolicyRecoWorkflowCtrlName).Error(err, "Error determining the policy transition. Skipping the notification.")
+		return
+	}
+	event.Type = eventType
+	r.Notifier.Notify(ctx, event)
+}
+
+// policyTransitionType classifies the move of the workload from its current policy to the new policy by their
+// risk indices. A move to a safer policy on a breach is a rollback.
+func (r *PolicyRecommendationReconciler) policyTransitionType(policyreco v1alpha1.PolicyRecommendation,
+	policyName string) (notifier.EventType, error) {
+	oldPolicy, err := r.PolicyStore.GetPolicyByName(policyreco.Spec.Policy)
+	if err != nil {
+		return "", err
+	}
+	newPolicy, err := r.PolicyStore.GetPolicyByName(policyName)
+	if err != nil {
+		return "", err
+	}
+	if newPolicy.Spec.RiskIndex > oldPolicy.Spec.RiskIndex {
+		return notifier.PolicyPromoted, nil
+	}
+	if recoTriggerReason(policyreco) == trigger.BreachDetectedReason {
+		return notifier.PolicyRolledBack, nil
+	}
+	return notifier.PolicyDemoted, nil
+}
+
+// reviewPolicyTransition submits the transition of the workload to the new policy to the change management system,
+// if it's gated, and tells whether it can be enforced, along with its change request if it's gated. A transition
+// which can't be reviewed is held back.
+func (r *PolicyRecommendationReconciler) reviewPolicyTransition(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	hpaConfigToBeApplied *v1alpha1.HPAConfiguration, policyName string, now time.Time) (*v1alpha1.ChangeRequest, bool) {
+	if r.ChangeApproval.Gate == nil || len(policyreco.Spec.Policy) == 0 || policyreco.Spec.Policy == policyName {
+		return nil, true
+	}
+	logger := ctrl.LoggerFrom(ctx).WithName(PolicyRecoWorkflowCtrlName)
+	transition, err := r.policyTransitionType(policyreco, policyName)
+	if err != nil {
+		logger.Error(err, "Error determining the policy transition. Holding it back.")
+		return nil, false
+	}
+	if !r.ChangeApproval.Gate.Gates(transition) {
+		return nil, true
+	}
+	oldConfig := policyreco.Spec.CurrentHPAConfiguration
+	request := approval.Request{
+		Transition:   transition,
+		Namespace:    policyreco.Namespace,
+		WorkloadKind: policyreco.Spec.WorkloadMeta.Kind,
+		Workload:     policyreco.Spec.WorkloadMeta.Name,
+		OldPolicy:    policyreco.Spec.Policy,
+		Policy:       policyName,
+		OldConfig:    &oldConfig,
+		NewConfig:    hpaConfigToBeApplied,
+	}
+	if r.ChangeApproval.Previewer != nil && hpaConfigToBeApplied != nil {
+		// the transition is still reviewed without the preview, which only adds to the context of the reviewers
+		if request.Preview, err = r.ChangeApproval.Previewer.PreviewEnforcement(ctx, policyreco, *hpaConfigToBeApplied); err != nil {
+			logger.Error(err, "Error previewing the enforcement of the policy transition. Submitting it without the preview.")
+		}
+	}
+	changeRequest, err := r.ChangeApproval.Gate.Review(ctx, policyreco.Status.ChangeRequest, request, now)
+	if err != nil {
+		logger.Error(err, "Error reviewing the policy transition with the change management system. Holding it back.")
+		return nil, false
+	}
+	if changeRequest.Status != v1alpha1.ChangeRequestApproved {
+		return changeRequest, false
+	}
+	// the approved transition is enforced right away, which spends the approval
+	enforced := changeRequest.DeepCopy()
+	enforcedAt := metav1.NewTime(now)
+	enforced.EnforcedAt = &enforcedAt
+	return enforced, true
+}
+
+// recoTriggerReason returns why the recommendation was regenerated for the policyreco.
+func recoTriggerReason(policyreco v1alpha1.PolicyRecommendation) string {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.HasBreached) && condition.Status == metav1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.RecoTaskQueued) && condition.Status == metav1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+func isRecommendationAccepted(policyreco v1alpha1.PolicyRecommendation) bool {
+	accepted, _ := strconv.ParseBool(policyreco.GetAnnotations()[v1alpha1.AcceptRecommendationAnnotation])
+	return accepted
+}
+
+// removeAcceptAnnotation removes the AcceptRecommendationAnnotation of the policyreco once the anomalous
+// recommendation it accepted is applied, so that it doesn't accept the later ones too.
+func (r *PolicyRecommendationReconciler) removeAcceptAnnotation(ctx context.Context, policyreco v1alpha1.PolicyRecommendation) error {
+	accepted := policyreco.DeepCopy()
+	patch := client.MergeFrom(policyreco.DeepCopy())
+	delete(accepted.Annotations, v1alpha1.AcceptRecommendationAnnotation)
+	return client.IgnoreNotFound(r.Patch(ctx, accepted, patch))
+}
+
+func isRecommendationFrozen(policyreco v1alpha1.PolicyRecommendation) bool {
+	frozen, _ := strconv.ParseBool(policyreco.GetAnnotations()[v1alpha1.FreezeRecommendationAnnotation])
+	return frozen
+}
+
 func logCurrentHPAConfiguration(policyreco v1alpha1.PolicyRecommendation, currentHPAReco *v1alpha1.HPAConfiguration) {
 	policyRecoCurrentMin.WithLabelValues(policyreco.Namespace, policyreco.Name).Set(float64(currentHPAReco.Min))
 	policyRecoCurrentMax.WithLabelValues(policyreco.Namespace, policyreco.Name).Set(float64(currentHPAReco.Max))
@@ -295,6 +961,19 @@ func logTargetHPAConfiguration(policyreco v1alpha1.PolicyRecommendation, targetH
 	policyRecoTargetUtil.WithLabelValues(policyreco.Namespace, policyreco.Name).Set(float64(targetHPAReco.TargetMetricValue))
 }
 
+// logRecoDrift captures how far the current policy config is from the target reco. A positive drift is the
+// savings yet to be unlocked by moving up the policy ladder.
+func logRecoDrift(policyreco v1alpha1.PolicyRecommendation, targetHPAReco *v1alpha1.HPAConfiguration, currentHPAReco *v1alpha1.HPAConfiguration) {
+	policyRecoUtilDrift.WithLabelValues(policyreco.Namespace, policyreco.Name).Set(float64(targetHPAReco.TargetMetricValue - currentHPAReco.TargetMetricValue))
+	policyRecoMinReplicasDrift.WithLabelValues(policyreco.Namespace, policyreco.Name).Set(float64(currentHPAReco.Min - targetHPAReco.Min))
+}
+
+// deleteRecoDrift drops the drift of a deleted policy reco so that it isn't reported as yet to be unlocked.
+func deleteRecoDrift(namespace, name string) {
+	policyRecoUtilDrift.DeleteLabelValues(namespace, name)
+	policyRecoMinReplicasDrift.DeleteLabelValues(namespace, name)
+}
+
 func logPolicyRecoGaugeMetric(policyreco v1alpha1.PolicyRecommendation, condition v1alpha1.PolicyRecommendationConditionType, status metav1.ConditionStatus) {
 	if status == metav1.ConditionTrue {
 		policyRecoConditionsGauge.WithLabelValues(policyreco.Namespace, policyreco.Name, string(condition), string(metav1.ConditionTrue)).Set(1)
@@ -341,6 +1020,33 @@ func fetchInitializedTime(policyreco *v1alpha1.PolicyRecommendation) time.Time {
 	return time.Now()
 }
 
+// applyWorkloadOverrides clamps the given HPA configuration to the floor and ceiling constraints specified by the
+// workload owners in the PolicyRecommendation spec. The ceiling is applied first so that the floor wins when both
+// the recommendation and the constraints conflict.
+func applyWorkloadOverrides(hpaConfig *v1alpha1.HPAConfiguration, spec v1alpha1.PolicyRecommendationSpec) *v1alpha1.HPAConfiguration {
+	if hpaConfig == nil {
+		return nil
+	}
+	clamped := *hpaConfig
+	if spec.MaxReplicaCeiling != nil && clamped.Max > *spec.MaxReplicaCeiling {
+		clamped.Max = *spec.MaxReplicaCeiling
+		if clamped.Min > clamped.Max {
+			clamped.Min = clamped.Max
+		}
+	}
+	if spec.MinReplicaFloor != nil && clamped.Min < *spec.MinReplicaFloor {
+		clamped.Min = *spec.MinReplicaFloor
+		if clamped.Max < clamped.Min {
+			clamped.Max = clamped.Min
+		}
+	}
+	if spec.MaxTargetUtilization != nil && clamped.GetTargetMetricType() == v1alpha1.UtilizationMetricTarget &&
+		clamped.TargetMetricValue > *spec.MaxTargetUtilization {
+		clamped.TargetMetricValue = *spec.MaxTargetUtilization
+	}
+	return &clamped
+}
+
 func retrieveTransitionTime(hpaConfigToBeApplied *v1alpha1.HPAConfiguration, policyreco *v1alpha1.PolicyRecommendation, generatedAt metav1.Time) metav1.Time {
 	if hpaConfigToBeApplied == nil && policyreco != nil {
 		return *policyreco.Spec.TransitionedAt
@@ -400,10 +1106,29 @@ func (r *PolicyRecommendationReconciler) SetupWithManager(mgr ctrl.Manager) erro
 		},
 	}
 	compoundPredicate := predicate.And(predicate.GenerationChangedPredicate{}, queuedTaskPredicate)
+	if !r.InteractiveQueue.enabled() {
+		return ctrl.NewControllerManagedBy(mgr).
+			For(&v1alpha1.PolicyRecommendation{}).
+			WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+			WithEventFilter(compoundPredicate).
+			Named(PolicyRecoWorkflowCtrlName).
+			Complete(r)
+	}
+
+	// the policy recos queued by the users go through a queue of their own, with its own rate limits and workers
+	interactivePredicate := predicate.NewPredicateFuncs(isInteractivelyQueued)
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.PolicyRecommendation{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.InteractiveQueue.MaxConcurrentReconciles}).
+		WithEventFilter(predicate.And(compoundPredicate, interactivePredicate)).
+		Named(PolicyRecoInteractiveWorkflowCtrlName).
+		Complete(r); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.PolicyRecommendation{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
-		WithEventFilter(compoundPredicate).
+		WithEventFilter(predicate.And(compoundPredicate, predicate.Not(interactivePredicate))).
 		Named(PolicyRecoWorkflowCtrlName).
 		Complete(r)
 }
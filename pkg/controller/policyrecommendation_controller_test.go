@@ -8,13 +8,20 @@ import (
 
 	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/approval"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("PolicyrecommendationController", func() {
@@ -154,6 +161,12 @@ var _ = Describe("PolicyrecommendationController", func() {
 			Expect(updatedPolicy.Namespace).Should(Equal(PolicyRecoNamespace))
 			Expect(*updatedPolicy.Spec.QueuedForExecution).Should(BeFalse())
 			Expect(updatedPolicy.Spec.GeneratedAt).Should(Equal(updatedPolicy.Spec.TransitionedAt))
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx,
+					types.NamespacedName{Name: PolicyRecoName, Namespace: PolicyRecoNamespace},
+					updatedPolicy)
+				return err == nil && updatedPolicy.Status.GeneratedAt != nil
+			}, timeout, interval).Should(BeTrue())
 
 			By("Aging the policy again")
 			time.Sleep(2 * policyAge)
@@ -1092,3 +1105,509 @@ func queueRecoByUpdateOp(name, namespace string) error {
 	fmt.Fprintf(GinkgoWriter, "Policy after queuing update %s \n", policyString)
 	return err
 }
+
+var _ = Describe("applyWorkloadOverrides", func() {
+	intPtr := func(i int) *int { return &i }
+
+	It("Should return the config as is when no overrides are set", func() {
+		config := &v1alpha1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 60}
+		Expect(*applyWorkloadOverrides(config, v1alpha1.PolicyRecommendationSpec{})).Should(Equal(*config))
+	})
+
+	It("Should return nil for a nil config", func() {
+		Expect(applyWorkloadOverrides(nil, v1alpha1.PolicyRecommendationSpec{MinReplicaFloor: intPtr(3)})).Should(BeNil())
+	})
+
+	It("Should clamp the config to the floor, ceiling and max utilization", func() {
+		config := &v1alpha1.HPAConfiguration{Min: 2, Max: 40, TargetMetricValue: 70}
+		clamped := applyWorkloadOverrides(config, v1alpha1.PolicyRecommendationSpec{
+			MinReplicaFloor:      intPtr(4),
+			MaxReplicaCeiling:    intPtr(30),
+			MaxTargetUtilization: intPtr(50),
+		})
+		Expect(*clamped).Should(Equal(v1alpha1.HPAConfiguration{Min: 4, Max: 30, TargetMetricValue: 50}))
+		Expect(config.Min).Should(Equal(2))
+	})
+
+	It("Should keep min within max when the ceiling is below the recommended min", func() {
+		config := &v1alpha1.HPAConfiguration{Min: 25, Max: 40, TargetMetricValue: 60}
+		clamped := applyWorkloadOverrides(config, v1alpha1.PolicyRecommendationSpec{MaxReplicaCeiling: intPtr(20)})
+		Expect(*clamped).Should(Equal(v1alpha1.HPAConfiguration{Min: 20, Max: 20, TargetMetricValue: 60}))
+	})
+
+	It("Should raise max when the floor is above the recommended max", func() {
+		config := &v1alpha1.HPAConfiguration{Min: 2, Max: 5, TargetMetricValue: 60}
+		clamped := applyWorkloadOverrides(config, v1alpha1.PolicyRecommendationSpec{MinReplicaFloor: intPtr(8)})
+		Expect(*clamped).Should(Equal(v1alpha1.HPAConfiguration{Min: 8, Max: 8, TargetMetricValue: 60}))
+	})
+	It("Should not clamp targets which are not utilization percentages", func() {
+		config := &v1alpha1.HPAConfiguration{Min: 2, Max: 5, TargetMetricValue: 400, MetricName: "http_requests_per_second", TargetMetricType: v1alpha1.AverageValueMetricTarget}
+		clamped := applyWorkloadOverrides(config, v1alpha1.PolicyRecommendationSpec{MaxTargetUtilization: intPtr(50)})
+		Expect(clamped.TargetMetricValue).Should(Equal(400))
+	})
+})
+
+var _ = Describe("isRecommendationFrozen", func() {
+	It("should be frozen only when the freeze annotation is set to true", func() {
+		policyreco := v1alpha1.PolicyRecommendation{}
+		Expect(isRecommendationFrozen(policyreco)).Should(BeFalse())
+
+		policyreco.SetAnnotations(map[string]string{v1alpha1.FreezeRecommendationAnnotation: "false"})
+		Expect(isRecommendationFrozen(policyreco)).Should(BeFalse())
+
+		policyreco.SetAnnotations(map[string]string{v1alpha1.FreezeRecommendationAnnotation: "true"})
+		Expect(isRecommendationFrozen(policyreco)).Should(BeTrue())
+	})
+})
+
+var _ = Describe("cronTriggersForConfig", func() {
+	It("should keep the cron triggers pre-scaling beyond the min replicas within the max replicas", func() {
+		recoMetadata := &reco.RecommendationMetadata{CronTriggers: []v1alpha1.CronTrigger{
+			{Start: "45 7 * * *", End: "0 10 * * *", Timezone: "UTC", DesiredReplicas: 6},
+			{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "UTC", DesiredReplicas: 40},
+		}}
+		config := &v1alpha1.HPAConfiguration{Min: 8, Max: 30, TargetMetricValue: 60}
+		Expect(cronTriggersForConfig(recoMetadata, config)).Should(Equal([]v1alpha1.CronTrigger{
+			{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "UTC", DesiredReplicas: 30},
+		}))
+		Expect(cronTriggersForConfig(nil, config)).Should(BeNil())
+	})
+})
+
+var _ = Describe("scalingSchedule", func() {
+	cronTriggers := []v1alpha1.CronTrigger{{Start: "45 19 * * *", End: "0 21 * * *", Timezone: "UTC", DesiredReplicas: 30}}
+	idleWindow := &v1alpha1.IdleWindow{Start: "0 1 * * *", End: "0 5 * * *", Timezone: "UTC", MinReplicas: 2}
+
+	It("should schedule the recommended replicas on top of the HPA config", func() {
+		recoMetadata := &reco.RecommendationMetadata{CronTriggers: cronTriggers, IdleWindow: idleWindow}
+		spec := v1alpha1.PolicyRecommendationSpec{}
+		scalingScheduleForConfig(recoMetadata, &v1alpha1.HPAConfiguration{Min: 8, Max: 30}).applyTo(&spec)
+		Expect(spec.CronTriggers).Should(Equal(cronTriggers))
+		Expect(spec.IdleWindow).Should(Equal(idleWindow))
+	})
+
+	It("should carry the scaling schedule of a spec over to another", func() {
+		previous := v1alpha1.PolicyRecommendationSpec{CronTriggers: cronTriggers, IdleWindow: idleWindow}
+		spec := v1alpha1.PolicyRecommendationSpec{}
+		scalingScheduleOf(previous).applyTo(&spec)
+		Expect(spec).Should(Equal(previous))
+	})
+})
+
+var _ = Describe("createWorkloadHistoryPatch", func() {
+	It("should mark the workloads with insufficient history till they are old enough", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createWorkloadHistoryPatch(policyreco, nil)).Should(BeNil())
+		Expect(createWorkloadHistoryPatch(policyreco, &reco.RecommendationMetadata{WorkloadAge: 50 * time.Hour})).Should(BeNil())
+
+		historyPatch := createWorkloadHistoryPatch(policyreco, &reco.RecommendationMetadata{InsufficientHistory: true, WorkloadAge: 50 * time.Hour})
+		Expect(historyPatch.Status.Conditions).Should(HaveLen(1))
+		Expect(historyPatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.InsufficientHistory)))
+		Expect(historyPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(historyPatch.Status.Conditions[0].Reason).Should(Equal(WorkloadBootstrapping))
+		Expect(historyPatch.Status.Conditions[0].Message).Should(ContainSubstring("50h0m0s ago"))
+
+		policyreco.Status.Conditions = historyPatch.Status.Conditions
+		historyPatch = createWorkloadHistoryPatch(policyreco, &reco.RecommendationMetadata{WorkloadAge: 8 * 24 * time.Hour})
+		Expect(historyPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(historyPatch.Status.Conditions[0].Reason).Should(Equal(SufficientHistory))
+	})
+})
+
+var _ = Describe("createNoOpPatch", func() {
+	It("should mark the workloads recommended the no-op configuration till they are recommended a config", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createNoOpPatch(policyreco, nil)).Should(BeNil())
+		Expect(createNoOpPatch(policyreco, &reco.RecommendationMetadata{})).Should(BeNil())
+
+		noOpPatch := createNoOpPatch(policyreco, &reco.RecommendationMetadata{NoOp: &reco.NoOpRecommendation{
+			Reason: reco.InsufficientMetricsReason, Message: "Only 20% of the datapoints of the metrics window are available"}})
+		Expect(noOpPatch.Status.Conditions).Should(HaveLen(1))
+		Expect(noOpPatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.NoOpRecommended)))
+		Expect(noOpPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(noOpPatch.Status.Conditions[0].Reason).Should(Equal(reco.InsufficientMetricsReason))
+
+		policyreco.Status.Conditions = noOpPatch.Status.Conditions
+		noOpPatch = createNoOpPatch(policyreco, &reco.RecommendationMetadata{ProjectedSavingsPercent: 30})
+		Expect(noOpPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(noOpPatch.Status.Conditions[0].Reason).Should(Equal(WorkloadRecommended))
+	})
+
+	It("should not project any savings for the no-op configuration", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		metadataPatch := CreateRecoMetadataPatch(policyreco, metav1.Now(), &reco.RecommendationMetadata{
+			DataPointsCoveragePercent: 20, NoOp: &reco.NoOpRecommendation{Reason: reco.InsufficientMetricsReason}})
+		Expect(*metadataPatch.Status.DataPointsCoveragePercent).Should(Equal(20))
+		Expect(metadataPatch.Status.ProjectedSavingsPercent).Should(BeNil())
+	})
+
+	It("should record the confidence of the recommendation", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		metadataPatch := CreateRecoMetadataPatch(policyreco, metav1.Now(), &reco.RecommendationMetadata{
+			ProjectedSavingsPercent: 30, Confidence: &reco.RecommendationConfidence{Score: 81}})
+		Expect(*metadataPatch.Status.ProjectedSavingsPercent).Should(Equal(30))
+		Expect(*metadataPatch.Status.ConfidencePercent).Should(Equal(81))
+		Expect(CreateRecoMetadataPatch(policyreco, metav1.Now(), &reco.RecommendationMetadata{}).Status.ConfidencePercent).Should(BeNil())
+	})
+
+	It("should record the search space of the recommendation", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		searchSpace := []v1alpha1.SearchSpacePoint{{MinReplicas: 3, TargetUtilization: 48, ProjectedSavingsPercent: 41},
+			{MinReplicas: 4, TargetUtilization: 52, ProjectedSavingsPercent: 38}}
+		metadataPatch := CreateRecoMetadataPatch(policyreco, metav1.Now(), &reco.RecommendationMetadata{
+			ProjectedSavingsPercent: 41, SearchSpace: searchSpace})
+		Expect(metadataPatch.Status.SearchSpace).Should(Equal(searchSpace))
+		Expect(CreateRecoMetadataPatch(policyreco, metav1.Now(), &reco.RecommendationMetadata{}).Status.SearchSpace).Should(BeNil())
+	})
+})
+
+var _ = Describe("RecommendationDiffThreshold", func() {
+	current := v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 62}
+
+	It("should enforce every change without a threshold", func() {
+		threshold := RecommendationDiffThreshold{}
+		Expect(threshold.differsMaterially(current, current)).Should(BeFalse())
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 63})).Should(BeTrue())
+	})
+
+	It("should not enforce the changes below the threshold", func() {
+		threshold := RecommendationDiffThreshold{TargetMetricValue: 3, MinReplicas: 2}
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 11, Max: 30, TargetMetricValue: 63})).Should(BeFalse())
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 59})).Should(BeTrue())
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 8, Max: 30, TargetMetricValue: 62})).Should(BeTrue())
+	})
+
+	It("should apply separate thresholds to the raises and the cuts of the target", func() {
+		raise, lower := 5, 0
+		threshold := RecommendationDiffThreshold{TargetMetricValue: 3, TargetMetricValueRaise: &raise, TargetMetricValueLower: &lower}
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 66})).Should(BeFalse())
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 67})).Should(BeTrue())
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 61})).Should(BeTrue())
+		threshold.TargetMetricValueLower = nil
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 60})).Should(BeFalse())
+	})
+
+	It("should always enforce the changes of the max replicas and the metric", func() {
+		threshold := RecommendationDiffThreshold{TargetMetricValue: 3, MinReplicas: 2}
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 31, TargetMetricValue: 62})).Should(BeTrue())
+		Expect(threshold.differsMaterially(current, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 62,
+			MetricName: "memory"})).Should(BeTrue())
+		Expect(threshold.differsMaterially(v1alpha1.HPAConfiguration{}, current)).Should(BeTrue())
+	})
+
+	It("should keep back the changes below the threshold only within the same policy", func() {
+		threshold := RecommendationDiffThreshold{TargetMetricValue: 3, MinReplicas: 2}
+		policyreco := v1alpha1.PolicyRecommendation{Spec: v1alpha1.PolicyRecommendationSpec{Policy: "safest",
+			CurrentHPAConfiguration: current}}
+		step := v1alpha1.HPAConfiguration{Min: 9, Max: 30, TargetMetricValue: 64}
+		Expect(threshold.suppressesUpdate(policyreco, "safest", step)).Should(BeTrue())
+		Expect(threshold.suppressesUpdate(policyreco, "safest", current)).Should(BeFalse())
+		// the step up the ladder goes through even though its config is within the threshold
+		Expect(threshold.suppressesUpdate(policyreco, "safe", step)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("AnomalyGuard", func() {
+	previous := v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 40}
+
+	It("should not flag any recommendation without the thresholds", func() {
+		Expect(AnomalyGuard{}.detect(previous, v1alpha1.HPAConfiguration{Min: 1, Max: 30, TargetMetricValue: 90})).Should(BeEmpty())
+	})
+
+	It("should flag the wild drops of the min replicas and the jumps of the target", func() {
+		guard := AnomalyGuard{MinDropPercent: 70, TargetJumpPoints: 30}
+		Expect(guard.detect(previous, v1alpha1.HPAConfiguration{Min: 3, Max: 30, TargetMetricValue: 40})).Should(BeEmpty())
+		Expect(guard.detect(previous, v1alpha1.HPAConfiguration{Min: 2, Max: 30, TargetMetricValue: 40})).
+			Should(Equal("the min replicas dropped from 10 to 2"))
+		Expect(guard.detect(previous, v1alpha1.HPAConfiguration{Min: 20, Max: 30, TargetMetricValue: 40})).Should(BeEmpty())
+		Expect(guard.detect(previous, v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 69})).Should(BeEmpty())
+		Expect(guard.detect(previous, v1alpha1.HPAConfiguration{Min: 1, Max: 30, TargetMetricValue: 10})).
+			Should(Equal("the min replicas dropped from 10 to 1 and the target moved from 40 to 10"))
+		Expect(guard.detect(v1alpha1.HPAConfiguration{}, v1alpha1.HPAConfiguration{Min: 1, Max: 30, TargetMetricValue: 90})).Should(BeEmpty())
+	})
+
+	It("should mark the anomalous recommendations till they aren't", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createAnomalyPatch(policyreco, "", "")).Should(BeNil())
+
+		anomalyPatch := createAnomalyPatch(policyreco, "the min replicas dropped from 10 to 2", AnomalousRecommendationBlocked)
+		Expect(anomalyPatch.Status.Conditions).Should(HaveLen(1))
+		Expect(anomalyPatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.AnomalousRecommendation)))
+		Expect(anomalyPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(anomalyPatch.Status.Conditions[0].Reason).Should(Equal(AnomalousRecommendationBlocked))
+		Expect(anomalyPatch.Status.Conditions[0].Message).Should(ContainSubstring(v1alpha1.AcceptRecommendationAnnotation))
+
+		policyreco.Status.Conditions = anomalyPatch.Status.Conditions
+		anomalyPatch = createAnomalyPatch(policyreco, "", "")
+		Expect(anomalyPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(anomalyPatch.Status.Conditions[0].Reason).Should(Equal(ConsistentRecommendation))
+	})
+})
+
+var _ = Describe("createPinnedPatch", func() {
+	target := v1alpha1.HPAConfiguration{Min: 4, Max: 20, TargetMetricValue: 60}
+
+	It("should mark the pinned workloads with the savings they forgo till they aren't", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createPinnedPatch(policyreco, target)).Should(BeNil())
+
+		policyreco.Spec.PinnedHPAConfiguration = &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50}
+		pinnedPatch := createPinnedPatch(policyreco, target)
+		Expect(pinnedPatch.Status.Conditions).Should(HaveLen(1))
+		Expect(pinnedPatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.RecommendationPinned)))
+		Expect(pinnedPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(pinnedPatch.Status.Conditions[0].Reason).Should(Equal(WorkloadPinned))
+		Expect(pinnedPatch.Status.Conditions[0].Message).Should(ContainSubstring("forgoing 30%"))
+
+		policyreco.Spec.PinnedHPAConfiguration = nil
+		policyreco.Status.Conditions = pinnedPatch.Status.Conditions
+		pinnedPatch = createPinnedPatch(policyreco, target)
+		Expect(pinnedPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(pinnedPatch.Status.Conditions[0].Reason).Should(Equal(WorkloadUnpinned))
+	})
+
+	It("should forgo no savings when the pin runs fewer min replicas than the target", func() {
+		Expect(pinnedSavingsForgonePercent(v1alpha1.HPAConfiguration{Min: 2, Max: 20}, target)).Should(BeZero())
+		Expect(pinnedSavingsForgonePercent(v1alpha1.HPAConfiguration{}, target)).Should(BeZero())
+	})
+})
+
+var _ = Describe("createResizePatch", func() {
+	It("should mark the workloads which need resizing till they don't", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createResizePatch(policyreco, &reco.RecommendationMetadata{})).Should(BeNil())
+
+		resizePatch := createResizePatch(policyreco, &reco.RecommendationMetadata{Resize: &reco.ResizeSignal{
+			Reason: reco.OversizedPodsReason, Message: "oversized"}})
+		Expect(resizePatch.Status.Conditions).Should(HaveLen(1))
+		Expect(resizePatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.ResizeRecommended)))
+		Expect(resizePatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(resizePatch.Status.Conditions[0].Reason).Should(Equal(reco.OversizedPodsReason))
+
+		policyreco.Status.Conditions = resizePatch.Status.Conditions
+		resizePatch = createResizePatch(policyreco, &reco.RecommendationMetadata{})
+		Expect(resizePatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(resizePatch.Status.Conditions[0].Reason).Should(Equal(ResizeNotRequired))
+	})
+})
+
+var _ = Describe("createQuotaPatch", func() {
+	It("should mark the workloads constrained by the quotas till they aren't", func() {
+		policyreco := v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"}}
+		Expect(createQuotaPatch(policyreco, &reco.RecommendationMetadata{})).Should(BeNil())
+
+		quotaPatch := createQuotaPatch(policyreco, &reco.RecommendationMetadata{QuotaConstraint: &reco.QuotaConstraint{
+			ResourceQuotas: []string{"cpu-quota"}, MaxReplicas: 6, RecommendedMaxReplicas: 20}})
+		Expect(quotaPatch.Status.Conditions).Should(HaveLen(1))
+		Expect(quotaPatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.QuotaConstrained)))
+		Expect(quotaPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(quotaPatch.Status.Conditions[0].Reason).Should(Equal(QuotaExceeded))
+		Expect(quotaPatch.Status.Conditions[0].Message).Should(ContainSubstring("clamped from 20"))
+
+		policyreco.Status.Conditions = quotaPatch.Status.Conditions
+		quotaPatch = createQuotaPatch(policyreco, &reco.RecommendationMetadata{})
+		Expect(quotaPatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(quotaPatch.Status.Conditions[0].Reason).Should(Equal(QuotaSufficient))
+	})
+})
+
+var _ = Describe("StaleDataGrace", func() {
+	insufficientMetrics := &reco.RecommendationMetadata{NoOp: &reco.NoOpRecommendation{
+		Reason: reco.InsufficientMetricsReason, Message: "Only 20% of the datapoints of the metrics window are available"}}
+	now := time.Now()
+
+	recommended := func() v1alpha1.PolicyRecommendation {
+		return v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{TargetHPAConfiguration: v1alpha1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 60}}}
+	}
+
+	It("should not keep the previous recommendation without a grace", func() {
+		keep, _, _ := StaleDataGrace{}.keepsPreviousRecommendation(recommended(), insufficientMetrics, now)
+		Expect(keep).Should(BeFalse())
+	})
+
+	It("should keep the previous recommendation only for the insufficient metrics of a recommended workload", func() {
+		grace := StaleDataGrace{Period: 24 * time.Hour}
+		keep, staleSince, staleRecommendations := grace.keepsPreviousRecommendation(recommended(), insufficientMetrics, now)
+		Expect(keep).Should(BeTrue())
+		Expect(staleSince).Should(Equal(now))
+		Expect(staleRecommendations).Should(Equal(1))
+
+		keep, _, _ = grace.keepsPreviousRecommendation(recommended(), &reco.RecommendationMetadata{NoOp: &reco.NoOpRecommendation{
+			Reason: reco.NoBreachFreeConfigReason}}, now)
+		Expect(keep).Should(BeFalse())
+		keep, _, _ = grace.keepsPreviousRecommendation(v1alpha1.PolicyRecommendation{}, insufficientMetrics, now)
+		Expect(keep).Should(BeFalse())
+	})
+
+	It("should recommend the no-op configuration once the period and the recommendations of the grace are over", func() {
+		grace := StaleDataGrace{Period: 24 * time.Hour, Recommendations: 3}
+		policyreco := recommended()
+		stalePatch := createStaleDataPatch(policyreco, insufficientMetrics, true, now.Add(-30*time.Hour), 3)
+		Expect(stalePatch.Status.Conditions[0].Type).Should(Equal(string(v1alpha1.StaleData)))
+		Expect(stalePatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(stalePatch.Status.Conditions[0].Reason).Should(Equal(PreviousRecommendationKept))
+		policyreco.Status = stalePatch.Status
+
+		keep, staleSince, staleRecommendations := grace.keepsPreviousRecommendation(policyreco, insufficientMetrics, now)
+		Expect(keep).Should(BeFalse())
+		Expect(staleSince).Should(BeTemporally("==", now.Add(-30*time.Hour)))
+		Expect(staleRecommendations).Should(Equal(4))
+
+		stalePatch = createStaleDataPatch(policyreco, insufficientMetrics, keep, staleSince, staleRecommendations)
+		Expect(stalePatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(stalePatch.Status.Conditions[0].Reason).Should(Equal(StaleDataGraceExpired))
+		Expect(stalePatch.Status.StaleRecommendations).Should(BeNil())
+
+		grace.Period = 48 * time.Hour
+		keep, _, _ = grace.keepsPreviousRecommendation(policyreco, insufficientMetrics, now)
+		Expect(keep).Should(BeTrue())
+	})
+
+	It("should unmark the stale data once the metrics are sufficient", func() {
+		policyreco := recommended()
+		Expect(createStaleDataPatch(policyreco, &reco.RecommendationMetadata{}, false, time.Time{}, 0)).Should(BeNil())
+		policyreco.Status = createStaleDataPatch(policyreco, insufficientMetrics, true, now, 1).Status
+		stalePatch := createStaleDataPatch(policyreco, &reco.RecommendationMetadata{}, false, time.Time{}, 0)
+		Expect(stalePatch.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(stalePatch.Status.Conditions[0].Reason).Should(Equal(FreshData))
+	})
+})
+
+// fakeApprover decides the change requests with the status it's set to.
+type fakeApprover struct {
+	submitted []approval.Request
+	status    v1alpha1.ChangeRequestStatus
+}
+
+func (f *fakeApprover) Submit(ctx context.Context, request approval.Request) (string, v1alpha1.ChangeRequestStatus, error) {
+	f.submitted = append(f.submitted, request)
+	return fmt.Sprintf("CHG%d", len(f.submitted)), v1alpha1.ChangeRequestPending, nil
+}
+
+func (f *fakeApprover) Status(ctx context.Context, ticketID string) (v1alpha1.ChangeRequestStatus, error) {
+	return f.status, nil
+}
+
+// policiesByNameStore looks up the policies of the FakePolicyStore by their name, along with their risk indices.
+type policiesByNameStore struct {
+	*FakePolicyStore
+}
+
+func (ps policiesByNameStore) GetPolicyByName(name string) (*v1alpha1.Policy, error) {
+	for _, policy := range ps.policies {
+		if policy.Name == name {
+			return &policy, nil
+		}
+	}
+	return nil, fmt.Errorf("policy %s not found", name)
+}
+
+var _ = Describe("ChangeApproval", func() {
+	var approver *fakeApprover
+	var reconciler *PolicyRecommendationReconciler
+	now := time.Now()
+	config := &v1alpha1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 60}
+
+	atPolicy := func(policyName string) v1alpha1.PolicyRecommendation {
+		return v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{Policy: policyName,
+				WorkloadMeta: v1alpha1.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "test-workload"}}}
+	}
+
+	BeforeEach(func() {
+		approver = &fakeApprover{status: v1alpha1.ChangeRequestPending}
+		reconciler = &PolicyRecommendationReconciler{PolicyStore: policiesByNameStore{newFakePolicyStore()},
+			ChangeApproval: ChangeApproval{Gate: approval.NewGate(approver, time.Hour), PollInterval: time.Minute}}
+	})
+
+	It("should not hold back the transitions without a gate or which aren't gated", func() {
+		changeRequest, approved := (&PolicyRecommendationReconciler{}).reviewPolicyTransition(context.TODO(),
+			atPolicy("policy-1"), config, "policy-2", now)
+		Expect(approved).Should(BeTrue())
+		Expect(changeRequest).Should(BeNil())
+
+		changeRequest, approved = reconciler.reviewPolicyTransition(context.TODO(), atPolicy("policy-2"), config, "policy-1", now)
+		Expect(approved).Should(BeTrue())
+		Expect(changeRequest).Should(BeNil())
+		changeRequest, approved = reconciler.reviewPolicyTransition(context.TODO(), atPolicy("policy-2"), config, "policy-2", now)
+		Expect(approved).Should(BeTrue())
+		Expect(changeRequest).Should(BeNil())
+		Expect(approver.submitted).Should(BeEmpty())
+	})
+
+	It("should hold back the promotions until they're approved", func() {
+		policyreco := atPolicy("policy-1")
+		changeRequest, approved := reconciler.reviewPolicyTransition(context.TODO(), policyreco, config, "policy-2", now)
+		Expect(approved).Should(BeFalse())
+		Expect(changeRequest.TicketID).Should(Equal("CHG1"))
+		Expect(changeRequest.Status).Should(Equal(v1alpha1.ChangeRequestPending))
+		Expect(approver.submitted).Should(HaveLen(1))
+		Expect(approver.submitted[0].Transition).Should(Equal(notifier.PolicyPromoted))
+		Expect(approver.submitted[0].Workload).Should(Equal("test-workload"))
+		Expect(*approver.submitted[0].NewConfig).Should(Equal(*config))
+
+		changeRequestPatch := createChangeRequestPatch(policyreco, changeRequest)
+		Expect(changeRequestPatch.Status.ChangeRequest).Should(Equal(changeRequest))
+		policyreco.Status = changeRequestPatch.Status
+		Expect(createChangeRequestPatch(policyreco, changeRequest.DeepCopy())).Should(BeNil())
+
+		approver.status = v1alpha1.ChangeRequestApproved
+		changeRequest, approved = reconciler.reviewPolicyTransition(context.TODO(), policyreco, config, "policy-2", now.Add(time.Minute))
+		Expect(approved).Should(BeTrue())
+		Expect(changeRequest.TicketID).Should(Equal("CHG1"))
+		Expect(changeRequest.Status).Should(Equal(v1alpha1.ChangeRequestApproved))
+		Expect(approver.submitted).Should(HaveLen(1))
+		Expect(changeRequest.EnforcedAt.Time).Should(Equal(now.Add(time.Minute)))
+		Expect(createChangeRequestPatch(policyreco, changeRequest).Status.ChangeRequest.Status).Should(Equal(v1alpha1.ChangeRequestApproved))
+	})
+
+	It("should submit the promotion again after a rollback from the approved policy", func() {
+		approver.status = v1alpha1.ChangeRequestApproved
+		policyreco := atPolicy("policy-1")
+		changeRequest, approved := reconciler.reviewPolicyTransition(context.TODO(), policyreco, config, "policy-2", now)
+		Expect(approved).Should(BeFalse())
+		policyreco.Status.ChangeRequest = changeRequest
+		changeRequest, approved = reconciler.reviewPolicyTransition(context.TODO(), policyreco, config, "policy-2", now.Add(time.Minute))
+		Expect(approved).Should(BeTrue())
+		Expect(approver.submitted).Should(HaveLen(1))
+
+		// the workload is rolled back to policy-1 after the approved promotion was enforced
+		policyreco.Status.ChangeRequest = changeRequest
+		approver.status = v1alpha1.ChangeRequestPending
+		changeRequest, approved = reconciler.reviewPolicyTransition(context.TODO(), policyreco, config, "policy-2", now.Add(time.Hour))
+		Expect(approved).Should(BeFalse())
+		Expect(changeRequest.TicketID).Should(Equal("CHG2"))
+		Expect(changeRequest.Status).Should(Equal(v1alpha1.ChangeRequestPending))
+		Expect(approver.submitted).Should(HaveLen(2))
+	})
+
+	It("should hold back the transitions between the unknown policies", func() {
+		_, approved := reconciler.reviewPolicyTransition(context.TODO(), atPolicy("unknown-policy"), config, "policy-2", now)
+		Expect(approved).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Reco drift", func() {
+	policyreco := v1alpha1.PolicyRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: "drift-app", Namespace: "default"},
+	}
+	target := &v1alpha1.HPAConfiguration{Min: 4, Max: 20, TargetMetricValue: 70}
+	current := &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50}
+
+	It("should drop the drift of the deleted policy recos", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &PolicyRecommendationReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+		logRecoDrift(policyreco, target, current)
+		Expect(testutil.ToFloat64(policyRecoUtilDrift.WithLabelValues("default", "drift-app"))).To(Equal(20.0))
+		Expect(testutil.ToFloat64(policyRecoMinReplicasDrift.WithLabelValues("default", "drift-app"))).To(Equal(6.0))
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "drift-app"}})
+		Expect(err).NotTo(HaveOccurred())
+		// deleting the drift of the policy reco tells whether it was still reported
+		Expect(policyRecoUtilDrift.DeleteLabelValues("default", "drift-app")).To(BeFalse())
+		Expect(policyRecoMinReplicasDrift.DeleteLabelValues("default", "drift-app")).To(BeFalse())
+	})
+})
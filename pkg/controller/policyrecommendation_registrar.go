@@ -53,6 +53,7 @@ type PolicyRecommendationRegistrar struct {
 	ClientsRegistry      registry.DeploymentClientRegistry
 	ExcludedNamespaces   []string
 	IncludedNamespaces   []string
+	Criticality          Criticality
 }
 
 func NewPolicyRecommendationRegistrar(client client.Client,
@@ -86,6 +87,13 @@ func (controller *PolicyRecommendationRegistrar) Reconcile(ctx context.Context,
 	logger := log.FromContext(ctx)
 	logger = logger.WithValues("request", request).WithName(PolicyRecoRegistrarCtrlName)
 
+	if terminating, err := skipsTerminatingNamespace(ctx, controller.Client, request.Namespace, PolicyRecoRegistrarCtrlName); err != nil || terminating {
+		if terminating {
+			logger.V(1).Info("Skipping the registration as the namespace is terminating.")
+		}
+		return ctrl.Result{}, err
+	}
+
 	for _, obj := range controller.ClientsRegistry.Clients {
 		object, err := obj.GetObject(request.Namespace, request.Name)
 		if err == nil {
@@ -109,21 +117,44 @@ func (controller *PolicyRecommendationRegistrar) createPolicyRecommendation(
 	instance client.Object,
 	scheme *runtime.Scheme,
 	logger logr.Logger) (*ottoscaleriov1alpha1.PolicyRecommendation, error) {
+	initialPolicy, err := controller.Criticality.initialPolicy(controller.PolicyStore, instance)
+	if err != nil {
+		logger.Error(err, "Error getting the initial policy by the criticality - requeue the request")
+		return nil, err
+	}
+	return createPolicyRecommendationForWorkload(ctx, controller.Client, controller.PolicyStore, instance, initialPolicy, scheme, logger)
+}
+
+// createPolicyRecommendationForWorkload creates a PolicyRecommendation owned by the given workload if one doesn't
+// exist already. The recommendation starts at the given policy, or the safest policy when it's empty.
+func createPolicyRecommendationForWorkload(
+	ctx context.Context,
+	k8sClient client.Client,
+	policyStore policy.Store,
+	instance client.Object,
+	policyName string,
+	scheme *runtime.Scheme,
+	logger logr.Logger) (*ottoscaleriov1alpha1.PolicyRecommendation, error) {
 
 	// Check if a PolicyRecommendation object already exists
 	policyRecommendation := &ottoscaleriov1alpha1.PolicyRecommendation{}
-	err := controller.Client.Get(ctx, types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, policyRecommendation)
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, policyRecommendation)
 	if err == nil {
 		logger.Info("PolicyRecommendation object already exists")
-		return nil, nil
+		return nil, adoptPolicyRecommendation(ctx, k8sClient, instance, policyRecommendation, scheme)
 	} else if !errors.IsNotFound(err) {
 		logger.Error(err, "Error reading the object - requeue the request")
 		return nil, err
 	}
 
-	safestPolicy, err := controller.PolicyStore.GetSafestPolicy()
+	var initialPolicy *ottoscaleriov1alpha1.Policy
+	if policyName != "" {
+		initialPolicy, err = policyStore.GetPolicyByName(policyName)
+	} else {
+		initialPolicy, err = policyStore.GetSafestPolicy()
+	}
 	if err != nil {
-		logger.Error(err, "Error getting the safest policy - requeue the request")
+		logger.Error(err, "Error getting the initial policy - requeue the request")
 		return nil, err
 	}
 
@@ -140,12 +171,14 @@ func (controller *PolicyRecommendationRegistrar) createPolicyRecommendation(
 			WorkloadMeta: ottoscaleriov1alpha1.WorkloadMeta{
 				Name:     instance.GetName(),
 				TypeMeta: metav1.TypeMeta{Kind: gvk.Kind, APIVersion: gvk.GroupVersion().String()}},
-			Policy:               safestPolicy.Name,
+			Policy:               initialPolicy.Name,
 			TransitionedAt:       &now,
 			QueuedForExecution:   &trueBool,
 			QueuedForExecutionAt: &now,
 		},
 	}
+	// the first recommendation of an onboarded workload is awaited by its owners
+	trigger.SetQueuePriority(newPolicyRecommendation, true)
 	policyRecoWorkloadGauge.WithLabelValues(instance.GetNamespace(), instance.GetName(), gvk.Kind, instance.GetName()).Set(1)
 
 	err = controllerutil.SetControllerReference(instance, newPolicyRecommendation, scheme)
@@ -154,7 +187,7 @@ func (controller *PolicyRecommendationRegistrar) createPolicyRecommendation(
 		return nil, err
 	}
 
-	err = controller.Client.Create(ctx, newPolicyRecommendation)
+	err = k8sClient.Create(ctx, newPolicyRecommendation)
 	if err != nil {
 		// Error creating the object - requeue the request.
 		logger.Error(err, "Error creating the object - requeue the request")
@@ -164,7 +197,7 @@ func (controller *PolicyRecommendationRegistrar) createPolicyRecommendation(
 	var conditions []metav1.Condition
 	logger.Info("PolicyRecommendation created successfully")
 	statusPatch, conditions := CreatePolicyPatch(*newPolicyRecommendation, conditions, ottoscaleriov1alpha1.Initialized, metav1.ConditionTrue, PolicyRecommendationCreated, InitializedMessage)
-	if err := controller.Client.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(PolicyRecoRegistrarCtrlName)); err != nil {
+	if err := k8sClient.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(PolicyRecoRegistrarCtrlName)); err != nil {
 		logger.Error(err, "Failed to patch the status")
 		return nil, client.IgnoreNotFound(err)
 	}
@@ -173,6 +206,20 @@ func (controller *PolicyRecommendationRegistrar) createPolicyRecommendation(
 	return newPolicyRecommendation, nil
 }
 
+// adoptPolicyRecommendation makes the workload the controller of its PolicyRecommendation if it has none, e.g. as it was
+// imported from another cluster ahead of the workload.
+func adoptPolicyRecommendation(ctx context.Context, k8sClient client.Client, instance client.Object,
+	policyRecommendation *ottoscaleriov1alpha1.PolicyRecommendation, scheme *runtime.Scheme) error {
+	if metav1.GetControllerOf(policyRecommendation) != nil {
+		return nil
+	}
+	patch := client.MergeFrom(policyRecommendation.DeepCopy())
+	if err := controllerutil.SetControllerReference(instance, policyRecommendation, scheme); err != nil {
+		return err
+	}
+	return k8sClient.Patch(ctx, policyRecommendation, patch)
+}
+
 func (controller *PolicyRecommendationRegistrar) handleReconcile(ctx context.Context,
 	object client.Object,
 	scheme *runtime.Scheme,
@@ -1,7 +1,14 @@
 package controller
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/approval"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -17,6 +24,12 @@ const (
 	RecoTaskInProgress        = "RecoTaskInProgress"
 	RecoTaskInProgressMessage = "Recommendation Workflow execution is in progress"
 
+	RecoTaskFrozen        = "RecoTaskFrozen"
+	RecoTaskFrozenMessage = "The Recommendation Workflow execution is skipped as the recommendation is frozen"
+
+	RecoTaskSkipped        = "RecoTaskSkipped"
+	RecoTaskSkippedMessage = "The Recommendation Workflow execution is skipped as the workload is inactive"
+
 	RecoTaskErrored        = "RecoTaskErrored"
 	EmptyRecoConfigMessage = "Empty recommendation config could be due to lack of utilization data points or non availability of pod ready time"
 	EmptyHPAConfigMessage  = "HPA config to be applied is empty"
@@ -25,6 +38,47 @@ const (
 	PolicyRecommendationCreated = "PolicyRecommendationCreated"
 	InitializedMessage          = "PolicyRecommendation has been created"
 
+	//Reason for WorkloadInactive Condition when the workload is active. It's inactive for the reasons of the registry.
+	WorkloadActive        = "WorkloadActive"
+	WorkloadActiveMessage = "The workload is active"
+
+	//Reasons for InsufficientHistory Condition
+	WorkloadBootstrapping      = "WorkloadBootstrapping"
+	InsufficientHistoryMessage = "The no-op configuration is recommended until the workload has enough history of its traffic"
+	SufficientHistory          = "SufficientHistory"
+	SufficientHistoryMessage   = "The workload has enough history of its traffic to be recommended"
+
+	//Reason for NoOpRecommended Condition when the workload is recommended a config. It's not for the reasons of the recommender.
+	WorkloadRecommended        = "WorkloadRecommended"
+	WorkloadRecommendedMessage = "A config has been recommended for the workload"
+
+	//Reasons for StaleData Condition
+	PreviousRecommendationKept = "PreviousRecommendationKept"
+	StaleDataGraceExpired      = "StaleDataGraceExpired"
+	FreshData                  = "FreshData"
+	FreshDataMessage           = "The metrics of the workload are sufficient to be recommended"
+
+	//Reason for ResizeRecommended Condition when the pods don't need resizing. It's recommended for the reasons of the recommender.
+	ResizeNotRequired        = "ResizeNotRequired"
+	ResizeNotRequiredMessage = "The pods of the workload don't need resizing"
+
+	//Reasons for QuotaConstrained Condition
+	QuotaExceeded          = "QuotaExceeded"
+	QuotaSufficient        = "QuotaSufficient"
+	QuotaSufficientMessage = "The ResourceQuotas of the namespace leave the workload the room to scale up to its max replicas"
+
+	//Reasons for AnomalousRecommendation Condition
+	AnomalousRecommendationFlagged  = "AnomalousRecommendationFlagged"
+	AnomalousRecommendationBlocked  = "AnomalousRecommendationBlocked"
+	AnomalousRecommendationAccepted = "AnomalousRecommendationAccepted"
+	ConsistentRecommendation        = "ConsistentRecommendation"
+	ConsistentRecommendationMessage = "The recommendation doesn't differ wildly from the previous one"
+
+	//Reasons for RecommendationPinned Condition
+	WorkloadPinned          = "WorkloadPinned"
+	WorkloadUnpinned        = "WorkloadUnpinned"
+	WorkloadUnpinnedMessage = "The workload isn't pinned to an HPA configuration"
+
 	//Reason for TargetRecoAchieved Condition
 	PolicyRecommendationAtTargetReco    = "PolicyRecommendationAtTargetReco"
 	PolicyRecommendationNotAtTargetReco = "PolicyRecommendationNotAtTargetReco"
@@ -61,6 +115,259 @@ func CreatePolicyPatch(policyreco v1alpha1.PolicyRecommendation, conditions []me
 	return statusPatch, updatedConditions
 }
 
+// CreateRecoMetadataPatch creates a status patch describing when and from what data the latest recommendation
+// was generated. Only the generation time is set when the recommender didn't describe its data.
+func CreateRecoMetadataPatch(policyreco v1alpha1.PolicyRecommendation, generatedAt metav1.Time, recoMetadata *reco.RecommendationMetadata) *v1alpha1.PolicyRecommendation {
+	status := v1alpha1.PolicyRecommendationStatus{
+		GeneratedAt: &generatedAt,
+	}
+	if recoMetadata != nil {
+		windowStart := metav1.NewTime(recoMetadata.MetricsWindowStart)
+		windowEnd := metav1.NewTime(recoMetadata.MetricsWindowEnd)
+		coverage := recoMetadata.DataPointsCoveragePercent
+		status.MetricsWindowStart = &windowStart
+		status.MetricsWindowEnd = &windowEnd
+		status.DataPointsCoveragePercent = &coverage
+		// the no-op configuration isn't projected to save anything as it isn't a recommendation
+		if recoMetadata.NoOp == nil {
+			savings := recoMetadata.ProjectedSavingsPercent
+			status.ProjectedSavingsPercent = &savings
+		}
+		if recoMetadata.Confidence != nil {
+			confidence := recoMetadata.Confidence.Score
+			status.ConfidencePercent = &confidence
+		}
+		if recoMetadata.BreachFreeMaxReplicas > 0 {
+			breachFreeMaxReplicas := recoMetadata.BreachFreeMaxReplicas
+			status.BreachFreeMaxReplicas = &breachFreeMaxReplicas
+		}
+		status.SearchSpace = recoMetadata.SearchSpace
+	}
+	return &v1alpha1.PolicyRecommendation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "PolicyRecommendation",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyreco.Name,
+			Namespace: policyreco.Namespace,
+		},
+		Status: status,
+	}
+}
+
+// scalingSchedule is the schedule of the replicas recommended alongside an HPA configuration, on top of it. It's
+// enforced with its HPA configuration, so it's kept along with the previous HPA configuration whenever that one is.
+type scalingSchedule struct {
+	cronTriggers []v1alpha1.CronTrigger
+	idleWindow   *v1alpha1.IdleWindow
+}
+
+// scalingScheduleForConfig returns the recommended scaling schedule of the HPA configuration.
+func scalingScheduleForConfig(recoMetadata *reco.RecommendationMetadata, config *v1alpha1.HPAConfiguration) scalingSchedule {
+	return scalingSchedule{
+		cronTriggers: cronTriggersForConfig(recoMetadata, config),
+		idleWindow:   idleWindowForConfig(recoMetadata, config),
+	}
+}
+
+// scalingScheduleOf returns the scaling schedule of the spec of a PolicyRecommendation.
+func scalingScheduleOf(spec v1alpha1.PolicyRecommendationSpec) scalingSchedule {
+	return scalingSchedule{
+		cronTriggers: spec.CronTriggers,
+		idleWindow:   spec.IdleWindow,
+	}
+}
+
+// applyTo sets the scaling schedule on the spec of a PolicyRecommendation.
+func (s scalingSchedule) applyTo(spec *v1alpha1.PolicyRecommendationSpec) {
+	spec.CronTriggers = s.cronTriggers
+	spec.IdleWindow = s.idleWindow
+}
+
+// cronTriggersForConfig returns the recommended cron triggers pre-scaling the workload beyond the min replicas of the
+// HPA configuration, with their replicas capped at its max replicas.
+func cronTriggersForConfig(recoMetadata *reco.RecommendationMetadata, config *v1alpha1.HPAConfiguration) []v1alpha1.CronTrigger {
+	if recoMetadata == nil || config == nil {
+		return nil
+	}
+	var triggers []v1alpha1.CronTrigger
+	for _, trigger := range recoMetadata.CronTriggers {
+		if trigger.DesiredReplicas > config.Max {
+			trigger.DesiredReplicas = config.Max
+		}
+		if trigger.DesiredReplicas > config.Min {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// idleWindowForConfig returns the recommended idle window if it lowers the min replicas of the HPA configuration.
+func idleWindowForConfig(recoMetadata *reco.RecommendationMetadata, config *v1alpha1.HPAConfiguration) *v1alpha1.IdleWindow {
+	if recoMetadata == nil || config == nil || recoMetadata.IdleWindow == nil || recoMetadata.IdleWindow.MinReplicas >= config.Min {
+		return nil
+	}
+	idleWindow := *recoMetadata.IdleWindow
+	return &idleWindow
+}
+
+// RecommendationDiffThreshold is how much a recommendation has to differ from the enforced HPA configuration to be
+// enforced, so that the small day to day fluctuations of the recommendations, e.g. a target flapping between 62 and
+// 63, don't needlessly update the autoscalers. The zero value enforces every change.
+type RecommendationDiffThreshold struct {
+	// TargetMetricValue is the least change of the target metric value which is enforced.
+	TargetMetricValue int
+	// TargetMetricValueRaise and TargetMetricValueLower override TargetMetricValue for the raises and the cuts of the
+	// target metric value, if set, so that the target of a noisy workload doesn't oscillate between adjacent values,
+	// e.g. raising it only by 5 points or more while lowering it right away.
+	TargetMetricValueRaise *int
+	TargetMetricValueLower *int
+	// MinReplicas is the least change of the min replicas which is enforced.
+	MinReplicas int
+}
+
+// differsMaterially returns true if the config differs enough from the current config to replace it. The changes of
+// the max replicas or of the metric are always material, as is any config replacing an empty one.
+func (t RecommendationDiffThreshold) differsMaterially(current, config v1alpha1.HPAConfiguration) bool {
+	if current.DeepEquals(v1alpha1.HPAConfiguration{}) || current.Max != config.Max ||
+		current.GetMetricName() != config.GetMetricName() || current.GetTargetMetricType() != config.GetTargetMetricType() {
+		return true
+	}
+	return isMaterialChange(current.TargetMetricValue, config.TargetMetricValue,
+		t.targetMetricValueThreshold(current.TargetMetricValue, config.TargetMetricValue)) ||
+		isMaterialChange(current.Min, config.Min, t.MinReplicas)
+}
+
+// suppressesUpdate returns true if the config recommended for the policyreco at the policy is kept back in favour of
+// its current config, as it doesn't differ materially from it. The transitions to another policy are never kept back,
+// as the aging iterator would otherwise propose the same policy over and over without the workload ever moving up.
+func (t RecommendationDiffThreshold) suppressesUpdate(policyreco v1alpha1.PolicyRecommendation, policyName string,
+	config v1alpha1.HPAConfiguration) bool {
+	return policyName == policyreco.Spec.Policy && !config.DeepEquals(policyreco.Spec.CurrentHPAConfiguration) &&
+		!t.differsMaterially(policyreco.Spec.CurrentHPAConfiguration, config)
+}
+
+// targetMetricValueThreshold returns the least change of the target metric value which is enforced in the direction
+// of the change from the current target to the next one.
+func (t RecommendationDiffThreshold) targetMetricValueThreshold(current, next int) int {
+	if next > current && t.TargetMetricValueRaise != nil {
+		return *t.TargetMetricValueRaise
+	}
+	if next < current && t.TargetMetricValueLower != nil {
+		return *t.TargetMetricValueLower
+	}
+	return t.TargetMetricValue
+}
+
+// StaleDataGrace is how long the previous recommendation of a workload is kept while its metrics fall short of the
+// coverage threshold, e.g. during an outage of the Prometheus instances, rather than wiping its savings with the no-op
+// configuration right away. The no-op configuration is recommended only once the metrics have fallen short for longer
+// than the Period and for more than the Recommendations in a row. The zero value doesn't keep it.
+type StaleDataGrace struct {
+	// Period is how long the previous recommendation is kept since the metrics first fell short.
+	Period time.Duration
+	// Recommendations is how many recommendations in a row keep the previous recommendation.
+	Recommendations int
+}
+
+// keepsPreviousRecommendation returns true if the previous recommendation of the policyreco is kept as the metrics
+// of its workload fell short, along with when they first fell short and how many recommendations in a row kept it,
+// including this one.
+func (g StaleDataGrace) keepsPreviousRecommendation(policyreco v1alpha1.PolicyRecommendation,
+	recoMetadata *reco.RecommendationMetadata, now time.Time) (bool, time.Time, int) {
+	if g.Period <= 0 && g.Recommendations <= 0 {
+		return false, time.Time{}, 0
+	}
+	if recoMetadata == nil || recoMetadata.NoOp == nil || recoMetadata.NoOp.Reason != reco.InsufficientMetricsReason {
+		return false, time.Time{}, 0
+	}
+	// there's no recommendation to keep for the workloads which haven't been recommended a config yet
+	if hasCondition(policyreco, v1alpha1.NoOpRecommended) ||
+		policyreco.Spec.TargetHPAConfiguration.DeepEquals(v1alpha1.HPAConfiguration{}) {
+		return false, time.Time{}, 0
+	}
+
+	staleSince, staleRecommendations := now, 1
+	if hasCondition(policyreco, v1alpha1.StaleData) {
+		if policyreco.Status.StaleDataSince != nil {
+			staleSince = policyreco.Status.StaleDataSince.Time
+		}
+		if policyreco.Status.StaleRecommendations != nil {
+			staleRecommendations = *policyreco.Status.StaleRecommendations + 1
+		}
+	}
+	keep := now.Sub(staleSince) < g.Period || staleRecommendations <= g.Recommendations
+	return keep, staleSince, staleRecommendations
+}
+
+// AnomalyGuard flags the recommendations which differ wildly from the previous recommendation of the workload, as
+// such jumps usually come of bad metric data, e.g. a broken exporter reporting no utilization, rather than a real
+// change of the traffic. With Block, the flagged recommendations are held back, keeping the previous one, until they're
+// accepted with the AcceptRecommendationAnnotation. The zero value doesn't flag any recommendation.
+type AnomalyGuard struct {
+	// MinDropPercent flags the recommendations whose min replicas dropped by more than this percent of the previous.
+	MinDropPercent int
+	// TargetJumpPoints flags the recommendations whose target metric value moved by this much or more either way.
+	TargetJumpPoints int
+	// Block holds back the flagged recommendations.
+	Block bool
+}
+
+// detect returns what makes the config anomalous given the previous recommendation, or an empty string if it isn't.
+// There's nothing to compare with while the previous recommendation is empty.
+func (g AnomalyGuard) detect(previous, config v1alpha1.HPAConfiguration) string {
+	if previous.DeepEquals(v1alpha1.HPAConfiguration{}) {
+		return ""
+	}
+	var anomalies []string
+	if g.MinDropPercent > 0 && previous.Min > 0 && (previous.Min-config.Min)*100 > previous.Min*g.MinDropPercent {
+		anomalies = append(anomalies, fmt.Sprintf("the min replicas dropped from %d to %d", previous.Min, config.Min))
+	}
+	if g.TargetJumpPoints > 0 && isMaterialChange(previous.TargetMetricValue, config.TargetMetricValue, g.TargetJumpPoints) {
+		anomalies = append(anomalies, fmt.Sprintf("the target moved from %d to %d", previous.TargetMetricValue,
+			config.TargetMetricValue))
+	}
+	return strings.Join(anomalies, " and ")
+}
+
+// ChangeApproval holds back the policy transitions of the workloads the Gate gates until the change management system
+// approves them, reviewing the pending ones every PollInterval. The transitions are submitted along with the change
+// of the autoscalers of the workloads the Previewer previews, if it's set. The zero value doesn't hold back any
+// transition.
+type ChangeApproval struct {
+	Gate         *approval.Gate
+	PollInterval time.Duration
+	Previewer    EnforcementPreviewer
+}
+
+// createChangeRequestPatch creates a status patch recording the change request for the policy transition of the
+// policyreco. It returns nil if the change request doesn't change.
+func createChangeRequestPatch(policyreco v1alpha1.PolicyRecommendation, changeRequest *v1alpha1.ChangeRequest) *v1alpha1.PolicyRecommendation {
+	if changeRequest == nil || equality.Semantic.DeepEqual(changeRequest, policyreco.Status.ChangeRequest) {
+		return nil
+	}
+	return &v1alpha1.PolicyRecommendation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "PolicyRecommendation",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyreco.Name,
+			Namespace: policyreco.Namespace,
+		},
+		Status: v1alpha1.PolicyRecommendationStatus{ChangeRequest: changeRequest},
+	}
+}
+
+func isMaterialChange(current, next, threshold int) bool {
+	change := next - current
+	if change < 0 {
+		change = -change
+	}
+	return change > 0 && change >= threshold
+}
+
 func SetConditions(conditions []metav1.Condition, newCondition metav1.Condition) []metav1.Condition {
 	var newConditions []metav1.Condition
 	for _, c := range conditions {
@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	ottoscaleriov1beta1 "github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	policyRecoBindingWorkloadsGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "policyreco_binding_matched_workloads",
+			Help: "Number of workloads matched by a PolicyRecommendationBinding"}, []string{"namespace", "binding"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(policyRecoBindingWorkloadsGauge)
+}
+
+const (
+	PolicyRecoBindingCtrlName = "PolicyRecommendationBindingController"
+
+	// policyBindingFinalizerName holds a deleted binding until the PolicyRecommendations it bound are released.
+	policyBindingFinalizerName = "policybinding.ottoscaler.io/release"
+
+	// PolicyBindingAnnotation is set on the PolicyRecommendations bound by a binding to the name of the binding.
+	PolicyBindingAnnotation = "ottoscalr.io/policy-binding"
+	// boundPolicyAnnotation is the policy the binding last moved the PolicyRecommendation to, so that the
+	// PolicyRecommendation is only moved again once the policy of the binding changes.
+	boundPolicyAnnotation = "ottoscalr.io/bound-policy"
+)
+
+// PolicyRecommendationBindingReconciler reconciles a PolicyRecommendationBinding object to ensure a
+// PolicyRecommendation exists for every workload selected by it and is at the policy of the binding. The
+// PolicyRecommendations of the workloads it no longer selects, or of all its workloads once it's deleted, are released
+// back to the policy the registrar starts the workloads at.
+type PolicyRecommendationBindingReconciler struct {
+	Client               client.Client
+	Scheme               *runtime.Scheme
+	MonitorManager       trigger.MonitorManager
+	RequeueDelayDuration time.Duration
+	PolicyStore          policy.Store
+	ClientsRegistry      registry.DeploymentClientRegistry
+	// Criticality picks the policy the released PolicyRecommendations go back to, as it does for the registrar.
+	Criticality Criticality
+}
+
+func NewPolicyRecommendationBindingReconciler(client client.Client,
+	scheme *runtime.Scheme,
+	requeueDelayMs int,
+	monitorManager trigger.MonitorManager,
+	policyStore policy.Store,
+	clientsRegistry registry.DeploymentClientRegistry) *PolicyRecommendationBindingReconciler {
+	return &PolicyRecommendationBindingReconciler{
+		Client:               client,
+		Scheme:               scheme,
+		MonitorManager:       monitorManager,
+		RequeueDelayDuration: time.Duration(requeueDelayMs) * time.Millisecond,
+		PolicyStore:          policyStore,
+		ClientsRegistry:      clientsRegistry,
+	}
+}
+
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendationbindings,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendationbindings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=policyrecommendationbindings/finalizers,verbs=update
+
+func (r *PolicyRecommendationBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("request", req).WithName(PolicyRecoBindingCtrlName)
+
+	binding := &ottoscaleriov1beta1.PolicyRecommendationBinding{}
+	if err := r.Client.Get(ctx, req.NamespacedName, binding); err != nil {
+		if errors.IsNotFound(err) {
+			policyRecoBindingWorkloadsGauge.DeleteLabelValues(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Error reading the PolicyRecommendationBinding")
+		return ctrl.Result{}, err
+	}
+
+	terminating, err := skipsTerminatingNamespace(ctx, r.Client, binding.Namespace, PolicyRecoBindingCtrlName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !binding.DeletionTimestamp.IsZero() {
+		// the PolicyRecommendations of a terminating namespace are deleted along with it
+		if !terminating {
+			if err := r.releasePolicyRecommendations(ctx, binding, nil, logger); err != nil {
+				return ctrl.Result{RequeueAfter: r.RequeueDelayDuration}, err
+			}
+		}
+		if containsString(binding.Finalizers, policyBindingFinalizerName) {
+			binding.Finalizers = removeString(binding.Finalizers, policyBindingFinalizerName)
+			if err := r.Client.Update(ctx, binding); err != nil {
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+		}
+		policyRecoBindingWorkloadsGauge.DeleteLabelValues(binding.Namespace, binding.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if terminating {
+		logger.V(1).Info("Skipping the binding as the namespace is terminating.")
+		return ctrl.Result{}, nil
+	}
+
+	if !containsString(binding.Finalizers, policyBindingFinalizerName) {
+		binding.Finalizers = append(binding.Finalizers, policyBindingFinalizerName)
+		if err := r.Client.Update(ctx, binding); err != nil {
+			logger.Error(err, "Error adding the finalizer to the PolicyRecommendationBinding")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+
+	selector, err := workloadSelectorAsSelector(binding.Spec.WorkloadSelector)
+	if err != nil {
+		logger.Error(err, "Invalid workload selector in the PolicyRecommendationBinding. Skipping.")
+		return ctrl.Result{}, nil
+	}
+
+	boundPolicy, err := r.boundPolicy(binding)
+	if err != nil {
+		logger.Error(err, "Error getting the policy of the PolicyRecommendationBinding. Requeue the request")
+		return ctrl.Result{RequeueAfter: r.RequeueDelayDuration}, err
+	}
+
+	matchedWorkloads := 0
+	matched := map[string]bool{}
+	for _, objectClient := range r.ClientsRegistry.Clients {
+		workloads, err := objectClient.GetObjectList(binding.Namespace, selector)
+		if err != nil {
+			logger.Error(err, "Error listing the workloads. Requeue the request", "kind", objectClient.GetKind())
+			return ctrl.Result{RequeueAfter: r.RequeueDelayDuration}, err
+		}
+		for _, workload := range workloads {
+			if _, err := createPolicyRecommendationForWorkload(ctx, r.Client, r.PolicyStore, workload,
+				binding.Spec.Policy, r.Scheme, logger.WithValues("workload", workload.GetName())); err != nil {
+				return ctrl.Result{RequeueAfter: r.RequeueDelayDuration}, err
+			}
+			if err := r.bindPolicyRecommendation(ctx, binding, boundPolicy, workload, logger); err != nil {
+				return ctrl.Result{RequeueAfter: r.RequeueDelayDuration}, err
+			}
+			r.MonitorManager.RegisterMonitor(workload.GetObjectKind().GroupVersionKind().Kind,
+				types.NamespacedName{Name: workload.GetName(), Namespace: workload.GetNamespace()})
+			matched[workload.GetName()] = true
+			matchedWorkloads++
+		}
+	}
+	if err := r.releasePolicyRecommendations(ctx, binding, matched, logger); err != nil {
+		return ctrl.Result{RequeueAfter: r.RequeueDelayDuration}, err
+	}
+	policyRecoBindingWorkloadsGauge.WithLabelValues(binding.Namespace, binding.Name).Set(float64(matchedWorkloads))
+
+	now := metav1.Now()
+	statusPatch := &ottoscaleriov1beta1.PolicyRecommendationBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ottoscaleriov1beta1.GroupVersion.String(),
+			Kind:       "PolicyRecommendationBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      binding.Name,
+			Namespace: binding.Namespace,
+		},
+		Status: ottoscaleriov1beta1.PolicyRecommendationBindingStatus{
+			MatchedWorkloads: matchedWorkloads,
+			LastSyncedAt:     &now,
+		},
+	}
+	if err := r.Client.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(PolicyRecoBindingCtrlName)); err != nil {
+		logger.Error(err, "Error updating the status of the PolicyRecommendationBinding")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logger.V(1).Info("PolicyRecommendationBinding synced", "matchedWorkloads", matchedWorkloads)
+	return ctrl.Result{}, nil
+}
+
+// boundPolicy returns the name of the policy the binding moves the PolicyRecommendations of its workloads to.
+func (r *PolicyRecommendationBindingReconciler) boundPolicy(binding *ottoscaleriov1beta1.PolicyRecommendationBinding) (string, error) {
+	if binding.Spec.Policy != "" {
+		return binding.Spec.Policy, nil
+	}
+	safestPolicy, err := r.PolicyStore.GetSafestPolicy()
+	if err != nil {
+		return "", err
+	}
+	return safestPolicy.Name, nil
+}
+
+// bindPolicyRecommendation moves the PolicyRecommendation of the workload to the policy of the binding and queues it
+// for a fresh recommendation. It's moved only once per policy of the binding, so that it's free to move along the
+// policy ladder afterwards, and it's left to the binding which bound it first when several bindings select the workload.
+func (r *PolicyRecommendationBindingReconciler) bindPolicyRecommendation(ctx context.Context,
+	binding *ottoscaleriov1beta1.PolicyRecommendationBinding, boundPolicy string, workload client.Object,
+	logger logr.Logger) error {
+	policyreco := &ottoscaleriov1alpha1.PolicyRecommendation{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: workload.GetNamespace(), Name: workload.GetName()},
+		policyreco); err != nil {
+		logger.Error(err, "Error reading the PolicyRecommendation of the workload", "workload", workload.GetName())
+		return client.IgnoreNotFound(err)
+	}
+	owner, bound := policyreco.Annotations[PolicyBindingAnnotation]
+	if bound && owner != binding.Name {
+		logger.V(1).Info("Skipping the workload as it's bound by another binding.", "workload", workload.GetName(),
+			"binding", owner)
+		return nil
+	}
+	if bound && policyreco.Annotations[boundPolicyAnnotation] == boundPolicy {
+		return nil
+	}
+
+	patch := client.MergeFrom(policyreco.DeepCopy())
+	if policyreco.Annotations == nil {
+		policyreco.Annotations = map[string]string{}
+	}
+	policyreco.Annotations[PolicyBindingAnnotation] = binding.Name
+	policyreco.Annotations[boundPolicyAnnotation] = boundPolicy
+	if policyreco.Spec.Policy != boundPolicy {
+		movePolicyRecommendation(policyreco, boundPolicy)
+	}
+	if err := r.Client.Patch(ctx, policyreco, patch, client.FieldOwner(PolicyRecoBindingCtrlName)); err != nil {
+		logger.Error(err, "Error binding the PolicyRecommendation of the workload", "workload", workload.GetName())
+		return client.IgnoreNotFound(err)
+	}
+	logger.V(0).Info("Bound the PolicyRecommendation to the policy of the binding.", "workload", workload.GetName(),
+		"policy", boundPolicy)
+	return nil
+}
+
+// releasePolicyRecommendations moves the PolicyRecommendations bound by the binding, except those of the matched
+// workloads, back to the policy the registrar starts their workloads at and queues them for a fresh recommendation.
+func (r *PolicyRecommendationBindingReconciler) releasePolicyRecommendations(ctx context.Context,
+	binding *ottoscaleriov1beta1.PolicyRecommendationBinding, matched map[string]bool, logger logr.Logger) error {
+	policyrecos := &ottoscaleriov1alpha1.PolicyRecommendationList{}
+	if err := r.Client.List(ctx, policyrecos, client.InNamespace(binding.Namespace)); err != nil {
+		logger.Error(err, "Error listing the PolicyRecommendations of the namespace")
+		return err
+	}
+	for i := range policyrecos.Items {
+		policyreco := &policyrecos.Items[i]
+		if policyreco.Annotations[PolicyBindingAnnotation] != binding.Name || matched[policyreco.Name] {
+			continue
+		}
+		defaultPolicy, err := r.defaultPolicy(policyreco)
+		if err != nil {
+			logger.Error(err, "Error getting the default policy of the PolicyRecommendation", "policyreco", policyreco.Name)
+			return err
+		}
+
+		patch := client.MergeFrom(policyreco.DeepCopy())
+		delete(policyreco.Annotations, PolicyBindingAnnotation)
+		delete(policyreco.Annotations, boundPolicyAnnotation)
+		if policyreco.Spec.Policy != defaultPolicy {
+			movePolicyRecommendation(policyreco, defaultPolicy)
+		}
+		if err := r.Client.Patch(ctx, policyreco, patch, client.FieldOwner(PolicyRecoBindingCtrlName)); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Error releasing the PolicyRecommendation", "policyreco", policyreco.Name)
+			return err
+		}
+		logger.V(0).Info("Released the PolicyRecommendation to the default policy.", "policyreco", policyreco.Name,
+			"policy", defaultPolicy)
+	}
+	return nil
+}
+
+// defaultPolicy returns the policy the registrar starts the workload of the PolicyRecommendation at, i.e. the policy
+// by its criticality or else the safest policy.
+func (r *PolicyRecommendationBindingReconciler) defaultPolicy(policyreco *ottoscaleriov1alpha1.PolicyRecommendation) (string, error) {
+	if objectClient, err := r.ClientsRegistry.GetObjectClient(policyreco.Spec.WorkloadMeta.Kind); err == nil {
+		workload, err := objectClient.GetObject(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		if err == nil {
+			initialPolicy, err := r.Criticality.initialPolicy(r.PolicyStore, workload)
+			if err != nil || initialPolicy != "" {
+				return initialPolicy, err
+			}
+		}
+	}
+	safestPolicy, err := r.PolicyStore.GetSafestPolicy()
+	if err != nil {
+		return "", err
+	}
+	return safestPolicy.Name, nil
+}
+
+// movePolicyRecommendation moves the PolicyRecommendation to the policy and queues it for a fresh recommendation.
+func movePolicyRecommendation(policyreco *ottoscaleriov1alpha1.PolicyRecommendation, policyName string) {
+	now := metav1.Now()
+	policyreco.Spec.Policy = policyName
+	policyreco.Spec.TransitionedAt = &now
+	policyreco.Spec.QueuedForExecution = &trueBool
+	policyreco.Spec.QueuedForExecutionAt = &now
+	trigger.SetQueuePriority(policyreco, false)
+}
+
+// workloadSelectorAsSelector converts the workload selector of a binding to a labels.Selector. An empty
+// selector selects all the workloads.
+func workloadSelectorAsSelector(workloadSelector *metav1.LabelSelector) (labels.Selector, error) {
+	if workloadSelector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(workloadSelector)
+}
+
+// bindingsForWorkload maps a workload to the bindings in its namespace which select it, and to the binding which bound
+// its PolicyRecommendation so that it's released once the binding no longer selects it.
+func (r *PolicyRecommendationBindingReconciler) bindingsForWorkload(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName(PolicyRecoBindingCtrlName)
+	bindings := &ottoscaleriov1beta1.PolicyRecommendationBindingList{}
+	if err := r.Client.List(ctx, bindings, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.Error(err, "Error listing the PolicyRecommendationBindings", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	policyreco := &ottoscaleriov1alpha1.PolicyRecommendation{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		policyreco); client.IgnoreNotFound(err) != nil {
+		logger.Error(err, "Error reading the PolicyRecommendation of the workload", "workload", obj.GetName())
+	}
+
+	var requests []reconcile.Request
+	for _, binding := range bindings.Items {
+		selector, err := workloadSelectorAsSelector(binding.Spec.WorkloadSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(obj.GetLabels())) || policyreco.Annotations[PolicyBindingAnnotation] == binding.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      binding.Name,
+				Namespace: binding.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PolicyRecommendationBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	workloadCreatePredicate := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		Named(PolicyRecoBindingCtrlName).
+		For(&ottoscaleriov1beta1.PolicyRecommendationBinding{},
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{},
+				predicate.NewPredicateFuncs(isTerminating))))
+
+	for _, object := range r.ClientsRegistry.Clients {
+		controllerBuilder.Watches(
+			object.GetObjectType(),
+			handler.EnqueueRequestsFromMapFunc(r.bindingsForWorkload),
+			builder.WithPredicates(workloadCreatePredicate),
+		)
+	}
+
+	return controllerBuilder.Complete(r)
+}
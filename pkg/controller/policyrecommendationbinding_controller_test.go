@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	ottoscaleriov1beta1 "github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PolicyRecommendationBinding controller", func() {
+
+	const (
+		BindingNamespace = "namespace2"
+		BindingName      = "test-binding"
+
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	newDeployment := func(name string, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: BindingNamespace,
+				Labels:    labels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": name},
+				},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app": name},
+					},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{
+								Name:  "test-container",
+								Image: "nginx:1.17.5",
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	Context("When a binding selects workloads in a namespace excluded from the registrar", func() {
+		var namespace *v1.Namespace
+		var binding *ottoscaleriov1beta1.PolicyRecommendationBinding
+		var selectedDeployment, otherDeployment, newDeploymentObj *appsv1.Deployment
+		ctx := context.TODO()
+
+		BeforeEach(func() {
+			namespace = &v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: BindingNamespace},
+			}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+
+			selectedDeployment = newDeployment("bound-deployment", map[string]string{"team": "checkout"})
+			otherDeployment = newDeployment("unbound-deployment", map[string]string{"team": "search"})
+			Expect(k8sClient.Create(ctx, selectedDeployment)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, otherDeployment)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, binding)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, selectedDeployment)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, otherDeployment)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, newDeploymentObj)).Should(Succeed())
+		})
+
+		It("Should create PolicyRecommendations only for the selected workloads", func() {
+			binding = &ottoscaleriov1beta1.PolicyRecommendationBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      BindingName,
+					Namespace: BindingNamespace,
+				},
+				Spec: ottoscaleriov1beta1.PolicyRecommendationBindingSpec{
+					WorkloadSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"team": "checkout"},
+					},
+					Policy: "policy-1",
+				},
+			}
+			Expect(k8sClient.Create(ctx, binding)).Should(Succeed())
+
+			createdPolicy := &ottoscaleriov1alpha1.PolicyRecommendation{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx,
+					types.NamespacedName{Name: selectedDeployment.Name, Namespace: BindingNamespace}, createdPolicy)
+			}, timeout, interval).Should(Succeed())
+			Expect(createdPolicy.Spec.Policy).Should(Equal("policy-1"))
+			Expect(createdPolicy.Spec.WorkloadMeta.Kind).Should(Equal("Deployment"))
+
+			Consistently(func() bool {
+				err := k8sClient.Get(ctx,
+					types.NamespacedName{Name: otherDeployment.Name, Namespace: BindingNamespace},
+					&ottoscaleriov1alpha1.PolicyRecommendation{})
+				return errors.IsNotFound(err)
+			}, 2*time.Second, interval).Should(BeTrue())
+
+			By("Creating a new Deployment matching the binding")
+			newDeploymentObj = newDeployment("new-bound-deployment", map[string]string{"team": "checkout"})
+			Expect(k8sClient.Create(ctx, newDeploymentObj)).Should(Succeed())
+			Eventually(func() error {
+				return k8sClient.Get(ctx,
+					types.NamespacedName{Name: newDeploymentObj.Name, Namespace: BindingNamespace},
+					&ottoscaleriov1alpha1.PolicyRecommendation{})
+			}, timeout, interval).Should(Succeed())
+
+			updatedBinding := &ottoscaleriov1beta1.PolicyRecommendationBinding{}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx,
+					types.NamespacedName{Name: BindingName, Namespace: BindingNamespace}, updatedBinding); err != nil {
+					return 0
+				}
+				return updatedBinding.Status.MatchedWorkloads
+			}, timeout, interval).Should(Equal(2))
+		})
+	})
+
+	Context("When a binding selects workloads which already have PolicyRecommendations", func() {
+		const ReleaseNamespace = "binding-release"
+		var binding *ottoscaleriov1beta1.PolicyRecommendationBinding
+		var deployment *appsv1.Deployment
+		ctx := context.TODO()
+
+		getPolicyReco := func() *ottoscaleriov1alpha1.PolicyRecommendation {
+			policyreco := &ottoscaleriov1alpha1.PolicyRecommendation{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: ReleaseNamespace},
+				policyreco)).Should(Succeed())
+			return policyreco
+		}
+
+		BeforeEach(func() {
+			namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ReleaseNamespace}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, namespace))).Should(Succeed())
+
+			deployment = newDeployment("released-deployment", map[string]string{"team": "checkout"})
+			deployment.Namespace = ReleaseNamespace
+			Expect(k8sClient.Create(ctx, deployment)).Should(Succeed())
+			// the registrar onboards the workload at the safest policy before the binding selects it
+			Eventually(func() string {
+				policyreco := &ottoscaleriov1alpha1.PolicyRecommendation{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: ReleaseNamespace},
+					policyreco); err != nil {
+					return ""
+				}
+				return policyreco.Spec.Policy
+			}, timeout, interval).Should(Equal("safest-policy"))
+
+			binding = &ottoscaleriov1beta1.PolicyRecommendationBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      BindingName,
+					Namespace: ReleaseNamespace,
+				},
+				Spec: ottoscaleriov1beta1.PolicyRecommendationBindingSpec{
+					WorkloadSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"team": "checkout"},
+					},
+					Policy: "policy-1",
+				},
+			}
+			Expect(k8sClient.Create(ctx, binding)).Should(Succeed())
+			Eventually(func() map[string]string {
+				return getPolicyReco().Annotations
+			}, timeout, interval).Should(HaveKeyWithValue(PolicyBindingAnnotation, BindingName))
+			Expect(getPolicyReco().Spec.Policy).Should(Equal("policy-1"))
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, binding))).Should(Succeed())
+			// the binding is held by its finalizer until its workloads are released
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: BindingName, Namespace: ReleaseNamespace},
+					&ottoscaleriov1beta1.PolicyRecommendationBinding{})
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, deployment)).Should(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getPolicyReco()))).Should(Succeed())
+		})
+
+		It("Should release the PolicyRecommendations of the workloads it no longer selects", func() {
+			deployment.Labels = map[string]string{"team": "search"}
+			Expect(k8sClient.Update(ctx, deployment)).Should(Succeed())
+
+			Eventually(func() map[string]string {
+				return getPolicyReco().Annotations
+			}, timeout, interval).ShouldNot(HaveKey(PolicyBindingAnnotation))
+			Expect(getPolicyReco().Spec.Policy).Should(Equal("safest-policy"))
+		})
+
+		It("Should release the PolicyRecommendations of its workloads once it's deleted", func() {
+			Expect(k8sClient.Delete(ctx, binding)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: BindingName, Namespace: ReleaseNamespace},
+					&ottoscaleriov1beta1.PolicyRecommendationBinding{})
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+			Expect(getPolicyReco().Annotations).ShouldNot(HaveKey(PolicyBindingAnnotation))
+			Expect(getPolicyReco().Spec.Policy).Should(Equal("safest-policy"))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	PolicyRecoInteractiveWorkflowCtrlName = "InteractiveRecoWorkflowController"
+
+	// inFlightRequeueDelay is how long a PolicyRecommendation being recommended by the other queue is requeued for.
+	inFlightRequeueDelay = 5 * time.Second
+)
+
+// InteractiveQueue is the queue of the PolicyRecommendations a user queued for a fresh recommendation, which is
+// worked separately from the queue of the routine regenerations, so that a periodic regeneration of the whole fleet
+// doesn't hold them up.
+type InteractiveQueue struct {
+	// MaxConcurrentReconciles are the reconciles working the interactive queue. It's disabled if zero, in which case
+	// all the PolicyRecommendations go through the one queue.
+	MaxConcurrentReconciles int
+}
+
+func (q InteractiveQueue) enabled() bool {
+	return q.MaxConcurrentReconciles > 0
+}
+
+// isInteractivelyQueued returns whether a user queued the PolicyRecommendation.
+func isInteractivelyQueued(obj client.Object) bool {
+	return obj.GetAnnotations()[v1alpha1.QueuePriorityAnnotation] == v1alpha1.QueuePriorityInteractive
+}
+
+// inFlightRecos are the PolicyRecommendations being recommended, so that the two queues don't recommend the same one
+// at once.
+type inFlightRecos struct {
+	recos sync.Map
+}
+
+// acquire marks the PolicyRecommendation as being recommended, returning false if it already is.
+func (f *inFlightRecos) acquire(policyreco types.NamespacedName) bool {
+	_, loaded := f.recos.LoadOrStore(policyreco, struct{}{})
+	return !loaded
+}
+
+func (f *inFlightRecos) release(policyreco types.NamespacedName) {
+	f.recos.Delete(policyreco)
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/trigger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Queue priority", func() {
+	It("should tell the policy recos queued by the users from the routine ones", func() {
+		policyreco := &v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"}}
+		Expect(isInteractivelyQueued(policyreco)).To(BeFalse())
+
+		trigger.SetQueuePriority(policyreco, true)
+		Expect(isInteractivelyQueued(policyreco)).To(BeTrue())
+
+		trigger.SetQueuePriority(policyreco, false)
+		Expect(isInteractivelyQueued(policyreco)).To(BeFalse())
+		Expect(policyreco.Annotations).NotTo(HaveKey(v1alpha1.QueuePriorityAnnotation))
+	})
+
+	It("should let only one queue recommend a policy reco at once", func() {
+		inFlight := &inFlightRecos{}
+		checkout := types.NamespacedName{Name: "checkout", Namespace: "default"}
+		Expect(inFlight.acquire(checkout)).To(BeTrue())
+		Expect(inFlight.acquire(checkout)).To(BeFalse())
+		Expect(inFlight.acquire(types.NamespacedName{Name: "search", Namespace: "default"})).To(BeTrue())
+
+		inFlight.release(checkout)
+		Expect(inFlight.acquire(checkout)).To(BeTrue())
+	})
+})
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ShadowAdoptionStatusManager = "ShadowAdoptionStatusManager"
+	// ShadowAdoptionReason marks the policyrecos whose enforcement is held back while ottoscalr shadows the HPA the
+	// owners of their workloads created.
+	ShadowAdoptionReason = "ShadowAdoption"
+
+	//Reasons for ShadowAdoption Condition
+	ShadowPeriodInProgress = "ShadowPeriodInProgress"
+	ShadowRegression       = "ShadowRegression"
+	ShadowParity           = "ShadowParity"
+
+	// shadowComparisonInterval is how often a workload whose recommendation didn't perform on par with its HPA over
+	// the shadow period is compared again, over the shadow period up to then.
+	shadowComparisonInterval = 24 * time.Hour
+)
+
+var (
+	hpaenforcerShadowAdoptionHeldCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_shadow_adoption_held_count",
+			Help: "Number of enforcements held back while shadowing the HPAs the owners of the workloads created"}, []string{"namespace", "policyreco"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(hpaenforcerShadowAdoptionHeldCounter)
+}
+
+// ShadowAdoption makes the controller shadow the HPA the owners of a workload created for Period before adopting the
+// workload from it, and adopt it only once the Comparator finds the recommendation performing on par with the HPA or
+// better over the period.
+type ShadowAdoption struct {
+	Period     time.Duration
+	Comparator reco.ShadowComparator
+}
+
+// holdForShadowAdoption tells whether the adoption of the workload from the HPA its owners created is to be held back
+// while ottoscalr shadows the HPA: the shadow period starts at the first reconcile finding the HPA, and the workload
+// is adopted once the recommendation compares on par with the HPA or better over the period. The workloads without
+// such an HPA, or with an autoscaler managed by ottoscalr already, are never held. It also returns the message of the
+// hold and when the workload is to be reconciled again.
+func (r *HPAEnforcementController) holdForShadowAdoption(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	workload client.Object, now time.Time) (bool, string, time.Duration, error) {
+	labelSelector, err := labels.Parse(fmt.Sprintf("%s=%s", createdByLabelKey, createdByLabelValue))
+	if err != nil {
+		return false, "", 0, err
+	}
+	autoscalerObjects, err := r.autoscalerClient.GetList(ctx, labelSelector, workload.GetNamespace(), fields.OneTermEqualSelector(autoscalerField, workload.GetName()))
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return false, "", 0, err
+	}
+	if len(autoscalerObjects) > 0 {
+		return false, "", 0, nil
+	}
+	hpa, err := autoscaler.FindAdoptedHPA(ctx, r.Client, workload, map[string]string{createdByLabelKey: createdByLabelValue})
+	if err != nil || hpa == nil {
+		return false, "", 0, err
+	}
+
+	status := policyreco.Status
+	if status.ShadowAdoptionSince == nil {
+		since := metav1.NewTime(now)
+		message := fmt.Sprintf("Shadowing the HPA %s for %s before adopting the workload", hpa.Name, r.ShadowAdoption.Period)
+		if err := r.patchShadowAdoption(ctx, policyreco, metav1.ConditionTrue, ShadowPeriodInProgress, message, since, nil); err != nil {
+			return false, "", 0, err
+		}
+		return true, message, r.ShadowAdoption.Period, nil
+	}
+	shadowEnd := status.ShadowAdoptionSince.Add(r.ShadowAdoption.Period)
+	if now.Before(shadowEnd) {
+		return true, fmt.Sprintf("Shadowing the HPA %s till %s before adopting the workload", hpa.Name,
+			shadowEnd.Format(time.RFC3339)), shadowEnd.Sub(now), nil
+	}
+
+	if comparison := status.ShadowComparison; comparison != nil && now.Sub(comparison.WindowEnd.Time) < shadowComparisonInterval {
+		if comparison.AtParity() {
+			return false, "", 0, nil
+		}
+		return true, shadowComparisonMessage(hpa.Name, *comparison),
+			comparison.WindowEnd.Add(shadowComparisonInterval).Sub(now), nil
+	}
+
+	workloadMeta := reco.WorkloadMeta{TypeMeta: policyreco.Spec.WorkloadMeta.TypeMeta, Name: workload.GetName(),
+		Namespace: workload.GetNamespace()}
+	comparison, err := r.ShadowAdoption.Comparator.Compare(ctx, workloadMeta, policyreco.Spec.CurrentHPAConfiguration,
+		now.Add(-r.ShadowAdoption.Period), now)
+	if err != nil {
+		return false, "", 0, err
+	}
+	message := shadowComparisonMessage(hpa.Name, *comparison)
+	if comparison.AtParity() {
+		if err := r.patchShadowAdoption(ctx, policyreco, metav1.ConditionFalse, ShadowParity, message, *status.ShadowAdoptionSince, comparison); err != nil {
+			return false, "", 0, err
+		}
+		r.Recorder.Event(&policyreco, eventTypeNormal, ShadowParity, message)
+		return false, "", 0, nil
+	}
+	if err := r.patchShadowAdoption(ctx, policyreco, metav1.ConditionTrue, ShadowRegression, message, *status.ShadowAdoptionSince, comparison); err != nil {
+		return false, "", 0, err
+	}
+	return true, message, shadowComparisonInterval, nil
+}
+
+// patchShadowAdoption patches the ShadowAdoption condition of the policyreco along with the start of its shadow period
+// and its latest comparison.
+func (r *HPAEnforcementController) patchShadowAdoption(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	status metav1.ConditionStatus, reason, message string, since metav1.Time, comparison *v1alpha1.ShadowComparison) error {
+	shadowPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.ShadowAdoption, status, reason, message)
+	shadowPatch.Status.ShadowAdoptionSince = &since
+	shadowPatch.Status.ShadowComparison = comparison
+	return r.Status().Patch(ctx, shadowPatch, client.Apply, getSubresourcePatchOptions(ShadowAdoptionStatusManager))
+}
+
+func shadowComparisonMessage(hpaName string, comparison v1alpha1.ShadowComparison) string {
+	verdict := "doesn't perform on par with"
+	if comparison.AtParity() {
+		verdict = "performs on par with or better than"
+	}
+	return fmt.Sprintf("The recommendation %s the HPA %s from %s to %s: %d replica-hours and %d breached datapoints against %d and %d of the HPA",
+		verdict, hpaName, comparison.WindowStart.Format(time.RFC3339), comparison.WindowEnd.Format(time.RFC3339),
+		comparison.ShadowReplicaHours, comparison.ShadowBreaches, comparison.HPAReplicaHours, comparison.HPABreaches)
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeShadowComparator returns the comparison it's set up with, counting the comparisons.
+type fakeShadowComparator struct {
+	comparison  v1alpha1.ShadowComparison
+	comparisons int
+}
+
+func (f *fakeShadowComparator) Compare(ctx context.Context, workloadMeta reco.WorkloadMeta,
+	config v1alpha1.HPAConfiguration, start, end time.Time) (*v1alpha1.ShadowComparison, error) {
+	f.comparisons++
+	return &f.comparison, nil
+}
+
+var _ = Describe("Shadow adoption", func() {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	period := 7 * 24 * time.Hour
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	userHPA := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			Kind: "Deployment", Name: "app"}},
+	}
+	shadowed := func(since time.Time, comparison *v1alpha1.ShadowComparison) v1alpha1.PolicyRecommendation {
+		shadowSince := metav1.NewTime(since)
+		return v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Status:     v1alpha1.PolicyRecommendationStatus{ShadowAdoptionSince: &shadowSince, ShadowComparison: comparison},
+		}
+	}
+	comparison := func(windowEnd time.Time, shadowReplicaHours int64) *v1alpha1.ShadowComparison {
+		return &v1alpha1.ShadowComparison{WindowStart: metav1.NewTime(windowEnd.Add(-period)),
+			WindowEnd: metav1.NewTime(windowEnd), HPAReplicaHours: 1680, ShadowReplicaHours: shadowReplicaHours,
+			HPABreaches: 2, ShadowBreaches: 1}
+	}
+
+	var comparator *fakeShadowComparator
+	newController := func(objects ...client.Object) *HPAEnforcementController {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(kedaapi.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithIndex(&kedaapi.ScaledObject{}, autoscalerField, func(obj client.Object) []string {
+				return []string{obj.(*kedaapi.ScaledObject).Spec.ScaleTargetRef.Name}
+			}).Build()
+		comparator = &fakeShadowComparator{}
+		return &HPAEnforcementController{Client: k8sClient, autoscalerClient: autoscaler.NewScaledobjectClient(k8sClient),
+			ShadowAdoption: &ShadowAdoption{Period: period, Comparator: comparator}}
+	}
+
+	It("should not hold the workloads without an HPA of their owners", func() {
+		r := newController()
+		held, _, _, err := r.holdForShadowAdoption(context.TODO(), shadowed(now, nil), workload, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+	})
+
+	It("should not hold the workloads already autoscaled by ottoscalr", func() {
+		scaledObject := &kedaapi.ScaledObject{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default",
+				Labels: map[string]string{createdByLabelKey: createdByLabelValue}},
+			Spec: kedaapi.ScaledObjectSpec{ScaleTargetRef: &kedaapi.ScaleTarget{Name: "app"}},
+		}
+		r := newController(userHPA, scaledObject)
+		held, _, _, err := r.holdForShadowAdoption(context.TODO(), shadowed(now, nil), workload, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+	})
+
+	It("should hold the workload till the end of the shadow period", func() {
+		r := newController(userHPA)
+		held, message, requeueAfter, err := r.holdForShadowAdoption(context.TODO(), shadowed(now.Add(-5*24*time.Hour), nil), workload, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeTrue())
+		Expect(message).To(ContainSubstring("app-hpa"))
+		Expect(requeueAfter).To(Equal(2 * 24 * time.Hour))
+		Expect(comparator.comparisons).To(BeZero())
+	})
+
+	It("should adopt the workload once the recommendation compared on par with the HPA", func() {
+		r := newController(userHPA)
+		held, _, _, err := r.holdForShadowAdoption(context.TODO(),
+			shadowed(now.Add(-8*24*time.Hour), comparison(now.Add(-time.Hour), 1120)), workload, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeFalse())
+		Expect(comparator.comparisons).To(BeZero())
+	})
+
+	It("should keep holding the workload till the next comparison while the recommendation regresses", func() {
+		r := newController(userHPA)
+		held, message, requeueAfter, err := r.holdForShadowAdoption(context.TODO(),
+			shadowed(now.Add(-8*24*time.Hour), comparison(now.Add(-time.Hour), 2000)), workload, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeTrue())
+		Expect(message).To(ContainSubstring("doesn't perform on par"))
+		Expect(requeueAfter).To(Equal(23 * time.Hour))
+		Expect(comparator.comparisons).To(BeZero())
+	})
+})
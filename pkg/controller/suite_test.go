@@ -21,7 +21,9 @@ import (
 	"time"
 
 	rolloutv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/audit"
 	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/notifier"
 	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
 	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
 	"github.com/flipkart-incubator/ottoscalr/pkg/testutil"
@@ -44,6 +46,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	ottoscaleriov1beta1 "github.com/flipkart-incubator/ottoscalr/api/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -95,6 +98,9 @@ var _ = BeforeSuite(func() {
 	err = ottoscaleriov1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = ottoscaleriov1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = kedaapi.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 	//+kubebuilder:scaffold:Scheme
@@ -150,6 +156,13 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	err = NewPolicyRecommendationBindingReconciler(k8sManager.GetClient(),
+		k8sManager.GetScheme(),
+		0,
+		&FakeMonitorManager{},
+		newFakePolicyStore(), clientsRegistry).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&PolicyWatcher{
 		Client: k8sManager.GetClient(),
 		Scheme: k8sManager.GetScheme(),
@@ -168,7 +181,7 @@ var _ = BeforeSuite(func() {
 
 	policyRecoReconciler, err := NewPolicyRecommendationReconciler(k8sManager.GetClient(),
 		k8sManager.GetScheme(), k8sManager.GetEventRecorderFor(PolicyRecoWorkflowCtrlName),
-		1, 3, recommender, newFakePolicyStore(), reco.NewDefaultPolicyIterator(k8sManager.GetClient()),
+		1, 3, recommender, newFakePolicyStore(), audit.NewAuditor(logger), notifier.NewRoutingNotifier(logger, 0, 0, nil), nil, nil, nil, nil, nil, reco.NewDefaultPolicyIterator(k8sManager.GetClient()),
 		reco.NewAgingPolicyIterator(k8sManager.GetClient(), policyAge))
 	Expect(err).NotTo(HaveOccurred())
 	err = policyRecoReconciler.
@@ -185,8 +198,8 @@ var _ = BeforeSuite(func() {
 	var autoscalerCRUD autoscaler.AutoscalerClient
 	autoscalerCRUD = autoscaler.NewScaledobjectClient(k8sManager.GetClient())
 	hpaenforcer, err := NewHPAEnforcementController(k8sManager.GetClient(),
-		k8sManager.GetScheme(),clientsRegistry, k8sManager.GetEventRecorderFor(HPAEnforcementCtrlName),
-		1, hpaEnforcerIsDryRun, hpaEnforcerExcludedNamespaces, hpaEnforcerIncludedNamespaces, whitelistMode, 3, autoscalerCRUD)
+		k8sManager.GetScheme(), clientsRegistry, k8sManager.GetEventRecorderFor(HPAEnforcementCtrlName),
+		1, hpaEnforcerIsDryRun, hpaEnforcerExcludedNamespaces, hpaEnforcerIncludedNamespaces, whitelistMode, 3, autoscalerCRUD, audit.NewAuditor(logger), notifier.NewRoutingNotifier(logger, 0, 0, nil))
 	Expect(err).NotTo(HaveOccurred())
 	err = hpaenforcer.
 		SetupWithManager(k8sManager)
@@ -330,10 +343,10 @@ type MockRecommender struct {
 	Max       int
 }
 
-func (r *MockRecommender) Recommend(ctx context.Context, wm reco.WorkloadMeta) (*ottoscaleriov1alpha1.HPAConfiguration, error) {
+func (r *MockRecommender) Recommend(ctx context.Context, wm reco.WorkloadMeta) (*ottoscaleriov1alpha1.HPAConfiguration, *reco.RecommendationMetadata, error) {
 	return &ottoscaleriov1alpha1.HPAConfiguration{
 		Min:               r.Min,
 		Max:               r.Max,
 		TargetMetricValue: r.Threshold,
-	}, nil
+	}, nil, nil
 }
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DependsOnAnnotation lists the workloads of its namespace a workload calls, separated by commas.
+	DependsOnAnnotation = "ottoscalr.io/depends-on"
+	// WorkloadGroupAnnotation names the group of the workloads of a namespace whose recommendations are applied
+	// together.
+	WorkloadGroupAnnotation = "ottoscalr.io/workload-group"
+	// dependencyRequeueInterval is how often a workload whose min replicas cut is held for its callers or its group is
+	// reconciled again.
+	dependencyRequeueInterval = time.Minute
+)
+
+var (
+	hpaenforcerDependencyCutsHeldCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "hpaenforcer_dependency_cuts_held_count",
+			Help: "Number of min replicas cuts held back till the callers or the group of the workload apply their recommendations"}, []string{"namespace", "policyreco"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(hpaenforcerDependencyCutsHeldCounter)
+}
+
+// dependsOn returns the workloads the workload calls, from its DependsOnAnnotation.
+func dependsOn(workload client.Object) []string {
+	var dependencies []string
+	for _, name := range strings.Split(workload.GetAnnotations()[DependsOnAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			dependencies = append(dependencies, name)
+		}
+	}
+	return dependencies
+}
+
+// coordinatedWorkloads returns the callers of the workload among the workloads of its namespace, i.e. the workloads
+// depending on it, along with the other workloads of its group. The callers the workload depends on itself, directly or
+// through its dependencies, are left out so that a cycle of dependencies can't hold its workloads forever.
+func coordinatedWorkloads(workload client.Object, workloads []client.Object) (callers, group []client.Object) {
+	dependencies := map[string][]string{}
+	for _, w := range workloads {
+		dependencies[w.GetName()] = dependsOn(w)
+	}
+	dependencies[workload.GetName()] = dependsOn(workload)
+
+	reachable := map[string]bool{}
+	next := dependencies[workload.GetName()]
+	for len(next) > 0 {
+		name := next[0]
+		next = next[1:]
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		next = append(next, dependencies[name]...)
+	}
+
+	groupName := workload.GetAnnotations()[WorkloadGroupAnnotation]
+	for _, w := range workloads {
+		if w.GetName() == workload.GetName() {
+			continue
+		}
+		if !reachable[w.GetName()] {
+			for _, dependency := range dependencies[w.GetName()] {
+				if dependency == workload.GetName() {
+					callers = append(callers, w)
+					break
+				}
+			}
+		}
+		if groupName != "" && w.GetAnnotations()[WorkloadGroupAnnotation] == groupName {
+			group = append(group, w)
+		}
+	}
+	return callers, group
+}
+
+// pendingCoordinatedWorkload returns a caller of the workload whose min replicas cut isn't enforced yet, or a workload
+// of its group whose recommendation isn't generated yet, so that the min replicas of the workload aren't cut before
+// the load it gets from its callers is. It returns an empty name if there isn't any.
+func (r *HPAEnforcementController) pendingCoordinatedWorkload(ctx context.Context, workload client.Object) (string, error) {
+	workloads, err := r.namespaceWorkloads(workload.GetNamespace())
+	if err != nil {
+		return "", err
+	}
+	callers, group := coordinatedWorkloads(workload, workloads)
+
+	for _, caller := range callers {
+		policyreco := v1alpha1.PolicyRecommendation{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: caller.GetNamespace(), Name: caller.GetName()}, &policyreco); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return "", err
+			}
+			continue
+		}
+		recommendedMin := int32(policyreco.Spec.CurrentHPAConfiguration.Min)
+		managedMin, err := r.managedMinReplicas(ctx, caller, recommendedMin)
+		if err != nil {
+			return "", err
+		}
+		if managedMin != recommendedMin {
+			return caller.GetName(), nil
+		}
+	}
+	for _, peer := range group {
+		policyreco := v1alpha1.PolicyRecommendation{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: peer.GetNamespace(), Name: peer.GetName()}, &policyreco); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return "", err
+			}
+			continue
+		}
+		if !isRecoGenerated(policyreco.Status.Conditions) {
+			return peer.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// namespaceWorkloads returns the workloads of the namespace of every kind of the clients registry.
+func (r *HPAEnforcementController) namespaceWorkloads(namespace string) ([]client.Object, error) {
+	var workloads []client.Object
+	for _, object := range r.clientsRegistry.Clients {
+		objects, err := object.GetObjectList(namespace, labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, objects...)
+	}
+	return workloads, nil
+}
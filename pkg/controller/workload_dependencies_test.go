@@ -0,0 +1,54 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Workload dependencies", func() {
+	workload := func(name string, annotations map[string]string) client.Object {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations}}
+	}
+	names := func(workloads []client.Object) []string {
+		var result []string
+		for _, w := range workloads {
+			result = append(result, w.GetName())
+		}
+		return result
+	}
+
+	It("should parse the dependencies of the workloads", func() {
+		Expect(dependsOn(workload("frontend", map[string]string{DependsOnAnnotation: " backend, ,cache "}))).
+			Should(Equal([]string{"backend", "cache"}))
+		Expect(dependsOn(workload("backend", nil))).Should(BeEmpty())
+	})
+
+	It("should find the callers and the group of a workload", func() {
+		backend := workload("backend", map[string]string{DependsOnAnnotation: "db", WorkloadGroupAnnotation: "checkout"})
+		workloads := []client.Object{
+			workload("frontend", map[string]string{DependsOnAnnotation: "backend"}),
+			workload("admin", map[string]string{DependsOnAnnotation: "backend,db", WorkloadGroupAnnotation: "checkout"}),
+			backend,
+			workload("db", nil),
+			workload("payments", map[string]string{WorkloadGroupAnnotation: "payments"}),
+		}
+		callers, group := coordinatedWorkloads(backend, workloads)
+		Expect(names(callers)).Should(ConsistOf("frontend", "admin"))
+		Expect(names(group)).Should(ConsistOf("admin"))
+
+		callers, group = coordinatedWorkloads(workloads[3], workloads)
+		Expect(names(callers)).Should(ConsistOf("admin", "backend"))
+		Expect(group).Should(BeEmpty())
+	})
+
+	It("should ignore the callers in a cycle of dependencies", func() {
+		a := workload("a", map[string]string{DependsOnAnnotation: "b"})
+		b := workload("b", map[string]string{DependsOnAnnotation: "c"})
+		c := workload("c", map[string]string{DependsOnAnnotation: "a"})
+		callers, _ := coordinatedWorkloads(a, []client.Object{a, b, c})
+		Expect(callers).Should(BeEmpty())
+	})
+})
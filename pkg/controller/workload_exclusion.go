@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	WorkloadExclusionStatusManager = "WorkloadExclusionStatusManager"
+
+	//Reasons for Skipped Condition
+	ExcludedOwnerKind       = "ExcludedOwnerKind"
+	ExcludedLabel           = "ExcludedLabel"
+	WorkloadIncluded        = "WorkloadIncluded"
+	WorkloadIncludedMessage = "The workload isn't excluded from autoscaling"
+)
+
+// DefaultExcludedOwnerKinds are the kinds of the owners of the workloads which aren't autoscaled unless configured
+// otherwise, as the workloads run to completion rather than serve traffic.
+var DefaultExcludedOwnerKinds = []string{"Job", "CronJob"}
+
+// WorkloadExclusion excludes the workloads which are meaningless to autoscale, e.g. the deployments created by
+// CronJobs, from the recommendations.
+type WorkloadExclusion struct {
+	// OwnerKinds are the kinds of the owners whose workloads are excluded.
+	OwnerKinds []string
+	// Selectors are the label selectors of the workloads which are excluded, e.g. a batch label.
+	Selectors []labels.Selector
+}
+
+// NewWorkloadExclusion creates a WorkloadExclusion excluding the workloads owned by the ownerKinds or matching any of
+// the label selectors.
+func NewWorkloadExclusion(ownerKinds []string, labelSelectors []string) (WorkloadExclusion, error) {
+	exclusion := WorkloadExclusion{OwnerKinds: ownerKinds}
+	for _, labelSelector := range labelSelectors {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return WorkloadExclusion{}, fmt.Errorf("invalid label selector %s: %v", labelSelector, err)
+		}
+		exclusion.Selectors = append(exclusion.Selectors, selector)
+	}
+	return exclusion, nil
+}
+
+// excludes returns why the workload is excluded, along with a message, or an empty reason if it isn't.
+func (e WorkloadExclusion) excludes(workload metav1.Object) (string, string) {
+	for _, owner := range workload.GetOwnerReferences() {
+		for _, kind := range e.OwnerKinds {
+			if owner.Kind == kind {
+				return ExcludedOwnerKind, fmt.Sprintf("The workload is owned by the %s %s", owner.Kind, owner.Name)
+			}
+		}
+	}
+	for _, selector := range e.Selectors {
+		if selector.Matches(labels.Set(workload.GetLabels())) {
+			return ExcludedLabel, fmt.Sprintf("The workload matches the label selector %s", selector.String())
+		}
+	}
+	return "", ""
+}
+
+// excludedReason returns why the workload of the policyreco is excluded, along with a message, or an empty reason if
+// it isn't or it isn't found.
+func (r *PolicyRecommendationReconciler) excludedReason(ctx context.Context, policyreco v1alpha1.PolicyRecommendation) (string, string, error) {
+	if len(r.WorkloadExclusion.OwnerKinds) == 0 && len(r.WorkloadExclusion.Selectors) == 0 {
+		return "", "", nil
+	}
+	workload := &metav1.PartialObjectMetadata{TypeMeta: policyreco.Spec.WorkloadMeta.TypeMeta}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: policyreco.Namespace, Name: policyreco.Spec.WorkloadMeta.Name}, workload); err != nil {
+		return "", "", client.IgnoreNotFound(err)
+	}
+	reason, message := r.WorkloadExclusion.excludes(workload)
+	return reason, message, nil
+}
+
+// skipExcludedWorkload marks the workload of the policyreco as skipped and completes the recommendation task without
+// generating a recommendation.
+func (r *PolicyRecommendationReconciler) skipExcludedWorkload(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	reason string, message string) (ctrl.Result, error) {
+	logger := ctrl.LoggerFrom(ctx).WithName(PolicyRecoWorkflowCtrlName)
+	logger.V(0).Info("Skipping the recommendation workflow as the workload is excluded from autoscaling.", "reason", message)
+
+	exclusionPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.Skipped, metav1.ConditionTrue, reason, message)
+	if err := r.Status().Patch(ctx, exclusionPatch, client.Apply, getSubresourcePatchOptions(WorkloadExclusionStatusManager)); err != nil {
+		logger.Error(err, "Error updating the status of the policy reco object")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logPolicyRecoGaugeMetric(policyreco, v1alpha1.Skipped, metav1.ConditionTrue)
+
+	statusPatch, _ := CreatePolicyPatch(policyreco, nil, v1alpha1.RecoTaskQueued, metav1.ConditionFalse, RecoTaskExecutionDone, RecoTaskExecutionDoneMessage)
+	if err := r.Status().Patch(ctx, statusPatch, client.Apply, getSubresourcePatchOptions(RecoQueuedStatusManager)); err != nil {
+		logger.Error(err, "Error updating the status of the policy reco object")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	r.Recorder.Event(&policyreco, eventTypeNormal, "WorkloadExcluded", fmt.Sprintf("%s. Skipping the recommendation workflow.", message))
+	reco.CountNoOpRecommendation(policyreco.Namespace, reco.NoOpExcluded)
+	return ctrl.Result{}, nil
+}
+
+// isWorkloadSkipped returns true if the policyreco was last marked with the Skipped condition.
+func isWorkloadSkipped(policyreco v1alpha1.PolicyRecommendation) bool {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.Skipped) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+
+	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WorkloadExclusion", func() {
+	newDeployment := func(name string, ownerKind string, labels map[string]string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+		if len(ownerKind) > 0 {
+			deployment.OwnerReferences = []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: ownerKind, Name: "nightly", UID: "uid"}}
+		}
+		return deployment
+	}
+
+	It("should exclude the workloads owned by the excluded kinds or matching the label selectors", func() {
+		exclusion, err := NewWorkloadExclusion(DefaultExcludedOwnerKinds, []string{"workload-type=batch"})
+		Expect(err).NotTo(HaveOccurred())
+
+		reason, message := exclusion.excludes(newDeployment("report", "CronJob", nil))
+		Expect(reason).To(Equal(ExcludedOwnerKind))
+		Expect(message).To(ContainSubstring("CronJob nightly"))
+		reason, _ = exclusion.excludes(newDeployment("report", "", map[string]string{"workload-type": "batch"}))
+		Expect(reason).To(Equal(ExcludedLabel))
+		reason, _ = exclusion.excludes(newDeployment("checkout", "ReplicaSet", map[string]string{"workload-type": "web"}))
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("should reject the invalid label selectors", func() {
+		_, err := NewWorkloadExclusion(nil, []string{"workload-type in batch"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should look up the workload of the policyreco", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newDeployment("report", "Job", nil)).Build()
+		exclusion, err := NewWorkloadExclusion(DefaultExcludedOwnerKinds, nil)
+		Expect(err).NotTo(HaveOccurred())
+		r := &PolicyRecommendationReconciler{Client: k8sClient, WorkloadExclusion: exclusion}
+
+		policyreco := func(name string) v1alpha1.PolicyRecommendation {
+			return v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: v1alpha1.PolicyRecommendationSpec{WorkloadMeta: v1alpha1.WorkloadMeta{
+					TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}, Name: name}}}
+		}
+		reason, _, err := r.excludedReason(context.TODO(), policyreco("report"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal(ExcludedOwnerKind))
+		reason, _, err = r.excludedReason(context.TODO(), policyreco("deleted"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+})
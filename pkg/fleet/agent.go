@@ -0,0 +1,291 @@
+package fleet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// FleetManagedLabel marks the policies synced from the central recommender. The policies without it are left alone.
+const FleetManagedLabel = "ottoscalr.io/fleet-managed"
+
+var (
+	fleetPolicySyncErrorsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{Name: "fleet_policy_sync_errors_count",
+			Help: "Number of failed syncs of the policies from the central recommender"},
+	)
+
+	fleetPolicySyncConflictsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "fleet_policy_sync_conflicts_count",
+			Help: "Number of policies of the central recommender not synced as is as they conflict with the local policies, by reason"},
+		[]string{"policy", "reason"},
+	)
+)
+
+const (
+	// localPolicyConflict is a policy of the central recommender named like a local policy, which is left alone.
+	localPolicyConflict = "local_policy"
+	// defaultPolicyConflict is a default policy of the central recommender, synced as a non default policy as the
+	// cluster has a local default policy.
+	defaultPolicyConflict = "default_policy"
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(fleetPolicySyncErrorsCounter, fleetPolicySyncConflictsCounter)
+}
+
+// Client talks to the central recommender on behalf of the agent of a cluster, with the token of the fleet.
+type Client struct {
+	centralURL string
+	cluster    string
+	authToken  string
+	httpClient *http.Client
+}
+
+func NewClient(centralURL string, cluster string, authToken string, timeout time.Duration) *Client {
+	return &Client{
+		centralURL: strings.TrimSuffix(centralURL, "/"),
+		cluster:    cluster,
+		authToken:  authToken,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// WithRootCAs makes the client verify the certificate of the central recommender with the CAs instead of the ones of
+// the host.
+func (c *Client) WithRootCAs(rootCAs *x509.CertPool) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	c.httpClient.Transport = transport
+	return c
+}
+
+// Recommend sends the summary of the workload to the central recommender and returns its recommendation.
+func (c *Client) Recommend(ctx context.Context, wm reco.WorkloadMeta, summary *reco.WorkloadSummary) (*RecommendResponse, error) {
+	var body bytes.Buffer
+	gzipWriter := gzip.NewWriter(&body)
+	if err := json.NewEncoder(gzipWriter).Encode(NewRecommendRequest(c.cluster, wm, summary)); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.centralURL+recommendPath, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	response := &RecommendResponse{}
+	if err := c.do(req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetPolicies returns the policies of the central recommender.
+func (c *Client) GetPolicies(ctx context.Context) ([]FleetPolicy, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.centralURL+policiesPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var policies []FleetPolicy
+	if err := c.do(req, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (c *Client) do(req *http.Request, response interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+c.authToken)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("central recommender responded to %s with status code %d: %s", req.URL.Path, resp.StatusCode,
+			strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// WorkloadSummarizer summarizes the workloads of the agent's cluster.
+type WorkloadSummarizer interface {
+	SummarizeWorkload(ctx context.Context, wm reco.WorkloadMeta) (*reco.WorkloadSummary, error)
+}
+
+// RemoteRecommender is the recommender of an agent. It summarizes the workloads from the metrics and the objects of
+// its cluster and has the central recommender generate their recommendations.
+type RemoteRecommender struct {
+	summarizer WorkloadSummarizer
+	client     *Client
+}
+
+func NewRemoteRecommender(summarizer WorkloadSummarizer, client *Client) *RemoteRecommender {
+	return &RemoteRecommender{
+		summarizer: summarizer,
+		client:     client,
+	}
+}
+
+func (r *RemoteRecommender) Recommend(ctx context.Context, wm reco.WorkloadMeta) (*v1alpha1.HPAConfiguration,
+	*reco.RecommendationMetadata, error) {
+	summary, err := r.summarizer.SummarizeWorkload(ctx, wm)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := r.client.Recommend(ctx, wm, summary)
+	if err != nil {
+		return nil, nil, err
+	}
+	if response.Recommendation == nil {
+		return nil, nil, fmt.Errorf("central recommender returned no recommendation for %s/%s", wm.Namespace, wm.Name)
+	}
+	return response.Recommendation, &reco.RecommendationMetadata{
+		MetricsWindowStart:        response.MetricsWindowStart,
+		MetricsWindowEnd:          response.MetricsWindowEnd,
+		DataPointsCoveragePercent: response.DataPointsCoveragePercent,
+		ProjectedSavingsPercent:   response.ProjectedSavingsPercent,
+		TransformersApplied:       response.TransformersApplied,
+		TransformerAudits:         response.TransformerAudits,
+		CronTriggers:              response.CronTriggers,
+		IdleWindow:                response.IdleWindow,
+		InsufficientHistory:       response.InsufficientHistory,
+		WorkloadAge:               response.WorkloadAge,
+		NoOp:                      response.NoOp,
+		Confidence:                response.Confidence,
+		Resize:                    response.Resize,
+	}, nil
+}
+
+// PolicySyncer periodically syncs the policies of the central recommender to the agent's cluster. The synced
+// policies are labelled with FleetManagedLabel and are deleted once they are removed from the central recommender.
+type PolicySyncer struct {
+	k8sClient client.Client
+	client    *Client
+	interval  time.Duration
+	logger    logr.Logger
+}
+
+func NewPolicySyncer(k8sClient client.Client, fleetClient *Client, interval time.Duration, logger logr.Logger) *PolicySyncer {
+	return &PolicySyncer{
+		k8sClient: k8sClient,
+		client:    fleetClient,
+		interval:  interval,
+		logger:    logger.WithName("FleetPolicySyncer"),
+	}
+}
+
+// Start syncs the policies right away and then every interval until the context is cancelled. It implements the
+// manager.Runnable interface.
+func (s *PolicySyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sync(ctx); err != nil {
+			fleetPolicySyncErrorsCounter.Inc()
+			s.logger.Error(err, "Error syncing the policies from the central recommender.")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection is true so that only the leader syncs the policies.
+func (s *PolicySyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// Sync creates or updates the policies of the central recommender and deletes the synced policies which aren't
+// there anymore. The local policies are never adopted: a policy of the central recommender named like one is skipped,
+// and its default policy is synced as a non default one while the cluster has a local default policy.
+func (s *PolicySyncer) Sync(ctx context.Context) error {
+	fleetPolicies, err := s.client.GetPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	policies := &v1alpha1.PolicyList{}
+	if err := s.k8sClient.List(ctx, policies); err != nil {
+		return err
+	}
+	existing := make(map[string]v1alpha1.Policy, len(policies.Items))
+	localDefault := ""
+	for _, policy := range policies.Items {
+		existing[policy.Name] = policy
+		if policy.Spec.IsDefault && policy.Labels[FleetManagedLabel] != "true" {
+			localDefault = policy.Name
+		}
+	}
+
+	synced := make(map[string]bool, len(fleetPolicies))
+	for _, fleetPolicy := range fleetPolicies {
+		synced[fleetPolicy.Name] = true
+		policy, ok := existing[fleetPolicy.Name]
+		if ok && policy.Labels[FleetManagedLabel] != "true" {
+			fleetPolicySyncConflictsCounter.WithLabelValues(fleetPolicy.Name, localPolicyConflict).Inc()
+			s.logger.Info("Skipping the policy of the central recommender as a local policy has its name",
+				"policy", fleetPolicy.Name)
+			continue
+		}
+		spec := fleetPolicy.Spec
+		if spec.IsDefault && len(localDefault) > 0 {
+			spec.IsDefault = false
+			fleetPolicySyncConflictsCounter.WithLabelValues(fleetPolicy.Name, defaultPolicyConflict).Inc()
+			s.logger.Info("Syncing the default policy of the central recommender as a non default policy as the "+
+				"cluster has a local default policy", "policy", fleetPolicy.Name, "localDefaultPolicy", localDefault)
+		}
+		if !ok {
+			policy = v1alpha1.Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: fleetPolicy.Name, Labels: map[string]string{FleetManagedLabel: "true"}},
+				Spec:       spec,
+			}
+			if err := s.k8sClient.Create(ctx, &policy); err != nil {
+				return err
+			}
+			s.logger.Info("Created the policy of the central recommender", "policy", fleetPolicy.Name)
+			continue
+		}
+		if policy.Spec == spec {
+			continue
+		}
+		policy.Spec = spec
+		if err := s.k8sClient.Update(ctx, &policy); err != nil {
+			return err
+		}
+		s.logger.Info("Updated the policy to the central recommender's", "policy", fleetPolicy.Name)
+	}
+
+	for name, policy := range existing {
+		if synced[name] || policy.Labels[FleetManagedLabel] != "true" {
+			continue
+		}
+		if err := s.k8sClient.Delete(ctx, &policy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		s.logger.Info("Deleted the policy removed from the central recommender", "policy", name)
+	}
+	return nil
+}
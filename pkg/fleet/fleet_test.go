@@ -0,0 +1,214 @@
+package fleet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeSummaryRecommender recommends from the summary the central recommender receives.
+type fakeSummaryRecommender struct {
+	summary *reco.WorkloadSummary
+}
+
+func (f *fakeSummaryRecommender) RecommendForSummary(ctx context.Context, wm reco.WorkloadMeta,
+	summary *reco.WorkloadSummary) (*v1alpha1.HPAConfiguration, *reco.RecommendationMetadata, error) {
+	f.summary = summary
+	return &v1alpha1.HPAConfiguration{Min: len(summary.DataPoints), Max: summary.MaxReplicas,
+			TargetMetricValue: int(summary.ACL.Minutes())},
+		&reco.RecommendationMetadata{MetricsWindowStart: summary.MetricsWindowStart, MetricsWindowEnd: summary.MetricsWindowEnd,
+			DataPointsCoveragePercent: int(summary.MetricsWindowEnd.Sub(summary.MetricsWindowStart).Hours()),
+			ProjectedSavingsPercent:   30}, nil
+}
+
+type fakeSummarizer struct {
+	summary *reco.WorkloadSummary
+}
+
+func (f *fakeSummarizer) SummarizeWorkload(ctx context.Context, wm reco.WorkloadMeta) (*reco.WorkloadSummary, error) {
+	return f.summary, nil
+}
+
+func newPolicy(name string, riskIndex int, labels map[string]string) *v1alpha1.Policy {
+	return &v1alpha1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       v1alpha1.PolicySpec{RiskIndex: riskIndex, MinReplicaPercentageCut: 100, TargetUtilization: 50},
+	}
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+var _ = Describe("Fleet", func() {
+	var (
+		centralClient client.Client
+		central       *httptest.Server
+		recommender   *fakeSummaryRecommender
+		fleetClient   *Client
+		summary       *reco.WorkloadSummary
+		wm            reco.WorkloadMeta
+	)
+
+	BeforeEach(func() {
+		centralClient = newFakeClient(newPolicy("safe", 1, nil), newPolicy("aggressive", 10, nil))
+		recommender = &fakeSummaryRecommender{}
+		server := NewServer(centralClient, recommender, ":0", "s3cr3t", logr.Discard())
+		central = httptest.NewServer(server.Handler())
+		DeferCleanup(central.Close)
+		fleetClient = NewClient(central.URL+"/", "cluster-1", "s3cr3t", 5*time.Second)
+
+		end := time.UnixMilli(time.Now().UnixMilli())
+		summary = &reco.WorkloadSummary{
+			MetricsWindowStart: end.Add(-48 * time.Hour),
+			MetricsWindowEnd:   end,
+			DataPoints: []metrics.DataPoint{
+				{Timestamp: end.Add(-2 * time.Minute), Value: 12.5},
+				{Timestamp: end.Add(-time.Minute), Value: 15},
+				{Timestamp: end, Value: 20},
+			},
+			MetricStep:                time.Minute,
+			ACL:                       5 * time.Minute,
+			PerPodResources:           4,
+			MaxReplicas:               40,
+			Labels:                    map[string]string{"tier": "batch"},
+			Annotations:               map[string]string{reco.DowntimeWindowsAnnotation: "* 01:00-02:00"},
+			CreatedAt:                 end.Add(-90 * 24 * time.Hour).UTC(),
+			BreachAssertionViolations: []time.Time{end.Add(-time.Minute)},
+			Pods:                      []metrics.DataPoint{{Timestamp: end.Add(-time.Minute), Value: 6}},
+			Config:                    &reco.WorkloadConfig{Name: "payments", RedLineUtilization: 0.7},
+		}
+		wm = reco.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "app", Namespace: "payments"}
+	})
+
+	It("should carry the summary of the workload through the request", func() {
+		request := NewRecommendRequest("cluster-1", wm, summary)
+		Expect(request.Timestamps).To(HaveLen(3))
+		Expect(request.Summary()).To(Equal(summary))
+	})
+
+	It("should have the central recommender generate the recommendations of the agents", func() {
+		recommendation, recoMetadata, err := NewRemoteRecommender(&fakeSummarizer{summary: summary}, fleetClient).Recommend(context.TODO(), wm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*recommendation).To(Equal(v1alpha1.HPAConfiguration{Min: 3, Max: 40, TargetMetricValue: 5}))
+		Expect(recoMetadata.DataPointsCoveragePercent).To(Equal(48))
+		Expect(recoMetadata.ProjectedSavingsPercent).To(Equal(30))
+		// the central recommender recommends for the window and the workload of the summary
+		Expect(recoMetadata.MetricsWindowEnd.Equal(summary.MetricsWindowEnd)).To(BeTrue())
+		Expect(recommender.summary.Annotations).To(Equal(summary.Annotations))
+		Expect(recommender.summary.Config).To(Equal(summary.Config))
+
+		req, err := http.NewRequest(http.MethodGet, central.URL+recommendationsPath+"?cluster=cluster-1", nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should reject the requests without the token of the fleet", func() {
+		_, err := NewClient(central.URL, "cluster-1", "guessed", 5*time.Second).Recommend(context.TODO(), wm, summary)
+		Expect(err).To(MatchError(ContainSubstring("status code 401")))
+		Expect(recommender.summary).To(BeNil())
+
+		for _, path := range []string{recommendationsPath, policiesPath} {
+			resp, err := http.Get(central.URL + path)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		}
+	})
+
+	It("should reject the summaries without the workload", func() {
+		wm.Name = ""
+		_, err := fleetClient.Recommend(context.TODO(), wm, summary)
+		Expect(err).To(MatchError(ContainSubstring("status code 400")))
+	})
+
+	It("should bound the summaries decompressed", func() {
+		// a valid summary, but for its size
+		summary.Labels["padding"] = strings.Repeat("a", maxRecommendRequestBytes)
+		var body bytes.Buffer
+		gzipWriter := gzip.NewWriter(&body)
+		Expect(json.NewEncoder(gzipWriter).Encode(NewRecommendRequest("cluster-1", wm, summary))).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+		Expect(body.Len()).To(BeNumerically("<", maxRecommendRequestBytes/100))
+
+		req, err := http.NewRequest(http.MethodPost, central.URL+recommendPath, &body)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		req.Header.Set("Content-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(recommender.summary).To(BeNil())
+	})
+
+	It("should sync the policies of the central recommender", func() {
+		agentClient := newFakeClient(
+			newPolicy("safe", 2, nil),
+			newPolicy("removed", 5, map[string]string{FleetManagedLabel: "true"}),
+			newPolicy("local", 3, nil),
+		)
+		Expect(NewPolicySyncer(agentClient, fleetClient, time.Minute, logr.Discard()).Sync(context.TODO())).To(Succeed())
+
+		policies := &v1alpha1.PolicyList{}
+		Expect(agentClient.List(context.TODO(), policies)).To(Succeed())
+		riskIndices := map[string]int{}
+		for _, policy := range policies.Items {
+			riskIndices[policy.Name] = policy.Spec.RiskIndex
+		}
+		// the local policy named like a policy of the central recommender is left alone
+		Expect(riskIndices).To(Equal(map[string]int{"safe": 2, "aggressive": 10, "local": 3}))
+
+		local := &v1alpha1.Policy{}
+		Expect(agentClient.Get(context.TODO(), types.NamespacedName{Name: "safe"}, local)).To(Succeed())
+		Expect(local.Labels).NotTo(HaveKey(FleetManagedLabel))
+		synced := &v1alpha1.Policy{}
+		Expect(agentClient.Get(context.TODO(), types.NamespacedName{Name: "aggressive"}, synced)).To(Succeed())
+		Expect(synced.Labels).To(HaveKeyWithValue(FleetManagedLabel, "true"))
+	})
+
+	It("should sync the default policy of the central recommender unless the cluster has its own", func() {
+		centralPolicy := &v1alpha1.Policy{}
+		Expect(centralClient.Get(context.TODO(), types.NamespacedName{Name: "safe"}, centralPolicy)).To(Succeed())
+		centralPolicy.Spec.IsDefault = true
+		Expect(centralClient.Update(context.TODO(), centralPolicy)).To(Succeed())
+
+		localDefault := newPolicy("local-default", 3, nil)
+		localDefault.Spec.IsDefault = true
+		agentClient := newFakeClient(localDefault)
+		syncer := NewPolicySyncer(agentClient, fleetClient, time.Minute, logr.Discard())
+		Expect(syncer.Sync(context.TODO())).To(Succeed())
+		synced := &v1alpha1.Policy{}
+		Expect(agentClient.Get(context.TODO(), types.NamespacedName{Name: "safe"}, synced)).To(Succeed())
+		Expect(synced.Spec.IsDefault).To(BeFalse())
+
+		// the default policy of the central recommender is the default once the local one is gone
+		Expect(agentClient.Delete(context.TODO(), localDefault)).To(Succeed())
+		Expect(syncer.Sync(context.TODO())).To(Succeed())
+		Expect(agentClient.Get(context.TODO(), types.NamespacedName{Name: "safe"}, synced)).To(Succeed())
+		Expect(synced.Spec.IsDefault).To(BeTrue())
+	})
+})
@@ -0,0 +1,287 @@
+package fleet
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	recommendPath       = "/fleet/v1/recommend"
+	recommendationsPath = "/fleet/v1/recommendations"
+	policiesPath        = "/fleet/v1/policies"
+
+	// maxRecommendRequestBytes bounds the bodies of the recommend requests, compressed or not. A summary of a 28 days
+	// window of 30s datapoints is a few MBs.
+	maxRecommendRequestBytes = 64 << 20
+	shutdownTimeout          = 10 * time.Second
+)
+
+var (
+	fleetRecommendationsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "fleet_recommendations_count",
+			Help: "Number of recommendations generated by the central recommender by cluster and result"},
+		[]string{"cluster", "result"},
+	)
+
+	fleetUnauthorizedRequestsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{Name: "fleet_unauthorized_requests_count",
+			Help: "Number of requests to the central recommender rejected as they don't bear the token of the fleet"},
+	)
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(fleetRecommendationsCounter, fleetUnauthorizedRequestsCounter)
+}
+
+// SummaryRecommender generates the recommendation of a workload from its summary, for the metrics window of the
+// summary.
+type SummaryRecommender interface {
+	RecommendForSummary(ctx context.Context, wm reco.WorkloadMeta, summary *reco.WorkloadSummary) (*v1alpha1.HPAConfiguration,
+		*reco.RecommendationMetadata, error)
+}
+
+// Server is the central recommender of a fleet. It generates the recommendations of the workloads summarized by the
+// agents of the clusters and distributes its policies to them, so that all the clusters are recommended for with
+// the same configuration and policies. Only the requests bearing the token of the fleet are served.
+type Server struct {
+	k8sClient       client.Client
+	recommender     SummaryRecommender
+	bindAddress     string
+	authToken       string
+	tlsCertFile     string
+	tlsKeyFile      string
+	mu              sync.RWMutex
+	recommendations map[string]FleetRecommendation
+	logger          logr.Logger
+}
+
+func NewServer(k8sClient client.Client, recommender SummaryRecommender, bindAddress string, authToken string,
+	logger logr.Logger) *Server {
+	return &Server{
+		k8sClient:       k8sClient,
+		recommender:     recommender,
+		bindAddress:     bindAddress,
+		authToken:       authToken,
+		recommendations: make(map[string]FleetRecommendation),
+		logger:          logger.WithName("FleetServer"),
+	}
+}
+
+// WithTLS makes the server serve the agents over TLS with the certificate and the key of the files.
+func (s *Server) WithTLS(certFile, keyFile string) *Server {
+	s.tlsCertFile = certFile
+	s.tlsKeyFile = keyFile
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(recommendPath, s.authenticated(s.recommend))
+	mux.HandleFunc(recommendationsPath, s.authenticated(s.listRecommendations))
+	mux.HandleFunc(policiesPath, s.authenticated(s.listPolicies))
+	return mux
+}
+
+// authenticated serves the requests bearing the token of the fleet and rejects the others.
+func (s *Server) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || len(s.authToken) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+			fleetUnauthorizedRequestsCounter.Inc()
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// Start serves the agents until the context is cancelled. It implements the manager.Runnable interface.
+func (s *Server) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.bindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error(err, "Error shutting down the fleet server")
+		}
+	}()
+
+	s.logger.Info("Starting the fleet server", "address", s.bindAddress, "tls", len(s.tlsCertFile) > 0)
+	var err error
+	if len(s.tlsCertFile) > 0 {
+		err = server.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection is false as all the replicas generate recommendations. The fleet view of a replica only lists
+// the recommendations it generated.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) recommend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxRecommendRequestBytes)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gzipReader.Close()
+		// a summary is decompressed up to the same bound, so that a small body can't inflate without bound
+		body = io.LimitReader(gzipReader, maxRecommendRequestBytes)
+	}
+	request := RecommendRequest{}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(request.Cluster) == 0 || len(request.Namespace) == 0 || len(request.Kind) == 0 || len(request.Name) == 0 {
+		http.Error(w, "cluster, namespace, kind and name are required", http.StatusBadRequest)
+		return
+	}
+	if !request.valid() {
+		http.Error(w, "the metrics of the summary are invalid", http.StatusBadRequest)
+		return
+	}
+
+	recommendation, recoMetadata, err := s.recommender.RecommendForSummary(r.Context(), reco.WorkloadMeta{
+		TypeMeta:  metav1.TypeMeta{Kind: request.Kind, APIVersion: request.APIVersion},
+		Name:      request.Name,
+		Namespace: request.Namespace,
+	}, request.Summary())
+	if err != nil {
+		fleetRecommendationsCounter.WithLabelValues(request.Cluster, "error").Inc()
+		s.logger.Error(err, "Error generating the recommendation", "cluster", request.Cluster,
+			"namespace", request.Namespace, "workload", request.Name)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fleetRecommendationsCounter.WithLabelValues(request.Cluster, "success").Inc()
+
+	response := RecommendResponse{Recommendation: recommendation}
+	if recoMetadata != nil {
+		response.MetricsWindowStart = recoMetadata.MetricsWindowStart
+		response.MetricsWindowEnd = recoMetadata.MetricsWindowEnd
+		response.DataPointsCoveragePercent = recoMetadata.DataPointsCoveragePercent
+		response.ProjectedSavingsPercent = recoMetadata.ProjectedSavingsPercent
+		response.TransformersApplied = recoMetadata.TransformersApplied
+		response.TransformerAudits = recoMetadata.TransformerAudits
+		response.CronTriggers = recoMetadata.CronTriggers
+		response.IdleWindow = recoMetadata.IdleWindow
+		response.InsufficientHistory = recoMetadata.InsufficientHistory
+		response.WorkloadAge = recoMetadata.WorkloadAge
+		response.NoOp = recoMetadata.NoOp
+		response.Confidence = recoMetadata.Confidence
+		response.Resize = recoMetadata.Resize
+	}
+	s.putRecommendation(FleetRecommendation{
+		Cluster:                 request.Cluster,
+		Namespace:               request.Namespace,
+		Kind:                    request.Kind,
+		Name:                    request.Name,
+		Recommendation:          recommendation,
+		ProjectedSavingsPercent: response.ProjectedSavingsPercent,
+		GeneratedAt:             time.Now(),
+	})
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) putRecommendation(recommendation FleetRecommendation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := recommendation.Cluster + "/" + recommendation.Namespace + "/" + recommendation.Kind + "/" + recommendation.Name
+	s.recommendations[key] = recommendation
+}
+
+// listRecommendations lists the last recommendations generated by this replica, optionally of a single cluster.
+func (s *Server) listRecommendations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	s.mu.RLock()
+	recommendations := make([]FleetRecommendation, 0, len(s.recommendations))
+	for _, recommendation := range s.recommendations {
+		if len(cluster) == 0 || recommendation.Cluster == cluster {
+			recommendations = append(recommendations, recommendation)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	s.writeJSON(w, http.StatusOK, recommendations)
+}
+
+func (s *Server) listPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	policies := &v1alpha1.PolicyList{}
+	if err := s.k8sClient.List(r.Context(), policies); err != nil {
+		s.logger.Error(err, "Error listing the policies")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fleetPolicies := make([]FleetPolicy, 0, len(policies.Items))
+	for _, policy := range policies.Items {
+		fleetPolicies = append(fleetPolicies, FleetPolicy{Name: policy.Name, Spec: policy.Spec})
+	}
+	s.writeJSON(w, http.StatusOK, fleetPolicies)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Error(err, "Error writing the response")
+	}
+}
@@ -0,0 +1,165 @@
+package fleet
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/reco"
+)
+
+// Modes of an ottoscalr instance in a fleet.
+const (
+	ModeAgent   = "agent"
+	ModeCentral = "central"
+)
+
+// RecommendRequest is pushed by an agent to the central recommender for the recommendation of a workload of its
+// cluster. The datapoints are sent as columns of unix milliseconds and values to keep the requests small.
+type RecommendRequest struct {
+	Cluster            string    `json:"cluster"`
+	Namespace          string    `json:"namespace"`
+	Kind               string    `json:"kind"`
+	APIVersion         string    `json:"apiVersion,omitempty"`
+	Name               string    `json:"name"`
+	MetricsWindowStart time.Time `json:"metricsWindowStart"`
+	MetricsWindowEnd   time.Time `json:"metricsWindowEnd"`
+	Timestamps         []int64   `json:"timestamps"`
+	Values             []float64 `json:"values"`
+	MetricStepSeconds  float64   `json:"metricStepSeconds,omitempty"`
+	ACLSeconds         float64   `json:"aclSeconds"`
+	PerPodResources    float64   `json:"perPodResources"`
+	MaxReplicas        int       `json:"maxReplicas"`
+	// MaxReplicasFromCurrent is true if the max replicas fell back to the current replicas of the workload.
+	MaxReplicasFromCurrent bool `json:"maxReplicasFromCurrent,omitempty"`
+	// Labels, Annotations and CreatedAt are the metadata of the workload the per workload features of the recommender
+	// read, e.g. its downtime windows or its redline tier.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt,omitempty"`
+	// BreachAssertionTimestamps are the unix milliseconds the breach assertion of the workload held at, evaluated by
+	// the agent, and PodTimestamps and PodCounts the pods of the workload, if it has a breach assertion.
+	BreachAssertionTimestamps []int64   `json:"breachAssertionTimestamps,omitempty"`
+	PodTimestamps             []int64   `json:"podTimestamps,omitempty"`
+	PodCounts                 []float64 `json:"podCounts,omitempty"`
+	// Config is the OttoscalrConfig of the namespace of the workload in the agent's cluster.
+	Config *reco.WorkloadConfig `json:"config,omitempty"`
+}
+
+func NewRecommendRequest(cluster string, wm reco.WorkloadMeta, summary *reco.WorkloadSummary) RecommendRequest {
+	request := RecommendRequest{
+		Cluster:            cluster,
+		Namespace:          wm.Namespace,
+		Kind:               wm.Kind,
+		APIVersion:         wm.APIVersion,
+		Name:               wm.Name,
+		MetricsWindowStart: summary.MetricsWindowStart,
+		MetricsWindowEnd:   summary.MetricsWindowEnd,
+		Timestamps:         make([]int64, len(summary.DataPoints)),
+		Values:             make([]float64, len(summary.DataPoints)),
+		MetricStepSeconds:  summary.MetricStep.Seconds(),
+		ACLSeconds:         summary.ACL.Seconds(),
+		PerPodResources:    summary.PerPodResources,
+		MaxReplicas:        summary.MaxReplicas,
+
+		MaxReplicasFromCurrent: summary.MaxReplicasFromCurrent,
+		Labels:                 summary.Labels,
+		Annotations:            summary.Annotations,
+		CreatedAt:              summary.CreatedAt,
+		Config:                 summary.Config,
+	}
+	request.Timestamps, request.Values = columns(summary.DataPoints)
+	request.PodTimestamps, request.PodCounts = columns(summary.Pods)
+	for _, violation := range summary.BreachAssertionViolations {
+		request.BreachAssertionTimestamps = append(request.BreachAssertionTimestamps, violation.UnixMilli())
+	}
+	return request
+}
+
+// columns returns the unix milliseconds and the values of the datapoints.
+func columns(dataPoints []metrics.DataPoint) ([]int64, []float64) {
+	if dataPoints == nil {
+		return nil, nil
+	}
+	timestamps, values := make([]int64, len(dataPoints)), make([]float64, len(dataPoints))
+	for i, dp := range dataPoints {
+		timestamps[i] = dp.Timestamp.UnixMilli()
+		values[i] = dp.Value
+	}
+	return timestamps, values
+}
+
+// dataPoints returns the datapoints of the columns of unix milliseconds and values.
+func dataPoints(timestamps []int64, values []float64) []metrics.DataPoint {
+	if timestamps == nil {
+		return nil
+	}
+	dataPoints := make([]metrics.DataPoint, len(timestamps))
+	for i := range timestamps {
+		dataPoints[i] = metrics.DataPoint{Timestamp: time.UnixMilli(timestamps[i]), Value: values[i]}
+	}
+	return dataPoints
+}
+
+// Summary returns the workload summary sent in the request.
+func (r RecommendRequest) Summary() *reco.WorkloadSummary {
+	summary := &reco.WorkloadSummary{
+		MetricsWindowStart:     r.MetricsWindowStart,
+		MetricsWindowEnd:       r.MetricsWindowEnd,
+		MetricStep:             time.Duration(r.MetricStepSeconds * float64(time.Second)),
+		DataPoints:             dataPoints(r.Timestamps, r.Values),
+		ACL:                    time.Duration(r.ACLSeconds * float64(time.Second)),
+		PerPodResources:        r.PerPodResources,
+		MaxReplicas:            r.MaxReplicas,
+		MaxReplicasFromCurrent: r.MaxReplicasFromCurrent,
+		Labels:                 r.Labels,
+		Annotations:            r.Annotations,
+		CreatedAt:              r.CreatedAt,
+		Pods:                   dataPoints(r.PodTimestamps, r.PodCounts),
+		Config:                 r.Config,
+	}
+	for _, violation := range r.BreachAssertionTimestamps {
+		summary.BreachAssertionViolations = append(summary.BreachAssertionViolations, time.UnixMilli(violation))
+	}
+	return summary
+}
+
+// valid returns true if the columns of the datapoints of the request line up.
+func (r RecommendRequest) valid() bool {
+	return len(r.Timestamps) == len(r.Values) && len(r.PodTimestamps) == len(r.PodCounts) &&
+		r.MetricsWindowEnd.After(r.MetricsWindowStart)
+}
+
+type RecommendResponse struct {
+	Recommendation            *v1alpha1.HPAConfiguration     `json:"recommendation"`
+	MetricsWindowStart        time.Time                      `json:"metricsWindowStart"`
+	MetricsWindowEnd          time.Time                      `json:"metricsWindowEnd"`
+	DataPointsCoveragePercent int                            `json:"dataPointsCoveragePercent"`
+	ProjectedSavingsPercent   int                            `json:"projectedSavingsPercent"`
+	TransformersApplied       []string                       `json:"transformersApplied,omitempty"`
+	TransformerAudits         []reco.TransformerAudit        `json:"transformerAudits,omitempty"`
+	CronTriggers              []v1alpha1.CronTrigger         `json:"cronTriggers,omitempty"`
+	IdleWindow                *v1alpha1.IdleWindow           `json:"idleWindow,omitempty"`
+	InsufficientHistory       bool                           `json:"insufficientHistory,omitempty"`
+	WorkloadAge               time.Duration                  `json:"workloadAge,omitempty"`
+	NoOp                      *reco.NoOpRecommendation       `json:"noOp,omitempty"`
+	Confidence                *reco.RecommendationConfidence `json:"confidence,omitempty"`
+	Resize                    *reco.ResizeSignal             `json:"resize,omitempty"`
+}
+
+// FleetRecommendation is the last recommendation generated by the central recommender for a workload of the fleet.
+type FleetRecommendation struct {
+	Cluster                 string                     `json:"cluster"`
+	Namespace               string                     `json:"namespace"`
+	Kind                    string                     `json:"kind"`
+	Name                    string                     `json:"name"`
+	Recommendation          *v1alpha1.HPAConfiguration `json:"recommendation"`
+	ProjectedSavingsPercent int                        `json:"projectedSavingsPercent"`
+	GeneratedAt             time.Time                  `json:"generatedAt"`
+}
+
+// FleetPolicy is a policy of the central recommender distributed to the clusters of the fleet.
+type FleetPolicy struct {
+	Name string              `json:"name"`
+	Spec v1alpha1.PolicySpec `json:"spec"`
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Names of the subsystems checked by the probes of the manager.
+const (
+	MetricsBackend     = "metrics-backend"
+	PolicyStore        = "policy-store"
+	EnforcementBackend = "enforcement-backend"
+)
+
+var (
+	subsystemHealthyGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "subsystem_healthy",
+			Help: "Boolean to show if the subsystem passed its last health check"}, []string{"subsystem"},
+	)
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(subsystemHealthyGauge)
+}
+
+// Check checks the health of a subsystem, returning an error if it's unhealthy.
+type Check func(ctx context.Context) error
+
+// SubsystemChecker runs the check of a subsystem at most once every interval and answers the probes in between with
+// its last result, so that the probes neither load the subsystem nor time out waiting for it.
+type SubsystemChecker struct {
+	name     string
+	check    Check
+	interval time.Duration
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+func NewSubsystemChecker(name string, check Check, interval time.Duration) *SubsystemChecker {
+	return &SubsystemChecker{name: name, check: check, interval: interval}
+}
+
+// Check runs the check of the subsystem unless its last result is recent enough, and returns its result. Its
+// signature is the one of a healthz.Checker.
+func (c *SubsystemChecker) Check(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.interval {
+		return c.err
+	}
+	c.err, c.checkedAt = nil, time.Now()
+	if err := c.check(req.Context()); err != nil {
+		c.err = fmt.Errorf("%s is unhealthy: %w", c.name, err)
+		subsystemHealthyGauge.WithLabelValues(c.name).Set(0)
+		return c.err
+	}
+	subsystemHealthyGauge.WithLabelValues(c.name).Set(1)
+	return nil
+}
+
+// MetricsBackendCheck checks that the metrics backend of the scraper answers the queries. The scrapers which can't
+// check their backend are always healthy.
+func MetricsBackendCheck(scraper metrics.Scraper) Check {
+	return func(ctx context.Context) error {
+		if checker, ok := scraper.(metrics.HealthChecker); ok {
+			return checker.CheckHealth(ctx)
+		}
+		return nil
+	}
+}
+
+// PolicyStoreCheck checks that the policy store has synced the policies, which the recommendations can't be generated
+// without.
+func PolicyStoreCheck(store policy.Store) Check {
+	return func(ctx context.Context) error {
+		_, err := store.GetDefaultPolicy()
+		return err
+	}
+}
+
+// EnforcementBackendCheck checks that the autoscalers of the type of the object can be created and updated across
+// the namespaces.
+func EnforcementBackendCheck(k8sClient client.Client, object client.Object) Check {
+	return func(ctx context.Context) error {
+		gvk, err := apiutil.GVKForObject(object, k8sClient.Scheme())
+		if err != nil {
+			return err
+		}
+		mapping, err := k8sClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+		for _, verb := range []string{"create", "update"} {
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Verb:     verb,
+						Group:    mapping.Resource.Group,
+						Version:  mapping.Resource.Version,
+						Resource: mapping.Resource.Resource,
+					},
+				},
+			}
+			if err := k8sClient.Create(ctx, review); err != nil {
+				return err
+			}
+			if !review.Status.Allowed {
+				return fmt.Errorf("not allowed to %s the %s: %s", verb, mapping.Resource.Resource, review.Status.Reason)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Subsystem health", func() {
+	It("should answer the probes with the last result of the check within the interval", func() {
+		checks := 0
+		var checkErr error
+		checker := NewSubsystemChecker(MetricsBackend, func(ctx context.Context) error {
+			checks++
+			return checkErr
+		}, time.Hour)
+		req := httptest.NewRequest("GET", "/readyz", nil)
+
+		Expect(checker.Check(req)).To(Succeed())
+		checkErr = errors.New("connection refused")
+		Expect(checker.Check(req)).To(Succeed())
+		Expect(checks).To(Equal(1))
+
+		checker.interval = 0
+		err := checker.Check(req)
+		Expect(err).To(MatchError(ContainSubstring("metrics-backend is unhealthy: connection refused")))
+		Expect(checks).To(Equal(2))
+	})
+
+	It("should check that the policy store has synced the default policy", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		newStore := func(objects ...client.Object) policy.Store {
+			return policy.NewPolicyStore(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build())
+		}
+
+		Expect(PolicyStoreCheck(newStore())(context.TODO())).NotTo(Succeed())
+		Expect(PolicyStoreCheck(newStore(&v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: v1alpha1.PolicySpec{IsDefault: true}}))(context.TODO())).To(Succeed())
+	})
+
+	It("should consider the scrapers which can't check their backend healthy", func() {
+		Expect(MetricsBackendCheck(nil)(context.TODO())).To(Succeed())
+	})
+})
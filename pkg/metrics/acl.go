@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+// Strategies of aggregating the pod ready latencies of a workload into its ACL.
+const (
+	ACLStrategyMedian = "p50"
+	ACLStrategyMean   = "mean"
+	ACLStrategyP90    = "p90"
+	ACLStrategyP99    = "p99"
+)
+
+// ACLScraper scrapes the ACL of the workloads aggregated by a strategy, e.g. the p99 of the pod ready latencies for
+// the workloads whose slow pods matter more than the typical ones.
+type ACLScraper interface {
+	// GetACLByWorkloadWithStrategy returns the ACL of the workload with the pod ready latencies aggregated by the
+	// strategy.
+	GetACLByWorkloadWithStrategy(namespace, workload, strategy string) (time.Duration, error)
+}
+
+// ValidateACLStrategy returns an error if the strategy isn't one of the ACL strategies.
+func ValidateACLStrategy(strategy string) error {
+	_, err := aclAggregation(strategy)
+	return err
+}
+
+// aclAggregation returns the promql aggregation of the pod ready latencies of the strategy, opening the parentheses
+// of the aggregated expression.
+func aclAggregation(strategy string) (string, error) {
+	switch strategy {
+	case ACLStrategyMedian:
+		return "quantile(0.5,", nil
+	case ACLStrategyMean:
+		return "avg(", nil
+	case ACLStrategyP90:
+		return "quantile(0.9,", nil
+	case ACLStrategyP99:
+		return "quantile(0.99,", nil
+	default:
+		return "", fmt.Errorf("unknown ACL strategy %q, expected %s, %s, %s or %s", strategy, ACLStrategyMedian,
+			ACLStrategyMean, ACLStrategyP90, ACLStrategyP99)
+	}
+}
+
+func (ps *PrometheusScraper) GetACLByWorkloadWithStrategy(namespace, workload, strategy string) (time.Duration, error) {
+	aggregation, err := aclAggregation(strategy)
+	if err != nil {
+		return 0, err
+	}
+	podBootStrapTime, err := ps.getPodReadyLatencyByWorkload(namespace, workload, aggregation)
+	if err != nil {
+		return 0.0, fmt.Errorf("error getting pod bootstrap time: %v", err)
+	}
+	totalACL := ps.metricIngestionTime + ps.metricProbeTime + podBootStrapTime
+	return time.Duration(totalACL) * time.Second, nil
+}
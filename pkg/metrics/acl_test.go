@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ACL strategies", func() {
+	It("should aggregate the pod ready latencies by the strategy", func() {
+		for strategy, aggregation := range map[string]string{
+			ACLStrategyMedian: "quantile(0.5,",
+			ACLStrategyMean:   "avg(",
+			ACLStrategyP90:    "quantile(0.9,",
+			ACLStrategyP99:    "quantile(0.99,",
+		} {
+			Expect(aclAggregation(strategy)).To(Equal(aggregation))
+		}
+		Expect(ValidateACLStrategy("p75")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const BreachAssertionDataPointsQuery = "breachAssertionDataPointsQuery"
+
+// BreachAssertionScraper evaluates the PromQL assertions defining the breaches of the workloads, e.g.
+// `histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket{app="checkout"}[5m])) by (le)) > 0.3`, along
+// with the pods the workloads ran at.
+type BreachAssertionScraper interface {
+	// GetBreachAssertionTimestamps returns the timestamps at which the assertion holds, i.e. at which it returns any
+	// sample, in order.
+	GetBreachAssertionTimestamps(assertion string, start, end time.Time, step time.Duration) ([]time.Time, error)
+	// GetPodCountByWorkload returns the number of pods of the workload.
+	GetPodCountByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+}
+
+func (ps *PrometheusScraper) GetBreachAssertionTimestamps(assertion string, start, end time.Time,
+	step time.Duration) ([]time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ps.queryTimeout)
+	defer cancel()
+
+	if ps.api == nil {
+		return nil, fmt.Errorf("no apiurl for executing prometheus query")
+	}
+	resultChan := make(chan model.Matrix, len(ps.api))
+	var wg sync.WaitGroup
+	for _, pi := range ps.api {
+		wg.Add(1)
+		go func(pi PrometheusInstance) {
+			defer wg.Done()
+			p8sQueryStartTime := time.Now()
+			result, err := ps.rangeQuerySplitter.QueryRangeByInterval(ctx, pi, assertion, start, end, step)
+			if err != nil {
+				ps.logger.Error(err, "failed to execute Prometheus query", "Instance", pi.address)
+				logP8sMetrics(p8sQueryStartTime, "", BreachAssertionDataPointsQuery, pi.address, "", -1, 0)
+				resultChan <- nil
+				return
+			}
+			matrix, ok := result.(model.Matrix)
+			if !ok {
+				logP8sMetrics(p8sQueryStartTime, "", BreachAssertionDataPointsQuery, pi.address, "", 0, 0)
+				resultChan <- nil
+				return
+			}
+			dataPointsLength := 0
+			for _, stream := range matrix {
+				dataPointsLength += len(stream.Values)
+			}
+			logP8sMetrics(p8sQueryStartTime, "", BreachAssertionDataPointsQuery, pi.address, "", dataPointsLength, 1)
+			// an assertion which never holds returns no series, which isn't a failure
+			if matrix == nil {
+				matrix = model.Matrix{}
+			}
+			resultChan <- matrix
+		}(pi)
+	}
+	wg.Wait()
+	close(resultChan)
+
+	// the assertion holds at a timestamp if it does on any of the instances, for any of its series
+	queried := false
+	asserted := map[time.Time]bool{}
+	for matrix := range resultChan {
+		if matrix == nil {
+			continue
+		}
+		queried = true
+		for _, stream := range matrix {
+			for _, sample := range stream.Values {
+				if !sample.Timestamp.Time().IsZero() {
+					asserted[sample.Timestamp.Time()] = true
+				}
+			}
+		}
+	}
+	if !queried {
+		return nil, fmt.Errorf("unable to evaluate the breach assertion %s on any of the prometheus instances", assertion)
+	}
+	timestamps := make([]time.Time, 0, len(asserted))
+	for timestamp := range asserted {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+	return timestamps, nil
+}
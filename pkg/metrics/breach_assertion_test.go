@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+var _ = Describe("Breach assertions", func() {
+	start := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Minute)
+
+	instance := func(matrix model.Matrix, err error) PrometheusInstance {
+		return PrometheusInstance{address: fmt.Sprintf("p8s-%d", len(matrix)), apiUrl: &mockAPI{
+			queryRangeFunc: func(ctx context.Context, query string, r v1.Range, options ...v1.Option) (model.Value,
+				v1.Warnings, error) {
+				if err != nil {
+					return nil, nil, err
+				}
+				return matrix, nil, nil
+			},
+		}}
+	}
+	stream := func(minutes ...int) *model.SampleStream {
+		s := &model.SampleStream{}
+		for _, m := range minutes {
+			s.Values = append(s.Values, model.SamplePair{Timestamp: model.TimeFromUnix(start.Add(time.Duration(m) * time.Minute).Unix()),
+				Value: 1})
+		}
+		return s
+	}
+	scraperOf := func(instances ...PrometheusInstance) *PrometheusScraper {
+		return &PrometheusScraper{api: instances, rangeQuerySplitter: NewRangeQuerySplitter(time.Hour),
+			queryTimeout: time.Second, logger: logr.Discard()}
+	}
+
+	It("should return the timestamps the assertion held at on any series or instance, in order", func() {
+		ps := scraperOf(instance(model.Matrix{stream(3, 1), stream(1, 5)}, nil), instance(model.Matrix{stream(7)}, nil))
+		timestamps, err := ps.GetBreachAssertionTimestamps("error_rate > 0.01", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		for i := range timestamps {
+			timestamps[i] = timestamps[i].UTC()
+		}
+		Expect(timestamps).To(Equal([]time.Time{start.Add(time.Minute), start.Add(3 * time.Minute),
+			start.Add(5 * time.Minute), start.Add(7 * time.Minute)}))
+	})
+
+	It("should tolerate the assertion never holding and the failures of some instances", func() {
+		ps := scraperOf(instance(model.Matrix{}, nil), instance(nil, fmt.Errorf("unavailable")))
+		timestamps, err := ps.GetBreachAssertionTimestamps("error_rate > 0.01", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timestamps).To(BeEmpty())
+	})
+
+	It("should fail if the assertion couldn't be evaluated on any instance", func() {
+		ps := scraperOf(instance(nil, fmt.Errorf("bad query")))
+		_, err := ps.GetBreachAssertionTimestamps("error_rate >", start, end, time.Minute)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// CardinalityConfig controls the cardinality of the metrics exported by ottoscalr.
+type CardinalityConfig struct {
+	// DisabledMetrics are the metrics which aren't exported at all.
+	DisabledMetrics []string
+	// AggregatedLabels are the labels which are dropped from the metrics. The series which only differ in these
+	// labels are aggregated into one: the counters, the histograms and the summaries are summed up, while the gauges
+	// keep the max as their sum is meaningless, e.g. for percentages.
+	AggregatedLabels []string
+	// AggregatedMetrics are the metrics the AggregatedLabels are dropped from. Empty means all the metrics.
+	AggregatedMetrics []string
+}
+
+// CardinalityLimitingRegistry wraps a registry and applies the CardinalityConfig to the metrics gathered from it.
+type CardinalityLimitingRegistry struct {
+	prometheus.Registerer
+	gatherer          prometheus.Gatherer
+	disabledMetrics   map[string]bool
+	aggregatedLabels  map[string]bool
+	aggregatedMetrics map[string]bool
+}
+
+type registererGatherer interface {
+	prometheus.Registerer
+	prometheus.Gatherer
+}
+
+func NewCardinalityLimitingRegistry(registry registererGatherer, config CardinalityConfig) *CardinalityLimitingRegistry {
+	return &CardinalityLimitingRegistry{
+		Registerer:        registry,
+		gatherer:          registry,
+		disabledMetrics:   toSet(config.DisabledMetrics),
+		aggregatedLabels:  toSet(config.AggregatedLabels),
+		aggregatedMetrics: toSet(config.AggregatedMetrics),
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+func (r *CardinalityLimitingRegistry) Gather() ([]*dto.MetricFamily, error) {
+	families, err := r.gatherer.Gather()
+	limited := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		if r.disabledMetrics[family.GetName()] {
+			continue
+		}
+		if len(r.aggregatedLabels) > 0 && (len(r.aggregatedMetrics) == 0 || r.aggregatedMetrics[family.GetName()]) {
+			family = aggregateMetricFamily(family, r.aggregatedLabels)
+		}
+		limited = append(limited, family)
+	}
+	return limited, err
+}
+
+// aggregateMetricFamily drops the labels from the metrics in the family and merges the metrics which end up
+// with the same label set. Summaries lose their quantiles as they can't be aggregated.
+func aggregateMetricFamily(family *dto.MetricFamily, droppedLabels map[string]bool) *dto.MetricFamily {
+	aggregated := &dto.MetricFamily{
+		Name: family.Name,
+		Help: family.Help,
+		Type: family.Type,
+	}
+	metricsBySignature := make(map[string]*dto.Metric)
+	var signatures []string
+	for _, metric := range family.Metric {
+		var labels []*dto.LabelPair
+		for _, label := range metric.Label {
+			if !droppedLabels[label.GetName()] {
+				labels = append(labels, label)
+			}
+		}
+		signature := labelsSignature(labels)
+		existing, ok := metricsBySignature[signature]
+		if !ok {
+			copied := proto.Clone(metric).(*dto.Metric)
+			copied.Label = labels
+			copied.TimestampMs = nil
+			if copied.Summary != nil {
+				copied.Summary.Quantile = nil
+			}
+			metricsBySignature[signature] = copied
+			signatures = append(signatures, signature)
+			continue
+		}
+		mergeMetric(existing, metric)
+	}
+	sort.Strings(signatures)
+	for _, signature := range signatures {
+		aggregated.Metric = append(aggregated.Metric, metricsBySignature[signature])
+	}
+	return aggregated
+}
+
+func labelsSignature(labels []*dto.LabelPair) string {
+	var sb strings.Builder
+	for _, label := range labels {
+		sb.WriteString(label.GetName())
+		sb.WriteByte('=')
+		sb.WriteString(label.GetValue())
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+// mergeMetric merges the metric into the one with the same label set. The cumulative metrics are summed up, while the
+// gauges and the untyped metrics, whose sum may not mean anything, keep the max.
+func mergeMetric(into, from *dto.Metric) {
+	switch {
+	case into.Counter != nil && from.Counter != nil:
+		into.Counter.Value = proto.Float64(into.Counter.GetValue() + from.Counter.GetValue())
+	case into.Gauge != nil && from.Gauge != nil:
+		into.Gauge.Value = proto.Float64(math.Max(into.Gauge.GetValue(), from.Gauge.GetValue()))
+	case into.Untyped != nil && from.Untyped != nil:
+		into.Untyped.Value = proto.Float64(math.Max(into.Untyped.GetValue(), from.Untyped.GetValue()))
+	case into.Summary != nil && from.Summary != nil:
+		into.Summary.SampleCount = proto.Uint64(into.Summary.GetSampleCount() + from.Summary.GetSampleCount())
+		into.Summary.SampleSum = proto.Float64(into.Summary.GetSampleSum() + from.Summary.GetSampleSum())
+	case into.Histogram != nil && from.Histogram != nil:
+		into.Histogram.SampleCount = proto.Uint64(into.Histogram.GetSampleCount() + from.Histogram.GetSampleCount())
+		into.Histogram.SampleSum = proto.Float64(into.Histogram.GetSampleSum() + from.Histogram.GetSampleSum())
+		cumulativeCounts := make(map[float64]uint64, len(from.Histogram.Bucket))
+		for _, bucket := range from.Histogram.Bucket {
+			cumulativeCounts[bucket.GetUpperBound()] = bucket.GetCumulativeCount()
+		}
+		for _, bucket := range into.Histogram.Bucket {
+			bucket.CumulativeCount = proto.Uint64(bucket.GetCumulativeCount() + cumulativeCounts[bucket.GetUpperBound()])
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("CardinalityLimitingRegistry", func() {
+	var (
+		registry  *prometheus.Registry
+		counter   *prometheus.CounterVec
+		histogram *prometheus.HistogramVec
+		gauge     *prometheus.GaugeVec
+		summary   *prometheus.SummaryVec
+	)
+
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		counter = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_counter", Help: "test"},
+			[]string{"namespace", "workload"})
+		histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_histogram", Help: "test",
+			Buckets: []float64{1, 5}}, []string{"namespace", "workload"})
+		gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_gauge", Help: "test"},
+			[]string{"namespace", "workload"})
+		summary = prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: "test_summary", Help: "test",
+			Objectives: map[float64]float64{0.5: 0.05}}, []string{"namespace", "workload"})
+		registry.MustRegister(counter, histogram, gauge, summary)
+
+		counter.WithLabelValues("ns1", "app1").Add(1)
+		counter.WithLabelValues("ns1", "app2").Add(2)
+		counter.WithLabelValues("ns2", "app3").Add(4)
+		histogram.WithLabelValues("ns1", "app1").Observe(0.5)
+		histogram.WithLabelValues("ns1", "app2").Observe(3)
+		gauge.WithLabelValues("ns1", "app1").Set(40)
+		gauge.WithLabelValues("ns1", "app2").Set(70)
+		summary.WithLabelValues("ns1", "app1").Observe(2)
+		summary.WithLabelValues("ns1", "app2").Observe(6)
+	})
+
+	findFamily := func(families []*dto.MetricFamily, name string) *dto.MetricFamily {
+		for _, family := range families {
+			if family.GetName() == name {
+				return family
+			}
+		}
+		return nil
+	}
+
+	It("should export the metrics as is without any config", func() {
+		families, err := NewCardinalityLimitingRegistry(registry, CardinalityConfig{}).Gather()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findFamily(families, "test_counter").Metric).To(HaveLen(3))
+		Expect(findFamily(families, "test_histogram").Metric).To(HaveLen(2))
+	})
+
+	It("should drop the disabled metrics", func() {
+		families, err := NewCardinalityLimitingRegistry(registry, CardinalityConfig{
+			DisabledMetrics: []string{"test_histogram"},
+		}).Gather()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findFamily(families, "test_histogram")).To(BeNil())
+		Expect(findFamily(families, "test_counter")).NotTo(BeNil())
+	})
+
+	It("should aggregate the series over the dropped labels", func() {
+		families, err := NewCardinalityLimitingRegistry(registry, CardinalityConfig{
+			AggregatedLabels: []string{"workload"},
+		}).Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		counters := findFamily(families, "test_counter").Metric
+		Expect(counters).To(HaveLen(2))
+		Expect(counters[0].Label).To(HaveLen(1))
+		Expect(counters[0].Label[0].GetValue()).To(Equal("ns1"))
+		Expect(counters[0].Counter.GetValue()).To(Equal(3.0))
+		Expect(counters[1].Counter.GetValue()).To(Equal(4.0))
+
+		histograms := findFamily(families, "test_histogram").Metric
+		Expect(histograms).To(HaveLen(1))
+		Expect(histograms[0].Histogram.GetSampleCount()).To(Equal(uint64(2)))
+		Expect(histograms[0].Histogram.GetSampleSum()).To(Equal(3.5))
+		Expect(histograms[0].Histogram.Bucket[0].GetCumulativeCount()).To(Equal(uint64(1)))
+		Expect(histograms[0].Histogram.Bucket[1].GetCumulativeCount()).To(Equal(uint64(2)))
+	})
+
+	It("should keep the max of the gauges rather than summing them up", func() {
+		families, err := NewCardinalityLimitingRegistry(registry, CardinalityConfig{
+			AggregatedLabels: []string{"workload"},
+		}).Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		gauges := findFamily(families, "test_gauge").Metric
+		Expect(gauges).To(HaveLen(1))
+		Expect(gauges[0].Gauge.GetValue()).To(Equal(70.0))
+	})
+
+	It("should sum up the counts of the summaries and drop their quantiles", func() {
+		families, err := NewCardinalityLimitingRegistry(registry, CardinalityConfig{
+			AggregatedLabels: []string{"workload"},
+		}).Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		summaries := findFamily(families, "test_summary").Metric
+		Expect(summaries).To(HaveLen(1))
+		Expect(summaries[0].Summary.GetSampleCount()).To(Equal(uint64(2)))
+		Expect(summaries[0].Summary.GetSampleSum()).To(Equal(8.0))
+		Expect(summaries[0].Summary.Quantile).To(BeEmpty())
+	})
+
+	It("should aggregate only the configured metrics", func() {
+		families, err := NewCardinalityLimitingRegistry(registry, CardinalityConfig{
+			AggregatedLabels:  []string{"workload"},
+			AggregatedMetrics: []string{"test_histogram"},
+		}).Gather()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findFamily(families, "test_counter").Metric).To(HaveLen(3))
+		Expect(findFamily(families, "test_histogram").Metric).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const ContainerDataPointsQuery = "containerDataPointsQuery"
+
+// ContainerScraper scrapes the cpu utilization of the containers of the workloads.
+type ContainerScraper interface {
+	// GetCPUUtilizationByContainer returns the cpu usage of every container summed across the pods of the workload
+	// relative to the cpu limits of the container, i.e. the number of the containers it would keep fully utilized, by
+	// the name of the container.
+	GetCPUUtilizationByContainer(namespace, workload string, start, end time.Time,
+		step time.Duration) (map[string][]DataPoint, error)
+}
+
+func (ps *PrometheusScraper) GetCPUUtilizationByContainer(namespace, workload string, start, end time.Time,
+	step time.Duration) (map[string][]DataPoint, error) {
+	query := fmt.Sprintf("sum(%s{namespace=\"%s\"} * on (namespace,pod) group_left(workload, workload_type)"+
+		"%s{namespace=\"%s\", workload=\"%s\", workload_type=\"deployment\"}) by(container) / "+
+		"avg(%s{namespace=\"%s\"} * on (namespace,pod) group_left(workload, workload_type)"+
+		"%s{namespace=\"%s\", workload=\"%s\", workload_type=\"deployment\"}) by(container)",
+		ps.metricRegistry.utilizationMetric, namespace, ps.metricRegistry.podOwnerMetric, namespace, workload,
+		ps.metricRegistry.resourceLimitMetric, namespace, ps.metricRegistry.podOwnerMetric, namespace, workload)
+	return ps.getRangeDataPointsByLabel(query, ContainerDataPointsQuery, workload, "container", start, end, step)
+}
+
+// getRangeDataPointsByLabel runs the range query on all the prometheus instances and merges the datapoints they return
+// for every value of the label.
+func (ps *PrometheusScraper) getRangeDataPointsByLabel(query, queryType, subject string, label model.LabelName,
+	start, end time.Time, step time.Duration) (map[string][]DataPoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ps.queryTimeout)
+	defer cancel()
+
+	if ps.api == nil {
+		return nil, fmt.Errorf("no apiurl for executing prometheus query")
+	}
+	resultChan := make(chan model.Matrix, len(ps.api))
+	var wg sync.WaitGroup
+	for _, pi := range ps.api {
+		wg.Add(1)
+		go func(pi PrometheusInstance) {
+			defer wg.Done()
+			p8sQueryStartTime := time.Now()
+			result, err := ps.rangeQuerySplitter.QueryRangeByInterval(ctx, pi, query, start, end, step)
+			if err != nil {
+				ps.logger.Error(err, "failed to execute Prometheus query", "Instance", pi.address)
+				logP8sMetrics(p8sQueryStartTime, "", queryType, pi.address, subject, -1, 0)
+				resultChan <- nil
+				return
+			}
+			matrix, ok := result.(model.Matrix)
+			if !ok {
+				logP8sMetrics(p8sQueryStartTime, "", queryType, pi.address, subject, 0, 1)
+				resultChan <- nil
+				return
+			}
+			dataPointsLength := 0
+			for _, stream := range matrix {
+				dataPointsLength += len(stream.Values)
+			}
+			logP8sMetrics(p8sQueryStartTime, "", queryType, pi.address, subject, dataPointsLength, 1)
+			resultChan <- matrix
+		}(pi)
+	}
+	wg.Wait()
+	close(resultChan)
+
+	dataPointsByLabel := map[string][]DataPoint{}
+	for matrix := range resultChan {
+		for _, stream := range matrix {
+			dataPoints := make([]DataPoint, 0, len(stream.Values))
+			for _, sample := range stream.Values {
+				if !sample.Timestamp.Time().IsZero() {
+					dataPoints = append(dataPoints, DataPoint{sample.Timestamp.Time(), float64(sample.Value)})
+				}
+			}
+			sort.SliceStable(dataPoints, func(i, j int) bool {
+				return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
+			})
+			value := string(stream.Metric[label])
+			dataPointsByLabel[value] = aggregateMetrics(dataPointsByLabel[value], dataPoints)
+		}
+	}
+	if len(dataPointsByLabel) == 0 {
+		return nil, fmt.Errorf("unable to get the datapoints of %s from any of the prometheus instances", query)
+	}
+	return dataPointsByLabel, nil
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import "time"
+
+// DataPointColumns is a compact columnar representation of datapoints meant for holding them for long, e.g. across
+// recommendation runs. A datapoint takes 12 bytes instead of the 32 bytes of a DataPoint as the timestamps are kept
+// as unix milliseconds (the resolution of prometheus samples) and the values as float32.
+type DataPointColumns struct {
+	timestamps []int64
+	values     []float32
+}
+
+func NewDataPointColumns(dataPoints []DataPoint) *DataPointColumns {
+	columns := &DataPointColumns{}
+	columns.Set(dataPoints)
+	return columns
+}
+
+// Set replaces the datapoints held, reusing the allocated columns when they have enough capacity.
+func (dc *DataPointColumns) Set(dataPoints []DataPoint) {
+	if cap(dc.timestamps) < len(dataPoints) {
+		dc.timestamps = make([]int64, len(dataPoints))
+		dc.values = make([]float32, len(dataPoints))
+	}
+	dc.timestamps = dc.timestamps[:len(dataPoints)]
+	dc.values = dc.values[:len(dataPoints)]
+	for i, dp := range dataPoints {
+		dc.timestamps[i] = dp.Timestamp.UnixMilli()
+		dc.values[i] = float32(dp.Value)
+	}
+}
+
+func (dc *DataPointColumns) Len() int {
+	return len(dc.timestamps)
+}
+
+func (dc *DataPointColumns) Timestamp(i int) time.Time {
+	return time.UnixMilli(dc.timestamps[i])
+}
+
+// Search returns the index of the first datapoint at or after t, or Len() if there's none.
+func (dc *DataPointColumns) Search(t time.Time) int {
+	millis := t.UnixMilli()
+	low, high := 0, len(dc.timestamps)
+	for low < high {
+		mid := low + (high-low)/2
+		if dc.timestamps[mid] < millis {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+// AppendTo appends the datapoints from index from onwards to dst and returns the extended slice. Callers can size
+// dst to also fit the datapoints they are going to append afterwards.
+func (dc *DataPointColumns) AppendTo(dst []DataPoint, from int) []DataPoint {
+	for i := from; i < len(dc.timestamps); i++ {
+		dst = append(dst, DataPoint{Timestamp: time.UnixMilli(dc.timestamps[i]), Value: float64(dc.values[i])})
+	}
+	return dst
+}
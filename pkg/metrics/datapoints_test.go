@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DataPointColumns", func() {
+	It("should round trip the datapoints", func() {
+		start := time.Now().Truncate(time.Minute)
+		dataPoints := []DataPoint{
+			{Timestamp: start, Value: 1.5},
+			{Timestamp: start.Add(time.Minute), Value: 2.25},
+			{Timestamp: start.Add(2 * time.Minute), Value: 40},
+		}
+		columns := NewDataPointColumns(dataPoints)
+		Expect(columns.Len()).To(Equal(3))
+		Expect(columns.AppendTo(nil, 0)).To(Equal(dataPoints))
+		Expect(columns.AppendTo(nil, 1)).To(Equal(dataPoints[1:]))
+
+		Expect(columns.Search(start.Add(-time.Minute))).To(Equal(0))
+		Expect(columns.Search(start.Add(30 * time.Second))).To(Equal(1))
+		Expect(columns.Search(start.Add(3 * time.Minute))).To(Equal(3))
+
+		columns.Set(dataPoints[:1])
+		Expect(columns.Len()).To(Equal(1))
+		Expect(columns.Timestamp(0)).To(Equal(start))
+	})
+})
+
+func BenchmarkDataPointColumns(b *testing.B) {
+	step := 30 * time.Second
+	dataPoints := make([]DataPoint, 30*24*int(time.Hour/step))
+	start := time.Now().Truncate(step)
+	for i := range dataPoints {
+		dataPoints[i] = DataPoint{Timestamp: start.Add(time.Duration(i) * step), Value: float64(i % 100)}
+	}
+
+	b.Run("slice copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			copied := make([]DataPoint, len(dataPoints))
+			copy(copied, dataPoints)
+		}
+	})
+
+	b.Run("columns", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = NewDataPointColumns(dataPoints)
+		}
+	})
+}
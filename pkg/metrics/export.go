@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportedDataPoint is a datapoint of an exported datapoint file.
+type exportedDataPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// IsCSV returns true if the datapoint file is a CSV file rather than a JSON one, going by its extension.
+func IsCSV(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".csv")
+}
+
+// ReadDataPoints reads the datapoints exported as a JSON array of {"timestamp", "value"} objects or as CSV rows of
+// timestamp,value with an optional header. The timestamps are RFC3339 or unix seconds. The datapoints are returned
+// sorted by their timestamps.
+func ReadDataPoints(r io.Reader, isCSV bool) ([]DataPoint, error) {
+	var dataPoints []DataPoint
+	if isCSV {
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for i, record := range records {
+			if len(record) != 2 {
+				return nil, fmt.Errorf("line %d: expected timestamp,value", i+1)
+			}
+			timestamp, err := parseTimestamp(record[0])
+			if err != nil {
+				if i == 0 {
+					continue
+				}
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			dataPoints = append(dataPoints, DataPoint{Timestamp: timestamp, Value: value})
+		}
+	} else {
+		var exported []exportedDataPoint
+		if err := json.NewDecoder(r).Decode(&exported); err != nil {
+			return nil, err
+		}
+		dataPoints = make([]DataPoint, 0, len(exported))
+		for _, dp := range exported {
+			dataPoints = append(dataPoints, DataPoint{Timestamp: dp.Timestamp, Value: dp.Value})
+		}
+	}
+	sort.SliceStable(dataPoints, func(i, j int) bool {
+		return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
+	})
+	return dataPoints, nil
+}
+
+// WriteDataPoints exports the datapoints in the format read by ReadDataPoints.
+func WriteDataPoints(w io.Writer, dataPoints []DataPoint, isCSV bool) error {
+	if isCSV {
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"timestamp", "value"}); err != nil {
+			return err
+		}
+		for _, dp := range dataPoints {
+			if err := writer.Write([]string{dp.Timestamp.UTC().Format(time.RFC3339),
+				strconv.FormatFloat(dp.Value, 'f', -1, 64)}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	exported := make([]exportedDataPoint, 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		exported = append(exported, exportedDataPoint{Timestamp: dp.Timestamp.UTC(), Value: dp.Value})
+	}
+	return json.NewEncoder(w).Encode(exported)
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(seconds * 1000)), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
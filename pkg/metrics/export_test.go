@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exported datapoints", func() {
+	var dataPoints []DataPoint
+
+	BeforeEach(func() {
+		start := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+		dataPoints = nil
+		for i := 0; i < 10; i++ {
+			dataPoints = append(dataPoints, DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: float64(i) + 0.5})
+		}
+	})
+
+	It("should round trip the exported datapoints", func() {
+		for _, isCSV := range []bool{true, false} {
+			buf := &bytes.Buffer{}
+			Expect(WriteDataPoints(buf, dataPoints, isCSV)).To(Succeed())
+			read, err := ReadDataPoints(buf, isCSV)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read).To(Equal(dataPoints))
+		}
+	})
+
+	It("should read the unix timestamps and sort the datapoints", func() {
+		read, err := ReadDataPoints(strings.NewReader("1685613660,2.5\n1685613600,1.5\n"), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read).To(Equal([]DataPoint{
+			{Timestamp: time.Unix(1685613600, 0), Value: 1.5},
+			{Timestamp: time.Unix(1685613660, 0), Value: 2.5},
+		}))
+	})
+})
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HealthChecker checks whether the metrics backend of a scraper can be queried.
+type HealthChecker interface {
+	// CheckHealth returns an error if none of the Prometheus instances answer a query.
+	CheckHealth(ctx context.Context) error
+}
+
+func (ps *PrometheusScraper) CheckHealth(ctx context.Context) error {
+	if ps.api == nil {
+		return fmt.Errorf("no apiurl for executing prometheus query")
+	}
+	ctx, cancel := context.WithTimeout(ctx, ps.queryTimeout)
+	defer cancel()
+
+	var lastErr error
+	for _, pi := range ps.api {
+		if _, _, err := pi.apiUrl.Query(ctx, "vector(1)", time.Now()); err != nil {
+			lastErr = fmt.Errorf("prometheus instance %s is unreachable: %w", pi.address, err)
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scraper health", func() {
+	It("should be healthy while any of the prometheus instances answers the queries", func() {
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{},"value":[1700000000,"1"]}]}}`))
+		}))
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+
+		scraper, err := NewPrometheusScraper([]string{down.URL, prometheus.URL}, time.Minute, time.Hour, 15, 15,
+			logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewReloadableScraper(scraper).CheckHealth(context.TODO())).To(Succeed())
+
+		prometheus.Close()
+		Expect(scraper.CheckHealth(context.TODO())).To(MatchError(ContainSubstring("is unreachable")))
+	})
+})
@@ -2,7 +2,25 @@ package metrics
 
 import "time"
 
+// MetricsTransformer transforms the datapoints of a window. Transform may modify dataPoints in place and the
+// datapoints returned may share its backing array, so callers must not use dataPoints afterwards.
 type MetricsTransformer interface {
 	Transform(
 		startTime time.Time, endTime time.Time, dataPoints []DataPoint) ([]DataPoint, error)
 }
+
+// ExcludedInterval is an interval of a window a transformer excluded from the datapoints, along with the reason it was
+// excluded for, e.g. the names of the events of the interval.
+type ExcludedInterval struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// IntervalExcludingTransformer is implemented by the transformers which exclude intervals of the window from the
+// datapoints, to report the intervals they excluded along with the transformed datapoints.
+type IntervalExcludingTransformer interface {
+	MetricsTransformer
+	TransformExcluding(startTime time.Time, endTime time.Time,
+		dataPoints []DataPoint) ([]DataPoint, []ExcludedInterval, error)
+}
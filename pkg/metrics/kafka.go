@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	KafkaDataPointsQuery = "kafkaDataPointsQuery"
+
+	kafkaConsumerGroupLagMetric    = "kafka_consumergroup_lag"
+	kafkaConsumerGroupOffsetMetric = "kafka_consumergroup_current_offset"
+	kafkaTopicPartitionsMetric     = "kafka_topic_partitions"
+)
+
+// KafkaScraper scrapes the metrics of the kafka consumer groups, as exported by the kafka exporter, along with the
+// pods consuming them.
+type KafkaScraper interface {
+	// GetConsumerGroupLag returns the lag of the consumer group summed across the partitions of the topic.
+	GetConsumerGroupLag(consumerGroup, topic string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+	// GetConsumerGroupConsumeRate returns the messages consumed per second by the consumer group from the topic.
+	GetConsumerGroupConsumeRate(consumerGroup, topic string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+	// GetPodCountByWorkload returns the number of pods of the workload.
+	GetPodCountByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+	// GetTopicPartitions returns the number of partitions of the topic.
+	GetTopicPartitions(topic string) (int, error)
+}
+
+func (ps *PrometheusScraper) GetConsumerGroupLag(consumerGroup, topic string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	query := fmt.Sprintf("sum(%s{consumergroup=\"%s\", topic=\"%s\"})",
+		kafkaConsumerGroupLagMetric, consumerGroup, topic)
+	return ps.getRangeDataPoints(query, KafkaDataPointsQuery, consumerGroup, start, end, step)
+}
+
+func (ps *PrometheusScraper) GetConsumerGroupConsumeRate(consumerGroup, topic string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	// the rate needs at least two scrapes of the offsets within its window
+	window := time.Duration(math.Max(float64(step), float64(2*time.Minute)))
+	query := fmt.Sprintf("sum(rate(%s{consumergroup=\"%s\", topic=\"%s\"}[%s]))",
+		kafkaConsumerGroupOffsetMetric, consumerGroup, topic, model.Duration(window))
+	return ps.getRangeDataPoints(query, KafkaDataPointsQuery, consumerGroup, start, end, step)
+}
+
+func (ps *PrometheusScraper) GetPodCountByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	query := fmt.Sprintf("count(%s{namespace=\"%s\", workload=\"%s\", workload_type=\"deployment\"})",
+		ps.metricRegistry.podOwnerMetric, namespace, workload)
+	return ps.getRangeDataPoints(query, KafkaDataPointsQuery, workload, start, end, step)
+}
+
+func (ps *PrometheusScraper) GetTopicPartitions(topic string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ps.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("max(%s{topic=\"%s\"})", kafkaTopicPartitionsMetric, topic)
+	if ps.api == nil {
+		return 0, fmt.Errorf("no apiurl for executing prometheus query")
+	}
+	partitions := 0.0
+	for _, pi := range ps.api {
+		result, _, err := pi.apiUrl.Query(ctx, query, time.Now())
+		if err != nil {
+			ps.logger.Error(err, "failed to execute Prometheus query", "Instance", pi.address)
+			continue
+		}
+		if result.Type() != model.ValVector {
+			ps.logger.Error(fmt.Errorf("unexpected result type: %v", result.Type()), "Result Type Error", "Instance", pi.address)
+			continue
+		}
+		vector := result.(model.Vector)
+		if len(vector) != 1 {
+			continue
+		}
+		partitions = math.Max(partitions, float64(vector[0].Value))
+	}
+	if partitions == 0 {
+		return 0, fmt.Errorf("unable to get the partitions of the topic %s from any of the prometheus instances", topic)
+	}
+	return int(partitions), nil
+}
+
+// getRangeDataPoints runs the range query on all the prometheus instances and merges the datapoints they return.
+func (ps *PrometheusScraper) getRangeDataPoints(query, queryType, subject string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ps.queryTimeout)
+	defer cancel()
+
+	if ps.api == nil {
+		return nil, fmt.Errorf("no apiurl for executing prometheus query")
+	}
+	resultChan := make(chan []DataPoint, len(ps.api))
+	var wg sync.WaitGroup
+	for _, pi := range ps.api {
+		wg.Add(1)
+		go func(pi PrometheusInstance) {
+			defer wg.Done()
+			p8sQueryStartTime := time.Now()
+			result, err := ps.rangeQuerySplitter.QueryRangeByInterval(ctx, pi, query, start, end, step)
+			if err != nil {
+				ps.logger.Error(err, "failed to execute Prometheus query", "Instance", pi.address)
+				logP8sMetrics(p8sQueryStartTime, "", queryType, pi.address, subject, -1, 0)
+				resultChan <- nil
+				return
+			}
+			matrix, ok := result.(model.Matrix)
+			if !ok || len(matrix) != 1 {
+				logP8sMetrics(p8sQueryStartTime, "", queryType, pi.address, subject, 0, 1)
+				resultChan <- nil
+				return
+			}
+			dataPoints := make([]DataPoint, 0, len(matrix[0].Values))
+			for _, sample := range matrix[0].Values {
+				if !sample.Timestamp.Time().IsZero() {
+					dataPoints = append(dataPoints, DataPoint{sample.Timestamp.Time(), float64(sample.Value)})
+				}
+			}
+			logP8sMetrics(p8sQueryStartTime, "", queryType, pi.address, subject, len(dataPoints), 1)
+			sort.SliceStable(dataPoints, func(i, j int) bool {
+				return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
+			})
+			resultChan <- dataPoints
+		}(pi)
+	}
+	wg.Wait()
+	close(resultChan)
+
+	var totalDataPoints []DataPoint
+	for p8sQueryResult := range resultChan {
+		totalDataPoints = aggregateMetrics(totalDataPoints, p8sQueryResult)
+	}
+	if totalDataPoints == nil {
+		return nil, fmt.Errorf("unable to get the datapoints of %s from any of the prometheus instances", query)
+	}
+	return totalDataPoints, nil
+}
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// LabelSchemeAuto detects the label scheme of the metrics of the cluster among the DefaultLabelSchemes.
+const LabelSchemeAuto = "auto"
+
+// LabelScheme is a set of query templates for the labels and the series names a cluster's kube-state-metrics and
+// cadvisor expose, which applies when all its probes return series.
+type LabelScheme struct {
+	Name string
+	// Probes are the selectors of the series the templates are made of.
+	Probes []string
+	// Templates are the query templates of the scheme, the empty ones being the default queries.
+	Templates QueryTemplates
+}
+
+// ksmPodOwner selects the pods of the deployment from the owner series of the pods and of their replicasets, with the
+// workload and workload_type labels of the namespace_workload_pod:kube_pod_owner:relabel recording rule.
+const ksmPodOwner = `label_replace(max by (namespace, pod, workload) (label_replace(label_replace(kube_pod_owner{` +
+	`namespace="{{ .Namespace }}", owner_kind="ReplicaSet"}, "replicaset", "$1", "owner_name", "(.*)") ` +
+	`* on (namespace, replicaset) group_left(owner_name) topk by (namespace, replicaset) (1, ` +
+	`max by (namespace, replicaset, owner_name) ({{ .Metrics.ReplicaSetOwner }}{namespace="{{ .Namespace }}", ` +
+	`owner_kind="Deployment", owner_name="{{ .Workload }}"})), "workload", "$1", "owner_name", "(.*)")), ` +
+	`"workload_type", "deployment", "", "")`
+
+// cadvisorQueryTemplates returns the query templates of the cpu usage and limits by pod, summed up over the pods of
+// the workload like the default queries do over the recording rules.
+func cadvisorQueryTemplates(usage, limits string) QueryTemplates {
+	byWorkload := func(byPod string) string {
+		return `sum(` + byPod + ` * on (namespace, pod) group_left(workload, workload_type) ` + ksmPodOwner +
+			`) by (namespace, workload, workload_type)`
+	}
+	return QueryTemplates{
+		CPUUtilization:  byWorkload(usage),
+		CPULimitsPerPod: byWorkload(limits) + ` / count(` + ksmPodOwner + `) by (namespace, workload, workload_type)`,
+		PodReadyLatency: `{{ .Aggregation }}({{ .Metrics.PodReadyTime }}{namespace="{{ .Namespace }}"} - on ` +
+			`(namespace, pod) ({{ .Metrics.PodCreatedTime }}{namespace="{{ .Namespace }}"})) * on (namespace, pod) ` +
+			`group_left(workload, workload_type) ` + ksmPodOwner + `)`,
+		Breach: `(` + byWorkload(usage) + ` / on (namespace, workload, workload_type) group_left ` +
+			byWorkload(limits) + ` > {{ printf "%.2f" .RedLineUtilization }}) and on(namespace, workload) ` +
+			`label_replace(sum({{ .Metrics.ReadyReplicas }}{namespace="{{ .Namespace }}"} * on(replicaset) ` +
+			`group_left(namespace, owner_kind, owner_name) {{ .Metrics.ReplicaSetOwner }}{namespace="{{ .Namespace }}", ` +
+			`owner_kind="{{ .WorkloadType }}", owner_name="{{ .Workload }}"}) by (namespace, owner_kind, owner_name) ` +
+			`< on(namespace, owner_kind, owner_name) ({{ .Metrics.HPAMaxReplicas }}{namespace="{{ .Namespace }}"} ` +
+			`* on(namespace, horizontalpodautoscaler) group_left(owner_kind, owner_name) label_replace(label_replace(` +
+			`{{ .Metrics.HPAOwnerInfo }}{namespace="{{ .Namespace }}", scaletargetref_kind="{{ .WorkloadType }}", ` +
+			`scaletargetref_name="{{ .Workload }}"}, "owner_kind", "$1", "scaletargetref_kind", "(.*)"), ` +
+			`"owner_name", "$1", "scaletargetref_name", "(.*)")), "workload", "$1", "owner_name", "(.*)")`,
+	}
+}
+
+// DefaultLabelSchemes are the label schemes detected in order: the recording rules of the kube-prometheus the default
+// queries are made of, the cadvisor and kube-state-metrics v2 series without them, and the cadvisor series of the
+// clusters before Kubernetes 1.16, labeled with container_name and pod_name, with the kube-state-metrics v1 series.
+var DefaultLabelSchemes = []LabelScheme{
+	{
+		Name: "kube-prometheus",
+		Probes: []string{
+			"node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate",
+			"namespace_workload_pod:kube_pod_owner:relabel",
+			"cluster:namespace:pod_cpu:active:kube_pod_container_resource_limits",
+		},
+	},
+	{
+		Name: "kube-state-metrics-v2",
+		Probes: []string{
+			`container_cpu_usage_seconds_total{container!=""}`,
+			"kube_pod_owner",
+			"kube_replicaset_owner",
+			`kube_pod_container_resource_limits{resource="cpu"}`,
+		},
+		Templates: cadvisorQueryTemplates(
+			`sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{namespace="{{ .Namespace }}", `+
+				`container!="", container!="POD"}[5m]))`,
+			`sum by (namespace, pod) (kube_pod_container_resource_limits{namespace="{{ .Namespace }}", `+
+				`resource="cpu"})`),
+	},
+	{
+		Name: "kube-state-metrics-v1",
+		Probes: []string{
+			`container_cpu_usage_seconds_total{container_name!=""}`,
+			"kube_pod_owner",
+			"kube_replicaset_owner",
+			"kube_pod_container_resource_limits_cpu_cores",
+		},
+		Templates: cadvisorQueryTemplates(
+			`sum by (namespace, pod) (label_replace(rate(container_cpu_usage_seconds_total{`+
+				`namespace="{{ .Namespace }}", container_name!="", container_name!="POD"}[5m]), "pod", "$1", `+
+				`"pod_name", "(.*)"))`,
+			`sum by (namespace, pod) (kube_pod_container_resource_limits_cpu_cores{namespace="{{ .Namespace }}"})`),
+	},
+}
+
+// LabelSchemeByName returns the label scheme of the name among the schemes.
+func LabelSchemeByName(schemes []LabelScheme, name string) (LabelScheme, error) {
+	names := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		if scheme.Name == name {
+			return scheme, nil
+		}
+		names = append(names, scheme.Name)
+	}
+	return LabelScheme{}, fmt.Errorf("unknown label scheme %q, expected %s or %s", name, LabelSchemeAuto,
+		strings.Join(names, ", "))
+}
+
+// DetectLabelScheme returns the first of the schemes all of whose probes return series from any of the Prometheus
+// instances. If none does, the error lists the probes every scheme misses.
+func (ps *PrometheusScraper) DetectLabelScheme(ctx context.Context, schemes []LabelScheme) (LabelScheme, error) {
+	if ps.api == nil {
+		return LabelScheme{}, fmt.Errorf("no apiurl for executing prometheus query")
+	}
+	ctx, cancel := context.WithTimeout(ctx, ps.queryTimeout)
+	defer cancel()
+
+	diagnostics := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		var missing []string
+		for _, probe := range scheme.Probes {
+			if err := ps.probeSeries(ctx, probe); err != nil {
+				missing = append(missing, err.Error())
+			}
+		}
+		if len(missing) == 0 {
+			return scheme, nil
+		}
+		diagnostics = append(diagnostics, fmt.Sprintf("%s: %s", scheme.Name, strings.Join(missing, ", ")))
+	}
+	return LabelScheme{}, fmt.Errorf("none of the label schemes match the metrics of the prometheus instances, "+
+		"set the query templates of the scraper: %s", strings.Join(diagnostics, "; "))
+}
+
+// probeSeries returns an error if none of the Prometheus instances has a series of the selector.
+func (ps *PrometheusScraper) probeSeries(ctx context.Context, selector string) error {
+	var lastErr error
+	for _, pi := range ps.api {
+		result, _, err := pi.apiUrl.Query(ctx, fmt.Sprintf("count(%s)", selector), time.Now())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if vector, ok := result.(model.Vector); ok && len(vector) > 0 {
+			return nil
+		}
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%s unknown (%v)", selector, lastErr)
+	}
+	return fmt.Errorf("%s missing", selector)
+}
+
+// WithDefaults returns the templates with the empty ones taken from the defaults.
+func (t QueryTemplates) WithDefaults(defaults QueryTemplates) QueryTemplates {
+	if t.CPUUtilization == "" {
+		t.CPUUtilization = defaults.CPUUtilization
+	}
+	if t.CPULimitsPerPod == "" {
+		t.CPULimitsPerPod = defaults.CPULimitsPerPod
+	}
+	if t.PodReadyLatency == "" {
+		t.PodReadyLatency = defaults.PodReadyLatency
+	}
+	if t.Breach == "" {
+		t.Breach = defaults.Breach
+	}
+	return t
+}
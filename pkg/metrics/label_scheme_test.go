@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Label schemes", func() {
+	// newPrometheus returns the instant queries of the series it has a result and the others an empty one
+	newPrometheus := func(series ...string) (*httptest.Server, *[]string) {
+		var queries []string
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.FormValue("query")
+			queries = append(queries, query)
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/query_range") {
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+					`{"metric":{},"values":[[1700000000,"1"]]}]}}`))
+				return
+			}
+			for _, s := range series {
+				if query == "count("+s+")" {
+					_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+						`{"metric":{},"value":[1700000000,"3"]}]}}`))
+					return
+				}
+			}
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		})), &queries
+	}
+
+	It("should detect the first scheme whose series are all present", func() {
+		prometheus, _ := newPrometheus(`container_cpu_usage_seconds_total{container!=""}`, "kube_pod_owner",
+			"kube_replicaset_owner", `kube_pod_container_resource_limits{resource="cpu"}`,
+			"kube_pod_container_resource_limits_cpu_cores", "namespace_workload_pod:kube_pod_owner:relabel")
+		defer prometheus.Close()
+		scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+
+		scheme, err := scraper.DetectLabelScheme(context.Background(), DefaultLabelSchemes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scheme.Name).To(Equal("kube-state-metrics-v2"))
+	})
+
+	It("should fail with the series every scheme misses", func() {
+		prometheus, _ := newPrometheus("kube_pod_owner", "kube_replicaset_owner")
+		defer prometheus.Close()
+		scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = scraper.DetectLabelScheme(context.Background(), DefaultLabelSchemes)
+		Expect(err).To(MatchError(And(
+			ContainSubstring("kube-prometheus: node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate missing"),
+			ContainSubstring(`kube-state-metrics-v2: container_cpu_usage_seconds_total{container!=""} missing, `+
+				`kube_pod_container_resource_limits{resource="cpu"} missing;`),
+			ContainSubstring("kube-state-metrics-v1: "),
+			Not(ContainSubstring("kube_replicaset_owner missing")))))
+
+		_, err = LabelSchemeByName(DefaultLabelSchemes, "kube-state-metrics-v3")
+		Expect(err).To(MatchError(ContainSubstring("auto or kube-prometheus, kube-state-metrics-v2, kube-state-metrics-v1")))
+	})
+
+	It("should query the workloads by the templates of the scheme under the overridden ones", func() {
+		prometheus, queries := newPrometheus()
+		defer prometheus.Close()
+		scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		scheme, err := LabelSchemeByName(DefaultLabelSchemes, "kube-state-metrics-v1")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.WithQueryTemplates(QueryTemplates{CPULimitsPerPod: `limits{app="{{ .Workload }}"}`}.
+			WithDefaults(scheme.Templates))
+		Expect(err).NotTo(HaveOccurred())
+
+		end := time.Now()
+		_, err = scraper.GetAverageCPUUtilizationByWorkload("test-ns", "test-workload", end.Add(-time.Hour), end,
+			time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetCPULimitsPerPodByWorkload("test-ns", "test-workload", end.Add(-time.Hour), end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetCPUUtilizationBreachDataPoints("test-ns", "Deployment", "test-workload", 0.85,
+			end.Add(-time.Hour), end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*queries).To(HaveLen(3))
+		Expect((*queries)[0]).To(And(ContainSubstring(`container_cpu_usage_seconds_total{namespace="test-ns", container_name!=""`),
+			ContainSubstring(`kube_replicaset_owner{namespace="test-ns", owner_kind="Deployment", owner_name="test-workload"}`)))
+		Expect((*queries)[1]).To(Equal(`limits{app="test-workload"}`))
+		Expect((*queries)[2]).To(And(ContainSubstring("> 0.85) and on(namespace, workload)"),
+			ContainSubstring(`kube_horizontalpodautoscaler_info{namespace="test-ns", scaletargetref_kind="Deployment"`)))
+	})
+})
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	NetworkDataPointsQuery = "networkDataPointsQuery"
+
+	networkReceiveBytesMetric  = "container_network_receive_bytes_total"
+	networkTransmitBytesMetric = "container_network_transmit_bytes_total"
+)
+
+// NetworkScraper scrapes the network throughput of the workloads.
+type NetworkScraper interface {
+	// GetNetworkThroughputByWorkload returns the bytes per second received or transmitted by the pods of the workload,
+	// whichever is higher, since the NICs are full duplex.
+	GetNetworkThroughputByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+}
+
+func (ps *PrometheusScraper) GetNetworkThroughputByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	received, err := ps.getRangeDataPoints(ps.networkThroughputQuery(networkReceiveBytesMetric, namespace, workload, step),
+		NetworkDataPointsQuery, workload, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	transmitted, err := ps.getRangeDataPoints(ps.networkThroughputQuery(networkTransmitBytesMetric, namespace, workload, step),
+		NetworkDataPointsQuery, workload, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	return maxDataPoints(received, transmitted), nil
+}
+
+func (ps *PrometheusScraper) networkThroughputQuery(metric, namespace, workload string, step time.Duration) string {
+	// the rate needs at least two scrapes of the counters within its window
+	window := time.Duration(math.Max(float64(step), float64(2*time.Minute)))
+	return fmt.Sprintf("sum(rate(%s{namespace=\"%s\"}[%s]) * on (namespace,pod) group_left(workload, workload_type)"+
+		"%s{namespace=\"%s\", workload=\"%s\", workload_type=\"deployment\"}) by(namespace, workload, workload_type)",
+		metric, namespace, model.Duration(window), ps.metricRegistry.podOwnerMetric, namespace, workload)
+}
+
+// maxDataPoints returns the higher of the values of the datapoints of both the sorted series at every timestamp.
+func maxDataPoints(dataPoints1, dataPoints2 []DataPoint) []DataPoint {
+	merged := make([]DataPoint, 0, len(dataPoints1))
+	index1, index2 := 0, 0
+	for index1 < len(dataPoints1) && index2 < len(dataPoints2) {
+		dp1, dp2 := dataPoints1[index1], dataPoints2[index2]
+		switch {
+		case dp1.Timestamp.Before(dp2.Timestamp):
+			merged = append(merged, dp1)
+			index1++
+		case dp2.Timestamp.Before(dp1.Timestamp):
+			merged = append(merged, dp2)
+			index2++
+		default:
+			merged = append(merged, DataPoint{Timestamp: dp1.Timestamp, Value: math.Max(dp1.Value, dp2.Value)})
+			index1++
+			index2++
+		}
+	}
+	merged = append(merged, dataPoints1[index1:]...)
+	return append(merged, dataPoints2[index2:]...)
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network throughput", func() {
+	It("should take the busier direction at every timestamp", func() {
+		t := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+		received := []DataPoint{{t, 100}, {t.Add(time.Minute), 300}, {t.Add(3 * time.Minute), 50}}
+		transmitted := []DataPoint{{t, 200}, {t.Add(time.Minute), 100}, {t.Add(2 * time.Minute), 400}}
+		Expect(maxDataPoints(received, transmitted)).To(Equal([]DataPoint{
+			{t, 200}, {t.Add(time.Minute), 300}, {t.Add(2 * time.Minute), 400}, {t.Add(3 * time.Minute), 50},
+		}))
+	})
+})
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+const PodResourcesDataPointsQuery = "podResourcesDataPointsQuery"
+
+// PodResourcesScraper scrapes the resources of the pods of the workloads over time, which change when their pods are
+// resized, e.g. by a VPA or on a change of their limits.
+type PodResourcesScraper interface {
+	// GetCPULimitsPerPodByWorkload returns the cpu limits of the containers of a pod of the workload, averaged across
+	// its pods.
+	GetCPULimitsPerPodByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+}
+
+func (ps *PrometheusScraper) GetCPULimitsPerPodByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	// the sizes of the pods are averaged while a rollout runs both the old and the new ones
+	query := fmt.Sprintf("sum(%s{namespace=\"%s\"} * on (namespace,pod) group_left(workload, workload_type)"+
+		"%s{namespace=\"%s\", workload=\"%s\", workload_type=\"deployment\"}) by(namespace, workload, workload_type) / "+
+		"count(%s{namespace=\"%s\", workload=\"%s\", workload_type=\"deployment\"}) by(namespace, workload, workload_type)",
+		ps.metricRegistry.resourceLimitMetric, namespace, ps.metricRegistry.podOwnerMetric, namespace, workload,
+		ps.metricRegistry.podOwnerMetric, namespace, workload)
+	query, err := ps.templatedQuery(PodResourcesDataPointsQuery, QueryTemplateData{Namespace: namespace,
+		Workload: workload}, query)
+	if err != nil {
+		return nil, err
+	}
+	return ps.getRangeDataPoints(query, PodResourcesDataPointsQuery, workload, start, end, step)
+}
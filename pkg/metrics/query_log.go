@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// CPUUtilizationQueryID returns the ID of the query of the cpu utilization of the workload over the range. The
+// scraper logs the PromQL it issues with it and the recommender attaches it to the exemplars of the latency of the
+// query, so that a slow query seen in Prometheus can be pulled up from the logs.
+func CPUUtilizationQueryID(namespace, workload string, start, end time.Time, step time.Duration) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%d/%d/%d", namespace, workload, start.UnixMilli(), end.UnixMilli(), step.Milliseconds())
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// WithQueryLogging logs the PromQL of the cpu utilization queries issued to every Prometheus instance, along with their
+// ID, range and latency.
+func (ps *PrometheusScraper) WithQueryLogging(enabled bool) *PrometheusScraper {
+	ps.logQueries = enabled
+	return ps
+}
+
+func (ps *PrometheusScraper) logQuery(queryID, query, address string, start, end time.Time, step time.Duration,
+	queryStartTime time.Time, err error) {
+	if !ps.logQueries {
+		return
+	}
+	ps.logger.Info("Issued prometheus query", "queryId", queryID, "query", query, "instance", address,
+		"start", start, "end", end, "step", step, "latency", time.Since(queryStartTime), "error", err)
+}
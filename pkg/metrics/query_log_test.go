@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query logging", func() {
+	It("should log the PromQL of the utilization queries with their ID", func() {
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{},"values":[[1700000000,"1"]]}]}}`))
+		}))
+		defer prometheus.Close()
+		var logs []string
+		logger := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+
+		end := time.Now()
+		start := end.Add(-time.Hour)
+		scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logger)
+		Expect(err).NotTo(HaveOccurred())
+		logs = nil
+		_, err = scraper.GetAverageCPUUtilizationByWorkload("test-ns", "test-workload", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logs).To(BeEmpty())
+
+		_, err = scraper.WithQueryLogging(true).GetAverageCPUUtilizationByWorkload("test-ns", "test-workload", start,
+			end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		queryID := CPUUtilizationQueryID("test-ns", "test-workload", start, end, time.Minute)
+		Expect(logs).To(ConsistOf(And(ContainSubstring(`"queryId"=%q`, queryID),
+			ContainSubstring(`workload=\"test-workload\"`))))
+	})
+
+	It("should tell the queries of other workloads and ranges apart", func() {
+		end := time.Now()
+		start := end.Add(-time.Hour)
+		queryID := CPUUtilizationQueryID("test-ns", "test-workload", start, end, time.Minute)
+		Expect(CPUUtilizationQueryID("test-ns", "test-workload", start, end, time.Minute)).To(Equal(queryID))
+		Expect(CPUUtilizationQueryID("test-ns", "other-workload", start, end, time.Minute)).NotTo(Equal(queryID))
+		Expect(CPUUtilizationQueryID("test-ns", "test-workload", start.Add(time.Minute), end, time.Minute)).NotTo(Equal(queryID))
+	})
+})
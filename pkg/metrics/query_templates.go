@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const PodReadyLatencyQuery = "podReadyLatencyQuery"
+
+// QueryTemplates are the Go templates of the PromQL queries of the scraper, executed with the QueryTemplateData of
+// the workload, for the sites whose relabeling doesn't match the default queries, e.g. with custom workload labels or
+// another variant of the kube-state-metrics. The queries whose template is empty are the default ones.
+type QueryTemplates struct {
+	// CPUUtilization returns the cpu utilization of the workload summed up across its pods.
+	CPUUtilization string `yaml:"cpuUtilization"`
+	// CPULimitsPerPod returns the cpu limits of a pod of the workload, averaged across its pods.
+	CPULimitsPerPod string `yaml:"cpuLimitsPerPod"`
+	// PodReadyLatency returns the latency of the pods of the workload to get ready, aggregated by the Aggregation.
+	PodReadyLatency string `yaml:"podReadyLatency"`
+	// Breach returns the cpu utilization of the workload above the RedLineUtilization while it's short of the max
+	// replicas of its HPA.
+	Breach string `yaml:"breach"`
+}
+
+// QueryTemplateData are the variables of the query templates.
+type QueryTemplateData struct {
+	Namespace string
+	Workload  string
+	// WorkloadType is the kind of the workload, e.g. Deployment. It's set for the Breach template only.
+	WorkloadType string
+	// RedLineUtilization is set for the Breach template only.
+	RedLineUtilization float64
+	// Aggregation opens the aggregation of the pod ready latencies, e.g. `quantile(0.9,`, whose parentheses the
+	// PodReadyLatency template closes. It's set for the PodReadyLatency template only.
+	Aggregation string
+	// Metrics are the names of the metrics the default queries are made of.
+	Metrics QueryTemplateMetrics
+}
+
+// QueryTemplateMetrics are the names of the metrics of the MetricNameRegistry of the scraper.
+type QueryTemplateMetrics struct {
+	Utilization     string
+	PodOwner        string
+	ResourceLimit   string
+	ReadyReplicas   string
+	ReplicaSetOwner string
+	HPAMaxReplicas  string
+	HPAOwnerInfo    string
+	PodCreatedTime  string
+	PodReadyTime    string
+}
+
+// WithQueryTemplates overrides the default queries of the scraper with the non-empty query templates. It returns an
+// error if any of them fails to parse. The cpu utilization isn't fetched with the remote read once its query is
+// overridden, since the remote read evaluates the default one.
+func (ps *PrometheusScraper) WithQueryTemplates(templates QueryTemplates) (*PrometheusScraper, error) {
+	parsed := map[string]*template.Template{}
+	for query, text := range map[string]string{
+		CPUUtilizationDataPointsQuery: templates.CPUUtilization,
+		PodResourcesDataPointsQuery:   templates.CPULimitsPerPod,
+		PodReadyLatencyQuery:          templates.PodReadyLatency,
+		BreachDataPointsQuery:         templates.Breach,
+	} {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		tmpl, err := template.New(query).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of the %s: %v", query, err)
+		}
+		parsed[query] = tmpl
+	}
+	ps.queryTemplates = parsed
+	return ps, nil
+}
+
+// templatedQuery returns the query rendered from the template of the query with the data, or the default query if
+// it isn't overridden.
+func (ps *PrometheusScraper) templatedQuery(query string, data QueryTemplateData, defaultQuery string) (string,
+	error) {
+	tmpl, ok := ps.queryTemplates[query]
+	if !ok {
+		return defaultQuery, nil
+	}
+	data.Metrics = QueryTemplateMetrics{
+		Utilization:     ps.metricRegistry.utilizationMetric,
+		PodOwner:        ps.metricRegistry.podOwnerMetric,
+		ResourceLimit:   ps.metricRegistry.resourceLimitMetric,
+		ReadyReplicas:   ps.metricRegistry.readyReplicasMetric,
+		ReplicaSetOwner: ps.metricRegistry.replicaSetOwnerMetric,
+		HPAMaxReplicas:  ps.metricRegistry.hpaMaxReplicasMetric,
+		HPAOwnerInfo:    ps.metricRegistry.hpaOwnerInfoMetric,
+		PodCreatedTime:  ps.metricRegistry.podCreatedTimeMetric,
+		PodReadyTime:    ps.metricRegistry.podReadyTimeMetric,
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("error rendering the template of the %s: %v", query, err)
+	}
+	return rendered.String(), nil
+}
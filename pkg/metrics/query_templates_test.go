@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query templates", func() {
+	end := time.Now()
+	start := end.Add(-time.Hour)
+
+	var (
+		queries    []string
+		prometheus *httptest.Server
+		scraper    *PrometheusScraper
+	)
+
+	BeforeEach(func() {
+		queries = nil
+		prometheus = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == remoteReadPath {
+				http.Error(w, "unexpected remote read", http.StatusBadRequest)
+				return
+			}
+			queries = append(queries, r.FormValue("query"))
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/query_range") {
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+					`{"metric":{},"values":[[1700000000,"1"]]}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{},"value":[1700000000,"20"]}]}}`))
+		}))
+		var err error
+		scraper, err = NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		prometheus.Close()
+	})
+
+	It("should render the overridden queries with the variables of the workload", func() {
+		_, err := scraper.WithQueryTemplates(QueryTemplates{
+			CPUUtilization: `sum(rate(cpu{ns="{{ .Namespace }}", app="{{ .Workload }}"}[5m]))`,
+			PodReadyLatency: `{{ .Aggregation }} {{ .Metrics.PodReadyTime }}{ns="{{ .Namespace }}"} - ` +
+				`{{ .Metrics.PodCreatedTime }}{ns="{{ .Namespace }}"})`,
+			Breach: `sum(cpu{app="{{ .Workload }}", kind="{{ .WorkloadType }}"}) > {{ printf "%.2f" .RedLineUtilization }}`,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = scraper.WithRemoteRead(0).GetAverageCPUUtilizationByWorkload("test-ns", "test-workload", start, end,
+			time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetCPUUtilizationBreachDataPoints("test-ns", "Deployment", "test-workload", 0.85, start, end,
+			time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetACLByWorkloadWithStrategy("test-ns", "test-workload", ACLStrategyP90)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queries).To(Equal([]string{
+			`sum(rate(cpu{ns="test-ns", app="test-workload"}[5m]))`,
+			`sum(cpu{app="test-workload", kind="Deployment"}) > 0.85`,
+			`quantile(0.9, alm_kube_pod_ready_time{ns="test-ns"} - kube_pod_created{ns="test-ns"})`,
+		}))
+	})
+
+	It("should keep the default queries without their templates", func() {
+		_, err := scraper.WithQueryTemplates(QueryTemplates{CPUUtilization: "  "})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetCPULimitsPerPodByWorkload("test-ns", "test-workload", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetAverageCPUUtilizationByWorkload("test-ns", "test-workload", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queries).To(HaveLen(2))
+		Expect(queries[0]).To(HavePrefix("sum(cluster:namespace:pod_cpu:active:kube_pod_container_resource_limits{"))
+		Expect(queries[1]).To(HavePrefix("sum(node_namespace_pod_container:container_cpu_usage_seconds_total"))
+	})
+
+	It("should fail on the templates which don't parse or render", func() {
+		_, err := scraper.WithQueryTemplates(QueryTemplates{CPULimitsPerPod: `sum(limits{app="{{ .Workload }"})`})
+		Expect(err).To(MatchError(ContainSubstring(PodResourcesDataPointsQuery)))
+
+		_, err = scraper.WithQueryTemplates(QueryTemplates{CPULimitsPerPod: `sum(limits{app="{{ .Deployment }}"})`})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.GetCPULimitsPerPodByWorkload("test-ns", "test-workload", start, end, time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(queries).To(BeEmpty())
+	})
+})
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+const QueueDataPointsQuery = "queueDataPointsQuery"
+
+// QueueQueries are the PromQL templates of the metrics of a type of queue, formatted with the name of the queue.
+type QueueQueries struct {
+	// Depth returns the messages waiting in the queue.
+	Depth string `yaml:"depth"`
+	// ConsumeRate returns the messages consumed from the queue per second.
+	ConsumeRate string `yaml:"consumeRate"`
+}
+
+// DefaultQueueQueries are the queries of the SQS metrics exported by the yet-another-cloudwatch-exporter, at its
+// default period of 60s, and of the RabbitMQ metrics exported by the rabbitmq-exporter, by the queue type of the
+// queue consumers.
+var DefaultQueueQueries = map[string]QueueQueries{
+	"sqs": {
+		Depth:       `sum(aws_sqs_approximate_number_of_messages_visible_average{dimension_QueueName="%s"})`,
+		ConsumeRate: `sum(aws_sqs_number_of_messages_deleted_sum{dimension_QueueName="%s"}) / 60`,
+	},
+	"rabbitmq": {
+		Depth:       `sum(rabbitmq_queue_messages_ready{queue="%s"})`,
+		ConsumeRate: `sum(rate(rabbitmq_queue_messages_delivered_total{queue="%s"}[5m]))`,
+	},
+}
+
+// QueueScraper scrapes the metrics of the queues along with the pods consuming them.
+type QueueScraper interface {
+	// GetQueueDepth returns the messages waiting in the queue of the type.
+	GetQueueDepth(queueType, queue string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+	// GetQueueConsumeRate returns the messages consumed per second from the queue of the type.
+	GetQueueConsumeRate(queueType, queue string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+	// GetPodCountByWorkload returns the number of pods of the workload.
+	GetPodCountByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]DataPoint, error)
+}
+
+// WithQueueQueries overrides the default queries of the metrics of the queue types.
+func (ps *PrometheusScraper) WithQueueQueries(queueQueries map[string]QueueQueries) *PrometheusScraper {
+	ps.queueQueries = queueQueries
+	return ps
+}
+
+func (ps *PrometheusScraper) GetQueueDepth(queueType, queue string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	queries, err := ps.queueQueriesOf(queueType)
+	if err != nil {
+		return nil, err
+	}
+	return ps.getRangeDataPoints(fmt.Sprintf(queries.Depth, queue), QueueDataPointsQuery, queue, start, end, step)
+}
+
+func (ps *PrometheusScraper) GetQueueConsumeRate(queueType, queue string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	queries, err := ps.queueQueriesOf(queueType)
+	if err != nil {
+		return nil, err
+	}
+	return ps.getRangeDataPoints(fmt.Sprintf(queries.ConsumeRate, queue), QueueDataPointsQuery, queue, start, end, step)
+}
+
+func (ps *PrometheusScraper) queueQueriesOf(queueType string) (QueueQueries, error) {
+	if queries, ok := ps.queueQueries[queueType]; ok {
+		return queries, nil
+	}
+	if queries, ok := DefaultQueueQueries[queueType]; ok {
+		return queries, nil
+	}
+	return QueueQueries{}, fmt.Errorf("no queries for the metrics of the %s queues", queueType)
+}
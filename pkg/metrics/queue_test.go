@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue queries", func() {
+	It("should default to the queries of the exporters of the queue types", func() {
+		ps := &PrometheusScraper{}
+		queries, err := ps.queueQueriesOf("rabbitmq")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries).To(Equal(DefaultQueueQueries["rabbitmq"]))
+		_, err = ps.queueQueriesOf("kinesis")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should override the default queries of the queue types", func() {
+		sqsQueries := QueueQueries{
+			Depth:       `sum(sqs_messages_visible{queue="%s"})`,
+			ConsumeRate: `sum(rate(sqs_messages_deleted_total{queue="%s"}[5m]))`,
+		}
+		ps := (&PrometheusScraper{}).WithQueueQueries(map[string]QueueQueries{"sqs": sqsQueries})
+		queries, err := ps.queueQueriesOf("sqs")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries).To(Equal(sqsQueries))
+		queries, err = ps.queueQueriesOf("rabbitmq")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries).To(Equal(DefaultQueueQueries["rabbitmq"]))
+	})
+})
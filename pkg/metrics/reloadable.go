@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	scraperReloadCount = promauto.NewCounter(
+		prometheus.CounterOpts{Name: "prometheus_scraper_reload_count",
+			Help: "Number of times the prometheus scraper was rebuilt on the changes of its config"},
+	)
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(scraperReloadCount)
+}
+
+// ReloadableScraper is a Scraper which can be swapped for a PrometheusScraper of another config, e.g. of the changed
+// urls or credentials of the Prometheus instances, while the recommenders and the monitors holding it keep scraping.
+// The queries in flight complete on the scraper they were started on. The queries of a workload go to the scraper of
+// the first metrics route matching it, if any.
+type ReloadableScraper struct {
+	backends atomic.Pointer[metricsBackends]
+}
+
+func NewReloadableScraper(scraper *PrometheusScraper, routes ...MetricsRoute) *ReloadableScraper {
+	rs := &ReloadableScraper{}
+	rs.backends.Store(&metricsBackends{scraper: scraper, routes: routes})
+	return rs
+}
+
+// Reload swaps the scraper and the metrics routes for the next queries. The queue queries of the current scraper carry
+// over to it.
+func (rs *ReloadableScraper) Reload(scraper *PrometheusScraper, routes ...MetricsRoute) {
+	scraper.queueQueries = rs.current().queueQueries
+	rs.backends.Store(&metricsBackends{scraper: scraper, routes: routes})
+	scraperReloadCount.Inc()
+}
+
+// WithQueueQueries overrides the default queries of the metrics of the queue types.
+func (rs *ReloadableScraper) WithQueueQueries(queueQueries map[string]QueueQueries) *ReloadableScraper {
+	rs.current().WithQueueQueries(queueQueries)
+	return rs
+}
+
+func (rs *ReloadableScraper) current() *PrometheusScraper {
+	return rs.backends.Load().scraper
+}
+
+func (rs *ReloadableScraper) scraperFor(namespace, workload string) *PrometheusScraper {
+	return rs.backends.Load().scraperFor(namespace, workload)
+}
+
+func (rs *ReloadableScraper) GetAverageCPUUtilizationByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.scraperFor(namespace, workload).GetAverageCPUUtilizationByWorkload(namespace, workload, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetCPUUtilizationBreachDataPoints(namespace, workloadType, workload string,
+	redLineUtilization float64, start, end time.Time, step time.Duration) ([]DataPoint, error) {
+	return rs.scraperFor(namespace, workload).GetCPUUtilizationBreachDataPoints(namespace, workloadType, workload, redLineUtilization, start,
+		end, step)
+}
+
+func (rs *ReloadableScraper) GetACLByWorkload(namespace, workload string) (time.Duration, error) {
+	return rs.scraperFor(namespace, workload).GetACLByWorkload(namespace, workload)
+}
+
+func (rs *ReloadableScraper) GetACLByWorkloadWithStrategy(namespace, workload, strategy string) (time.Duration, error) {
+	return rs.scraperFor(namespace, workload).GetACLByWorkloadWithStrategy(namespace, workload, strategy)
+}
+
+func (rs *ReloadableScraper) GetNetworkThroughputByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.scraperFor(namespace, workload).GetNetworkThroughputByWorkload(namespace, workload, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetCPULimitsPerPodByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.scraperFor(namespace, workload).GetCPULimitsPerPodByWorkload(namespace, workload, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetCPUUtilizationByContainer(namespace, workload string, start, end time.Time,
+	step time.Duration) (map[string][]DataPoint, error) {
+	return rs.scraperFor(namespace, workload).GetCPUUtilizationByContainer(namespace, workload, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetQueueDepth(queueType, queue string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.current().GetQueueDepth(queueType, queue, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetQueueConsumeRate(queueType, queue string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.current().GetQueueConsumeRate(queueType, queue, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetPodCountByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.scraperFor(namespace, workload).GetPodCountByWorkload(namespace, workload, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetBreachAssertionTimestamps(assertion string, start, end time.Time,
+	step time.Duration) ([]time.Time, error) {
+	return rs.current().GetBreachAssertionTimestamps(assertion, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetConsumerGroupLag(consumerGroup, topic string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.current().GetConsumerGroupLag(consumerGroup, topic, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetConsumerGroupConsumeRate(consumerGroup, topic string, start, end time.Time,
+	step time.Duration) ([]DataPoint, error) {
+	return rs.current().GetConsumerGroupConsumeRate(consumerGroup, topic, start, end, step)
+}
+
+func (rs *ReloadableScraper) GetTopicPartitions(topic string) (int, error) {
+	return rs.current().GetTopicPartitions(topic)
+}
+
+// CheckHealth returns an error if the default scraper or the scraper of any of the metrics routes can't be queried.
+func (rs *ReloadableScraper) CheckHealth(ctx context.Context) error {
+	backends := rs.backends.Load()
+	if err := backends.scraper.CheckHealth(ctx); err != nil {
+		return err
+	}
+	for _, route := range backends.routes {
+		if err := route.scraper.CheckHealth(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ Scraper             = &ReloadableScraper{}
+	_ ACLScraper          = &ReloadableScraper{}
+	_ NetworkScraper      = &ReloadableScraper{}
+	_ PodResourcesScraper = &ReloadableScraper{}
+	_ ContainerScraper    = &ReloadableScraper{}
+	_ QueueScraper        = &ReloadableScraper{}
+	_ KafkaScraper        = &ReloadableScraper{}
+	_ HealthChecker       = &ReloadableScraper{}
+)
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReloadableScraper", func() {
+	var authorizations chan string
+
+	newPrometheus := func(limit string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations <- r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{},"values":[[1700000000,"` + limit + `"]]}]}}`))
+		}))
+	}
+
+	BeforeEach(func() {
+		authorizations = make(chan string, 10)
+	})
+
+	It("should scrape the reloaded prometheus with its credentials", func() {
+		oldPrometheus, newPrometheus := newPrometheus("2"), newPrometheus("4")
+		defer oldPrometheus.Close()
+		defer newPrometheus.Close()
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("rotated"), 0600)).To(Succeed())
+
+		oldScraper, err := NewPrometheusScraper([]string{oldPrometheus.URL}, time.Minute, time.Hour, 15, 15,
+			logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		queueQueries := map[string]QueueQueries{"sqs": {Depth: "depth", ConsumeRate: "rate"}}
+		reloadable := NewReloadableScraper(oldScraper).WithQueueQueries(queueQueries)
+
+		_, err = reloadable.GetPodCountByWorkload("default", "checkout", time.Unix(1700000000, 0),
+			time.Unix(1700000060, 0), time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorizations).To(Receive(BeEmpty()))
+
+		newScraper, err := NewPrometheusScraperWithAuth([]string{newPrometheus.URL},
+			PrometheusAuth{BearerTokenFile: tokenFile}, time.Minute, time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		reloadable.Reload(newScraper)
+
+		dataPoints, err := reloadable.GetPodCountByWorkload("default", "checkout", time.Unix(1700000000, 0),
+			time.Unix(1700000060, 0), time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataPoints).NotTo(BeEmpty())
+		Expect(dataPoints[0].Value).To(Equal(4.0))
+		Expect(authorizations).To(Receive(Equal("Bearer rotated")))
+		Expect(newScraper.queueQueries).To(Equal(queueQueries))
+	})
+
+	It("should scrape the workloads from the prometheus of the first metrics route matching them", func() {
+		defaultPrometheus, tenantPrometheus, otherPrometheus := newPrometheus("1"), newPrometheus("2"), newPrometheus("3")
+		defer defaultPrometheus.Close()
+		defer tenantPrometheus.Close()
+		defer otherPrometheus.Close()
+		scraperOf := func(prometheus *httptest.Server) *PrometheusScraper {
+			scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			return scraper
+		}
+		tenantRoute, err := NewMetricsRoute("payments-.*", "", scraperOf(tenantPrometheus))
+		Expect(err).NotTo(HaveOccurred())
+		otherRoute, err := NewMetricsRoute("", "checkout|cart", scraperOf(otherPrometheus))
+		Expect(err).NotTo(HaveOccurred())
+		reloadable := NewReloadableScraper(scraperOf(defaultPrometheus), tenantRoute, otherRoute)
+
+		for workload, limit := range map[[2]string]float64{
+			{"payments-in", "checkout"}: 2, {"payments", "checkout"}: 3, {"default", "checkout"}: 3,
+			{"default", "checkout-v2"}: 1, {"default", "search"}: 1} {
+			dataPoints, err := reloadable.GetCPULimitsPerPodByWorkload(workload[0], workload[1],
+				time.Unix(1700000000, 0), time.Unix(1700000060, 0), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dataPoints).NotTo(BeEmpty())
+			Expect(dataPoints[0].Value).To(Equal(limit), "workload %v", workload)
+		}
+
+		reloadable.Reload(scraperOf(defaultPrometheus))
+		dataPoints, err := reloadable.GetCPULimitsPerPodByWorkload("payments-in", "checkout", time.Unix(1700000000, 0),
+			time.Unix(1700000060, 0), time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataPoints[0].Value).To(Equal(1.0))
+
+		_, err = NewMetricsRoute("payments-(", "", scraperOf(defaultPrometheus))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,488 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The remote read protocol of Prometheus, see https://prometheus.io/docs/prometheus/latest/querying/remote_read_api/.
+// The requests are snappy compressed protobuf ReadRequests and the responses are streamed as frames of
+// ChunkedReadResponses holding the XOR chunks of the series, as Prometheus stores them.
+const (
+	RemoteReadDataPointsQuery = "remoteReadDataPointsQuery"
+
+	remoteReadPath    = "/api/v1/read"
+	remoteReadVersion = "0.1.0"
+
+	streamedXORChunksResponseType = 1
+	xorChunkEncoding              = 1
+	maxRemoteReadFrameSize        = 50 * 1024 * 1024
+
+	// remoteReadLookback is the lookback delta of the Prometheus instances: the value of a series at a step is its latest
+	// sample within the lookback delta before it.
+	remoteReadLookback = 5 * time.Minute
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+type remoteReadMatcher struct {
+	name  string
+	value string
+	regex bool
+}
+
+type remoteReadSeries struct {
+	labels  map[string]string
+	samples []DataPoint
+}
+
+// WithRemoteRead makes the scraper fetch the cpu utilization of the workloads over the windows of at least minWindow
+// with the remote read protocol rather than the range queries split by the RangeQuerySplitter. The raw samples of the
+// utilization and the pod owner series are streamed as chunks and the utilization of the workload is evaluated from
+// them, sparing the query frontend from evaluating the PromQL over the window. The instances failing the remote read
+// are queried with the range queries.
+func (ps *PrometheusScraper) WithRemoteRead(minWindow time.Duration) *PrometheusScraper {
+	ps.remoteRead = true
+	ps.remoteReadMinWindow = minWindow
+	return ps
+}
+
+func (ps *PrometheusScraper) useRemoteRead(start, end time.Time) bool {
+	_, templated := ps.queryTemplates[CPUUtilizationDataPointsQuery]
+	return ps.remoteRead && !templated && end.Sub(start) >= ps.remoteReadMinWindow
+}
+
+// getAverageCPUUtilizationByRemoteRead returns the same datapoints as the range query of the cpu utilization of the
+// workload, evaluated from the samples of the utilization of its pods and their owner series: the utilization of the
+// pods owned by the workload at every step, summed up.
+func (ps *PrometheusScraper) getAverageCPUUtilizationByRemoteRead(ctx context.Context, pi PrometheusInstance,
+	namespace, workload string, start, end time.Time, step time.Duration) ([]DataPoint, error) {
+	from := start.Add(-remoteReadLookback)
+	owners, err := ps.readSeries(ctx, pi, from, end, []remoteReadMatcher{
+		{name: model.MetricNameLabel, value: ps.metricRegistry.podOwnerMetric},
+		{name: "namespace", value: namespace},
+		{name: "workload", value: workload},
+		{name: "workload_type", value: "deployment"},
+	})
+	if err != nil || len(owners) == 0 {
+		return nil, err
+	}
+
+	ownersByPod := map[string]remoteReadSeries{}
+	var pods []string
+	for _, owner := range owners {
+		pod := owner.labels["pod"]
+		if _, ok := ownersByPod[pod]; !ok {
+			ownersByPod[pod] = owner
+			pods = append(pods, regexp.QuoteMeta(pod))
+		}
+	}
+	sort.Strings(pods)
+	utilizations, err := ps.readSeries(ctx, pi, from, end, []remoteReadMatcher{
+		{name: model.MetricNameLabel, value: ps.metricRegistry.utilizationMetric},
+		{name: "namespace", value: namespace},
+		{name: "pod", value: strings.Join(pods, "|"), regex: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	steps := int(end.Sub(start)/step) + 1
+	sums := make([]float64, steps)
+	present := make([]bool, steps)
+	for _, utilization := range utilizations {
+		owner, ok := ownersByPod[utilization.labels["pod"]]
+		if !ok {
+			continue
+		}
+		values, ownerValues := utilization.valuesAt(start, step, steps), owner.valuesAt(start, step, steps)
+		for k := range values {
+			if math.IsNaN(values[k]) || math.IsNaN(ownerValues[k]) {
+				continue
+			}
+			sums[k] += values[k] * ownerValues[k]
+			present[k] = true
+		}
+	}
+	var dataPoints []DataPoint
+	for k := range sums {
+		if present[k] {
+			dataPoints = append(dataPoints, DataPoint{start.Add(time.Duration(k) * step), sums[k]})
+		}
+	}
+	return dataPoints, nil
+}
+
+// valuesAt returns the values of the series at the steps, the way Prometheus evaluates it, or NaN where it has none.
+// The staleness markers, which are NaN too, end the series.
+func (s remoteReadSeries) valuesAt(start time.Time, step time.Duration, steps int) []float64 {
+	values := make([]float64, steps)
+	i := -1
+	for k := range values {
+		t := start.Add(time.Duration(k) * step)
+		for i+1 < len(s.samples) && !s.samples[i+1].Timestamp.After(t) {
+			i++
+		}
+		values[k] = math.NaN()
+		if i >= 0 && s.samples[i].Timestamp.After(t.Add(-remoteReadLookback)) {
+			values[k] = s.samples[i].Value
+		}
+	}
+	return values
+}
+
+// readSeries returns the samples of the series matching the matchers within the range, read from the instance.
+func (ps *PrometheusScraper) readSeries(ctx context.Context, pi PrometheusInstance, start, end time.Time,
+	matchers []remoteReadMatcher) ([]remoteReadSeries, error) {
+	body := snappy.Encode(nil, encodeReadRequest(start, end, matchers))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(pi.address, "/")+remoteReadPath,
+		bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Read-Version", remoteReadVersion)
+
+	resp, err := pi.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute the remote read: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("remote read failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/x-streamed-protobuf") {
+		return nil, fmt.Errorf("unexpected content type %q of the remote read response", contentType)
+	}
+	return decodeChunkedReadResponses(resp.Body)
+}
+
+func encodeReadRequest(start, end time.Time, matchers []remoteReadMatcher) []byte {
+	var query []byte
+	query = protowire.AppendTag(query, 1, protowire.VarintType)
+	query = protowire.AppendVarint(query, uint64(start.UnixMilli()))
+	query = protowire.AppendTag(query, 2, protowire.VarintType)
+	query = protowire.AppendVarint(query, uint64(end.UnixMilli()))
+	for _, m := range matchers {
+		var matcher []byte
+		if m.regex {
+			matcher = protowire.AppendTag(matcher, 1, protowire.VarintType)
+			matcher = protowire.AppendVarint(matcher, 2)
+		}
+		matcher = protowire.AppendTag(matcher, 2, protowire.BytesType)
+		matcher = protowire.AppendString(matcher, m.name)
+		matcher = protowire.AppendTag(matcher, 3, protowire.BytesType)
+		matcher = protowire.AppendString(matcher, m.value)
+		query = protowire.AppendTag(query, 3, protowire.BytesType)
+		query = protowire.AppendBytes(query, matcher)
+	}
+
+	var request []byte
+	request = protowire.AppendTag(request, 1, protowire.BytesType)
+	request = protowire.AppendBytes(request, query)
+	request = protowire.AppendTag(request, 2, protowire.BytesType)
+	request = protowire.AppendBytes(request, protowire.AppendVarint(nil, streamedXORChunksResponseType))
+	return request
+}
+
+// decodeChunkedReadResponses decodes the frames of the streamed response, each of them its size, the CRC32 of its
+// data and the ChunkedReadResponse. The chunks of a series may be spread over several frames.
+func decodeChunkedReadResponses(body io.Reader) ([]remoteReadSeries, error) {
+	reader := bufio.NewReader(body)
+	seriesByLabels := map[string]*remoteReadSeries{}
+	var keys []string
+	for {
+		size, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the size of the remote read frame: %v", err)
+		}
+		if size > maxRemoteReadFrameSize {
+			return nil, fmt.Errorf("remote read frame of %d bytes exceeds the max of %d bytes", size, maxRemoteReadFrameSize)
+		}
+		frame := make([]byte, 4+size)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			return nil, fmt.Errorf("truncated remote read frame: %v", err)
+		}
+		if crc32.Checksum(frame[4:], castagnoliTable) != binary.BigEndian.Uint32(frame[:4]) {
+			return nil, fmt.Errorf("remote read frame doesn't match its checksum")
+		}
+
+		err = forEachField(frame[4:], func(num protowire.Number, _ uint64, b []byte) error {
+			if num != 1 {
+				return nil
+			}
+			series, err := decodeChunkedSeries(b)
+			if err != nil {
+				return err
+			}
+			key := labelsKey(series.labels)
+			if merged, ok := seriesByLabels[key]; ok {
+				merged.samples = append(merged.samples, series.samples...)
+				return nil
+			}
+			seriesByLabels[key] = &series
+			keys = append(keys, key)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	series := make([]remoteReadSeries, 0, len(keys))
+	for _, key := range keys {
+		s := seriesByLabels[key]
+		sort.SliceStable(s.samples, func(i, j int) bool {
+			return s.samples[i].Timestamp.Before(s.samples[j].Timestamp)
+		})
+		series = append(series, *s)
+	}
+	return series, nil
+}
+
+func decodeChunkedSeries(message []byte) (remoteReadSeries, error) {
+	series := remoteReadSeries{labels: map[string]string{}}
+	err := forEachField(message, func(num protowire.Number, _ uint64, b []byte) error {
+		switch num {
+		case 1:
+			var name, value string
+			err := forEachField(b, func(num protowire.Number, _ uint64, b []byte) error {
+				switch num {
+				case 1:
+					name = string(b)
+				case 2:
+					value = string(b)
+				}
+				return nil
+			})
+			series.labels[name] = value
+			return err
+		case 2:
+			var encoding uint64
+			var data []byte
+			err := forEachField(b, func(num protowire.Number, v uint64, b []byte) error {
+				switch num {
+				case 3:
+					encoding = v
+				case 4:
+					data = b
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if encoding != xorChunkEncoding {
+				return fmt.Errorf("unsupported encoding %d of the remote read chunk", encoding)
+			}
+			samples, err := decodeXORChunk(data)
+			series.samples = append(series.samples, samples...)
+			return err
+		}
+		return nil
+	})
+	return series, err
+}
+
+// forEachField calls fn with the number and the value of every field of the protobuf message, in v for the varints
+// and in b for the length delimited fields. The other fields are skipped.
+func forEachField(message []byte, fn func(num protowire.Number, v uint64, b []byte) error) error {
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		message = message[n:]
+		var v uint64
+		var b []byte
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(message)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(message)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, message)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		message = message[n:]
+		if err := fn(num, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func labelsKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&key, "%s=%q,", name, labels[name])
+	}
+	return key.String()
+}
+
+// decodeXORChunk decodes the samples of a chunk in the Gorilla encoding of Prometheus: the number of samples, then the
+// first timestamp and value in full, and the rest as the delta of delta of their timestamps and the XOR of their
+// values with the previous ones.
+func decodeXORChunk(data []byte) ([]DataPoint, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("remote read chunk of %d bytes is too short", len(data))
+	}
+	r := &xorChunkReader{bitReader: bitReader{data: data[2:]}}
+	count := int(binary.BigEndian.Uint16(data))
+	samples := make([]DataPoint, 0, count)
+	for i := 0; i < count; i++ {
+		if err := r.next(i); err != nil {
+			return nil, fmt.Errorf("corrupted remote read chunk: %v", err)
+		}
+		samples = append(samples, DataPoint{time.UnixMilli(r.t), r.v})
+	}
+	return samples, nil
+}
+
+type xorChunkReader struct {
+	bitReader
+	t        int64
+	tDelta   uint64
+	v        float64
+	leading  uint8
+	trailing uint8
+}
+
+func (r *xorChunkReader) next(i int) error {
+	switch i {
+	case 0:
+		t, err := binary.ReadVarint(r)
+		if err != nil {
+			return err
+		}
+		v, err := r.readBits(64)
+		if err != nil {
+			return err
+		}
+		r.t, r.v = t, math.Float64frombits(v)
+		return nil
+	case 1:
+		tDelta, err := binary.ReadUvarint(r)
+		if err != nil {
+			return err
+		}
+		r.tDelta = tDelta
+	default:
+		dod, err := r.readDeltaOfDelta()
+		if err != nil {
+			return err
+		}
+		r.tDelta = uint64(int64(r.tDelta) + dod)
+	}
+	r.t += int64(r.tDelta)
+	return r.readValue()
+}
+
+func (r *xorChunkReader) readDeltaOfDelta() (int64, error) {
+	// the delta of delta is prefixed by up to 4 ones ending with a zero, which tell its size
+	sizes := [...]uint8{0, 14, 17, 20, 64}
+	prefix := 0
+	for prefix < 4 {
+		bit, err := r.readBits(1)
+		if err != nil {
+			return 0, err
+		}
+		if bit == 0 {
+			break
+		}
+		prefix++
+	}
+	size := sizes[prefix]
+	if size == 0 {
+		return 0, nil
+	}
+	bits, err := r.readBits(size)
+	if err != nil {
+		return 0, err
+	}
+	// the negative deltas of delta come back as the high unsigned numbers of the size
+	if size < 64 && bits > 1<<(size-1) {
+		bits -= 1 << size
+	}
+	return int64(bits), nil
+}
+
+func (r *xorChunkReader) readValue() error {
+	changed, err := r.readBits(1)
+	if err != nil || changed == 0 {
+		return err
+	}
+	newWindow, err := r.readBits(1)
+	if err != nil {
+		return err
+	}
+	if newWindow == 1 {
+		leading, err := r.readBits(5)
+		if err != nil {
+			return err
+		}
+		significant, err := r.readBits(6)
+		if err != nil {
+			return err
+		}
+		if significant == 0 {
+			significant = 64
+		}
+		r.leading, r.trailing = uint8(leading), uint8(64-leading-significant)
+	}
+	bits, err := r.readBits(64 - r.leading - r.trailing)
+	if err != nil {
+		return err
+	}
+	r.v = math.Float64frombits(math.Float64bits(r.v) ^ bits<<r.trailing)
+	return nil
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) readBits(n uint8) (uint64, error) {
+	if r.pos+int(n) > len(r.data)*8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	var bits uint64
+	for i := uint8(0); i < n; i++ {
+		bits = bits<<1 | uint64(r.data[r.pos/8]>>(7-r.pos%8)&1)
+		r.pos++
+	}
+	return bits, nil
+}
+
+func (r *bitReader) ReadByte() (byte, error) {
+	b, err := r.readBits(8)
+	return byte(b), err
+}
@@ -0,0 +1,267 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/bits"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/snappy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// bitWriter and encodeXORChunk encode the samples the way Prometheus does, for the remote read responses of the tests.
+type bitWriter struct {
+	data []byte
+	pos  int
+}
+
+func (w *bitWriter) writeBits(u uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.pos%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		w.data[len(w.data)-1] |= byte(u>>i&1) << (7 - w.pos%8)
+		w.pos++
+	}
+}
+
+func encodeXORChunk(samples []DataPoint) []byte {
+	w := &bitWriter{}
+	var t int64
+	var tDelta uint64
+	var v float64
+	leading, trailing := uint8(0xff), uint8(0)
+	for i, sample := range samples {
+		switch i {
+		case 0:
+			for _, b := range binary.AppendVarint(nil, sample.Timestamp.UnixMilli()) {
+				w.writeBits(uint64(b), 8)
+			}
+			w.writeBits(math.Float64bits(sample.Value), 64)
+		default:
+			newDelta := uint64(sample.Timestamp.UnixMilli() - t)
+			if i == 1 {
+				for _, b := range binary.AppendUvarint(nil, newDelta) {
+					w.writeBits(uint64(b), 8)
+				}
+			} else {
+				dod := int64(newDelta - tDelta)
+				switch {
+				case dod == 0:
+					w.writeBits(0, 1)
+				case -(1<<13-1) <= dod && dod <= 1<<13:
+					w.writeBits(0b10, 2)
+					w.writeBits(uint64(dod), 14)
+				case -(1<<16-1) <= dod && dod <= 1<<16:
+					w.writeBits(0b110, 3)
+					w.writeBits(uint64(dod), 17)
+				case -(1<<19-1) <= dod && dod <= 1<<19:
+					w.writeBits(0b1110, 4)
+					w.writeBits(uint64(dod), 20)
+				default:
+					w.writeBits(0b1111, 4)
+					w.writeBits(uint64(dod), 64)
+				}
+			}
+			tDelta = newDelta
+
+			delta := math.Float64bits(sample.Value) ^ math.Float64bits(v)
+			switch newLeading, newTrailing := uint8(bits.LeadingZeros64(delta)), uint8(bits.TrailingZeros64(delta)); {
+			case delta == 0:
+				w.writeBits(0, 1)
+			case leading != 0xff && newLeading >= leading && newTrailing >= trailing:
+				w.writeBits(0b10, 2)
+				w.writeBits(delta>>trailing, int(64-leading-trailing))
+			default:
+				if newLeading >= 32 {
+					newLeading = 31
+				}
+				leading, trailing = newLeading, newTrailing
+				w.writeBits(0b11, 2)
+				w.writeBits(uint64(leading), 5)
+				w.writeBits(uint64(64-leading-trailing), 6)
+				w.writeBits(delta>>trailing, int(64-leading-trailing))
+			}
+		}
+		t, v = sample.Timestamp.UnixMilli(), sample.Value
+	}
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(samples))), w.data...)
+}
+
+// chunkedReadFrame returns the frame of a ChunkedReadResponse holding the series in a chunk of its samples.
+func chunkedReadFrame(labels map[string]string, samples []DataPoint) []byte {
+	var series []byte
+	for name, value := range labels {
+		var label []byte
+		label = protowire.AppendTag(label, 1, protowire.BytesType)
+		label = protowire.AppendString(label, name)
+		label = protowire.AppendTag(label, 2, protowire.BytesType)
+		label = protowire.AppendString(label, value)
+		series = protowire.AppendTag(series, 1, protowire.BytesType)
+		series = protowire.AppendBytes(series, label)
+	}
+	var chunk []byte
+	chunk = protowire.AppendTag(chunk, 3, protowire.VarintType)
+	chunk = protowire.AppendVarint(chunk, xorChunkEncoding)
+	chunk = protowire.AppendTag(chunk, 4, protowire.BytesType)
+	chunk = protowire.AppendBytes(chunk, encodeXORChunk(samples))
+	series = protowire.AppendTag(series, 2, protowire.BytesType)
+	series = protowire.AppendBytes(series, chunk)
+
+	var response []byte
+	response = protowire.AppendTag(response, 1, protowire.BytesType)
+	response = protowire.AppendBytes(response, series)
+	frame := binary.AppendUvarint(nil, uint64(len(response)))
+	frame = binary.BigEndian.AppendUint32(frame, crc32.Checksum(response, castagnoliTable))
+	return append(frame, response...)
+}
+
+// readRequestMatchers returns the values of the matchers of the first query of the ReadRequest by their names.
+func readRequestMatchers(request []byte) map[string]string {
+	matchers := map[string]string{}
+	Expect(forEachField(request, func(num protowire.Number, _ uint64, query []byte) error {
+		if num != 1 {
+			return nil
+		}
+		return forEachField(query, func(num protowire.Number, _ uint64, matcher []byte) error {
+			if num != 3 {
+				return nil
+			}
+			var name, value string
+			err := forEachField(matcher, func(num protowire.Number, _ uint64, b []byte) error {
+				switch num {
+				case 2:
+					name = string(b)
+				case 3:
+					value = string(b)
+				}
+				return nil
+			})
+			matchers[name] = value
+			return err
+		})
+	})).To(Succeed())
+	return matchers
+}
+
+var _ = Describe("Remote read", func() {
+	end := time.Now().Truncate(time.Hour)
+	start := end.Add(-2 * time.Hour)
+	samples := func(value func(k int) float64) []DataPoint {
+		var dataPoints []DataPoint
+		for t, k := start.Add(-4*time.Minute), 0; !t.After(end); t, k = t.Add(30*time.Second), k+1 {
+			dataPoints = append(dataPoints, DataPoint{t, value(k)})
+		}
+		return dataPoints
+	}
+
+	newPrometheus := func(remoteReads, rangeQueries *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != remoteReadPath {
+				*rangeQueries++
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+					`{"metric":{},"values":[[1700000000,"1"]]}]}}`))
+				return
+			}
+			*remoteReads++
+			Expect(r.Header.Get("Content-Encoding")).To(Equal("snappy"))
+			compressed, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			request, err := snappy.Decode(nil, compressed)
+			Expect(err).NotTo(HaveOccurred())
+			matchers := readRequestMatchers(request)
+
+			w.Header().Set("Content-Type", "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse")
+			switch matchers["__name__"] {
+			case "namespace_workload_pod:kube_pod_owner:relabel":
+				Expect(matchers).To(HaveKeyWithValue("workload", "test-workload"))
+				for _, pod := range []string{"pod-a", "pod-b"} {
+					_, _ = w.Write(chunkedReadFrame(map[string]string{"namespace": "test-ns", "pod": pod,
+						"workload": "test-workload"}, samples(func(int) float64 { return 1 })))
+				}
+			default:
+				Expect(regexp.MustCompile("^(?:" + matchers["pod"] + ")$").MatchString("pod-b")).To(BeTrue())
+				_, _ = w.Write(chunkedReadFrame(map[string]string{"namespace": "test-ns", "pod": "pod-a"},
+					samples(func(k int) float64 { return 0.25 * float64(k%7) })))
+				_, _ = w.Write(chunkedReadFrame(map[string]string{"namespace": "test-ns", "pod": "pod-b"},
+					samples(func(k int) float64 { return 1.5 })))
+			}
+		}))
+	}
+
+	It("should decode the samples of the XOR chunks", func() {
+		t := time.UnixMilli(1700000000000)
+		dataPoints := []DataPoint{{t, 1}, {t.Add(15 * time.Second), 1}, {t.Add(30 * time.Second), 2.5},
+			{t.Add(46 * time.Second), -3}, {t.Add(50 * time.Second), 1e9}, {t.Add(20 * time.Minute), 1e9},
+			{t.Add(20*time.Minute + time.Millisecond), 0.1}, {t.Add(100 * time.Hour), math.Inf(1)}}
+		decoded, err := decodeXORChunk(encodeXORChunk(dataPoints))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(dataPoints))
+
+		_, err = decodeXORChunk(encodeXORChunk(dataPoints)[:20])
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should sum up the utilization of the pods of the workload from the remote read", func() {
+		var remoteReads, rangeQueries int
+		prometheus := newPrometheus(&remoteReads, &rangeQueries)
+		defer prometheus.Close()
+		scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+
+		dataPoints, err := scraper.WithRemoteRead(time.Hour).GetAverageCPUUtilizationByWorkload("test-ns",
+			"test-workload", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remoteReads).To(Equal(2))
+		Expect(rangeQueries).To(BeZero())
+		Expect(dataPoints).To(HaveLen(121))
+		for k, dataPoint := range dataPoints {
+			// the steps fall on every other sample, the ninth of the range being the first
+			Expect(dataPoint.Timestamp).To(BeTemporally("==", start.Add(time.Duration(k)*time.Minute)))
+			Expect(dataPoint.Value).To(BeNumerically("~", 0.25*float64((8+2*k)%7)+1.5, 1e-9))
+		}
+	})
+
+	It("should fall back to the range queries if the remote read fails or the window is short", func() {
+		var rangeQueries int
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == remoteReadPath {
+				http.Error(w, "remote read is disabled", http.StatusNotFound)
+				return
+			}
+			rangeQueries++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{},"values":[[1700000000,"1"]]}]}}`))
+		}))
+		defer prometheus.Close()
+		scraper, err := NewPrometheusScraper([]string{prometheus.URL}, time.Minute, 24*time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		scraper.WithRemoteRead(time.Hour)
+
+		_, err = scraper.GetAverageCPUUtilizationByWorkload("test-ns", "test-workload", start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rangeQueries).To(Equal(1))
+
+		var remoteReads int
+		healthy := newPrometheus(&remoteReads, &rangeQueries)
+		defer healthy.Close()
+		scraper, err = NewPrometheusScraper([]string{healthy.URL}, time.Minute, 24*time.Hour, 15, 15, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scraper.WithRemoteRead(3*time.Hour).GetAverageCPUUtilizationByWorkload("test-ns", "test-workload",
+			start, end, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remoteReads).To(BeZero())
+		Expect(rangeQueries).To(Equal(2))
+	})
+})
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MetricsRoute routes the queries of the workloads whose namespace and name match its patterns to the scraper of
+// another metrics backend, e.g. a Thanos tenant holding the metrics of some of the namespaces.
+type MetricsRoute struct {
+	namespace *regexp.Regexp
+	workload  *regexp.Regexp
+	scraper   *PrometheusScraper
+}
+
+// NewMetricsRoute returns the route of the workloads matching the patterns to the scraper. The patterns are regular
+// expressions matching the whole namespace and name of the workloads, the empty ones matching all.
+func NewMetricsRoute(namespacePattern, workloadPattern string, scraper *PrometheusScraper) (MetricsRoute, error) {
+	namespace, err := compileRoutePattern(namespacePattern)
+	if err != nil {
+		return MetricsRoute{}, fmt.Errorf("invalid namespace pattern of the metrics route: %v", err)
+	}
+	workload, err := compileRoutePattern(workloadPattern)
+	if err != nil {
+		return MetricsRoute{}, fmt.Errorf("invalid workload pattern of the metrics route: %v", err)
+	}
+	return MetricsRoute{namespace: namespace, workload: workload, scraper: scraper}, nil
+}
+
+func compileRoutePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+func (r MetricsRoute) matches(namespace, workload string) bool {
+	return (r.namespace == nil || r.namespace.MatchString(namespace)) &&
+		(r.workload == nil || r.workload.MatchString(workload))
+}
+
+// metricsBackends are the scraper of the workloads and the routes of the ones scraped from other backends.
+type metricsBackends struct {
+	scraper *PrometheusScraper
+	routes  []MetricsRoute
+}
+
+// scraperFor returns the scraper of the first route matching the workload, or the default scraper if none does.
+func (b *metricsBackends) scraperFor(namespace, workload string) *PrometheusScraper {
+	for _, route := range b.routes {
+		if route.matches(namespace, workload) {
+			return route.scraper
+		}
+	}
+	return b.scraper
+}
@@ -8,12 +8,15 @@ import (
 	"github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"math"
+	"net/http"
 	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 )
 
@@ -97,6 +100,15 @@ type PrometheusScraper struct {
 	metricIngestionTime float64
 	metricProbeTime     float64
 	logger              logr.Logger
+
+	queueQueries map[string]QueueQueries
+
+	queryTemplates map[string]*template.Template
+
+	logQueries bool
+
+	remoteRead          bool
+	remoteReadMinWindow time.Duration
 }
 
 type MetricNameRegistry struct {
@@ -117,12 +129,7 @@ type PrometheusQueryResult struct {
 }
 
 func (ps *PrometheusScraper) GetACLByWorkload(namespace string, workload string) (time.Duration, error) {
-	podBootStrapTime, err := ps.getPodReadyLatencyByWorkload(namespace, workload)
-	if err != nil {
-		return 0.0, fmt.Errorf("error getting pod bootstrap time: %v", err)
-	}
-	totalACL := ps.metricIngestionTime + ps.metricProbeTime + podBootStrapTime
-	return time.Duration(totalACL) * time.Second, nil
+	return ps.GetACLByWorkloadWithStrategy(namespace, workload, ACLStrategyMedian)
 }
 
 func NewKubePrometheusMetricNameRegistry() *MetricNameRegistry {
@@ -151,6 +158,29 @@ func NewKubePrometheusMetricNameRegistry() *MetricNameRegistry {
 type PrometheusInstance struct {
 	apiUrl  v1.API
 	address string
+	// httpClient issues the requests of the remote read protocol, which the API doesn't cover.
+	httpClient *http.Client
+}
+
+// PrometheusAuth are the credentials the Prometheus instances are queried with. The credentials are read from the
+// files on every query, so that the rotated secrets mounted from the files apply without rebuilding the scraper.
+type PrometheusAuth struct {
+	// BearerTokenFile is the file holding the bearer token of the Authorization header.
+	BearerTokenFile string
+	// Username and PasswordFile are the basic auth credentials, used if there's no bearer token.
+	Username     string
+	PasswordFile string
+}
+
+func (a PrometheusAuth) roundTripper() http.RoundTripper {
+	roundTripper := api.DefaultRoundTripper
+	if len(a.BearerTokenFile) > 0 {
+		return config.NewAuthorizationCredentialsFileRoundTripper("Bearer", a.BearerTokenFile, roundTripper)
+	}
+	if len(a.Username) > 0 {
+		return config.NewBasicAuthRoundTripper(a.Username, "", a.PasswordFile, roundTripper)
+	}
+	return roundTripper
 }
 
 // NewPrometheusScraper returns a new PrometheusScraper instance.
@@ -161,12 +191,26 @@ func NewPrometheusScraper(apiUrls []string,
 	metricIngestionTime float64,
 	metricProbeTime float64,
 	logger logr.Logger) (*PrometheusScraper, error) {
+	return NewPrometheusScraperWithAuth(apiUrls, PrometheusAuth{}, timeout, splitInterval, metricIngestionTime,
+		metricProbeTime, logger)
+}
+
+// NewPrometheusScraperWithAuth returns a new PrometheusScraper instance querying the Prometheus instances with the
+// credentials.
+func NewPrometheusScraperWithAuth(apiUrls []string,
+	auth PrometheusAuth,
+	timeout time.Duration,
+	splitInterval time.Duration,
+	metricIngestionTime float64,
+	metricProbeTime float64,
+	logger logr.Logger) (*PrometheusScraper, error) {
 
 	var prometheusInstances []PrometheusInstance
 	for _, pi := range apiUrls {
 		logger.Info("prometheus instance ", "endpoint", pi)
 		client, err := api.NewClient(api.Config{
-			Address: pi,
+			Address:      pi,
+			RoundTripper: auth.roundTripper(),
 		})
 
 		if err != nil {
@@ -174,8 +218,9 @@ func NewPrometheusScraper(apiUrls []string,
 		}
 
 		prometheusInstances = append(prometheusInstances, PrometheusInstance{
-			apiUrl:  v1.NewAPI(client),
-			address: pi,
+			apiUrl:     v1.NewAPI(client),
+			address:    pi,
+			httpClient: &http.Client{Transport: auth.roundTripper()},
 		})
 	}
 
@@ -208,6 +253,12 @@ func (ps *PrometheusScraper) GetAverageCPUUtilizationByWorkload(namespace string
 		ps.metricRegistry.podOwnerMetric,
 		namespace,
 		workload)
+	query, err := ps.templatedQuery(CPUUtilizationDataPointsQuery, QueryTemplateData{Namespace: namespace,
+		Workload: workload}, query)
+	if err != nil {
+		return nil, err
+	}
+	queryID := CPUUtilizationQueryID(namespace, workload, start, end, step)
 
 	var totalDataPoints []DataPoint
 	if ps.api == nil {
@@ -223,8 +274,23 @@ func (ps *PrometheusScraper) GetAverageCPUUtilizationByWorkload(namespace string
 		go func(pi PrometheusInstance) {
 			defer wg.Done()
 
+			if ps.useRemoteRead(start, end) {
+				p8sQueryStartTime := time.Now()
+				dataPoints, err := ps.getAverageCPUUtilizationByRemoteRead(ctx, pi, namespace, workload, start, end, step)
+				if err == nil {
+					p8sQuerySuccessCount.WithLabelValues(RemoteReadDataPointsQuery, pi.address).Inc()
+					logP8sMetrics(p8sQueryStartTime, namespace, RemoteReadDataPointsQuery, pi.address, workload, len(dataPoints), 1)
+					resultChan <- dataPoints
+					return
+				}
+				ps.logger.Error(err, "failed to remote read, falling back to the range queries", "Instance", pi.address)
+				p8sQueryErrorCount.WithLabelValues(RemoteReadDataPointsQuery, pi.address).Inc()
+				logP8sMetrics(p8sQueryStartTime, namespace, RemoteReadDataPointsQuery, pi.address, workload, -1, 0)
+			}
+
 			p8sQueryStartTime := time.Now()
 			result, err := ps.rangeQuerySplitter.QueryRangeByInterval(ctx, pi, query, start, end, step)
+			ps.logQuery(queryID, query, pi.address, start, end, step, p8sQueryStartTime, err)
 
 			if err != nil {
 				ps.logger.Error(err, "failed to execute Prometheus query", "Instance", pi.address)
@@ -246,7 +312,7 @@ func (ps *PrometheusScraper) GetAverageCPUUtilizationByWorkload(namespace string
 				resultChan <- nil
 				return
 			}
-			var dataPoints []DataPoint
+			dataPoints := make([]DataPoint, 0, len(matrix[0].Values))
 			for _, sample := range matrix[0].Values {
 				datapoint := DataPoint{sample.Timestamp.Time(), float64(sample.Value)}
 				if !sample.Timestamp.Time().IsZero() {
@@ -277,7 +343,10 @@ func (ps *PrometheusScraper) GetAverageCPUUtilizationByWorkload(namespace string
 }
 
 func aggregateMetrics(dataPoints1 []DataPoint, dataPoints2 []DataPoint) []DataPoint {
-	var mergedDatapoints []DataPoint
+	if len(dataPoints1) == 0 && len(dataPoints2) == 0 {
+		return nil
+	}
+	mergedDatapoints := make([]DataPoint, 0, len(dataPoints1)+len(dataPoints2))
 	index1, index2 := 0, 0
 
 	for index1 < len(dataPoints1) && index2 < len(dataPoints2) {
@@ -361,6 +430,11 @@ func (ps *PrometheusScraper) GetCPUUtilizationBreachDataPoints(namespace,
 		namespace,
 		workloadType,
 		workload)
+	query, err := ps.templatedQuery(BreachDataPointsQuery, QueryTemplateData{Namespace: namespace, Workload: workload,
+		WorkloadType: workloadType, RedLineUtilization: redLineUtilization}, query)
+	if err != nil {
+		return nil, err
+	}
 
 	resultChanLength := len(ps.api) + 5 //Added some buffer
 	resultChan := make(chan []DataPoint, resultChanLength)
@@ -397,7 +471,7 @@ func (ps *PrometheusScraper) GetCPUUtilizationBreachDataPoints(namespace,
 				resultChan <- nil
 				return
 			}
-			var dataPoints []DataPoint
+			dataPoints := make([]DataPoint, 0, len(matrix[0].Values))
 			for _, sample := range matrix[0].Values {
 				datapoint := DataPoint{sample.Timestamp.Time(), float64(sample.Value)}
 				if !sample.Timestamp.Time().IsZero() {
@@ -525,15 +599,16 @@ func mergeMatrices(matrixA, matrixB model.Matrix) model.Matrix {
 
 	return resultMatrix
 }
-func (ps *PrometheusScraper) getPodReadyLatencyByWorkload(namespace string, workload string) (float64, error) {
+func (ps *PrometheusScraper) getPodReadyLatencyByWorkload(namespace string, workload string, aggregation string) (float64, error) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), ps.queryTimeout)
 	defer cancel()
 
-	query := fmt.Sprintf("quantile(0.5,(%s"+
+	query := fmt.Sprintf("%s(%s"+
 		"{namespace=\"%s\"} - on (namespace,pod) (%s{namespace=\"%s\"}))  * on (namespace,pod) group_left(workload, workload_type)"+
 		"(%s{namespace=\"%s\", workload=\"%s\","+
 		" workload_type=\"deployment\"}))",
+		aggregation,
 		ps.metricRegistry.podReadyTimeMetric,
 		namespace,
 		ps.metricRegistry.podCreatedTimeMetric,
@@ -541,6 +616,11 @@ func (ps *PrometheusScraper) getPodReadyLatencyByWorkload(namespace string, work
 		ps.metricRegistry.podOwnerMetric,
 		namespace,
 		workload)
+	query, err := ps.templatedQuery(PodReadyLatencyQuery, QueryTemplateData{Namespace: namespace, Workload: workload,
+		Aggregation: aggregation}, query)
+	if err != nil {
+		return 0.0, err
+	}
 
 	podBootstrapTime := 0.0
 	if ps.api == nil {
@@ -573,7 +653,8 @@ func (ps *PrometheusScraper) getPodReadyLatencyByWorkload(namespace string, work
 }
 
 func (ps *PrometheusScraper) interpolateMissingDataPoints(dataPoints []DataPoint, step time.Duration) []DataPoint {
-	var interpolatedData []DataPoint
+	expectedDataPoints := int(dataPoints[len(dataPoints)-1].Timestamp.Sub(dataPoints[0].Timestamp)/step) + 1
+	interpolatedData := make([]DataPoint, 0, int(math.Max(float64(len(dataPoints)), float64(expectedDataPoints))))
 	prevTimestamp := dataPoints[0].Timestamp
 	prevValue := dataPoints[0].Value
 
@@ -611,6 +692,15 @@ func logP8sMetrics(p8sQueryStartTime time.Time, namespace string, query string,
 }
 
 func getQueryType(query string) string {
+	if strings.Contains(query, "kafka_") {
+		return KafkaDataPointsQuery
+	}
+	if strings.Contains(query, "container_network_") {
+		return NetworkDataPointsQuery
+	}
+	if strings.Contains(query, "aws_sqs_") || strings.Contains(query, "rabbitmq_") {
+		return QueueDataPointsQuery
+	}
 	if strings.Contains(query, "kube_horizontalpodautoscaler") {
 		return BreachDataPointsQuery
 	}
@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+)
+
+// WebhookChannel POSTs every event as JSON to an HTTP endpoint.
+type WebhookChannel struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewWebhookChannel(url string, timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, c.httpClient, c.url, event)
+}
+
+func (c *WebhookChannel) GetName() string {
+	return "webhook"
+}
+
+// SlackChannel posts every event as a message to a Slack incoming webhook.
+type SlackChannel struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewSlackChannel(url string, timeout time.Duration) *SlackChannel {
+	return &SlackChannel{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (c *SlackChannel) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, c.httpClient, c.url, slackMessage{Text: FormatSlackMessage(event)})
+}
+
+func (c *SlackChannel) GetName() string {
+	return "slack"
+}
+
+// FormatSlackMessage renders the event as a Slack mrkdwn message.
+func FormatSlackMessage(event Event) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s* for %s `%s/%s`", event.Type, event.WorkloadKind, event.Namespace, event.Workload)
+	switch event.Type {
+	case PolicyPromoted, PolicyDemoted, PolicyRolledBack:
+		fmt.Fprintf(&sb, "\nPolicy: `%s` → `%s`", event.OldPolicy, event.Policy)
+	case RecommendationChanged:
+		if len(event.Policy) > 0 {
+			fmt.Fprintf(&sb, "\nPolicy: `%s`", event.Policy)
+		}
+	}
+	if event.OldConfig != nil || event.NewConfig != nil {
+		fmt.Fprintf(&sb, "\nHPA config: %s → %s", formatHPAConfiguration(event.OldConfig), formatHPAConfiguration(event.NewConfig))
+	}
+	if len(event.Message) > 0 {
+		fmt.Fprintf(&sb, "\n%s", event.Message)
+	}
+	return sb.String()
+}
+
+func formatHPAConfiguration(config *v1alpha1.HPAConfiguration) string {
+	if config == nil {
+		return "none"
+	}
+	return fmt.Sprintf("min=%d max=%d %s=%d", config.Min, config.Max, config.GetMetricName(), config.TargetMetricValue)
+}
+
+func postJSON(ctx context.Context, httpClient *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification endpoint responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package notifier
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	notificationsSentCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "notifier_notifications_sent_count",
+			Help: "Number of notifications sent to a channel"}, []string{"channel", "event"},
+	)
+
+	notificationErrorsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "notifier_notification_errors_count",
+			Help: "Number of notifications which couldn't be sent to a channel"}, []string{"channel", "event"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(notificationsSentCounter, notificationErrorsCounter)
+}
+
+// EventType is a transition of a workload the app teams are notified about.
+type EventType string
+
+const (
+	// RecommendationChanged is sent when the HPA configuration to be enforced on a workload changes.
+	RecommendationChanged EventType = "RecommendationChanged"
+	// PolicyPromoted is sent when a workload moves to a policy with a higher risk index.
+	PolicyPromoted EventType = "PolicyPromoted"
+	// PolicyDemoted is sent when a workload moves to a policy with a lower risk index.
+	PolicyDemoted EventType = "PolicyDemoted"
+	// PolicyRolledBack is sent when a workload is moved to a safer policy as its SLOs were breached.
+	PolicyRolledBack EventType = "PolicyRolledBack"
+	// EnforcementFailed is sent when the autoscaler couldn't be created or updated for a workload.
+	EnforcementFailed EventType = "EnforcementFailed"
+)
+
+// Event is a single notification about a workload.
+type Event struct {
+	Timestamp    time.Time                  `json:"timestamp"`
+	Type         EventType                  `json:"type"`
+	Namespace    string                     `json:"namespace"`
+	WorkloadKind string                     `json:"workloadKind"`
+	Workload     string                     `json:"workload"`
+	OldPolicy    string                     `json:"oldPolicy,omitempty"`
+	Policy       string                     `json:"policy,omitempty"`
+	OldConfig    *v1alpha1.HPAConfiguration `json:"oldConfig,omitempty"`
+	NewConfig    *v1alpha1.HPAConfiguration `json:"newConfig,omitempty"`
+	Message      string                     `json:"message,omitempty"`
+}
+
+// Channel delivers the notifications to an external system.
+type Channel interface {
+	Send(ctx context.Context, event Event) error
+	GetName() string
+}
+
+// Notifier notifies the app teams about the transitions of their workloads.
+type Notifier interface {
+	Notify(ctx context.Context, event Event)
+}
+
+// Route sends the notifications of the workloads in the namespaces to the channels.
+type Route struct {
+	Namespaces []string
+	Channels   []Channel
+}
+
+// RoutingNotifier sends every notification to the channels routed for the namespace of the workload, falling
+// back to the default channels. Changes in the recommendation below the threshold and repeats of a notification
+// for a workload within the repeat interval are dropped. Failing to send a notification is logged and doesn't
+// fail the transition being notified.
+type RoutingNotifier struct {
+	defaultChannels        []Channel
+	routes                 map[string][]Channel
+	changeThresholdPercent int
+	repeatInterval         time.Duration
+	lastSentAt             map[string]time.Time
+	mu                     sync.Mutex
+	logger                 logr.Logger
+}
+
+func NewRoutingNotifier(logger logr.Logger, changeThresholdPercent int, repeatInterval time.Duration,
+	defaultChannels []Channel, routes ...Route) *RoutingNotifier {
+	routedChannels := make(map[string][]Channel)
+	for _, route := range routes {
+		for _, namespace := range route.Namespaces {
+			routedChannels[namespace] = append(routedChannels[namespace], route.Channels...)
+		}
+	}
+	return &RoutingNotifier{
+		defaultChannels:        defaultChannels,
+		routes:                 routedChannels,
+		changeThresholdPercent: changeThresholdPercent,
+		repeatInterval:         repeatInterval,
+		lastSentAt:             make(map[string]time.Time),
+		logger:                 logger.WithName("Notifier"),
+	}
+}
+
+func (n *RoutingNotifier) Notify(ctx context.Context, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Type == RecommendationChanged && !IsSignificantChange(event.OldConfig, event.NewConfig, n.changeThresholdPercent) {
+		return
+	}
+	if n.isRepeated(event) {
+		n.logger.V(1).Info("Skipping the repeated notification.", "event", event)
+		return
+	}
+
+	for _, channel := range n.channelsFor(event.Namespace) {
+		if err := channel.Send(ctx, event); err != nil {
+			n.logger.Error(err, "Error sending the notification.", "channel", channel.GetName(), "event", event)
+			notificationErrorsCounter.WithLabelValues(channel.GetName(), string(event.Type)).Inc()
+			continue
+		}
+		notificationsSentCounter.WithLabelValues(channel.GetName(), string(event.Type)).Inc()
+	}
+}
+
+func (n *RoutingNotifier) channelsFor(namespace string) []Channel {
+	if channels, ok := n.routes[namespace]; ok {
+		return channels
+	}
+	return n.defaultChannels
+}
+
+// isRepeated reports whether the same notification was sent for the workload within the repeat interval and
+// records the event as sent otherwise.
+func (n *RoutingNotifier) isRepeated(event Event) bool {
+	if n.repeatInterval <= 0 {
+		return false
+	}
+	key := string(event.Type) + "/" + event.Namespace + "/" + event.WorkloadKind + "/" + event.Workload
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if lastSentAt, ok := n.lastSentAt[key]; ok && event.Timestamp.Sub(lastSentAt) < n.repeatInterval {
+		return true
+	}
+	n.lastSentAt[key] = event.Timestamp
+	return false
+}
+
+// IsSignificantChange reports whether the min replicas, max replicas or the target metric value changed by at
+// least thresholdPercent. Changes in the metric being targeted are always significant.
+func IsSignificantChange(oldConfig, newConfig *v1alpha1.HPAConfiguration, thresholdPercent int) bool {
+	if oldConfig == nil || newConfig == nil {
+		return oldConfig != newConfig
+	}
+	if oldConfig.DeepEquals(*newConfig) {
+		return false
+	}
+	if oldConfig.GetMetricName() != newConfig.GetMetricName() || oldConfig.GetTargetMetricType() != newConfig.GetTargetMetricType() {
+		return true
+	}
+	return percentChange(oldConfig.Min, newConfig.Min) >= float64(thresholdPercent) ||
+		percentChange(oldConfig.Max, newConfig.Max) >= float64(thresholdPercent) ||
+		percentChange(oldConfig.TargetMetricValue, newConfig.TargetMetricValue) >= float64(thresholdPercent)
+}
+
+func percentChange(old, new int) float64 {
+	if old == new {
+		return 0
+	}
+	if old == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(float64(new-old)) * 100 / float64(old)
+}
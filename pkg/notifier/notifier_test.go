@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type recordingChannel struct {
+	name   string
+	events []Event
+	err    error
+}
+
+func (c *recordingChannel) Send(_ context.Context, event Event) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.events = append(c.events, event)
+	return nil
+}
+
+func (c *recordingChannel) GetName() string {
+	return c.name
+}
+
+var _ = Describe("RoutingNotifier", func() {
+	var (
+		defaultChannel  *recordingChannel
+		teamChannel     *recordingChannel
+		routingNotifier *RoutingNotifier
+	)
+
+	BeforeEach(func() {
+		defaultChannel = &recordingChannel{name: "default"}
+		teamChannel = &recordingChannel{name: "team"}
+		routingNotifier = NewRoutingNotifier(logr.Discard(), 20, time.Hour, []Channel{defaultChannel},
+			Route{Namespaces: []string{"team-ns"}, Channels: []Channel{teamChannel}})
+	})
+
+	It("should route the events to the channels of the namespace", func() {
+		routingNotifier.Notify(context.TODO(), Event{Type: PolicyPromoted, Namespace: "team-ns", Workload: "app"})
+		routingNotifier.Notify(context.TODO(), Event{Type: PolicyPromoted, Namespace: "other-ns", Workload: "app"})
+
+		Expect(teamChannel.events).To(HaveLen(1))
+		Expect(teamChannel.events[0].Namespace).To(Equal("team-ns"))
+		Expect(teamChannel.events[0].Timestamp.IsZero()).To(BeFalse())
+		Expect(defaultChannel.events).To(HaveLen(1))
+		Expect(defaultChannel.events[0].Namespace).To(Equal("other-ns"))
+	})
+
+	It("should drop the changes in the recommendation below the threshold", func() {
+		routingNotifier.Notify(context.TODO(), Event{Type: RecommendationChanged, Namespace: "ns", Workload: "app1",
+			OldConfig: &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50},
+			NewConfig: &v1alpha1.HPAConfiguration{Min: 9, Max: 20, TargetMetricValue: 55}})
+		Expect(defaultChannel.events).To(BeEmpty())
+
+		routingNotifier.Notify(context.TODO(), Event{Type: RecommendationChanged, Namespace: "ns", Workload: "app2",
+			OldConfig: &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50},
+			NewConfig: &v1alpha1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 50}})
+		Expect(defaultChannel.events).To(HaveLen(1))
+	})
+
+	It("should suppress the repeated events within the repeat interval", func() {
+		now := time.Now()
+		event := Event{Type: EnforcementFailed, Namespace: "ns", Workload: "app", Timestamp: now}
+		routingNotifier.Notify(context.TODO(), event)
+		event.Timestamp = now.Add(30 * time.Minute)
+		routingNotifier.Notify(context.TODO(), event)
+		Expect(defaultChannel.events).To(HaveLen(1))
+
+		event.Timestamp = now.Add(2 * time.Hour)
+		routingNotifier.Notify(context.TODO(), event)
+		Expect(defaultChannel.events).To(HaveLen(2))
+	})
+
+	It("should keep notifying the other channels when a channel fails", func() {
+		failingChannel := &recordingChannel{name: "failing", err: errors.New("unavailable")}
+		routingNotifier = NewRoutingNotifier(logr.Discard(), 20, 0, []Channel{failingChannel, defaultChannel})
+		routingNotifier.Notify(context.TODO(), Event{Type: PolicyDemoted, Namespace: "ns", Workload: "app"})
+		Expect(defaultChannel.events).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("IsSignificantChange", func() {
+	It("should compare the change in min, max and target against the threshold", func() {
+		oldConfig := &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50}
+		Expect(IsSignificantChange(oldConfig, &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50}, 0)).To(BeFalse())
+		Expect(IsSignificantChange(oldConfig, &v1alpha1.HPAConfiguration{Min: 11, Max: 20, TargetMetricValue: 50}, 20)).To(BeFalse())
+		Expect(IsSignificantChange(oldConfig, &v1alpha1.HPAConfiguration{Min: 10, Max: 30, TargetMetricValue: 50}, 20)).To(BeTrue())
+		Expect(IsSignificantChange(oldConfig, &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 60}, 20)).To(BeTrue())
+		Expect(IsSignificantChange(oldConfig, &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 50,
+			MetricName: "memory"}, 20)).To(BeTrue())
+		Expect(IsSignificantChange(&v1alpha1.HPAConfiguration{}, oldConfig, 20)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Channels", func() {
+	var (
+		server   *httptest.Server
+		payloads []map[string]interface{}
+		status   int
+	)
+
+	BeforeEach(func() {
+		payloads = nil
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload := map[string]interface{}{}
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			payloads = append(payloads, payload)
+			w.WriteHeader(status)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	event := Event{Type: PolicyRolledBack, Namespace: "ns", WorkloadKind: "Deployment", Workload: "app",
+		OldPolicy: "aggressive", Policy: "safest",
+		OldConfig: &v1alpha1.HPAConfiguration{Min: 5, Max: 20, TargetMetricValue: 70},
+		NewConfig: &v1alpha1.HPAConfiguration{Min: 20, Max: 20, TargetMetricValue: 40}}
+
+	It("should post the event as JSON to the webhook", func() {
+		Expect(NewWebhookChannel(server.URL, time.Second).Send(context.TODO(), event)).To(Succeed())
+		Expect(payloads).To(HaveLen(1))
+		Expect(payloads[0]).To(HaveKeyWithValue("type", string(PolicyRolledBack)))
+		Expect(payloads[0]).To(HaveKeyWithValue("workload", "app"))
+	})
+
+	It("should post the formatted message to Slack", func() {
+		Expect(NewSlackChannel(server.URL, time.Second).Send(context.TODO(), event)).To(Succeed())
+		Expect(payloads).To(HaveLen(1))
+		Expect(payloads[0]["text"]).To(Equal("*PolicyRolledBack* for Deployment `ns/app`\nPolicy: `aggressive` → `safest`\n" +
+			"HPA config: min=5 max=20 cpu=70 → min=20 max=20 cpu=40"))
+	})
+
+	It("should fail on a non 2xx response", func() {
+		status = http.StatusInternalServerError
+		Expect(NewWebhookChannel(server.URL, time.Second).Send(context.TODO(), event)).NotTo(Succeed())
+	})
+})
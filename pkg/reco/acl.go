@@ -0,0 +1,77 @@
+package reco
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ACLStrategyAnnotation overrides the ACL strategy of the recommender for the workload, e.g. "p99" for a workload
+// whose slowest pods take much longer to get ready than the typical ones.
+const ACLStrategyAnnotation = "ottoscalr.io/acl-strategy"
+
+var (
+	recommendationACLGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "recommendation_acl_seconds",
+			Help: "ACL the last recommendation of the workload was simulated with"},
+		[]string{"namespace", "workload", "strategy"},
+	)
+)
+
+func init() {
+	registerCollectors(recommendationACLGauge)
+}
+
+// WithACLStrategy makes the recommender simulate the workloads with their ACL scraped by the scraper with the pod
+// ready latencies aggregated by the strategy, unless a workload overrides it with the ACLStrategyAnnotation.
+func (c *CpuUtilizationBasedRecommender) WithACLStrategy(scraper metrics.ACLScraper,
+	strategy string) *CpuUtilizationBasedRecommender {
+	c.aclScraper = scraper
+	c.aclStrategy = strategy
+	return c
+}
+
+// workloadACL returns the ACL of the workload along with the strategy it was aggregated by, which is empty if the
+// recommender doesn't have an ACL strategy.
+func (c *CpuUtilizationBasedRecommender) workloadACL(workloadMeta WorkloadMeta) (time.Duration, string, error) {
+	if c.aclScraper == nil {
+		acl, err := c.scraper.GetACLByWorkload(workloadMeta.Namespace, workloadMeta.Name)
+		return acl, "", err
+	}
+	strategy, err := c.aclStrategyOf(workloadMeta)
+	if err != nil {
+		return 0, "", err
+	}
+	acl, err := c.aclScraper.GetACLByWorkloadWithStrategy(workloadMeta.Namespace, workloadMeta.Name, strategy)
+	return acl, strategy, err
+}
+
+// aclStrategyOf returns the ACL strategy of the annotation of the workload, or the one of the recommender if the
+// workload isn't annotated.
+func (c *CpuUtilizationBasedRecommender) aclStrategyOf(workloadMeta WorkloadMeta) (string, error) {
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return "", err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return "", err
+	}
+	strategy, ok := workload.GetAnnotations()[ACLStrategyAnnotation]
+	if !ok {
+		return c.aclStrategy, nil
+	}
+	if err := metrics.ValidateACLStrategy(strategy); err != nil {
+		return "", fmt.Errorf("invalid %s annotation of the workload %s/%s: %v", ACLStrategyAnnotation,
+			workloadMeta.Namespace, workloadMeta.Name, err)
+	}
+	return strategy, nil
+}
+
+func logRecommendationACL(workloadMeta WorkloadMeta, acl time.Duration, strategy string) {
+	recommendationACLGauge.DeletePartialMatch(prometheus.Labels{"namespace": workloadMeta.Namespace,
+		"workload": workloadMeta.Name})
+	recommendationACLGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name, strategy).Set(acl.Seconds())
+}
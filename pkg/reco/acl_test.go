@@ -0,0 +1,64 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// strategyACLScraper returns the ACL of every workload by the strategy.
+type strategyACLScraper map[string]time.Duration
+
+func (s strategyACLScraper) GetACLByWorkloadWithStrategy(namespace, workload, strategy string) (time.Duration, error) {
+	return s[strategy], nil
+}
+
+var _ = Describe("ACL strategy", func() {
+	var aclRecommender *CpuUtilizationBasedRecommender
+
+	newDeployment := func(name string, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "acl-ns", Annotations: annotations}}
+	}
+
+	BeforeEach(func() {
+		fakeScheme := runtime.NewScheme()
+		Expect(appsv1.AddToScheme(fakeScheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			newDeployment("typical", nil),
+			newDeployment("slow", map[string]string{ACLStrategyAnnotation: metrics.ACLStrategyP99}),
+			newDeployment("invalid", map[string]string{ACLStrategyAnnotation: "p75"}),
+		).Build()
+		aclRecommender = (&CpuUtilizationBasedRecommender{logger: logr.Discard(),
+			clientsRegistry: registry.DeploymentClientRegistry{Clients: []registry.ObjectClient{registry.NewDeploymentClient(fakeClient)}},
+		}).WithACLStrategy(strategyACLScraper{metrics.ACLStrategyP90: 90 * time.Second, metrics.ACLStrategyP99: 3 * time.Minute},
+			metrics.ACLStrategyP90)
+	})
+
+	It("should scrape the ACL by the strategy of the recommender", func() {
+		acl, strategy, err := aclRecommender.workloadACL(WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"},
+			Name: "typical", Namespace: "acl-ns"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy).To(Equal(metrics.ACLStrategyP90))
+		Expect(acl).To(Equal(90 * time.Second))
+	})
+
+	It("should scrape the ACL by the strategy of the annotation of the workload", func() {
+		acl, strategy, err := aclRecommender.workloadACL(WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"},
+			Name: "slow", Namespace: "acl-ns"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy).To(Equal(metrics.ACLStrategyP99))
+		Expect(acl).To(Equal(3 * time.Minute))
+
+		_, _, err = aclRecommender.workloadACL(WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"},
+			Name: "invalid", Namespace: "acl-ns"})
+		Expect(err).To(HaveOccurred())
+	})
+})
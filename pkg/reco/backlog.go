@@ -0,0 +1,87 @@
+package reco
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+type backlogRecommendation struct {
+	config            *v1alpha1.HPAConfiguration
+	replicaThroughput float64
+	savingsPercent    int
+}
+
+// recommendBacklogConfig recommends the config keeping the backlog of the consumers, e.g. the lag of a kafka consumer
+// group or the depth of a queue, under the target backlog. The throughput of a replica is estimated from the datapoints
+// where the backlog was at least the target backlog, i.e. the replicas were consuming as fast as they could, or from
+// all the datapoints if it never was. The replicas needed at a datapoint are those consuming the messages produced
+// then, which are the messages consumed plus the growth of the backlog. The backlog threshold of a replica is such
+// that the consumers scale out to the replicas needed at the peak before the backlog crosses the target.
+func recommendBacklogConfig(backlog, consumeRate, pods []metrics.DataPoint, step time.Duration, targetBacklog,
+	maxReplicas int, metricName string) (*backlogRecommendation, error) {
+	if maxReplicas <= 0 {
+		return nil, errors.New("no replicas to recommend")
+	}
+	consumeRateAt := dataPointsByTimestamp(consumeRate)
+	podsAt := dataPointsByTimestamp(pods)
+
+	saturatedThroughput, throughput := 0.0, 0.0
+	for _, dp := range backlog {
+		rate, ok := consumeRateAt[dp.Timestamp.Unix()]
+		replicas := podsAt[dp.Timestamp.Unix()]
+		if !ok || replicas <= 0 {
+			continue
+		}
+		throughput = math.Max(throughput, rate/replicas)
+		if dp.Value >= float64(targetBacklog) {
+			saturatedThroughput = math.Max(saturatedThroughput, rate/replicas)
+		}
+	}
+	if saturatedThroughput > 0 {
+		throughput = saturatedThroughput
+	}
+	if throughput <= 0 {
+		return nil, errors.New("no messages consumed in the metric window")
+	}
+
+	minReplicas, peakReplicas, totalReplicas, samples := maxReplicas, 0, 0, 0
+	for i := 1; i < len(backlog); i++ {
+		rate, ok := consumeRateAt[backlog[i].Timestamp.Unix()]
+		if !ok {
+			continue
+		}
+		produceRate := math.Max(rate+(backlog[i].Value-backlog[i-1].Value)/step.Seconds(), 0)
+		replicas := int(math.Min(math.Max(math.Ceil(produceRate/throughput), 1), float64(maxReplicas)))
+		minReplicas = int(math.Min(float64(minReplicas), float64(replicas)))
+		peakReplicas = int(math.Max(float64(peakReplicas), float64(replicas)))
+		totalReplicas += replicas
+		samples++
+	}
+	if samples == 0 {
+		return nil, errors.New("no backlog and consume rate datapoints in the metric window")
+	}
+
+	return &backlogRecommendation{
+		config: &v1alpha1.HPAConfiguration{
+			Min:               minReplicas,
+			Max:               maxReplicas,
+			TargetMetricValue: int(math.Max(float64(targetBacklog/peakReplicas), 1)),
+			MetricName:        metricName,
+			TargetMetricType:  v1alpha1.AverageValueMetricTarget,
+		},
+		replicaThroughput: throughput,
+		savingsPercent:    100 - totalReplicas*100/(samples*maxReplicas),
+	}, nil
+}
+
+func dataPointsByTimestamp(dataPoints []metrics.DataPoint) map[int64]float64 {
+	values := make(map[int64]float64, len(dataPoints))
+	for _, dp := range dataPoints {
+		values[dp.Timestamp.Unix()] = dp.Value
+	}
+	return values
+}
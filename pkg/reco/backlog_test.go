@@ -0,0 +1,61 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backlog based recommendations", func() {
+	end := time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC)
+	dataPoints := func(values ...float64) []metrics.DataPoint {
+		var dps []metrics.DataPoint
+		for i, value := range values {
+			dps = append(dps, metrics.DataPoint{Timestamp: end.Add(time.Duration(i-len(values)+1) * time.Minute), Value: value})
+		}
+		return dps
+	}
+
+	It("should recommend the replicas consuming the messages produced at the peak", func() {
+		recommendation, err := recommendBacklogConfig(dataPoints(0, 1200, 1200, 0), dataPoints(100, 200, 200, 100),
+			dataPoints(2, 2, 2, 2), time.Minute, 1000, 10, autoscaler.KafkaLagMetricName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recommendation.replicaThroughput).To(Equal(100.0))
+		Expect(recommendation.config).To(Equal(&v1alpha1.HPAConfiguration{
+			Min:               1,
+			Max:               10,
+			TargetMetricValue: 333,
+			MetricName:        autoscaler.KafkaLagMetricName,
+			TargetMetricType:  v1alpha1.AverageValueMetricTarget,
+		}))
+		Expect(recommendation.savingsPercent).To(Equal(80))
+	})
+
+	It("should estimate the throughput of a replica from the saturated datapoints", func() {
+		// the replicas consumed 150 messages per second each only while they were idle after the lag was cleared
+		recommendation, err := recommendBacklogConfig(dataPoints(2000, 2000, 0), dataPoints(100, 100, 300),
+			dataPoints(2, 2, 2), time.Minute, 1000, 10, autoscaler.KafkaLagMetricName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recommendation.replicaThroughput).To(Equal(50.0))
+	})
+
+	It("should cap the recommended replicas at the max replicas", func() {
+		recommendation, err := recommendBacklogConfig(dataPoints(0, 60000), dataPoints(100, 100),
+			dataPoints(1, 1), time.Minute, 1000, 4, autoscaler.SQSQueueMetricName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recommendation.config.Min).To(Equal(4))
+		Expect(recommendation.config.Max).To(Equal(4))
+		Expect(recommendation.config.TargetMetricValue).To(Equal(250))
+		Expect(recommendation.config.MetricName).To(Equal(autoscaler.SQSQueueMetricName))
+	})
+
+	It("should not recommend the consumers which consumed nothing", func() {
+		_, err := recommendBacklogConfig(dataPoints(0, 0), dataPoints(0, 0), dataPoints(2, 2), time.Minute, 1000, 10,
+			autoscaler.KafkaLagMetricName)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,40 @@
+package reco
+
+import (
+	"time"
+)
+
+// WithMinWorkloadAge makes the recommender recommend the no-op configuration for the workloads younger than the age,
+// whose metrics don't tell their steady state traffic yet, and mark their recommendations as having insufficient
+// history.
+func (c *CpuUtilizationBasedRecommender) WithMinWorkloadAge(age time.Duration) *CpuUtilizationBasedRecommender {
+	c.minWorkloadAge = age
+	return c
+}
+
+// workloadCreatedAt returns the creation timestamp of the workload.
+func (c *CpuUtilizationBasedRecommender) workloadCreatedAt(workloadMeta WorkloadMeta) (time.Time, error) {
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return time.Time{}, err
+	}
+	object, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return object.GetCreationTimestamp().Time, nil
+}
+
+// hasInsufficientHistory returns the age of a workload created at createdAt along with whether it's younger than the
+// min workload age. The workloads without a creation timestamp are taken to have enough history.
+func (c *CpuUtilizationBasedRecommender) hasInsufficientHistory(createdAt, now time.Time) (time.Duration, bool) {
+	if createdAt.IsZero() {
+		return 0, false
+	}
+	// the creation timestamps ahead of now are due to clock skews
+	age := time.Duration(0)
+	if now.After(createdAt) {
+		age = now.Sub(createdAt)
+	}
+	return age, age < c.minWorkloadAge
+}
@@ -0,0 +1,41 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("hasInsufficientHistory", func() {
+	var (
+		bootstrapRecommender *CpuUtilizationBasedRecommender
+		now                  = time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		bootstrapRecommender = (&CpuUtilizationBasedRecommender{logger: logr.Discard()}).WithMinWorkloadAge(7 * 24 * time.Hour)
+	})
+
+	It("should flag the workloads younger than the min workload age", func() {
+		age, insufficient := bootstrapRecommender.hasInsufficientHistory(now.Add(-3*24*time.Hour), now)
+		Expect(age).To(Equal(3 * 24 * time.Hour))
+		Expect(insufficient).To(BeTrue())
+
+		age, insufficient = bootstrapRecommender.hasInsufficientHistory(now.Add(-7*24*time.Hour), now)
+		Expect(age).To(Equal(7 * 24 * time.Hour))
+		Expect(insufficient).To(BeFalse())
+	})
+
+	It("should take the creation timestamps ahead of now as just created", func() {
+		age, insufficient := bootstrapRecommender.hasInsufficientHistory(now.Add(time.Minute), now)
+		Expect(age).To(BeZero())
+		Expect(insufficient).To(BeTrue())
+	})
+
+	It("should not flag the workloads without a creation timestamp", func() {
+		_, insufficient := bootstrapRecommender.hasInsufficientHistory(time.Time{}, now)
+		Expect(insufficient).To(BeFalse())
+	})
+})
@@ -12,19 +12,17 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
-	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
-	breachGauge = promauto.NewGaugeVec(
+	breachGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{Name: "breachanalyzer_breached",
 			Help: "Number of breaches counter"}, []string{"namespace", "policyreco", "workloadKind", "workload"},
 	)
 )
 
 func init() {
-	p8smetrics.Registry.MustRegister(breachGauge)
+	registerCollectors(breachGauge)
 }
 
 type BreachAnalyzer struct {
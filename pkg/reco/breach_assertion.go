@@ -0,0 +1,94 @@
+package reco
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BreachAssertionAnnotation defines the breaches of the workload as a PromQL assertion on its user-facing SLOs, e.g.
+// `histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket{app="checkout"}[5m])) by (le)) > 0.3` for a
+// p99 latency above 300ms, along with the cpu redline. The workload breached wherever the assertion returns any
+// sample.
+const BreachAssertionAnnotation = "ottoscalr.io/breach-assertion"
+
+var (
+	sloBreachDataPointsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "slo_breach_datapoints",
+			Help: "Number of the datapoints of the workload at which its breach assertion held below its max replicas"},
+		[]string{"namespace", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(sloBreachDataPointsGauge)
+}
+
+// WithBreachAssertions makes the recommender evaluate the BreachAssertionAnnotation of the workloads over their
+// metrics window, so that the configs recommended to them run more replicas than the workloads did wherever the
+// assertion held, and not only keep them below the cpu redline.
+func (c *CpuUtilizationBasedRecommender) WithBreachAssertions(
+	scraper metrics.BreachAssertionScraper) *CpuUtilizationBasedRecommender {
+	c.breachAssertionScraper = scraper
+	return c
+}
+
+// workloadBreachAssertion returns the breach assertion of the annotation of the workload, or "" if the workload isn't
+// annotated.
+func (c *CpuUtilizationBasedRecommender) workloadBreachAssertion(workloadMeta WorkloadMeta) (string, error) {
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return "", err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return "", err
+	}
+	value, ok := workload.GetAnnotations()[BreachAssertionAnnotation]
+	if !ok {
+		return "", nil
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("invalid %s annotation of the workload %s/%s: empty assertion", BreachAssertionAnnotation,
+			workloadMeta.Namespace, workloadMeta.Name)
+	}
+	return value, nil
+}
+
+// sloDemand returns the capacity the simulated HPA has to provide at every datapoint for the workload not to breach
+// its breach assertion, which is the demand or, at the datapoints the assertion held at, the capacity of one more
+// replica than the workload ran at then. The pods of a datapoint are the latest ones at or before it and the
+// assertion held at a datapoint if it did within the metric step before it. The assertion holding at the max replicas
+// can't be helped by autoscaling, so it's left to the demand. It also returns the number of the datapoints the
+// assertion raised the demand at.
+func (c *CpuUtilizationBasedRecommender) sloDemand(demand []metrics.DataPoint, violations []time.Time,
+	pods []metrics.DataPoint, perPodResources float64, maxReplicas int) ([]metrics.DataPoint, int) {
+	raised := make([]metrics.DataPoint, len(demand))
+	breaches := 0
+	i, j := -1, -1
+	for k, dp := range demand {
+		raised[k] = dp
+		for i+1 < len(violations) && !violations[i+1].After(dp.Timestamp) {
+			i++
+		}
+		for j+1 < len(pods) && !pods[j+1].Timestamp.After(dp.Timestamp) {
+			j++
+		}
+		if i < 0 || j < 0 || !violations[i].After(dp.Timestamp.Add(-c.metricStep)) {
+			continue
+		}
+		replicas := int(pods[j].Value)
+		if replicas >= maxReplicas {
+			continue
+		}
+		// the simulated capacity of the ready replicas is their resources at the red line utilization
+		if sloCapacity := float64(replicas+1) * perPodResources * c.redLineUtil; sloCapacity > dp.Value {
+			raised[k].Value = sloCapacity
+			breaches++
+		}
+	}
+	return raised, breaches
+}
@@ -0,0 +1,78 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Breach assertions", func() {
+	var (
+		sloRecommender *CpuUtilizationBasedRecommender
+		end            = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	series := func(values ...float64) []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for i, value := range values {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: end.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+		return dataPoints
+	}
+	at := func(minutes ...int) []time.Time {
+		var timestamps []time.Time
+		for _, m := range minutes {
+			timestamps = append(timestamps, end.Add(time.Duration(m)*time.Minute))
+		}
+		return timestamps
+	}
+
+	BeforeEach(func() {
+		sloRecommender = (&CpuUtilizationBasedRecommender{redLineUtil: 0.5, metricStep: time.Minute,
+			logger: logr.Discard()}).WithBreachAssertions(nil)
+	})
+
+	It("should raise the demand to one more replica than the workload ran at where the assertion held", func() {
+		demand, breaches := sloRecommender.sloDemand(series(1, 1, 1, 1), at(1, 3), series(2, 2, 4, 4), 1, 10)
+		Expect(demand).To(Equal(series(1, 1.5, 1, 2.5)))
+		Expect(breaches).To(Equal(2))
+	})
+
+	It("should leave the demand of the datapoints at the max replicas or already above the raised capacity", func() {
+		demand, breaches := sloRecommender.sloDemand(series(1, 3, 1), at(0, 1, 2), series(2, 2, 4), 1, 4)
+		Expect(demand).To(Equal(series(1.5, 3, 1)))
+		Expect(breaches).To(Equal(1))
+	})
+
+	It("should match the assertion to the datapoints within the metric step after it", func() {
+		demand, _ := sloRecommender.sloDemand(series(1, 1, 1), []time.Time{end.Add(30 * time.Second)}, series(2, 2, 2), 1, 10)
+		Expect(demand).To(Equal(series(1, 1.5, 1)))
+	})
+
+	It("should recommend the configs running more replicas than the workload did where its SLOs were breached", func() {
+		var cpu, pods []float64
+		for i := 0; i < 120; i++ {
+			cpu = append(cpu, 2)
+			pods = append(pods, 5)
+		}
+		dataPoints := series(cpu...)
+		var violations []int
+		for i := 0; i < 120; i++ {
+			violations = append(violations, i)
+		}
+		demand, _ := sloRecommender.sloDemand(dataPoints, at(violations...), series(pods...), 1, 10)
+		target, minReplicas, maxReplicas, err := sloRecommender.findOptimalProfiledHPAConfigurations(dataPoints,
+			trafficProfile{demand: demand}, 0, 10, 60, 1, 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		simulated, _, err := sloRecommender.simulateHPA(dataPoints, 0, target, 1, maxReplicas, minReplicas)
+		Expect(err).NotTo(HaveOccurred())
+		for _, dp := range simulated {
+			// 6 ready replicas of 1 cpu at the red line run more replicas than the 5 the SLOs were breached at
+			Expect(dp.Value).To(BeNumerically(">=", 3))
+		}
+	})
+})
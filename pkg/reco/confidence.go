@@ -0,0 +1,80 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+const (
+	// confidenceFullWindow is the metrics window which covers the weekly patterns of the traffic of a workload.
+	confidenceFullWindow = 7 * 24 * time.Hour
+	// confidenceHeadroomMargin is the margin below the redline within which the simulated utilization lowers the
+	// confidence of a recommendation.
+	confidenceHeadroomMargin = 0.1
+
+	coverageWeight  = 0.3
+	windowWeight    = 0.2
+	stabilityWeight = 0.25
+	headroomWeight  = 0.25
+)
+
+// RecommendationConfidence is how far a recommendation can be relied upon. The factors are in [0, 1] and the score is
+// their weighted average as a percentage.
+type RecommendationConfidence struct {
+	Score int `json:"score"`
+	// Coverage is the fraction of the expected datapoints of the metrics window which were available.
+	Coverage float64 `json:"coverage"`
+	// Window is the length of the metrics window relative to a week, which covers the weekly patterns of the traffic.
+	Window float64 `json:"window"`
+	// Stability falls with the coefficient of variation of the metrics.
+	Stability float64 `json:"stability"`
+	// Headroom falls as the simulated utilization of the recommended config comes within 10% of the redline.
+	Headroom float64 `json:"headroom"`
+}
+
+// newRecommendationConfidence returns the confidence of a recommendation generated from the datapoints of the metrics
+// window, whose config was simulated to have the simulated resources at the redline for the demand.
+func newRecommendationConfidence(coveragePercent int, metricWindow time.Duration, dataPoints, demand,
+	simulated []metrics.DataPoint) *RecommendationConfidence {
+	confidence := &RecommendationConfidence{
+		Coverage:  math.Min(math.Max(float64(coveragePercent)/100, 0), 1),
+		Window:    math.Min(float64(metricWindow)/float64(confidenceFullWindow), 1),
+		Stability: 1 / (1 + coefficientOfVariation(dataPoints)),
+		Headroom:  1,
+	}
+	closest := 0.0
+	for i := range demand {
+		if i < len(simulated) && simulated[i].Value > 0 {
+			closest = math.Max(closest, demand[i].Value/simulated[i].Value)
+		}
+	}
+	if len(simulated) > 0 {
+		confidence.Headroom = math.Min(math.Max((1-closest)/confidenceHeadroomMargin, 0), 1)
+	}
+	confidence.Score = int(math.Round(100 * (coverageWeight*confidence.Coverage + windowWeight*confidence.Window +
+		stabilityWeight*confidence.Stability + headroomWeight*confidence.Headroom)))
+	return confidence
+}
+
+// coefficientOfVariation returns the standard deviation of the datapoints relative to their mean, or 0 if their mean
+// is 0.
+func coefficientOfVariation(dataPoints []metrics.DataPoint) float64 {
+	if len(dataPoints) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, dp := range dataPoints {
+		sum += dp.Value
+	}
+	mean := sum / float64(len(dataPoints))
+	if mean <= 0 {
+		return 0
+	}
+	variance := 0.0
+	for _, dp := range dataPoints {
+		variance += (dp.Value - mean) * (dp.Value - mean)
+	}
+	return math.Sqrt(variance/float64(len(dataPoints))) / mean
+}
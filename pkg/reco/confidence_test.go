@@ -0,0 +1,45 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RecommendationConfidence", func() {
+	dataPointsOf := func(values ...float64) []metrics.DataPoint {
+		dataPoints := make([]metrics.DataPoint, len(values))
+		for i, value := range values {
+			dataPoints[i] = metrics.DataPoint{Timestamp: time.Unix(int64(60*i), 0), Value: value}
+		}
+		return dataPoints
+	}
+
+	It("should be fully confident of a steady workload with a week of metrics and room below the redline", func() {
+		dataPoints := dataPointsOf(10, 10, 10, 10)
+		confidence := newRecommendationConfidence(100, 7*24*time.Hour, dataPoints, dataPoints, dataPointsOf(20, 20, 20, 20))
+		Expect(confidence.Coverage).To(Equal(1.0))
+		Expect(confidence.Window).To(Equal(1.0))
+		Expect(confidence.Stability).To(Equal(1.0))
+		Expect(confidence.Headroom).To(Equal(1.0))
+		Expect(confidence.Score).To(Equal(100))
+	})
+
+	It("should be less confident of the recommendations from fewer, shorter and noisier metrics closer to the redline", func() {
+		dataPoints := dataPointsOf(5, 15, 5, 15)
+		confidence := newRecommendationConfidence(50, 42*time.Hour, dataPoints, dataPoints, dataPointsOf(16, 16, 16, 16))
+		Expect(confidence.Coverage).To(Equal(0.5))
+		Expect(confidence.Window).To(Equal(0.25))
+		Expect(confidence.Stability).To(BeNumerically("~", 0.667, 0.001))
+		Expect(confidence.Headroom).To(BeNumerically("~", 0.625, 0.001))
+		Expect(confidence.Score).To(Equal(52))
+	})
+
+	It("should have no headroom once the demand reaches the redline", func() {
+		dataPoints := dataPointsOf(10, 20)
+		confidence := newRecommendationConfidence(100, 7*24*time.Hour, dataPoints, dataPoints, dataPointsOf(20, 20))
+		Expect(confidence.Headroom).To(BeZero())
+	})
+})
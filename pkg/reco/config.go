@@ -0,0 +1,132 @@
+package reco
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WorkloadConfig is the OttoscalrConfig of the namespace of a workload, which overrides the defaults of the controller
+// for its recommendation. The zero values keep the defaults.
+type WorkloadConfig struct {
+	// Name is the name of the OttoscalrConfig.
+	Name                string
+	MetricWindow        time.Duration
+	MinTarget           int
+	MaxTarget           int
+	RedLineUtilization  float64
+	MinRequiredReplicas *int
+	EnforcementMode     v1beta1.EnforcementMode
+	// MinCapacityReservation is the least aggregate cpu capacity, in cores, the enforced configurations must keep in
+	// the namespace.
+	MinCapacityReservation float64
+	// ScaledObjectTemplate overrides the template of the ScaledObjects generated for the workloads.
+	ScaledObjectTemplate *autoscaler.ScaledObjectTemplate
+}
+
+type workloadConfigKey struct{}
+
+// ContextWithWorkloadConfig returns a copy of the context carrying the config of the workload for the recommenders.
+func ContextWithWorkloadConfig(ctx context.Context, config *WorkloadConfig) context.Context {
+	return context.WithValue(ctx, workloadConfigKey{}, config)
+}
+
+// WorkloadConfigFromContext returns the config of the workload carried by the context, or nil if there's none.
+func WorkloadConfigFromContext(ctx context.Context) *WorkloadConfig {
+	config, _ := ctx.Value(workloadConfigKey{}).(*WorkloadConfig)
+	return config
+}
+
+// ConfigResolver resolves the OttoscalrConfig of the namespaces of the workloads at the time they are recommended,
+// so that the changes of the configs apply to the next recommendations without restarting the controller.
+type ConfigResolver struct {
+	k8sClient client.Client
+}
+
+func NewConfigResolver(k8sClient client.Client) *ConfigResolver {
+	return &ConfigResolver{k8sClient: k8sClient}
+}
+
+// Resolve returns the config of the namespace, or nil if it doesn't have any. The oldest config is used if the
+// namespace has more than one.
+func (r *ConfigResolver) Resolve(ctx context.Context, namespace string) (*WorkloadConfig, error) {
+	configList := &v1beta1.OttoscalrConfigList{}
+	if err := r.k8sClient.List(ctx, configList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if len(configList.Items) == 0 {
+		return nil, nil
+	}
+	configs := configList.Items
+	sort.Slice(configs, func(i, j int) bool {
+		if !configs[i].CreationTimestamp.Equal(&configs[j].CreationTimestamp) {
+			return configs[i].CreationTimestamp.Before(&configs[j].CreationTimestamp)
+		}
+		return configs[i].Name < configs[j].Name
+	})
+	return newWorkloadConfig(configs[0]), nil
+}
+
+func newWorkloadConfig(ottoscalrConfig v1beta1.OttoscalrConfig) *WorkloadConfig {
+	spec := ottoscalrConfig.Spec
+	config := &WorkloadConfig{
+		Name:                ottoscalrConfig.Name,
+		MinRequiredReplicas: spec.MinRequiredReplicas,
+		EnforcementMode:     spec.EnforcementMode,
+	}
+	if spec.MetricWindowInDays != nil {
+		config.MetricWindow = time.Duration(*spec.MetricWindowInDays) * 24 * time.Hour
+	}
+	if spec.MinTarget != nil {
+		config.MinTarget = *spec.MinTarget
+	}
+	if spec.MaxTarget != nil {
+		config.MaxTarget = *spec.MaxTarget
+	}
+	if spec.RedLineUtilizationPercent != nil {
+		config.RedLineUtilization = float64(*spec.RedLineUtilizationPercent) / 100
+	}
+	if spec.MinCapacityReservation != nil {
+		config.MinCapacityReservation = spec.MinCapacityReservation.AsApproximateFloat64()
+	}
+	if template := spec.ScaledObject; template != nil {
+		config.ScaledObjectTemplate = &autoscaler.ScaledObjectTemplate{
+			PollingInterval:  template.PollingInterval,
+			CooldownPeriod:   template.CooldownPeriod,
+			IdleReplicaCount: template.IdleReplicaCount,
+		}
+		if template.Fallback != nil {
+			config.ScaledObjectTemplate.Fallback = &autoscaler.ScaledObjectFallback{
+				FailureThreshold: template.Fallback.FailureThreshold,
+				Replicas:         template.Fallback.Replicas,
+			}
+		}
+	}
+	return config
+}
+
+// withWorkloadConfig returns a copy of the recommender generating the recommendation with the overrides of the
+// config, since the recommender is shared by the concurrent recommendations of the workloads of other namespaces.
+func (c *CpuUtilizationBasedRecommender) withWorkloadConfig(config *WorkloadConfig) *CpuUtilizationBasedRecommender {
+	if config == nil {
+		return c
+	}
+	configured := *c
+	if config.MinTarget > 0 {
+		configured.minTarget = config.MinTarget
+	}
+	if config.MaxTarget > 0 {
+		configured.maxTarget = config.MaxTarget
+	}
+	if config.RedLineUtilization > 0 {
+		configured.redLineUtil = config.RedLineUtilization
+	}
+	if configured.minTarget > configured.maxTarget {
+		configured.maxTarget = configured.minTarget
+	}
+	return &configured
+}
@@ -0,0 +1,93 @@
+package reco
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ConfigResolver", func() {
+	intPtr := func(value int) *int {
+		return &value
+	}
+	newConfig := func(name string, createdAt time.Time, spec v1beta1.OttoscalrConfigSpec) *v1beta1.OttoscalrConfig {
+		return &v1beta1.OttoscalrConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "config-ns", CreationTimestamp: metav1.NewTime(createdAt)},
+			Spec:       spec,
+		}
+	}
+	newResolver := func(configs ...client.Object) *ConfigResolver {
+		fakeScheme := runtime.NewScheme()
+		Expect(v1beta1.AddToScheme(fakeScheme)).To(Succeed())
+		return NewConfigResolver(fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(configs...).Build())
+	}
+	createdAt := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	It("should resolve the oldest config of the namespace", func() {
+		resolver := newResolver(
+			newConfig("newer", createdAt.Add(time.Hour), v1beta1.OttoscalrConfigSpec{MinTarget: intPtr(40)}),
+			newConfig("tenant", createdAt, v1beta1.OttoscalrConfigSpec{
+				MetricWindowInDays:        intPtr(14),
+				MaxTarget:                 intPtr(70),
+				RedLineUtilizationPercent: intPtr(65),
+				MinRequiredReplicas:       intPtr(2),
+				EnforcementMode:           v1beta1.EnforcementModeDryRun,
+				MinCapacityReservation:    resource.NewMilliQuantity(40500, resource.DecimalSI),
+			}),
+		)
+		config, err := resolver.Resolve(context.TODO(), "config-ns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(Equal(&WorkloadConfig{Name: "tenant", MetricWindow: 14 * 24 * time.Hour, MaxTarget: 70,
+			RedLineUtilization: 0.65, MinRequiredReplicas: intPtr(2), EnforcementMode: v1beta1.EnforcementModeDryRun,
+			MinCapacityReservation: 40.5}))
+	})
+
+	It("should resolve the ScaledObject template of the namespace", func() {
+		pollingInterval := int32(10)
+		resolver := newResolver(newConfig("tenant", createdAt, v1beta1.OttoscalrConfigSpec{
+			ScaledObject: &v1beta1.ScaledObjectTemplate{PollingInterval: &pollingInterval,
+				Fallback: &v1beta1.ScaledObjectFallback{FailureThreshold: 3, Replicas: 6}},
+		}))
+		config, err := resolver.Resolve(context.TODO(), "config-ns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.ScaledObjectTemplate).To(Equal(&autoscaler.ScaledObjectTemplate{PollingInterval: &pollingInterval,
+			Fallback: &autoscaler.ScaledObjectFallback{FailureThreshold: 3, Replicas: 6}}))
+	})
+
+	It("should not resolve any config for the namespaces without one", func() {
+		config, err := newResolver().Resolve(context.TODO(), "config-ns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(BeNil())
+	})
+
+	It("should carry the config to the recommenders in the context", func() {
+		Expect(WorkloadConfigFromContext(context.TODO())).To(BeNil())
+		config := &WorkloadConfig{Name: "tenant"}
+		Expect(WorkloadConfigFromContext(ContextWithWorkloadConfig(context.TODO(), config))).To(BeIdenticalTo(config))
+	})
+
+	It("should override the defaults of the recommender without changing the shared recommender", func() {
+		recommender := &CpuUtilizationBasedRecommender{redLineUtil: 0.8, minTarget: 10, maxTarget: 60, logger: logr.Discard()}
+		Expect(recommender.withWorkloadConfig(nil)).To(BeIdenticalTo(recommender))
+
+		configured := recommender.withWorkloadConfig(&WorkloadConfig{MinTarget: 30, RedLineUtilization: 0.7})
+		Expect(configured.minTarget).To(Equal(30))
+		Expect(configured.maxTarget).To(Equal(60))
+		Expect(configured.redLineUtil).To(Equal(0.7))
+		Expect(recommender.minTarget).To(Equal(10))
+		Expect(recommender.redLineUtil).To(Equal(0.8))
+
+		configured = recommender.withWorkloadConfig(&WorkloadConfig{MinTarget: 70})
+		Expect(configured.maxTarget).To(Equal(70))
+	})
+})
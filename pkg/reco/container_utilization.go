@@ -0,0 +1,96 @@
+package reco
+
+import (
+	"math"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Modes of combining the utilization of the containers of the pods.
+const (
+	// ContainerUtilizationWeighted averages the utilization of the containers by their weights.
+	ContainerUtilizationWeighted = "weighted"
+	// ContainerUtilizationMax takes the utilization of the most utilized container.
+	ContainerUtilizationMax = "max"
+)
+
+var (
+	containerDemandRatioGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "container_demand_ratio",
+			Help: "Ratio of the demand of the workload combined from the utilization of its containers to its aggregate cpu usage"},
+		[]string{"namespace", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(containerDemandRatioGauge)
+}
+
+// ContainerUtilization combines the utilization of the containers of the pods instead of dividing their aggregate cpu
+// usage by the sum of their limits, which weighs every container by its limits. A pod with a small saturated
+// container and a large idle one would otherwise look underutilized.
+type ContainerUtilization struct {
+	// Mode is ContainerUtilizationWeighted or ContainerUtilizationMax.
+	Mode string
+	// Weights are the weights of the containers by their names in the weighted mode. The containers not listed weigh 1.
+	Weights map[string]float64
+}
+
+// WithContainerUtilization makes the recommender simulate the workloads on the utilization of their containers,
+// scraped by the scraper, combined as configured.
+func (c *CpuUtilizationBasedRecommender) WithContainerUtilization(scraper metrics.ContainerScraper,
+	containerUtilization ContainerUtilization) *CpuUtilizationBasedRecommender {
+	c.containerScraper = scraper
+	c.containerUtilization = &containerUtilization
+	return c
+}
+
+// containerDemand returns the cpu usage of the pods which would utilize them as much as their containers combined,
+// given the number of the containers every container keeps fully utilized, by their names. The utilization of a
+// container at a datapoint is the latest one at or before it. The datapoints without the utilization of any container
+// are kept. It also returns the ratio of the combined demand to the aggregate usage.
+func (c *CpuUtilizationBasedRecommender) containerDemand(dataPoints []metrics.DataPoint,
+	byContainer map[string][]metrics.DataPoint, perPodResources float64) ([]metrics.DataPoint, float64) {
+	demand := make([]metrics.DataPoint, len(dataPoints))
+	latest := make(map[string]int, len(byContainer))
+	for container := range byContainer {
+		latest[container] = -1
+	}
+	usage, combinedUsage := 0.0, 0.0
+	for i, dp := range dataPoints {
+		demand[i] = dp
+		combined, weights := 0.0, 0.0
+		for container, utilization := range byContainer {
+			j := latest[container]
+			for j+1 < len(utilization) && !utilization[j+1].Timestamp.After(dp.Timestamp) {
+				j++
+			}
+			latest[container] = j
+			// the containers without limits don't have a utilization
+			if j < 0 || math.IsNaN(utilization[j].Value) || math.IsInf(utilization[j].Value, 0) {
+				continue
+			}
+			switch c.containerUtilization.Mode {
+			case ContainerUtilizationMax:
+				combined, weights = math.Max(combined, utilization[j].Value), 1
+			default:
+				weight, ok := c.containerUtilization.Weights[container]
+				if !ok {
+					weight = 1
+				}
+				combined += weight * utilization[j].Value
+				weights += weight
+			}
+		}
+		if weights > 0 {
+			demand[i].Value = perPodResources * combined / weights
+		}
+		usage += dp.Value
+		combinedUsage += demand[i].Value
+	}
+	if usage <= 0 {
+		return demand, 1
+	}
+	return demand, combinedUsage / usage
+}
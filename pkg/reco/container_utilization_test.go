@@ -0,0 +1,61 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Container utilization", func() {
+	var (
+		containerRecommender *CpuUtilizationBasedRecommender
+		start                = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	series := func(values ...float64) []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for i, value := range values {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+		return dataPoints
+	}
+
+	// 2 pods, each with a sidecar of 0.5 cpu limits and an app container of 3.5 cpu limits
+	dataPoints := series(1.2, 2, 0.9)
+	byContainer := map[string][]metrics.DataPoint{
+		"sidecar": series(1.6, 2, 1),
+		"app":     series(0.4, 0, math.NaN()),
+	}
+
+	BeforeEach(func() {
+		containerRecommender = &CpuUtilizationBasedRecommender{logger: logr.Discard()}
+	})
+
+	It("should take the utilization of the most utilized container", func() {
+		containerRecommender.WithContainerUtilization(nil, ContainerUtilization{Mode: ContainerUtilizationMax})
+		demand, ratio := containerRecommender.containerDemand(dataPoints, byContainer, 4)
+		Expect(demand).To(Equal(series(6.4, 8, 4)))
+		Expect(ratio).To(BeNumerically("~", 18.4/4.1, 0.001))
+	})
+
+	It("should average the utilization of the containers by their weights", func() {
+		containerRecommender.WithContainerUtilization(nil, ContainerUtilization{Mode: ContainerUtilizationWeighted,
+			Weights: map[string]float64{"sidecar": 3}})
+		demand, _ := containerRecommender.containerDemand(dataPoints, byContainer, 4)
+		Expect(demand).To(HaveLen(3))
+		for i, value := range []float64{5.2, 6, 4} {
+			Expect(demand[i].Value).To(BeNumerically("~", value, 0.001))
+		}
+	})
+
+	It("should keep the datapoints without the utilization of any container", func() {
+		containerRecommender.WithContainerUtilization(nil, ContainerUtilization{Mode: ContainerUtilizationMax})
+		demand, _ := containerRecommender.containerDemand(append(series(1), dataPoints[1:]...),
+			map[string][]metrics.DataPoint{"app": series(0.4, 0.5)[1:]}, 4)
+		Expect(demand).To(Equal(series(1, 2, 2)))
+	})
+})
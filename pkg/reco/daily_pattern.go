@@ -0,0 +1,235 @@
+package reco
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+const (
+	// dailyPatternSlot is the resolution the daily peaks are detected at.
+	dailyPatternSlot = 15 * time.Minute
+	slotsPerDay      = int(24 * time.Hour / dailyPatternSlot)
+	day              = 24 * time.Hour
+)
+
+// CronTriggerConfig configures the detection of the recurring daily peaks of the workloads, which are pre-scaled with
+// cron triggers instead of having the HPA target cover the ramp up to them.
+type CronTriggerConfig struct {
+	// PreScaleLead is how long before a peak the workload is pre-scaled.
+	PreScaleLead time.Duration
+	// PeakFactor is how many times the median utilization of a day a slot of the day should reach to be a peak.
+	PeakFactor float64
+	// MinRecurrencePercent is the percent of the days a slot should be a peak on to be a recurring daily peak.
+	MinRecurrencePercent int
+	// MinDays is the number of whole days of metrics required to detect the daily peaks.
+	MinDays int
+}
+
+// dailyPeak is a window of the day, as offsets from the midnight, in which the workload peaks every day. The end is
+// beyond a day for the windows spanning the midnight.
+type dailyPeak struct {
+	start           time.Duration
+	end             time.Duration
+	desiredReplicas int
+}
+
+// WithCronTriggers makes the recommender detect the recurring daily peaks of the workloads and recommend cron
+// triggers pre-scaling the workloads ahead of them. The HPA simulation accounts for the pre-scaled replicas. Only the
+// ScaledObjects run cron triggers, so the peaks are detected only along with WithScaledObjectBackend.
+func (c *CpuUtilizationBasedRecommender) WithCronTriggers(config CronTriggerConfig) *CpuUtilizationBasedRecommender {
+	c.cronTriggerConfig = &config
+	return c
+}
+
+// WithScaledObjectBackend tells the recommender that the recommendations are enforced with ScaledObjects, which run the
+// recommended cron triggers. With HPAs, which ignore them, the targets are recommended without the pre-scaled peaks.
+func (c *CpuUtilizationBasedRecommender) WithScaledObjectBackend() *CpuUtilizationBasedRecommender {
+	c.scaledObjectBackend = true
+	return c
+}
+
+func (c *CpuUtilizationBasedRecommender) patternLocation() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// sinceMidnight returns the wall clock time of the day of t in the location.
+func sinceMidnight(t time.Time, location *time.Location) time.Duration {
+	t = t.In(location)
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// inDailyWindow returns true if the offset from the midnight lies in [from, to), where from can be before the
+// midnight and to after the next one.
+func inDailyWindow(offset, from, to time.Duration) bool {
+	for _, o := range []time.Duration{offset - day, offset, offset + day} {
+		if o >= from && o < to {
+			return true
+		}
+	}
+	return false
+}
+
+// detectDailyPeaks returns the windows of the day in which the utilization recurringly peaks well above the median of
+// the day, along with the replicas needed to serve the highest utilization seen in them below the red line. There are
+// none unless the recommendations are enforced with ScaledObjects, as nothing else pre-scales the workloads for them.
+func (c *CpuUtilizationBasedRecommender) detectDailyPeaks(dataPoints []metrics.DataPoint, perPodResources float64,
+	maxReplicas int) []dailyPeak {
+	config := c.cronTriggerConfig
+	if config == nil || !c.scaledObjectBackend || len(dataPoints) == 0 || perPodResources <= 0 {
+		return nil
+	}
+	location := c.patternLocation()
+	recurrences, wholeDays := dailyRecurrences(dataPoints, location, func(slotMax, median float64) bool {
+		return slotMax >= config.PeakFactor*median
+	})
+	if wholeDays == 0 || wholeDays < config.MinDays {
+		return nil
+	}
+
+	var isPeak [slotsPerDay]bool
+	offPeak := -1
+	for slot, count := range recurrences {
+		isPeak[slot] = count*100 >= config.MinRecurrencePercent*wholeDays
+		if !isPeak[slot] && offPeak < 0 {
+			offPeak = slot
+		}
+	}
+	if offPeak < 0 {
+		// peaking all day long is no peak
+		return nil
+	}
+
+	// the slots are walked from an off peak slot so that the peaks spanning the midnight stay whole
+	var peaks []dailyPeak
+	for i := 1; i <= slotsPerDay; i++ {
+		slot := (offPeak + i) % slotsPerDay
+		if !isPeak[slot] {
+			continue
+		}
+		start := time.Duration(slot) * dailyPatternSlot
+		if n := len(peaks); n > 0 && peaks[n-1].end%day == start {
+			peaks[n-1].end += dailyPatternSlot
+			continue
+		}
+		peaks = append(peaks, dailyPeak{start: start, end: start + dailyPatternSlot})
+	}
+
+	desiredPeaks := peaks[:0]
+	for _, peak := range peaks {
+		peakValue := 0.0
+		for _, dp := range dataPoints {
+			if inDailyWindow(sinceMidnight(dp.Timestamp, location), peak.start, peak.end) {
+				peakValue = math.Max(peakValue, dp.Value)
+			}
+		}
+		peak.desiredReplicas = int(math.Min(float64(maxReplicas), math.Ceil(peakValue/(perPodResources*c.redLineUtil))))
+		if peak.desiredReplicas > 1 {
+			desiredPeaks = append(desiredPeaks, peak)
+		}
+	}
+	return desiredPeaks
+}
+
+// dailyRecurrences returns the number of the whole days of the datapoints each slot of the day recurs on, i.e. the
+// highest utilization of the slot is recurring relative to the median utilization of the day, along with the number of
+// the whole days.
+func dailyRecurrences(dataPoints []metrics.DataPoint, location *time.Location,
+	recurring func(slotMax, median float64) bool) ([slotsPerDay]int, int) {
+	type daySlots struct {
+		slotMax [slotsPerDay]float64
+		seen    [slotsPerDay]bool
+		values  []float64
+	}
+	days := make(map[string]*daySlots)
+	for _, dp := range dataPoints {
+		key := dp.Timestamp.In(location).Format("2006-01-02")
+		d, ok := days[key]
+		if !ok {
+			d = &daySlots{}
+			days[key] = d
+		}
+		slot := int(sinceMidnight(dp.Timestamp, location) / dailyPatternSlot)
+		if !d.seen[slot] || dp.Value > d.slotMax[slot] {
+			d.slotMax[slot] = dp.Value
+		}
+		d.seen[slot] = true
+		d.values = append(d.values, dp.Value)
+	}
+
+	var recurrences [slotsPerDay]int
+	wholeDays := 0
+	for _, d := range days {
+		seen := 0
+		for _, s := range d.seen {
+			if s {
+				seen++
+			}
+		}
+		// the partial days at the edges of the window or with large gaps can't tell the shape of the day
+		if seen*10 < slotsPerDay*9 {
+			continue
+		}
+		wholeDays++
+		sort.Float64s(d.values)
+		median := d.values[len(d.values)/2]
+		if median <= 0 {
+			continue
+		}
+		for slot := range d.slotMax {
+			if d.seen[slot] && recurring(d.slotMax[slot], median) {
+				recurrences[slot]++
+			}
+		}
+	}
+	return recurrences, wholeDays
+}
+
+// peakReplicaFloors returns the replicas the cron triggers of the peaks keep the workload at, at every datapoint.
+func (c *CpuUtilizationBasedRecommender) peakReplicaFloors(dataPoints []metrics.DataPoint, peaks []dailyPeak) []int {
+	if len(peaks) == 0 {
+		return nil
+	}
+	location := c.patternLocation()
+	floors := make([]int, len(dataPoints))
+	for i, dp := range dataPoints {
+		offset := sinceMidnight(dp.Timestamp, location)
+		for _, peak := range peaks {
+			if inDailyWindow(offset, peak.start-c.cronTriggerConfig.PreScaleLead, peak.end) && peak.desiredReplicas > floors[i] {
+				floors[i] = peak.desiredReplicas
+			}
+		}
+	}
+	return floors
+}
+
+// cronTriggers returns the cron triggers pre-scaling the workload ahead of the peaks which need more than the min
+// replicas.
+func (c *CpuUtilizationBasedRecommender) cronTriggers(peaks []dailyPeak, minReplicas int) []v1alpha1.CronTrigger {
+	var triggers []v1alpha1.CronTrigger
+	for _, peak := range peaks {
+		if peak.desiredReplicas <= minReplicas {
+			continue
+		}
+		triggers = append(triggers, v1alpha1.CronTrigger{
+			Start:           dailyCronExpression(peak.start - c.cronTriggerConfig.PreScaleLead),
+			End:             dailyCronExpression(peak.end),
+			Timezone:        c.patternLocation().String(),
+			DesiredReplicas: peak.desiredReplicas,
+		})
+	}
+	return triggers
+}
+
+// dailyCronExpression returns the cron expression firing every day at the offset from the midnight.
+func dailyCronExpression(offset time.Duration) string {
+	offset = ((offset % day) + day) % day
+	return fmt.Sprintf("%d %d * * *", int(offset%time.Hour/time.Minute), int(offset/time.Hour))
+}
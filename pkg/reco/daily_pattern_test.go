@@ -0,0 +1,148 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/testutil"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Daily peak detection", func() {
+	var (
+		patternRecommender *CpuUtilizationBasedRecommender
+		end                = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	replay := func(fixture []metrics.DataPoint, days int) []metrics.DataPoint {
+		scraper := testutil.NewReplayScraper().WithDefaultWorkload(testutil.ReplayWorkload{Fixture: fixture})
+		dataPoints, err := scraper.GetAverageCPUUtilizationByWorkload("default", "workload",
+			end.Add(-time.Duration(days)*24*time.Hour), end.Add(-5*time.Minute), 5*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		return dataPoints
+	}
+
+	BeforeEach(func() {
+		patternRecommender = (&CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}).
+			WithCronTriggers(CronTriggerConfig{
+				PreScaleLead:         15 * time.Minute,
+				PeakFactor:           2.5,
+				MinRecurrencePercent: 80,
+				MinDays:              3,
+			}).WithScaledObjectBackend()
+	})
+
+	It("should detect the recurring evening peak of diurnal traffic", func() {
+		fixture, err := testutil.LoadFixture("diurnal.csv")
+		Expect(err).NotTo(HaveOccurred())
+
+		peaks := patternRecommender.detectDailyPeaks(replay(fixture, 7), 1, 30)
+		Expect(peaks).To(HaveLen(1))
+		Expect(peaks[0].start).To(BeNumerically(">=", 19*time.Hour))
+		Expect(peaks[0].end).To(BeNumerically("<=", 21*time.Hour))
+		// 11 cores at the peak need 13 pods of a core below the red line
+		Expect(peaks[0].desiredReplicas).To(Equal(13))
+
+		triggers := patternRecommender.cronTriggers(peaks, 3)
+		Expect(triggers).To(HaveLen(1))
+		Expect(triggers[0].Start).To(Equal(dailyCronExpression(peaks[0].start - 15*time.Minute)))
+		Expect(triggers[0].End).To(Equal(dailyCronExpression(peaks[0].end)))
+		Expect(triggers[0].Timezone).To(Equal("UTC"))
+		Expect(triggers[0].DesiredReplicas).To(Equal(13))
+		Expect(patternRecommender.cronTriggers(peaks, 13)).To(BeEmpty())
+	})
+
+	It("should not detect peaks in flat traffic", func() {
+		fixture := []metrics.DataPoint{
+			{Timestamp: end, Value: 4},
+			{Timestamp: end.Add(time.Hour), Value: 4},
+		}
+		Expect(patternRecommender.detectDailyPeaks(replay(fixture, 7), 1, 30)).To(BeEmpty())
+	})
+
+	It("should not detect peaks with fewer whole days than required", func() {
+		fixture, err := testutil.LoadFixture("diurnal.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patternRecommender.detectDailyPeaks(replay(fixture, 2), 1, 30)).To(BeEmpty())
+	})
+
+	It("should keep a peak spanning the midnight whole", func() {
+		var fixture []metrics.DataPoint
+		for offset := time.Duration(0); offset < 24*time.Hour; offset += 15 * time.Minute {
+			value := 2.0
+			if offset < time.Hour || offset >= 23*time.Hour {
+				value = 8
+			}
+			fixture = append(fixture, metrics.DataPoint{Timestamp: end.Add(offset), Value: value})
+		}
+
+		peaks := patternRecommender.detectDailyPeaks(replay(fixture, 5), 1, 30)
+		Expect(peaks).To(HaveLen(1))
+		Expect(peaks[0].start).To(Equal(23 * time.Hour))
+		Expect(peaks[0].end).To(Equal(25 * time.Hour))
+		Expect(dailyCronExpression(peaks[0].end)).To(Equal("0 1 * * *"))
+	})
+
+	It("should floor the replicas from the pre-scale lead till the end of the peaks", func() {
+		peaks := []dailyPeak{{start: 20 * time.Hour, end: 21 * time.Hour, desiredReplicas: 10}}
+		dataPoints := []metrics.DataPoint{
+			{Timestamp: time.Date(2023, 6, 14, 19, 40, 0, 0, time.UTC)},
+			{Timestamp: time.Date(2023, 6, 14, 19, 45, 0, 0, time.UTC)},
+			{Timestamp: time.Date(2023, 6, 14, 20, 30, 0, 0, time.UTC)},
+			{Timestamp: time.Date(2023, 6, 14, 21, 0, 0, 0, time.UTC)},
+		}
+		Expect(patternRecommender.peakReplicaFloors(dataPoints, peaks)).To(Equal([]int{0, 10, 10, 0}))
+		Expect(patternRecommender.peakReplicaFloors(dataPoints, nil)).To(BeNil())
+	})
+
+	It("should evaluate the peaks in the location of the recommender", func() {
+		ist := time.FixedZone("IST", 5*60*60+30*60)
+		patternRecommender.WithLocation(ist)
+		// 14:30 UTC is 20:00 IST
+		Expect(sinceMidnight(time.Date(2023, 6, 14, 14, 30, 0, 0, time.UTC), patternRecommender.patternLocation())).
+			To(Equal(20 * time.Hour))
+		Expect(dailyCronExpression(-15 * time.Minute)).To(Equal("45 23 * * *"))
+	})
+
+	It("should not lower the target when the peaks are pre-scaled", func() {
+		fixture, err := testutil.LoadFixture("diurnal.csv")
+		Expect(err).NotTo(HaveOccurred())
+		dataPoints := replay(fixture, 7)
+		peaks := patternRecommender.detectDailyPeaks(dataPoints, 1, 30)
+		Expect(peaks).NotTo(BeEmpty())
+
+		target, _, _, err := patternRecommender.findOptimalHPAConfigurations(dataPoints, 15*time.Minute, 10, 60, 1, 30, nil)
+		Expect(err).NotTo(HaveOccurred())
+		floors := patternRecommender.peakReplicaFloors(dataPoints, peaks)
+		scheduledTarget, scheduledMin, scheduledMax, err := patternRecommender.findOptimalProfiledHPAConfigurations(
+			dataPoints, trafficProfile{floors: floors}, 15*time.Minute, 10, 60, 1, 30, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scheduledTarget).To(BeNumerically(">=", target))
+
+		simulated, _, err := patternRecommender.simulateScheduledHPAInto(nil, dataPoints, floors, 15*time.Minute,
+			scheduledTarget, 1, scheduledMax, scheduledMin)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patternRecommender.hasNoBreachOccurred(dataPoints, simulated)).To(BeTrue())
+	})
+	It("should recommend the plain HPA config without pre-scaled peaks when the backend is HPA", func() {
+		fixture, err := testutil.LoadFixture("diurnal.csv")
+		Expect(err).NotTo(HaveOccurred())
+		dataPoints := replay(fixture, 7)
+		hpaRecommender := (&CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}).
+			WithCronTriggers(*patternRecommender.cronTriggerConfig)
+
+		peaks := hpaRecommender.detectDailyPeaks(dataPoints, 1, 30)
+		Expect(peaks).To(BeEmpty())
+		Expect(hpaRecommender.peakReplicaFloors(dataPoints, peaks)).To(BeNil())
+		Expect(hpaRecommender.cronTriggers(peaks, 3)).To(BeEmpty())
+
+		target, min, max, err := hpaRecommender.findOptimalHPAConfigurations(dataPoints, 15*time.Minute, 10, 60, 1, 30, nil)
+		Expect(err).NotTo(HaveOccurred())
+		profiledTarget, profiledMin, profiledMax, err := hpaRecommender.findOptimalProfiledHPAConfigurations(dataPoints,
+			trafficProfile{floors: hpaRecommender.peakReplicaFloors(dataPoints, peaks)}, 15*time.Minute, 10, 60, 1, 30, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect([]int{profiledTarget, profiledMin, profiledMax}).To(Equal([]int{target, min, max}))
+	})
+})
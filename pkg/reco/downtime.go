@@ -0,0 +1,175 @@
+package reco
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+// DowntimeWindowsAnnotation registers the recurring windows a workload is known to have no traffic in, e.g. its
+// nightly scale-downs or the weekend shutdowns of an internal tool, as windows separated by semicolons. A window is
+// made of the days of the week, like "*", "Sat,Sun" or "Mon-Fri", and a time range of the day, like "01:00-05:00",
+// e.g. "Sat-Sun 00:00-24:00; Mon-Fri 22:00-06:00". The times are in the timezone of the recommender, UTC by default.
+// A range ending before it starts spans midnight, with the days matching the times on either side of it.
+const DowntimeWindowsAnnotation = "ottoscalr.io/downtime-windows"
+
+var weekdays = map[string]time.Weekday{"Sun": time.Sunday, "Mon": time.Monday, "Tue": time.Tuesday,
+	"Wed": time.Wednesday, "Thu": time.Thursday, "Fri": time.Friday, "Sat": time.Saturday}
+
+// DowntimeWindow is a recurring window of the week a workload has no traffic in.
+type DowntimeWindow struct {
+	Days [7]bool
+	// StartMinute and EndMinute bound the window in the minutes of the day, with the EndMinute excluded.
+	StartMinute int
+	EndMinute   int
+}
+
+// Contains tells whether the time, in its location, is within the window.
+func (w DowntimeWindow) Contains(t time.Time) bool {
+	if !w.Days[t.Weekday()] {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if w.StartMinute <= w.EndMinute {
+		return minute >= w.StartMinute && minute < w.EndMinute
+	}
+	return minute >= w.StartMinute || minute < w.EndMinute
+}
+
+// ParseDowntimeWindows parses the windows of the DowntimeWindowsAnnotation.
+func ParseDowntimeWindows(value string) ([]DowntimeWindow, error) {
+	var windows []DowntimeWindow
+	for _, spec := range strings.Split(value, ";") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		fields := strings.Fields(spec)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid downtime window %q, expected the days and a time range", spec)
+		}
+		window := DowntimeWindow{}
+		if err := parseDays(fields[0], &window.Days); err != nil {
+			return nil, fmt.Errorf("invalid days of the downtime window %q: %v", spec, err)
+		}
+		from, to, ok := strings.Cut(fields[1], "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time range of the downtime window %q", spec)
+		}
+		var err error
+		if window.StartMinute, err = parseMinuteOfDay(from); err != nil {
+			return nil, fmt.Errorf("invalid time range of the downtime window %q: %v", spec, err)
+		}
+		if window.EndMinute, err = parseMinuteOfDay(to); err != nil {
+			return nil, fmt.Errorf("invalid time range of the downtime window %q: %v", spec, err)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseDays(value string, days *[7]bool) error {
+	if value == "*" {
+		for i := range days {
+			days[i] = true
+		}
+		return nil
+	}
+	for _, dayRange := range strings.Split(value, ",") {
+		from, to, isRange := strings.Cut(dayRange, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return fmt.Errorf("unknown day %q", to)
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return h*60 + m, nil
+}
+
+// workloadDowntimeWindows returns the downtime windows of the annotation of the workload, if it's annotated.
+func (c *CpuUtilizationBasedRecommender) workloadDowntimeWindows(workloadMeta WorkloadMeta) ([]DowntimeWindow, error) {
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := workload.GetAnnotations()[DowntimeWindowsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	windows, err := ParseDowntimeWindows(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation of the workload %s/%s: %v", DowntimeWindowsAnnotation,
+			workloadMeta.Namespace, workloadMeta.Name, err)
+	}
+	return windows, nil
+}
+
+// excludeDowntime returns the datapoints outside the downtime windows along with the length of the metric window
+// [start, end) outside of them, so that the coverage of the datapoints isn't lowered by the datapoints the workload
+// is known not to have.
+func (c *CpuUtilizationBasedRecommender) excludeDowntime(dataPoints []metrics.DataPoint, start, end time.Time,
+	windows []DowntimeWindow) ([]metrics.DataPoint, time.Duration) {
+	if len(windows) == 0 || c.metricStep <= 0 {
+		return dataPoints, end.Sub(start)
+	}
+	location := c.location
+	if location == nil {
+		location = time.UTC
+	}
+	inDowntime := func(t time.Time) bool {
+		t = t.In(location)
+		for _, window := range windows {
+			if window.Contains(t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var uptime time.Duration
+	for t := start; t.Before(end); t = t.Add(c.metricStep) {
+		if !inDowntime(t) {
+			uptime += c.metricStep
+		}
+	}
+	upDataPoints := make([]metrics.DataPoint, 0, len(dataPoints))
+	for _, dataPoint := range dataPoints {
+		if !inDowntime(dataPoint.Timestamp) {
+			upDataPoints = append(upDataPoints, dataPoint)
+		}
+	}
+	return upDataPoints, uptime
+}
@@ -0,0 +1,97 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Downtime windows of the workloads", func() {
+	var downtimeRecommender *CpuUtilizationBasedRecommender
+
+	workloadMeta := func(name string) WorkloadMeta {
+		return WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name, Namespace: "downtime-ns"}
+	}
+
+	BeforeEach(func() {
+		newDeployment := func(name string, windows string) *appsv1.Deployment {
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "downtime-ns"}}
+			if windows != "" {
+				deployment.Annotations = map[string]string{DowntimeWindowsAnnotation: windows}
+			}
+			return deployment
+		}
+		fakeScheme := runtime.NewScheme()
+		Expect(appsv1.AddToScheme(fakeScheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			newDeployment("always-on", ""),
+			newDeployment("nightly", "* 00:00-06:00"),
+			newDeployment("invalid", "weekends"),
+		).Build()
+		downtimeRecommender = &CpuUtilizationBasedRecommender{logger: logr.Discard(), metricStep: time.Hour,
+			clientsRegistry: registry.DeploymentClientRegistry{Clients: []registry.ObjectClient{registry.NewDeploymentClient(fakeClient)}},
+		}
+	})
+
+	It("should parse the windows of the annotation", func() {
+		windows, err := ParseDowntimeWindows("Sat-Sun 00:00-24:00; Mon,Wed 22:00-06:00")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(windows).To(HaveLen(2))
+
+		saturday := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+		Expect(windows[0].Contains(saturday)).To(BeTrue())
+		Expect(windows[0].Contains(saturday.Add(2 * 24 * time.Hour))).To(BeFalse())
+		monday := saturday.Add(2 * 24 * time.Hour)
+		Expect(windows[1].Contains(monday.Add(11 * time.Hour))).To(BeTrue())
+		Expect(windows[1].Contains(monday.Add(-9 * time.Hour))).To(BeTrue())
+		Expect(windows[1].Contains(monday)).To(BeFalse())
+		Expect(windows[1].Contains(monday.Add(24 * time.Hour))).To(BeFalse())
+
+		for _, invalid := range []string{"weekends", "Sat 00:00", "Sat 01:00-25:00", "Fun 01:00-02:00"} {
+			_, err := ParseDowntimeWindows(invalid)
+			Expect(err).To(HaveOccurred(), invalid)
+		}
+	})
+
+	It("should reject the invalid annotations of the workloads", func() {
+		windows, err := downtimeRecommender.workloadDowntimeWindows(workloadMeta("always-on"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(windows).To(BeEmpty())
+		windows, err = downtimeRecommender.workloadDowntimeWindows(workloadMeta("nightly"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(windows).To(HaveLen(1))
+		_, err = downtimeRecommender.workloadDowntimeWindows(workloadMeta("invalid"))
+		Expect(err).To(MatchError(ContainSubstring(DowntimeWindowsAnnotation)))
+	})
+
+	It("should leave the downtime out of the coverage of the datapoints", func() {
+		start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+		end := start.Add(48 * time.Hour)
+		// the workload is scaled down every night, so it has no datapoints from 00:00 to 06:00
+		var dataPoints []metrics.DataPoint
+		for t := start; t.Before(end); t = t.Add(time.Hour) {
+			if t.Hour() >= 6 {
+				dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: t, Value: 1})
+			}
+		}
+		Expect(int(downtimeRecommender.dataPointsCoveragePercent(dataPoints, end.Sub(start)))).To(Equal(75))
+
+		windows, err := downtimeRecommender.workloadDowntimeWindows(workloadMeta("nightly"))
+		Expect(err).NotTo(HaveOccurred())
+		upDataPoints, upWindow := downtimeRecommender.excludeDowntime(dataPoints, start, end, windows)
+		Expect(upWindow).To(Equal(36 * time.Hour))
+		Expect(int(downtimeRecommender.dataPointsCoveragePercent(upDataPoints, upWindow))).To(Equal(100))
+
+		upDataPoints, upWindow = downtimeRecommender.excludeDowntime(dataPoints, start, end, nil)
+		Expect(upWindow).To(Equal(48 * time.Hour))
+		Expect(upDataPoints).To(HaveLen(len(dataPoints)))
+	})
+})
@@ -0,0 +1,131 @@
+package reco
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+)
+
+// Explanation is the machine readable reasoning behind the last recommendation generated for a workload.
+type Explanation struct {
+	Namespace                 string                     `json:"namespace"`
+	Kind                      string                     `json:"kind"`
+	Workload                  string                     `json:"workload"`
+	GeneratedAt               time.Time                  `json:"generatedAt"`
+	MetricsWindowStart        time.Time                  `json:"metricsWindowStart,omitempty"`
+	MetricsWindowEnd          time.Time                  `json:"metricsWindowEnd,omitempty"`
+	MetricStepSeconds         int                        `json:"metricStepSeconds,omitempty"`
+	DataPointsCoveragePercent int                        `json:"dataPointsCoveragePercent"`
+	ExcludedDowntimeSeconds   int                        `json:"excludedDowntimeSeconds,omitempty"`
+	BreachFreeMaxReplicas     int                        `json:"breachFreeMaxReplicas,omitempty"`
+	DerivedMaxReplicas        int                        `json:"derivedMaxReplicas,omitempty"`
+	TransformersApplied       []string                   `json:"transformersApplied,omitempty"`
+	TransformerAudits         []TransformerAudit         `json:"transformerAudits,omitempty"`
+	TargetRecoConfig          *v1alpha1.HPAConfiguration `json:"targetRecoConfig,omitempty"`
+	CronTriggers              []v1alpha1.CronTrigger     `json:"cronTriggers,omitempty"`
+	IdleWindow                *v1alpha1.IdleWindow       `json:"idleWindow,omitempty"`
+	InsufficientHistory       bool                       `json:"insufficientHistory,omitempty"`
+	NoOp                      *NoOpRecommendation        `json:"noOp,omitempty"`
+	Confidence                *RecommendationConfidence  `json:"confidence,omitempty"`
+	Resize                    *ResizeSignal              `json:"resize,omitempty"`
+	PodResize                 *PodResize                 `json:"podResize,omitempty"`
+	RedLineUtilization        float64                    `json:"redLineUtilization,omitempty"`
+	RedLineTier               string                     `json:"redLineTier,omitempty"`
+	Config                    string                     `json:"config,omitempty"`
+	PolicyDecision            PolicyDecision             `json:"policyDecision"`
+	Simulation                *SimulationDetails         `json:"simulation,omitempty"`
+	// PodDisruptionBudgets are the budgets selecting the pods of the workload, which require it to run with at least
+	// PodDisruptionBudgetMinReplicas for a pod to be evictable.
+	PodDisruptionBudgets           []string `json:"podDisruptionBudgets,omitempty"`
+	PodDisruptionBudgetMinReplicas int      `json:"podDisruptionBudgetMinReplicas,omitempty"`
+	// ResourceQuotas are the quotas of the namespace which don't leave the workload the room to scale up to the
+	// recommended max replicas.
+	ResourceQuotas []string `json:"resourceQuotas,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// PolicyDecision captures how the policy ladder decided the HPA config to be applied.
+type PolicyDecision struct {
+	// IteratorPolicies are the policies recommended by each policy iterator. Nil policies are no-ops.
+	IteratorPolicies []IteratorPolicy `json:"iteratorPolicies,omitempty"`
+	// NextPolicy is the safest of the policies recommended by the iterators.
+	NextPolicy string `json:"nextPolicy,omitempty"`
+	// TargetRecoApplied is true if the target reco is safer than the next policy and is applied as is.
+	TargetRecoApplied bool                       `json:"targetRecoApplied"`
+	AppliedPolicy     string                     `json:"appliedPolicy,omitempty"`
+	AppliedConfig     *v1alpha1.HPAConfiguration `json:"appliedConfig,omitempty"`
+}
+
+type IteratorPolicy struct {
+	Iterator string `json:"iterator"`
+	Policy   string `json:"policy,omitempty"`
+}
+
+// Explainer explains the last recommendation generated for a workload.
+type Explainer interface {
+	Explain(namespace, workload string) (*Explanation, bool)
+}
+
+// SimulationDetailsProvider is implemented by the recommenders which record the details of their simulations.
+type SimulationDetailsProvider interface {
+	GetSimulationDetails(namespace, workload string) (SimulationDetails, bool)
+}
+
+type explanationStore struct {
+	mu           sync.RWMutex
+	explanations map[string]Explanation
+}
+
+func newExplanationStore() *explanationStore {
+	return &explanationStore{
+		explanations: make(map[string]Explanation),
+	}
+}
+
+func (s *explanationStore) put(explanation Explanation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.explanations[simulationDetailsKey(explanation.Namespace, explanation.Workload)] = explanation
+}
+
+func (s *explanationStore) get(namespace, workload string) (Explanation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	explanation, ok := s.explanations[simulationDetailsKey(namespace, workload)]
+	return explanation, ok
+}
+
+func newPolicyDecision(iteratorPolicies map[string]*Policy) PolicyDecision {
+	decision := PolicyDecision{}
+	for iterator, p := range iteratorPolicies {
+		iteratorPolicy := IteratorPolicy{Iterator: iterator}
+		if p != nil {
+			iteratorPolicy.Policy = p.Name
+		}
+		decision.IteratorPolicies = append(decision.IteratorPolicies, iteratorPolicy)
+	}
+	sort.Slice(decision.IteratorPolicies, func(i, j int) bool {
+		return decision.IteratorPolicies[i].Iterator < decision.IteratorPolicies[j].Iterator
+	})
+	return decision
+}
+
+// NewExplanationHandler serves the explanation of the workload identified by the namespace and workload query params.
+func NewExplanationHandler(explainer Explainer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		explanation, ok := explainer.Explain(r.URL.Query().Get("namespace"), r.URL.Query().Get("workload"))
+		if !ok {
+			http.Error(w, "no recommendation found for the workload", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(explanation); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package reco
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+// IdleWindowAnnotation registers the daily window of low traffic of a workload, e.g. "01:00-06:00", in which its min
+// replicas are lowered to the replicas serving its highest utilization in the window. The times are in the timezone of
+// the recommender, UTC by default. A range ending before it starts spans midnight. It takes precedence over the
+// detected idle window.
+const IdleWindowAnnotation = "ottoscalr.io/idle-window"
+
+// IdleWindowConfig configures the detection of the recurring daily windows of low traffic of the workloads, in which
+// their min replicas are lowered.
+type IdleWindowConfig struct {
+	// IdleFactor is the fraction of the median utilization of a day a slot of the day should stay within to be idle.
+	IdleFactor float64
+	// MinRecurrencePercent is the percent of the days a slot should be idle on to be a recurring idle slot.
+	MinRecurrencePercent int
+	// MinDays is the number of whole days of metrics required to detect the idle window.
+	MinDays int
+	// MinDuration is the shortest idle window worth lowering the min replicas for.
+	MinDuration time.Duration
+}
+
+// dailyIdleWindow is a window of the day, as offsets from the midnight, in which the workload idles every day, along
+// with the replicas serving its highest utilization in the window. The end is beyond a day for the windows spanning
+// the midnight.
+type dailyIdleWindow struct {
+	start       time.Duration
+	end         time.Duration
+	minReplicas int
+}
+
+// WithIdleWindows makes the recommender detect the longest recurring daily window of low traffic of the workloads and
+// recommend lowering their min replicas in it.
+func (c *CpuUtilizationBasedRecommender) WithIdleWindows(config IdleWindowConfig) *CpuUtilizationBasedRecommender {
+	c.idleWindowConfig = &config
+	return c
+}
+
+// workloadIdleWindow returns the idle window of the annotation of the workload, if it's annotated.
+func (c *CpuUtilizationBasedRecommender) workloadIdleWindow(workloadMeta WorkloadMeta) (*dailyIdleWindow, error) {
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := workload.GetAnnotations()[IdleWindowAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	window, err := parseIdleWindow(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation of the workload %s/%s: %v", IdleWindowAnnotation,
+			workloadMeta.Namespace, workloadMeta.Name, err)
+	}
+	return window, nil
+}
+
+func parseIdleWindow(value string) (*dailyIdleWindow, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid time range %q, expected HH:MM-HH:MM", value)
+	}
+	startMinute, err := parseMinuteOfDay(from)
+	if err != nil {
+		return nil, err
+	}
+	endMinute, err := parseMinuteOfDay(to)
+	if err != nil {
+		return nil, err
+	}
+	if startMinute == endMinute {
+		return nil, fmt.Errorf("empty time range %q", value)
+	}
+	if endMinute < startMinute {
+		endMinute += 24 * 60
+	}
+	return &dailyIdleWindow{start: time.Duration(startMinute) * time.Minute, end: time.Duration(endMinute) * time.Minute}, nil
+}
+
+// detectIdleWindow returns the longest window of the day in which the utilization recurringly stays well below the
+// median of the day, if it's at least the min duration long.
+func (c *CpuUtilizationBasedRecommender) detectIdleWindow(dataPoints []metrics.DataPoint) *dailyIdleWindow {
+	config := c.idleWindowConfig
+	if config == nil || len(dataPoints) == 0 {
+		return nil
+	}
+	recurrences, wholeDays := dailyRecurrences(dataPoints, c.patternLocation(), func(slotMax, median float64) bool {
+		return slotMax <= config.IdleFactor*median
+	})
+	if wholeDays == 0 || wholeDays < config.MinDays {
+		return nil
+	}
+
+	var isIdle [slotsPerDay]bool
+	busy := -1
+	for slot, count := range recurrences {
+		isIdle[slot] = count*100 >= config.MinRecurrencePercent*wholeDays
+		if !isIdle[slot] && busy < 0 {
+			busy = slot
+		}
+	}
+	if busy < 0 {
+		// idling all day long leaves nothing to restore the min replicas for
+		return nil
+	}
+
+	// the slots are walked from a busy slot so that the window spanning the midnight stays whole
+	var longest, current *dailyIdleWindow
+	for i := 1; i <= slotsPerDay; i++ {
+		slot := (busy + i) % slotsPerDay
+		if !isIdle[slot] {
+			current = nil
+			continue
+		}
+		if current == nil {
+			start := time.Duration(slot) * dailyPatternSlot
+			current = &dailyIdleWindow{start: start, end: start}
+		}
+		current.end += dailyPatternSlot
+		if longest == nil || current.end-current.start > longest.end-longest.start {
+			longest = current
+		}
+	}
+	if longest == nil || longest.end-longest.start < config.MinDuration {
+		return nil
+	}
+	return longest
+}
+
+// idleWindowReplicas sets the min replicas of the idle window to the replicas serving the highest utilization seen in
+// it below the red line, up to the max replicas.
+func (c *CpuUtilizationBasedRecommender) idleWindowReplicas(window *dailyIdleWindow, dataPoints []metrics.DataPoint,
+	perPodResources float64, maxReplicas int) {
+	location := c.patternLocation()
+	idleValue := 0.0
+	for _, dp := range dataPoints {
+		if inDailyWindow(sinceMidnight(dp.Timestamp, location), window.start, window.end) {
+			idleValue = math.Max(idleValue, dp.Value)
+		}
+	}
+	window.minReplicas = int(math.Max(1, math.Min(float64(maxReplicas), math.Ceil(idleValue/(perPodResources*c.redLineUtil)))))
+}
+
+// idleWindow returns the idle window lowering the min replicas of the workload, unless it doesn't need fewer replicas
+// than the min replicas.
+func (c *CpuUtilizationBasedRecommender) idleWindow(window *dailyIdleWindow, minReplicas int) *v1alpha1.IdleWindow {
+	if window == nil || window.minReplicas >= minReplicas {
+		return nil
+	}
+	return &v1alpha1.IdleWindow{
+		Start:       dailyCronExpression(window.start),
+		End:         dailyCronExpression(window.end),
+		Timezone:    c.patternLocation().String(),
+		MinReplicas: window.minReplicas,
+	}
+}
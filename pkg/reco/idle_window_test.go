@@ -0,0 +1,95 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/testutil"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Idle window detection", func() {
+	var (
+		idleRecommender *CpuUtilizationBasedRecommender
+		end             = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	replay := func(fixture []metrics.DataPoint, days int) []metrics.DataPoint {
+		scraper := testutil.NewReplayScraper().WithDefaultWorkload(testutil.ReplayWorkload{Fixture: fixture})
+		dataPoints, err := scraper.GetAverageCPUUtilizationByWorkload("default", "workload",
+			end.Add(-time.Duration(days)*24*time.Hour), end.Add(-5*time.Minute), 5*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		return dataPoints
+	}
+
+	BeforeEach(func() {
+		idleRecommender = (&CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}).
+			WithIdleWindows(IdleWindowConfig{
+				IdleFactor:           0.65,
+				MinRecurrencePercent: 80,
+				MinDays:              3,
+				MinDuration:          2 * time.Hour,
+			})
+	})
+
+	It("should detect the recurring night lull of diurnal traffic", func() {
+		fixture, err := testutil.LoadFixture("diurnal.csv")
+		Expect(err).NotTo(HaveOccurred())
+		dataPoints := replay(fixture, 7)
+
+		window := idleRecommender.detectIdleWindow(dataPoints)
+		Expect(window).NotTo(BeNil())
+		Expect(window.start).To(BeNumerically(">=", 22*time.Hour))
+		Expect(window.end).To(BeNumerically(">=", day+5*time.Hour))
+		Expect(window.end).To(BeNumerically("<=", day+7*time.Hour))
+
+		// 2.11 cores in the lull need 3 pods of a core below the red line
+		idleRecommender.idleWindowReplicas(window, dataPoints, 1, 30)
+		Expect(window.minReplicas).To(Equal(3))
+
+		idleWindow := idleRecommender.idleWindow(window, 5)
+		Expect(idleWindow).NotTo(BeNil())
+		Expect(idleWindow.Start).To(Equal(dailyCronExpression(window.start)))
+		Expect(idleWindow.End).To(Equal(dailyCronExpression(window.end)))
+		Expect(idleWindow.Timezone).To(Equal("UTC"))
+		Expect(idleWindow.MinReplicas).To(Equal(3))
+		Expect(idleRecommender.idleWindow(window, 3)).To(BeNil())
+	})
+
+	It("should not detect an idle window in flat traffic", func() {
+		var fixture []metrics.DataPoint
+		for t := end.Add(-7 * day); t.Before(end); t = t.Add(5 * time.Minute) {
+			fixture = append(fixture, metrics.DataPoint{Timestamp: t, Value: 4})
+		}
+		Expect(idleRecommender.detectIdleWindow(fixture)).To(BeNil())
+	})
+
+	It("should parse the idle window of the annotation of the workload", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default",
+				Annotations: map[string]string{IdleWindowAnnotation: "22:30-05:00"}}}).Build()
+		idleRecommender.clientsRegistry = *registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+
+		window, err := idleRecommender.workloadIdleWindow(WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"},
+			Name: "nightly", Namespace: "default"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window.start).To(Equal(22*time.Hour + 30*time.Minute))
+		Expect(window.end).To(Equal(day + 5*time.Hour))
+
+		_, err = parseIdleWindow("01:00")
+		Expect(err).To(HaveOccurred())
+		_, err = parseIdleWindow("01:00-01:00")
+		Expect(err).To(HaveOccurred())
+	})
+})
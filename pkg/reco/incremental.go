@@ -0,0 +1,185 @@
+package reco
+
+import (
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	incrementalDataPointsFetchCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "reco_incremental_datapoints_fetch_count",
+			Help: "Number of datapoint fetches for recommendations by whether only the tail of the window was fetched"}, []string{"namespace", "incremental"},
+	)
+
+	incrementalSearchSkippedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "reco_incremental_search_skipped_count",
+			Help: "Number of recommendations which reused the outcome of the previous simulation search"}, []string{"namespace"},
+	)
+)
+
+func init() {
+	registerCollectors(incrementalDataPointsFetchCounter, incrementalSearchSkippedCounter)
+}
+
+// simulationOutcome is the outcome of the search for the optimal HPA configuration along with its inputs.
+type simulationOutcome struct {
+	acl               time.Duration
+	perPodResources   float64
+	maxReplicas       int
+	minTarget         int
+	maxTarget         int
+	targetUtilization int
+	minReplicas       int
+	searchedAt        time.Time
+	redLineUtil       float64
+	kedaTiming        *kedaTiming
+	// searchSpace is the search space of the search, if the recommender records it.
+	searchSpace []v1alpha1.SearchSpacePoint
+}
+
+type incrementalEntry struct {
+	start      time.Time
+	end        time.Time
+	step       time.Duration
+	dataPoints *metrics.DataPointColumns
+	outcome    *simulationOutcome
+}
+
+// IncrementalCache holds the datapoints and the simulation outcome of the previous recommendation per workload so
+// that a run whose metric window only advanced by up to maxWindowDelta fetches just the new tail of the window and
+// skips the search for the optimal HPA configuration when the previous one still doesn't breach. The search is run
+// afresh at least every fullSearchInterval. Up to maxEntries workloads are cached, evicting the least recently
+// updated ones. The datapoints are cached as metrics.DataPointColumns, so the retained values have float32 precision.
+type IncrementalCache struct {
+	maxWindowDelta     time.Duration
+	fullSearchInterval time.Duration
+	maxEntries         int
+
+	mu      sync.Mutex
+	entries map[string]*incrementalEntry
+}
+
+func NewIncrementalCache(maxWindowDelta time.Duration, fullSearchInterval time.Duration, maxEntries int) *IncrementalCache {
+	return &IncrementalCache{
+		maxWindowDelta:     maxWindowDelta,
+		fullSearchInterval: fullSearchInterval,
+		maxEntries:         maxEntries,
+		entries:            make(map[string]*incrementalEntry),
+	}
+}
+
+func incrementalCacheKey(wm WorkloadMeta) string {
+	return wm.Namespace + "/" + wm.Kind + "/" + wm.Name
+}
+
+// fetchRange returns the range of the window [start, end] which has to be fetched along with the cached datapoints
+// still within the window. The whole window is fetched if the cached window can't be extended.
+func (ic *IncrementalCache) fetchRange(wm WorkloadMeta, start, end time.Time, step time.Duration) (time.Time, []metrics.DataPoint) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	entry, ok := ic.entries[incrementalCacheKey(wm)]
+	if !ok || entry.step != step || start.Before(entry.start) || !end.After(entry.end) || end.Sub(entry.end) > ic.maxWindowDelta {
+		return start, nil
+	}
+
+	from, n := entry.dataPoints.Search(start), entry.dataPoints.Len()
+	if from == n || entry.dataPoints.Timestamp(n-1).Add(step).After(end) {
+		return start, nil
+	}
+	last := entry.dataPoints.Timestamp(n - 1)
+	retained := make([]metrics.DataPoint, 0, n-from+int(end.Sub(last)/step)+1)
+	return last.Add(step), entry.dataPoints.AppendTo(retained, from)
+}
+
+// mergeDataPoints appends the freshly fetched tail to the retained datapoints, dropping the overlapping ones. The
+// retained datapoints are owned by the caller and sized by fetchRange to fit the tail, so they are appended to in place.
+func mergeDataPoints(retained, tail []metrics.DataPoint) []metrics.DataPoint {
+	if len(retained) == 0 {
+		return tail
+	}
+	last := retained[len(retained)-1].Timestamp
+	for _, dp := range tail {
+		if dp.Timestamp.After(last) {
+			retained = append(retained, dp)
+		}
+	}
+	return retained
+}
+
+// putDataPoints caches the raw datapoints of the window, retaining the simulation outcome of the previous window.
+func (ic *IncrementalCache) putDataPoints(wm WorkloadMeta, start, end time.Time, step time.Duration, dataPoints []metrics.DataPoint) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	key := incrementalCacheKey(wm)
+	entry, ok := ic.entries[key]
+	if !ok {
+		ic.evictIfFull()
+		entry = &incrementalEntry{dataPoints: &metrics.DataPointColumns{}}
+		ic.entries[key] = entry
+	}
+	entry.start, entry.end, entry.step = start, end, step
+	entry.dataPoints.Set(dataPoints)
+}
+
+// previousOutcome returns the outcome of the previous search if it was searched with the same inputs within the
+// full search interval.
+func (ic *IncrementalCache) previousOutcome(wm WorkloadMeta, inputs simulationOutcome, now time.Time) (simulationOutcome, bool) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	entry, ok := ic.entries[incrementalCacheKey(wm)]
+	if !ok || entry.outcome == nil || now.Sub(entry.outcome.searchedAt) > ic.fullSearchInterval {
+		return simulationOutcome{}, false
+	}
+	outcome := *entry.outcome
+	if outcome.acl != inputs.acl || outcome.perPodResources != inputs.perPodResources || outcome.maxReplicas != inputs.maxReplicas ||
+		outcome.minTarget != inputs.minTarget || outcome.maxTarget != inputs.maxTarget || outcome.redLineUtil != inputs.redLineUtil ||
+		!sameKEDATiming(outcome.kedaTiming, inputs.kedaTiming) {
+		return simulationOutcome{}, false
+	}
+	return outcome, true
+}
+
+func (ic *IncrementalCache) putOutcome(wm WorkloadMeta, outcome simulationOutcome) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if entry, ok := ic.entries[incrementalCacheKey(wm)]; ok {
+		entry.outcome = &outcome
+	}
+}
+
+// reusePreviousOutcome returns the outcome of the previous search for the workload if it was searched with the same
+// inputs and its HPA configuration still doesn't breach on the datapoints of the current window, i.e. still covers the
+// demand of the datapoints.
+func (c *CpuUtilizationBasedRecommender) reusePreviousOutcome(wm WorkloadMeta, dataPoints, demand []metrics.DataPoint,
+	inputs simulationOutcome) (simulationOutcome, bool) {
+	previous, ok := c.incrementalCache.previousOutcome(wm, inputs, time.Now())
+	if !ok {
+		return simulationOutcome{}, false
+	}
+	simulated, _, err := c.simulateHPA(dataPoints, inputs.acl, previous.targetUtilization, inputs.perPodResources,
+		inputs.maxReplicas, previous.minReplicas)
+	if err != nil || len(simulated) == 0 || !c.hasNoBreachOccurred(demand, simulated) {
+		return simulationOutcome{}, false
+	}
+	incrementalSearchSkippedCounter.WithLabelValues(wm.Namespace).Inc()
+	return previous, true
+}
+
+// evictIfFull evicts the entry with the oldest window. It must be called with the lock held.
+func (ic *IncrementalCache) evictIfFull() {
+	if ic.maxEntries <= 0 || len(ic.entries) < ic.maxEntries {
+		return
+	}
+	var oldestKey string
+	var oldestEnd time.Time
+	for key, entry := range ic.entries {
+		if len(oldestKey) == 0 || entry.end.Before(oldestEnd) {
+			oldestKey, oldestEnd = key, entry.end
+		}
+	}
+	delete(ic.entries, oldestKey)
+}
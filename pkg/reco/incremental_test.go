@@ -0,0 +1,102 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IncrementalCache", func() {
+	var (
+		cache *IncrementalCache
+		wm    WorkloadMeta
+		now   time.Time
+		step  time.Duration
+	)
+
+	dataPointsBetween := func(start, end time.Time) []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for ts := start; !ts.After(end); ts = ts.Add(step) {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: ts, Value: float64(ts.Minute())})
+		}
+		return dataPoints
+	}
+
+	BeforeEach(func() {
+		cache = NewIncrementalCache(2*time.Hour, 24*time.Hour, 2)
+		wm = WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "app", Namespace: "ns"}
+		now = time.Now().Truncate(time.Minute)
+		step = time.Minute
+	})
+
+	It("should fetch only the tail of a window which advanced by a small delta", func() {
+		start, end := now.Add(-10*time.Hour), now
+		cache.putDataPoints(wm, start, end, step, dataPointsBetween(start, end))
+
+		newStart, newEnd := start.Add(time.Hour), end.Add(time.Hour)
+		fetchStart, retained := cache.fetchRange(wm, newStart, newEnd, step)
+		Expect(fetchStart).To(Equal(end.Add(step)))
+		Expect(retained[0].Timestamp).To(Equal(newStart))
+		Expect(retained[len(retained)-1].Timestamp).To(Equal(end))
+
+		merged := mergeDataPoints(retained, dataPointsBetween(end, newEnd))
+		Expect(merged).To(Equal(dataPointsBetween(newStart, newEnd)))
+	})
+
+	It("should fetch the whole window when it can't be extended", func() {
+		start, end := now.Add(-10*time.Hour), now
+		cache.putDataPoints(wm, start, end, step, dataPointsBetween(start, end))
+
+		By("advancing the window beyond the max delta")
+		fetchStart, retained := cache.fetchRange(wm, start.Add(3*time.Hour), end.Add(3*time.Hour), step)
+		Expect(fetchStart).To(Equal(start.Add(3 * time.Hour)))
+		Expect(retained).To(BeNil())
+
+		By("changing the step")
+		fetchStart, retained = cache.fetchRange(wm, start.Add(time.Hour), end.Add(time.Hour), 30*time.Second)
+		Expect(fetchStart).To(Equal(start.Add(time.Hour)))
+		Expect(retained).To(BeNil())
+
+		By("widening the window")
+		fetchStart, retained = cache.fetchRange(wm, start.Add(-time.Hour), end.Add(time.Hour), step)
+		Expect(fetchStart).To(Equal(start.Add(-time.Hour)))
+		Expect(retained).To(BeNil())
+	})
+
+	It("should reuse the outcome of a search with the same inputs within the full search interval", func() {
+		cache.putDataPoints(wm, now.Add(-time.Hour), now, step, nil)
+		inputs := simulationOutcome{acl: time.Minute, perPodResources: 2, maxReplicas: 10, minTarget: 10, maxTarget: 60}
+		outcome := inputs
+		outcome.targetUtilization, outcome.minReplicas, outcome.searchedAt = 50, 3, now
+		cache.putOutcome(wm, outcome)
+
+		previous, ok := cache.previousOutcome(wm, inputs, now.Add(time.Hour))
+		Expect(ok).To(BeTrue())
+		Expect(previous.targetUtilization).To(Equal(50))
+		Expect(previous.minReplicas).To(Equal(3))
+
+		_, ok = cache.previousOutcome(wm, inputs, now.Add(25*time.Hour))
+		Expect(ok).To(BeFalse())
+
+		inputs.perPodResources = 4
+		_, ok = cache.previousOutcome(wm, inputs, now.Add(time.Hour))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should evict the workload with the oldest window when full", func() {
+		other := wm
+		other.Name = "other"
+		another := wm
+		another.Name = "another"
+		cache.putDataPoints(wm, now.Add(-2*time.Hour), now.Add(-time.Hour), step, nil)
+		cache.putDataPoints(other, now.Add(-time.Hour), now, step, nil)
+		cache.putDataPoints(another, now.Add(-time.Hour), now, step, nil)
+
+		Expect(cache.entries).To(HaveLen(2))
+		Expect(cache.entries).NotTo(HaveKey(incrementalCacheKey(wm)))
+	})
+})
@@ -0,0 +1,63 @@
+package reco
+
+import (
+	"context"
+
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	unindexedScaledObjectListCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "unindexed_scaledobject_list_count",
+			Help: "Number of times the ScaledObjects of a workload were listed without the field index as it isn't registered"},
+		[]string{"namespace"},
+	)
+)
+
+func init() {
+	registerCollectors(unindexedScaledObjectListCounter)
+}
+
+// RegisterFieldIndexes registers the field indexes the recommenders list the objects by on the indexer of the
+// manager, e.g. for embedding the recommenders in another manager.
+func RegisterFieldIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &kedaapi.ScaledObject{}, ScaledObjectField, func(obj client.Object) []string {
+		scaledObject := obj.(*kedaapi.ScaledObject)
+		if scaledObject.Spec.ScaleTargetRef == nil || scaledObject.Spec.ScaleTargetRef.Name == "" {
+			return nil
+		}
+		return []string{scaledObject.Spec.ScaleTargetRef.Name}
+	})
+}
+
+// listScaledObjectsOf returns the ScaledObjects scaling the workload. They are listed by the ScaledObjectField index,
+// or, if the index isn't registered, by listing all the ScaledObjects of the namespace and matching their scale
+// target.
+func listScaledObjectsOf(ctx context.Context, k8sClient client.Client, namespace,
+	workload string) ([]kedaapi.ScaledObject, error) {
+	scaledObjects := &kedaapi.ScaledObjectList{}
+	err := k8sClient.List(ctx, scaledObjects, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(ScaledObjectField, workload),
+		Namespace:     namespace,
+	})
+	if err == nil || client.IgnoreNotFound(err) == nil {
+		return scaledObjects.Items, nil
+	}
+
+	// the cache and the fake clients fail the lists by unregistered indexes, and the api server the field selectors
+	// it doesn't support, alike
+	unindexedScaledObjectListCounter.WithLabelValues(namespace).Inc()
+	if err := k8sClient.List(ctx, scaledObjects, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var matched []kedaapi.ScaledObject
+	for _, scaledObject := range scaledObjects.Items {
+		if scaledObject.Spec.ScaleTargetRef != nil && scaledObject.Spec.ScaleTargetRef.Name == workload {
+			matched = append(matched, scaledObject)
+		}
+	}
+	return matched, nil
+}
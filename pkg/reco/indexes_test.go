@@ -0,0 +1,52 @@
+package reco
+
+import (
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("getMaxPods", func() {
+	var fakeBuilder *fake.ClientBuilder
+
+	newScaledObject := func(name, target string, maxReplicas int32) *kedaapi.ScaledObject {
+		return &kedaapi.ScaledObject{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "index-ns"},
+			Spec: kedaapi.ScaledObjectSpec{ScaleTargetRef: &kedaapi.ScaleTarget{Name: target},
+				MaxReplicaCount: &maxReplicas},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeScheme := runtime.NewScheme()
+		Expect(appsv1.AddToScheme(fakeScheme)).To(Succeed())
+		Expect(kedaapi.AddToScheme(fakeScheme)).To(Succeed())
+		fakeBuilder = fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "index-ns"}},
+			newScaledObject("search", "search", 8),
+			newScaledObject("checkout", "checkout", 12),
+		)
+	})
+
+	maxPodsOf := func(fakeClient client.Client) (int, error) {
+		clientsRegistry := registry.DeploymentClientRegistry{Clients: []registry.ObjectClient{registry.NewDeploymentClient(fakeClient)}}
+		return getMaxPods(fakeClient, clientsRegistry, "index-ns", "Deployment", "checkout")
+	}
+
+	It("should get the max replicas of the ScaledObject by the field index", func() {
+		fakeClient := fakeBuilder.WithIndex(&kedaapi.ScaledObject{}, ScaledObjectField, func(obj client.Object) []string {
+			return []string{obj.(*kedaapi.ScaledObject).Spec.ScaleTargetRef.Name}
+		}).Build()
+		Expect(maxPodsOf(fakeClient)).To(Equal(12))
+	})
+
+	It("should fall back to matching the scale targets without the field index", func() {
+		Expect(maxPodsOf(fakeBuilder.Build())).To(Equal(12))
+	})
+})
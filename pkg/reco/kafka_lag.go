@@ -0,0 +1,139 @@
+package reco
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	kafkaConsumerThroughputGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "kafka_consumer_replica_throughput",
+			Help: "Messages per second a replica of the kafka consumer is estimated to consume"},
+		[]string{"namespace", "workload", "consumergroup"},
+	)
+)
+
+func init() {
+	registerCollectors(kafkaConsumerThroughputGauge)
+}
+
+// KafkaLagBasedRecommender recommends the kafka consumers, i.e. the workloads annotated with
+// autoscaler.KafkaConsumerGroupAnnotation, the replicas needed to keep the lag of their consumer group under a target,
+// to be scaled by a KEDA kafka trigger rather than on their cpu utilization. The other workloads are recommended by the
+// delegate.
+type KafkaLagBasedRecommender struct {
+	delegate        Recommender
+	k8sClient       client.Client
+	scraper         metrics.KafkaScraper
+	clientsRegistry registry.DeploymentClientRegistry
+	metricWindow    time.Duration
+	metricStep      time.Duration
+	targetLag       int
+	logger          logr.Logger
+}
+
+func NewKafkaLagBasedRecommender(delegate Recommender,
+	k8sClient client.Client,
+	scraper metrics.KafkaScraper,
+	clientsRegistry registry.DeploymentClientRegistry,
+	metricWindow time.Duration,
+	metricStep time.Duration,
+	targetLag int,
+	logger logr.Logger) *KafkaLagBasedRecommender {
+	return &KafkaLagBasedRecommender{
+		delegate:        delegate,
+		k8sClient:       k8sClient,
+		scraper:         scraper,
+		clientsRegistry: clientsRegistry,
+		metricWindow:    metricWindow,
+		metricStep:      metricStep,
+		targetLag:       targetLag,
+		logger:          logger,
+	}
+}
+
+// GetSimulationDetails returns the details of the last simulation of the delegate for the workload.
+func (k *KafkaLagBasedRecommender) GetSimulationDetails(namespace, workload string) (SimulationDetails, bool) {
+	if provider, ok := k.delegate.(SimulationDetailsProvider); ok {
+		return provider.GetSimulationDetails(namespace, workload)
+	}
+	return SimulationDetails{}, false
+}
+
+func (k *KafkaLagBasedRecommender) Recommend(ctx context.Context, workloadMeta WorkloadMeta) (*v1alpha1.HPAConfiguration,
+	*RecommendationMetadata, error) {
+	objectClient, err := k.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	trigger, err := autoscaler.KafkaTriggerFromAnnotations(workload.GetAnnotations())
+	if err != nil {
+		return nil, nil, err
+	}
+	if trigger == nil {
+		return k.delegate.Recommend(ctx, workloadMeta)
+	}
+	targetLag := k.targetLag
+	if trigger.TargetLag > 0 {
+		targetLag = trigger.TargetLag
+	}
+
+	end := time.Now()
+	start := end.Add(-k.metricWindow)
+	lag, err := k.scraper.GetConsumerGroupLag(trigger.ConsumerGroup, trigger.Topic, start, end, k.metricStep)
+	if err != nil {
+		return nil, nil, err
+	}
+	consumeRate, err := k.scraper.GetConsumerGroupConsumeRate(trigger.ConsumerGroup, trigger.Topic, start, end, k.metricStep)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err := k.scraper.GetPodCountByWorkload(workloadMeta.Namespace, workloadMeta.Name, start, end, k.metricStep)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxReplicas, err := getMaxPods(k.k8sClient, k.clientsRegistry, workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the replicas beyond the partitions of the topic don't consume anything
+	partitions, err := k.scraper.GetTopicPartitions(trigger.Topic)
+	if err != nil {
+		k.logger.Error(err, "Error while getting the partitions of the topic. Not capping the max replicas by them.",
+			"topic", trigger.Topic)
+	} else if partitions < maxReplicas {
+		maxReplicas = partitions
+	}
+
+	recommendation, err := recommendBacklogConfig(lag, consumeRate, pods, k.metricStep, targetLag, maxReplicas,
+		autoscaler.KafkaLagMetricName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to recommend the kafka consumer group %s: %v", trigger.ConsumerGroup, err)
+	}
+	kafkaConsumerThroughputGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name, trigger.ConsumerGroup).
+		Set(recommendation.replicaThroughput)
+	k.logger.V(0).Info("Kafka lag based recommendation", "workload", workloadMeta, "consumerGroup", trigger.ConsumerGroup,
+		"replicaThroughput", recommendation.replicaThroughput, "config", recommendation.config)
+
+	expectedDataPoints := int(k.metricWindow / k.metricStep)
+	return recommendation.config, &RecommendationMetadata{
+		MetricsWindowStart:        start,
+		MetricsWindowEnd:          end,
+		DataPointsCoveragePercent: int(math.Min(float64(len(lag))*100/math.Max(float64(expectedDataPoints), 1), 100)),
+		ProjectedSavingsPercent:   recommendation.savingsPercent,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package reco
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultKEDAPollingInterval is the pollingInterval of KEDA for the ScaledObjects which don't set one.
+	DefaultKEDAPollingInterval = 30 * time.Second
+	// DefaultKEDACooldownPeriod is the cooldownPeriod of KEDA for the ScaledObjects which don't set one.
+	DefaultKEDACooldownPeriod = 300 * time.Second
+)
+
+// kedaTiming is the pollingInterval and the cooldownPeriod of the ScaledObject of a workload, as deployed.
+type kedaTiming struct {
+	pollingInterval time.Duration
+	cooldownPeriod  time.Duration
+}
+
+// replicaEvaluation is the replicas a poll of the simulated ScaledObject desired.
+type replicaEvaluation struct {
+	at       time.Time
+	replicas float64
+}
+
+// WithKEDATimings makes the HPA simulations of the workloads scaled by a ScaledObject model its pollingInterval and
+// cooldownPeriod, instead of rescaling the workload at every datapoint, so that the breaches found match what KEDA
+// does for the workloads polled every minute or slower. The workloads without a ScaledObject are simulated as before.
+func (c *CpuUtilizationBasedRecommender) WithKEDATimings() *CpuUtilizationBasedRecommender {
+	c.kedaTimings = true
+	return c
+}
+
+// workloadKEDATiming returns the timing of the ScaledObject scaling the workload, or nil if it isn't scaled by one.
+func (c *CpuUtilizationBasedRecommender) workloadKEDATiming(ctx context.Context, workloadMeta WorkloadMeta) (*kedaTiming, error) {
+	scaledObjects, err := listScaledObjectsOf(ctx, c.k8sClient, workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil || len(scaledObjects) == 0 {
+		return nil, err
+	}
+	timing := &kedaTiming{pollingInterval: DefaultKEDAPollingInterval, cooldownPeriod: DefaultKEDACooldownPeriod}
+	if pollingInterval := scaledObjects[0].Spec.PollingInterval; pollingInterval != nil {
+		timing.pollingInterval = time.Duration(*pollingInterval) * time.Second
+	}
+	if cooldownPeriod := scaledObjects[0].Spec.CooldownPeriod; cooldownPeriod != nil {
+		timing.cooldownPeriod = time.Duration(*cooldownPeriod) * time.Second
+	}
+	return timing, nil
+}
+
+// withKEDATiming returns a copy of the recommender simulating the HPA with the timing, or as rescaling at every
+// datapoint if it's nil.
+func (c *CpuUtilizationBasedRecommender) withKEDATiming(timing *kedaTiming) *CpuUtilizationBasedRecommender {
+	if timing == c.kedaTiming {
+		return c
+	}
+	timed := *c
+	timed.kedaTiming = timing
+	return &timed
+}
+
+// polls returns whether the ScaledObject is polled again at the time since its last poll. A nil timing polls at every
+// datapoint.
+func (t *kedaTiming) polls(last, at time.Time) bool {
+	return t == nil || at.Sub(last) >= t.pollingInterval
+}
+
+// cooledDown returns the replicas the ScaledObject scales to for the replicas desired by its poll at the time, which
+// are the most replicas desired over the cooldownPeriod, so that the scale downs wait out the cooldown since the last
+// poll desiring more replicas. The evaluations of the polls within the cooldown are returned along.
+func (t *kedaTiming) cooledDown(evaluations []replicaEvaluation, at time.Time,
+	desired float64) (float64, []replicaEvaluation) {
+	if t == nil {
+		return desired, evaluations
+	}
+	expired := 0
+	for expired < len(evaluations) && at.Sub(evaluations[expired].at) >= t.cooldownPeriod {
+		expired++
+	}
+	evaluations = append(evaluations[expired:], replicaEvaluation{at: at, replicas: desired})
+	for _, evaluation := range evaluations {
+		if evaluation.replicas > desired {
+			desired = evaluation.replicas
+		}
+	}
+	return desired, evaluations
+}
+
+func sameKEDATiming(a, b *kedaTiming) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,69 @@
+package reco
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("KEDA timings", func() {
+	start := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	// the workload needs 2 pods of a core for the first minute, 8 for the next four and 2 again after
+	var dataPoints []metrics.DataPoint
+	for i := 0; i < 24; i++ {
+		value := 1.0
+		if i >= 2 && i < 10 {
+			value = 4
+		}
+		dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * 30 * time.Second), Value: value})
+	}
+
+	It("should rescale the workload only at the polls of the ScaledObject and scale it down after the cooldown", func() {
+		recommender := &CpuUtilizationBasedRecommender{redLineUtil: 0.85}
+		untimed, _, err := recommender.simulateHPA(dataPoints, 0, 50, 1, 10, 1)
+		Expect(err).NotTo(HaveOccurred())
+		timed, _, err := recommender.withKEDATiming(&kedaTiming{pollingInterval: 2 * time.Minute,
+			cooldownPeriod: 5 * time.Minute}).simulateHPA(dataPoints, 0, 50, 1, 10, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the surge at 60s is upscaled for right away, but only at the poll at 120s by the ScaledObject
+		Expect(untimed[3].Value).To(BeNumerically("~", 8*0.85))
+		Expect(timed[3].Value).To(BeNumerically("~", 2*0.85))
+		Expect(timed[4].Value).To(BeNumerically("~", 2*0.85))
+		Expect(timed[5].Value).To(BeNumerically("~", 8*0.85))
+
+		// the lull from 300s is scaled down for right away, but only once the polls at 120s and 240s cool down
+		Expect(untimed[11].Value).To(BeNumerically("~", 2*0.85))
+		Expect(timed[16].Value).To(BeNumerically("~", 8*0.85))
+		Expect(timed[19].Value).To(BeNumerically("~", 8*0.85))
+		Expect(timed[20].Value).To(BeNumerically("~", 2*0.85))
+	})
+
+	It("should read the timing of the ScaledObject of the workload", func() {
+		scheme := runtime.NewScheme()
+		Expect(kedaapi.AddToScheme(scheme)).To(Succeed())
+		pollingInterval := int32(90)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&kedaapi.ScaledObject{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+			Spec: kedaapi.ScaledObjectSpec{ScaleTargetRef: &kedaapi.ScaleTarget{Name: "checkout"},
+				PollingInterval: &pollingInterval},
+		}).Build()
+		recommender := (&CpuUtilizationBasedRecommender{k8sClient: k8sClient}).WithKEDATimings()
+
+		timing, err := recommender.workloadKEDATiming(context.TODO(), WorkloadMeta{Name: "checkout", Namespace: "default"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*timing).To(Equal(kedaTiming{pollingInterval: 90 * time.Second, cooldownPeriod: DefaultKEDACooldownPeriod}))
+
+		timing, err = recommender.workloadKEDATiming(context.TODO(), WorkloadMeta{Name: "search", Namespace: "default"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timing).To(BeNil())
+	})
+})
@@ -0,0 +1,111 @@
+package reco
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyLadder is where the workloads stand on the ladder of the policies, from the safest to the riskiest, on their
+// way to the policies of their target recommendations, for the promotion funnel of the fleet.
+type PolicyLadder struct {
+	Steps     []PolicyLadderStep       `json:"steps"`
+	Workloads []WorkloadLadderPosition `json:"workloads"`
+}
+
+// PolicyLadderStep is a policy of the ladder along with the workloads on it and the workloads it's the target of.
+type PolicyLadderStep struct {
+	Policy          string `json:"policy"`
+	RiskIndex       int    `json:"riskIndex"`
+	Workloads       int    `json:"workloads"`
+	TargetWorkloads int    `json:"targetWorkloads"`
+}
+
+// WorkloadLadderPosition is the policy a workload is on and the policy its target recommendation takes it to.
+type WorkloadLadderPosition struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Workload  string `json:"workload"`
+	Policy    string `json:"policy"`
+	RiskIndex int    `json:"riskIndex"`
+	// TargetPolicy is the closest safe policy of the target recommendation, which the workload is promoted up to.
+	// It's empty if the target recommendation isn't laddered, e.g. if it doesn't target the utilization.
+	TargetPolicy   string `json:"targetPolicy,omitempty"`
+	StepsRemaining int    `json:"stepsRemaining"`
+	TargetReached  bool   `json:"targetReached"`
+	// Held is set for the workloads which aren't promoted while they're pinned or frozen.
+	Held bool `json:"held,omitempty"`
+	// NextTransitionAt is when the policy of the workload expires and it's promoted to the next policy by the aging
+	// iterator. It's nil for the workloads which aren't promoted anymore.
+	NextTransitionAt *metav1.Time `json:"nextTransitionAt,omitempty"`
+}
+
+// NewPolicyLadder returns the positions of the workloads of the policyrecos on the ladder of the policies sorted by
+// their risk index, with their next promotion after the expiry age of their policy.
+func NewPolicyLadder(policies []v1alpha1.Policy, policyrecos []v1alpha1.PolicyRecommendation,
+	expiryAge time.Duration) PolicyLadder {
+	ladder := PolicyLadder{Steps: make([]PolicyLadderStep, 0, len(policies)),
+		Workloads: make([]WorkloadLadderPosition, 0, len(policyrecos))}
+	steps := make(map[string]int, len(policies))
+	for i, p := range policies {
+		steps[p.Name] = i
+		ladder.Steps = append(ladder.Steps, PolicyLadderStep{Policy: p.Name, RiskIndex: p.Spec.RiskIndex})
+	}
+
+	for _, policyreco := range policyrecos {
+		position := WorkloadLadderPosition{
+			Namespace: policyreco.Namespace,
+			Kind:      policyreco.Spec.WorkloadMeta.Kind,
+			Workload:  policyreco.Spec.WorkloadMeta.Name,
+			Policy:    policyreco.Spec.Policy,
+		}
+		// the workloads without a known policy are below the safest one
+		step, ok := steps[policyreco.Spec.Policy]
+		if ok {
+			position.RiskIndex = policies[step].Spec.RiskIndex
+			ladder.Steps[step].Workloads++
+		} else {
+			step = -1
+		}
+
+		target := policyreco.Spec.TargetHPAConfiguration
+		if target.GetTargetMetricType() == v1alpha1.UtilizationMetricTarget {
+			if targetPolicy := closestSafePolicy(policies, target); targetPolicy != nil {
+				targetStep := steps[targetPolicy.Name]
+				position.TargetPolicy = targetPolicy.Name
+				ladder.Steps[targetStep].TargetWorkloads++
+				if targetStep > step {
+					position.StepsRemaining = targetStep - step
+				}
+			}
+		}
+		position.TargetReached = isTargetRecommendationAchieved(&policyreco) ||
+			(len(position.TargetPolicy) > 0 && position.StepsRemaining == 0)
+		if position.TargetReached {
+			position.StepsRemaining = 0
+		}
+
+		frozen, _ := strconv.ParseBool(policyreco.GetAnnotations()[v1alpha1.FreezeRecommendationAnnotation])
+		position.Held = frozen || policyreco.Spec.PinnedHPAConfiguration != nil
+		if !position.TargetReached && !position.Held && policyreco.Spec.TransitionedAt != nil {
+			nextTransitionAt := metav1.NewTime(policyreco.Spec.TransitionedAt.Add(expiryAge))
+			position.NextTransitionAt = &nextTransitionAt
+		}
+		ladder.Workloads = append(ladder.Workloads, position)
+	}
+	return ladder
+}
+
+// closestSafePolicy returns the riskiest of the policies sorted by their risk index which cut the min replicas all the
+// way to the ones of the config and target at most its utilization, or nil if none does.
+func closestSafePolicy(policies []v1alpha1.Policy, config v1alpha1.HPAConfiguration) *v1alpha1.Policy {
+	var closest *v1alpha1.Policy
+	for i := range policies {
+		if policies[i].Spec.MinReplicaPercentageCut == 100 && policies[i].Spec.TargetUtilization <= config.TargetMetricValue {
+			closest = &policies[i]
+		}
+	}
+	return closest
+}
@@ -0,0 +1,78 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("NewPolicyLadder", func() {
+	policies := []v1alpha1.Policy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "safest"}, Spec: v1alpha1.PolicySpec{RiskIndex: 1, MinReplicaPercentageCut: 0, TargetUtilization: 20}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cut-half"}, Spec: v1alpha1.PolicySpec{RiskIndex: 2, MinReplicaPercentageCut: 50, TargetUtilization: 30}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cut-all-40"}, Spec: v1alpha1.PolicySpec{RiskIndex: 3, MinReplicaPercentageCut: 100, TargetUtilization: 40}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cut-all-60"}, Spec: v1alpha1.PolicySpec{RiskIndex: 4, MinReplicaPercentageCut: 100, TargetUtilization: 60}},
+	}
+	transitionedAt := metav1.NewTime(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC))
+	policyReco := func(name, policy string, target int) v1alpha1.PolicyRecommendation {
+		return v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{
+				WorkloadMeta:           v1alpha1.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name},
+				TargetHPAConfiguration: v1alpha1.HPAConfiguration{Min: 4, Max: 20, TargetMetricValue: target},
+				Policy:                 policy,
+				TransitionedAt:         &transitionedAt,
+			},
+		}
+	}
+
+	It("should place the workloads on their steps with the steps remaining to their target policies", func() {
+		climbing := policyReco("climbing", "safest", 65)
+		fresh := policyReco("fresh", "", 45)
+		reached := policyReco("reached", "cut-all-40", 50)
+		pinned := policyReco("pinned", "cut-half", 70)
+		pinned.Spec.PinnedHPAConfiguration = &v1alpha1.HPAConfiguration{Min: 10, Max: 20, TargetMetricValue: 30}
+		frozen := policyReco("frozen", "cut-half", 70)
+		frozen.Annotations = map[string]string{v1alpha1.FreezeRecommendationAnnotation: "true"}
+		achieved := policyReco("achieved", "cut-half", 70)
+		achieved.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.TargetRecoAchieved),
+			Status: metav1.ConditionTrue}}
+
+		ladder := NewPolicyLadder(policies, []v1alpha1.PolicyRecommendation{climbing, fresh, reached, pinned, frozen,
+			achieved}, 48*time.Hour)
+		Expect(ladder.Steps).To(Equal([]PolicyLadderStep{
+			{Policy: "safest", RiskIndex: 1, Workloads: 1},
+			{Policy: "cut-half", RiskIndex: 2, Workloads: 3},
+			{Policy: "cut-all-40", RiskIndex: 3, Workloads: 1, TargetWorkloads: 2},
+			{Policy: "cut-all-60", RiskIndex: 4, TargetWorkloads: 4},
+		}))
+
+		nextTransitionAt := metav1.NewTime(transitionedAt.Add(48 * time.Hour))
+		Expect(ladder.Workloads).To(Equal([]WorkloadLadderPosition{
+			{Namespace: "default", Kind: "Deployment", Workload: "climbing", Policy: "safest", RiskIndex: 1,
+				TargetPolicy: "cut-all-60", StepsRemaining: 3, NextTransitionAt: &nextTransitionAt},
+			{Namespace: "default", Kind: "Deployment", Workload: "fresh", TargetPolicy: "cut-all-40", StepsRemaining: 3,
+				NextTransitionAt: &nextTransitionAt},
+			{Namespace: "default", Kind: "Deployment", Workload: "reached", Policy: "cut-all-40", RiskIndex: 3,
+				TargetPolicy: "cut-all-40", TargetReached: true},
+			{Namespace: "default", Kind: "Deployment", Workload: "pinned", Policy: "cut-half", RiskIndex: 2,
+				TargetPolicy: "cut-all-60", StepsRemaining: 2, Held: true},
+			{Namespace: "default", Kind: "Deployment", Workload: "frozen", Policy: "cut-half", RiskIndex: 2,
+				TargetPolicy: "cut-all-60", StepsRemaining: 2, Held: true},
+			{Namespace: "default", Kind: "Deployment", Workload: "achieved", Policy: "cut-half", RiskIndex: 2,
+				TargetPolicy: "cut-all-60", TargetReached: true},
+		}))
+	})
+
+	It("should leave the targets of the other metrics off the ladder", func() {
+		queue := policyReco("queue", "safest", 100)
+		queue.Spec.TargetHPAConfiguration.TargetMetricType = v1alpha1.AverageValueMetricTarget
+		ladder := NewPolicyLadder(policies, []v1alpha1.PolicyRecommendation{queue}, time.Hour)
+		Expect(ladder.Workloads[0].TargetPolicy).To(BeEmpty())
+		Expect(ladder.Workloads[0].TargetReached).To(BeFalse())
+		Expect(ladder.Workloads[0].NextTransitionAt).NotTo(BeNil())
+	})
+})
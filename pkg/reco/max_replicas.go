@@ -0,0 +1,92 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	breachFreeMaxReplicasGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "recommendation_breach_free_max_replicas",
+			Help: "Smallest max replicas which would have avoided the breaches of the workload over the metrics window at the recommended min replicas and target"},
+		[]string{"namespace", "workload"},
+	)
+
+	derivedMaxPodsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "recommendation_derived_max_replicas",
+			Help: "Max replicas derived from the peak demand of the workload without a max pods annotation"},
+		[]string{"namespace", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(breachFreeMaxReplicasGauge, derivedMaxPodsGauge)
+}
+
+// WithMaxReplicasAnalysis makes the recommender report the smallest max replicas which would still have avoided the
+// breaches over the metrics window of every workload, at its recommended min replicas and target, so that the teams
+// can tune the ceilings of their workloads too. The max replicas recommended isn't changed by it.
+func (c *CpuUtilizationBasedRecommender) WithMaxReplicasAnalysis() *CpuUtilizationBasedRecommender {
+	c.maxReplicasAnalysis = true
+	return c
+}
+
+// breachFreeMaxReplicas returns the smallest max replicas in [minReplicas, maxReplicas] whose simulation at the target
+// and the min replicas has no breaches over the datapoints. More max replicas never breach where fewer don't, so it's
+// binary searched. It returns the maxReplicas if even those breach.
+func (c *CpuUtilizationBasedRecommender) breachFreeMaxReplicas(dataPoints []metrics.DataPoint, profile trafficProfile,
+	acl time.Duration, target int, perPodResources float64, minReplicas, maxReplicas int) (int, error) {
+	demand := profile.demandOf(dataPoints)
+	buffer := make([]metrics.DataPoint, len(dataPoints))
+	low, high := minReplicas, maxReplicas
+	if low < 1 {
+		low = 1
+	}
+	for low < high {
+		mid := low + (high-low)/2
+		simulated, _, err := c.simulateScheduledHPAInto(buffer, dataPoints, profile.floors, acl, target, perPodResources, mid, minReplicas)
+		if err != nil {
+			return 0, err
+		}
+		if c.hasNoBreachOccurred(demand[profile.breachesFrom:], simulated[profile.breachesFrom:]) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return high, nil
+}
+
+func logBreachFreeMaxReplicas(workloadMeta WorkloadMeta, maxReplicas int) {
+	breachFreeMaxReplicasGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(float64(maxReplicas))
+}
+
+// WithDerivedMaxPods makes the recommender derive the max replicas of the workloads without a max pods annotation or
+// a ScaledObject from the replicas their peak utilization over the metrics window needs at the redline, plus the
+// headroom percent of them, rather than taking their current replicas, which are often stale.
+func (c *CpuUtilizationBasedRecommender) WithDerivedMaxPods(headroomPercent int) *CpuUtilizationBasedRecommender {
+	c.maxPodsHeadroomPercent = &headroomPercent
+	return c
+}
+
+// derivedMaxPods returns the replicas the peak of the datapoints needs to stay within the redline, plus the headroom
+// percent of them. It returns the current replicas if the demand can't be told from the datapoints.
+func (c *CpuUtilizationBasedRecommender) derivedMaxPods(dataPoints []metrics.DataPoint, perPodResources float64,
+	currentReplicas int) int {
+	if len(dataPoints) == 0 || perPodResources <= 0 || c.redLineUtil <= 0 {
+		return currentReplicas
+	}
+	peakReplicas := math.Ceil(peakUtilization(dataPoints) / (perPodResources * c.redLineUtil))
+	maxPods := int(math.Ceil(peakReplicas * (1 + float64(*c.maxPodsHeadroomPercent)/100)))
+	if maxPods < 1 {
+		return 1
+	}
+	return maxPods
+}
+
+func logDerivedMaxPods(workloadMeta WorkloadMeta, maxReplicas int) {
+	derivedMaxPodsGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(float64(maxReplicas))
+}
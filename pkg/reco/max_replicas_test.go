@@ -0,0 +1,73 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Max replicas analysis", func() {
+	var (
+		analysisRecommender *CpuUtilizationBasedRecommender
+		dataPoints          []metrics.DataPoint
+	)
+
+	BeforeEach(func() {
+		analysisRecommender = &CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}
+		start := time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC)
+		for i, value := range []float64{1, 2, 3, 4, 4, 4, 3, 2, 1} {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+	})
+
+	AfterEach(func() {
+		dataPoints = nil
+	})
+
+	breaches := func(maxReplicas int) bool {
+		simulated, _, err := analysisRecommender.simulateHPA(dataPoints, 0, 50, 1, maxReplicas, 3)
+		Expect(err).NotTo(HaveOccurred())
+		return !analysisRecommender.hasNoBreachOccurred(dataPoints, simulated)
+	}
+
+	It("should find the smallest max replicas which avoids the breaches", func() {
+		Expect(breaches(20)).To(BeFalse())
+		maxReplicas, err := analysisRecommender.breachFreeMaxReplicas(dataPoints, trafficProfile{}, 0, 50, 1, 3, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxReplicas).To(BeNumerically("<", 20))
+		Expect(breaches(maxReplicas)).To(BeFalse())
+		Expect(breaches(maxReplicas - 1)).To(BeTrue())
+	})
+
+	It("should not go beyond the max replicas of the workload", func() {
+		maxReplicas, err := analysisRecommender.breachFreeMaxReplicas(dataPoints, trafficProfile{}, 0, 50, 1, 3, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxReplicas).To(Equal(4))
+
+		maxReplicas, err = analysisRecommender.breachFreeMaxReplicas(dataPoints, trafficProfile{}, 0, 50, 1, 3, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxReplicas).To(Equal(3))
+	})
+})
+
+var _ = Describe("Derived max pods", func() {
+	derivedRecommender := &CpuUtilizationBasedRecommender{redLineUtil: 0.8, logger: logr.Discard()}
+	start := time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC)
+	dataPoints := []metrics.DataPoint{
+		{Timestamp: start, Value: 2},
+		{Timestamp: start.Add(time.Minute), Value: 7.5},
+		{Timestamp: start.Add(2 * time.Minute), Value: 3},
+	}
+
+	It("should derive the max pods from the peak demand plus the headroom", func() {
+		// the peak of 7.5 cpus needs 10 pods of 1 cpu at the redline of 0.8
+		derivedRecommender.WithDerivedMaxPods(0)
+		Expect(derivedRecommender.derivedMaxPods(dataPoints, 1, 40)).To(Equal(10))
+		derivedRecommender.WithDerivedMaxPods(25)
+		Expect(derivedRecommender.derivedMaxPods(dataPoints, 1, 40)).To(Equal(13))
+		Expect(derivedRecommender.derivedMaxPods(nil, 1, 40)).To(Equal(40))
+	})
+})
@@ -0,0 +1,54 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	recommendationMetricStepGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "recommendation_metric_step_seconds",
+			Help: "Step of the datapoints the last recommendation of the workload was generated from"},
+		[]string{"namespace", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(recommendationMetricStepGauge)
+}
+
+// WithAutoMetricStep makes the recommender derive the step of the datapoints from the length of the metric window of
+// every workload instead of scraping all the windows at its metric step: the least multiple of minStep which keeps
+// the datapoints of the window within maxDataPoints. The long windows are then scraped coarser without blowing up
+// the datapoints, and the short ones finer without losing resolution.
+func (c *CpuUtilizationBasedRecommender) WithAutoMetricStep(minStep time.Duration, maxDataPoints int) *CpuUtilizationBasedRecommender {
+	c.minMetricStep = minStep
+	c.maxDataPoints = maxDataPoints
+	return c
+}
+
+// metricStepFor returns the step of the datapoints of the metric window, which is the metric step of the recommender
+// unless it derives the step from the window.
+func (c *CpuUtilizationBasedRecommender) metricStepFor(metricWindow time.Duration) time.Duration {
+	if c.minMetricStep <= 0 || c.maxDataPoints <= 0 {
+		return c.metricStep
+	}
+	multiple := math.Ceil(float64(metricWindow) / float64(c.minMetricStep) / float64(c.maxDataPoints))
+	return time.Duration(math.Max(multiple, 1)) * c.minMetricStep
+}
+
+// withMetricStep returns a copy of the recommender scraping the datapoints at the step.
+func (c *CpuUtilizationBasedRecommender) withMetricStep(step time.Duration) *CpuUtilizationBasedRecommender {
+	if step == c.metricStep {
+		return c
+	}
+	stepped := *c
+	stepped.metricStep = step
+	return &stepped
+}
+
+func logRecommendationMetricStep(workloadMeta WorkloadMeta, step time.Duration) {
+	recommendationMetricStepGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(step.Seconds())
+}
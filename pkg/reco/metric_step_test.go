@@ -0,0 +1,28 @@
+package reco
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metric step", func() {
+	It("should derive the step from the metric window within the max datapoints", func() {
+		stepRecommender := (&CpuUtilizationBasedRecommender{metricStep: time.Minute}).
+			WithAutoMetricStep(30*time.Second, 20000)
+		// 28 days at 30s are 80640 datapoints, which 150s brings down to 16128
+		Expect(stepRecommender.metricStepFor(28 * 24 * time.Hour)).To(Equal(150 * time.Second))
+		Expect(stepRecommender.metricStepFor(3 * 24 * time.Hour)).To(Equal(30 * time.Second))
+	})
+
+	It("should scrape at the metric step of the recommender unless it derives the step", func() {
+		stepRecommender := &CpuUtilizationBasedRecommender{metricStep: time.Minute}
+		Expect(stepRecommender.metricStepFor(28 * 24 * time.Hour)).To(Equal(time.Minute))
+		Expect(stepRecommender.withMetricStep(time.Minute)).To(BeIdenticalTo(stepRecommender))
+
+		stepped := stepRecommender.withMetricStep(5 * time.Minute)
+		Expect(stepped.metricStep).To(Equal(5 * time.Minute))
+		Expect(stepRecommender.metricStep).To(Equal(time.Minute))
+	})
+})
@@ -0,0 +1,80 @@
+package reco
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricWindowAnnotation overrides the metric window of the recommender and of the config of its namespace for the
+// workload, in days like "14d" or as a duration like "36h", e.g. a longer window for a batch-heavy workload with
+// monthly spikes or a shorter one for a fast-moving workload.
+const MetricWindowAnnotation = "ottoscalr.io/metric-window"
+
+// Default bounds of the metric windows of the workloads.
+const (
+	DefaultMinMetricWindow = 24 * time.Hour
+	DefaultMaxMetricWindow = 90 * 24 * time.Hour
+)
+
+// WithMetricWindowBounds bounds the metric windows the workloads can override with the MetricWindowAnnotation, e.g. by
+// the retention of the Prometheus instances, in place of DefaultMinMetricWindow and DefaultMaxMetricWindow.
+func (c *CpuUtilizationBasedRecommender) WithMetricWindowBounds(min, max time.Duration) *CpuUtilizationBasedRecommender {
+	c.minMetricWindow, c.maxMetricWindow = min, max
+	return c
+}
+
+// workloadMetricWindow returns the metric window of the annotation of the workload, or metricWindow if the workload
+// isn't annotated.
+func (c *CpuUtilizationBasedRecommender) workloadMetricWindow(workloadMeta WorkloadMeta,
+	metricWindow time.Duration) (time.Duration, error) {
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return 0, err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := workload.GetAnnotations()[MetricWindowAnnotation]
+	if !ok {
+		return metricWindow, nil
+	}
+	window, err := c.parseMetricWindow(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation of the workload %s/%s: %v", MetricWindowAnnotation,
+			workloadMeta.Namespace, workloadMeta.Name, err)
+	}
+	return window, nil
+}
+
+// parseMetricWindow parses a metric window in days like "14d" or as a duration like "36h", and checks that it's within
+// the bounds of the recommender.
+func (c *CpuUtilizationBasedRecommender) parseMetricWindow(value string) (time.Duration, error) {
+	var window time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid metric window %q", value)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if window, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("invalid metric window %q", value)
+		}
+	}
+
+	min, max := c.minMetricWindow, c.maxMetricWindow
+	if min <= 0 {
+		min = DefaultMinMetricWindow
+	}
+	if max <= 0 {
+		max = DefaultMaxMetricWindow
+	}
+	if window < min || window > max {
+		return 0, fmt.Errorf("metric window %s is out of the bounds [%s, %s]", value, min, max)
+	}
+	return window, nil
+}
@@ -0,0 +1,72 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Metric window of the workloads", func() {
+	var windowRecommender *CpuUtilizationBasedRecommender
+
+	newDeployment := func(name string, window string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "window-ns"}}
+		if window != "" {
+			deployment.Annotations = map[string]string{MetricWindowAnnotation: window}
+		}
+		return deployment
+	}
+	workloadMeta := func(name string) WorkloadMeta {
+		return WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name, Namespace: "window-ns"}
+	}
+
+	BeforeEach(func() {
+		fakeScheme := runtime.NewScheme()
+		Expect(appsv1.AddToScheme(fakeScheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			newDeployment("default", ""),
+			newDeployment("batch", "56d"),
+			newDeployment("fast", "36h"),
+			newDeployment("too-long", "365d"),
+			newDeployment("invalid", "two weeks"),
+		).Build()
+		windowRecommender = &CpuUtilizationBasedRecommender{logger: logr.Discard(),
+			clientsRegistry: registry.DeploymentClientRegistry{Clients: []registry.ObjectClient{registry.NewDeploymentClient(fakeClient)}},
+		}
+	})
+
+	It("should override the metric window with the annotation of the workload", func() {
+		window, err := windowRecommender.workloadMetricWindow(workloadMeta("default"), 28*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window).To(Equal(28 * 24 * time.Hour))
+
+		window, err = windowRecommender.workloadMetricWindow(workloadMeta("batch"), 28*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window).To(Equal(56 * 24 * time.Hour))
+
+		window, err = windowRecommender.workloadMetricWindow(workloadMeta("fast"), 28*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window).To(Equal(36 * time.Hour))
+	})
+
+	It("should reject the windows which are invalid or out of the bounds", func() {
+		_, err := windowRecommender.workloadMetricWindow(workloadMeta("too-long"), 28*24*time.Hour)
+		Expect(err).To(MatchError(ContainSubstring("out of the bounds")))
+		_, err = windowRecommender.workloadMetricWindow(workloadMeta("invalid"), 28*24*time.Hour)
+		Expect(err).To(HaveOccurred())
+
+		windowRecommender.WithMetricWindowBounds(7*24*time.Hour, 400*24*time.Hour)
+		window, err := windowRecommender.workloadMetricWindow(workloadMeta("too-long"), 28*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window).To(Equal(365 * 24 * time.Hour))
+		_, err = windowRecommender.workloadMetricWindow(workloadMeta("fast"), 28*24*time.Hour)
+		Expect(err).To(HaveOccurred())
+	})
+})
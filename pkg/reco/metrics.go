@@ -0,0 +1,30 @@
+package reco
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectors are the metrics of the package, registered on the registry of the consumer by RegisterMetrics.
+var collectors []prometheus.Collector
+
+func registerCollectors(cs ...prometheus.Collector) {
+	collectors = append(collectors, cs...)
+}
+
+// RegisterMetrics registers the metrics of the package on the registerer, e.g. the registry of the controller-runtime
+// manager. Registering them more than once on the same registerer is a no-op, so the package can back several
+// consumers in one process. The metrics are recorded whether they're registered or not.
+func RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if errors.As(err, &alreadyRegistered) && alreadyRegistered.ExistingCollector == collector {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
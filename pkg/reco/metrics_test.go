@@ -0,0 +1,74 @@
+package reco
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ = Describe("RegisterMetrics", func() {
+	It("should register the metrics on the registerer of the consumer any number of times", func() {
+		registry := prometheus.NewRegistry()
+		Expect(RegisterMetrics(registry)).To(Succeed())
+		Expect(RegisterMetrics(registry)).To(Succeed())
+		Expect(RegisterMetrics(prometheus.NewRegistry())).To(Succeed())
+
+		noOpRecommendedGauge.WithLabelValues("test-ns", "test-workload", "InsufficientMetrics").Set(1)
+		defer noOpRecommendedGauge.DeleteLabelValues("test-ns", "test-workload", "InsufficientMetrics")
+		families, err := registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		Expect(names).To(ContainElement("noop_recommended"))
+	})
+
+	It("should fail on a conflicting metric", func() {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "noop_recommended", Help: "conflicting"}))
+		Expect(RegisterMetrics(registry)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("no_op_recommendation_total", func() {
+	It("should count the no-op recommendations by the reason the workloads were left unoptimized for", func() {
+		counted := func(reason string) float64 {
+			metric := &dto.Metric{}
+			Expect(noOpRecommendationCounter.WithLabelValues("noop-ns", reason).Write(metric)).To(Succeed())
+			return metric.GetCounter().GetValue()
+		}
+		defer noOpRecommendationCounter.DeletePartialMatch(prometheus.Labels{"namespace": "noop-ns"})
+
+		workloadMeta := WorkloadMeta{Name: "test-workload", Namespace: "noop-ns"}
+		logNoOpRecommendation(workloadMeta, &NoOpRecommendation{Reason: InsufficientHistoryReason})
+		logNoOpRecommendation(workloadMeta, &NoOpRecommendation{Reason: InsufficientMetricsReason})
+		logNoOpRecommendation(workloadMeta, &NoOpRecommendation{Reason: NoBreachFreeConfigReason})
+		logNoOpRecommendation(workloadMeta, nil)
+		CountNoOpRecommendation("noop-ns", NoOpFrozen)
+		Expect(counted(NoOpInsufficientData)).To(Equal(2.0))
+		Expect(counted(NoOpUnableToRecommend)).To(Equal(1.0))
+		Expect(counted(NoOpFrozen)).To(Equal(1.0))
+		Expect(counted(NoOpConflicted)).To(BeZero())
+	})
+})
+
+var _ = Describe("observeWithExemplar", func() {
+	It("should tag the latency with the query ID and the trace ID of a traced query", func() {
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_latency_seconds", Help: "test"})
+		traceID := trace.TraceID{1, 2, 3}
+		observeWithExemplar(histogram, 1, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID}), "abc")
+
+		metric := &dto.Metric{}
+		Expect(histogram.Write(metric)).To(Succeed())
+		labels := map[string]string{}
+		for _, bucket := range metric.GetHistogram().GetBucket() {
+			for _, label := range bucket.GetExemplar().GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+		}
+		Expect(labels).To(Equal(map[string]string{"query_id": "abc", "trace_id": traceID.String()}))
+	})
+})
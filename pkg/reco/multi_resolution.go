@@ -0,0 +1,192 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MultiResolution scans the metric window of the workloads at a multiple of the metric step and verifies the
+// recommended configs at the metric step only around the daily peaks of the window, which is where the coarse
+// datapoints miss the short spikes breaching the redline.
+type MultiResolution struct {
+	// CoarseFactor is the multiple of the metric step the window is scanned at.
+	CoarseFactor int
+	// PeakWindow is the width of the period around the peak of every day of the window which is verified at the
+	// metric step.
+	PeakWindow time.Duration
+}
+
+const (
+	fineResolutionVerified = "verified"
+	fineResolutionRefined  = "refined"
+)
+
+var (
+	fineResolutionDataPointsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "fine_resolution_datapoints",
+			Help: "Number of the datapoints fetched at the metric step to verify the last recommendation of the workload"},
+		[]string{"namespace", "workload"},
+	)
+
+	fineResolutionVerificationsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "fine_resolution_verifications_count",
+			Help: "Number of the recommendations verified at the metric step, by whether the search had to be redone"},
+		[]string{"namespace", "result"},
+	)
+)
+
+func init() {
+	registerCollectors(fineResolutionDataPointsGauge, fineResolutionVerificationsCounter)
+}
+
+// WithMultiResolution makes the recommender search the optimal config on the datapoints of the window at the coarse
+// step and simulate the config it finds on the datapoints at the metric step around the daily peaks. If the config
+// breaches there, the coarse datapoints of the peaks are raised to the spikes within their steps and the search is
+// redone on them. The datapoints pulled per workload drop by about the coarse factor.
+func (c *CpuUtilizationBasedRecommender) WithMultiResolution(resolution MultiResolution) *CpuUtilizationBasedRecommender {
+	c.multiResolution = &resolution
+	return c
+}
+
+// coarseMetricStep returns the step the window is scanned at, which is the metric step unless the recommender scans
+// it coarser.
+func (c *CpuUtilizationBasedRecommender) coarseMetricStep() time.Duration {
+	if c.multiResolution == nil || c.multiResolution.CoarseFactor <= 1 {
+		return c.metricStep
+	}
+	return c.metricStep * time.Duration(c.multiResolution.CoarseFactor)
+}
+
+// peakWindow is a period around a daily peak, as the indexes of its first and last coarse datapoints.
+type peakWindow struct {
+	from, to int
+}
+
+// peakWindows returns the periods of the peak window around the datapoint of the highest demand of every day, in
+// order. The periods of the peaks close to each other are merged.
+func (c *CpuUtilizationBasedRecommender) peakWindows(demand []metrics.DataPoint) []peakWindow {
+	if len(demand) == 0 {
+		return nil
+	}
+	location := c.patternLocation()
+	var peaks []int
+	day := ""
+	for i, dp := range demand {
+		if key := dp.Timestamp.In(location).Format("2006-01-02"); key != day {
+			day = key
+			peaks = append(peaks, i)
+		} else if dp.Value > demand[peaks[len(peaks)-1]].Value {
+			peaks[len(peaks)-1] = i
+		}
+	}
+
+	half := int(c.multiResolution.PeakWindow / 2 / c.metricStep)
+	var windows []peakWindow
+	for _, peak := range peaks {
+		window := peakWindow{from: peak - half, to: peak + half}
+		if window.from < 0 {
+			window.from = 0
+		}
+		if window.to >= len(demand) {
+			window.to = len(demand) - 1
+		}
+		if n := len(windows); n > 0 && window.from <= windows[n-1].to+1 {
+			windows[n-1].to = window.to
+			continue
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// refineAtFineResolution simulates the config on the datapoints at the fine step within the peak windows of the
+// coarse datapoints. The fine datapoints go through the metrics transformers like the coarse ones. If the config
+// breaches in any of the windows, it returns the coarse datapoints, and the demand of the profile, raised by the
+// ratio of the highest fine datapoint within their step to the one at their timestamp, which carries the spikes
+// over to the coarse datapoints however they were adjusted, and true. It also returns the number of the fine
+// datapoints it fetched.
+func (c *CpuUtilizationBasedRecommender) refineAtFineResolution(workloadMeta WorkloadMeta,
+	dataPoints []metrics.DataPoint, profile trafficProfile, fineStep time.Duration, acl time.Duration,
+	perPodResources float64, targetUtilization, minReplicas, maxReplicas int) ([]metrics.DataPoint, trafficProfile,
+	bool, int, error) {
+	raised := dataPoints
+	refined := false
+	fineDataPoints := 0
+	for _, window := range c.peakWindows(profile.demandOf(dataPoints)) {
+		start, end := dataPoints[window.from].Timestamp.Add(-c.metricStep+fineStep), dataPoints[window.to].Timestamp
+		fine, err := c.scraper.GetAverageCPUUtilizationByWorkload(workloadMeta.Namespace, workloadMeta.Name, start, end,
+			fineStep)
+		if err != nil {
+			return nil, profile, false, fineDataPoints, err
+		}
+		for _, transformer := range c.metricsTransformer {
+			if fine, err = transformer.Transform(start, end, fine); err != nil {
+				return nil, profile, false, fineDataPoints, err
+			}
+		}
+		fineDataPoints += len(fine)
+
+		simulated, _, err := c.simulateHPA(fine, acl, targetUtilization, perPodResources, maxReplicas, minReplicas)
+		if err != nil {
+			return nil, profile, false, fineDataPoints, err
+		}
+		if c.hasNoBreachOccurred(fine, simulated) {
+			continue
+		}
+		if !refined {
+			raised, refined = append([]metrics.DataPoint(nil), dataPoints...), true
+		}
+		c.raiseToFinePeaks(raised[window.from:window.to+1], fine)
+	}
+	if !refined {
+		return dataPoints, profile, false, fineDataPoints, nil
+	}
+
+	if profile.demand != nil {
+		demand := append([]metrics.DataPoint(nil), profile.demand...)
+		for i := range demand {
+			demand[i].Value += raised[i].Value - dataPoints[i].Value
+		}
+		profile.demand = demand
+	}
+	return raised, profile, true, fineDataPoints, nil
+}
+
+// raiseToFinePeaks raises every coarse datapoint by the ratio of the highest fine datapoint within the step ending at
+// it to the fine datapoint at it. The coarse datapoints whose fine one is zero are raised to the highest one.
+func (c *CpuUtilizationBasedRecommender) raiseToFinePeaks(coarse, fine []metrics.DataPoint) {
+	j := 0
+	for i := range coarse {
+		stepStart := coarse[i].Timestamp.Add(-c.metricStep)
+		for j < len(fine) && !fine[j].Timestamp.After(stepStart) {
+			j++
+		}
+		peak, at, found := 0.0, 0.0, false
+		for ; j < len(fine) && !fine[j].Timestamp.After(coarse[i].Timestamp); j++ {
+			if !found || fine[j].Value > peak {
+				peak, found = fine[j].Value, true
+			}
+			at = fine[j].Value
+		}
+		switch {
+		case !found:
+		case at > 0:
+			if peak > at {
+				coarse[i].Value *= peak / at
+			}
+		case peak > coarse[i].Value:
+			coarse[i].Value = peak
+		}
+	}
+}
+
+func logFineResolution(workloadMeta WorkloadMeta, fineDataPoints int, refined bool) {
+	fineResolutionDataPointsGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(float64(fineDataPoints))
+	result := fineResolutionVerified
+	if refined {
+		result = fineResolutionRefined
+	}
+	fineResolutionVerificationsCounter.WithLabelValues(workloadMeta.Namespace, result).Inc()
+}
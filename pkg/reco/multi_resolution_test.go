@@ -0,0 +1,109 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// stepScraper scrapes the utilization of the function at the step over the range it's asked for.
+type stepScraper struct {
+	FakeScraper
+	utilization func(t time.Time) float64
+	scraped     int
+}
+
+func (s *stepScraper) GetAverageCPUUtilizationByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]metrics.DataPoint, error) {
+	var dataPoints []metrics.DataPoint
+	for t := start; !t.After(end); t = t.Add(step) {
+		dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: t, Value: s.utilization(t)})
+	}
+	s.scraped += len(dataPoints)
+	return dataPoints, nil
+}
+
+var _ = Describe("Multi resolution", func() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48*time.Hour - 5*time.Minute)
+	workloadMeta := WorkloadMeta{Name: "spiky", Namespace: "default"}
+
+	// the utilization humps at 14:00 every day and spikes for 30 seconds in between the coarse steps
+	utilization := func(t time.Time) float64 {
+		hours := t.Sub(t.Truncate(24 * time.Hour)).Hours()
+		if t.Hour() == 14 && t.Minute() == 2 && t.Second() == 0 {
+			return 8
+		}
+		return 1 + 2*math.Exp(-(hours-14)*(hours-14)/2)
+	}
+
+	var (
+		scraper  *stepScraper
+		resolved *CpuUtilizationBasedRecommender
+		coarse   []metrics.DataPoint
+	)
+
+	BeforeEach(func() {
+		scraper = &stepScraper{utilization: utilization}
+		resolved = (&CpuUtilizationBasedRecommender{metricStep: 30 * time.Second, redLineUtil: 0.85, scraper: scraper,
+			logger: logr.Discard()}).WithMultiResolution(MultiResolution{CoarseFactor: 10, PeakWindow: time.Hour})
+		resolved = resolved.withMetricStep(resolved.coarseMetricStep())
+		coarse, _ = scraper.GetAverageCPUUtilizationByWorkload("default", "spiky", start, end, resolved.metricStep)
+		scraper.scraped = 0
+	})
+
+	It("should scan the window at the coarse factor of the metric step", func() {
+		Expect(resolved.metricStep).To(Equal(5 * time.Minute))
+		Expect((&CpuUtilizationBasedRecommender{metricStep: time.Minute}).coarseMetricStep()).To(Equal(time.Minute))
+	})
+
+	It("should verify the periods around the peak of every day, merging the close ones", func() {
+		Expect(resolved.peakWindows(coarse)).To(Equal([]peakWindow{{from: 162, to: 174}, {from: 450, to: 462}}))
+
+		lateNight := append([]metrics.DataPoint(nil), coarse...)
+		lateNight[287].Value, lateNight[288].Value = 10, 10
+		Expect(resolved.peakWindows(lateNight)).To(Equal([]peakWindow{{from: 281, to: 294}}))
+	})
+
+	It("should raise the coarse datapoints by the spikes within their steps", func() {
+		at := start.Add(14*time.Hour + 5*time.Minute)
+		raised := []metrics.DataPoint{{Timestamp: at, Value: 6}}
+		resolved.raiseToFinePeaks(raised, []metrics.DataPoint{{Timestamp: at.Add(-5 * time.Minute), Value: 9},
+			{Timestamp: at.Add(-time.Minute), Value: 4}, {Timestamp: at, Value: 2}})
+		Expect(raised[0].Value).To(Equal(12.0))
+	})
+
+	It("should redo the search on the spikes the coarse step missed", func() {
+		raised, _, refined, fineDataPoints, err := resolved.refineAtFineResolution(workloadMeta, coarse, trafficProfile{},
+			30*time.Second, time.Minute, 1, 80, 1, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refined).To(BeTrue())
+		Expect(fineDataPoints).To(Equal(scraper.scraped))
+		Expect(fineDataPoints * 10).To(BeNumerically("<", 2*24*120))
+
+		// the coarse datapoints of the falling side of the humps are raised to the higher start of their steps too
+		spikedAt := start.Add(14*time.Hour + 5*time.Minute)
+		for i, dp := range raised {
+			switch {
+			case dp.Timestamp.Equal(spikedAt) || dp.Timestamp.Equal(spikedAt.Add(24*time.Hour)):
+				Expect(dp.Value).To(BeNumerically("~", 8, 1e-9))
+			case (i >= 162 && i <= 174) || (i >= 450 && i <= 462):
+				Expect(dp.Value).To(BeNumerically(">=", coarse[i].Value))
+			default:
+				Expect(dp).To(Equal(coarse[i]))
+			}
+		}
+	})
+
+	It("should keep the coarse datapoints if the config doesn't breach at the metric step", func() {
+		raised, _, refined, _, err := resolved.refineAtFineResolution(workloadMeta, coarse, trafficProfile{},
+			30*time.Second, time.Minute, 1, 80, 10, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refined).To(BeFalse())
+		Expect(raised).To(Equal(coarse))
+	})
+})
@@ -0,0 +1,60 @@
+package reco
+
+import (
+	"math"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	networkBoundDataPointsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "network_bound_datapoints_percent",
+			Help: "Percent of the datapoints of the workload which need more replicas for the network ceiling than for the cpu"},
+		[]string{"namespace", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(networkBoundDataPointsGauge)
+}
+
+// WithNetworkCeiling makes the recommender keep the network throughput of every pod under the ceiling, in bytes per
+// second, along with their cpu utilization, for the workloads like proxies which saturate their NICs before their
+// cpus.
+func (c *CpuUtilizationBasedRecommender) WithNetworkCeiling(scraper metrics.NetworkScraper,
+	bytesPerSecond float64) *CpuUtilizationBasedRecommender {
+	c.networkScraper = scraper
+	c.networkCeiling = bytesPerSecond
+	return c
+}
+
+// networkDemand returns the capacity the simulated HPA has to provide at every datapoint, which is its cpu
+// utilization or, if higher, the capacity of the replicas keeping the network throughput of every pod under the
+// ceiling. The throughput of a datapoint is the latest one at or before it. It also returns the percent of the
+// datapoints bound by the network.
+func (c *CpuUtilizationBasedRecommender) networkDemand(dataPoints, throughput []metrics.DataPoint,
+	perPodResources float64) ([]metrics.DataPoint, float64) {
+	demand := make([]metrics.DataPoint, len(dataPoints))
+	networkBound := 0
+	j := -1
+	for i, dp := range dataPoints {
+		demand[i] = dp
+		for j+1 < len(throughput) && !throughput[j+1].Timestamp.After(dp.Timestamp) {
+			j++
+		}
+		if j < 0 {
+			continue
+		}
+		// the simulated capacity of the ready replicas is their resources at the red line utilization
+		replicas := throughput[j].Value / c.networkCeiling
+		if networkCapacity := replicas * perPodResources * c.redLineUtil; networkCapacity > dp.Value {
+			demand[i].Value = networkCapacity
+			networkBound++
+		}
+	}
+	if len(dataPoints) == 0 {
+		return demand, 0
+	}
+	return demand, math.Round(float64(networkBound) * 10000 / float64(len(dataPoints)) / 100)
+}
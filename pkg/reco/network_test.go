@@ -0,0 +1,71 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network ceiling", func() {
+	var (
+		networkRecommender *CpuUtilizationBasedRecommender
+		end                = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	series := func(values ...float64) []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for i, value := range values {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: end.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+		return dataPoints
+	}
+
+	BeforeEach(func() {
+		networkRecommender = (&CpuUtilizationBasedRecommender{redLineUtil: 0.8, logger: logr.Discard()}).
+			WithNetworkCeiling(nil, 100)
+	})
+
+	It("should raise the demand of the datapoints needing more replicas for the network than for the cpu", func() {
+		demand, networkBoundPercent := networkRecommender.networkDemand(series(2, 2, 2, 2), series(100, 800, 50, 500), 1)
+		Expect(demand).To(Equal(series(2, 6.4, 2, 4)))
+		Expect(networkBoundPercent).To(Equal(50.0))
+	})
+
+	It("should use the latest throughput at or before every datapoint", func() {
+		throughput := []metrics.DataPoint{
+			{Timestamp: end.Add(30 * time.Second), Value: 500},
+			{Timestamp: end.Add(150 * time.Second), Value: 100},
+		}
+		demand, _ := networkRecommender.networkDemand(series(2, 2, 2, 2), throughput, 1)
+		Expect(demand).To(Equal(series(2, 4, 4, 2)))
+	})
+
+	It("should recommend the configs keeping the network throughput of the pods under the ceiling", func() {
+		var cpu, throughput []float64
+		for i := 0; i < 120; i++ {
+			cpu = append(cpu, 2)
+			throughput = append(throughput, 800)
+		}
+		dataPoints := series(cpu...)
+		target, minReplicas, maxReplicas, err := networkRecommender.findOptimalHPAConfigurations(dataPoints, 0, 10, 60, 1,
+			10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(minReplicas).To(BeNumerically("<", 8))
+
+		demand, _ := networkRecommender.networkDemand(dataPoints, series(throughput...), 1)
+		networkTarget, networkMin, _, err := networkRecommender.findOptimalProfiledHPAConfigurations(dataPoints,
+			trafficProfile{demand: demand}, 0, 10, 60, 1, 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networkTarget <= target || networkMin > minReplicas).To(BeTrue())
+
+		simulated, _, err := networkRecommender.simulateHPA(dataPoints, 0, networkTarget, 1, maxReplicas, networkMin)
+		Expect(err).NotTo(HaveOccurred())
+		for _, dp := range simulated {
+			// 8 ready replicas of 1 cpu at the red line keep the 800 bytes per second under the ceiling of 100 per pod
+			Expect(dp.Value).To(BeNumerically(">=", 6.4))
+		}
+	})
+})
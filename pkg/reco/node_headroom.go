@@ -0,0 +1,59 @@
+package reco
+
+import (
+	"math"
+	"time"
+)
+
+// NodeHeadroom is the spare capacity of the nodes of the cluster, which the upscales of a workload can be scheduled
+// on within its ACL. The upscales beyond it wait for the cluster-autoscaler to provision new nodes, which takes
+// minutes rather than the ACL of the pods.
+type NodeHeadroom struct {
+	// CPUs is the spare cpu of the nodes a workload can scale up into without new nodes. The cluster-autoscaler is
+	// expected to restore it once the new nodes join, e.g. with overprovisioning pods.
+	CPUs float64
+	// ProvisioningPenalty is the time the new nodes take to join the cluster, which delays the upscales beyond the
+	// headroom on top of the ACL.
+	ProvisioningPenalty time.Duration
+}
+
+// WithNodeHeadroom makes the simulations of the recommender delay the upscales exceeding the node headroom by the
+// node provisioning penalty, so that the recommended min replicas account for the time the cluster takes to scale
+// from the min replicas to the peak.
+func (c *CpuUtilizationBasedRecommender) WithNodeHeadroom(headroom NodeHeadroom) *CpuUtilizationBasedRecommender {
+	c.nodeHeadroom = &headroom
+	return c
+}
+
+// scheduleUpscale returns the timers of the resources of the upscale by delta at the time, on top of the pending
+// upscales. The resources within the node headroom left by the pending upscales are ready after the ACL and the rest
+// after the node provisioning penalty too. The timers are kept sorted by their timestamps.
+func (c *CpuUtilizationBasedRecommender) scheduleUpscale(timers []TimerEvent, at time.Time, acl time.Duration,
+	delta float64) []TimerEvent {
+	if c.nodeHeadroom == nil || c.nodeHeadroom.ProvisioningPenalty <= 0 {
+		return append(timers, TimerEvent{Timestamp: at.Add(acl), Delta: delta})
+	}
+	headroom := c.nodeHeadroom.CPUs
+	for _, timer := range timers {
+		headroom -= timer.Delta
+	}
+	withinHeadroom := math.Min(delta, math.Max(headroom, 0))
+	if withinHeadroom > 0 {
+		timers = insertTimer(timers, TimerEvent{Timestamp: at.Add(acl), Delta: withinHeadroom})
+	}
+	if beyondHeadroom := delta - withinHeadroom; beyondHeadroom > 0 {
+		timers = insertTimer(timers, TimerEvent{Timestamp: at.Add(acl + c.nodeHeadroom.ProvisioningPenalty), Delta: beyondHeadroom})
+	}
+	return timers
+}
+
+func insertTimer(timers []TimerEvent, timer TimerEvent) []TimerEvent {
+	i := len(timers)
+	for i > 0 && timers[i-1].Timestamp.After(timer.Timestamp) {
+		i--
+	}
+	timers = append(timers, TimerEvent{})
+	copy(timers[i+1:], timers[i:])
+	timers[i] = timer
+	return timers
+}
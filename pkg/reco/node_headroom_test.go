@@ -0,0 +1,77 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node headroom", func() {
+	var (
+		headroomRecommender *CpuUtilizationBasedRecommender
+		start               = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		headroomRecommender = (&CpuUtilizationBasedRecommender{redLineUtil: 0.8, logger: logr.Discard()}).
+			WithNodeHeadroom(NodeHeadroom{CPUs: 2, ProvisioningPenalty: 5 * time.Minute})
+	})
+
+	It("should delay the upscales beyond the headroom by the provisioning penalty", func() {
+		timers := headroomRecommender.scheduleUpscale(nil, start, time.Minute, 3)
+		Expect(timers).To(Equal([]TimerEvent{
+			{Timestamp: start.Add(time.Minute), Delta: 2},
+			{Timestamp: start.Add(6 * time.Minute), Delta: 1},
+		}))
+
+		// the pending upscales have taken up all the headroom
+		timers = headroomRecommender.scheduleUpscale(timers, start.Add(time.Minute), time.Minute, 1)
+		Expect(timers).To(Equal([]TimerEvent{
+			{Timestamp: start.Add(time.Minute), Delta: 2},
+			{Timestamp: start.Add(6 * time.Minute), Delta: 1},
+			{Timestamp: start.Add(7 * time.Minute), Delta: 1},
+		}))
+	})
+
+	It("should keep the timers sorted", func() {
+		timers := headroomRecommender.scheduleUpscale(nil, start, time.Minute, 3)
+		headroomRecommender.nodeHeadroom.CPUs = 10
+		timers = headroomRecommender.scheduleUpscale(timers, start.Add(time.Minute), time.Minute, 1)
+		Expect(timers).To(Equal([]TimerEvent{
+			{Timestamp: start.Add(time.Minute), Delta: 2},
+			{Timestamp: start.Add(2 * time.Minute), Delta: 1},
+			{Timestamp: start.Add(6 * time.Minute), Delta: 1},
+		}))
+	})
+
+	It("should not recommend the configs which can't scale up to the peaks without new nodes in time", func() {
+		var dataPoints []metrics.DataPoint
+		for i := 0; i < 60; i++ {
+			// the traffic ramps up from 1 to 6 cpus over 5 minutes
+			value := 1.0
+			if i >= 30 && i < 45 {
+				value = math.Min(float64(i-28), 6)
+			}
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+		headroomRecommender.nodeHeadroom.CPUs = 1
+		withoutHeadroom := &CpuUtilizationBasedRecommender{redLineUtil: 0.8, logger: logr.Discard()}
+		target, minReplicas, maxReplicas, err := withoutHeadroom.findOptimalHPAConfigurations(dataPoints, time.Minute, 10, 60,
+			1, 20, nil)
+		Expect(err).NotTo(HaveOccurred())
+		simulated, _, err := headroomRecommender.simulateHPA(dataPoints, time.Minute, target, 1, maxReplicas, minReplicas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headroomRecommender.hasNoBreachOccurred(dataPoints, simulated)).To(BeFalse())
+
+		target, minReplicas, maxReplicas, err = headroomRecommender.findOptimalHPAConfigurations(dataPoints, time.Minute, 10,
+			60, 1, 20, nil)
+		Expect(err).NotTo(HaveOccurred())
+		simulated, _, err = headroomRecommender.simulateHPA(dataPoints, time.Minute, target, 1, maxReplicas, minReplicas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headroomRecommender.hasNoBreachOccurred(dataPoints, simulated)).To(BeTrue())
+	})
+})
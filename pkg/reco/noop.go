@@ -0,0 +1,89 @@
+package reco
+
+import (
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons of the no-op recommendations.
+const (
+	// InsufficientHistoryReason is recommended for the workloads younger than the min workload age.
+	InsufficientHistoryReason = "InsufficientHistory"
+	// InsufficientMetricsReason is recommended for the workloads without enough datapoints in the metrics window.
+	InsufficientMetricsReason = "InsufficientMetrics"
+	// NoBreachFreeConfigReason is recommended for the workloads which breach the redline even at their max replicas.
+	NoBreachFreeConfigReason = "NoBreachFreeConfig"
+)
+
+// Reasons of the no_op_recommendation_total counter, which groups the causes of the workloads not being optimized.
+const (
+	// NoOpInsufficientData counts the no-op recommendations for lack of history or metrics.
+	NoOpInsufficientData = "insufficient_data"
+	// NoOpUnableToRecommend counts the no-op recommendations for lack of a config which doesn't breach.
+	NoOpUnableToRecommend = "unable_to_recommend"
+	// NoOpFrozen counts the recommendations skipped as the recommendation of the workload is frozen.
+	NoOpFrozen = "frozen"
+	// NoOpExcluded counts the recommendations skipped as the workload is excluded from autoscaling, e.g. as it's owned
+	// by a Job.
+	NoOpExcluded = "excluded"
+	// NoOpConflicted counts the autoscalers not enforced as they conflict with another autoscaler of the workload, e.g.
+	// a VPA.
+	NoOpConflicted = "conflicted"
+)
+
+var (
+	noOpRecommendationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "no_op_recommendation_total",
+			Help: "Number of the times the workloads were left unoptimized, by the reason"},
+		[]string{"namespace", "reason"},
+	)
+
+	noOpRecommendedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "noop_recommended",
+			Help: "Boolean to show if the workload was recommended the no-op configuration as it couldn't be recommended"},
+		[]string{"namespace", "workload", "reason"},
+	)
+)
+
+func init() {
+	registerCollectors(noOpRecommendedGauge, noOpRecommendationCounter)
+}
+
+// NoOpRecommendation marks a recommendation as the no-op configuration, which keeps the workload at its max
+// replicas, recommended because the recommender couldn't recommend a config for the workload. It tells it apart
+// from a recommendation of running the workload at its full capacity.
+type NoOpRecommendation struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// noOpConfig returns the no-op configuration of a workload with the max replicas and marks the metadata of its
+// recommendation with the reason it was recommended for.
+func (c *CpuUtilizationBasedRecommender) noOpConfig(maxReplicas int, recoMetadata *RecommendationMetadata, reason,
+	message string) *v1alpha1.HPAConfiguration {
+	recoMetadata.NoOp = &NoOpRecommendation{Reason: reason, Message: message}
+	return &v1alpha1.HPAConfiguration{Min: maxReplicas, Max: maxReplicas, TargetMetricValue: c.minTarget}
+}
+
+// CountNoOpRecommendation counts a workload of the namespace left unoptimized for the reason, one of the reasons of
+// the no_op_recommendation_total counter.
+func CountNoOpRecommendation(namespace, reason string) {
+	noOpRecommendationCounter.WithLabelValues(namespace, reason).Inc()
+}
+
+func logNoOpRecommendation(workloadMeta WorkloadMeta, noOp *NoOpRecommendation) {
+	if noOp != nil {
+		reason := NoOpInsufficientData
+		if noOp.Reason == NoBreachFreeConfigReason {
+			reason = NoOpUnableToRecommend
+		}
+		CountNoOpRecommendation(workloadMeta.Namespace, reason)
+	}
+	for _, reason := range []string{InsufficientHistoryReason, InsufficientMetricsReason, NoBreachFreeConfigReason} {
+		value := 0.0
+		if noOp != nil && noOp.Reason == reason {
+			value = 1
+		}
+		noOpRecommendedGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name, reason).Set(value)
+	}
+}
@@ -0,0 +1,110 @@
+package reco
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	pdbMinReplicasRaisedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "reco_pdb_min_replicas_raised_count",
+			Help: "Number of recommendations whose min replicas was raised to honour the PodDisruptionBudgets of the workload"},
+		[]string{"namespace", "workloadKind", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(pdbMinReplicasRaisedCounter)
+}
+
+// PodDisruptionBudgetResolver resolves the min replicas a workload needs to run with for its PodDisruptionBudgets to
+// allow at least one voluntary disruption, so that a recommendation doesn't end up blocking node drains.
+type PodDisruptionBudgetResolver struct {
+	k8sClient       client.Client
+	clientsRegistry registry.DeploymentClientRegistry
+}
+
+func NewPodDisruptionBudgetResolver(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry) *PodDisruptionBudgetResolver {
+	return &PodDisruptionBudgetResolver{
+		k8sClient:       k8sClient,
+		clientsRegistry: clientsRegistry,
+	}
+}
+
+// MinReplicas returns the min replicas required by the PodDisruptionBudgets selecting the pods of the workload along
+// with the names of those budgets. It returns maxReplicas+1 if a budget doesn't allow any disruption within
+// maxReplicas, e.g. minAvailable of 100% or maxUnavailable of 0, and 0 if no budget selects the pods.
+func (r *PodDisruptionBudgetResolver) MinReplicas(ctx context.Context, wm WorkloadMeta, maxReplicas int) (int, []string, error) {
+	objectClient, err := r.clientsRegistry.GetObjectClient(wm.Kind)
+	if err != nil {
+		return 0, nil, err
+	}
+	podLabels, err := objectClient.GetPodTemplateLabels(wm.Namespace, wm.Name)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	if err := r.k8sClient.List(ctx, pdbList, client.InNamespace(wm.Namespace)); err != nil {
+		return 0, nil, err
+	}
+
+	minReplicas := 0
+	var budgets []string
+	for _, pdb := range pdbList.Items {
+		// a nil selector selects no pods while an empty one selects all the pods in the namespace.
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid selector in PodDisruptionBudget %s: %v", pdb.Name, err)
+		}
+		if !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+		required, err := pdbMinReplicas(pdb, maxReplicas)
+		if err != nil {
+			return 0, nil, err
+		}
+		budgets = append(budgets, pdb.Name)
+		if required > minReplicas {
+			minReplicas = required
+		}
+	}
+	sort.Strings(budgets)
+	return minReplicas, budgets, nil
+}
+
+// pdbMinReplicas returns the least replicas for which the budget allows a disruption, scaling percentages the way
+// the disruption controller does, or maxReplicas+1 if there's none up to maxReplicas.
+func pdbMinReplicas(pdb policyv1.PodDisruptionBudget, maxReplicas int) (int, error) {
+	for replicas := 1; replicas <= maxReplicas; replicas++ {
+		var disruptionsAllowed int
+		if pdb.Spec.MinAvailable != nil {
+			minAvailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, replicas, true)
+			if err != nil {
+				return 0, fmt.Errorf("invalid minAvailable in PodDisruptionBudget %s: %v", pdb.Name, err)
+			}
+			disruptionsAllowed = replicas - minAvailable
+		} else if pdb.Spec.MaxUnavailable != nil {
+			maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, replicas, true)
+			if err != nil {
+				return 0, fmt.Errorf("invalid maxUnavailable in PodDisruptionBudget %s: %v", pdb.Name, err)
+			}
+			disruptionsAllowed = maxUnavailable
+		} else {
+			return 1, nil
+		}
+		if disruptionsAllowed >= 1 {
+			return replicas, nil
+		}
+	}
+	return maxReplicas + 1, nil
+}
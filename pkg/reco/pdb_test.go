@@ -0,0 +1,113 @@
+package reco
+
+import (
+	"context"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("PodDisruptionBudgetResolver", func() {
+	var wm WorkloadMeta
+
+	newPDB := func(name string, selector *metav1.LabelSelector, minAvailable, maxUnavailable *intstr.IntOrString) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "pdb-ns"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: minAvailable, MaxUnavailable: maxUnavailable},
+		}
+	}
+	intOrString := func(value intstr.IntOrString) *intstr.IntOrString {
+		return &value
+	}
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}}
+
+	newResolver := func(pdbs ...client.Object) *PodDisruptionBudgetResolver {
+		fakeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(fakeScheme)).To(Succeed())
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "pdb-ns"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "payments", "tier": "api"}},
+			}},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(append(pdbs, deployment)...).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(fakeClient)).Build()
+		return NewPodDisruptionBudgetResolver(fakeClient, *clientsRegistry)
+	}
+
+	BeforeEach(func() {
+		wm = WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "payments", Namespace: "pdb-ns"}
+	})
+
+	It("should require a replica more than minAvailable for a pod to be evictable", func() {
+		resolver := newResolver(
+			newPDB("payments-pdb", appSelector, intOrString(intstr.FromInt(3)), nil),
+			newPDB("other-pdb", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "checkout"}}, intOrString(intstr.FromInt(10)), nil),
+		)
+		minReplicas, budgets, err := resolver.MinReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(minReplicas).To(Equal(4))
+		Expect(budgets).To(Equal([]string{"payments-pdb"}))
+	})
+
+	It("should scale the percentages and pick the strictest budget", func() {
+		resolver := newResolver(
+			newPDB("percent-pdb", appSelector, intOrString(intstr.FromString("80%")), nil),
+			newPDB("unavailable-pdb", &metav1.LabelSelector{}, nil, intOrString(intstr.FromString("10%"))),
+		)
+		minReplicas, budgets, err := resolver.MinReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		// ceil(80% of 5) = 4 leaves one pod to be evicted
+		Expect(minReplicas).To(Equal(5))
+		Expect(budgets).To(Equal([]string{"percent-pdb", "unavailable-pdb"}))
+	})
+
+	It("should report the budgets which don't allow any disruption", func() {
+		resolver := newResolver(newPDB("payments-pdb", appSelector, nil, intOrString(intstr.FromInt(0))))
+		minReplicas, _, err := resolver.MinReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(minReplicas).To(Equal(21))
+	})
+
+	It("should not require any replicas without budgets selecting the pods", func() {
+		resolver := newResolver(newPDB("nil-selector-pdb", nil, intOrString(intstr.FromInt(3)), nil))
+		minReplicas, budgets, err := resolver.MinReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(minReplicas).To(Equal(0))
+		Expect(budgets).To(BeEmpty())
+	})
+
+	It("should raise the min replicas of the configs to the min replicas required by the budgets", func() {
+		rw := &RecommendationWorkflowImpl{logger: logr.Discard()}
+		explanation := &Explanation{}
+
+		config := rw.honourPodDisruptionBudgets(&v1alpha1.HPAConfiguration{Min: 2, Max: 10, TargetMetricValue: 50}, 4,
+			[]string{"payments-pdb"}, wm, explanation)
+		Expect(*config).To(Equal(v1alpha1.HPAConfiguration{Min: 4, Max: 10, TargetMetricValue: 50}))
+		Expect(explanation.Warnings).To(HaveLen(1))
+
+		config = rw.honourPodDisruptionBudgets(&v1alpha1.HPAConfiguration{Min: 5, Max: 10, TargetMetricValue: 50}, 4,
+			[]string{"payments-pdb"}, wm, explanation)
+		Expect(config.Min).To(Equal(5))
+		Expect(explanation.Warnings).To(HaveLen(1))
+
+		config = rw.honourPodDisruptionBudgets(&v1alpha1.HPAConfiguration{Min: 2, Max: 3, TargetMetricValue: 50}, 4,
+			[]string{"payments-pdb"}, wm, explanation)
+		Expect(config.Min).To(Equal(3))
+		Expect(explanation.Warnings).To(HaveLen(2))
+		Expect(explanation.Warnings[1]).To(ContainSubstring("more than the max replicas 3"))
+	})
+})
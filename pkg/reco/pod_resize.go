@@ -0,0 +1,108 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// podResizeTolerance is the relative change of the per pod resources below which the pods aren't considered resized,
+// which absorbs the pods of both the sizes being averaged while a resize rolls out.
+const podResizeTolerance = 0.05
+
+var (
+	resizedDataPointsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "pod_resized_datapoints_percent",
+			Help: "Percent of the datapoints of the metrics window of the workload recorded under other per pod resources than its current ones"},
+		[]string{"namespace", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(resizedDataPointsGauge)
+}
+
+// PodResize describes the datapoints of the metrics window recorded before the pods of the workload were resized,
+// e.g. by a VPA or on a change of their limits, and how they were carried over to the current per pod resources.
+type PodResize struct {
+	// ResizedAt is the first datapoint recorded under the current per pod resources, or the end of the metrics window if
+	// there isn't any yet.
+	ResizedAt time.Time `json:"resizedAt"`
+	// PreviousPerPodResources are the per pod resources right before the pods were resized.
+	PreviousPerPodResources float64 `json:"previousPerPodResources"`
+	ResizedDataPoints       int     `json:"resizedDataPoints"`
+	// SplitWindow is set when the datapoints before the resize were dropped, otherwise they were carried over to the
+	// current per pod resources.
+	SplitWindow bool `json:"splitWindow,omitempty"`
+}
+
+// WithPodResizeNormalization makes the recommender carry the datapoints recorded before the pods of a workload were
+// resized over to their current per pod resources, tracked by the scraper. The datapoints before the last resize are
+// dropped if the resize left at least minWindowAfterResize of the metrics window, otherwise every datapoint is scaled
+// by the ratio of the current per pod resources to the ones it was recorded under, unless it's combined from the
+// utilization of the containers, which carries over to the current per pod resources already.
+func (c *CpuUtilizationBasedRecommender) WithPodResizeNormalization(scraper metrics.PodResourcesScraper,
+	minWindowAfterResize time.Duration) *CpuUtilizationBasedRecommender {
+	c.podResourcesScraper = scraper
+	c.minWindowAfterResize = minWindowAfterResize
+	return c
+}
+
+// normalizePodResizes returns the datapoints carried over to the per pod resources, given the per pod resources of the
+// workload over the metrics window ending at end, along with the resize if the pods were resized within the window.
+// The per pod resources of a datapoint are the latest ones at or before it. The datapoints aren't modified in place as
+// they may be shared with the incremental cache.
+func (c *CpuUtilizationBasedRecommender) normalizePodResizes(dataPoints, perPodLimits []metrics.DataPoint,
+	perPodResources float64, end time.Time) ([]metrics.DataPoint, *PodResize) {
+	if perPodResources <= 0 {
+		return dataPoints, nil
+	}
+	sizes := make([]float64, len(dataPoints))
+	lastResized, resizedDataPoints := -1, 0
+	j := -1
+	for i, dp := range dataPoints {
+		for j+1 < len(perPodLimits) && !perPodLimits[j+1].Timestamp.After(dp.Timestamp) {
+			j++
+		}
+		sizes[i] = perPodResources
+		if j < 0 || perPodLimits[j].Value <= 0 {
+			continue
+		}
+		if math.Abs(perPodLimits[j].Value-perPodResources)/perPodResources > podResizeTolerance {
+			sizes[i] = perPodLimits[j].Value
+			lastResized = i
+			resizedDataPoints++
+		}
+	}
+	if lastResized < 0 {
+		return dataPoints, nil
+	}
+
+	resize := &PodResize{ResizedAt: end, PreviousPerPodResources: sizes[lastResized], ResizedDataPoints: resizedDataPoints}
+	if lastResized+1 < len(dataPoints) {
+		resize.ResizedAt = dataPoints[lastResized+1].Timestamp
+	}
+	if c.minWindowAfterResize > 0 && end.Sub(resize.ResizedAt) >= c.minWindowAfterResize {
+		resize.SplitWindow = true
+		return dataPoints[lastResized+1:], resize
+	}
+	// the demand combined from the utilization of the containers is relative to the limits it was recorded under
+	if c.containerUtilization != nil {
+		return dataPoints, resize
+	}
+	normalized := make([]metrics.DataPoint, len(dataPoints))
+	for i, dp := range dataPoints {
+		normalized[i] = metrics.DataPoint{Timestamp: dp.Timestamp, Value: dp.Value * perPodResources / sizes[i]}
+	}
+	return normalized, resize
+}
+
+func logPodResize(workloadMeta WorkloadMeta, resize *PodResize, dataPoints int) {
+	percent := 0.0
+	if resize != nil && dataPoints > 0 {
+		percent = math.Round(float64(resize.ResizedDataPoints) * 10000 / float64(dataPoints) / 100)
+	}
+	resizedDataPointsGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(percent)
+}
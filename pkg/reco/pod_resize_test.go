@@ -0,0 +1,58 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pod resize normalization", func() {
+	var (
+		resizeRecommender *CpuUtilizationBasedRecommender
+		start             = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+		end               = start.Add(6 * time.Minute)
+	)
+
+	series := func(values ...float64) []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for i, value := range values {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value})
+		}
+		return dataPoints
+	}
+
+	BeforeEach(func() {
+		resizeRecommender = (&CpuUtilizationBasedRecommender{logger: logr.Discard()}).WithPodResizeNormalization(nil, 0)
+	})
+
+	It("should scale the datapoints recorded under other per pod resources to the current ones", func() {
+		dataPoints := series(2, 2, 3, 4, 4, 4)
+		normalized, resize := resizeRecommender.normalizePodResizes(dataPoints, series(1, 1, 1, 2, 2, 2), 2, end)
+		Expect(normalized).To(Equal(series(4, 4, 6, 4, 4, 4)))
+		Expect(resize).To(Equal(&PodResize{ResizedAt: start.Add(3 * time.Minute), PreviousPerPodResources: 1,
+			ResizedDataPoints: 3}))
+		// the datapoints may be shared with the incremental cache
+		Expect(dataPoints).To(Equal(series(2, 2, 3, 4, 4, 4)))
+	})
+
+	It("should split the window if the resize left enough of it", func() {
+		resizeRecommender.WithPodResizeNormalization(nil, 3*time.Minute)
+		normalized, resize := resizeRecommender.normalizePodResizes(series(2, 2, 3, 4, 4, 4), series(1, 1, 1, 2, 2, 2), 2, end)
+		Expect(normalized).To(Equal(series(2, 2, 3, 4, 4, 4)[3:]))
+		Expect(resize.SplitWindow).To(BeTrue())
+
+		resizeRecommender.WithPodResizeNormalization(nil, 4*time.Minute)
+		_, resize = resizeRecommender.normalizePodResizes(series(2, 2, 3, 4, 4, 4), series(1, 1, 1, 2, 2, 2), 2, end)
+		Expect(resize.SplitWindow).To(BeFalse())
+	})
+
+	It("should ignore the changes of the per pod resources within the tolerance", func() {
+		dataPoints := series(2, 2, 3)
+		normalized, resize := resizeRecommender.normalizePodResizes(dataPoints, series(1.96, 2, 2.04), 2, end)
+		Expect(normalized).To(Equal(dataPoints))
+		Expect(resize).To(BeNil())
+	})
+})
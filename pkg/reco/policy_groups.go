@@ -0,0 +1,119 @@
+package reco
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PolicyGroupLabelAnnotation names the label whose value groups a workload with the other workloads of its namespace
+// carrying the annotation with the same label and the same value of it, e.g. `app` on the regional shards of
+// `app=checkout`. The workloads of a group share their policy promotion state, so that they age along the policies
+// together.
+const PolicyGroupLabelAnnotation = "ottoscalr.io/policy-group-label"
+
+var (
+	groupPolicyHeldCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "policyage_group_held_counter",
+			Help: "Number of policy promotions held back till the rest of the policy group of the workload ages along"}, []string{"namespace", "policyreco", "workloadKind", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(groupPolicyHeldCounter)
+}
+
+// WithPolicyGroups makes the iterator age the workloads of a policy group together, looking the groups up with the
+// clients of the registry.
+func (pi *AgingPolicyIterator) WithPolicyGroups(clientsRegistry registry.DeploymentClientRegistry) *AgingPolicyIterator {
+	pi.clientsRegistry = &clientsRegistry
+	return pi
+}
+
+// policyGroup returns the policyrecos of the other workloads of the policy group of the workload, or nil if the
+// workload isn't grouped.
+func (pi *AgingPolicyIterator) policyGroup(ctx context.Context, wm WorkloadMeta) ([]v1alpha1.PolicyRecommendation, error) {
+	objectClient, err := pi.clientsRegistry.GetObjectClient(wm.Kind)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := objectClient.GetObject(wm.Namespace, wm.Name)
+	if err != nil {
+		return nil, err
+	}
+	groupLabel, ok := workload.GetAnnotations()[PolicyGroupLabelAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	groupValue, ok := workload.GetLabels()[groupLabel]
+	if !ok {
+		return nil, fmt.Errorf("no %s label on the workload %s/%s to group it by", groupLabel, wm.Namespace, wm.Name)
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{groupLabel: groupValue})
+	var peers []client.Object
+	for _, objectClient := range pi.clientsRegistry.Clients {
+		objects, err := objectClient.GetObjectList(wm.Namespace, selector)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, objects...)
+	}
+
+	group := []v1alpha1.PolicyRecommendation{}
+	for _, peer := range peers {
+		if peer.GetName() == wm.Name || peer.GetAnnotations()[PolicyGroupLabelAnnotation] != groupLabel {
+			continue
+		}
+		policyreco := v1alpha1.PolicyRecommendation{}
+		if err := pi.client.Get(ctx, types.NamespacedName{Namespace: peer.GetNamespace(), Name: peer.GetName()}, &policyreco); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+		group = append(group, policyreco)
+	}
+	return group, nil
+}
+
+// isGroupAgeBeyondExpiry tells whether the expired policy of a workload of a policy group has expired for the rest of
+// the group too, i.e. whether every other workload of the group has been on it, or on a policy after it, for the age.
+// The workloads behind the rest of the group, e.g. a new shard, hold back the promotions of the group till they catch
+// up.
+func (pi *AgingPolicyIterator) isGroupAgeBeyondExpiry(currentPolicy *v1alpha1.Policy,
+	group []v1alpha1.PolicyRecommendation) (bool, error) {
+	for i := range group {
+		peer := &group[i]
+		if len(peer.Spec.Policy) == 0 {
+			return false, nil
+		}
+		peerPolicy, err := pi.store.GetPolicyByName(peer.Spec.Policy)
+		if err != nil {
+			// the workloads on a deleted policy are moved to the safest one
+			if errors.Is(err, policy.NoPolicyFoundErr) {
+				return false, nil
+			}
+			return false, err
+		}
+		if peerPolicy.Spec.RiskIndex < currentPolicy.Spec.RiskIndex {
+			return false, nil
+		}
+		if peerPolicy.Spec.RiskIndex > currentPolicy.Spec.RiskIndex {
+			continue
+		}
+		peerExpired, err := isAgeBeyondExpiry(peer, pi.Age)
+		if err != nil || !peerExpired {
+			return peerExpired, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,86 @@
+package reco
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Policy groups", func() {
+	age := time.Hour
+	expired := time.Now().Add(-2 * age)
+	recent := time.Now().Add(-age / 2)
+	wm := WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "checkout-east", Namespace: "default"}
+
+	newPolicy := func(name string, riskIndex int) *v1alpha1.Policy {
+		return &v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.PolicySpec{RiskIndex: riskIndex, MinReplicaPercentageCut: 100 - 10*riskIndex,
+				TargetUtilization: 10 * riskIndex}}
+	}
+	shard := func(name string, grouped bool) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default",
+			Labels: map[string]string{"app": "checkout"}}}
+		if grouped {
+			deployment.Annotations = map[string]string{PolicyGroupLabelAnnotation: "app"}
+		}
+		return deployment
+	}
+	shardReco := func(name, policy string, transitionedAt time.Time) *v1alpha1.PolicyRecommendation {
+		transitioned := metav1.NewTime(transitionedAt)
+		return &v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{Policy: policy, TransitionedAt: &transitioned}}
+	}
+	newIterator := func(objects ...client.Object) *AgingPolicyIterator {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		objects = append(objects, newPolicy("safe", 1), newPolicy("balanced", 2), newPolicy("aggressive", 3))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		return NewAgingPolicyIterator(k8sClient, age).WithPolicyGroups(*clientsRegistry)
+	}
+
+	It("should promote the workload once the rest of its group has aged on its policy too", func() {
+		pi := newIterator(shard("checkout-east", true), shard("checkout-west", true),
+			shardReco("checkout-east", "balanced", expired), shardReco("checkout-west", "balanced", expired))
+		policy, err := pi.NextPolicy(context.TODO(), wm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Name).To(Equal("aggressive"))
+	})
+
+	It("should hold the workload till the rest of its group has aged on its policy", func() {
+		pi := newIterator(shard("checkout-east", true), shard("checkout-west", true),
+			shardReco("checkout-east", "balanced", expired), shardReco("checkout-west", "balanced", recent))
+		policy, err := pi.NextPolicy(context.TODO(), wm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Name).To(Equal("balanced"))
+	})
+
+	It("should hold the workload while a workload of its group is behind it", func() {
+		pi := newIterator(shard("checkout-east", true), shard("checkout-west", true), shard("checkout-north", true),
+			shardReco("checkout-east", "balanced", expired), shardReco("checkout-west", "aggressive", expired),
+			shardReco("checkout-north", "safe", expired))
+		policy, err := pi.NextPolicy(context.TODO(), wm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Name).To(Equal("balanced"))
+	})
+
+	It("should age the workloads without the annotation on their own", func() {
+		pi := newIterator(shard("checkout-east", false), shard("checkout-west", true),
+			shardReco("checkout-east", "balanced", expired), shardReco("checkout-west", "safe", recent))
+		policy, err := pi.NextPolicy(context.TODO(), wm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Name).To(Equal("aggressive"))
+	})
+})
@@ -0,0 +1,70 @@
+package reco
+
+import (
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	skippedPoliciesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "policyage_skipped_policies_counter",
+			Help: "Number of policies skipped by the promotions of the workloads confidently recommended far safer configs"}, []string{"namespace", "policyreco", "workloadKind", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(skippedPoliciesCounter)
+}
+
+// policySkipping is how far the aging iterator promotes the workloads in one transition.
+type policySkipping struct {
+	maxSkip              int
+	minConfidencePercent int
+}
+
+// WithPolicySkipping makes the iterator promote the workloads whose target recommendation is safe at a policy several
+// steps up the ladder past up to maxSkip policies at once, instead of to the next one, as long as the confidence of
+// their latest recommendation is at least minConfidencePercent. The promotions still stop at the closest safe policy of the target
+// recommendation, so that the overprovisioned workloads climb the ladder in weeks instead of months.
+func (pi *AgingPolicyIterator) WithPolicySkipping(maxSkip, minConfidencePercent int) *AgingPolicyIterator {
+	pi.skipping = &policySkipping{maxSkip: maxSkip, minConfidencePercent: minConfidencePercent}
+	return pi
+}
+
+// skipTo returns the policy the expired policyreco is promoted to by skipping the policies in between, along with how
+// many it skips, or nil if it's promoted to the next policy. The policies are sorted by their risk index.
+func (s *policySkipping) skipTo(policyreco *v1alpha1.PolicyRecommendation, policies []v1alpha1.Policy,
+	currentPolicy string) (*v1alpha1.Policy, int) {
+	if s == nil || s.maxSkip <= 0 {
+		return nil, 0
+	}
+	confidence := policyreco.Status.ConfidencePercent
+	if confidence == nil || *confidence < s.minConfidencePercent {
+		return nil, 0
+	}
+	target := policyreco.Spec.TargetHPAConfiguration
+	if target.GetTargetMetricType() != v1alpha1.UtilizationMetricTarget {
+		return nil, 0
+	}
+	targetPolicy := closestSafePolicy(policies, target)
+	if targetPolicy == nil {
+		return nil, 0
+	}
+	currentStep, targetStep := -1, -1
+	for i := range policies {
+		if policies[i].Name == currentPolicy {
+			currentStep = i
+		}
+		if policies[i].Name == targetPolicy.Name {
+			targetStep = i
+		}
+	}
+	steps := targetStep - currentStep
+	if currentStep < 0 || steps <= 1 {
+		return nil, 0
+	}
+	if steps > s.maxSkip+1 {
+		steps = s.maxSkip + 1
+	}
+	return &policies[currentStep+steps], steps - 1
+}
@@ -0,0 +1,56 @@
+package reco
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Policy skipping", func() {
+	newPolicy := func(name string, riskIndex, cut, target int) *v1alpha1.Policy {
+		return &v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.PolicySpec{RiskIndex: riskIndex, MinReplicaPercentageCut: cut, TargetUtilization: target}}
+	}
+	policies := []client.Object{newPolicy("safest", 1, 0, 40), newPolicy("cautious", 2, 50, 50),
+		newPolicy("safe", 3, 100, 50), newPolicy("balanced", 4, 100, 60), newPolicy("aggressive", 5, 100, 70)}
+
+	nextPolicy := func(currentPolicy string, confidence, targetUtilization int) string {
+		transitionedAt := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		policyreco := &v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+			Spec: v1alpha1.PolicyRecommendationSpec{
+				Policy:                 currentPolicy,
+				TransitionedAt:         &transitionedAt,
+				TargetHPAConfiguration: v1alpha1.HPAConfiguration{Min: 2, Max: 20, TargetMetricValue: targetUtilization},
+			},
+			Status: v1alpha1.PolicyRecommendationStatus{ConfidencePercent: &confidence},
+		}
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(policies, policyreco)...).Build()
+
+		iterator := NewAgingPolicyIterator(k8sClient, time.Hour).WithPolicySkipping(2, 90)
+		policy, err := iterator.NextPolicy(context.TODO(), WorkloadMeta{Name: "checkout", Namespace: "default"})
+		Expect(err).NotTo(HaveOccurred())
+		return policy.Name
+	}
+
+	It("should skip up to the max skip of the policies below the closest safe policy of the target recommendation", func() {
+		Expect(nextPolicy("safest", 95, 70)).To(Equal("balanced"))
+		Expect(nextPolicy("cautious", 95, 70)).To(Equal("aggressive"))
+		Expect(nextPolicy("safest", 95, 50)).To(Equal("safe"))
+	})
+
+	It("should promote to the next policy without confidence or without a safe policy further up", func() {
+		Expect(nextPolicy("safest", 80, 70)).To(Equal("cautious"))
+		Expect(nextPolicy("cautious", 95, 50)).To(Equal("safe"))
+		Expect(nextPolicy("safe", 95, 45)).To(Equal("balanced"))
+	})
+})
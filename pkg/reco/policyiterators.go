@@ -5,25 +5,24 @@ import (
 	"errors"
 	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
 	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"time"
 )
 
 var (
-	agedPolicyCounter = promauto.NewCounterVec(
+	agedPolicyCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{Name: "policyage_expired_counter",
 			Help: "Number of policyrecos reconcile errored counter"}, []string{"namespace", "policyreco", "workloadKind", "workload"},
 	)
 )
 
 func init() {
-	p8smetrics.Registry.MustRegister(agedPolicyCounter)
+	registerCollectors(agedPolicyCounter)
 }
 
 type Policy struct {
@@ -68,9 +67,11 @@ func (pi *DefaultPolicyIterator) GetName() string {
 }
 
 type AgingPolicyIterator struct {
-	store  policy.Store
-	client client.Client
-	Age    time.Duration
+	store           policy.Store
+	client          client.Client
+	clientsRegistry *registry.DeploymentClientRegistry
+	skipping        *policySkipping
+	Age             time.Duration
 }
 
 func NewAgingPolicyIterator(k8sClient client.Client, age time.Duration) *AgingPolicyIterator {
@@ -124,7 +125,35 @@ func (pi *AgingPolicyIterator) NextPolicy(ctx context.Context, wm WorkloadMeta)
 		return PolicyFromCR(currentAppliedPolicy), nil
 	}
 
+	if pi.clientsRegistry != nil {
+		group, err := pi.policyGroup(ctx, wm)
+		if err != nil {
+			return nil, err
+		}
+		groupExpired, err := pi.isGroupAgeBeyondExpiry(currentAppliedPolicy, group)
+		if err != nil {
+			return nil, err
+		}
+		if !groupExpired {
+			logger.V(0).Info("Policy hasn't expired for the policy group yet")
+			groupPolicyHeldCounter.WithLabelValues(wm.Namespace, policyreco.Name, wm.Kind, wm.Name).Inc()
+			return PolicyFromCR(currentAppliedPolicy), nil
+		}
+	}
+
 	agedPolicyCounter.WithLabelValues(wm.Namespace, policyreco.Name, wm.Kind, wm.Name).Inc()
+	if pi.skipping != nil {
+		policies, err := pi.store.GetSortedPolicies()
+		if err != nil {
+			return nil, err
+		}
+		if skippedTo, skipped := pi.skipping.skipTo(policyreco, policies.Items, currentAppliedPolicy.Name); skippedTo != nil {
+			logger.V(0).Info("Skipping policies as the target recommendation is confidently safe further up the ladder",
+				"policy", skippedTo.Name, "skipped", skipped)
+			skippedPoliciesCounter.WithLabelValues(wm.Namespace, policyreco.Name, wm.Kind, wm.Name).Add(float64(skipped))
+			return PolicyFromCR(skippedTo), nil
+		}
+	}
 	nextPolicy, err := pi.store.GetNextPolicyByName(policyreco.Spec.Policy)
 	if err != nil {
 		if policy.IsLastPolicy(err) {
@@ -0,0 +1,154 @@
+package reco
+
+import (
+	"context"
+	"sort"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PolicyProjection is how applying a Policy, or a change to an existing one, would change the configs of the
+// workloads across the fleet.
+type PolicyProjection struct {
+	Policy string `json:"policy"`
+	// Workloads are the workloads the policy would be applied to: the workloads on the policy if it exists, otherwise
+	// the workloads on the policy preceding it on the ladder, which age into it next.
+	Workloads        int `json:"workloads"`
+	ChangedWorkloads int `json:"changedWorkloads"`
+	// MinReplicasDelta is the change of the aggregate min replicas of the changed workloads.
+	MinReplicasDelta int `json:"minReplicasDelta"`
+	// ProjectedSavingsPercent is the reduction of the aggregate min replicas of the changed workloads relative to their
+	// current aggregate min replicas. It's negative if the policy raises them.
+	ProjectedSavingsPercent float64              `json:"projectedSavingsPercent"`
+	Changes                 []WorkloadProjection `json:"changes,omitempty"`
+}
+
+// WorkloadProjection is the config a workload would move to on applying a Policy.
+type WorkloadProjection struct {
+	Namespace string                    `json:"namespace"`
+	Kind      string                    `json:"kind"`
+	Workload  string                    `json:"workload"`
+	Current   v1alpha1.HPAConfiguration `json:"current"`
+	Projected v1alpha1.HPAConfiguration `json:"projected"`
+}
+
+// PolicyProjector projects a Policy over the PolicyRecommendations before it's applied. The configs are projected
+// from the target recommendations the way the workflow would pick them, without regenerating the recommendations.
+type PolicyProjector struct {
+	k8sClient   client.Client
+	policyStore policy.Store
+}
+
+func NewPolicyProjector(k8sClient client.Client, policyStore policy.Store) *PolicyProjector {
+	return &PolicyProjector{k8sClient: k8sClient, policyStore: policyStore}
+}
+
+// Project returns how applying the policy would change the configs of the workloads in the namespace, or in all the
+// namespaces if it's empty.
+func (p *PolicyProjector) Project(ctx context.Context, candidate v1alpha1.Policy, namespace string) (*PolicyProjection,
+	error) {
+	policies, err := p.policyStore.GetSortedPolicies()
+	if err != nil {
+		return nil, err
+	}
+	ladder, affectedPolicy := projectLadder(policies.Items, candidate)
+
+	policyrecos := &v1alpha1.PolicyRecommendationList{}
+	var opts []client.ListOption
+	if len(namespace) > 0 {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := p.k8sClient.List(ctx, policyrecos, opts...); err != nil {
+		return nil, err
+	}
+
+	projection := &PolicyProjection{Policy: candidate.Name}
+	currentMinReplicas := 0
+	for _, policyreco := range policyrecos.Items {
+		if policyreco.Spec.Policy != affectedPolicy {
+			continue
+		}
+		projected, ok := projectConfig(ladder, candidate, policyreco)
+		if !ok {
+			continue
+		}
+		projection.Workloads++
+		current := policyreco.Spec.CurrentHPAConfiguration
+		if projected.DeepEquals(current) {
+			continue
+		}
+		projection.ChangedWorkloads++
+		projection.MinReplicasDelta += projected.Min - current.Min
+		currentMinReplicas += current.Min
+		projection.Changes = append(projection.Changes, WorkloadProjection{
+			Namespace: policyreco.Namespace,
+			Kind:      policyreco.Spec.WorkloadMeta.Kind,
+			Workload:  policyreco.Spec.WorkloadMeta.Name,
+			Current:   current,
+			Projected: *projected,
+		})
+	}
+	if currentMinReplicas > 0 {
+		projection.ProjectedSavingsPercent = -100 * float64(projection.MinReplicasDelta) / float64(currentMinReplicas)
+	}
+	return projection, nil
+}
+
+// projectLadder returns the policies sorted by their risk index with the candidate in place of the policy of its name,
+// along with the policy whose workloads the candidate applies to. A new policy applies to the workloads of the policy
+// preceding it, or to the workloads without a policy if it's the safest.
+func projectLadder(policies []v1alpha1.Policy, candidate v1alpha1.Policy) ([]v1alpha1.Policy, string) {
+	ladder := []v1alpha1.Policy{candidate}
+	affectedPolicy, exists := "", false
+	for _, existing := range policies {
+		if existing.Name == candidate.Name {
+			exists = true
+			continue
+		}
+		ladder = append(ladder, existing)
+	}
+	sort.SliceStable(ladder, func(i, j int) bool {
+		return ladder[i].Spec.RiskIndex < ladder[j].Spec.RiskIndex
+	})
+	if exists {
+		return ladder, candidate.Name
+	}
+	for _, existing := range ladder {
+		if existing.Name == candidate.Name {
+			break
+		}
+		affectedPolicy = existing.Name
+	}
+	return ladder, affectedPolicy
+}
+
+// projectConfig returns the config the workflow would pick for the workload on the policy: the target recommendation
+// if the policy is riskier than the closest safe policy of the recommendation, otherwise the config of the policy.
+// The workloads without a recommendation of the utilization target, which the policies don't apply to, aren't projected.
+func projectConfig(ladder []v1alpha1.Policy, candidate v1alpha1.Policy,
+	policyreco v1alpha1.PolicyRecommendation) (*v1alpha1.HPAConfiguration, bool) {
+	target := policyreco.Spec.TargetHPAConfiguration
+	if target.Max == 0 || target.GetTargetMetricType() != v1alpha1.UtilizationMetricTarget {
+		return nil, false
+	}
+	var closestSafePolicy *v1alpha1.Policy
+	for i, pc := range ladder {
+		if pc.Spec.MinReplicaPercentageCut == 100 && pc.Spec.TargetUtilization <= target.TargetMetricValue {
+			closestSafePolicy = &ladder[i]
+		}
+	}
+	if closestSafePolicy == nil {
+		return nil, false
+	}
+	if candidate.Spec.RiskIndex > closestSafePolicy.Spec.RiskIndex ||
+		(candidate.Spec.RiskIndex == closestSafePolicy.Spec.RiskIndex && isTargetRecommendationAchieved(&policyreco)) {
+		return &target, true
+	}
+	projected, err := createRecoConfigFromPolicy(PolicyFromCR(&candidate), &target, WorkloadMeta{})
+	if err != nil {
+		return nil, false
+	}
+	return projected, true
+}
@@ -0,0 +1,74 @@
+package reco
+
+import (
+	"context"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("PolicyProjector", func() {
+	var projector *PolicyProjector
+
+	newPolicy := func(name string, riskIndex, cut, target int) v1alpha1.Policy {
+		return v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.PolicySpec{RiskIndex: riskIndex, MinReplicaPercentageCut: cut, TargetUtilization: target}}
+	}
+	newPolicyReco := func(name, policyName string, current, target v1alpha1.HPAConfiguration) client.Object {
+		return &v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "projection-ns"},
+			Spec: v1alpha1.PolicyRecommendationSpec{
+				WorkloadMeta:            v1alpha1.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name},
+				Policy:                  policyName,
+				CurrentHPAConfiguration: current,
+				TargetHPAConfiguration:  target,
+			},
+		}
+	}
+	safest, balanced, safe := newPolicy("safest", 1, 100, 40), newPolicy("balanced", 2, 50, 60), newPolicy("safe", 3, 100, 70)
+
+	BeforeEach(func() {
+		fakeScheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(fakeScheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(&safest, &balanced, &safe,
+			// on the config of the balanced policy as its recommendation is safe only at the safe policy
+			newPolicyReco("checkout", "balanced", v1alpha1.HPAConfiguration{Min: 12, Max: 20, TargetMetricValue: 60},
+				v1alpha1.HPAConfiguration{Min: 4, Max: 20, TargetMetricValue: 70}),
+			// on its recommendation as it's safe already at the safest policy
+			newPolicyReco("search", "balanced", v1alpha1.HPAConfiguration{Min: 2, Max: 10, TargetMetricValue: 50},
+				v1alpha1.HPAConfiguration{Min: 2, Max: 10, TargetMetricValue: 50}),
+			newPolicyReco("payments", "safest", v1alpha1.HPAConfiguration{Min: 6, Max: 6, TargetMetricValue: 40},
+				v1alpha1.HPAConfiguration{Min: 3, Max: 6, TargetMetricValue: 70}),
+		).Build()
+		projector = NewPolicyProjector(fakeClient, policy.NewPolicyStore(fakeClient))
+	})
+
+	It("should project the change of a policy on the workloads on it", func() {
+		projection, err := projector.Project(context.TODO(), newPolicy("balanced", 2, 75, 65), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(projection.Workloads).To(Equal(2))
+		Expect(projection.ChangedWorkloads).To(Equal(1))
+		Expect(projection.MinReplicasDelta).To(Equal(-4))
+		Expect(projection.ProjectedSavingsPercent).To(BeNumerically("~", 33.33, 0.01))
+		Expect(projection.Changes).To(HaveLen(1))
+		Expect(projection.Changes[0].Workload).To(Equal("checkout"))
+		Expect(projection.Changes[0].Projected).To(Equal(v1alpha1.HPAConfiguration{Min: 8, Max: 20, TargetMetricValue: 65}))
+	})
+
+	It("should project a new policy on the workloads of the policy preceding it", func() {
+		projection, err := projector.Project(context.TODO(), newPolicy("safer", 0, 100, 30), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(projection.Workloads).To(BeZero())
+
+		_, affectedPolicy := projectLadder([]v1alpha1.Policy{safest, balanced, safe}, newPolicy("aggressive", 4, 100, 80))
+		Expect(affectedPolicy).To(Equal("safe"))
+		_, affectedPolicy = projectLadder([]v1alpha1.Policy{safest, balanced, safe}, newPolicy("bolder", 2, 60, 65))
+		Expect(affectedPolicy).To(Equal("safest"))
+	})
+})
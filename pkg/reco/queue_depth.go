@@ -0,0 +1,131 @@
+package reco
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	queueConsumerThroughputGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "queue_consumer_replica_throughput",
+			Help: "Messages per second a replica of the queue consumer is estimated to consume"},
+		[]string{"namespace", "workload", "queue"},
+	)
+)
+
+func init() {
+	registerCollectors(queueConsumerThroughputGauge)
+}
+
+// QueueDepthBasedRecommender recommends the queue consumers, i.e. the workloads annotated with
+// autoscaler.QueueTypeAnnotation, the replicas needed to keep the depth of their queue under a target, to be scaled by
+// the KEDA trigger of the queue rather than on their cpu utilization. The other workloads are recommended by the
+// delegate.
+type QueueDepthBasedRecommender struct {
+	delegate        Recommender
+	k8sClient       client.Client
+	scraper         metrics.QueueScraper
+	clientsRegistry registry.DeploymentClientRegistry
+	metricWindow    time.Duration
+	metricStep      time.Duration
+	targetDepth     int
+	logger          logr.Logger
+}
+
+func NewQueueDepthBasedRecommender(delegate Recommender,
+	k8sClient client.Client,
+	scraper metrics.QueueScraper,
+	clientsRegistry registry.DeploymentClientRegistry,
+	metricWindow time.Duration,
+	metricStep time.Duration,
+	targetDepth int,
+	logger logr.Logger) *QueueDepthBasedRecommender {
+	return &QueueDepthBasedRecommender{
+		delegate:        delegate,
+		k8sClient:       k8sClient,
+		scraper:         scraper,
+		clientsRegistry: clientsRegistry,
+		metricWindow:    metricWindow,
+		metricStep:      metricStep,
+		targetDepth:     targetDepth,
+		logger:          logger,
+	}
+}
+
+// GetSimulationDetails returns the details of the last simulation of the delegate for the workload.
+func (q *QueueDepthBasedRecommender) GetSimulationDetails(namespace, workload string) (SimulationDetails, bool) {
+	if provider, ok := q.delegate.(SimulationDetailsProvider); ok {
+		return provider.GetSimulationDetails(namespace, workload)
+	}
+	return SimulationDetails{}, false
+}
+
+func (q *QueueDepthBasedRecommender) Recommend(ctx context.Context, workloadMeta WorkloadMeta) (*v1alpha1.HPAConfiguration,
+	*RecommendationMetadata, error) {
+	objectClient, err := q.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	trigger, err := autoscaler.QueueTriggerFromAnnotations(workload.GetAnnotations())
+	if err != nil {
+		return nil, nil, err
+	}
+	if trigger == nil {
+		return q.delegate.Recommend(ctx, workloadMeta)
+	}
+	targetDepth := q.targetDepth
+	if trigger.TargetDepth > 0 {
+		targetDepth = trigger.TargetDepth
+	}
+
+	end := time.Now()
+	start := end.Add(-q.metricWindow)
+	depth, err := q.scraper.GetQueueDepth(trigger.Type, trigger.Name, start, end, q.metricStep)
+	if err != nil {
+		return nil, nil, err
+	}
+	consumeRate, err := q.scraper.GetQueueConsumeRate(trigger.Type, trigger.Name, start, end, q.metricStep)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err := q.scraper.GetPodCountByWorkload(workloadMeta.Namespace, workloadMeta.Name, start, end, q.metricStep)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxReplicas, err := getMaxPods(q.k8sClient, q.clientsRegistry, workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recommendation, err := recommendBacklogConfig(depth, consumeRate, pods, q.metricStep, targetDepth, maxReplicas,
+		trigger.MetricName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to recommend the consumers of the %s queue %s: %v", trigger.Type, trigger.Name, err)
+	}
+	queueConsumerThroughputGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name, trigger.Name).
+		Set(recommendation.replicaThroughput)
+	q.logger.V(0).Info("Queue depth based recommendation", "workload", workloadMeta, "queue", trigger.Name,
+		"replicaThroughput", recommendation.replicaThroughput, "config", recommendation.config)
+
+	expectedDataPoints := int(q.metricWindow / q.metricStep)
+	return recommendation.config, &RecommendationMetadata{
+		MetricsWindowStart:        start,
+		MetricsWindowEnd:          end,
+		DataPointsCoveragePercent: int(math.Min(float64(len(depth))*100/math.Max(float64(expectedDataPoints), 1), 100)),
+		ProjectedSavingsPercent:   recommendation.savingsPercent,
+	}, nil
+}
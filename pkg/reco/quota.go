@@ -0,0 +1,144 @@
+package reco
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	quotaMaxReplicasClampedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "reco_quota_max_replicas_clamped_count",
+			Help: "Number of recommendations whose max replicas was clamped to the replicas the ResourceQuotas of the namespace allow"},
+		[]string{"namespace", "workloadKind", "workload"},
+	)
+)
+
+func init() {
+	registerCollectors(quotaMaxReplicasClampedCounter)
+}
+
+// QuotaConstraint describes the ResourceQuotas which don't leave the workload enough room to scale up to the max
+// replicas of its recommendation.
+type QuotaConstraint struct {
+	// ResourceQuotas are the names of the quotas which can't fit the max replicas.
+	ResourceQuotas []string
+	// MaxReplicas is the most replicas the workload can scale up to within the quotas.
+	MaxReplicas int
+	// RecommendedMaxReplicas is the max replicas the recommendation was clamped from.
+	RecommendedMaxReplicas int
+}
+
+// ResourceQuotaResolver resolves the max replicas a workload can scale up to within the ResourceQuotas of its
+// namespace, so that a recommendation doesn't cut the min replicas of a workload which then can't surge back to its
+// max replicas once the quota freed up by the cut is taken by the other workloads.
+type ResourceQuotaResolver struct {
+	k8sClient       client.Client
+	clientsRegistry registry.DeploymentClientRegistry
+}
+
+func NewResourceQuotaResolver(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry) *ResourceQuotaResolver {
+	return &ResourceQuotaResolver{
+		k8sClient:       k8sClient,
+		clientsRegistry: clientsRegistry,
+	}
+}
+
+// MaxReplicas returns the most replicas up to maxReplicas the workload can run with within the ResourceQuotas of its
+// namespace along with the names of the quotas which don't fit maxReplicas. The replicas the workload runs with are
+// already accounted in the usage of the quotas. The scoped quotas are ignored since the scopes, e.g. the priority
+// class, of the pods aren't known before they are created.
+func (r *ResourceQuotaResolver) MaxReplicas(ctx context.Context, wm WorkloadMeta, maxReplicas int) (int, []string, error) {
+	objectClient, err := r.clientsRegistry.GetObjectClient(wm.Kind)
+	if err != nil {
+		return 0, nil, err
+	}
+	object, err := objectClient.GetObject(wm.Namespace, wm.Name)
+	if err != nil {
+		return 0, nil, err
+	}
+	podTemplate := registry.PodTemplate(object)
+	if podTemplateClient, ok := objectClient.(registry.PodTemplateClient); ok {
+		if podTemplate, err = podTemplateClient.GetPodTemplate(wm.Namespace, wm.Name); err != nil {
+			return 0, nil, err
+		}
+	}
+	if podTemplate == nil {
+		return 0, nil, fmt.Errorf("no pod template in the workload of kind %s", wm.Kind)
+	}
+	replicas, err := objectClient.GetReplicaCount(wm.Namespace, wm.Name)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	quotaList := &corev1.ResourceQuotaList{}
+	if err := r.k8sClient.List(ctx, quotaList, client.InNamespace(wm.Namespace)); err != nil {
+		return 0, nil, err
+	}
+
+	requests, limits := registry.PodResources(podTemplate.Spec)
+	quotaMaxReplicas := maxReplicas
+	var quotas []string
+	for _, quota := range quotaList.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		allowed := quotaAllowedReplicas(quota, replicas, requests, limits)
+		if allowed >= maxReplicas {
+			continue
+		}
+		quotas = append(quotas, quota.Name)
+		if allowed < quotaMaxReplicas {
+			quotaMaxReplicas = allowed
+		}
+	}
+	sort.Strings(quotas)
+	return quotaMaxReplicas, quotas, nil
+}
+
+// quotaAllowedReplicas returns the replicas the workload can scale up to from its current replicas with the room
+// left in the quota by its usage, for the resources the pods are charged for.
+func quotaAllowedReplicas(quota corev1.ResourceQuota, replicas int, requests, limits corev1.ResourceList) int {
+	allowed := math.MaxInt32
+	for name, hard := range quota.Status.Hard {
+		perPod := quotaPerPodUsage(name, requests, limits)
+		if perPod <= 0 {
+			continue
+		}
+		used := quota.Status.Used[name]
+		room := math.Max(hard.AsApproximateFloat64()-used.AsApproximateFloat64(), 0)
+		if reachable := replicas + int(math.Floor(room/perPod)); reachable < allowed {
+			allowed = reachable
+		}
+	}
+	return allowed
+}
+
+// quotaPerPodUsage returns the usage of the quota resource charged for every pod, e.g. its cpu requests for
+// requests.cpu, or 0 if the pods aren't charged for it, e.g. for the object counts.
+func quotaPerPodUsage(name corev1.ResourceName, requests, limits corev1.ResourceList) float64 {
+	var quantity resource.Quantity
+	var ok bool
+	switch {
+	case name == corev1.ResourcePods:
+		return 1
+	case strings.HasPrefix(string(name), "requests."):
+		quantity, ok = requests[corev1.ResourceName(strings.TrimPrefix(string(name), "requests."))]
+	case strings.HasPrefix(string(name), "limits."):
+		quantity, ok = limits[corev1.ResourceName(strings.TrimPrefix(string(name), "limits."))]
+	case name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage:
+		quantity, ok = requests[name]
+	}
+	if !ok {
+		return 0
+	}
+	return quantity.AsApproximateFloat64()
+}
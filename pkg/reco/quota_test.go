@@ -0,0 +1,111 @@
+package reco
+
+import (
+	"context"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ResourceQuotaResolver", func() {
+	var wm WorkloadMeta
+
+	newQuota := func(name string, hard, used corev1.ResourceList, scopes ...corev1.ResourceQuotaScope) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "quota-ns"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard, Scopes: scopes},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	newResolver := func(quotas ...client.Object) *ResourceQuotaResolver {
+		fakeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(fakeScheme)).To(Succeed())
+		replicas := int32(4)
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "quota-ns"},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas, Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+						Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+				}}},
+			}},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(append(quotas, deployment)...).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(fakeClient)).Build()
+		return NewResourceQuotaResolver(fakeClient, *clientsRegistry)
+	}
+
+	BeforeEach(func() {
+		wm = WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "payments", Namespace: "quota-ns"}
+	})
+
+	It("should allow the replicas fitting in the room left by the usage of the strictest quota", func() {
+		resolver := newResolver(
+			newQuota("cpu-quota",
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("10"), corev1.ResourceLimitsCPU: resource.MustParse("20")},
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("7"), corev1.ResourceLimitsCPU: resource.MustParse("12")}),
+			newQuota("memory-quota",
+				corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("16Gi"), corev1.ResourcePods: resource.MustParse("100")},
+				corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("10Gi"), corev1.ResourcePods: resource.MustParse("20")}),
+		)
+		maxReplicas, quotas, err := resolver.MaxReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		// 3 cpus of requests left fit 6 more pods of 500m over the 4 running
+		Expect(maxReplicas).To(Equal(10))
+		Expect(quotas).To(Equal([]string{"cpu-quota", "memory-quota"}))
+	})
+
+	It("should not constrain the workloads within the quotas", func() {
+		resolver := newResolver(newQuota("pods-quota",
+			corev1.ResourceList{corev1.ResourcePods: resource.MustParse("100"), corev1.ResourceServices: resource.MustParse("1")},
+			corev1.ResourceList{corev1.ResourcePods: resource.MustParse("20"), corev1.ResourceServices: resource.MustParse("1")}))
+		maxReplicas, quotas, err := resolver.MaxReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxReplicas).To(Equal(20))
+		Expect(quotas).To(BeEmpty())
+	})
+
+	It("should ignore the scoped quotas", func() {
+		resolver := newResolver(newQuota("best-effort-quota",
+			corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")},
+			corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}, corev1.ResourceQuotaScopeBestEffort))
+		maxReplicas, quotas, err := resolver.MaxReplicas(context.TODO(), wm, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxReplicas).To(Equal(20))
+		Expect(quotas).To(BeEmpty())
+	})
+
+	It("should clamp the max replicas of the configs to the replicas allowed by the quotas", func() {
+		rw := &RecommendationWorkflowImpl{logger: logr.Discard()}
+		explanation := &Explanation{}
+		constraint := &QuotaConstraint{ResourceQuotas: []string{"cpu-quota"}, MaxReplicas: 6, RecommendedMaxReplicas: 20}
+
+		config := rw.honourResourceQuotas(&v1alpha1.HPAConfiguration{Min: 3, Max: 20, TargetMetricValue: 50}, constraint, wm, explanation)
+		Expect(*config).To(Equal(v1alpha1.HPAConfiguration{Min: 3, Max: 6, TargetMetricValue: 50}))
+		Expect(explanation.Warnings).To(HaveLen(1))
+
+		config = rw.honourResourceQuotas(&v1alpha1.HPAConfiguration{Min: 8, Max: 20, TargetMetricValue: 50}, constraint, wm, explanation)
+		Expect(*config).To(Equal(v1alpha1.HPAConfiguration{Min: 6, Max: 6, TargetMetricValue: 50}))
+
+		config = rw.honourResourceQuotas(&v1alpha1.HPAConfiguration{Min: 2, Max: 5, TargetMetricValue: 50}, constraint, wm, explanation)
+		Expect(config.Max).To(Equal(5))
+		Expect(explanation.Warnings).To(HaveLen(2))
+	})
+})
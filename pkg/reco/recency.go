@@ -0,0 +1,46 @@
+package reco
+
+import (
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+// RecencyWeighting weights the recent datapoints of the metric window more heavily than the older ones, since the
+// traffic of a few weeks ago is often no longer representative of a workload, e.g. after a feature launch.
+type RecencyWeighting struct {
+	// HalfLife is the age at which a datapoint weighs half as much as the latest one.
+	HalfLife time.Duration
+	// MinBreachWeight is the weight below which the breaches of the datapoints are ignored. Zero counts all of them.
+	MinBreachWeight float64
+}
+
+// WithRecencyWeighting makes the recommender weight the savings by the recency of the datapoints, decaying
+// exponentially over the metric window, and ignore the breaches of the datapoints which are too old to matter.
+func (c *CpuUtilizationBasedRecommender) WithRecencyWeighting(weighting RecencyWeighting) *CpuUtilizationBasedRecommender {
+	c.recencyWeighting = &weighting
+	return c
+}
+
+// recencyWeights returns the weights of the datapoints, halving every half life before the end of the window, along
+// with the first datapoint whose breaches count. The weights are nil without a recency weighting.
+func (c *CpuUtilizationBasedRecommender) recencyWeights(dataPoints []metrics.DataPoint, end time.Time) ([]float64, int) {
+	if c.recencyWeighting == nil || c.recencyWeighting.HalfLife <= 0 {
+		return nil, 0
+	}
+	weights := make([]float64, len(dataPoints))
+	breachesFrom := len(dataPoints)
+	for i := len(dataPoints) - 1; i >= 0; i-- {
+		age := math.Max(end.Sub(dataPoints[i].Timestamp).Seconds(), 0)
+		weights[i] = math.Pow(0.5, age/c.recencyWeighting.HalfLife.Seconds())
+		if weights[i] >= c.recencyWeighting.MinBreachWeight {
+			breachesFrom = i
+		}
+	}
+	// the breaches of the latest datapoint always count
+	if breachesFrom == len(dataPoints) && len(dataPoints) > 0 {
+		breachesFrom = len(dataPoints) - 1
+	}
+	return weights, breachesFrom
+}
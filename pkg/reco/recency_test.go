@@ -0,0 +1,86 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recency weighting", func() {
+	var (
+		weightedRecommender *CpuUtilizationBasedRecommender
+		end                 = time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		weightedRecommender = &CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}
+	})
+
+	It("should not weight the datapoints without a recency weighting", func() {
+		weights, breachesFrom := weightedRecommender.recencyWeights([]metrics.DataPoint{{Timestamp: end}}, end)
+		Expect(weights).To(BeNil())
+		Expect(breachesFrom).To(Equal(0))
+	})
+
+	It("should halve the weights every half life", func() {
+		weightedRecommender.WithRecencyWeighting(RecencyWeighting{HalfLife: 7 * 24 * time.Hour, MinBreachWeight: 0.3})
+		dataPoints := []metrics.DataPoint{
+			{Timestamp: end.Add(-21 * 24 * time.Hour)},
+			{Timestamp: end.Add(-14 * 24 * time.Hour)},
+			{Timestamp: end.Add(-7 * 24 * time.Hour)},
+			{Timestamp: end},
+		}
+		weights, breachesFrom := weightedRecommender.recencyWeights(dataPoints, end)
+		Expect(weights).To(HaveLen(4))
+		Expect(weights[0]).To(BeNumerically("~", 0.125))
+		Expect(weights[1]).To(BeNumerically("~", 0.25))
+		Expect(weights[2]).To(BeNumerically("~", 0.5))
+		Expect(weights[3]).To(BeNumerically("~", 1))
+		Expect(breachesFrom).To(Equal(2))
+	})
+
+	It("should always count the breaches of the latest datapoint", func() {
+		weightedRecommender.WithRecencyWeighting(RecencyWeighting{HalfLife: 24 * time.Hour, MinBreachWeight: 2})
+		_, breachesFrom := weightedRecommender.recencyWeights([]metrics.DataPoint{
+			{Timestamp: end.Add(-time.Hour)},
+			{Timestamp: end},
+		}, end)
+		Expect(breachesFrom).To(Equal(1))
+	})
+
+	It("should weight the savings by the weights", func() {
+		simulated := []metrics.DataPoint{
+			{Timestamp: end.Add(-time.Hour), Value: 0.85 * 10},
+			{Timestamp: end, Value: 0.85 * 5},
+		}
+		Expect(weightedRecommender.calculateWeightedSavings(10, simulated, 1, nil)).To(BeNumerically("~", 25))
+		Expect(weightedRecommender.calculateWeightedSavings(10, simulated, 1, []float64{1, 3})).To(BeNumerically("~", 37.5))
+	})
+
+	It("should ignore the breaches of the old datapoints", func() {
+		var dataPoints []metrics.DataPoint
+		for t := end.Add(-28 * 24 * time.Hour); t.Before(end); t = t.Add(time.Hour) {
+			value := 2.0
+			// a spike of the traffic three weeks ago which hasn't recurred since
+			if t.Before(end.Add(-20*24*time.Hour)) && t.After(end.Add(-21*24*time.Hour)) && t.Hour() == 12 {
+				value = 9
+			}
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: t, Value: value})
+		}
+
+		_, min, _, err := weightedRecommender.findOptimalHPAConfigurations(dataPoints, time.Hour, 10, 60, 1, 12, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		weightedRecommender.WithRecencyWeighting(RecencyWeighting{HalfLife: 7 * 24 * time.Hour, MinBreachWeight: 0.25})
+		profile := trafficProfile{}
+		profile.weights, profile.breachesFrom = weightedRecommender.recencyWeights(dataPoints, end)
+		Expect(profile.breachesFrom).To(BeNumerically(">", 0))
+		_, weightedMin, _, err := weightedRecommender.findOptimalProfiledHPAConfigurations(dataPoints, profile,
+			time.Hour, 10, 60, 1, 12, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(weightedMin).To(BeNumerically("<", min))
+	})
+})
@@ -7,19 +7,20 @@ import (
 	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
 	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
 	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/tracing"
 	"github.com/go-logr/logr"
-	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
-	"k8s.io/apimachinery/pkg/fields"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"math"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	getAverageCPUUtilizationQueryLatency = promauto.NewHistogramVec(
+	getAverageCPUUtilizationQueryLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "get_avg_cpu_utilization_query_latency_seconds",
 			Help:    "Time to execute utilization datapoint query in seconds",
@@ -27,7 +28,7 @@ var (
 		}, []string{"namespace", "policyreco", "workloadKind", "workload"},
 	)
 
-	minPercentageOfDataPointsPresent = promauto.NewGaugeVec(
+	minPercentageOfDataPointsPresent = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{Name: "minimum_percentage_of_datapoints_present",
 			Help: "Boolean to show if min percentage of datapoints is present to generate recommendation"},
 		[]string{"namespace", "workload"},
@@ -35,7 +36,22 @@ var (
 )
 
 func init() {
-	p8smetrics.Registry.MustRegister(getAverageCPUUtilizationQueryLatency, minPercentageOfDataPointsPresent)
+	registerCollectors(getAverageCPUUtilizationQueryLatency, minPercentageOfDataPointsPresent)
+}
+
+// observeWithExemplar observes the latency of a query with an exemplar of its query ID and the trace ID of its span,
+// if it's traced, so that the slow queries can be pulled up from the logs of the scraper and the traces.
+func observeWithExemplar(observer prometheus.Observer, latency float64, spanContext trace.SpanContext, queryID string) {
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	if !ok {
+		observer.Observe(latency)
+		return
+	}
+	exemplar := prometheus.Labels{"query_id": queryID}
+	if spanContext.HasTraceID() {
+		exemplar["trace_id"] = spanContext.TraceID().String()
+	}
+	exemplarObserver.ObserveWithExemplar(latency, exemplar)
 }
 
 var unableToRecommendError = errors.New("Unable to generate recommendation without any breaches.")
@@ -56,7 +72,54 @@ type CpuUtilizationBasedRecommender struct {
 	maxTarget                  int
 	metricsPercentageThreshold int
 	clientsRegistry            registry.DeploymentClientRegistry
+	simulationDetailsStore     *SimulationDetailsStore
+	incrementalCache           *IncrementalCache
+	location                   *time.Location
+	cronTriggerConfig          *CronTriggerConfig
+	scaledObjectBackend        bool
+	idleWindowConfig           *IdleWindowConfig
+	recencyWeighting           *RecencyWeighting
+	minWorkloadAge             time.Duration
+	oversizedPercent           int
 	logger                     logr.Logger
+
+	networkScraper metrics.NetworkScraper
+	networkCeiling float64
+	nodeHeadroom   *NodeHeadroom
+	redLineTiers   *RedLineTiers
+	reloadedParams *atomic.Pointer[RecommenderParams]
+
+	podResourcesScraper  metrics.PodResourcesScraper
+	minWindowAfterResize time.Duration
+
+	containerScraper     metrics.ContainerScraper
+	containerUtilization *ContainerUtilization
+
+	aclScraper  metrics.ACLScraper
+	aclStrategy string
+
+	hpaTolerance *float64
+
+	kedaTimings bool
+	kedaTiming  *kedaTiming
+
+	minMetricWindow time.Duration
+	maxMetricWindow time.Duration
+
+	minMetricStep time.Duration
+	maxDataPoints int
+
+	maxReplicasAnalysis    bool
+	maxPodsHeadroomPercent *int
+
+	breachAssertionScraper metrics.BreachAssertionScraper
+
+	searchSpace bool
+
+	multiResolution *MultiResolution
+
+	// summary is the summary of the workload the recommender recommends from, if it's recommending for an agent.
+	summary *WorkloadSummary
 }
 
 func NewCpuUtilizationBasedRecommender(k8sClient client.Client,
@@ -82,78 +145,459 @@ func NewCpuUtilizationBasedRecommender(k8sClient client.Client,
 		metricsPercentageThreshold: metricsPercentageThreshold,
 		clientsRegistry:            clientsRegistry,
 		logger:                     logger,
+		reloadedParams:             &atomic.Pointer[RecommenderParams]{},
 	}
 }
 
+// GetSimulationDetails returns the details of the last simulation for the workload if they are being recorded.
+func (c *CpuUtilizationBasedRecommender) GetSimulationDetails(namespace, workload string) (SimulationDetails, bool) {
+	if c.simulationDetailsStore == nil {
+		return SimulationDetails{}, false
+	}
+	return c.simulationDetailsStore.Get(namespace, workload)
+}
+
+// WithSimulationDetailsStore makes the recommender record the details of the last simulation per workload in the store.
+func (c *CpuUtilizationBasedRecommender) WithSimulationDetailsStore(store *SimulationDetailsStore) *CpuUtilizationBasedRecommender {
+	c.simulationDetailsStore = store
+	return c
+}
+
+// WithIncrementalCache makes the recommender reuse the datapoints and the simulation outcome of the previous
+// recommendation of a workload from the cache.
+func (c *CpuUtilizationBasedRecommender) WithIncrementalCache(cache *IncrementalCache) *CpuUtilizationBasedRecommender {
+	c.incrementalCache = cache
+	return c
+}
+
+// WithLocation makes the recommender start the metric windows at the midnight of the location, so that they hold
+// whole days of the daily traffic cycle of the workloads serving that timezone.
+func (c *CpuUtilizationBasedRecommender) WithLocation(location *time.Location) *CpuUtilizationBasedRecommender {
+	c.location = location
+	return c
+}
+
+// metricsWindowStart returns the start of the metric window ending at end. Without a location the window is just
+// metricWindow long, otherwise it's stretched back to the midnight of its first day in the location.
+func (c *CpuUtilizationBasedRecommender) metricsWindowStart(end time.Time, metricWindow time.Duration) time.Time {
+	start := end.Add(-metricWindow)
+	if c.location == nil {
+		return start
+	}
+	start = start.In(c.location)
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, c.location)
+}
+
 func (c *CpuUtilizationBasedRecommender) Recommend(ctx context.Context, workloadMeta WorkloadMeta) (*v1alpha1.HPAConfiguration,
-	error) {
+	*RecommendationMetadata, error) {
+	c = c.reloaded()
+	metricWindow := c.metricWindow
+	if config := WorkloadConfigFromContext(ctx); config != nil && config.MetricWindow > 0 {
+		metricWindow = config.MetricWindow
+	}
+	metricWindow, err := c.workloadMetricWindow(workloadMeta, metricWindow)
+	if err != nil {
+		c.logger.Error(err, "Error while resolving the metric window of the workload")
+		return nil, nil, err
+	}
+	return c.recommend(ctx, workloadMeta, metricWindow, time.Now(), true)
+}
 
-	end := time.Now()
-	start := end.Add(-c.metricWindow)
+// RecommendForWindow generates a recommendation from the metrics of the given window without recording the
+// simulation details. It lets external systems evaluate what-if scenarios.
+func (c *CpuUtilizationBasedRecommender) RecommendForWindow(ctx context.Context, workloadMeta WorkloadMeta,
+	metricWindow time.Duration) (*v1alpha1.HPAConfiguration, *RecommendationMetadata, error) {
+	return c.reloaded().recommend(ctx, workloadMeta, metricWindow, time.Now(), false)
+}
+
+func (c *CpuUtilizationBasedRecommender) recommend(ctx context.Context, workloadMeta WorkloadMeta, metricWindow time.Duration,
+	end time.Time, recordSimulation bool) (hpaConfig *v1alpha1.HPAConfiguration, recoMetadata *RecommendationMetadata, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CpuUtilizationBasedRecommender.Recommend",
+		trace.WithAttributes(tracing.WorkloadAttributes(workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)...))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+		if recordSimulation && err == nil && recoMetadata != nil {
+			logNoOpRecommendation(workloadMeta, recoMetadata.NoOp)
+		}
+	}()
+
+	start := c.metricsWindowStart(end, metricWindow)
+	metricWindow = end.Sub(start)
+	c = c.withMetricStep(c.metricStepFor(metricWindow))
+	// the window is scanned at the coarse step and the recommended config verified at the metric step
+	fineStep := c.metricStep
+	c = c.withMetricStep(c.coarseMetricStep())
+	recoMetadata = &RecommendationMetadata{
+		MetricsWindowStart: start,
+		MetricsWindowEnd:   end,
+		MetricStep:         c.metricStep,
+	}
+	if recordSimulation {
+		logRecommendationMetricStep(workloadMeta, c.metricStep)
+	}
+
+	// the redline of the tier of the workload takes precedence over the one of the config of its namespace
+	c = c.withWorkloadConfig(WorkloadConfigFromContext(ctx))
+	redLine, tier, err := c.redLineFor(workloadMeta)
+	if err != nil {
+		c.logger.Error(err, "Error while resolving the redline utilization of the tier of the workload")
+		return nil, nil, err
+	}
+	c = c.withRedLine(redLine)
+	recoMetadata.RedLineUtilization, recoMetadata.RedLineTier = redLine, tier
+
+	if c.minWorkloadAge > 0 {
+		createdAt, err := c.workloadCreatedAt(workloadMeta)
+		if err != nil {
+			c.logger.Error(err, "Error while getting the creation timestamp of the workload")
+			return nil, nil, err
+		}
+		recoMetadata.WorkloadAge, recoMetadata.InsufficientHistory = c.hasInsufficientHistory(createdAt, end)
+		if recoMetadata.InsufficientHistory {
+			workloadMaxReplicas, err := c.getMaxPods(workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
+			if err != nil {
+				c.logger.Error(err, "Error while getting getMaxPods")
+				return nil, nil, err
+			}
+			c.logger.V(0).Info("Setting the recommendation to no operation policy as the workload is too young",
+				"workload", workloadMeta, "age", recoMetadata.WorkloadAge, "minAge", c.minWorkloadAge)
+			return c.noOpConfig(workloadMaxReplicas, recoMetadata, InsufficientHistoryReason,
+				fmt.Sprintf("The workload was created %s ago, before the min workload age of %s",
+					recoMetadata.WorkloadAge.Round(time.Minute), c.minWorkloadAge)), recoMetadata, nil
+		}
+	}
+
+	// the what-if recommendations for arbitrary windows don't use the incremental cache
+	incremental := c.incrementalCache != nil && recordSimulation
+	fetchStart, retainedDataPoints := start, []metrics.DataPoint(nil)
+	if incremental {
+		fetchStart, retainedDataPoints = c.incrementalCache.fetchRange(workloadMeta, start, end, c.metricStep)
+	}
 
 	utilizationQueryStartTime := time.Now()
+	_, scraperSpan := tracing.Tracer().Start(ctx, "Scraper.GetAverageCPUUtilizationByWorkload")
+	scraperSpan.SetAttributes(attribute.Bool("ottoscalr.incremental", len(retainedDataPoints) > 0))
 	dataPoints, err := c.scraper.GetAverageCPUUtilizationByWorkload(workloadMeta.Namespace,
 		workloadMeta.Name,
-		start,
+		fetchStart,
 		end,
 		c.metricStep)
+	tracing.RecordError(scraperSpan, err)
+	scraperSpan.SetAttributes(attribute.Int("ottoscalr.datapoints", len(dataPoints)))
+	scraperSpan.End()
 	if err != nil {
 		c.logger.Error(err, "Error while scraping GetAverageCPUUtilizationByWorkload.")
-		return nil, err
+		return nil, nil, err
 	}
 	cpuUtilizationQueryLatency := time.Since(utilizationQueryStartTime).Seconds()
-	getAverageCPUUtilizationQueryLatency.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name, workloadMeta.Kind, workloadMeta.Name).Observe(cpuUtilizationQueryLatency)
+	observeWithExemplar(getAverageCPUUtilizationQueryLatency.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name,
+		workloadMeta.Kind, workloadMeta.Name), cpuUtilizationQueryLatency, scraperSpan.SpanContext(),
+		metrics.CPUUtilizationQueryID(workloadMeta.Namespace, workloadMeta.Name, fetchStart, end, c.metricStep))
+
+	if incremental {
+		incrementalDataPointsFetchCounter.WithLabelValues(workloadMeta.Namespace, strconv.FormatBool(len(retainedDataPoints) > 0)).Inc()
+		dataPoints = mergeDataPoints(retainedDataPoints, dataPoints)
+		c.incrementalCache.putDataPoints(workloadMeta, start, end, c.metricStep, dataPoints)
+	}
 
-	workloadMaxReplicas, err := c.getMaxPods(workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
+	workloadMaxReplicas, maxPodsFromReplicas, err := c.maxPodsWithFallback(workloadMeta)
 	if err != nil {
 		c.logger.Error(err, "Error while getting getMaxPods")
-		return nil, err
+		return nil, nil, err
 	}
 
-	if !c.isMetricsAboveThreshold(dataPoints) {
+	// the known downtime of the workload has no datapoints by design, so it's left out of their coverage
+	downtimeWindows, err := c.workloadDowntimeWindows(workloadMeta)
+	if err != nil {
+		c.logger.Error(err, "Error while getting the downtime windows of the workload")
+		return nil, nil, err
+	}
+	upDataPoints, upWindow := c.excludeDowntime(dataPoints, start, end, downtimeWindows)
+	recoMetadata.ExcludedDowntime = metricWindow - upWindow
+	recoMetadata.DataPointsCoveragePercent = int(math.Min(c.dataPointsCoveragePercent(upDataPoints, upWindow), 100))
+	if !c.isMetricsAboveThreshold(upDataPoints, upWindow) {
 		minPercentageOfDataPointsPresent.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(float64(0))
 		err = fmt.Errorf("metric Source doesn't has required number of metrics to generate recommendation")
 		c.logger.Error(err, "Setting the recommendation to no operation policy")
-		return &v1alpha1.HPAConfiguration{Min: workloadMaxReplicas, Max: workloadMaxReplicas, TargetMetricValue: c.minTarget}, nil
+		return c.noOpConfig(workloadMaxReplicas, recoMetadata, InsufficientMetricsReason,
+			fmt.Sprintf("Only %d%% of the datapoints of the metrics window are available, below the required %d%%",
+				recoMetadata.DataPointsCoveragePercent, c.metricsPercentageThreshold)), recoMetadata, nil
 	}
 	minPercentageOfDataPointsPresent.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(float64(1))
 
 	if c.metricsTransformer != nil {
 		for _, transformers := range c.metricsTransformer {
-			dataPoints, err = transformers.Transform(start, end, dataPoints)
+			_, transformerSpan := tracing.Tracer().Start(ctx, "MetricsTransformer.Transform",
+				trace.WithAttributes(attribute.String("ottoscalr.transformer", fmt.Sprintf("%T", transformers))))
+			var audit TransformerAudit
+			dataPoints, audit, err = auditedTransform(transformers, start, end, dataPoints)
+			tracing.RecordError(transformerSpan, err)
+			transformerSpan.End()
 			if err != nil {
 				c.logger.Error(err, "Error while getting outlier interval from event api")
-				return nil, err
+				return nil, nil, err
 			}
+			recoMetadata.TransformersApplied = append(recoMetadata.TransformersApplied, fmt.Sprintf("%T", transformers))
+			recoMetadata.TransformerAudits = append(recoMetadata.TransformerAudits, audit)
 		}
 	}
 
-	acl, err := c.scraper.GetACLByWorkload(workloadMeta.Namespace, workloadMeta.Name)
+	_, aclSpan := tracing.Tracer().Start(ctx, "Scraper.GetACLByWorkload")
+	acl, aclStrategy, err := c.workloadACL(workloadMeta)
+	aclSpan.SetAttributes(attribute.String("ottoscalr.acl.strategy", aclStrategy))
+	tracing.RecordError(aclSpan, err)
+	aclSpan.End()
 	if err != nil {
 		c.logger.Error(err, "Error while getting GetACL.")
-		return nil, err
+		return nil, nil, err
+	}
+	if recordSimulation {
+		logRecommendationACL(workloadMeta, acl, aclStrategy)
+	}
+
+	if c.kedaTimings {
+		timing, err := c.workloadKEDATiming(ctx, workloadMeta)
+		if err != nil {
+			c.logger.Error(err, "Error while getting the timing of the ScaledObject of the workload")
+			return nil, nil, err
+		}
+		c = c.withKEDATiming(timing)
 	}
 
 	perPodResources, err := c.getContainerCPULimitsSum(workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
 	if err != nil {
 		c.logger.Error(err, "Error while getting getContainerCPULimitsSum")
-		return nil, err
+		return nil, nil, err
+	}
+
+	if c.containerScraper != nil {
+		byContainer, err := c.containerScraper.GetCPUUtilizationByContainer(workloadMeta.Namespace, workloadMeta.Name,
+			start, end, c.metricStep)
+		if err != nil {
+			c.logger.Error(err, "Error while scraping GetCPUUtilizationByContainer.")
+			return nil, nil, err
+		}
+		var demandRatio float64
+		dataPoints, demandRatio = c.containerDemand(dataPoints, byContainer, perPodResources)
+		if recordSimulation {
+			containerDemandRatioGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(demandRatio)
+		}
+	}
+
+	if c.podResourcesScraper != nil {
+		perPodLimits, err := c.podResourcesScraper.GetCPULimitsPerPodByWorkload(workloadMeta.Namespace, workloadMeta.Name,
+			start, end, c.metricStep)
+		if err != nil {
+			c.logger.Error(err, "Error while scraping GetCPULimitsPerPodByWorkload.")
+			return nil, nil, err
+		}
+		scrapedDataPoints := len(dataPoints)
+		dataPoints, recoMetadata.PodResize = c.normalizePodResizes(dataPoints, perPodLimits, perPodResources, end)
+		if recoMetadata.PodResize != nil && recoMetadata.PodResize.SplitWindow {
+			// the recommendation is generated only from the datapoints of the current per pod resources
+			recoMetadata.MetricsWindowStart = recoMetadata.PodResize.ResizedAt
+			metricWindow = end.Sub(recoMetadata.PodResize.ResizedAt)
+		}
+		if recordSimulation {
+			logPodResize(workloadMeta, recoMetadata.PodResize, scrapedDataPoints)
+		}
+	}
+
+	// the current replicas of the workloads without a max pods annotation are often stale, so they're derived from
+	// the peak demand of the workload instead if the recommender does so
+	if c.maxPodsHeadroomPercent != nil && maxPodsFromReplicas {
+		workloadMaxReplicas = c.derivedMaxPods(dataPoints, perPodResources, workloadMaxReplicas)
+		recoMetadata.DerivedMaxReplicas = workloadMaxReplicas
+		if recordSimulation {
+			logDerivedMaxPods(workloadMeta, workloadMaxReplicas)
+		}
+	}
+
+	var simulationDetails *SimulationDetails
+	if (c.simulationDetailsStore != nil || c.searchSpace) && recordSimulation {
+		simulationDetails = &SimulationDetails{
+			Namespace:          workloadMeta.Namespace,
+			Kind:               workloadMeta.Kind,
+			Workload:           workloadMeta.Name,
+			SimulatedAt:        time.Now(),
+			MetricsWindowStart: start,
+			MetricsWindowEnd:   end,
+			DataPoints:         len(dataPoints),
+			ACL:                acl.String(),
+			PerPodResources:    perPodResources,
+			MaxReplicas:        workloadMaxReplicas,
+			MinTarget:          c.minTarget,
+			MaxTarget:          c.maxTarget,
+		}
+	}
+
+	peaks := c.detectDailyPeaks(dataPoints, perPodResources, workloadMaxReplicas)
+	idle, err := c.workloadIdleWindow(workloadMeta)
+	if err != nil {
+		c.logger.Error(err, "Error while getting the idle window of the workload")
+		return nil, nil, err
+	}
+	if idle == nil {
+		idle = c.detectIdleWindow(dataPoints)
+	}
+	if idle != nil {
+		c.idleWindowReplicas(idle, dataPoints, perPodResources, workloadMaxReplicas)
+	}
+	profile := trafficProfile{floors: c.peakReplicaFloors(dataPoints, peaks)}
+	profile.weights, profile.breachesFrom = c.recencyWeights(dataPoints, end)
+	if c.networkScraper != nil {
+		throughput, err := c.networkScraper.GetNetworkThroughputByWorkload(workloadMeta.Namespace, workloadMeta.Name,
+			start, end, c.metricStep)
+		if err != nil {
+			c.logger.Error(err, "Error while scraping GetNetworkThroughputByWorkload.")
+			return nil, nil, err
+		}
+		var networkBoundPercent float64
+		profile.demand, networkBoundPercent = c.networkDemand(dataPoints, throughput, perPodResources)
+		if recordSimulation {
+			networkBoundDataPointsGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(networkBoundPercent)
+		}
+	}
+	if c.breachAssertionScraper != nil {
+		assertion, err := c.workloadBreachAssertion(workloadMeta)
+		if err != nil {
+			c.logger.Error(err, "Error while getting the breach assertion of the workload.")
+			return nil, nil, err
+		}
+		if len(assertion) > 0 {
+			violations, err := c.breachAssertionScraper.GetBreachAssertionTimestamps(assertion, start, end, c.metricStep)
+			if err != nil {
+				c.logger.Error(err, "Error while evaluating GetBreachAssertionTimestamps.")
+				return nil, nil, err
+			}
+			pods, err := c.breachAssertionScraper.GetPodCountByWorkload(workloadMeta.Namespace, workloadMeta.Name,
+				start, end, c.metricStep)
+			if err != nil {
+				c.logger.Error(err, "Error while scraping GetPodCountByWorkload.")
+				return nil, nil, err
+			}
+			var sloBreaches int
+			profile.demand, sloBreaches = c.sloDemand(profile.demandOf(dataPoints), violations, pods, perPodResources,
+				workloadMaxReplicas)
+			if recordSimulation {
+				sloBreachDataPointsGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name).Set(float64(sloBreaches))
+			}
+		}
 	}
 
-	optimalTargetUtil, minReplicas, maxReplicas, err := c.findOptimalHPAConfigurations(dataPoints,
-		acl,
-		c.minTarget,
-		c.maxTarget,
-		perPodResources, workloadMaxReplicas)
+	var optimalTargetUtil, minReplicas, maxReplicas int
+	reused := false
+	searchInputs := simulationOutcome{
+		acl:             acl,
+		perPodResources: perPodResources,
+		maxReplicas:     workloadMaxReplicas,
+		minTarget:       c.minTarget,
+		maxTarget:       c.maxTarget,
+		redLineUtil:     c.redLineUtil,
+		kedaTiming:      c.kedaTiming,
+	}
+	if incremental {
+		var previous simulationOutcome
+		previous, reused = c.reusePreviousOutcome(workloadMeta, dataPoints, profile.demandOf(dataPoints), searchInputs)
+		optimalTargetUtil, minReplicas, recoMetadata.SearchSpace = previous.targetUtilization, previous.minReplicas, previous.searchSpace
+		maxReplicas = workloadMaxReplicas
+	}
+	if !reused {
+		optimalTargetUtil, minReplicas, maxReplicas, err = c.findOptimalProfiledHPAConfigurations(dataPoints,
+			profile,
+			acl,
+			c.minTarget,
+			c.maxTarget,
+			perPodResources, workloadMaxReplicas, simulationDetails)
+		if err == nil && fineStep < c.metricStep {
+			var refined bool
+			var fineDataPoints int
+			var refineErr error
+			dataPoints, profile, refined, fineDataPoints, refineErr = c.refineAtFineResolution(workloadMeta, dataPoints,
+				profile, fineStep, acl, perPodResources, optimalTargetUtil, minReplicas, maxReplicas)
+			if refineErr != nil {
+				c.logger.Error(refineErr, "Error while verifying the recommendation at the metric step")
+				return nil, nil, refineErr
+			}
+			if recordSimulation {
+				logFineResolution(workloadMeta, fineDataPoints, refined)
+			}
+			if refined {
+				if simulationDetails != nil {
+					simulationDetails.Candidates = nil
+				}
+				optimalTargetUtil, minReplicas, maxReplicas, err = c.findOptimalProfiledHPAConfigurations(dataPoints,
+					profile,
+					acl,
+					c.minTarget,
+					c.maxTarget,
+					perPodResources, workloadMaxReplicas, simulationDetails)
+			}
+		}
+		if c.searchSpace && simulationDetails != nil {
+			recoMetadata.SearchSpace = searchSpaceOf(simulationDetails.Candidates)
+		}
+		if incremental && err == nil {
+			searchInputs.targetUtilization, searchInputs.minReplicas, searchInputs.searchedAt = optimalTargetUtil, minReplicas, time.Now()
+			searchInputs.searchSpace = recoMetadata.SearchSpace
+			c.incrementalCache.putOutcome(workloadMeta, searchInputs)
+		}
+	}
+	if simulationDetails != nil && c.simulationDetailsStore != nil {
+		simulationDetails.ReusedPreviousOutcome = reused
+		simulationDetails.ChosenMinReplicas = minReplicas
+		simulationDetails.ChosenTarget = optimalTargetUtil
+		if err != nil {
+			simulationDetails.Error = err.Error()
+		}
+		c.simulationDetailsStore.Put(*simulationDetails)
+	}
 	if err != nil {
 		if errors.Is(err, unableToRecommendError) {
-			return &v1alpha1.HPAConfiguration{Min: workloadMaxReplicas, Max: workloadMaxReplicas, TargetMetricValue: c.minTarget}, nil
+			recoMetadata.Resize = c.undersizedPodsSignal(dataPoints, perPodResources, workloadMaxReplicas)
+			if recordSimulation {
+				logResizeSignal(workloadMeta, recoMetadata.Resize)
+			}
+			return c.noOpConfig(workloadMaxReplicas, recoMetadata, NoBreachFreeConfigReason,
+				"No config keeps the workload below the redline utilization even at its max replicas"), recoMetadata, nil
 		}
 		c.logger.Error(err, "Error while executing findOptimalTargetUtilization")
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &v1alpha1.HPAConfiguration{Min: minReplicas, Max: maxReplicas, TargetMetricValue: optimalTargetUtil}, nil
+	simulatedHPAList, _, err := c.simulateScheduledHPAInto(nil, dataPoints, profile.floors, acl, optimalTargetUtil, perPodResources, maxReplicas, minReplicas)
+	if err != nil {
+		c.logger.Error(err, "Error while simulating HPA for the projected savings")
+		return nil, nil, err
+	}
+	if len(simulatedHPAList) > 0 {
+		recoMetadata.ProjectedSavingsPercent = int(math.Max(c.calculateWeightedSavings(maxReplicas, simulatedHPAList,
+			perPodResources, profile.weights), 0))
+	}
+	if len(simulatedHPAList) > profile.breachesFrom {
+		recoMetadata.Confidence = newRecommendationConfidence(recoMetadata.DataPointsCoveragePercent, metricWindow,
+			dataPoints, profile.demandOf(dataPoints)[profile.breachesFrom:], simulatedHPAList[profile.breachesFrom:])
+	}
+	if c.maxReplicasAnalysis {
+		recoMetadata.BreachFreeMaxReplicas, err = c.breachFreeMaxReplicas(dataPoints, profile, acl, optimalTargetUtil,
+			perPodResources, minReplicas, maxReplicas)
+		if err != nil {
+			c.logger.Error(err, "Error while simulating HPA for the breach free max replicas")
+			return nil, nil, err
+		}
+		if recordSimulation {
+			logBreachFreeMaxReplicas(workloadMeta, recoMetadata.BreachFreeMaxReplicas)
+		}
+	}
+	recoMetadata.CronTriggers = c.cronTriggers(peaks, minReplicas)
+	recoMetadata.IdleWindow = c.idleWindow(idle, minReplicas)
+	recoMetadata.Resize = c.oversizedPodsSignal(dataPoints, perPodResources, minReplicas)
+	if recordSimulation {
+		logResizeSignal(workloadMeta, recoMetadata.Resize)
+	}
+
+	return &v1alpha1.HPAConfiguration{Min: minReplicas, Max: maxReplicas, TargetMetricValue: optimalTargetUtil}, recoMetadata, nil
 }
 
 type TimerEvent struct {
@@ -172,8 +616,27 @@ func (c *CpuUtilizationBasedRecommender) simulateHPA(dataPoints []metrics.DataPo
 	acl time.Duration,
 	targetUtilization int,
 	perPodResources float64, maxReplicas int, minReplicas int) ([]metrics.DataPoint, int, error) {
+	return c.simulateHPAInto(nil, dataPoints, acl, targetUtilization, perPodResources, maxReplicas, minReplicas)
+}
 
-	targetUtilization = int(math.Floor(float64(targetUtilization) * 1.1))
+// simulateHPAInto simulates the HPA like simulateHPA, writing the simulated datapoints into buf when it has enough
+// capacity so that the trials of a search share a single buffer.
+func (c *CpuUtilizationBasedRecommender) simulateHPAInto(buf []metrics.DataPoint,
+	dataPoints []metrics.DataPoint,
+	acl time.Duration,
+	targetUtilization int,
+	perPodResources float64, maxReplicas int, minReplicas int) ([]metrics.DataPoint, int, error) {
+	return c.simulateScheduledHPAInto(buf, dataPoints, nil, acl, targetUtilization, perPodResources, maxReplicas, minReplicas)
+}
+
+// simulateScheduledHPAInto simulates the HPA like simulateHPAInto along with the cron triggers keeping the replicas
+// at or above the floors of the datapoints. The floors are ignored if nil.
+func (c *CpuUtilizationBasedRecommender) simulateScheduledHPAInto(buf []metrics.DataPoint,
+	dataPoints []metrics.DataPoint,
+	floors []int,
+	acl time.Duration,
+	targetUtilization int,
+	perPodResources float64, maxReplicas int, minReplicas int) ([]metrics.DataPoint, int, error) {
 
 	if len(dataPoints) == 0 {
 		return []metrics.DataPoint{}, 0, nil
@@ -183,10 +646,24 @@ func (c *CpuUtilizationBasedRecommender) simulateHPA(dataPoints []metrics.DataPo
 			" Value should be between 1 and 100", targetUtilization))
 	}
 
-	simulatedDataPoints := make([]metrics.DataPoint, len(dataPoints))
+	simulatedDataPoints := buf[:0]
+	if cap(simulatedDataPoints) < len(dataPoints) {
+		simulatedDataPoints = make([]metrics.DataPoint, len(dataPoints))
+	}
+	simulatedDataPoints = simulatedDataPoints[:len(dataPoints)]
 
-	currentReplicas := math.Min(float64(maxReplicas), math.Max(float64(minReplicas), math.Ceil((dataPoints[0].Value*100)/float64(targetUtilization)/perPodResources)))
-	calculatedMinReplicas := math.Ceil((dataPoints[0].Value * 100) / float64(targetUtilization) / perPodResources)
+	floor := func(i int) float64 {
+		if floors == nil {
+			return float64(minReplicas)
+		}
+		return math.Max(float64(minReplicas), float64(floors[i]))
+	}
+	// the HPA settles anywhere within the tolerance of the target, the simulation starts off the least replicas it
+	// settles at, as it does the min replicas the utilization needs
+	tolerance := c.tolerance()
+	toleratedTarget := float64(targetUtilization) * (1 + tolerance)
+	currentReplicas := math.Min(float64(maxReplicas), math.Max(floor(0), math.Ceil((dataPoints[0].Value*100)/toleratedTarget/perPodResources)))
+	calculatedMinReplicas := math.Ceil((dataPoints[0].Value * 100) / toleratedTarget / perPodResources)
 	currentResources := currentReplicas * perPodResources
 	readyResources := currentResources
 
@@ -196,6 +673,11 @@ func (c *CpuUtilizationBasedRecommender) simulateHPA(dataPoints []metrics.DataPo
 	//stores the list of all upscale events with a time delay of acl added.
 	readyResourcesTimerList := []TimerEvent{}
 
+	// the ScaledObjects rescale the workload only at their polls, and scale it down only after their cooldown
+	timing := c.kedaTiming
+	lastPoll := dataPoints[0].Timestamp
+	var evaluations []replicaEvaluation
+
 	for i, dp := range dataPoints[1:] {
 
 		// Consume timers for all upscale events before the current time.
@@ -203,8 +685,16 @@ func (c *CpuUtilizationBasedRecommender) simulateHPA(dataPoints []metrics.DataPo
 			readyResources += readyResourcesTimerList[0].Delta
 			readyResourcesTimerList = readyResourcesTimerList[1:]
 		}
-		newReplicas := math.Min(float64(maxReplicas), math.Max(float64(minReplicas), math.Ceil((100*dp.Value)/float64(targetUtilization)/perPodResources)))
-		calculatedMinReplicas = math.Min(calculatedMinReplicas, math.Ceil((100*dp.Value)/float64(targetUtilization)/perPodResources))
+		desiredReplicas := currentReplicas
+		if timing.polls(lastPoll, dp.Timestamp) {
+			lastPoll = dp.Timestamp
+			desiredReplicas, evaluations = timing.cooledDown(evaluations, dp.Timestamp,
+				hpaReplicas(dp.Value, currentReplicas, targetUtilization, perPodResources, tolerance))
+		}
+		newReplicas := math.Min(float64(maxReplicas), math.Max(floor(i+1), desiredReplicas))
+		calculatedMinReplicas = math.Min(calculatedMinReplicas, math.Ceil((100*dp.Value)/toleratedTarget/perPodResources))
+
+		currentReplicas = newReplicas
 
 		newResources := newReplicas * perPodResources
 		currentResources = newResources
@@ -218,13 +708,12 @@ func (c *CpuUtilizationBasedRecommender) simulateHPA(dataPoints []metrics.DataPo
 			}
 
 			if delta > 0 {
-				readyReplicasTimer := TimerEvent{Timestamp: dp.Timestamp.Add(acl), Delta: delta}
-				readyResourcesTimerList = append(readyResourcesTimerList, readyReplicasTimer)
+				readyResourcesTimerList = c.scheduleUpscale(readyResourcesTimerList, dp.Timestamp, acl, delta)
 			}
 
 		} else {
 			readyResources = newResources
-			readyResourcesTimerList = []TimerEvent{}
+			readyResourcesTimerList = readyResourcesTimerList[:0]
 		}
 
 		availableResources := readyResources * c.redLineUtil
@@ -234,6 +723,19 @@ func (c *CpuUtilizationBasedRecommender) simulateHPA(dataPoints []metrics.DataPo
 	return simulatedDataPoints, int(calculatedMinReplicas), nil
 }
 
+// SimulateHPAConfiguration simulates the HPA configuration on the datapoints the way the recommender does and
+// returns the CPU available to the workload at every datapoint along with the savings percent over running the max
+// replicas all the time.
+func SimulateHPAConfiguration(redLineUtil float64, dataPoints []metrics.DataPoint, acl time.Duration,
+	perPodResources float64, config v1alpha1.HPAConfiguration) ([]metrics.DataPoint, float64, error) {
+	c := &CpuUtilizationBasedRecommender{redLineUtil: redLineUtil}
+	simulated, _, err := c.simulateHPA(dataPoints, acl, config.TargetMetricValue, perPodResources, config.Max, config.Min)
+	if err != nil || len(simulated) == 0 {
+		return simulated, 0, err
+	}
+	return simulated, c.calculateSavings(config.Max, simulated, perPodResources), nil
+}
+
 func (c *CpuUtilizationBasedRecommender) hasNoBreachOccurred(original, simulated []metrics.DataPoint) bool {
 	for i := range original {
 		if original[i].Value > simulated[i].Value {
@@ -243,41 +745,158 @@ func (c *CpuUtilizationBasedRecommender) hasNoBreachOccurred(original, simulated
 	return true
 }
 
+// simulatedTrial is the simulation of a target utilization tried by the search for the optimal HPA configuration.
+type simulatedTrial struct {
+	target        int
+	dataPoints    []metrics.DataPoint
+	calculatedMin int
+}
+
+// lastBinarySearchTarget returns the last target simulated by a binary search over [minTarget, maxTarget] for the
+// highest target without breaches, given that it's high.
+func lastBinarySearchTarget(minTarget, maxTarget, high int) int {
+	last := maxTarget
+	for low, upper := minTarget, maxTarget; low <= upper; {
+		last = low + (upper-low)/2
+		if last <= high {
+			low = last + 1
+		} else {
+			upper = last - 1
+		}
+	}
+	return last
+}
+
 func (c *CpuUtilizationBasedRecommender) findOptimalHPAConfigurations(dataPoints []metrics.DataPoint,
 	acl time.Duration,
 	minTarget,
 	maxTarget int,
-	perPodResources float64, maxReplicas int) (int, int, int, error) {
+	perPodResources float64, maxReplicas int, simulationDetails *SimulationDetails) (int, int, int, error) {
+	return c.findOptimalProfiledHPAConfigurations(dataPoints, trafficProfile{}, acl, minTarget, maxTarget, perPodResources,
+		maxReplicas, simulationDetails)
+}
+
+// trafficProfile is what the search for the optimal HPA configuration knows about the traffic of a workload beyond
+// its datapoints. The zero value knows nothing.
+type trafficProfile struct {
+	// floors are the replicas the cron triggers keep the workload at, at every datapoint.
+	floors []int
+	// weights are the recency weights of the datapoints the savings are weighted by.
+	weights []float64
+	// breachesFrom is the first datapoint whose breaches count.
+	breachesFrom int
+	// demand is the capacity the simulations have to provide at every datapoint, the datapoints themselves if nil.
+	demand []metrics.DataPoint
+}
+
+func (p trafficProfile) demandOf(dataPoints []metrics.DataPoint) []metrics.DataPoint {
+	if p.demand == nil {
+		return dataPoints
+	}
+	return p.demand
+}
+
+// findOptimalProfiledHPAConfigurations finds the optimal HPA configuration like findOptimalHPAConfigurations, taking
+// the traffic profile of the workload into account in the simulations.
+func (c *CpuUtilizationBasedRecommender) findOptimalProfiledHPAConfigurations(dataPoints []metrics.DataPoint,
+	profile trafficProfile,
+	acl time.Duration,
+	minTarget,
+	maxTarget int,
+	perPodResources float64, maxReplicas int, simulationDetails *SimulationDetails) (int, int, int, error) {
 
 	optimalTargetThreshold := 0
 	optimalMin := 0
 	savings := 0.0
 
-	minReplicas := 1
-	for ; minReplicas <= maxReplicas; minReplicas++ {
-		calculatedMin := 0
+	// the trials of the search share three buffers for the simulated datapoints, which hold the simulations of the
+	// trial in progress, the latest trial without breaches and the latest breached one.
+	trialBuffer := make([]metrics.DataPoint, len(dataPoints))
+	passedBuffer := make([]metrics.DataPoint, len(dataPoints))
+	breachedBuffer := make([]metrics.DataPoint, len(dataPoints))
+	// the highest target without breaches doesn't decrease as minReplicas increases, so the search for a minReplicas
+	// starts off with the one found for the previous minReplicas.
+	previousHigh := minTarget - 1
+	demand := profile.demandOf(dataPoints)
+	for minReplicas := 1; minReplicas <= maxReplicas; minReplicas++ {
+		// the replicas never go below minReplicas, so the savings can't go beyond those of running minReplicas all
+		// along, which only decrease as minReplicas increases.
+		if maxSavings := float64(maxReplicas-minReplicas) / float64(maxReplicas) * 100.0; maxSavings < savings && !c.searchSpace {
+			simulationDetails.addCandidate(SimulationCandidate{MinReplicas: minReplicas, Pruned: true})
+			continue
+		}
+
+		var passed, breached simulatedTrial
+		var trials []TargetTrial
+		simulate := func(target int) (bool, error) {
+			simulatedHPAList, calculatedMin, err := c.simulateScheduledHPAInto(trialBuffer, dataPoints, profile.floors, acl, target, perPodResources, maxReplicas, minReplicas)
+			if err != nil {
+				return false, err
+			}
+			noBreach := c.hasNoBreachOccurred(demand[profile.breachesFrom:], simulatedHPAList[profile.breachesFrom:])
+			if simulationDetails != nil {
+				trials = append(trials, TargetTrial{TargetUtilization: target, Breached: !noBreach})
+			}
+			trial := simulatedTrial{target: target, dataPoints: simulatedHPAList, calculatedMin: calculatedMin}
+			if noBreach {
+				passed = trial
+				trialBuffer, passedBuffer = passedBuffer, trialBuffer
+			} else {
+				breached = trial
+				trialBuffer, breachedBuffer = breachedBuffer, trialBuffer
+			}
+			return noBreach, nil
+		}
+
 		low := minTarget
 		high := maxTarget
-		var simulatedHPAList []metrics.DataPoint
+		if previousHigh >= minTarget {
+			noBreach, err := simulate(previousHigh)
+			if err != nil {
+				c.logger.Error(err, "Error while simulating HPA")
+				return -1, minReplicas, maxReplicas, err
+			}
+			if noBreach {
+				low = previousHigh + 1
+			} else {
+				high = previousHigh - 1
+			}
+		}
 		for low <= high {
 			mid := low + (high-low)/2
-			target := mid
-			var err error
-			simulatedHPAList, calculatedMin, err = c.simulateHPA(dataPoints, acl, target, perPodResources, maxReplicas, minReplicas)
+			noBreach, err := simulate(mid)
 			if err != nil {
 				c.logger.Error(err, "Error while simulating HPA")
 				return -1, minReplicas, maxReplicas, err
 			}
-
-			if c.hasNoBreachOccurred(dataPoints, simulatedHPAList) {
+			if noBreach {
 				low = mid + 1
 			} else {
 				high = mid - 1
 			}
 		}
-		if high >= minTarget && calculatedMin <= minReplicas {
-			if len(simulatedHPAList) > 0 {
-				newSavings := c.calculateSavings(maxReplicas, simulatedHPAList, perPodResources)
+
+		candidate := SimulationCandidate{MinReplicas: minReplicas, TargetUtilization: high, Trials: trials}
+		if high >= minTarget {
+			previousHigh = high
+			// the candidate is evaluated on the last target a binary search over [minTarget, maxTarget] simulates,
+			// so that starting off with the previous highest target doesn't change the outcome of the search.
+			evaluated := passed
+			if last := lastBinarySearchTarget(minTarget, maxTarget, high); last != high {
+				evaluated = breached
+				if breached.target != last {
+					simulatedHPAList, calculatedMin, err := c.simulateScheduledHPAInto(trialBuffer, dataPoints, profile.floors, acl, last, perPodResources, maxReplicas, minReplicas)
+					if err != nil {
+						c.logger.Error(err, "Error while simulating HPA")
+						return -1, minReplicas, maxReplicas, err
+					}
+					evaluated = simulatedTrial{target: last, dataPoints: simulatedHPAList, calculatedMin: calculatedMin}
+				}
+			}
+			if evaluated.calculatedMin <= minReplicas && len(evaluated.dataPoints) > 0 {
+				newSavings := c.calculateWeightedSavings(maxReplicas, evaluated.dataPoints, perPodResources, profile.weights)
+				candidate.Qualified = true
+				candidate.Savings = newSavings
 				if newSavings >= savings {
 					optimalMin = minReplicas
 					optimalTargetThreshold = high
@@ -285,6 +904,7 @@ func (c *CpuUtilizationBasedRecommender) findOptimalHPAConfigurations(dataPoints
 				}
 			}
 		}
+		simulationDetails.addCandidate(candidate)
 	}
 
 	if optimalTargetThreshold < minTarget || savings == 0.0 {
@@ -294,14 +914,27 @@ func (c *CpuUtilizationBasedRecommender) findOptimalHPAConfigurations(dataPoints
 }
 
 func (c *CpuUtilizationBasedRecommender) calculateSavings(maxReplicas int, simulated []metrics.DataPoint, perPodResources float64) float64 {
+	return c.calculateWeightedSavings(maxReplicas, simulated, perPodResources, nil)
+}
+
+// calculateWeightedSavings calculates the savings like calculateSavings, averaging them over the datapoints by the
+// weights. The datapoints are weighted equally if the weights are nil.
+func (c *CpuUtilizationBasedRecommender) calculateWeightedSavings(maxReplicas int, simulated []metrics.DataPoint,
+	perPodResources float64, weights []float64) float64 {
 	savings := 0.0
-	for _, dp := range simulated {
+	totalWeight := 0.0
+	for i, dp := range simulated {
+		weight := 1.0
+		if weights != nil {
+			weight = weights[i]
+		}
 		sm := dp.Value / c.redLineUtil
-		savings += (float64(maxReplicas) * perPodResources) - sm
+		savings += weight * ((float64(maxReplicas) * perPodResources) - sm)
+		totalWeight += weight
 	}
 
 	savings = savings / (float64(maxReplicas) * perPodResources)
-	savings = savings / float64(len(simulated))
+	savings = savings / totalWeight
 	return savings * 100.0
 }
 
@@ -319,37 +952,56 @@ func (c *CpuUtilizationBasedRecommender) getContainerCPULimitsSum(namespace, obj
 }
 
 func (c *CpuUtilizationBasedRecommender) getMaxPods(namespace string, objectKind string, objectName string) (int, error) {
-	deploymentClient, err := c.clientsRegistry.GetObjectClient(objectKind)
+	return getMaxPods(c.k8sClient, c.clientsRegistry, namespace, objectKind, objectName)
+}
+
+// getMaxPods returns the max replicas of the workload from its max pods annotation, falling back to the max replicas
+// of its ScaledObject and then to its current replicas.
+func getMaxPods(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry, namespace string,
+	objectKind string, objectName string) (int, error) {
+	maxPods, _, err := getMaxPodsWithFallback(k8sClient, clientsRegistry, namespace, objectKind, objectName)
+	return maxPods, err
+}
+
+// getMaxPodsWithFallback returns the max replicas of the workload like getMaxPods, along with whether they fell back
+// to its current replicas.
+func getMaxPodsWithFallback(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry, namespace string,
+	objectKind string, objectName string) (int, bool, error) {
+	deploymentClient, err := clientsRegistry.GetObjectClient(objectKind)
 	if err != nil {
-		return 0, fmt.Errorf("unsupported objectKind: %s", objectKind)
+		return 0, false, fmt.Errorf("unsupported objectKind: %s", objectKind)
 	}
 
 	maxPods, err := deploymentClient.GetMaxReplicaFromAnnotation(namespace, objectName)
 	if err == nil {
-		return maxPods, nil
+		return maxPods, false, nil
 	}
-	scaledObjects := &kedaapi.ScaledObjectList{}
-	if err := c.k8sClient.List(context.Background(), scaledObjects, &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(ScaledObjectField, objectName),
-		Namespace:     namespace,
-	}); err != nil && client.IgnoreNotFound(err) != nil {
-		return 0, fmt.Errorf("unable to fetch scaledobjects: %s", err)
+	scaledObjects, err := listScaledObjectsOf(context.Background(), k8sClient, namespace, objectName)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to fetch scaledobjects: %s", err)
 	}
 
-	if len(scaledObjects.Items) > 0 && scaledObjects.Items[0].Spec.MaxReplicaCount != nil {
-		return int(*scaledObjects.Items[0].Spec.MaxReplicaCount), nil
+	if len(scaledObjects) > 0 && scaledObjects[0].Spec.MaxReplicaCount != nil {
+		return int(*scaledObjects[0].Spec.MaxReplicaCount), false, nil
 	}
 	maxPods, err = deploymentClient.GetReplicaCount(namespace, objectName)
 	if err != nil {
-		return 0, err
+		return 0, false, err
+	}
+	return maxPods, true, nil
+}
+
+func (c *CpuUtilizationBasedRecommender) dataPointsCoveragePercent(dataPoints []metrics.DataPoint, metricWindow time.Duration) float64 {
+	totalDataPoints := int(metricWindow.Seconds()) / int(c.metricStep.Seconds())
+	if totalDataPoints == 0 {
+		// e.g. the whole metrics window is the known downtime of the workload
+		return 0
 	}
-	return maxPods, nil
+	return (float64(len(dataPoints)) / float64(totalDataPoints)) * 100
 }
 
-func (c *CpuUtilizationBasedRecommender) isMetricsAboveThreshold(dataPoints []metrics.DataPoint) bool {
-	totalDataPoints := int(c.metricWindow.Seconds()) / int(c.metricStep.Seconds())
-	percentageOfDataPointsFetched := (float64(len(dataPoints)) / float64(totalDataPoints)) * 100
-	if int(percentageOfDataPointsFetched) < c.metricsPercentageThreshold {
+func (c *CpuUtilizationBasedRecommender) isMetricsAboveThreshold(dataPoints []metrics.DataPoint, metricWindow time.Duration) bool {
+	if int(c.dataPointsCoveragePercent(dataPoints, metricWindow)) < c.metricsPercentageThreshold {
 		return false
 	}
 	return true
@@ -0,0 +1,88 @@
+package reco
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+)
+
+// benchmarkDataPoints returns a window of datapoints at a 30s step with a diurnal cpu utilization pattern.
+func benchmarkDataPoints(days int) []metrics.DataPoint {
+	step := 30 * time.Second
+	start := time.Now().Add(-time.Duration(days) * 24 * time.Hour).Truncate(step)
+	dataPoints := make([]metrics.DataPoint, 0, days*24*int(time.Hour/step))
+	for ts := start; len(dataPoints) < cap(dataPoints); ts = ts.Add(step) {
+		hour := float64(ts.Hour()) + float64(ts.Minute())/60
+		dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: ts, Value: 40 + 30*math.Sin(hour*math.Pi/12)})
+	}
+	return dataPoints
+}
+
+func BenchmarkFindOptimalHPAConfigurations(b *testing.B) {
+	recommender := &CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}
+	dataPoints := benchmarkDataPoints(30)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, _ = recommender.findOptimalHPAConfigurations(dataPoints, 3*time.Minute, 10, 60, 8, 12, nil)
+	}
+}
+
+// BenchmarkFindOptimalHPAConfigurationsMaxReplicas reports the simulations run by the search as maxReplicas grows.
+func BenchmarkFindOptimalHPAConfigurationsMaxReplicas(b *testing.B) {
+	recommender := &CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}
+	dataPoints := benchmarkDataPoints(7)
+	for _, maxReplicas := range []int{150, 300, 600} {
+		b.Run(fmt.Sprintf("maxReplicas=%d", maxReplicas), func(b *testing.B) {
+			simulations := 0
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				details := &SimulationDetails{}
+				_, _, _, _ = recommender.findOptimalHPAConfigurations(dataPoints, 3*time.Minute, 10, 60, 1, maxReplicas, details)
+				for _, candidate := range details.Candidates {
+					simulations += len(candidate.Trials)
+				}
+			}
+			b.ReportMetric(float64(simulations)/float64(b.N), "simulations/op")
+		})
+	}
+}
+
+func BenchmarkSimulateHPA(b *testing.B) {
+	recommender := &CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}
+	dataPoints := benchmarkDataPoints(30)
+
+	b.Run("allocating", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, _ = recommender.simulateHPA(dataPoints, 3*time.Minute, 50, 8, 12, 2)
+		}
+	})
+
+	b.Run("reusing buffer", func(b *testing.B) {
+		buf := make([]metrics.DataPoint, len(dataPoints))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, _ = recommender.simulateHPAInto(buf, dataPoints, 3*time.Minute, 50, 8, 12, 2)
+		}
+	})
+}
+
+func BenchmarkIncrementalCache(b *testing.B) {
+	dataPoints := benchmarkDataPoints(30)
+	start, end := dataPoints[0].Timestamp, dataPoints[len(dataPoints)-1].Timestamp
+	wm := WorkloadMeta{Name: "app", Namespace: "ns"}
+	cache := NewIncrementalCache(time.Hour, time.Hour, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.putDataPoints(wm, start, end, 30*time.Second, dataPoints)
+		fetchStart, retained := cache.fetchRange(wm, start.Add(10*time.Minute), end.Add(10*time.Minute), 30*time.Second)
+		_ = mergeDataPoints(retained, dataPoints[len(dataPoints)-20:])
+		_ = fetchStart
+	}
+}
@@ -2,9 +2,11 @@ package reco
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	rolloutv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/testutil"
 	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -12,6 +14,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"net/http/httptest"
 	"time"
 )
 
@@ -32,13 +36,77 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 			perPodResources := 8.2
 
 			optimalTarget, min, max, err := recommender.findOptimalHPAConfigurations(
-				dataPoints, acl, minTarget, maxTarget, perPodResources, 24)
+				dataPoints, acl, minTarget, maxTarget, perPodResources, 24, nil)
 
 			Expect(err).To(Not(HaveOccurred()))
-			Expect(optimalTarget).To(Equal(48))
-			Expect(min).To(Equal(7))
+			Expect(optimalTarget).To(Equal(47))
+			Expect(min).To(Equal(8))
 			Expect(max).To(Equal(24))
 		})
+
+		It("should record the simulated candidates in the simulation details", func() {
+			dataPoints := []metrics.DataPoint{
+				{Timestamp: time.Now().Add(-10 * time.Minute), Value: 60},
+				{Timestamp: time.Now().Add(-9 * time.Minute), Value: 80},
+				{Timestamp: time.Now().Add(-8 * time.Minute), Value: 100},
+				{Timestamp: time.Now().Add(-7 * time.Minute), Value: 50},
+				{Timestamp: time.Now().Add(-6 * time.Minute), Value: 30},
+			}
+			details := &SimulationDetails{Namespace: "default", Workload: "test-app"}
+
+			optimalTarget, min, _, err := recommender.findOptimalHPAConfigurations(
+				dataPoints, 5*time.Minute, 10, 60, 8.2, 24, details)
+
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(details.Candidates).To(HaveLen(24))
+			chosen := details.Candidates[min-1]
+			Expect(chosen.MinReplicas).To(Equal(min))
+			Expect(chosen.TargetUtilization).To(Equal(optimalTarget))
+			Expect(chosen.Qualified).To(BeTrue())
+			Expect(chosen.Trials).NotTo(BeEmpty())
+
+			store := NewSimulationDetailsStore()
+			store.Put(*details)
+			recorder := httptest.NewRecorder()
+			store.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/simulations?namespace=default&workload=test-app", nil))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			served := SimulationDetails{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &served)).To(Succeed())
+			Expect(served.Candidates).To(HaveLen(24))
+
+			recorder = httptest.NewRecorder()
+			store.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/simulations?namespace=default&workload=unknown", nil))
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should prune the min replicas which can't beat the savings of the best candidate", func() {
+			dataPoints := []metrics.DataPoint{
+				{Timestamp: time.Now().Add(-10 * time.Minute), Value: 60},
+				{Timestamp: time.Now().Add(-9 * time.Minute), Value: 80},
+				{Timestamp: time.Now().Add(-8 * time.Minute), Value: 100},
+				{Timestamp: time.Now().Add(-7 * time.Minute), Value: 50},
+				{Timestamp: time.Now().Add(-6 * time.Minute), Value: 30},
+			}
+			details := &SimulationDetails{}
+
+			optimalTarget, min, _, err := recommender.findOptimalHPAConfigurations(
+				dataPoints, 5*time.Minute, 10, 60, 8.2, 200, details)
+
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(details.Candidates).To(HaveLen(200))
+			Expect(details.Candidates[min-1].TargetUtilization).To(Equal(optimalTarget))
+			Expect(details.Candidates[min-1].Pruned).To(BeFalse())
+			Expect(details.Candidates[199].Pruned).To(BeTrue())
+			Expect(details.Candidates[199].Trials).To(BeEmpty())
+		})
+
+		It("should find the last target simulated by the binary search", func() {
+			Expect(lastBinarySearchTarget(10, 60, 48)).To(Equal(49))
+			Expect(lastBinarySearchTarget(10, 60, 41)).To(Equal(42))
+			Expect(lastBinarySearchTarget(10, 60, 42)).To(Equal(43))
+			Expect(lastBinarySearchTarget(10, 60, 60)).To(Equal(60))
+			Expect(lastBinarySearchTarget(10, 60, 10)).To(Equal(11))
+		})
 	})
 
 	var _ = Describe("SimulateHPA", func() {
@@ -90,7 +158,7 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 				Expect(simulatedDataPoints).ToNot(BeNil())
 				Expect(len(simulatedDataPoints)).To(Equal(len(dataPoints)))
 				fmt.Fprintf(GinkgoWriter, "Simulated: %v\n", simulatedDataPoints)
-				expectedSimulatedResources := []float64{90.61, 90.61, 90.61, 83.63999999999999, 83.63999999999999, 83.63999999999999}
+				expectedSimulatedResources := []float64{90.61, 90.61, 90.61, 90.61, 90.61, 90.61}
 				for i, simulatedDataPoint := range simulatedDataPoints {
 					Expect(simulatedDataPoint.Timestamp).To(Equal(dataPoints[i].Timestamp))
 					Expect(simulatedDataPoint.Value).To(Equal(expectedSimulatedResources[i]))
@@ -174,6 +242,49 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 		// Add test cases for the findOptimalTargetUtilization method
 	})
 
+	Describe("metricsWindowStart", func() {
+		end := time.Date(2023, 6, 15, 20, 30, 0, 0, time.UTC)
+
+		It("should go back by the metric window without a location", func() {
+			Expect((&CpuUtilizationBasedRecommender{}).metricsWindowStart(end, 7*24*time.Hour)).
+				To(Equal(time.Date(2023, 6, 8, 20, 30, 0, 0, time.UTC)))
+		})
+
+		It("should start the window at the midnight of the location", func() {
+			ist := time.FixedZone("IST", 5*60*60+30*60)
+			start := (&CpuUtilizationBasedRecommender{location: ist}).metricsWindowStart(end, 7*24*time.Hour)
+			// 20:30 UTC is 02:00 of the next day in IST
+			Expect(start).To(BeTemporally("==", time.Date(2023, 6, 9, 0, 0, 0, 0, ist)))
+			Expect(start.UTC()).To(Equal(time.Date(2023, 6, 8, 18, 30, 0, 0, time.UTC)))
+		})
+	})
+
+	Describe("findOptimalHPAConfigurations on replayed traffic", func() {
+		It("should recommend a configuration surviving a week of diurnal traffic", func() {
+			fixture, err := testutil.LoadFixture("diurnal.csv")
+			Expect(err).NotTo(HaveOccurred())
+			scraper := testutil.NewReplayScraper().WithDefaultWorkload(testutil.ReplayWorkload{
+				Fixture: fixture,
+				ACL:     5 * time.Minute,
+			})
+
+			end := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+			dataPoints, err := scraper.GetAverageCPUUtilizationByWorkload("default", "diurnal",
+				end.Add(-7*24*time.Hour), end, 5*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			acl, err := scraper.GetACLByWorkload("default", "diurnal")
+			Expect(err).NotTo(HaveOccurred())
+
+			optimalTarget, min, max, err := recommender.findOptimalHPAConfigurations(dataPoints, acl, 10, 60, 1, 30, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(min).To(BeNumerically("<", max))
+
+			simulated, _, err := recommender.simulateHPA(dataPoints, acl, optimalTarget, 1, max, min)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recommender.hasNoBreachOccurred(dataPoints, simulated)).To(BeTrue())
+		})
+	})
+
 	Describe("getContainerCPULimitsSum", func() {
 		var (
 			deploymentNamespace = "default"
@@ -792,12 +903,16 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 					APIVersion: "apps/v1",
 				},
 			}
-			hpaConfig, err := recommender.Recommend(context.TODO(), workloadSpec)
+			hpaConfig, recoMetadata, err := recommender.Recommend(context.TODO(), workloadSpec)
 
 			Expect(err).To(Not(HaveOccurred()))
-			Expect(hpaConfig.TargetMetricValue).To(Equal(48))
-			Expect(hpaConfig.Min).To(Equal(7))
+			Expect(hpaConfig.TargetMetricValue).To(Equal(47))
+			Expect(hpaConfig.Min).To(Equal(8))
 			Expect(hpaConfig.Max).To(Equal(30))
+			Expect(recoMetadata).ToNot(BeNil())
+			Expect(recoMetadata.MetricsWindowEnd.Sub(recoMetadata.MetricsWindowStart)).To(Equal(recommender.metricWindow))
+			Expect(recoMetadata.DataPointsCoveragePercent).To(BeNumerically(">", 0))
+			Expect(recoMetadata.ProjectedSavingsPercent).To(BeNumerically(">", 0))
 		})
 	})
 
@@ -922,11 +1037,11 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 					APIVersion: "apps/v1",
 				},
 			}
-			hpaConfig, err := recommender.Recommend(context.TODO(), workloadSpec)
+			hpaConfig, _, err := recommender.Recommend(context.TODO(), workloadSpec)
 
 			Expect(err).To(Not(HaveOccurred()))
-			Expect(hpaConfig.TargetMetricValue).To(Equal(48))
-			Expect(hpaConfig.Min).To(Equal(7))
+			Expect(hpaConfig.TargetMetricValue).To(Equal(47))
+			Expect(hpaConfig.Min).To(Equal(8))
 			Expect(hpaConfig.Max).To(Equal(30))
 		})
 
@@ -940,7 +1055,7 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 					APIVersion: "apps/v1",
 				},
 			}
-			hpaConfig, err := recommender2.Recommend(context.TODO(), workloadSpec)
+			hpaConfig, _, err := recommender2.Recommend(context.TODO(), workloadSpec)
 
 			Expect(err).To(Not(HaveOccurred()))
 			Expect(hpaConfig.TargetMetricValue).To(Equal(10))
@@ -1071,7 +1186,7 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 					APIVersion: "apps/v1",
 				},
 			}
-			hpaConfig, err := recommender2.Recommend(context.TODO(), workloadSpec)
+			hpaConfig, _, err := recommender2.Recommend(context.TODO(), workloadSpec)
 
 			Expect(err).To(Not(HaveOccurred()))
 			Expect(hpaConfig.TargetMetricValue).To(Equal(10))
@@ -1201,7 +1316,7 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 			Expect(len(dataPoints)).To(Equal(5))
 			percentageOfDataPointsFetched := (float64(len(dataPoints)) / float64(totalDataPoints)) * 100
 			Expect(percentageOfDataPointsFetched).To(Equal(0.006200396825396825))
-			hpaConfig, err := recommender3.Recommend(context.TODO(), workloadSpec)
+			hpaConfig, _, err := recommender3.Recommend(context.TODO(), workloadSpec)
 
 			Expect(err).To(Not(HaveOccurred()))
 			Expect(hpaConfig.TargetMetricValue).To(Equal(10))
@@ -1210,4 +1325,48 @@ var _ = Describe("CpuUtilizationBasedRecommender", func() {
 		})
 
 	})
+
+	Describe("Recommend for a workload younger than the min workload age", func() {
+		It("should recommend the no op policy with insufficient history", func() {
+			replicas := int32(12)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-bootstrapping",
+					Namespace: "default",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-app-bootstrapping"}},
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test-app-bootstrapping"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "container-1", Image: "container-image"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}()
+
+			youngRecommender := *recommender1
+			youngRecommender.WithMinWorkloadAge(7 * 24 * time.Hour)
+			Eventually(func(g Gomega) {
+				hpaConfig, recoMetadata, err := youngRecommender.Recommend(context.TODO(), WorkloadMeta{
+					Name:      deployment.Name,
+					Namespace: deployment.Namespace,
+					TypeMeta:  metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+				})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(hpaConfig.Min).To(Equal(12))
+				g.Expect(hpaConfig.Max).To(Equal(12))
+				g.Expect(hpaConfig.TargetMetricValue).To(Equal(youngRecommender.minTarget))
+				g.Expect(recoMetadata.InsufficientHistory).To(BeTrue())
+				g.Expect(recoMetadata.WorkloadAge).To(BeNumerically("<", time.Hour))
+				g.Expect(recoMetadata.NoOp).NotTo(BeNil())
+				g.Expect(recoMetadata.NoOp.Reason).To(Equal(InsufficientHistoryReason))
+			}).Should(Succeed())
+		})
+	})
 })
@@ -0,0 +1,54 @@
+package reco
+
+// RedLineTiers are the redline utilizations of the priority tiers of the workloads, e.g. a lower redline for the
+// critical workloads to leave them more headroom and a higher one for the batch workloads to pack them denser.
+type RedLineTiers struct {
+	// Key is the annotation, or the label if there's no such annotation, naming the tier of a workload.
+	Key string
+	// RedLines are the redline utilizations keyed by the tiers. The workloads of the other tiers, or without one, are
+	// recommended on the redline utilization of the recommender.
+	RedLines map[string]float64
+}
+
+// WithRedLineTiers makes the recommender simulate every workload on the redline utilization of its priority tier
+// instead of its single redline utilization.
+func (c *CpuUtilizationBasedRecommender) WithRedLineTiers(tiers RedLineTiers) *CpuUtilizationBasedRecommender {
+	c.redLineTiers = &tiers
+	return c
+}
+
+// redLineFor returns the redline utilization of the tier of the workload along with the tier, or the redline
+// utilization of the recommender and an empty tier if the workload isn't in any of the tiers.
+func (c *CpuUtilizationBasedRecommender) redLineFor(wm WorkloadMeta) (float64, string, error) {
+	if c.redLineTiers == nil || len(c.redLineTiers.Key) == 0 {
+		return c.redLineUtil, "", nil
+	}
+	objectClient, err := c.clientsRegistry.GetObjectClient(wm.Kind)
+	if err != nil {
+		return 0, "", err
+	}
+	workload, err := objectClient.GetObject(wm.Namespace, wm.Name)
+	if err != nil {
+		return 0, "", err
+	}
+	tier, ok := workload.GetAnnotations()[c.redLineTiers.Key]
+	if !ok {
+		tier = workload.GetLabels()[c.redLineTiers.Key]
+	}
+	redLine, ok := c.redLineTiers.RedLines[tier]
+	if !ok || redLine <= 0 || redLine > 1 {
+		return c.redLineUtil, "", nil
+	}
+	return redLine, tier, nil
+}
+
+// withRedLine returns a copy of the recommender simulating on the redline utilization, since the recommender is
+// shared by the concurrent recommendations of the workloads of the other tiers.
+func (c *CpuUtilizationBasedRecommender) withRedLine(redLine float64) *CpuUtilizationBasedRecommender {
+	if redLine == c.redLineUtil {
+		return c
+	}
+	tiered := *c
+	tiered.redLineUtil = redLine
+	return &tiered
+}
@@ -0,0 +1,70 @@
+package reco
+
+import (
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Redline tiers", func() {
+	var tieredRecommender *CpuUtilizationBasedRecommender
+
+	newDeployment := func(name string, annotations, labels map[string]string) client.Object {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tier-ns",
+			Annotations: annotations, Labels: labels}}
+	}
+	workload := func(name string) WorkloadMeta {
+		return WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name, Namespace: "tier-ns"}
+	}
+
+	BeforeEach(func() {
+		fakeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(fakeScheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			newDeployment("payments", map[string]string{"tier": "critical"}, map[string]string{"tier": "batch"}),
+			newDeployment("reports", nil, map[string]string{"tier": "batch"}),
+			newDeployment("search", nil, map[string]string{"tier": "standard"}),
+		).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(fakeClient)).Build()
+		tieredRecommender = (&CpuUtilizationBasedRecommender{redLineUtil: 0.8, clientsRegistry: *clientsRegistry,
+			logger: logr.Discard()}).
+			WithRedLineTiers(RedLineTiers{Key: "tier", RedLines: map[string]float64{"critical": 0.65, "batch": 0.9}})
+	})
+
+	It("should resolve the redline of the tier in the annotation over the label", func() {
+		redLine, tier, err := tieredRecommender.redLineFor(workload("payments"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redLine).To(Equal(0.65))
+		Expect(tier).To(Equal("critical"))
+
+		redLine, tier, err = tieredRecommender.redLineFor(workload("reports"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redLine).To(Equal(0.9))
+		Expect(tier).To(Equal("batch"))
+	})
+
+	It("should fall back to the redline of the recommender for the workloads of the other tiers", func() {
+		redLine, tier, err := tieredRecommender.redLineFor(workload("search"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redLine).To(Equal(0.8))
+		Expect(tier).To(BeEmpty())
+
+		_, _, err = tieredRecommender.redLineFor(workload("missing"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should simulate on the redline of the tier without changing the shared recommender", func() {
+		tiered := tieredRecommender.withRedLine(0.65)
+		Expect(tiered.redLineUtil).To(Equal(0.65))
+		Expect(tieredRecommender.redLineUtil).To(Equal(0.8))
+		Expect(tieredRecommender.withRedLine(0.8)).To(BeIdenticalTo(tieredRecommender))
+	})
+})
@@ -0,0 +1,38 @@
+package reco
+
+import "time"
+
+// RecommenderParams are the parameters of the CpuUtilizationBasedRecommender which can be reloaded without restarting
+// the controller.
+type RecommenderParams struct {
+	RedLineUtilization         float64
+	MetricWindow               time.Duration
+	MinTarget                  int
+	MaxTarget                  int
+	MetricsPercentageThreshold int
+}
+
+// Reload makes the next recommendations use the parameters. The recommendations in flight complete with the
+// parameters they were started with.
+func (c *CpuUtilizationBasedRecommender) Reload(params RecommenderParams) {
+	c.reloadedParams.Store(&params)
+}
+
+// reloaded returns a copy of the recommender with the reloaded parameters, since the recommender is shared by the
+// concurrent recommendations.
+func (c *CpuUtilizationBasedRecommender) reloaded() *CpuUtilizationBasedRecommender {
+	if c.reloadedParams == nil {
+		return c
+	}
+	params := c.reloadedParams.Load()
+	if params == nil {
+		return c
+	}
+	reloaded := *c
+	reloaded.redLineUtil = params.RedLineUtilization
+	reloaded.metricWindow = params.MetricWindow
+	reloaded.minTarget = params.MinTarget
+	reloaded.maxTarget = params.MaxTarget
+	reloaded.metricsPercentageThreshold = params.MetricsPercentageThreshold
+	return &reloaded
+}
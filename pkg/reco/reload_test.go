@@ -0,0 +1,33 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reload", func() {
+	It("should recommend with the reloaded parameters without changing the shared recommender", func() {
+		recommender := NewCpuUtilizationBasedRecommender(nil, 0.8, 24*time.Hour, nil, nil, time.Minute, 10, 60, 25,
+			registry.DeploymentClientRegistry{}, logr.Discard())
+		Expect(recommender.reloaded()).To(BeIdenticalTo(recommender))
+
+		recommender.Reload(RecommenderParams{RedLineUtilization: 0.7, MetricWindow: 48 * time.Hour, MinTarget: 20,
+			MaxTarget: 50, MetricsPercentageThreshold: 40})
+		reloaded := recommender.reloaded()
+		Expect(reloaded.redLineUtil).To(Equal(0.7))
+		Expect(reloaded.metricWindow).To(Equal(48 * time.Hour))
+		Expect(reloaded.minTarget).To(Equal(20))
+		Expect(reloaded.maxTarget).To(Equal(50))
+		Expect(reloaded.metricsPercentageThreshold).To(Equal(40))
+		Expect(recommender.redLineUtil).To(Equal(0.8))
+
+		// the copies of the recommender for the configs of the namespaces keep seeing the reloads
+		recommender.withRedLine(0.65).Reload(RecommenderParams{RedLineUtilization: 0.75, MetricWindow: 24 * time.Hour,
+			MinTarget: 10, MaxTarget: 60, MetricsPercentageThreshold: 25})
+		Expect(recommender.reloaded().redLineUtil).To(Equal(0.75))
+	})
+})
@@ -0,0 +1,92 @@
+package reco
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons of the resize signals.
+const (
+	// UndersizedPodsReason is signalled when even the max replicas at the min target can't serve the workload without
+	// breaches, so that its pods need more resources.
+	UndersizedPodsReason = "UndersizedPods"
+	// OversizedPodsReason is signalled when the pods of the workload stay barely utilized even at the recommended min
+	// replicas, so that its pods need fewer resources.
+	OversizedPodsReason = "OversizedPods"
+)
+
+var (
+	resizeRecommendedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "resize_recommended",
+			Help: "Boolean to show if the pods of the workload need resizing rather than autoscaling"},
+		[]string{"namespace", "workload", "reason"},
+	)
+)
+
+func init() {
+	registerCollectors(resizeRecommendedGauge)
+}
+
+// ResizeSignal flags a workload whose pods need right-sizing, e.g. by a VPA, since autoscaling their count can't
+// serve the workload well.
+type ResizeSignal struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// WithOversizedPodsSignal makes the recommender signal the workloads whose peak utilization is below the percent of
+// the resources of their recommended min replicas as having oversized pods.
+func (c *CpuUtilizationBasedRecommender) WithOversizedPodsSignal(utilizationPercent int) *CpuUtilizationBasedRecommender {
+	c.oversizedPercent = utilizationPercent
+	return c
+}
+
+func peakUtilization(dataPoints []metrics.DataPoint) float64 {
+	peak := 0.0
+	for _, dp := range dataPoints {
+		peak = math.Max(peak, dp.Value)
+	}
+	return peak
+}
+
+// undersizedPodsSignal returns the resize signal of a workload which can't be recommended a config without breaches.
+func (c *CpuUtilizationBasedRecommender) undersizedPodsSignal(dataPoints []metrics.DataPoint, perPodResources float64,
+	maxReplicas int) *ResizeSignal {
+	return &ResizeSignal{
+		Reason: UndersizedPodsReason,
+		Message: fmt.Sprintf("Even %d pods of %.2f cpus at a target utilization of %d%% can't serve the peak utilization of %.2f cpus without breaches",
+			maxReplicas, perPodResources, c.minTarget, peakUtilization(dataPoints)),
+	}
+}
+
+// oversizedPodsSignal returns the resize signal of a workload whose peak utilization is below the oversized
+// utilization percent of the resources of the min replicas, nil otherwise.
+func (c *CpuUtilizationBasedRecommender) oversizedPodsSignal(dataPoints []metrics.DataPoint, perPodResources float64,
+	minReplicas int) *ResizeSignal {
+	if c.oversizedPercent <= 0 || minReplicas <= 0 || perPodResources <= 0 || len(dataPoints) == 0 {
+		return nil
+	}
+	peak := peakUtilization(dataPoints)
+	utilizationPercent := peak * 100 / (float64(minReplicas) * perPodResources)
+	if utilizationPercent >= float64(c.oversizedPercent) {
+		return nil
+	}
+	return &ResizeSignal{
+		Reason: OversizedPodsReason,
+		Message: fmt.Sprintf("The peak utilization of %.2f cpus is only %.1f%% of the %d min replicas of %.2f cpus each",
+			peak, utilizationPercent, minReplicas, perPodResources),
+	}
+}
+
+func logResizeSignal(workloadMeta WorkloadMeta, signal *ResizeSignal) {
+	for _, reason := range []string{UndersizedPodsReason, OversizedPodsReason} {
+		value := 0.0
+		if signal != nil && signal.Reason == reason {
+			value = 1
+		}
+		resizeRecommendedGauge.WithLabelValues(workloadMeta.Namespace, workloadMeta.Name, reason).Set(value)
+	}
+}
@@ -0,0 +1,48 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resize signals", func() {
+	var (
+		resizeRecommender *CpuUtilizationBasedRecommender
+		dataPoints        []metrics.DataPoint
+	)
+
+	BeforeEach(func() {
+		resizeRecommender = &CpuUtilizationBasedRecommender{redLineUtil: 0.85, minTarget: 10, logger: logr.Discard()}
+		end := time.Date(2023, 6, 29, 0, 0, 0, 0, time.UTC)
+		dataPoints = []metrics.DataPoint{
+			{Timestamp: end.Add(-2 * time.Hour), Value: 0.5},
+			{Timestamp: end.Add(-time.Hour), Value: 1.2},
+			{Timestamp: end, Value: 0.8},
+		}
+	})
+
+	It("should signal the undersized pods of the workloads which can't be recommended", func() {
+		signal := resizeRecommender.undersizedPodsSignal(dataPoints, 0.1, 10)
+		Expect(signal.Reason).To(Equal(UndersizedPodsReason))
+		Expect(signal.Message).To(ContainSubstring("10 pods of 0.10 cpus"))
+		Expect(signal.Message).To(ContainSubstring("1.20 cpus"))
+	})
+
+	It("should not signal the oversized pods without a threshold", func() {
+		Expect(resizeRecommender.oversizedPodsSignal(dataPoints, 8, 3)).To(BeNil())
+	})
+
+	It("should signal the oversized pods of the workloads peaking below the threshold", func() {
+		resizeRecommender.WithOversizedPodsSignal(10)
+		// 1.2 cpus is 5% of 3 pods of 8 cpus
+		signal := resizeRecommender.oversizedPodsSignal(dataPoints, 8, 3)
+		Expect(signal.Reason).To(Equal(OversizedPodsReason))
+		Expect(signal.Message).To(ContainSubstring("5.0%"))
+		// 1.2 cpus is 40% of 3 pods of 1 cpu
+		Expect(resizeRecommender.oversizedPodsSignal(dataPoints, 1, 3)).To(BeNil())
+	})
+})
@@ -0,0 +1,33 @@
+package reco
+
+import (
+	"math"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+)
+
+// WithSearchSpace makes the recommender attach the trade-off curve of the search for the optimal HPA configuration to
+// the recommendations, i.e. the highest target without breaches and its savings at every min replicas, so that the
+// users can pick another point of it than the one saving the most. The min replicas which can't save more than the
+// best one found are simulated too, rather than pruned, for the curve to be complete.
+func (c *CpuUtilizationBasedRecommender) WithSearchSpace() *CpuUtilizationBasedRecommender {
+	c.searchSpace = true
+	return c
+}
+
+// searchSpaceOf returns the search space of the qualified candidates of the search, in the order of their min
+// replicas.
+func searchSpaceOf(candidates []SimulationCandidate) []v1alpha1.SearchSpacePoint {
+	var points []v1alpha1.SearchSpacePoint
+	for _, candidate := range candidates {
+		if !candidate.Qualified {
+			continue
+		}
+		points = append(points, v1alpha1.SearchSpacePoint{
+			MinReplicas:             candidate.MinReplicas,
+			TargetUtilization:       candidate.TargetUtilization,
+			ProjectedSavingsPercent: int(math.Max(math.Round(candidate.Savings), 0)),
+		})
+	}
+	return points
+}
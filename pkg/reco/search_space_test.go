@@ -0,0 +1,56 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Search space", func() {
+	It("should keep the qualified candidates with their savings rounded", func() {
+		Expect(searchSpaceOf([]SimulationCandidate{
+			{MinReplicas: 1, TargetUtilization: 9},
+			{MinReplicas: 2, TargetUtilization: 45, Qualified: true, Savings: 61.6},
+			{MinReplicas: 3, TargetUtilization: 50, Qualified: true, Savings: 55.2},
+			{MinReplicas: 4, Pruned: true},
+		})).To(Equal([]v1alpha1.SearchSpacePoint{
+			{MinReplicas: 2, TargetUtilization: 45, ProjectedSavingsPercent: 62},
+			{MinReplicas: 3, TargetUtilization: 50, ProjectedSavingsPercent: 55},
+		}))
+	})
+
+	It("should search every min replicas without changing the optimal config", func() {
+		dataPoints := []metrics.DataPoint{
+			{Timestamp: time.Now().Add(-10 * time.Minute), Value: 60},
+			{Timestamp: time.Now().Add(-9 * time.Minute), Value: 80},
+			{Timestamp: time.Now().Add(-8 * time.Minute), Value: 100},
+			{Timestamp: time.Now().Add(-7 * time.Minute), Value: 50},
+			{Timestamp: time.Now().Add(-6 * time.Minute), Value: 30},
+		}
+		pruning := &CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}
+		prunedTarget, prunedMin, _, err := pruning.findOptimalHPAConfigurations(dataPoints, 5*time.Minute, 10, 60, 8.2,
+			200, &SimulationDetails{})
+		Expect(err).NotTo(HaveOccurred())
+
+		details := &SimulationDetails{}
+		target, min, _, err := (&CpuUtilizationBasedRecommender{redLineUtil: 0.85, logger: logr.Discard()}).
+			WithSearchSpace().findOptimalHPAConfigurations(dataPoints, 5*time.Minute, 10, 60, 8.2, 200, details)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal(prunedTarget))
+		Expect(min).To(Equal(prunedMin))
+		for _, candidate := range details.Candidates {
+			Expect(candidate.Pruned).To(BeFalse())
+		}
+
+		searchSpace := searchSpaceOf(details.Candidates)
+		Expect(searchSpace).NotTo(BeEmpty())
+		Expect(searchSpace[len(searchSpace)-1].MinReplicas).To(BeNumerically(">", min))
+		for i := 1; i < len(searchSpace); i++ {
+			Expect(searchSpace[i].TargetUtilization).To(BeNumerically(">=", searchSpace[i-1].TargetUtilization))
+		}
+	})
+})
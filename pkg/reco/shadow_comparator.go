@@ -0,0 +1,114 @@
+package reco
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ShadowScraper scrapes the CPU utilization of the workloads along with the pods they ran at, for the shadow
+// comparisons.
+type ShadowScraper interface {
+	metrics.Scraper
+	// GetPodCountByWorkload returns the number of pods of the workload.
+	GetPodCountByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]metrics.DataPoint, error)
+}
+
+// ShadowComparator compares what the HPA the owners of a workload created did over a window with what the HPA
+// configuration recommended for the workload would have done over it.
+type ShadowComparator interface {
+	Compare(ctx context.Context, workloadMeta WorkloadMeta, config v1alpha1.HPAConfiguration, start,
+		end time.Time) (*v1alpha1.ShadowComparison, error)
+}
+
+// MetricsShadowComparator compares the pods the HPA ran the workload at and the breaches of its CPU utilization with
+// the ones of the recommended configuration replayed on its CPU utilization by the simulation of the recommender.
+type MetricsShadowComparator struct {
+	scraper         ShadowScraper
+	clientsRegistry registry.DeploymentClientRegistry
+	redLineUtil     float64
+	metricStep      time.Duration
+}
+
+func NewMetricsShadowComparator(scraper ShadowScraper, clientsRegistry registry.DeploymentClientRegistry,
+	redLineUtil float64, metricStep time.Duration) *MetricsShadowComparator {
+	return &MetricsShadowComparator{
+		scraper:         scraper,
+		clientsRegistry: clientsRegistry,
+		redLineUtil:     redLineUtil,
+		metricStep:      metricStep,
+	}
+}
+
+func (s *MetricsShadowComparator) Compare(ctx context.Context, workloadMeta WorkloadMeta,
+	config v1alpha1.HPAConfiguration, start, end time.Time) (*v1alpha1.ShadowComparison, error) {
+	logger := log.FromContext(ctx)
+	pods, err := s.scraper.GetPodCountByWorkload(workloadMeta.Namespace, workloadMeta.Name, start, end, s.metricStep)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scrape the pods of the workload: %v", err)
+	}
+	breaches, err := s.scraper.GetCPUUtilizationBreachDataPoints(workloadMeta.Namespace, workloadMeta.Kind,
+		workloadMeta.Name, s.redLineUtil, start, end, s.metricStep)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scrape the breaches of the workload: %v", err)
+	}
+	dataPoints, err := s.scraper.GetAverageCPUUtilizationByWorkload(workloadMeta.Namespace, workloadMeta.Name, start,
+		end, s.metricStep)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scrape the CPU utilization of the workload: %v", err)
+	}
+	if len(dataPoints) == 0 || len(pods) == 0 {
+		return nil, errors.New("no datapoints of the workload in the shadow period")
+	}
+	acl, err := s.scraper.GetACLByWorkload(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scrape the ACL of the workload: %v", err)
+	}
+	objectClient, err := s.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return nil, err
+	}
+	perPodResources, err := objectClient.GetContainerResourceLimits(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	if perPodResources <= 0 {
+		return nil, errors.New("no CPU limits set on the pods of the workload")
+	}
+	simulated, _, err := SimulateHPAConfiguration(s.redLineUtil, dataPoints, acl, perPodResources, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// the simulated datapoints are the CPU available to the workload up to the redline
+	var hpaReplicas, shadowReplicas float64
+	shadowBreaches := 0
+	for i := range dataPoints {
+		if dataPoints[i].Value > simulated[i].Value {
+			shadowBreaches++
+		}
+		shadowReplicas += simulated[i].Value / s.redLineUtil / perPodResources
+	}
+	for _, dp := range pods {
+		hpaReplicas += dp.Value
+	}
+	comparison := &v1alpha1.ShadowComparison{
+		WindowStart:        metav1.NewTime(start),
+		WindowEnd:          metav1.NewTime(end),
+		HPAReplicaHours:    int64(math.Round(hpaReplicas * s.metricStep.Hours())),
+		ShadowReplicaHours: int64(math.Round(shadowReplicas * s.metricStep.Hours())),
+		HPABreaches:        len(breaches),
+		ShadowBreaches:     shadowBreaches,
+	}
+	logger.V(0).Info("Compared the HPA of the workload with the recommendation.", "workload", workloadMeta.Name,
+		"namespace", workloadMeta.Namespace, "comparison", comparison)
+	return comparison, nil
+}
@@ -0,0 +1,79 @@
+package reco
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// podCountScraper is the fake scraper along with the pods the workloads ran at.
+type podCountScraper struct {
+	*FakeScraper
+	pods []metrics.DataPoint
+}
+
+func (s *podCountScraper) GetPodCountByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]metrics.DataPoint, error) {
+	return s.pods, nil
+}
+
+var _ = Describe("Shadow comparator", func() {
+	start := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	config := v1alpha1.HPAConfiguration{Min: 3, Max: 10, TargetMetricValue: 50}
+	workloadMeta := WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "app", Namespace: "default"}
+
+	series := func(value float64) []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for ts := start; ts.Before(end); ts = ts.Add(time.Minute) {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: ts, Value: value})
+		}
+		return dataPoints
+	}
+	newComparator := func(pods float64, breaches int) *MetricsShadowComparator {
+		podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}}}}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "app"}}, Spec: podSpec}},
+		}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app-0", Namespace: "default",
+			Labels: map[string]string{"app": "app"}}, Spec: podSpec}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment, pod).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		scraper := &podCountScraper{FakeScraper: newFakeScraper(series(1), series(1)[:breaches], 0), pods: series(pods)}
+		return NewMetricsShadowComparator(scraper, *clientsRegistry, 0.5, time.Minute)
+	}
+
+	It("should find the recommendation on par when it runs fewer replicas without more breaches", func() {
+		comparison, err := newComparator(5, 2).Compare(context.TODO(), workloadMeta, config, start, end)
+		Expect(err).NotTo(HaveOccurred())
+		// the workload needs 2 replicas of 1 core at the redline of 0.5, which the min replicas of 3 cover
+		Expect(comparison.ShadowReplicaHours).To(Equal(int64(3)))
+		Expect(comparison.ShadowBreaches).To(Equal(0))
+		Expect(comparison.HPAReplicaHours).To(Equal(int64(5)))
+		Expect(comparison.HPABreaches).To(Equal(2))
+		Expect(comparison.WindowStart.Time).To(Equal(start))
+		Expect(comparison.AtParity()).To(BeTrue())
+	})
+
+	It("should find the recommendation regressing when it runs more replicas than the HPA did", func() {
+		comparison, err := newComparator(2, 0).Compare(context.TODO(), workloadMeta, config, start, end)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comparison.HPAReplicaHours).To(Equal(int64(2)))
+		Expect(comparison.AtParity()).To(BeFalse())
+	})
+})
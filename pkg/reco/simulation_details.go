@@ -0,0 +1,127 @@
+package reco
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TargetTrial is a target utilization simulated while searching for the highest target without breaches.
+type TargetTrial struct {
+	TargetUtilization int  `json:"targetUtilization"`
+	Breached          bool `json:"breached"`
+}
+
+// SimulationCandidate is the outcome of the search for the highest target utilization for a min replicas.
+type SimulationCandidate struct {
+	MinReplicas       int           `json:"minReplicas"`
+	TargetUtilization int           `json:"targetUtilization"`
+	Qualified         bool          `json:"qualified"`
+	Savings           float64       `json:"savings"`
+	Trials            []TargetTrial `json:"trials"`
+	// Pruned is set when the min replicas wasn't simulated as it can't have more savings than the best candidate.
+	Pruned bool `json:"pruned,omitempty"`
+}
+
+// SimulationDetails captures the inputs and outputs of the last HPA simulation run for a workload.
+type SimulationDetails struct {
+	Namespace          string                `json:"namespace"`
+	Kind               string                `json:"kind"`
+	Workload           string                `json:"workload"`
+	SimulatedAt        time.Time             `json:"simulatedAt"`
+	MetricsWindowStart time.Time             `json:"metricsWindowStart"`
+	MetricsWindowEnd   time.Time             `json:"metricsWindowEnd"`
+	DataPoints         int                   `json:"dataPoints"`
+	ACL                string                `json:"acl"`
+	PerPodResources    float64               `json:"perPodResources"`
+	MaxReplicas        int                   `json:"maxReplicas"`
+	MinTarget          int                   `json:"minTarget"`
+	MaxTarget          int                   `json:"maxTarget"`
+	Candidates         []SimulationCandidate `json:"candidates"`
+	ChosenMinReplicas  int                   `json:"chosenMinReplicas"`
+	ChosenTarget       int                   `json:"chosenTarget"`
+	// ReusedPreviousOutcome is set when the search was skipped as the previous HPA configuration still doesn't breach.
+	ReusedPreviousOutcome bool   `json:"reusedPreviousOutcome,omitempty"`
+	Error                 string `json:"error,omitempty"`
+}
+
+func (d *SimulationDetails) addCandidate(candidate SimulationCandidate) {
+	if d == nil {
+		return
+	}
+	d.Candidates = append(d.Candidates, candidate)
+}
+
+// SimulationDetailsStore holds the details of the last simulation per workload and serves them over HTTP.
+type SimulationDetailsStore struct {
+	mu      sync.RWMutex
+	details map[string]SimulationDetails
+}
+
+func NewSimulationDetailsStore() *SimulationDetailsStore {
+	return &SimulationDetailsStore{
+		details: make(map[string]SimulationDetails),
+	}
+}
+
+func simulationDetailsKey(namespace, workload string) string {
+	return namespace + "/" + workload
+}
+
+func (s *SimulationDetailsStore) Put(details SimulationDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.details[simulationDetailsKey(details.Namespace, details.Workload)] = details
+}
+
+func (s *SimulationDetailsStore) Get(namespace, workload string) (SimulationDetails, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	details, ok := s.details[simulationDetailsKey(namespace, workload)]
+	return details, ok
+}
+
+// ServeHTTP responds with the simulation details of the workload identified by the namespace and workload
+// query params. Without a workload, it responds with a summary of the simulations of all the workloads,
+// optionally filtered by the namespace.
+func (s *SimulationDetailsStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	workload := r.URL.Query().Get("workload")
+
+	var response interface{}
+	if len(workload) > 0 {
+		details, ok := s.Get(namespace, workload)
+		if !ok {
+			http.Error(w, "no simulation found for the workload", http.StatusNotFound)
+			return
+		}
+		response = details
+	} else {
+		response = s.summaries(namespace)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *SimulationDetailsStore) summaries(namespace string) []SimulationDetails {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summaries := []SimulationDetails{}
+	for _, details := range s.details {
+		if len(namespace) > 0 && details.Namespace != namespace {
+			continue
+		}
+		details.Candidates = nil
+		summaries = append(summaries, details)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return simulationDetailsKey(summaries[i].Namespace, summaries[i].Workload) <
+			simulationDetailsKey(summaries[j].Namespace, summaries[j].Workload)
+	})
+	return summaries
+}
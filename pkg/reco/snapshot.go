@@ -0,0 +1,246 @@
+package reco
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ottoscalrAnnotationPrefix is the prefix of the annotations of the PolicyRecommendations carried over by the snapshots,
+// e.g. the freeze annotation.
+const ottoscalrAnnotationPrefix = "ottoscalr.io/"
+
+// PolicySnapshot is the Policies and the PolicyRecommendations of a cluster, exported to be imported in another
+// cluster so that the workloads migrated to it keep their position on the policy ladder instead of restarting from
+// the safest policy.
+type PolicySnapshot struct {
+	ExportedAt            time.Time                      `json:"exportedAt"`
+	Policies              []PolicySnapshotEntry          `json:"policies"`
+	PolicyRecommendations []PolicyRecommendationSnapshot `json:"policyRecommendations"`
+}
+
+// PolicySnapshotEntry is a Policy of a PolicySnapshot.
+type PolicySnapshotEntry struct {
+	Name string              `json:"name"`
+	Spec v1alpha1.PolicySpec `json:"spec"`
+}
+
+// PolicyRecommendationSnapshot is a PolicyRecommendation of a PolicySnapshot, with its policy, when it transitioned
+// to it, its configs and the overrides of its owners.
+type PolicyRecommendationSnapshot struct {
+	Namespace   string                            `json:"namespace"`
+	Name        string                            `json:"name"`
+	Annotations map[string]string                 `json:"annotations,omitempty"`
+	Spec        v1alpha1.PolicyRecommendationSpec `json:"spec"`
+}
+
+// PolicySnapshotImport is the outcome of importing a PolicySnapshot.
+type PolicySnapshotImport struct {
+	DryRun          bool     `json:"dryRun"`
+	CreatedPolicies []string `json:"createdPolicies,omitempty"`
+	// ConflictingPolicies are the policies which exist with another spec in the cluster. They're left as they are.
+	ConflictingPolicies []string `json:"conflictingPolicies,omitempty"`
+	// CreatedPolicyRecommendations are the policyrecos imported ahead of their workloads, which adopt them once
+	// onboarded.
+	CreatedPolicyRecommendations  []string `json:"createdPolicyRecommendations,omitempty"`
+	RestoredPolicyRecommendations []string `json:"restoredPolicyRecommendations,omitempty"`
+	// SkippedPolicyRecommendations are the policyrecos which weren't imported, along with why.
+	SkippedPolicyRecommendations map[string]string `json:"skippedPolicyRecommendations,omitempty"`
+}
+
+// PolicySnapshotter exports the policy state of a cluster and imports it in another.
+type PolicySnapshotter struct {
+	k8sClient client.Client
+	now       func() time.Time
+}
+
+func NewPolicySnapshotter(k8sClient client.Client) *PolicySnapshotter {
+	return &PolicySnapshotter{k8sClient: k8sClient, now: time.Now}
+}
+
+// Export returns the snapshot of all the Policies and of the PolicyRecommendations in the namespace, or in all the
+// namespaces if it's empty.
+func (s *PolicySnapshotter) Export(ctx context.Context, namespace string) (*PolicySnapshot, error) {
+	policies := &v1alpha1.PolicyList{}
+	if err := s.k8sClient.List(ctx, policies); err != nil {
+		return nil, err
+	}
+	policyrecos := &v1alpha1.PolicyRecommendationList{}
+	var opts []client.ListOption
+	if len(namespace) > 0 {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := s.k8sClient.List(ctx, policyrecos, opts...); err != nil {
+		return nil, err
+	}
+
+	snapshot := &PolicySnapshot{ExportedAt: s.now().UTC()}
+	for _, policy := range policies.Items {
+		snapshot.Policies = append(snapshot.Policies, PolicySnapshotEntry{Name: policy.Name, Spec: policy.Spec})
+	}
+	sort.Slice(snapshot.Policies, func(i, j int) bool {
+		return snapshot.Policies[i].Spec.RiskIndex < snapshot.Policies[j].Spec.RiskIndex
+	})
+	for _, policyreco := range policyrecos.Items {
+		spec := *policyreco.Spec.DeepCopy()
+		spec.QueuedForExecution, spec.QueuedForExecutionAt = nil, nil
+		snapshot.PolicyRecommendations = append(snapshot.PolicyRecommendations, PolicyRecommendationSnapshot{
+			Namespace:   policyreco.Namespace,
+			Name:        policyreco.Name,
+			Annotations: ottoscalrAnnotations(policyreco.Annotations),
+			Spec:        spec,
+		})
+	}
+	return snapshot, nil
+}
+
+// Import restores the PolicyRecommendations of the snapshot in the namespace, or in all the namespaces if it's empty,
+// in which case the Policies of the snapshot missing from the cluster are created as well. The policyrecos which exist
+// already, e.g. as the registrar onboarded their workloads, are moved to the policy of the snapshot and queued for a
+// fresh recommendation, while the ones missing are created to be adopted by their workloads. Nothing is changed on a
+// dry run.
+func (s *PolicySnapshotter) Import(ctx context.Context, snapshot PolicySnapshot, namespace string,
+	dryRun bool) (*PolicySnapshotImport, error) {
+	result := &PolicySnapshotImport{DryRun: dryRun, SkippedPolicyRecommendations: map[string]string{}}
+	policies := &v1alpha1.PolicyList{}
+	if err := s.k8sClient.List(ctx, policies); err != nil {
+		return nil, err
+	}
+	existingPolicies := map[string]v1alpha1.PolicySpec{}
+	hasDefault := false
+	for _, policy := range policies.Items {
+		existingPolicies[policy.Name] = policy.Spec
+		hasDefault = hasDefault || policy.Spec.IsDefault
+	}
+
+	for _, entry := range snapshot.Policies {
+		if spec, ok := existingPolicies[entry.Name]; ok {
+			if spec != entry.Spec {
+				result.ConflictingPolicies = append(result.ConflictingPolicies, entry.Name)
+			}
+			continue
+		}
+		if len(namespace) > 0 {
+			continue
+		}
+		policy := &v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: entry.Name}, Spec: entry.Spec}
+		// the cluster keeps its own default policy
+		policy.Spec.IsDefault = policy.Spec.IsDefault && !hasDefault
+		if !dryRun {
+			if err := s.k8sClient.Create(ctx, policy); err != nil {
+				return nil, fmt.Errorf("unable to create the policy %s: %v", entry.Name, err)
+			}
+		}
+		existingPolicies[entry.Name] = policy.Spec
+		result.CreatedPolicies = append(result.CreatedPolicies, entry.Name)
+	}
+
+	for _, entry := range snapshot.PolicyRecommendations {
+		if len(namespace) > 0 && entry.Namespace != namespace {
+			continue
+		}
+		key := types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}
+		if _, ok := existingPolicies[entry.Spec.Policy]; !ok {
+			result.SkippedPolicyRecommendations[key.String()] = fmt.Sprintf("the policy %q doesn't exist",
+				entry.Spec.Policy)
+			continue
+		}
+		created, err := s.restorePolicyRecommendation(ctx, entry, dryRun)
+		if err != nil {
+			result.SkippedPolicyRecommendations[key.String()] = err.Error()
+			continue
+		}
+		if created {
+			result.CreatedPolicyRecommendations = append(result.CreatedPolicyRecommendations, key.String())
+		} else {
+			result.RestoredPolicyRecommendations = append(result.RestoredPolicyRecommendations, key.String())
+		}
+	}
+	return result, nil
+}
+
+// restorePolicyRecommendation moves the policyreco to the policy of the snapshot, or creates it if it's missing, and
+// queues it for a fresh recommendation. It returns whether the policyreco was created.
+func (s *PolicySnapshotter) restorePolicyRecommendation(ctx context.Context, entry PolicyRecommendationSnapshot,
+	dryRun bool) (bool, error) {
+	now := metav1.NewTime(s.now())
+	queued := true
+	policyreco := &v1alpha1.PolicyRecommendation{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}, policyreco)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	created := apierrors.IsNotFound(err)
+	if created {
+		policyreco = &v1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Namespace: entry.Namespace, Name: entry.Name}}
+	} else if policyreco.Spec.WorkloadMeta.Kind != entry.Spec.WorkloadMeta.Kind {
+		return false, fmt.Errorf("the policyreco is of a %s rather than a %s", policyreco.Spec.WorkloadMeta.Kind,
+			entry.Spec.WorkloadMeta.Kind)
+	}
+
+	spec := *entry.Spec.DeepCopy()
+	if !created {
+		spec.WorkloadMeta = policyreco.Spec.WorkloadMeta
+	}
+	spec.QueuedForExecution, spec.QueuedForExecutionAt = &queued, &now
+	policyreco.Spec = spec
+	if policyreco.Annotations == nil {
+		policyreco.Annotations = map[string]string{}
+	}
+	for k, v := range entry.Annotations {
+		policyreco.Annotations[k] = v
+	}
+	// the import is on behalf of a user
+	policyreco.Annotations[v1alpha1.QueuePriorityAnnotation] = v1alpha1.QueuePriorityInteractive
+	if dryRun {
+		return created, nil
+	}
+	if !created {
+		return false, s.k8sClient.Update(ctx, policyreco)
+	}
+	if err := s.setWorkloadOwner(ctx, policyreco); err != nil {
+		return false, err
+	}
+	return true, s.k8sClient.Create(ctx, policyreco)
+}
+
+// setWorkloadOwner makes the workload of the policyreco its controller if the workload exists.
+func (s *PolicySnapshotter) setWorkloadOwner(ctx context.Context, policyreco *v1alpha1.PolicyRecommendation) error {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion(policyreco.Spec.WorkloadMeta.APIVersion)
+	workload.SetKind(policyreco.Spec.WorkloadMeta.Kind)
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Namespace: policyreco.Namespace,
+		Name: policyreco.Spec.WorkloadMeta.Name}, workload)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return controllerutil.SetControllerReference(workload, policyreco, s.k8sClient.Scheme())
+}
+
+func ottoscalrAnnotations(annotations map[string]string) map[string]string {
+	var carried map[string]string
+	for k, v := range annotations {
+		if !strings.HasPrefix(k, ottoscalrAnnotationPrefix) || k == v1alpha1.QueuePriorityAnnotation {
+			continue
+		}
+		if carried == nil {
+			carried = map[string]string{}
+		}
+		carried[k] = v
+	}
+	return carried
+}
@@ -0,0 +1,95 @@
+package reco
+
+import (
+	"context"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("PolicySnapshotter", func() {
+	newPolicy := func(name string, riskIndex, target int, isDefault bool) *v1alpha1.Policy {
+		return &v1alpha1.Policy{ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.PolicySpec{RiskIndex: riskIndex, MinReplicaPercentageCut: 100, TargetUtilization: target,
+				IsDefault: isDefault}}
+	}
+	newPolicyReco := func(name, policyName string, annotations map[string]string) *v1alpha1.PolicyRecommendation {
+		return &v1alpha1.PolicyRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "snapshot-ns", Annotations: annotations},
+			Spec: v1alpha1.PolicyRecommendationSpec{
+				WorkloadMeta: v1alpha1.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					Name: name},
+				Policy:                  policyName,
+				CurrentHPAConfiguration: v1alpha1.HPAConfiguration{Min: 4, Max: 20, TargetMetricValue: 60},
+			},
+		}
+	}
+	newClient := func(objects ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	}
+
+	It("should carry the policies of the workloads over to another cluster", func() {
+		source := newClient(newPolicy("safest", 1, 40, true), newPolicy("balanced", 2, 60, false),
+			newPolicyReco("checkout", "balanced", map[string]string{v1alpha1.FreezeRecommendationAnnotation: "true",
+				"example.com/team": "payments"}),
+			newPolicyReco("search", "balanced", nil))
+		snapshot, err := NewPolicySnapshotter(source).Export(context.TODO(), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.Policies).To(HaveLen(2))
+		Expect(snapshot.PolicyRecommendations).To(HaveLen(2))
+
+		// the registrar of the target cluster has onboarded checkout at its safest policy
+		onboarded := newPolicyReco("checkout", "safest", nil)
+		onboarded.Spec.CurrentHPAConfiguration = v1alpha1.HPAConfiguration{Min: 20, Max: 20, TargetMetricValue: 40}
+		target := newClient(newPolicy("safest", 1, 40, true), onboarded,
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "snapshot-ns"}})
+		result, err := NewPolicySnapshotter(target).Import(context.TODO(), *snapshot, "", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.CreatedPolicies).To(Equal([]string{"balanced"}))
+		Expect(result.RestoredPolicyRecommendations).To(Equal([]string{"snapshot-ns/checkout"}))
+		Expect(result.CreatedPolicyRecommendations).To(Equal([]string{"snapshot-ns/search"}))
+		Expect(result.SkippedPolicyRecommendations).To(BeEmpty())
+
+		checkout := &v1alpha1.PolicyRecommendation{}
+		Expect(target.Get(context.TODO(), types.NamespacedName{Namespace: "snapshot-ns", Name: "checkout"}, checkout)).To(Succeed())
+		Expect(checkout.Spec.Policy).To(Equal("balanced"))
+		Expect(checkout.Spec.CurrentHPAConfiguration.Min).To(Equal(4))
+		Expect(*checkout.Spec.QueuedForExecution).To(BeTrue())
+		Expect(checkout.Annotations).To(HaveKeyWithValue(v1alpha1.FreezeRecommendationAnnotation, "true"))
+		Expect(checkout.Annotations).To(HaveKeyWithValue(v1alpha1.QueuePriorityAnnotation, v1alpha1.QueuePriorityInteractive))
+		Expect(checkout.Annotations).NotTo(HaveKey("example.com/team"))
+
+		search := &v1alpha1.PolicyRecommendation{}
+		Expect(target.Get(context.TODO(), types.NamespacedName{Namespace: "snapshot-ns", Name: "search"}, search)).To(Succeed())
+		Expect(search.Spec.Policy).To(Equal("balanced"))
+		Expect(metav1.GetControllerOf(search).Name).To(Equal("search"))
+	})
+
+	It("should skip the workloads whose policies aren't in the cluster on a namespaced import", func() {
+		target := newClient(newPolicy("safest", 1, 40, true))
+		snapshot := PolicySnapshot{
+			Policies: []PolicySnapshotEntry{{Name: "safest", Spec: v1alpha1.PolicySpec{RiskIndex: 1, TargetUtilization: 50}},
+				{Name: "balanced", Spec: v1alpha1.PolicySpec{RiskIndex: 2, TargetUtilization: 60}}},
+			PolicyRecommendations: []PolicyRecommendationSnapshot{
+				{Namespace: "snapshot-ns", Name: "checkout", Spec: newPolicyReco("checkout", "balanced", nil).Spec},
+				{Namespace: "other-ns", Name: "search", Spec: newPolicyReco("search", "safest", nil).Spec},
+			},
+		}
+		result, err := NewPolicySnapshotter(target).Import(context.TODO(), snapshot, "snapshot-ns", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.CreatedPolicies).To(BeEmpty())
+		Expect(result.ConflictingPolicies).To(Equal([]string{"safest"}))
+		Expect(result.SkippedPolicyRecommendations).To(HaveKey("snapshot-ns/checkout"))
+		Expect(result.CreatedPolicyRecommendations).To(BeEmpty())
+	})
+})
@@ -0,0 +1,159 @@
+package reco
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	kedaapi "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StandaloneOptions configure a StandaloneEngine. The zero values take the defaults of the controller.
+type StandaloneOptions struct {
+	RecommenderParams
+
+	PrometheusURLs      []string
+	PrometheusAuth      metrics.PrometheusAuth
+	QueryTimeout        time.Duration
+	QuerySplitInterval  time.Duration
+	MetricIngestionTime float64
+	MetricProbeTime     float64
+	MetricStep          time.Duration
+
+	// EnableArgoRollouts recommends for the Rollouts along with the Deployments.
+	EnableArgoRollouts  bool
+	MinRequiredReplicas int
+	PolicyExpiryAge     time.Duration
+	Logger              logr.Logger
+}
+
+func (o *StandaloneOptions) setDefaults() {
+	if o.RedLineUtilization == 0 {
+		o.RedLineUtilization = 0.85
+	}
+	if o.MetricWindow == 0 {
+		o.MetricWindow = 28 * 24 * time.Hour
+	}
+	if o.MinTarget == 0 {
+		o.MinTarget = 10
+	}
+	if o.MaxTarget == 0 {
+		o.MaxTarget = 60
+	}
+	if o.QueryTimeout == 0 {
+		o.QueryTimeout = 30 * time.Second
+	}
+	if o.QuerySplitInterval == 0 {
+		o.QuerySplitInterval = 24 * time.Hour
+	}
+	if o.MetricStep == 0 {
+		o.MetricStep = 30 * time.Second
+	}
+	if o.PolicyExpiryAge == 0 {
+		o.PolicyExpiryAge = 48 * time.Hour
+	}
+	if o.Logger.GetSink() == nil {
+		o.Logger = logr.Discard()
+	}
+}
+
+// StandaloneEngine generates the recommendations of the workloads of a cluster without running the controllers, e.g.
+// for the batch jobs and the CLIs. It reads the cluster directly rather than through the caches of a
+// controller-runtime manager. Any number of engines can be created in a process. Their metrics are only exported once
+// registered on a registry with RegisterMetrics.
+type StandaloneEngine struct {
+	Recommender     *CpuUtilizationBasedRecommender
+	Workflow        RecommendationWorkflow
+	Scraper         metrics.Scraper
+	ClientsRegistry *registry.DeploymentClientRegistry
+	PolicyStore     policy.Store
+	K8sClient       client.Client
+}
+
+// NewStandaloneEngine returns an engine reading the cluster of the config and the metrics of the Prometheus instances
+// of the options.
+func NewStandaloneEngine(cfg *rest.Config, opts StandaloneOptions) (*StandaloneEngine, error) {
+	if len(opts.PrometheusURLs) == 0 {
+		return nil, errors.New("no prometheus urls to scrape the metrics of the workloads from")
+	}
+	opts.setDefaults()
+
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, v1alpha1.AddToScheme,
+		v1beta1.AddToScheme, kedaapi.AddToScheme, argov1alpha1.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	scraper, err := metrics.NewPrometheusScraperWithAuth(opts.PrometheusURLs, opts.PrometheusAuth, opts.QueryTimeout,
+		opts.QuerySplitInterval, opts.MetricIngestionTime, opts.MetricProbeTime, opts.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	clientsRegistryBuilder := registry.NewDeploymentClientRegistryBuilder().
+		WithK8sClient(k8sClient).
+		WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient))
+	if opts.EnableArgoRollouts {
+		clientsRegistryBuilder.WithCustomDeploymentClient(registry.NewRolloutClient(k8sClient))
+	}
+	clientsRegistry := clientsRegistryBuilder.Build()
+
+	recommender := NewCpuUtilizationBasedRecommender(k8sClient, opts.RedLineUtilization, opts.MetricWindow, scraper,
+		nil, opts.MetricStep, opts.MinTarget, opts.MaxTarget, opts.MetricsPercentageThreshold, *clientsRegistry,
+		opts.Logger)
+	policyStore := policy.NewPolicyStore(k8sClient)
+	workflow, err := NewRecommendationWorkflowBuilder().
+		WithK8sClient(k8sClient).
+		WithRecommender(recommender).
+		WithPolicyStore(policyStore).
+		WithPolicyIterator(NewDefaultPolicyIterator(k8sClient)).
+		WithPolicyIterator(NewAgingPolicyIterator(k8sClient, opts.PolicyExpiryAge).WithPolicyGroups(*clientsRegistry)).
+		WithMinRequiredReplicas(opts.MinRequiredReplicas).
+		WithConfigResolver(NewConfigResolver(k8sClient)).
+		WithClientsRegistry(clientsRegistry).
+		WithLogger(opts.Logger).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StandaloneEngine{
+		Recommender:     recommender,
+		Workflow:        workflow,
+		Scraper:         scraper,
+		ClientsRegistry: clientsRegistry,
+		PolicyStore:     policyStore,
+		K8sClient:       k8sClient,
+	}, nil
+}
+
+// Recommend returns the target recommendation of the workload, without walking it through the policies.
+func (e *StandaloneEngine) Recommend(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration,
+	*RecommendationMetadata, error) {
+	return e.Recommender.Recommend(ctx, wm)
+}
+
+// Execute walks the workload through the policies the way the controller does, which requires the
+// PolicyRecommendation of the workload, and returns the config to be applied next along with the target
+// recommendation.
+func (e *StandaloneEngine) Execute(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration,
+	*v1alpha1.HPAConfiguration, *Policy, *RecommendationMetadata, error) {
+	return e.Workflow.Execute(ctx, wm)
+}
@@ -0,0 +1,27 @@
+package reco
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("StandaloneEngine", func() {
+	It("should be created without a manager, any number of times", func() {
+		opts := StandaloneOptions{PrometheusURLs: []string{"http://localhost:9090"}, EnableArgoRollouts: true}
+		for i := 0; i < 2; i++ {
+			engine, err := NewStandaloneEngine(&rest.Config{Host: "http://localhost:6443"}, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(engine.Recommender.metricWindow).To(Equal(28 * 24 * time.Hour))
+			Expect(engine.Recommender.redLineUtil).To(Equal(0.85))
+			Expect(engine.ClientsRegistry.Clients).To(HaveLen(2))
+		}
+	})
+
+	It("should require the prometheus urls", func() {
+		_, err := NewStandaloneEngine(&rest.Config{Host: "http://localhost:6443"}, StandaloneOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+})
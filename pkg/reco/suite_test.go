@@ -76,12 +76,12 @@ type MockRecommender struct {
 	Max       int
 }
 
-func (r *MockRecommender) Recommend(ctx context.Context, wm WorkloadMeta) (*ottoscaleriov1alpha1.HPAConfiguration, error) {
+func (r *MockRecommender) Recommend(ctx context.Context, wm WorkloadMeta) (*ottoscaleriov1alpha1.HPAConfiguration, *RecommendationMetadata, error) {
 	return &ottoscaleriov1alpha1.HPAConfiguration{
 		Min:               r.Min,
 		Max:               r.Max,
 		TargetMetricValue: r.Threshold,
-	}, nil
+	}, nil, nil
 }
 
 type MockNoOpPI struct{}
@@ -0,0 +1,262 @@
+package reco
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errNotSummarized = errors.New("not supported for the workloads summarized by the agents")
+
+// WorkloadSummary holds everything the recommendation of a workload is generated from, so that it can be generated
+// away from the cluster of the workload, e.g. by the central recommender of a fleet.
+type WorkloadSummary struct {
+	MetricsWindowStart time.Time
+	MetricsWindowEnd   time.Time
+	MetricStep         time.Duration
+	DataPoints         []metrics.DataPoint
+	ACL                time.Duration
+	PerPodResources    float64
+	MaxReplicas        int
+	// MaxReplicasFromCurrent is true if the max replicas fell back to the current replicas of the workload.
+	MaxReplicasFromCurrent bool
+	// Labels, Annotations and CreatedAt are the metadata of the workload the per workload features read, e.g. the
+	// downtime windows or the redline tier.
+	Labels      map[string]string
+	Annotations map[string]string
+	CreatedAt   time.Time
+	// BreachAssertionViolations and Pods are the timestamps the breach assertion of the workload held at and its pods
+	// over the metrics window, if it has one.
+	BreachAssertionViolations []time.Time
+	Pods                      []metrics.DataPoint
+	// Config is the OttoscalrConfig of the namespace of the workload.
+	Config *WorkloadConfig
+}
+
+// SummarizeWorkload collects the metrics of the metric window of the workload along with the ACL, the per pod
+// resources, the max replicas, the metadata of the workload and the evaluation of its breach assertion.
+func (c *CpuUtilizationBasedRecommender) SummarizeWorkload(ctx context.Context, workloadMeta WorkloadMeta) (*WorkloadSummary, error) {
+	c = c.reloaded()
+	config := WorkloadConfigFromContext(ctx)
+	metricWindow := c.metricWindow
+	if config != nil && config.MetricWindow > 0 {
+		metricWindow = config.MetricWindow
+	}
+	metricWindow, err := c.workloadMetricWindow(workloadMeta, metricWindow)
+	if err != nil {
+		return nil, err
+	}
+	end := time.Now()
+	start := c.metricsWindowStart(end, metricWindow)
+	c = c.withMetricStep(c.metricStepFor(end.Sub(start)))
+
+	objectClient, err := c.clientsRegistry.GetObjectClient(workloadMeta.Kind)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := objectClient.GetObject(workloadMeta.Namespace, workloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	dataPoints, err := c.scraper.GetAverageCPUUtilizationByWorkload(workloadMeta.Namespace, workloadMeta.Name, start, end,
+		c.metricStep)
+	if err != nil {
+		return nil, err
+	}
+	maxReplicas, maxReplicasFromCurrent, err := getMaxPodsWithFallback(c.k8sClient, c.clientsRegistry,
+		workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	acl, _, err := c.workloadACL(workloadMeta)
+	if err != nil {
+		return nil, err
+	}
+	perPodResources, err := c.getContainerCPULimitsSum(workloadMeta.Namespace, workloadMeta.Kind, workloadMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	summary := &WorkloadSummary{
+		MetricsWindowStart:     start,
+		MetricsWindowEnd:       end,
+		MetricStep:             c.metricStep,
+		DataPoints:             dataPoints,
+		ACL:                    acl,
+		PerPodResources:        perPodResources,
+		MaxReplicas:            maxReplicas,
+		MaxReplicasFromCurrent: maxReplicasFromCurrent,
+		Labels:                 workload.GetLabels(),
+		Annotations:            workload.GetAnnotations(),
+		CreatedAt:              workload.GetCreationTimestamp().Time,
+		Config:                 config,
+	}
+
+	if c.breachAssertionScraper != nil {
+		assertion, err := c.workloadBreachAssertion(workloadMeta)
+		if err != nil {
+			return nil, err
+		}
+		if len(assertion) > 0 {
+			summary.BreachAssertionViolations, err = c.breachAssertionScraper.GetBreachAssertionTimestamps(assertion,
+				start, end, c.metricStep)
+			if err != nil {
+				return nil, err
+			}
+			summary.Pods, err = c.breachAssertionScraper.GetPodCountByWorkload(workloadMeta.Namespace,
+				workloadMeta.Name, start, end, c.metricStep)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return summary, nil
+}
+
+// RecommendForSummary generates the recommendation of a workload summarized by the agent of its cluster for the
+// metrics window of the summary, the way the recommender of its cluster would, without recording the simulation
+// details. The per workload features read the workload from the summary.
+func (c *CpuUtilizationBasedRecommender) RecommendForSummary(ctx context.Context, workloadMeta WorkloadMeta,
+	summary *WorkloadSummary) (*v1alpha1.HPAConfiguration, *RecommendationMetadata, error) {
+	summarized := *c.reloaded()
+	summarized.summary = summary
+	summarized.scraper = &summaryScraper{summary: summary}
+	summarized.clientsRegistry = *registry.NewDeploymentClientRegistryBuilder().
+		WithCustomDeploymentClient(&summaryObjectClient{kind: workloadMeta.Kind, workloadMeta: workloadMeta,
+			summary: summary}).Build()
+	// the ACL was aggregated by the strategy of the agent already
+	summarized.aclScraper = nil
+	if summarized.breachAssertionScraper != nil {
+		summarized.breachAssertionScraper = &summaryScraper{summary: summary}
+	}
+	// the datapoints are summarized at the step of the agent
+	if summary.MetricStep > 0 {
+		summarized.metricStep = summary.MetricStep
+	}
+	summarized.minMetricStep, summarized.multiResolution = 0, nil
+	if summary.Config != nil {
+		ctx = ContextWithWorkloadConfig(ctx, summary.Config)
+	}
+	return summarized.recommend(ctx, workloadMeta, summary.MetricsWindowEnd.Sub(summary.MetricsWindowStart),
+		summary.MetricsWindowEnd, false)
+}
+
+// UnsummarizedFeatures returns the features of the recommender which read the metrics or the objects of the cluster
+// of a workload beyond its summary, which RecommendForSummary can't serve.
+func (c *CpuUtilizationBasedRecommender) UnsummarizedFeatures() []string {
+	var features []string
+	if c.networkScraper != nil {
+		features = append(features, "networkCeiling")
+	}
+	if c.containerScraper != nil {
+		features = append(features, "containerUtilization")
+	}
+	if c.podResourcesScraper != nil {
+		features = append(features, "podResizeNormalization")
+	}
+	if c.kedaTimings {
+		features = append(features, "kedaTimings")
+	}
+	return features
+}
+
+// maxPodsWithFallback returns the max replicas of the workload like getMaxPodsWithFallback, or the ones of the summary
+// it's recommended from.
+func (c *CpuUtilizationBasedRecommender) maxPodsWithFallback(workloadMeta WorkloadMeta) (int, bool, error) {
+	if c.summary != nil {
+		return c.summary.MaxReplicas, c.summary.MaxReplicasFromCurrent, nil
+	}
+	return getMaxPodsWithFallback(c.k8sClient, c.clientsRegistry, workloadMeta.Namespace, workloadMeta.Kind,
+		workloadMeta.Name)
+}
+
+// summaryScraper serves the metrics of a workload from its summary.
+type summaryScraper struct {
+	summary *WorkloadSummary
+}
+
+func (s *summaryScraper) GetAverageCPUUtilizationByWorkload(namespace, workload string, start time.Time, end time.Time,
+	step time.Duration) ([]metrics.DataPoint, error) {
+	return s.summary.DataPoints, nil
+}
+
+func (s *summaryScraper) GetCPUUtilizationBreachDataPoints(namespace, workloadType, workload string,
+	redLineUtilization float64, start time.Time, end time.Time, step time.Duration) ([]metrics.DataPoint, error) {
+	return nil, errNotSummarized
+}
+
+func (s *summaryScraper) GetACLByWorkload(namespace, workload string) (time.Duration, error) {
+	return s.summary.ACL, nil
+}
+
+func (s *summaryScraper) GetBreachAssertionTimestamps(assertion string, start, end time.Time,
+	step time.Duration) ([]time.Time, error) {
+	return s.summary.BreachAssertionViolations, nil
+}
+
+func (s *summaryScraper) GetPodCountByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]metrics.DataPoint, error) {
+	return s.summary.Pods, nil
+}
+
+// summaryObjectClient serves the metadata, the max replicas and the per pod resources of a workload from its summary.
+type summaryObjectClient struct {
+	kind         string
+	workloadMeta WorkloadMeta
+	summary      *WorkloadSummary
+}
+
+func (sc *summaryObjectClient) GetObject(namespace string, name string) (client.Object, error) {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: sc.workloadMeta.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			Labels:            sc.summary.Labels,
+			Annotations:       sc.summary.Annotations,
+			CreationTimestamp: metav1.NewTime(sc.summary.CreatedAt),
+		},
+	}, nil
+}
+
+func (sc *summaryObjectClient) GetObjectList(namespace string, selector labels.Selector) ([]client.Object, error) {
+	return nil, errNotSummarized
+}
+
+func (sc *summaryObjectClient) GetObjectType() client.Object {
+	return nil
+}
+
+func (sc *summaryObjectClient) GetKind() string {
+	return sc.kind
+}
+
+func (sc *summaryObjectClient) GetMaxReplicaFromAnnotation(namespace string, name string) (int, error) {
+	return sc.summary.MaxReplicas, nil
+}
+
+func (sc *summaryObjectClient) GetContainerResourceLimits(namespace string, name string) (float64, error) {
+	return sc.summary.PerPodResources, nil
+}
+
+func (sc *summaryObjectClient) GetReplicaCount(namespace string, name string) (int, error) {
+	return sc.summary.MaxReplicas, nil
+}
+
+func (sc *summaryObjectClient) GetPodTemplateLabels(namespace string, name string) (map[string]string, error) {
+	return nil, errNotSummarized
+}
+
+func (sc *summaryObjectClient) GetInactiveReason(namespace string, name string) (string, error) {
+	return "", nil
+}
+
+func (sc *summaryObjectClient) Scale(namespace string, name string, replicas int32) error {
+	return errNotSummarized
+}
@@ -0,0 +1,75 @@
+package reco
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RecommendForSummary", func() {
+	var (
+		summaryRecommender *CpuUtilizationBasedRecommender
+		summary            *WorkloadSummary
+		wm                 = WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "checkout", Namespace: "payments"}
+		end                = time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		summaryRecommender = NewCpuUtilizationBasedRecommender(nil, 0.8, 7*24*time.Hour, nil, nil, time.Minute, 10, 60,
+			25, registry.DeploymentClientRegistry{}, logr.Discard()).
+			WithMinWorkloadAge(7 * 24 * time.Hour).
+			WithRedLineTiers(RedLineTiers{Key: "tier", RedLines: map[string]float64{"batch": 0.9}})
+		summary = &WorkloadSummary{
+			MetricsWindowStart: end.Add(-24 * time.Hour),
+			MetricsWindowEnd:   end,
+			MetricStep:         5 * time.Minute,
+			ACL:                5 * time.Minute,
+			PerPodResources:    1,
+			MaxReplicas:        20,
+			Labels:             map[string]string{"tier": "batch"},
+			Annotations:        map[string]string{DowntimeWindowsAnnotation: "* 01:00-02:00"},
+			CreatedAt:          end.Add(-30 * 24 * time.Hour),
+		}
+		for at := summary.MetricsWindowStart; at.Before(end); at = at.Add(5 * time.Minute) {
+			if at.Hour() != 1 {
+				summary.DataPoints = append(summary.DataPoints, metrics.DataPoint{Timestamp: at, Value: 6})
+			}
+		}
+	})
+
+	It("should recommend for the window and the workload of the summary", func() {
+		recommendation, recoMetadata, err := summaryRecommender.RecommendForSummary(context.TODO(), wm, summary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.Max).To(Equal(20))
+		Expect(recoMetadata.MetricsWindowStart).To(Equal(summary.MetricsWindowStart))
+		Expect(recoMetadata.MetricsWindowEnd).To(Equal(end))
+		Expect(recoMetadata.MetricStep).To(Equal(5 * time.Minute))
+		Expect(recoMetadata.RedLineTier).To(Equal("batch"))
+		Expect(recoMetadata.RedLineUtilization).To(Equal(0.9))
+		Expect(recoMetadata.ExcludedDowntime).To(Equal(time.Hour))
+		Expect(recoMetadata.DataPointsCoveragePercent).To(Equal(100))
+	})
+
+	It("should recommend the no op policy for the workloads summarized as too young", func() {
+		summary.CreatedAt = end.Add(-24 * time.Hour)
+		recommendation, recoMetadata, err := summaryRecommender.RecommendForSummary(context.TODO(), wm, summary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recoMetadata.InsufficientHistory).To(BeTrue())
+		Expect(recoMetadata.WorkloadAge).To(Equal(24 * time.Hour))
+		Expect(recommendation.Max).To(Equal(20))
+	})
+
+	It("should take the config of the namespace from the summary", func() {
+		summaryRecommender.redLineTiers = nil
+		summary.Config = &WorkloadConfig{Name: "payments", RedLineUtilization: 0.7}
+		_, recoMetadata, err := summaryRecommender.RecommendForSummary(context.TODO(), wm, summary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recoMetadata.RedLineUtilization).To(Equal(0.7))
+	})
+})
@@ -0,0 +1,31 @@
+package reco
+
+import "math"
+
+// DefaultHPATolerance is the tolerance of the HPA controller, which doesn't scale a workload while the ratio of its
+// utilization to the target is within 10% of 1.
+const DefaultHPATolerance = 0.1
+
+// WithHPATolerance makes the HPA simulations model the tolerance of the HPA controller of the clusters, e.g. the
+// --horizontal-pod-autoscaler-tolerance of their kube-controller-manager, in place of DefaultHPATolerance.
+func (c *CpuUtilizationBasedRecommender) WithHPATolerance(tolerance float64) *CpuUtilizationBasedRecommender {
+	c.hpaTolerance = &tolerance
+	return c
+}
+
+func (c *CpuUtilizationBasedRecommender) tolerance() float64 {
+	if c.hpaTolerance == nil {
+		return DefaultHPATolerance
+	}
+	return *c.hpaTolerance
+}
+
+// hpaReplicas returns the replicas the HPA scales the current replicas to for the utilization. Like the HPA, it keeps
+// the current replicas while the ratio of the utilization to the target is within the tolerance.
+func hpaReplicas(utilization, currentReplicas float64, targetUtilization int, perPodResources, tolerance float64) float64 {
+	desiredReplicas := (100 * utilization) / float64(targetUtilization) / perPodResources
+	if currentReplicas > 0 && math.Abs(desiredReplicas/currentReplicas-1) <= tolerance {
+		return currentReplicas
+	}
+	return math.Ceil(desiredReplicas)
+}
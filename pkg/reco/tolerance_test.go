@@ -0,0 +1,40 @@
+package reco
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HPA tolerance", func() {
+	It("should keep the replicas while the utilization is within the tolerance of the target", func() {
+		// 10 replicas of 1 cpu at a target of 50 are at 5 cpus
+		Expect(hpaReplicas(5.4, 10, 50, 1, 0.1)).To(Equal(10.0))
+		Expect(hpaReplicas(4.6, 10, 50, 1, 0.1)).To(Equal(10.0))
+		Expect(hpaReplicas(5.6, 10, 50, 1, 0.1)).To(Equal(12.0))
+		Expect(hpaReplicas(4.4, 10, 50, 1, 0.1)).To(Equal(9.0))
+		Expect(hpaReplicas(5.4, 10, 50, 1, 0)).To(Equal(11.0))
+	})
+
+	It("should simulate the HPA with the tolerance of the clusters", func() {
+		t := time.Now()
+		dataPoints := []metrics.DataPoint{
+			{Timestamp: t, Value: 10},
+			{Timestamp: t.Add(time.Minute), Value: 10.4},
+			{Timestamp: t.Add(2 * time.Minute), Value: 10.4},
+		}
+		recommender := &CpuUtilizationBasedRecommender{redLineUtil: 1, logger: logr.Discard()}
+
+		simulated, _, err := recommender.simulateHPA(dataPoints, 0, 50, 1, 100, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(simulated[2].Value).To(Equal(19.0))
+
+		simulated, _, err = recommender.WithHPATolerance(0).simulateHPA(dataPoints, 0, 50, 1, 100, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(simulated[0].Value).To(Equal(20.0))
+		Expect(simulated[2].Value).To(Equal(21.0))
+	})
+})
@@ -0,0 +1,59 @@
+package reco
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+// TransformerAudit is what a metrics transformer changed of the datapoints a recommendation was generated from, so
+// that it can be told why a recommendation ignores a spike without going through the logs of the controller.
+type TransformerAudit struct {
+	Transformer        string `json:"transformer"`
+	RemovedDataPoints  int    `json:"removedDataPoints"`
+	ModifiedDataPoints int    `json:"modifiedDataPoints"`
+	// ExcludedIntervals are the intervals of the metrics window the transformer excluded, if it reports them.
+	ExcludedIntervals []metrics.ExcludedInterval `json:"excludedIntervals,omitempty"`
+}
+
+// auditedTransform transforms the datapoints with the transformer and returns the transformed datapoints along with
+// its audit. The datapoints are compared to a copy of them taken beforehand as the transformers modify them in place.
+func auditedTransform(transformer metrics.MetricsTransformer, start, end time.Time,
+	dataPoints []metrics.DataPoint) ([]metrics.DataPoint, TransformerAudit, error) {
+	audit := TransformerAudit{Transformer: fmt.Sprintf("%T", transformer)}
+	original := make([]metrics.DataPoint, len(dataPoints))
+	copy(original, dataPoints)
+
+	var transformed []metrics.DataPoint
+	var err error
+	if excluding, ok := transformer.(metrics.IntervalExcludingTransformer); ok {
+		transformed, audit.ExcludedIntervals, err = excluding.TransformExcluding(start, end, dataPoints)
+	} else {
+		transformed, err = transformer.Transform(start, end, dataPoints)
+	}
+	if err != nil {
+		return nil, audit, err
+	}
+	audit.RemovedDataPoints, audit.ModifiedDataPoints = diffDataPoints(original, transformed)
+	return transformed, audit, nil
+}
+
+// diffDataPoints returns how many of the original datapoints are missing from the transformed ones and how many have
+// another value there. Both are in the order of their timestamps.
+func diffDataPoints(original, transformed []metrics.DataPoint) (removed, modified int) {
+	j := 0
+	for _, dp := range original {
+		for j < len(transformed) && transformed[j].Timestamp.Before(dp.Timestamp) {
+			j++
+		}
+		if j == len(transformed) || !transformed[j].Timestamp.Equal(dp.Timestamp) {
+			removed++
+			continue
+		}
+		if transformed[j].Value != dp.Value {
+			modified++
+		}
+	}
+	return removed, modified
+}
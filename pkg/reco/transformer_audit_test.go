@@ -0,0 +1,55 @@
+package reco
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type dropFirstTransformer struct{}
+
+func (dropFirstTransformer) Transform(_, _ time.Time, dataPoints []metrics.DataPoint) ([]metrics.DataPoint, error) {
+	dataPoints[2].Value = 0
+	return dataPoints[1:], nil
+}
+
+type excludingTransformer struct {
+	dropFirstTransformer
+	interval metrics.ExcludedInterval
+}
+
+func (t excludingTransformer) TransformExcluding(start, end time.Time,
+	dataPoints []metrics.DataPoint) ([]metrics.DataPoint, []metrics.ExcludedInterval, error) {
+	transformed, err := t.Transform(start, end, dataPoints)
+	return transformed, []metrics.ExcludedInterval{t.interval}, err
+}
+
+var _ = Describe("Transformer audits", func() {
+	start := time.Date(2023, 10, 24, 0, 0, 0, 0, time.UTC)
+	newDataPoints := func() []metrics.DataPoint {
+		var dataPoints []metrics.DataPoint
+		for i := 0; i < 5; i++ {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: 10})
+		}
+		return dataPoints
+	}
+
+	It("should count the datapoints the transformers removed and modified in place", func() {
+		transformed, audit, err := auditedTransform(dropFirstTransformer{}, start, start.Add(5*time.Minute), newDataPoints())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(HaveLen(4))
+		Expect(audit).To(Equal(TransformerAudit{Transformer: fmt.Sprintf("%T", dropFirstTransformer{}), RemovedDataPoints: 1,
+			ModifiedDataPoints: 1}))
+	})
+
+	It("should record the intervals the transformers excluded", func() {
+		diwali := metrics.ExcludedInterval{Start: start, End: start.Add(time.Minute), Reason: "Diwali sale"}
+		_, audit, err := auditedTransform(excludingTransformer{interval: diwali}, start, start.Add(5*time.Minute), newDataPoints())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audit.RemovedDataPoints).To(Equal(1))
+		Expect(audit.ExcludedIntervals).To(Equal([]metrics.ExcludedInterval{diwali}))
+	})
+})
@@ -0,0 +1,161 @@
+package reco
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	workerPoolActiveWorkers = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "reco_workflow_pool_active_workers",
+			Help: "Number of recommendation workflow executions in progress"},
+	)
+
+	workerPoolQueueLength = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "reco_workflow_pool_queue_length",
+			Help: "Number of recommendation workflow executions waiting for a worker"},
+	)
+
+	workerPoolQueueWaitTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{Name: "reco_workflow_pool_queue_wait_seconds",
+			Help:    "Time recommendation workflow executions waited for a worker in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12)}, []string{"namespace"},
+	)
+
+	workerPoolRejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "reco_workflow_pool_rejected_count",
+			Help: "Number of recommendation workflow executions rejected as the queue was full"}, []string{"namespace"},
+	)
+)
+
+func init() {
+	registerCollectors(workerPoolActiveWorkers, workerPoolQueueLength, workerPoolQueueWaitTime,
+		workerPoolRejectedCounter)
+}
+
+// ErrWorkerPoolQueueFull is returned when a workflow execution can't be queued as the queue of the worker pool is full.
+var ErrWorkerPoolQueueFull = errors.New("recommendation workflow queue is full")
+
+// WorkerPool bounds the number of concurrent workflow executions so that a surge of reconciles can't exhaust the
+// memory or saturate the metrics backend. Executions beyond the workers wait in a bounded queue and are handed the
+// freed up workers round robin across the namespaces, so that a namespace with many workloads can't starve the others.
+type WorkerPool struct {
+	workers        int
+	maxQueueLength int
+
+	mu         sync.Mutex
+	active     int
+	queued     int
+	waiters    map[string][]chan struct{}
+	namespaces []string
+}
+
+// NewWorkerPool returns a pool of workers with a queue of up to maxQueueLength executions. A non positive
+// maxQueueLength doesn't bound the queue.
+func NewWorkerPool(workers int, maxQueueLength int) *WorkerPool {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &WorkerPool{
+		workers:        workers,
+		maxQueueLength: maxQueueLength,
+		waiters:        make(map[string][]chan struct{}),
+	}
+}
+
+// Acquire blocks until a worker is available for an execution for the namespace, the context is done or returns
+// ErrWorkerPoolQueueFull right away if the queue is full. Every successful Acquire must be followed by a Release.
+func (p *WorkerPool) Acquire(ctx context.Context, namespace string) error {
+	p.mu.Lock()
+	if p.active < p.workers && p.queued == 0 {
+		p.active++
+		p.mu.Unlock()
+		workerPoolActiveWorkers.Inc()
+		return nil
+	}
+	if p.maxQueueLength > 0 && p.queued >= p.maxQueueLength {
+		p.mu.Unlock()
+		workerPoolRejectedCounter.WithLabelValues(namespace).Inc()
+		return ErrWorkerPoolQueueFull
+	}
+	ready := make(chan struct{})
+	if _, ok := p.waiters[namespace]; !ok {
+		p.namespaces = append(p.namespaces, namespace)
+	}
+	p.waiters[namespace] = append(p.waiters[namespace], ready)
+	p.queued++
+	p.mu.Unlock()
+	workerPoolQueueLength.Inc()
+
+	queuedAt := time.Now()
+	select {
+	case <-ready:
+		workerPoolQueueWaitTime.WithLabelValues(namespace).Observe(time.Since(queuedAt).Seconds())
+		return nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		if p.removeWaiter(namespace, ready) {
+			p.mu.Unlock()
+			workerPoolQueueLength.Dec()
+			return ctx.Err()
+		}
+		p.mu.Unlock()
+		// the worker was handed over concurrently, pass it on
+		p.Release()
+		return ctx.Err()
+	}
+}
+
+// Release frees up the worker of an execution, handing it over to the next waiting execution if any.
+func (p *WorkerPool) Release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queued == 0 {
+		p.active--
+		workerPoolActiveWorkers.Dec()
+		return
+	}
+
+	namespace := p.namespaces[0]
+	p.namespaces = p.namespaces[1:]
+	ready := p.waiters[namespace][0]
+	if len(p.waiters[namespace]) == 1 {
+		delete(p.waiters, namespace)
+	} else {
+		p.waiters[namespace] = p.waiters[namespace][1:]
+		p.namespaces = append(p.namespaces, namespace)
+	}
+	p.queued--
+	workerPoolQueueLength.Dec()
+	close(ready)
+}
+
+// removeWaiter removes the waiter of the namespace from the queue and reports whether it was still queued.
+// It must be called with the lock held.
+func (p *WorkerPool) removeWaiter(namespace string, ready chan struct{}) bool {
+	waiters := p.waiters[namespace]
+	for i, waiter := range waiters {
+		if waiter != ready {
+			continue
+		}
+		waiters = append(waiters[:i], waiters[i+1:]...)
+		if len(waiters) == 0 {
+			delete(p.waiters, namespace)
+			for j, ns := range p.namespaces {
+				if ns == namespace {
+					p.namespaces = append(p.namespaces[:j], p.namespaces[j+1:]...)
+					break
+				}
+			}
+		} else {
+			p.waiters[namespace] = waiters
+		}
+		p.queued--
+		return true
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package reco
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func queuedExecutions(pool *WorkerPool) int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	return pool.queued
+}
+
+var _ = Describe("WorkerPool", func() {
+	It("should reject the executions beyond the queue length", func() {
+		pool := NewWorkerPool(1, 1)
+		Expect(pool.Acquire(context.TODO(), "ns1")).To(Succeed())
+
+		acquired := make(chan error)
+		go func() {
+			acquired <- pool.Acquire(context.TODO(), "ns1")
+		}()
+		Eventually(func() int { return queuedExecutions(pool) }).Should(Equal(1))
+
+		Expect(pool.Acquire(context.TODO(), "ns2")).To(MatchError(ErrWorkerPoolQueueFull))
+
+		pool.Release()
+		Eventually(acquired).Should(Receive(BeNil()))
+		pool.Release()
+		Expect(pool.active).To(Equal(0))
+	})
+
+	It("should hand over the workers round robin across the namespaces", func() {
+		pool := NewWorkerPool(1, 0)
+		Expect(pool.Acquire(context.TODO(), "busy")).To(Succeed())
+
+		var mu sync.Mutex
+		var order []string
+		var wg sync.WaitGroup
+		queue := func(namespace string) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				Expect(pool.Acquire(context.TODO(), namespace)).To(Succeed())
+				mu.Lock()
+				order = append(order, namespace)
+				mu.Unlock()
+				pool.Release()
+			}()
+			queued := queuedExecutions(pool)
+			Eventually(func() int { return queuedExecutions(pool) }).Should(BeNumerically(">", queued))
+		}
+		queue("busy")
+		queue("busy")
+		queue("busy")
+		queue("quiet")
+
+		pool.Release()
+		wg.Wait()
+		Expect(order).To(Equal([]string{"busy", "quiet", "busy", "busy"}))
+	})
+
+	It("should stop waiting when the context is done", func() {
+		pool := NewWorkerPool(1, 0)
+		Expect(pool.Acquire(context.TODO(), "ns1")).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+		defer cancel()
+		Expect(pool.Acquire(ctx, "ns1")).To(MatchError(context.DeadlineExceeded))
+		Expect(pool.queued).To(Equal(0))
+		Expect(pool.namespaces).To(BeEmpty())
+
+		pool.Release()
+		Expect(pool.active).To(Equal(0))
+	})
+})
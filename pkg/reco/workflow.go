@@ -6,9 +6,12 @@ import (
 	"fmt"
 	v1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
 	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/tracing"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	golog "log"
@@ -16,13 +19,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"strconv"
+	"sync"
 	"time"
 )
 
 var (
-	getRecoGenerationLatency = promauto.NewHistogramVec(
+	getRecoGenerationLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "get_reco_generation_latency_seconds",
 			Help:    "Time to generate recommendation in seconds",
@@ -32,15 +35,66 @@ var (
 )
 
 func init() {
-	p8smetrics.Registry.MustRegister(getRecoGenerationLatency)
+	registerCollectors(getRecoGenerationLatency)
 }
 
 type RecommendationWorkflow interface {
-	Execute(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration, *v1alpha1.HPAConfiguration, *Policy, error)
+	Execute(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration, *v1alpha1.HPAConfiguration, *Policy, *RecommendationMetadata, error)
 }
 
 type Recommender interface {
-	Recommend(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration, error)
+	Recommend(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration, *RecommendationMetadata, error)
+}
+
+// RecommendationMetadata describes the data a recommendation was generated from. Recommenders which can't
+// describe it return nil.
+type RecommendationMetadata struct {
+	MetricsWindowStart time.Time
+	MetricsWindowEnd   time.Time
+	// MetricStep is the step of the datapoints the recommendation was generated from.
+	MetricStep                time.Duration
+	DataPointsCoveragePercent int
+	ProjectedSavingsPercent   int
+	TransformersApplied       []string
+	// TransformerAudits are what every transformer applied changed of the datapoints, in the order they were applied.
+	TransformerAudits []TransformerAudit
+	// CronTriggers pre-scale the workload ahead of its recurring daily peaks.
+	CronTriggers []v1alpha1.CronTrigger
+	// IdleWindow lowers the min replicas of the workload during its recurring daily window of low traffic.
+	IdleWindow *v1alpha1.IdleWindow
+	// InsufficientHistory is set for the workloads younger than the min workload age of the recommender, which are
+	// recommended the no-op configuration.
+	InsufficientHistory bool
+	// WorkloadAge is how old the workload was when the recommendation was generated, if the recommender checked it.
+	WorkloadAge time.Duration
+	// Confidence is how far the recommendation can be relied upon, if the recommender can tell it.
+	Confidence *RecommendationConfidence
+	// NoOp is set when the recommender couldn't recommend a config for the workload and recommended the no-op
+	// configuration instead.
+	NoOp *NoOpRecommendation
+	// Resize is set for the workloads whose pods need right-sizing rather than autoscaling.
+	Resize *ResizeSignal
+	// PodResize is set when the pods of the workload were resized within the metrics window.
+	PodResize *PodResize
+	// RedLineUtilization is the redline utilization the workload was simulated on, which is the one of its RedLineTier
+	// if it's in one.
+	RedLineUtilization float64
+	RedLineTier        string
+	// QuotaConstraint is set by the workflow for the workloads whose max replicas was clamped to the ResourceQuotas of
+	// their namespace.
+	QuotaConstraint *QuotaConstraint
+	// ExcludedDowntime is how much of the metrics window was left out of the coverage of the datapoints as the known
+	// downtime of the workload.
+	ExcludedDowntime time.Duration
+	// BreachFreeMaxReplicas is the smallest max replicas which would have avoided the breaches over the metrics window
+	// at the recommended min replicas and target, if the recommender analyses it.
+	BreachFreeMaxReplicas int
+	// DerivedMaxReplicas is the max replicas derived from the peak demand of the workload, if it had no max pods
+	// annotation and the recommender derives them.
+	DerivedMaxReplicas int
+	// SearchSpace is the highest target without breaches and its savings at every min replicas the search found one
+	// for, if the recommender records it.
+	SearchSpace []v1alpha1.SearchSpacePoint
 }
 
 type RecommendationWorkflowImpl struct {
@@ -50,6 +104,24 @@ type RecommendationWorkflowImpl struct {
 	policyStore         policy.Store
 	logger              logr.Logger
 	minRequiredReplicas int
+	explanations        *explanationStore
+	workerPool          *WorkerPool
+	pdbResolver         *PodDisruptionBudgetResolver
+	clientsRegistry     *registry.DeploymentClientRegistry
+
+	quotaResolver  *ResourceQuotaResolver
+	configResolver *ConfigResolver
+	reloadLock     sync.RWMutex
+}
+
+// WorkloadInactiveError is returned by the workflow for the workloads which are scaled to zero or paused. Their
+// recommendations are skipped until they are active again.
+type WorkloadInactiveError struct {
+	Reason string
+}
+
+func (e *WorkloadInactiveError) Error() string {
+	return fmt.Sprintf("the workload is inactive: %s", e.Reason)
 }
 
 type WorkloadMeta struct {
@@ -102,6 +174,39 @@ func (b *RecoWorkflowBuilder) WithK8sClient(k8sClient client.Client) *RecoWorkfl
 	return b
 }
 
+// WithWorkerPool bounds the concurrent executions of the workflow with the worker pool.
+func (b *RecoWorkflowBuilder) WithWorkerPool(workerPool *WorkerPool) *RecoWorkflowBuilder {
+	b.workerPool = workerPool
+	return b
+}
+
+// WithPodDisruptionBudgetResolver keeps the recommended min replicas from violating the PodDisruptionBudgets of the
+// workloads.
+func (b *RecoWorkflowBuilder) WithPodDisruptionBudgetResolver(pdbResolver *PodDisruptionBudgetResolver) *RecoWorkflowBuilder {
+	b.pdbResolver = pdbResolver
+	return b
+}
+
+// WithResourceQuotaResolver keeps the recommended max replicas within the room the ResourceQuotas of the namespaces
+// leave the workloads to scale up into.
+func (b *RecoWorkflowBuilder) WithResourceQuotaResolver(quotaResolver *ResourceQuotaResolver) *RecoWorkflowBuilder {
+	b.quotaResolver = quotaResolver
+	return b
+}
+
+// WithConfigResolver makes the workflow generate the recommendations of the workloads with the OttoscalrConfigs of
+// their namespaces.
+func (b *RecoWorkflowBuilder) WithConfigResolver(configResolver *ConfigResolver) *RecoWorkflowBuilder {
+	b.configResolver = configResolver
+	return b
+}
+
+// WithClientsRegistry skips the recommendations of the workloads which are inactive.
+func (b *RecoWorkflowBuilder) WithClientsRegistry(clientsRegistry *registry.DeploymentClientRegistry) *RecoWorkflowBuilder {
+	b.clientsRegistry = clientsRegistry
+	return b
+}
+
 func (b *RecoWorkflowBuilder) Build() (RecommendationWorkflow, error) {
 	var zeroValLogger logr.Logger
 	if b.logger == zeroValLogger {
@@ -123,6 +228,12 @@ func (b *RecoWorkflowBuilder) Build() (RecommendationWorkflow, error) {
 		logger:              b.logger,
 		minRequiredReplicas: b.minRequiredReplicas,
 		policyStore:         b.policyStore,
+		explanations:        newExplanationStore(),
+		workerPool:          b.workerPool,
+		pdbResolver:         b.pdbResolver,
+		clientsRegistry:     b.clientsRegistry,
+		quotaResolver:       b.quotaResolver,
+		configResolver:      b.configResolver,
 	}, nil
 }
 
@@ -130,32 +241,107 @@ func NewRecommendationWorkflowBuilder() *RecoWorkflowBuilder {
 	return &RecoWorkflowBuilder{}
 }
 
-func (rw *RecommendationWorkflowImpl) Execute(ctx context.Context, wm WorkloadMeta) (*v1alpha1.HPAConfiguration, *v1alpha1.HPAConfiguration, *Policy, error) {
+func (rw *RecommendationWorkflowImpl) Execute(ctx context.Context, wm WorkloadMeta) (nextConfig *v1alpha1.HPAConfiguration, targetRecoConfig *v1alpha1.HPAConfiguration, policyToApply *Policy, recoMetadata *RecommendationMetadata, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "RecommendationWorkflow.Execute",
+		trace.WithAttributes(tracing.WorkloadAttributes(wm.Namespace, wm.Kind, wm.Name)...))
+	explanation := Explanation{
+		Namespace:   wm.Namespace,
+		Kind:        wm.Kind,
+		Workload:    wm.Name,
+		GeneratedAt: time.Now(),
+	}
+	iteratorPolicies := make(map[string]*Policy)
+	var nextPolicy *Policy
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+		rw.recordExplanation(explanation, iteratorPolicies, nextPolicy, recoMetadata, targetRecoConfig, nextConfig, policyToApply, err)
+	}()
 	ctx = log.IntoContext(ctx, rw.logger)
 	rw.logger.V(0).Info("Workload Meta", "workload", wm)
 	if rw.recommender == nil {
-		return nil, nil, nil, errors.New("No recommenders configured in the workflow.")
+		return nil, nil, nil, nil, errors.New("No recommenders configured in the workflow.")
+	}
+	if rw.clientsRegistry != nil {
+		if err := rw.checkWorkloadActive(wm); err != nil {
+			rw.logger.V(0).Info("Skipping the recommendation", "workload", wm, "reason", err.Error())
+			return nil, nil, nil, nil, err
+		}
+	}
+	if rw.workerPool != nil {
+		if err := rw.workerPool.Acquire(ctx, wm.Namespace); err != nil {
+			rw.logger.Error(err, "Error while waiting for a worker to generate the recommendation")
+			return nil, nil, nil, nil, err
+		}
+		defer rw.workerPool.Release()
+	}
+
+	rw.reloadLock.RLock()
+	minRequiredReplicas := rw.minRequiredReplicas
+	rw.reloadLock.RUnlock()
+	if rw.configResolver != nil {
+		config, err := rw.configResolver.Resolve(ctx, wm.Namespace)
+		if err != nil {
+			rw.logger.Error(err, "Error while resolving the OttoscalrConfig of the namespace")
+			return nil, nil, nil, nil, err
+		}
+		if config != nil {
+			explanation.Config = config.Name
+			if config.MinRequiredReplicas != nil {
+				minRequiredReplicas = *config.MinRequiredReplicas
+			}
+			ctx = ContextWithWorkloadConfig(ctx, config)
+		}
 	}
 
 	recoGenerationStartTime := time.Now()
-	targetRecoConfig, err := rw.recommender.Recommend(ctx, wm)
+	targetRecoConfig, recoMetadata, err = rw.recommender.Recommend(ctx, wm)
 	recoGenerationLatency := time.Since(recoGenerationStartTime).Seconds()
 	getRecoGenerationLatency.WithLabelValues(wm.Namespace, wm.Name, wm.Kind, wm.Name).Observe(recoGenerationLatency)
 	if err != nil {
 		rw.logger.Error(err, "Error while generating recommendation")
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	//Add a metric for the actual recommendation config generated by the recommendation
-	targetRecoConfig = transformTargetRecoConfig(targetRecoConfig, rw.minRequiredReplicas)
-	var nextPolicy *Policy
+	targetRecoConfig = transformTargetRecoConfig(targetRecoConfig, minRequiredReplicas)
+	var quotaConstraint *QuotaConstraint
+	if rw.quotaResolver != nil && targetRecoConfig != nil {
+		quotaMaxReplicas, quotas, err := rw.quotaResolver.MaxReplicas(ctx, wm, targetRecoConfig.Max)
+		if err != nil {
+			rw.logger.Error(err, "Error while resolving the max replicas allowed by the ResourceQuotas")
+			return nil, nil, nil, nil, err
+		}
+		if len(quotas) > 0 {
+			quotaConstraint = &QuotaConstraint{ResourceQuotas: quotas, MaxReplicas: quotaMaxReplicas,
+				RecommendedMaxReplicas: targetRecoConfig.Max}
+			explanation.ResourceQuotas = quotas
+			targetRecoConfig = rw.honourResourceQuotas(targetRecoConfig, quotaConstraint, wm, &explanation)
+		}
+		if recoMetadata != nil {
+			recoMetadata.QuotaConstraint = quotaConstraint
+		}
+	}
+	pdbMinReplicas := 0
+	if rw.pdbResolver != nil && targetRecoConfig != nil {
+		var budgets []string
+		pdbMinReplicas, budgets, err = rw.pdbResolver.MinReplicas(ctx, wm, targetRecoConfig.Max)
+		if err != nil {
+			rw.logger.Error(err, "Error while resolving the min replicas required by the PodDisruptionBudgets")
+			return nil, nil, nil, nil, err
+		}
+		explanation.PodDisruptionBudgets = budgets
+		explanation.PodDisruptionBudgetMinReplicas = pdbMinReplicas
+		targetRecoConfig = rw.honourPodDisruptionBudgets(targetRecoConfig, pdbMinReplicas, budgets, wm, &explanation)
+	}
 	for i, pi := range rw.policyIterators {
 		rw.logger.V(0).Info("Running policy iterator", "iterator", i)
-		p, err := pi.NextPolicy(ctx, wm)
+		p, err := rw.nextPolicy(ctx, pi, wm)
 		if err != nil {
 			rw.logger.Error(err, "Error while generating recommendation")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
+		iteratorPolicies[pi.GetName()] = p
 
 		if p == nil {
 			rw.logger.V(0).Info("Skipping this PI since it has recommended nil policy (no-op)", "iterator", i)
@@ -168,14 +354,151 @@ func (rw *RecommendationWorkflowImpl) Execute(ctx context.Context, wm WorkloadMe
 
 	}
 
-	nextConfig, policyToApply, err := rw.generateNextRecoConfig(targetRecoConfig, nextPolicy, wm)
+	nextConfig, policyToApply, err = rw.generateNextRecoConfig(targetRecoConfig, nextPolicy, wm)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
-	return nextConfig, targetRecoConfig, policyToApply, nil
+	nextConfig = rw.honourResourceQuotas(nextConfig, quotaConstraint, wm, &explanation)
+	nextConfig = rw.honourPodDisruptionBudgets(nextConfig, pdbMinReplicas, explanation.PodDisruptionBudgets, wm, &explanation)
+	return nextConfig, targetRecoConfig, policyToApply, recoMetadata, nil
+}
+
+// checkWorkloadActive returns a WorkloadInactiveError if the workload is scaled to zero or paused.
+// ReloadMinRequiredReplicas makes the next recommendations keep the min replicas at least at minRequiredReplicas,
+// unless the OttoscalrConfig of the namespace of the workload overrides it.
+func (rw *RecommendationWorkflowImpl) ReloadMinRequiredReplicas(minRequiredReplicas int) {
+	rw.reloadLock.Lock()
+	defer rw.reloadLock.Unlock()
+	rw.minRequiredReplicas = minRequiredReplicas
+}
+
+func (rw *RecommendationWorkflowImpl) checkWorkloadActive(wm WorkloadMeta) error {
+	objectClient, err := rw.clientsRegistry.GetObjectClient(wm.Kind)
+	if err != nil {
+		return err
+	}
+	reason, err := objectClient.GetInactiveReason(wm.Namespace, wm.Name)
+	if err != nil {
+		return err
+	}
+	if len(reason) > 0 {
+		return &WorkloadInactiveError{Reason: reason}
+	}
+	return nil
+}
+
+// honourPodDisruptionBudgets raises the min replicas of the config to the min replicas required by the
+// PodDisruptionBudgets of the workload, up to its max replicas, and warns about it.
+func (rw *RecommendationWorkflowImpl) honourPodDisruptionBudgets(config *v1alpha1.HPAConfiguration, pdbMinReplicas int,
+	budgets []string, wm WorkloadMeta, explanation *Explanation) *v1alpha1.HPAConfiguration {
+	if config == nil || config.Min >= pdbMinReplicas {
+		return config
+	}
+	minReplicas := int(math.Min(float64(pdbMinReplicas), float64(config.Max)))
+	warning := fmt.Sprintf("min replicas %d doesn't allow any disruption with the PodDisruptionBudgets %v which require %d replicas",
+		config.Min, budgets, pdbMinReplicas)
+	if pdbMinReplicas > config.Max {
+		warning += fmt.Sprintf(", which is more than the max replicas %d", config.Max)
+	}
+	rw.logger.Info("Warning: "+warning, "workload", wm)
+	explanation.Warnings = append(explanation.Warnings, warning)
+	if minReplicas <= config.Min {
+		return config
+	}
+	pdbMinReplicasRaisedCounter.WithLabelValues(wm.Namespace, wm.Kind, wm.Name).Inc()
+	return &v1alpha1.HPAConfiguration{Min: minReplicas, Max: config.Max, TargetMetricValue: config.TargetMetricValue,
+		MetricName: config.MetricName, TargetMetricType: config.TargetMetricType}
+}
+
+// honourResourceQuotas clamps the max replicas of the config to the replicas the ResourceQuotas of the workload allow,
+// along with its min replicas, and warns about it.
+func (rw *RecommendationWorkflowImpl) honourResourceQuotas(config *v1alpha1.HPAConfiguration, quotaConstraint *QuotaConstraint,
+	wm WorkloadMeta, explanation *Explanation) *v1alpha1.HPAConfiguration {
+	if config == nil || quotaConstraint == nil || config.Max <= quotaConstraint.MaxReplicas {
+		return config
+	}
+	warning := fmt.Sprintf("max replicas %d doesn't fit in the ResourceQuotas %v which allow %d replicas",
+		config.Max, quotaConstraint.ResourceQuotas, quotaConstraint.MaxReplicas)
+	rw.logger.Info("Warning: "+warning, "workload", wm)
+	explanation.Warnings = append(explanation.Warnings, warning)
+	quotaMaxReplicasClampedCounter.WithLabelValues(wm.Namespace, wm.Kind, wm.Name).Inc()
+	minReplicas := int(math.Min(float64(config.Min), float64(quotaConstraint.MaxReplicas)))
+	return &v1alpha1.HPAConfiguration{Min: minReplicas, Max: quotaConstraint.MaxReplicas, TargetMetricValue: config.TargetMetricValue,
+		MetricName: config.MetricName, TargetMetricType: config.TargetMetricType}
+}
+
+func (rw *RecommendationWorkflowImpl) recordExplanation(explanation Explanation, iteratorPolicies map[string]*Policy, nextPolicy *Policy,
+	recoMetadata *RecommendationMetadata, targetRecoConfig, nextConfig *v1alpha1.HPAConfiguration, policyToApply *Policy, err error) {
+	if recoMetadata != nil {
+		explanation.MetricsWindowStart = recoMetadata.MetricsWindowStart
+		explanation.MetricsWindowEnd = recoMetadata.MetricsWindowEnd
+		explanation.MetricStepSeconds = int(recoMetadata.MetricStep.Seconds())
+		explanation.DataPointsCoveragePercent = recoMetadata.DataPointsCoveragePercent
+		explanation.ExcludedDowntimeSeconds = int(recoMetadata.ExcludedDowntime.Seconds())
+		explanation.BreachFreeMaxReplicas = recoMetadata.BreachFreeMaxReplicas
+		explanation.DerivedMaxReplicas = recoMetadata.DerivedMaxReplicas
+		explanation.TransformersApplied = recoMetadata.TransformersApplied
+		explanation.TransformerAudits = recoMetadata.TransformerAudits
+		explanation.CronTriggers = recoMetadata.CronTriggers
+		explanation.IdleWindow = recoMetadata.IdleWindow
+		explanation.InsufficientHistory = recoMetadata.InsufficientHistory
+		explanation.NoOp = recoMetadata.NoOp
+		explanation.Confidence = recoMetadata.Confidence
+		explanation.Resize = recoMetadata.Resize
+		explanation.PodResize = recoMetadata.PodResize
+		explanation.RedLineUtilization = recoMetadata.RedLineUtilization
+		explanation.RedLineTier = recoMetadata.RedLineTier
+	}
+	explanation.TargetRecoConfig = targetRecoConfig
+	explanation.PolicyDecision = newPolicyDecision(iteratorPolicies)
+	explanation.PolicyDecision.AppliedConfig = nextConfig
+	if nextPolicy != nil {
+		explanation.PolicyDecision.NextPolicy = nextPolicy.Name
+	}
+	if policyToApply != nil {
+		explanation.PolicyDecision.AppliedPolicy = policyToApply.Name
+	}
+	if targetRecoConfig != nil && nextConfig != nil {
+		explanation.PolicyDecision.TargetRecoApplied = nextConfig.DeepEquals(*targetRecoConfig)
+	}
+	if err != nil {
+		explanation.Error = err.Error()
+	}
+	rw.explanations.put(explanation)
+}
+
+// Explain returns the reasoning behind the last recommendation generated for the workload, along with the
+// details of the simulation if the recommender records them.
+func (rw *RecommendationWorkflowImpl) Explain(namespace, workload string) (*Explanation, bool) {
+	explanation, ok := rw.explanations.get(namespace, workload)
+	if !ok {
+		return nil, false
+	}
+	if provider, ok := rw.recommender.(SimulationDetailsProvider); ok {
+		if details, ok := provider.GetSimulationDetails(namespace, workload); ok {
+			explanation.Simulation = &details
+		}
+	}
+	return &explanation, true
+}
+
+func (rw *RecommendationWorkflowImpl) nextPolicy(ctx context.Context, pi PolicyIterator, wm WorkloadMeta) (*Policy, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "PolicyIterator.NextPolicy",
+		trace.WithAttributes(attribute.String("ottoscalr.policyiterator", pi.GetName())))
+	defer span.End()
+	p, err := pi.NextPolicy(ctx, wm)
+	tracing.RecordError(span, err)
+	if p != nil {
+		span.SetAttributes(attribute.String("ottoscalr.policy", p.Name))
+	}
+	return p, err
 }
 
 func (rw *RecommendationWorkflowImpl) generateNextRecoConfig(config *v1alpha1.HPAConfiguration, policy *Policy, wm WorkloadMeta) (*v1alpha1.HPAConfiguration, *Policy, error) {
+	// the policies ladder the target utilization, which doesn't apply to the configs targeting other metric values
+	if config != nil && config.GetTargetMetricType() != v1alpha1.UtilizationMetricTarget {
+		return config, nil, nil
+	}
 	applyReco, closestSafePolicy, err := rw.shouldApplyReco(config, policy, wm)
 	if err != nil {
 		return nil, nil, err
@@ -199,6 +522,11 @@ func createRecoConfigFromPolicy(policy *Policy, recoConfig *v1alpha1.HPAConfigur
 	}, nil
 }
 
+// PolicyConfig returns the config the workflow moves a workload on the policy to, given its target recommendation.
+func PolicyConfig(policy *Policy, recoConfig *v1alpha1.HPAConfiguration) (*v1alpha1.HPAConfiguration, error) {
+	return createRecoConfigFromPolicy(policy, recoConfig, WorkloadMeta{})
+}
+
 // Determines whether the recommendation should take precedence over the nextPolicy
 func (rw *RecommendationWorkflowImpl) shouldApplyReco(config *v1alpha1.HPAConfiguration, policy *Policy, wm WorkloadMeta) (bool, *Policy, error) {
 	if config == nil {
@@ -254,7 +582,8 @@ func transformTargetRecoConfig(targetRecoConfig *v1alpha1.HPAConfiguration, minR
 	if maxReplicas >= minRequiredReplicas && minReplicas < minRequiredReplicas {
 		minReplicas = minRequiredReplicas
 	}
-	return &v1alpha1.HPAConfiguration{Min: minReplicas, Max: maxReplicas, TargetMetricValue: targetRecoConfig.TargetMetricValue}
+	return &v1alpha1.HPAConfiguration{Min: minReplicas, Max: maxReplicas, TargetMetricValue: targetRecoConfig.TargetMetricValue,
+		MetricName: targetRecoConfig.MetricName, TargetMetricType: targetRecoConfig.TargetMetricType}
 }
 
 func (rw *RecommendationWorkflowImpl) findClosestSafePolicy(config *v1alpha1.HPAConfiguration) (*Policy, error) {
@@ -262,17 +591,7 @@ func (rw *RecommendationWorkflowImpl) findClosestSafePolicy(config *v1alpha1.HPA
 	if err != nil {
 		return nil, err
 	}
-	var closestSafePolicy *v1alpha1.Policy
-
-	for _, pc := range policies.Items {
-		if pc.Spec.MinReplicaPercentageCut == 100 && (pc.Spec.TargetUtilization <= config.TargetMetricValue) {
-			// pc is a loop variable and should not assign address of loop variable
-			candidatePolicy := pc
-			closestSafePolicy = &candidatePolicy
-		}
-
-	}
-
+	closestSafePolicy := closestSafePolicy(policies.Items, *config)
 	if closestSafePolicy == nil {
 		return nil, errors.New("closest safe policy not found")
 	}
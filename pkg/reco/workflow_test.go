@@ -2,11 +2,18 @@ package reco
 
 import (
 	"context"
+	"errors"
 	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/policy"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("RecommendationWorkflow", func() {
@@ -82,7 +89,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 			Expect(recoWorkflowBuilder.recommender).NotTo(BeNil())
 			Expect(recoWorkflowBuilder.policyIterators).To(BeNil())
 
-			nextConfig, targetConfig, policy, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+			nextConfig, targetConfig, policy, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 				Name:      "test",
 				Namespace: "default",
 			})
@@ -114,7 +121,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 			Expect(recoWorkflowBuilder.recommender).To(BeNil())
 			Expect(recoWorkflowBuilder.policyIterators).NotTo(BeNil())
 
-			_, _, _, err = recoWorkflow.Execute(ctx, WorkloadMeta{
+			_, _, _, _, err = recoWorkflow.Execute(ctx, WorkloadMeta{
 				Name:      "test",
 				Namespace: "default",
 			})
@@ -149,7 +156,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 			Expect(recoWorkflowBuilder.policyIterators["mockPI"]).NotTo(BeNil())
 			Expect(recoWorkflowBuilder.policyIterators["mockPI"].GetName()).To(Equal("mockPI"))
 
-			nextConfig, targetConfig, policy, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+			nextConfig, targetConfig, policy, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 				Name:      "test",
 				Namespace: "default",
 			})
@@ -168,6 +175,17 @@ var _ = Describe("RecommendationWorkflow", func() {
 			Expect(policy.RiskIndex).To(Equal(10))
 			Expect(policy.TargetUtilization).To(Equal(20))
 
+			explanation, ok := recoWorkflow.(Explainer).Explain("default", "test")
+			Expect(ok).To(BeTrue())
+			Expect(explanation.TargetRecoConfig.TargetMetricValue).To(Equal(50))
+			Expect(explanation.PolicyDecision.IteratorPolicies).To(Equal([]IteratorPolicy{{Iterator: "mockPI", Policy: "mockPolicy"}}))
+			Expect(explanation.PolicyDecision.NextPolicy).To(Equal("mockPolicy"))
+			Expect(explanation.PolicyDecision.AppliedPolicy).To(Equal("mockPolicy"))
+			Expect(explanation.PolicyDecision.TargetRecoApplied).To(BeFalse())
+			Expect(explanation.PolicyDecision.AppliedConfig.TargetMetricValue).To(Equal(20))
+
+			_, ok = recoWorkflow.(Explainer).Explain("default", "unknown")
+			Expect(ok).To(BeFalse())
 		})
 
 		Context("Test with a valid recommender and PIs with target reco safer than policy", func() {
@@ -196,7 +214,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 				Expect(recoWorkflowBuilder.policyIterators["mockPI"]).NotTo(BeNil())
 				Expect(recoWorkflowBuilder.policyIterators["mockPI"].GetName()).To(Equal("mockPI"))
 
-				nextConfig, targetConfig, policy, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+				nextConfig, targetConfig, policy, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 					Name:      "test",
 					Namespace: "default",
 				})
@@ -236,7 +254,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 				}).WithPolicyIterator(&MockPI{}).WithMinRequiredReplicas(3).WithPolicyStore(store).WithK8sClient(k8sClient).Build()
 				Expect(recoWorkflow).NotTo(BeNil())
 				Expect(err).NotTo(HaveOccurred())
-				_, targetConfig, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+				_, targetConfig, _, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 					Name:      "test",
 					Namespace: "default",
 				})
@@ -267,7 +285,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 				}).WithPolicyIterator(&MockPI{}).WithMinRequiredReplicas(3).WithPolicyStore(store).WithK8sClient(k8sClient).Build()
 				Expect(recoWorkflow).NotTo(BeNil())
 				Expect(err).NotTo(HaveOccurred())
-				_, targetConfig, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+				_, targetConfig, _, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 					Name:      "test",
 					Namespace: "default",
 				})
@@ -297,7 +315,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 				}).WithPolicyIterator(&MockPI{}).WithMinRequiredReplicas(3).WithPolicyStore(store).WithK8sClient(k8sClient).Build()
 				Expect(recoWorkflow).NotTo(BeNil())
 				Expect(err).NotTo(HaveOccurred())
-				_, targetConfig, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+				_, targetConfig, _, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 					Name:      "test",
 					Namespace: "default",
 				})
@@ -360,7 +378,7 @@ var _ = Describe("RecommendationWorkflow", func() {
 
 				Expect(k8sClient.Status().Patch(ctx, statusPatch, client.Apply, client.FieldOwner("test"))).To(Succeed())
 
-				nextConfig, targetConfig, policy, err := recoWorkflow.Execute(ctx, WorkloadMeta{
+				nextConfig, targetConfig, policy, _, err := recoWorkflow.Execute(ctx, WorkloadMeta{
 					Name:      "test",
 					Namespace: "default",
 				})
@@ -382,3 +400,36 @@ var _ = Describe("RecommendationWorkflow", func() {
 
 	})
 })
+
+var _ = Describe("RecommendationWorkflow of inactive workloads", func() {
+	newWorkflow := func(replicas int32, paused bool) RecommendationWorkflow {
+		fakeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(fakeScheme)).To(Succeed())
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "inactive", Namespace: "inactive-ns"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Paused: paused},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(deployment).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(fakeClient)).Build()
+		recoWorkflow, err := NewRecommendationWorkflowBuilder().WithRecommender(&MockRecommender{Min: 10, Threshold: 50, Max: 20}).
+			WithPolicyStore(policy.NewPolicyStore(fakeClient)).WithK8sClient(fakeClient).WithClientsRegistry(clientsRegistry).Build()
+		Expect(err).NotTo(HaveOccurred())
+		return recoWorkflow
+	}
+	wm := WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: "inactive", Namespace: "inactive-ns"}
+
+	It("should skip the workloads scaled to zero", func() {
+		_, _, _, _, err := newWorkflow(0, false).Execute(context.TODO(), wm)
+		var inactiveErr *WorkloadInactiveError
+		Expect(errors.As(err, &inactiveErr)).To(BeTrue())
+		Expect(inactiveErr.Reason).To(Equal(registry.ScaledToZeroReason))
+	})
+
+	It("should skip the paused workloads", func() {
+		_, _, _, _, err := newWorkflow(3, true).Execute(context.TODO(), wm)
+		var inactiveErr *WorkloadInactiveError
+		Expect(errors.As(err, &inactiveErr)).To(BeTrue())
+		Expect(inactiveErr.Reason).To(Equal(registry.PausedReason))
+	})
+})
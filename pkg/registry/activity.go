@@ -0,0 +1,35 @@
+package registry
+
+import (
+	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons for a workload to be inactive.
+const (
+	ScaledToZeroReason = "ScaledToZero"
+	PausedReason       = "Paused"
+)
+
+// WorkloadInactiveReason returns why the workload is inactive, i.e. scaled to zero or paused, or an empty string if
+// it's active. Replicas default to 1 when unset.
+func WorkloadInactiveReason(object client.Object) string {
+	var replicas *int32
+	var paused bool
+	switch workload := object.(type) {
+	case *appsv1.Deployment:
+		replicas, paused = workload.Spec.Replicas, workload.Spec.Paused
+	case *argov1alpha1.Rollout:
+		replicas, paused = workload.Spec.Replicas, workload.Spec.Paused
+	default:
+		return ""
+	}
+	if replicas != nil && *replicas == 0 {
+		return ScaledToZeroReason
+	}
+	if paused {
+		return PausedReason
+	}
+	return ""
+}
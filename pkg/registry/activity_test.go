@@ -0,0 +1,32 @@
+package registry
+
+import (
+	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("WorkloadInactiveReason", func() {
+	replicas := func(count int32) *int32 {
+		return &count
+	}
+
+	It("should detect the deployments scaled to zero or paused", func() {
+		Expect(WorkloadInactiveReason(&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: replicas(0)}})).To(Equal(ScaledToZeroReason))
+		Expect(WorkloadInactiveReason(&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: replicas(3), Paused: true}})).To(Equal(PausedReason))
+		Expect(WorkloadInactiveReason(&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: replicas(3)}})).To(BeEmpty())
+		Expect(WorkloadInactiveReason(&appsv1.Deployment{})).To(BeEmpty())
+	})
+
+	It("should detect the rollouts scaled to zero or paused", func() {
+		Expect(WorkloadInactiveReason(&argov1alpha1.Rollout{Spec: argov1alpha1.RolloutSpec{Replicas: replicas(0), Paused: true}})).To(Equal(ScaledToZeroReason))
+		Expect(WorkloadInactiveReason(&argov1alpha1.Rollout{Spec: argov1alpha1.RolloutSpec{Paused: true}})).To(Equal(PausedReason))
+		Expect(WorkloadInactiveReason(&argov1alpha1.Rollout{Spec: argov1alpha1.RolloutSpec{Replicas: replicas(1)}})).To(BeEmpty())
+	})
+
+	It("should treat the other kinds as active", func() {
+		Expect(WorkloadInactiveReason(&corev1.Pod{})).To(BeEmpty())
+	})
+})
@@ -50,6 +50,22 @@ func (dc *DeploymentClient) GetObject(namespace string, name string) (client.Obj
 
 }
 
+func (dc *DeploymentClient) GetObjectList(namespace string, selector labels.Selector) ([]client.Object, error) {
+	deploymentList := &appsv1.DeploymentList{}
+	if err := dc.k8sClient.List(context.Background(), deploymentList, &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: selector,
+	}); err != nil {
+		return nil, err
+	}
+	var result []client.Object
+	for i := range deploymentList.Items {
+		deploymentList.Items[i].SetGroupVersionKind(dc.gvk)
+		result = append(result, &deploymentList.Items[i])
+	}
+	return result, nil
+}
+
 func (dc *DeploymentClient) GetMaxReplicaFromAnnotation(namespace string, name string) (int, error) {
 	deploymentObject := &appsv1.Deployment{}
 	if err := dc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, deploymentObject); err != nil {
@@ -110,6 +126,22 @@ func (dc *DeploymentClient) GetReplicaCount(namespace string, name string) (int,
 	return int(*deploymentObject.Spec.Replicas), nil
 }
 
+func (dc *DeploymentClient) GetPodTemplateLabels(namespace string, name string) (map[string]string, error) {
+	deploymentObject := &appsv1.Deployment{}
+	if err := dc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, deploymentObject); err != nil {
+		return nil, err
+	}
+	return deploymentObject.Spec.Template.Labels, nil
+}
+
+func (dc *DeploymentClient) GetInactiveReason(namespace string, name string) (string, error) {
+	object, err := dc.GetObject(namespace, name)
+	if err != nil {
+		return "", err
+	}
+	return WorkloadInactiveReason(object), nil
+}
+
 func (dc *DeploymentClient) Scale(namespace string, name string, replicas int32) error {
 	var workloadPatch client.Object
 
@@ -2,16 +2,20 @@ package registry
 
 import (
 	"fmt"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ObjectClient interface {
 	GetObject(namespace string, name string) (client.Object, error)
+	GetObjectList(namespace string, selector labels.Selector) ([]client.Object, error)
 	GetObjectType() client.Object
 	GetKind() string
 	GetMaxReplicaFromAnnotation(namespace string, name string) (int, error)
 	GetContainerResourceLimits(namespace string, name string) (float64, error)
 	GetReplicaCount(namespace string, name string) (int, error)
+	GetPodTemplateLabels(namespace string, name string) (map[string]string, error)
+	GetInactiveReason(namespace string, name string) (string, error)
 	Scale(namespace string, name string, replicas int32) error
 }
 
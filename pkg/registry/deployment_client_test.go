@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -170,6 +171,19 @@ var _ = Describe("DeploymentClient", func() {
 			})
 		})
 	})
+	Describe("GetObjectList", func() {
+		It("returns the deployments matching the selector", func() {
+			objects, err := deploymentClient.GetObjectList(deploymentNamespace, labels.Everything())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(1))
+			Expect(objects[0].GetName()).To(Equal(deploymentName))
+			Expect(objects[0].GetObjectKind().GroupVersionKind()).To(Equal(DeploymentGVK))
+
+			objects, err = deploymentClient.GetObjectList(deploymentNamespace, labels.SelectorFromSet(labels.Set{"team": "unknown"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(BeEmpty())
+		})
+	})
 	Describe("GetKind", func() {
 		It("returns the kind of the deployment client", func() {
 			kind := deploymentClient.GetKind()
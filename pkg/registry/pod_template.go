@@ -0,0 +1,73 @@
+package registry
+
+import (
+	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodTemplateClient is implemented by the ObjectClients of the workloads which may take their pod template from another
+// object, e.g. the Rollouts referencing a Deployment with their workloadRef.
+type PodTemplateClient interface {
+	GetPodTemplate(namespace string, name string) (*corev1.PodTemplateSpec, error)
+}
+
+// PodTemplate returns the pod template of the workload, or nil for the kinds without one.
+func PodTemplate(object client.Object) *corev1.PodTemplateSpec {
+	switch workload := object.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Template
+	case *argov1alpha1.Rollout:
+		return &workload.Spec.Template
+	default:
+		return nil
+	}
+}
+
+// PodResources returns the requests and limits a pod of the spec is charged for by the ResourceQuotas, which is the
+// higher of the sum of its containers and the largest of its init containers, plus its overhead. The requests
+// default to the limits of the containers which don't set them.
+func PodResources(spec corev1.PodSpec) (corev1.ResourceList, corev1.ResourceList) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range spec.Containers {
+		addResources(requests, containerRequests(container))
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range spec.InitContainers {
+		maxResources(requests, containerRequests(container))
+		maxResources(limits, container.Resources.Limits)
+	}
+	addResources(requests, spec.Overhead)
+	addResources(limits, spec.Overhead)
+	return requests, limits
+}
+
+func containerRequests(container corev1.Container) corev1.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	for name, limit := range container.Resources.Limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = limit.DeepCopy()
+		}
+	}
+	return requests
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package registry
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("PodResources", func() {
+	resources := func(cpu, memory string) corev1.ResourceList {
+		list := corev1.ResourceList{}
+		if cpu != "" {
+			list[corev1.ResourceCPU] = resource.MustParse(cpu)
+		}
+		if memory != "" {
+			list[corev1.ResourceMemory] = resource.MustParse(memory)
+		}
+		return list
+	}
+
+	It("should sum the containers and default the requests to the limits", func() {
+		requests, limits := PodResources(corev1.PodSpec{Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: resources("500m", "256Mi"), Limits: resources("1", "512Mi")}},
+			{Resources: corev1.ResourceRequirements{Limits: resources("250m", "")}},
+		}})
+		Expect(requests.Cpu().MilliValue()).To(Equal(int64(750)))
+		Expect(requests.Memory().Value()).To(Equal(int64(256 << 20)))
+		Expect(limits.Cpu().MilliValue()).To(Equal(int64(1250)))
+	})
+
+	It("should charge for the largest init container and the overhead", func() {
+		requests, _ := PodResources(corev1.PodSpec{
+			Containers:     []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: resources("500m", "")}}},
+			InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: resources("2", "")}}},
+			Overhead:       resources("100m", ""),
+		})
+		Expect(requests.Cpu().MilliValue()).To(Equal(int64(2100)))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	argov1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,6 +21,8 @@ var RolloutGVK = schema.GroupVersionKind{
 	Kind:    "Rollout",
 }
 
+var _ PodTemplateClient = &RolloutClient{}
+
 type RolloutClient struct {
 	k8sClient client.Client
 	gvk       schema.GroupVersionKind
@@ -50,12 +53,37 @@ func (rc *RolloutClient) GetObject(namespace string, name string) (client.Object
 
 }
 
+func (rc *RolloutClient) GetObjectList(namespace string, selector labels.Selector) ([]client.Object, error) {
+	rolloutList := &argov1alpha1.RolloutList{}
+	if err := rc.k8sClient.List(context.Background(), rolloutList, &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: selector,
+	}); err != nil {
+		return nil, err
+	}
+	var result []client.Object
+	for i := range rolloutList.Items {
+		rolloutList.Items[i].SetGroupVersionKind(rc.gvk)
+		result = append(result, &rolloutList.Items[i])
+	}
+	return result, nil
+}
+
+// GetMaxReplicaFromAnnotation returns the max pods annotated on the rollout, or on the workload of its workloadRef if
+// the rollout isn't annotated.
 func (rc *RolloutClient) GetMaxReplicaFromAnnotation(namespace string, name string) (int, error) {
 	rolloutObject := &argov1alpha1.Rollout{}
 	if err := rc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, rolloutObject); err != nil {
 		return 0, err
 	}
 	maxPodsAnnotation, ok := rolloutObject.GetAnnotations()["ottoscalr.io/max-pods"]
+	if !ok && rolloutObject.Spec.WorkloadRef != nil {
+		referenced, err := rc.getWorkloadRef(rolloutObject)
+		if err != nil {
+			return 0, err
+		}
+		maxPodsAnnotation, ok = referenced.GetAnnotations()["ottoscalr.io/max-pods"]
+	}
 	if ok {
 		var err error
 		maxPods, err := strconv.Atoi(maxPodsAnnotation)
@@ -68,11 +96,10 @@ func (rc *RolloutClient) GetMaxReplicaFromAnnotation(namespace string, name stri
 }
 
 func (rc *RolloutClient) GetContainerResourceLimits(namespace string, name string) (float64, error) {
-	rolloutObject := &argov1alpha1.Rollout{}
-	if err := rc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, rolloutObject); err != nil {
+	podTemplateSpec, err := rc.GetPodTemplate(namespace, name)
+	if err != nil {
 		return 0, err
 	}
-	podTemplateSpec := rolloutObject.Spec.Template
 
 	podList := &corev1.PodList{}
 
@@ -102,14 +129,83 @@ func (rc *RolloutClient) GetContainerResourceLimits(namespace string, name strin
 	return float64(cpuLimitsSum) / 1000, nil
 }
 
+// GetReplicaCount returns the replicas of the rollout, which scales its pods even if they are templated by the
+// workload of its workloadRef. The replicas default to 1 when unset, as with argo rollouts.
 func (rc *RolloutClient) GetReplicaCount(namespace string, name string) (int, error) {
 	rolloutObject := &argov1alpha1.Rollout{}
 	if err := rc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, rolloutObject); err != nil {
 		return 0, err
 	}
+	if rolloutObject.Spec.Replicas == nil {
+		return 1, nil
+	}
 	return int(*rolloutObject.Spec.Replicas), nil
 }
 
+func (rc *RolloutClient) GetPodTemplateLabels(namespace string, name string) (map[string]string, error) {
+	podTemplateSpec, err := rc.GetPodTemplate(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return podTemplateSpec.Labels, nil
+}
+
+// GetPodTemplate returns the pod template of the rollout, which is the one of the workload of its workloadRef if it
+// references one.
+func (rc *RolloutClient) GetPodTemplate(namespace string, name string) (*corev1.PodTemplateSpec, error) {
+	rolloutObject := &argov1alpha1.Rollout{}
+	if err := rc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, rolloutObject); err != nil {
+		return nil, err
+	}
+	if rolloutObject.Spec.WorkloadRef == nil {
+		return &rolloutObject.Spec.Template, nil
+	}
+	referenced, err := rc.getWorkloadRef(rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	switch workload := referenced.(type) {
+	case *appsv1.ReplicaSet:
+		return &workload.Spec.Template, nil
+	case *corev1.PodTemplate:
+		return &workload.Template, nil
+	default:
+		return PodTemplate(referenced), nil
+	}
+}
+
+// getWorkloadRef returns the workload the workloadRef of the rollout references, which provides the pod template of
+// the rollout while the rollout scales its pods. Argo rollouts references the Deployments, the ReplicaSets and the
+// PodTemplates, none of which reference a workload in turn.
+func (rc *RolloutClient) getWorkloadRef(rolloutObject *argov1alpha1.Rollout) (client.Object, error) {
+	workloadRef := rolloutObject.Spec.WorkloadRef
+	var referenced client.Object
+	switch workloadRef.Kind {
+	case "Deployment":
+		referenced = &appsv1.Deployment{}
+	case "ReplicaSet":
+		referenced = &appsv1.ReplicaSet{}
+	case "PodTemplate":
+		referenced = &corev1.PodTemplate{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %s in the workloadRef of the rollout %s/%s", workloadRef.Kind,
+			rolloutObject.Namespace, rolloutObject.Name)
+	}
+	if err := rc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: rolloutObject.Namespace,
+		Name: workloadRef.Name}, referenced); err != nil {
+		return nil, err
+	}
+	return referenced, nil
+}
+
+func (rc *RolloutClient) GetInactiveReason(namespace string, name string) (string, error) {
+	object, err := rc.GetObject(namespace, name)
+	if err != nil {
+		return "", err
+	}
+	return WorkloadInactiveReason(object), nil
+}
+
 func (rc *RolloutClient) Scale(namespace string, name string, replicas int32) error {
 	var workloadPatch client.Object
 
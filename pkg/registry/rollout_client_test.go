@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	rolloutv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -212,3 +213,84 @@ var _ = Describe("rolloutClient", func() {
 	})
 
 })
+
+var _ = Describe("rolloutClient with a workloadRef", func() {
+
+	var (
+		deployment *appsv1.Deployment
+		rollout    *rolloutv1alpha1.Rollout
+		pod        *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		labels := map[string]string{"app": "test-workload-ref"}
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-workload-ref",
+				Namespace:   "default",
+				Annotations: map[string]string{"ottoscalr.io/max-pods": "12"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(0),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{
+						Name:  "container-1",
+						Image: "container-image",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1.5")},
+						},
+					}}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+		rollout = &rolloutv1alpha1.Rollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workload-ref", Namespace: "default"},
+			Spec: rolloutv1alpha1.RolloutSpec{
+				Replicas: int32Ptr(4),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				WorkloadRef: &rolloutv1alpha1.ObjectRef{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "test-workload-ref",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rollout)).To(Succeed())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workload-ref-pod", Namespace: "default", Labels: labels},
+			Spec:       *deployment.Spec.Template.Spec.DeepCopy(),
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, rollout)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+	})
+
+	It("should get the limits, the labels and the annotations from the referenced deployment", func() {
+		limits, err := rolloutClient.GetContainerResourceLimits("default", "test-workload-ref")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(Equal(1.5))
+
+		podTemplateLabels, err := rolloutClient.GetPodTemplateLabels("default", "test-workload-ref")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podTemplateLabels).To(Equal(map[string]string{"app": "test-workload-ref"}))
+
+		maxPods, err := rolloutClient.GetMaxReplicaFromAnnotation("default", "test-workload-ref")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxPods).To(Equal(12))
+	})
+
+	It("should get the replicas from the rollout", func() {
+		replicas, err := rolloutClient.GetReplicaCount("default", "test-workload-ref")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(Equal(4))
+	})
+})
@@ -0,0 +1,253 @@
+package report
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	hoursPerYear = 365 * 24
+	// savingsReportCheckInterval is how often the SavingsReports are checked for the ones due for regeneration, so that
+	// the new ones don't wait for the next regeneration of the others.
+	savingsReportCheckInterval = 10 * time.Minute
+)
+
+// SavingsReportRegenerator regenerates the status of the SavingsReport objects every interval with the simulated
+// annual savings of the recommendations.
+type SavingsReportRegenerator struct {
+	k8sClient       client.Client
+	clientsRegistry registry.DeploymentClientRegistry
+	pricing         PricingProvider
+	interval        time.Duration
+	logger          logr.Logger
+}
+
+func NewSavingsReportRegenerator(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry,
+	interval time.Duration, logger logr.Logger) *SavingsReportRegenerator {
+	return &SavingsReportRegenerator{
+		k8sClient:       k8sClient,
+		clientsRegistry: clientsRegistry,
+		interval:        interval,
+		logger:          logger.WithName("SavingsReportRegenerator"),
+	}
+}
+
+// WithPricing prices the replica-hours of the reports in the currency of the pricing provider.
+func (r *SavingsReportRegenerator) WithPricing(pricing PricingProvider) *SavingsReportRegenerator {
+	r.pricing = pricing
+	return r
+}
+
+// Start regenerates the reports due for regeneration until the context is cancelled. It implements the
+// manager.Runnable interface.
+func (r *SavingsReportRegenerator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(savingsReportCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.Regenerate(ctx); err != nil {
+			r.logger.Error(err, "Error regenerating the savings reports.")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection is true so that only the leader regenerates the reports.
+func (r *SavingsReportRegenerator) NeedLeaderElection() bool {
+	return true
+}
+
+// workloadSavings are the annual replica-hours of a workload along with its labels and the price of its replicas.
+type workloadSavings struct {
+	namespace               string
+	labels                  map[string]string
+	enforced                bool
+	baselineReplicaHours    int64
+	recommendedReplicaHours int64
+	replicaHourPrice        *float64
+}
+
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=savingsreports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ottoscaler.io,resources=savingsreports/status,verbs=get;update;patch
+
+// Regenerate regenerates the reports which were never generated or were last generated an interval ago.
+func (r *SavingsReportRegenerator) Regenerate(ctx context.Context) error {
+	reports := &v1beta1.SavingsReportList{}
+	if err := r.k8sClient.List(ctx, reports); err != nil {
+		return err
+	}
+	var due []v1beta1.SavingsReport
+	for _, savingsReport := range reports.Items {
+		if generatedAt := savingsReport.Status.GeneratedAt; generatedAt == nil || time.Since(generatedAt.Time) >= r.interval {
+			due = append(due, savingsReport)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	policyrecos := &v1alpha1.PolicyRecommendationList{}
+	if err := r.k8sClient.List(ctx, policyrecos); err != nil {
+		return err
+	}
+	workloads := make([]workloadSavings, 0, len(policyrecos.Items))
+	for _, policyreco := range policyrecos.Items {
+		workloads = append(workloads, r.workloadSavings(policyreco))
+	}
+
+	now := metav1.Now()
+	for _, savingsReport := range due {
+		patch := client.MergeFrom(savingsReport.DeepCopy())
+		savingsReport.Status = aggregateSavings(workloads, savingsReport.Spec.TeamLabel, r.pricing)
+		savingsReport.Status.GeneratedAt = &now
+		if err := r.k8sClient.Status().Patch(ctx, &savingsReport, patch); err != nil {
+			r.logger.Error(err, "Error updating the savings report.", "report", savingsReport.Name)
+			continue
+		}
+		r.logger.V(0).Info("Regenerated the savings report.", "report", savingsReport.Name)
+	}
+	return nil
+}
+
+// workloadSavings simulates running the workload for a year at the max replicas of its recommendation for the
+// baseline, and at the replicas saving its projected savings otherwise. The workloads without projected savings, e.g.
+// the ones recommended the no-op configuration, save nothing. The labels and the price of the replicas are left
+// unset if the workload can't be fetched.
+func (r *SavingsReportRegenerator) workloadSavings(policyreco v1alpha1.PolicyRecommendation) workloadSavings {
+	maxReplicas := policyreco.Spec.TargetHPAConfiguration.Max
+	if maxReplicas <= 0 {
+		maxReplicas = policyreco.Spec.CurrentHPAConfiguration.Max
+	}
+	savings := workloadSavings{
+		namespace:            policyreco.Namespace,
+		enforced:             isHPAEnforced(policyreco),
+		baselineReplicaHours: int64(maxReplicas) * hoursPerYear,
+	}
+	savings.recommendedReplicaHours = savings.baselineReplicaHours
+	if projected := policyreco.Status.ProjectedSavingsPercent; projected != nil {
+		savings.recommendedReplicaHours -= savings.baselineReplicaHours * int64(*projected) / 100
+	}
+
+	workload := policyreco.Spec.WorkloadMeta
+	objectClient, err := r.clientsRegistry.GetObjectClient(workload.Kind)
+	if err != nil {
+		r.logger.V(0).Error(err, "Unable to fetch the workload. Leaving it out of the teams and the costs.",
+			"namespace", policyreco.Namespace, "workload", workload.Name)
+		return savings
+	}
+	object, err := objectClient.GetObject(policyreco.Namespace, workload.Name)
+	if err != nil {
+		r.logger.V(0).Error(err, "Unable to fetch the workload. Leaving it out of the teams and the costs.",
+			"namespace", policyreco.Namespace, "workload", workload.Name)
+		return savings
+	}
+	savings.labels = object.GetLabels()
+	if r.pricing == nil {
+		return savings
+	}
+
+	podTemplate := registry.PodTemplate(object)
+	if podTemplateClient, ok := objectClient.(registry.PodTemplateClient); ok {
+		if podTemplate, err = podTemplateClient.GetPodTemplate(policyreco.Namespace, workload.Name); err != nil {
+			podTemplate = nil
+		}
+	}
+	if podTemplate == nil {
+		r.logger.V(0).Info("No pod template in the workload. Leaving it out of the costs.",
+			"namespace", policyreco.Namespace, "workload", workload.Name)
+		return savings
+	}
+	price, err := r.pricing.GetReplicaHourPrice(podTemplate)
+	if err != nil {
+		r.logger.V(0).Error(err, "Unable to price the replicas of the workload. Leaving it out of the costs.",
+			"namespace", policyreco.Namespace, "workload", workload.Name)
+		return savings
+	}
+	savings.replicaHourPrice = &price
+	return savings
+}
+
+// savingsAggregate sums up the savings of the workloads along with their costs.
+type savingsAggregate struct {
+	savings      v1beta1.Savings
+	baselineCost float64
+	savedCost    float64
+}
+
+func (a *savingsAggregate) add(workload workloadSavings) {
+	a.savings.Workloads++
+	if workload.enforced {
+		a.savings.EnforcedWorkloads++
+	}
+	saved := workload.baselineReplicaHours - workload.recommendedReplicaHours
+	a.savings.BaselineReplicaHours += workload.baselineReplicaHours
+	a.savings.RecommendedReplicaHours += workload.recommendedReplicaHours
+	a.savings.SavedReplicaHours += saved
+	if workload.replicaHourPrice != nil {
+		a.baselineCost += float64(workload.baselineReplicaHours) * *workload.replicaHourPrice
+		a.savedCost += float64(saved) * *workload.replicaHourPrice
+	}
+}
+
+// result returns the savings with their costs formatted if they're priced.
+func (a *savingsAggregate) result(priced bool) v1beta1.Savings {
+	if priced {
+		a.savings.BaselineCost = strconv.FormatFloat(a.baselineCost, 'f', 2, 64)
+		a.savings.SavedCost = strconv.FormatFloat(a.savedCost, 'f', 2, 64)
+	}
+	return a.savings
+}
+
+// aggregateSavings sums up the savings of the workloads in total, by namespace and by the values of the team label.
+func aggregateSavings(workloads []workloadSavings, teamLabel string, pricing PricingProvider) v1beta1.SavingsReportStatus {
+	total := &savingsAggregate{}
+	namespaces := make(map[string]*savingsAggregate)
+	teams := make(map[string]*savingsAggregate)
+	for _, workload := range workloads {
+		total.add(workload)
+		if _, ok := namespaces[workload.namespace]; !ok {
+			namespaces[workload.namespace] = &savingsAggregate{}
+		}
+		namespaces[workload.namespace].add(workload)
+		team := workload.labels[teamLabel]
+		if len(teamLabel) == 0 || len(team) == 0 {
+			continue
+		}
+		if _, ok := teams[team]; !ok {
+			teams[team] = &savingsAggregate{}
+		}
+		teams[team].add(workload)
+	}
+
+	priced := pricing != nil
+	status := v1beta1.SavingsReportStatus{Total: total.result(priced)}
+	if priced {
+		status.Currency = pricing.GetCurrency()
+	}
+	for namespace, aggregate := range namespaces {
+		status.Namespaces = append(status.Namespaces,
+			v1beta1.NamespaceSavings{Namespace: namespace, Savings: aggregate.result(priced)})
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool {
+		return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace
+	})
+	for team, aggregate := range teams {
+		status.Teams = append(status.Teams, v1beta1.TeamSavings{Team: team, Savings: aggregate.result(priced)})
+	}
+	sort.Slice(status.Teams, func(i, j int) bool {
+		return status.Teams[i].Team < status.Teams[j].Team
+	})
+	return status
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/api/v1beta1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Annual savings report", func() {
+	var k8sClient client.Client
+	var regenerator *SavingsReportRegenerator
+
+	newTeamDeployment := func(namespace, name, team string) *appsv1.Deployment {
+		deployment := newDeployment(namespace, name, 4)
+		if len(team) > 0 {
+			deployment.Labels = map[string]string{"team": team}
+		}
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi")},
+		}}}
+		return deployment
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+		generatedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+		objects := []client.Object{
+			newPolicyReco("ns1", "app1", "safest-policy", intPtr(20), 20),
+			newPolicyReco("ns1", "app2", "aggressive-policy", intPtr(50), 10),
+			newPolicyReco("ns2", "app3", "safest-policy", nil, 0),
+			newTeamDeployment("ns1", "app1", "checkout"),
+			newTeamDeployment("ns1", "app2", "payments"),
+			newTeamDeployment("ns2", "app3", ""),
+			&v1beta1.SavingsReport{ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: v1beta1.SavingsReportSpec{TeamLabel: "team"}},
+			&v1beta1.SavingsReport{ObjectMeta: metav1.ObjectMeta{Name: "recent"},
+				Status: v1beta1.SavingsReportStatus{GeneratedAt: &generatedAt}},
+		}
+		objects[2].(*v1alpha1.PolicyRecommendation).Spec.TargetHPAConfiguration.Max = 6
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithStatusSubresource(&v1beta1.SavingsReport{}).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		regenerator = NewSavingsReportRegenerator(k8sClient, *clientsRegistry, 24*time.Hour, logr.Discard())
+	})
+
+	It("should aggregate the annual replica-hours by namespace and team", func() {
+		Expect(regenerator.Regenerate(context.TODO())).To(Succeed())
+
+		savingsReport := &v1beta1.SavingsReport{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "fleet"}, savingsReport)).To(Succeed())
+		status := savingsReport.Status
+		Expect(status.GeneratedAt).NotTo(BeNil())
+		Expect(status.Currency).To(BeEmpty())
+		// app1 saves 20% of 20 replicas, app2 50% of 10 and app3 nothing of 6
+		Expect(status.Total).To(Equal(v1beta1.Savings{Workloads: 3, EnforcedWorkloads: 2,
+			BaselineReplicaHours: 36 * hoursPerYear, RecommendedReplicaHours: 27 * hoursPerYear,
+			SavedReplicaHours: 9 * hoursPerYear}))
+		Expect(status.Namespaces).To(HaveLen(2))
+		Expect(status.Namespaces[0].Namespace).To(Equal("ns1"))
+		Expect(status.Namespaces[0].SavedReplicaHours).To(Equal(int64(9 * hoursPerYear)))
+		Expect(status.Namespaces[1].Namespace).To(Equal("ns2"))
+		Expect(status.Namespaces[1].BaselineReplicaHours).To(Equal(int64(6 * hoursPerYear)))
+		Expect(status.Teams).To(HaveLen(2))
+		Expect(status.Teams[0].Team).To(Equal("checkout"))
+		Expect(status.Teams[0].SavedReplicaHours).To(Equal(int64(4 * hoursPerYear)))
+		Expect(status.Teams[1].Team).To(Equal("payments"))
+		Expect(status.Teams[1].SavedReplicaHours).To(Equal(int64(5 * hoursPerYear)))
+
+		// the report generated within the interval isn't regenerated
+		recent := &v1beta1.SavingsReport{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "recent"}, recent)).To(Succeed())
+		Expect(recent.Status.Namespaces).To(BeEmpty())
+	})
+
+	It("should price the replica-hours with the pricing provider", func() {
+		Expect(regenerator.WithPricing(NewResourcePricing("USD", 0.03, 0.005)).Regenerate(context.TODO())).To(Succeed())
+
+		savingsReport := &v1beta1.SavingsReport{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "fleet"}, savingsReport)).To(Succeed())
+		// a replica requesting 2 cores and 4Gi costs 0.08 an hour
+		Expect(savingsReport.Status.Currency).To(Equal("USD"))
+		Expect(savingsReport.Status.Total.BaselineCost).To(Equal("25228.80"))
+		Expect(savingsReport.Status.Total.SavedCost).To(Equal("6307.20"))
+		Expect(savingsReport.Status.Teams[0].SavedCost).To(Equal("2803.20"))
+	})
+})
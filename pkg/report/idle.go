@@ -0,0 +1,281 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	idleWorkloadUtilizationGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "idle_workload_p99_utilization_percent",
+			Help: "P99 cpu utilization over the window of the workloads found idle by the last analysis"},
+		[]string{"namespace", "kind", "workload"},
+	)
+
+	idleWorkloadsReportUploadErrorsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "idle_workloads_report_upload_errors_count",
+			Help: "Number of idle workloads reports which couldn't be uploaded to a sink"}, []string{"sink"},
+	)
+
+	idleWorkloadsReportLastGeneratedTime = promauto.NewGauge(
+		prometheus.GaugeOpts{Name: "idle_workloads_report_last_generated_timestamp_seconds",
+			Help: "Unix time the last idle workloads report was generated at"},
+	)
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(idleWorkloadUtilizationGauge, idleWorkloadsReportUploadErrorsCounter,
+		idleWorkloadsReportLastGeneratedTime)
+}
+
+// IdleWorkloadsReport lists the workloads whose p99 cpu utilization over the window is below the threshold, the
+// candidates for decommissioning.
+type IdleWorkloadsReport struct {
+	GeneratedAt      time.Time      `json:"generatedAt"`
+	Window           string         `json:"window"`
+	ThresholdPercent float64        `json:"thresholdPercent"`
+	Workloads        []IdleWorkload `json:"workloads"`
+}
+
+// IdleWorkload is a workload found idle, with its p99 cpu utilization over the resources of its current replicas.
+type IdleWorkload struct {
+	Namespace             string  `json:"namespace"`
+	Kind                  string  `json:"kind"`
+	Name                  string  `json:"name"`
+	Replicas              int     `json:"replicas"`
+	P99UtilizationPercent float64 `json:"p99UtilizationPercent"`
+	P99UtilizationCPUs    float64 `json:"p99UtilizationCPUs"`
+	ReservedCPUs          float64 `json:"reservedCPUs"`
+	DataPoints            int     `json:"dataPoints"`
+}
+
+// IdleWorkloadsAnalyzer periodically looks for the idle workloads among the workloads with a PolicyRecommendation,
+// exports their utilization as metrics and uploads the report to all of its sinks.
+type IdleWorkloadsAnalyzer struct {
+	k8sClient        client.Client
+	clientsRegistry  registry.DeploymentClientRegistry
+	scraper          metrics.Scraper
+	window           time.Duration
+	step             time.Duration
+	thresholdPercent float64
+	interval         time.Duration
+	format           Format
+	sinks            []Sink
+	logger           logr.Logger
+}
+
+func NewIdleWorkloadsAnalyzer(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry,
+	scraper metrics.Scraper, window time.Duration, step time.Duration, thresholdPercent float64,
+	interval time.Duration, format Format, logger logr.Logger, sinks ...Sink) *IdleWorkloadsAnalyzer {
+	return &IdleWorkloadsAnalyzer{
+		k8sClient:        k8sClient,
+		clientsRegistry:  clientsRegistry,
+		scraper:          scraper,
+		window:           window,
+		step:             step,
+		thresholdPercent: thresholdPercent,
+		interval:         interval,
+		format:           format,
+		sinks:            sinks,
+		logger:           logger.WithName("IdleWorkloadsAnalyzer"),
+	}
+}
+
+// Start analyzes the workloads every interval until the context is cancelled. It implements the manager.Runnable
+// interface.
+func (a *IdleWorkloadsAnalyzer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.Report(ctx); err != nil {
+				a.logger.Error(err, "Error generating the idle workloads report.")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection is true so that only the leader scrapes the workloads and uploads the reports.
+func (a *IdleWorkloadsAnalyzer) NeedLeaderElection() bool {
+	return true
+}
+
+// Report analyzes the workloads, exports the utilization of the idle ones and uploads the report to all the sinks.
+// Failing to upload to a sink is logged and doesn't stop the upload to the other sinks.
+func (a *IdleWorkloadsAnalyzer) Report(ctx context.Context) error {
+	idleReport, err := a.Analyze(ctx)
+	if err != nil {
+		return err
+	}
+	idleWorkloadUtilizationGauge.Reset()
+	for _, workload := range idleReport.Workloads {
+		idleWorkloadUtilizationGauge.WithLabelValues(workload.Namespace, workload.Kind, workload.Name).
+			Set(workload.P99UtilizationPercent)
+	}
+	idleWorkloadsReportLastGeneratedTime.Set(float64(idleReport.GeneratedAt.Unix()))
+	a.logger.V(0).Info("Analyzed the idle workloads.", "idleWorkloads", len(idleReport.Workloads))
+	if len(a.sinks) == 0 {
+		return nil
+	}
+
+	body, contentType, err := EncodeIdleWorkloads(idleReport, a.format)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("ottoscalr-idle-workloads-report-%s.%s", idleReport.GeneratedAt.UTC().Format("20060102T150405Z"),
+		a.format)
+	for _, sink := range a.sinks {
+		if err := sink.Upload(ctx, name, contentType, body); err != nil {
+			a.logger.Error(err, "Error uploading the idle workloads report.", "sink", sink.GetName(), "report", name)
+			idleWorkloadsReportUploadErrorsCounter.WithLabelValues(sink.GetName()).Inc()
+			continue
+		}
+		a.logger.V(0).Info("Uploaded the idle workloads report.", "sink", sink.GetName(), "report", name)
+	}
+	return nil
+}
+
+// Analyze lists the workloads whose p99 cpu utilization over the window is below the threshold. The workloads which
+// can't be analyzed, e.g. as they have no replicas or no metrics, are logged and left out.
+func (a *IdleWorkloadsAnalyzer) Analyze(ctx context.Context) (*IdleWorkloadsReport, error) {
+	policyrecos := &v1alpha1.PolicyRecommendationList{}
+	if err := a.k8sClient.List(ctx, policyrecos); err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	idleReport := &IdleWorkloadsReport{GeneratedAt: end, Window: a.window.String(), ThresholdPercent: a.thresholdPercent,
+		Workloads: []IdleWorkload{}}
+	for _, policyreco := range policyrecos.Items {
+		workload, err := a.analyzeWorkload(policyreco, end)
+		if err != nil {
+			a.logger.V(1).Info("Unable to analyze the utilization of the workload. Skipping it.",
+				"namespace", policyreco.Namespace, "workload", policyreco.Spec.WorkloadMeta.Name, "reason", err.Error())
+			continue
+		}
+		if workload.P99UtilizationPercent < a.thresholdPercent {
+			idleReport.Workloads = append(idleReport.Workloads, *workload)
+		}
+	}
+	sort.Slice(idleReport.Workloads, func(i, j int) bool {
+		if idleReport.Workloads[i].Namespace != idleReport.Workloads[j].Namespace {
+			return idleReport.Workloads[i].Namespace < idleReport.Workloads[j].Namespace
+		}
+		return idleReport.Workloads[i].Name < idleReport.Workloads[j].Name
+	})
+	return idleReport, nil
+}
+
+func (a *IdleWorkloadsAnalyzer) analyzeWorkload(policyreco v1alpha1.PolicyRecommendation, end time.Time) (*IdleWorkload, error) {
+	kind, name := policyreco.Spec.WorkloadMeta.Kind, policyreco.Spec.WorkloadMeta.Name
+	objectClient, err := a.clientsRegistry.GetObjectClient(kind)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := objectClient.GetReplicaCount(policyreco.Namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if replicas <= 0 {
+		return nil, fmt.Errorf("the workload has no replicas")
+	}
+	cpuPerPod, err := objectClient.GetContainerResourceLimits(policyreco.Namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if cpuPerPod <= 0 {
+		return nil, fmt.Errorf("the workload has no cpu limits")
+	}
+	dataPoints, err := a.scraper.GetAverageCPUUtilizationByWorkload(policyreco.Namespace, name, end.Add(-a.window), end,
+		a.step)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataPoints) == 0 {
+		return nil, fmt.Errorf("no datapoints of the cpu utilization in the window")
+	}
+
+	reserved := float64(replicas) * cpuPerPod
+	p99 := percentile(dataPoints, 99)
+	return &IdleWorkload{
+		Namespace:             policyreco.Namespace,
+		Kind:                  kind,
+		Name:                  name,
+		Replicas:              replicas,
+		P99UtilizationPercent: p99 / reserved * 100,
+		P99UtilizationCPUs:    p99,
+		ReservedCPUs:          reserved,
+		DataPoints:            len(dataPoints),
+	}, nil
+}
+
+// percentile returns the nearest-rank percentile of the values of the datapoints.
+func percentile(dataPoints []metrics.DataPoint, p float64) float64 {
+	values := make([]float64, len(dataPoints))
+	for i, dataPoint := range dataPoints {
+		values[i] = dataPoint.Value
+	}
+	sort.Float64s(values)
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	if rank < 1 {
+		rank = 1
+	}
+	return values[rank-1]
+}
+
+// EncodeIdleWorkloads renders the report in the format along with its content type.
+func EncodeIdleWorkloads(idleReport *IdleWorkloadsReport, format Format) ([]byte, string, error) {
+	switch format {
+	case JSONFormat:
+		body, err := json.MarshalIndent(idleReport, "", "  ")
+		return body, "application/json", err
+	case CSVFormat:
+		body, err := encodeIdleWorkloadsCSV(idleReport)
+		return body, "text/csv", err
+	}
+	return nil, "", fmt.Errorf("unsupported report format %s", format)
+}
+
+// encodeIdleWorkloadsCSV writes a row per idle workload.
+func encodeIdleWorkloadsCSV(idleReport *IdleWorkloadsReport) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	if err := writer.Write([]string{"namespace", "kind", "name", "replicas", "p99UtilizationPercent",
+		"p99UtilizationCPUs", "reservedCPUs"}); err != nil {
+		return nil, err
+	}
+	for _, workload := range idleReport.Workloads {
+		if err := writer.Write([]string{
+			workload.Namespace,
+			workload.Kind,
+			workload.Name,
+			strconv.Itoa(workload.Replicas),
+			strconv.FormatFloat(workload.P99UtilizationPercent, 'f', 2, 64),
+			strconv.FormatFloat(workload.P99UtilizationCPUs, 'f', 2, 64),
+			strconv.FormatFloat(workload.ReservedCPUs, 'f', 2, 64),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
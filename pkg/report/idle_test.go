@@ -0,0 +1,107 @@
+package report
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/flipkart-incubator/ottoscalr/pkg/testutil"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newIdleTestWorkload returns a deployment of the replicas with a pod of the cpu limits, and its PolicyRecommendation.
+func newIdleTestWorkload(namespace, name string, replicas int32, cpus string) []client.Object {
+	deployment := newDeployment(namespace, name, replicas)
+	deployment.Spec.Template.Labels = map[string]string{"app": name}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-pod", Namespace: namespace, Labels: map[string]string{"app": name}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app",
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpus)}}}}},
+	}
+	return []client.Object{deployment, pod, newPolicyReco(namespace, name, "safest-policy", nil, 0)}
+}
+
+func replayedUtilization(values ...float64) testutil.ReplayWorkload {
+	start := time.Now()
+	var fixture []metrics.DataPoint
+	for i, value := range values {
+		fixture = append(fixture, metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value})
+	}
+	return testutil.ReplayWorkload{Fixture: fixture}
+}
+
+var _ = Describe("Idle workloads report", func() {
+	var analyzer *IdleWorkloadsAnalyzer
+	var sinkServer *httptest.Server
+	var bodies []string
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		var objects []client.Object
+		// 4 replicas of 2 cpus reserve 8 cpus
+		objects = append(objects, newIdleTestWorkload("ns1", "idle", 4, "2")...)
+		objects = append(objects, newIdleTestWorkload("ns1", "busy", 4, "2")...)
+		objects = append(objects, newIdleTestWorkload("ns2", "unscraped", 4, "2")...)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		scraper := testutil.NewReplayScraper().
+			WithWorkload("ns1", "idle", replayedUtilization(0.1, 0.2, 0.3, 0.2)).
+			WithWorkload("ns1", "busy", replayedUtilization(0.1, 4, 0.3, 0.2))
+
+		bodies = nil
+		sinkServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+		}))
+		analyzer = NewIdleWorkloadsAnalyzer(k8sClient, *clientsRegistry, scraper, time.Hour, time.Minute, 5, time.Hour,
+			CSVFormat, logr.Discard(), NewWebhookSink(sinkServer.URL, time.Second))
+	})
+
+	AfterEach(func() {
+		sinkServer.Close()
+	})
+
+	It("should list the workloads whose p99 utilization is below the threshold", func() {
+		idleReport, err := analyzer.Analyze(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idleReport.Workloads).To(HaveLen(1))
+		idle := idleReport.Workloads[0]
+		Expect(idle.Name).To(Equal("idle"))
+		Expect(idle.ReservedCPUs).To(Equal(8.0))
+		Expect(idle.P99UtilizationCPUs).To(Equal(0.3))
+		Expect(idle.P99UtilizationPercent).To(BeNumerically("~", 3.75, 0.001))
+	})
+
+	It("should upload the report to the sinks", func() {
+		Expect(analyzer.Report(context.TODO())).To(Succeed())
+		Expect(bodies).To(HaveLen(1))
+		Expect(bodies[0]).To(Equal("namespace,kind,name,replicas,p99UtilizationPercent,p99UtilizationCPUs,reservedCPUs\n" +
+			"ns1,Deployment,idle,4,3.75,0.30,8.00\n"))
+	})
+
+	It("should take the nearest rank percentile of the datapoints", func() {
+		var dataPoints []metrics.DataPoint
+		for i := 1; i <= 200; i++ {
+			dataPoints = append(dataPoints, metrics.DataPoint{Value: float64(i)})
+		}
+		Expect(percentile(dataPoints, 99)).To(Equal(198.0))
+		Expect(percentile(dataPoints[:1], 99)).To(Equal(1.0))
+	})
+})
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const bytesPerGiB = 1 << 30
+
+var _ PricingProvider = &ResourcePricing{}
+
+// PricingProvider prices the replicas of the workloads for the costs of the savings reports.
+type PricingProvider interface {
+	GetCurrency() string
+	// GetReplicaHourPrice returns the price of running a pod of the template for an hour.
+	GetReplicaHourPrice(podTemplate *corev1.PodTemplateSpec) (float64, error)
+}
+
+// ResourcePricing prices the replicas by the cpu cores and the memory their pods request, at a flat price per core
+// and per GiB for an hour, e.g. the on-demand prices of the nodes of the cluster.
+type ResourcePricing struct {
+	currency         string
+	pricePerCoreHour float64
+	pricePerGiBHour  float64
+}
+
+func NewResourcePricing(currency string, pricePerCoreHour, pricePerGiBHour float64) *ResourcePricing {
+	return &ResourcePricing{
+		currency:         currency,
+		pricePerCoreHour: pricePerCoreHour,
+		pricePerGiBHour:  pricePerGiBHour,
+	}
+}
+
+func (p *ResourcePricing) GetCurrency() string {
+	return p.currency
+}
+
+// GetReplicaHourPrice prices the requests a pod of the template is charged for, which default to the limits of the
+// containers which don't set them.
+func (p *ResourcePricing) GetReplicaHourPrice(podTemplate *corev1.PodTemplateSpec) (float64, error) {
+	requests, _ := registry.PodResources(podTemplate.Spec)
+	cpu, hasCPU := requests[corev1.ResourceCPU]
+	memory, hasMemory := requests[corev1.ResourceMemory]
+	if !hasCPU && !hasMemory {
+		return 0, fmt.Errorf("no cpu or memory requested by the pods")
+	}
+	return cpu.AsApproximateFloat64()*p.pricePerCoreHour +
+		memory.AsApproximateFloat64()/bytesPerGiB*p.pricePerGiBHour, nil
+}
@@ -0,0 +1,156 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/labels"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// managedAutoscalerSelector selects the autoscalers the HPA enforcer manages by the label it creates them with.
+const managedAutoscalerSelector = "created-by=ottoscalr"
+
+var (
+	workloadRealizedSavingsGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "workload_realized_savings_percent",
+			Help: "Savings of the average replicas of an enforced workload since its enforcement over the ones of the baseline window before it"},
+		[]string{"namespace", "kind", "workload"},
+	)
+
+	workloadSavingsGapGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "workload_savings_gap_percent",
+			Help: "Projected savings of an enforced workload less its realized savings"},
+		[]string{"namespace", "kind", "workload"},
+	)
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(workloadRealizedSavingsGauge, workloadSavingsGapGauge)
+}
+
+// ReplicaTimelineScraper scrapes the replicas the workloads actually ran at over time.
+type ReplicaTimelineScraper interface {
+	// GetPodCountByWorkload returns the number of pods of the workload.
+	GetPodCountByWorkload(namespace, workload string, start, end time.Time, step time.Duration) ([]metrics.DataPoint, error)
+}
+
+// RealizedSavings are the savings of an enforced workload measured on the replicas it actually ran at: the average
+// replicas over the baseline window before ottoscalr enforced its autoscaler against the ones since, over no more
+// than the same window. The savings are negative if the workload ran at more replicas since.
+type RealizedSavings struct {
+	Namespace               string    `json:"namespace"`
+	Kind                    string    `json:"kind"`
+	Name                    string    `json:"name"`
+	EnforcedAt              time.Time `json:"enforcedAt"`
+	BaselineAvgReplicas     float64   `json:"baselineAvgReplicas"`
+	RealizedAvgReplicas     float64   `json:"realizedAvgReplicas"`
+	ProjectedSavingsPercent *int      `json:"projectedSavingsPercent,omitempty"`
+	RealizedSavingsPercent  float64   `json:"realizedSavingsPercent"`
+}
+
+// ReplicaTimelineAnalyzer reconstructs the replica timelines of the enforced workloads around their enforcement, i.e.
+// the creation of the autoscalers ottoscalr manages for them, to measure the savings they realized.
+type ReplicaTimelineAnalyzer struct {
+	scraper          ReplicaTimelineScraper
+	autoscalerClient autoscaler.AutoscalerClient
+	window           time.Duration
+	step             time.Duration
+}
+
+func NewReplicaTimelineAnalyzer(scraper ReplicaTimelineScraper, autoscalerClient autoscaler.AutoscalerClient,
+	window time.Duration, step time.Duration) *ReplicaTimelineAnalyzer {
+	return &ReplicaTimelineAnalyzer{
+		scraper:          scraper,
+		autoscalerClient: autoscalerClient,
+		window:           window,
+		step:             step,
+	}
+}
+
+// RealizedSavings measures the savings the workload of the policyreco realized since its enforcement, up to now.
+func (a *ReplicaTimelineAnalyzer) RealizedSavings(ctx context.Context, policyreco v1alpha1.PolicyRecommendation,
+	now time.Time) (*RealizedSavings, error) {
+	workload := policyreco.Spec.WorkloadMeta
+	enforcedAt, err := a.enforcedAt(ctx, policyreco.Namespace, workload.Name)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := a.scraper.GetPodCountByWorkload(policyreco.Namespace, workload.Name,
+		enforcedAt.Add(-a.window), enforcedAt, a.step)
+	if err != nil {
+		return nil, err
+	}
+	realizedStart := enforcedAt
+	if now.Sub(enforcedAt) > a.window {
+		realizedStart = now.Add(-a.window)
+	}
+	realized, err := a.scraper.GetPodCountByWorkload(policyreco.Namespace, workload.Name, realizedStart, now, a.step)
+	if err != nil {
+		return nil, err
+	}
+	baselineReplicas, realizedReplicas := averageReplicas(baseline), averageReplicas(realized)
+	if baselineReplicas <= 0 {
+		return nil, fmt.Errorf("no replicas of the workload in the baseline window before %s",
+			enforcedAt.Format(time.RFC3339))
+	}
+	if len(realized) == 0 {
+		return nil, fmt.Errorf("no replicas of the workload since %s", enforcedAt.Format(time.RFC3339))
+	}
+	return &RealizedSavings{
+		Namespace:               policyreco.Namespace,
+		Kind:                    workload.Kind,
+		Name:                    workload.Name,
+		EnforcedAt:              enforcedAt,
+		BaselineAvgReplicas:     baselineReplicas,
+		RealizedAvgReplicas:     realizedReplicas,
+		ProjectedSavingsPercent: policyreco.Status.ProjectedSavingsPercent,
+		RealizedSavingsPercent:  (baselineReplicas - realizedReplicas) / baselineReplicas * 100,
+	}, nil
+}
+
+// enforcedAt returns the creation time of the autoscaler ottoscalr manages for the workload.
+func (a *ReplicaTimelineAnalyzer) enforcedAt(ctx context.Context, namespace, workload string) (time.Time, error) {
+	labelSelector, err := labels.Parse(managedAutoscalerSelector)
+	if err != nil {
+		return time.Time{}, err
+	}
+	autoscalerObjects, err := a.autoscalerClient.GetList(ctx, labelSelector, namespace, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, autoscalerObject := range autoscalerObjects {
+		if a.autoscalerClient.GetScaleTargetName(autoscalerObject) == workload {
+			return autoscalerObject.GetCreationTimestamp().Time, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no %s managed by ottoscalr for the workload", a.autoscalerClient.GetName())
+}
+
+func averageReplicas(dataPoints []metrics.DataPoint) float64 {
+	if len(dataPoints) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, dp := range dataPoints {
+		sum += dp.Value
+	}
+	return sum / float64(len(dataPoints))
+}
+
+// exportRealizedSavings exports the realized savings of the workload along with how far they fell short of the
+// projected savings.
+func exportRealizedSavings(savings RealizedSavings) {
+	workloadRealizedSavingsGauge.WithLabelValues(savings.Namespace, savings.Kind, savings.Name).
+		Set(savings.RealizedSavingsPercent)
+	if savings.ProjectedSavingsPercent != nil {
+		workloadSavingsGapGauge.WithLabelValues(savings.Namespace, savings.Kind, savings.Name).
+			Set(float64(*savings.ProjectedSavingsPercent) - savings.RealizedSavingsPercent)
+	}
+}
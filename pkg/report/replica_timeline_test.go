@@ -0,0 +1,91 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/autoscaler"
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeReplicaTimelineScraper runs the workloads at the replicas before their enforcement till then and at the
+// replicas after it since.
+type fakeReplicaTimelineScraper struct {
+	enforcedAt time.Time
+	before     float64
+	after      float64
+}
+
+func (s *fakeReplicaTimelineScraper) GetPodCountByWorkload(namespace, workload string, start, end time.Time,
+	step time.Duration) ([]metrics.DataPoint, error) {
+	var dataPoints []metrics.DataPoint
+	for ts := start; ts.Before(end); ts = ts.Add(step) {
+		value := s.after
+		if ts.Before(s.enforcedAt) {
+			value = s.before
+		}
+		dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: ts, Value: value})
+	}
+	return dataPoints, nil
+}
+
+var _ = Describe("Replica timelines", func() {
+	now := time.Now()
+	enforcedAt := now.Add(-2 * 24 * time.Hour).Truncate(time.Hour)
+	var generator *Generator
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		objects := []client.Object{
+			newPolicyReco("ns1", "app1", "safest-policy", intPtr(50), 20),
+			newPolicyReco("ns1", "app2", "safest-policy", intPtr(20), 10),
+			newDeployment("ns1", "app1", 10),
+			newDeployment("ns1", "app2", 5),
+			&autoscalingv1.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "ns1", CreationTimestamp: metav1.NewTime(enforcedAt),
+					Labels: map[string]string{"created-by": "ottoscalr"}},
+				Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{Name: "app1"}},
+			},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		timelines := NewReplicaTimelineAnalyzer(&fakeReplicaTimelineScraper{enforcedAt: enforcedAt, before: 16, after: 12},
+			autoscaler.NewHPAClient(k8sClient), 7*24*time.Hour, time.Hour)
+		generator = NewGenerator(k8sClient, *clientsRegistry, logr.Discard()).WithReplicaTimelines(timelines)
+	})
+
+	It("should measure the realized savings on the replicas before and since the enforcement", func() {
+		savingsReport, err := generator.Generate(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(savingsReport.Workloads).To(HaveLen(1))
+		realized := savingsReport.Workloads[0]
+		Expect(realized.Name).To(Equal("app1"))
+		Expect(realized.EnforcedAt).To(BeTemporally("==", enforcedAt))
+		Expect(realized.BaselineAvgReplicas).To(Equal(16.0))
+		Expect(realized.RealizedAvgReplicas).To(Equal(12.0))
+		Expect(realized.RealizedSavingsPercent).To(Equal(25.0))
+		Expect(*realized.ProjectedSavingsPercent).To(Equal(50))
+	})
+
+	It("should fall back to the current replicas of the workloads without a replica timeline", func() {
+		savingsReport, err := generator.Generate(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		// app1 realized 25% on its timeline and app2, without an autoscaler of ottoscalr, 50% of its max replicas
+		Expect(savingsReport.Namespaces[0].AvgRealizedSavingsPercent).To(Equal(37.5))
+	})
+})
@@ -0,0 +1,220 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Format is the encoding of the savings report.
+type Format string
+
+const (
+	JSONFormat Format = "json"
+	CSVFormat  Format = "csv"
+)
+
+// SavingsReport summarizes the recommendations and the savings across the fleet per namespace.
+type SavingsReport struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Namespaces  []NamespaceReport `json:"namespaces"`
+	// Workloads are the savings the enforced workloads realized, measured on their replica timelines.
+	Workloads []RealizedSavings `json:"workloads,omitempty"`
+}
+
+// NamespaceReport summarizes the recommendations of the workloads in a namespace. The projected savings average
+// over the workloads with a projection and the realized savings over the workloads with an enforced autoscaler.
+type NamespaceReport struct {
+	Namespace                  string         `json:"namespace"`
+	Workloads                  int            `json:"workloads"`
+	EnforcedWorkloads          int            `json:"enforcedWorkloads"`
+	PolicyDistribution         map[string]int `json:"policyDistribution"`
+	AvgProjectedSavingsPercent float64        `json:"avgProjectedSavingsPercent"`
+	AvgRealizedSavingsPercent  float64        `json:"avgRealizedSavingsPercent"`
+}
+
+// Generator aggregates all the PolicyRecommendations into a SavingsReport.
+type Generator struct {
+	k8sClient       client.Client
+	clientsRegistry registry.DeploymentClientRegistry
+	timelines       *ReplicaTimelineAnalyzer
+	logger          logr.Logger
+}
+
+func NewGenerator(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry, logger logr.Logger) *Generator {
+	return &Generator{
+		k8sClient:       k8sClient,
+		clientsRegistry: clientsRegistry,
+		logger:          logger.WithName("SavingsReportGenerator"),
+	}
+}
+
+// WithReplicaTimelines measures the realized savings of the enforced workloads on the replicas they ran at since
+// their enforcement against the ones before, rather than on their current replicas against their max replicas. The
+// workloads whose replica timelines can't be reconstructed fall back to their current replicas.
+func (g *Generator) WithReplicaTimelines(timelines *ReplicaTimelineAnalyzer) *Generator {
+	g.timelines = timelines
+	return g
+}
+
+type savingsAccumulator struct {
+	total float64
+	count int
+}
+
+func (a *savingsAccumulator) add(savings float64) {
+	a.total += savings
+	a.count++
+}
+
+func (a *savingsAccumulator) average() float64 {
+	if a.count == 0 {
+		return 0
+	}
+	return a.total / float64(a.count)
+}
+
+func (g *Generator) Generate(ctx context.Context) (*SavingsReport, error) {
+	policyrecos := &v1alpha1.PolicyRecommendationList{}
+	if err := g.k8sClient.List(ctx, policyrecos); err != nil {
+		return nil, err
+	}
+
+	report := &SavingsReport{GeneratedAt: time.Now()}
+	namespaces := make(map[string]*NamespaceReport)
+	projectedSavings := make(map[string]*savingsAccumulator)
+	realizedSavings := make(map[string]*savingsAccumulator)
+	for _, policyreco := range policyrecos.Items {
+		ns, ok := namespaces[policyreco.Namespace]
+		if !ok {
+			ns = &NamespaceReport{Namespace: policyreco.Namespace, PolicyDistribution: make(map[string]int)}
+			namespaces[policyreco.Namespace] = ns
+			projectedSavings[policyreco.Namespace] = &savingsAccumulator{}
+			realizedSavings[policyreco.Namespace] = &savingsAccumulator{}
+		}
+		ns.Workloads++
+		ns.PolicyDistribution[policyreco.Spec.Policy]++
+		if policyreco.Status.ProjectedSavingsPercent != nil {
+			projectedSavings[policyreco.Namespace].add(float64(*policyreco.Status.ProjectedSavingsPercent))
+		}
+		if !isHPAEnforced(policyreco) {
+			continue
+		}
+		ns.EnforcedWorkloads++
+		if g.timelines != nil {
+			realized, err := g.timelines.RealizedSavings(ctx, policyreco, report.GeneratedAt)
+			if err == nil {
+				exportRealizedSavings(*realized)
+				realizedSavings[policyreco.Namespace].add(realized.RealizedSavingsPercent)
+				report.Workloads = append(report.Workloads, *realized)
+				continue
+			}
+			g.logger.V(0).Error(err, "Unable to reconstruct the replica timeline of the workload. Falling back to its current replicas.",
+				"namespace", policyreco.Namespace, "workload", policyreco.Spec.WorkloadMeta.Name)
+		}
+		if savings, err := g.realizedSavingsPercent(policyreco); err != nil {
+			g.logger.V(0).Error(err, "Unable to compute the realized savings of the workload. Skipping it.",
+				"namespace", policyreco.Namespace, "workload", policyreco.Spec.WorkloadMeta.Name)
+		} else {
+			realizedSavings[policyreco.Namespace].add(savings)
+		}
+	}
+
+	for namespace, ns := range namespaces {
+		ns.AvgProjectedSavingsPercent = projectedSavings[namespace].average()
+		ns.AvgRealizedSavingsPercent = realizedSavings[namespace].average()
+		report.Namespaces = append(report.Namespaces, *ns)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		if report.Workloads[i].Namespace != report.Workloads[j].Namespace {
+			return report.Workloads[i].Namespace < report.Workloads[j].Namespace
+		}
+		return report.Workloads[i].Name < report.Workloads[j].Name
+	})
+	return report, nil
+}
+
+// realizedSavingsPercent is the savings of the current replicas of the workload over the baseline of running it
+// at the max replicas of the enforced HPA configuration.
+func (g *Generator) realizedSavingsPercent(policyreco v1alpha1.PolicyRecommendation) (float64, error) {
+	baselineReplicas := policyreco.Spec.CurrentHPAConfiguration.Max
+	if baselineReplicas <= 0 {
+		return 0, fmt.Errorf("invalid max replicas %d in the HPA configuration", baselineReplicas)
+	}
+	objectClient, err := g.clientsRegistry.GetObjectClient(policyreco.Spec.WorkloadMeta.Kind)
+	if err != nil {
+		return 0, err
+	}
+	currentReplicas, err := objectClient.GetReplicaCount(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(float64(baselineReplicas-currentReplicas)/float64(baselineReplicas)*100, 0), nil
+}
+
+func isHPAEnforced(policyreco v1alpha1.PolicyRecommendation) bool {
+	for _, condition := range policyreco.Status.Conditions {
+		if condition.Type == string(v1alpha1.HPAEnforced) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Encode renders the report in the format along with its content type.
+func Encode(report *SavingsReport, format Format) ([]byte, string, error) {
+	switch format {
+	case JSONFormat:
+		body, err := json.MarshalIndent(report, "", "  ")
+		return body, "application/json", err
+	case CSVFormat:
+		body, err := encodeCSV(report)
+		return body, "text/csv", err
+	}
+	return nil, "", fmt.Errorf("unsupported report format %s", format)
+}
+
+// encodeCSV writes a row per namespace. The policy distribution is flattened as policy=count pairs.
+func encodeCSV(report *SavingsReport) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	if err := writer.Write([]string{"namespace", "workloads", "enforcedWorkloads", "policyDistribution",
+		"avgProjectedSavingsPercent", "avgRealizedSavingsPercent"}); err != nil {
+		return nil, err
+	}
+	for _, ns := range report.Namespaces {
+		policies := make([]string, 0, len(ns.PolicyDistribution))
+		for policy, count := range ns.PolicyDistribution {
+			policies = append(policies, fmt.Sprintf("%s=%d", policy, count))
+		}
+		sort.Strings(policies)
+		if err := writer.Write([]string{
+			ns.Namespace,
+			strconv.Itoa(ns.Workloads),
+			strconv.Itoa(ns.EnforcedWorkloads),
+			strings.Join(policies, ";"),
+			strconv.FormatFloat(ns.AvgProjectedSavingsPercent, 'f', 2, 64),
+			strconv.FormatFloat(ns.AvgRealizedSavingsPercent, 'f', 2, 64),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPolicyReco(namespace, name, policy string, projectedSavings *int, enforcedMax int) *v1alpha1.PolicyRecommendation {
+	policyreco := &v1alpha1.PolicyRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1alpha1.PolicyRecommendationSpec{
+			WorkloadMeta:            v1alpha1.WorkloadMeta{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}, Name: name},
+			CurrentHPAConfiguration: v1alpha1.HPAConfiguration{Min: 5, Max: enforcedMax, TargetMetricValue: 50},
+			Policy:                  policy,
+		},
+		Status: v1alpha1.PolicyRecommendationStatus{ProjectedSavingsPercent: projectedSavings},
+	}
+	if enforcedMax > 0 {
+		policyreco.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.HPAEnforced),
+			Status: metav1.ConditionTrue}}
+	}
+	return policyreco
+}
+
+func newDeployment(namespace, name string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+var _ = Describe("Savings report", func() {
+	var generator *Generator
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		objects := []client.Object{
+			newPolicyReco("ns1", "app1", "safest-policy", intPtr(20), 20),
+			newPolicyReco("ns1", "app2", "aggressive-policy", intPtr(40), 10),
+			newPolicyReco("ns2", "app3", "safest-policy", nil, 0),
+			newDeployment("ns1", "app1", 10),
+			newDeployment("ns1", "app2", 8),
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(k8sClient)).Build()
+		generator = NewGenerator(k8sClient, *clientsRegistry, logr.Discard())
+	})
+
+	It("should aggregate the recommendations per namespace", func() {
+		savingsReport, err := generator.Generate(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(savingsReport.Namespaces).To(HaveLen(2))
+
+		ns1 := savingsReport.Namespaces[0]
+		Expect(ns1.Namespace).To(Equal("ns1"))
+		Expect(ns1.Workloads).To(Equal(2))
+		Expect(ns1.EnforcedWorkloads).To(Equal(2))
+		Expect(ns1.PolicyDistribution).To(Equal(map[string]int{"safest-policy": 1, "aggressive-policy": 1}))
+		Expect(ns1.AvgProjectedSavingsPercent).To(Equal(30.0))
+		// app1 runs 10 of 20 and app2 8 of 10 max replicas
+		Expect(ns1.AvgRealizedSavingsPercent).To(BeNumerically("~", 35.0, 0.001))
+
+		ns2 := savingsReport.Namespaces[1]
+		Expect(ns2.Workloads).To(Equal(1))
+		Expect(ns2.EnforcedWorkloads).To(Equal(0))
+		Expect(ns2.AvgProjectedSavingsPercent).To(Equal(0.0))
+	})
+
+	It("should encode the report as CSV", func() {
+		savingsReport := &SavingsReport{Namespaces: []NamespaceReport{{
+			Namespace:                  "ns1",
+			Workloads:                  2,
+			EnforcedWorkloads:          1,
+			PolicyDistribution:         map[string]int{"safest-policy": 1, "aggressive-policy": 1},
+			AvgProjectedSavingsPercent: 30,
+			AvgRealizedSavingsPercent:  12.5,
+		}}}
+		body, contentType, err := Encode(savingsReport, CSVFormat)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contentType).To(Equal("text/csv"))
+		Expect(string(body)).To(Equal("namespace,workloads,enforcedWorkloads,policyDistribution,avgProjectedSavingsPercent,avgRealizedSavingsPercent\n" +
+			"ns1,2,1,aggressive-policy=1;safest-policy=1,30.00,12.50\n"))
+
+		_, _, err = Encode(savingsReport, Format("xml"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should upload the report to all the sinks", func() {
+		var uploads []*http.Request
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, r)
+			bodies = append(bodies, string(body))
+		}))
+		defer server.Close()
+
+		reporter := NewSavingsReporter(generator, time.Hour, JSONFormat, logr.Discard(),
+			NewObjectStorageSink(server.URL+"/bucket/", "token", time.Second),
+			NewWebhookSink(server.URL+"/reports", time.Second))
+		Expect(reporter.Report(context.TODO())).To(Succeed())
+
+		Expect(uploads).To(HaveLen(2))
+		Expect(uploads[0].Method).To(Equal(http.MethodPut))
+		Expect(uploads[0].URL.Path).To(HavePrefix("/bucket/ottoscalr-savings-report-"))
+		Expect(uploads[0].URL.Path).To(HaveSuffix(".json"))
+		Expect(uploads[0].Header.Get("Authorization")).To(Equal("Bearer token"))
+		Expect(uploads[1].Method).To(Equal(http.MethodPost))
+		Expect(uploads[1].URL.Path).To(Equal("/reports"))
+		Expect(uploads[1].Header.Get("X-Report-Name")).To(Equal(strings.TrimPrefix(uploads[0].URL.Path, "/bucket/")))
+		Expect(bodies[1]).To(ContainSubstring(`"namespace": "ns1"`))
+	})
+})
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	savingsReportUploadErrorsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "savings_report_upload_errors_count",
+			Help: "Number of savings reports which couldn't be uploaded to a sink"}, []string{"sink"},
+	)
+
+	savingsReportLastGeneratedTime = promauto.NewGauge(
+		prometheus.GaugeOpts{Name: "savings_report_last_generated_timestamp_seconds",
+			Help: "Unix time the last savings report was generated at"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(savingsReportUploadErrorsCounter, savingsReportLastGeneratedTime)
+}
+
+// SavingsReporter periodically generates the savings report and uploads it to all of its sinks.
+type SavingsReporter struct {
+	generator *Generator
+	interval  time.Duration
+	format    Format
+	sinks     []Sink
+	logger    logr.Logger
+}
+
+func NewSavingsReporter(generator *Generator, interval time.Duration, format Format, logger logr.Logger, sinks ...Sink) *SavingsReporter {
+	return &SavingsReporter{
+		generator: generator,
+		interval:  interval,
+		format:    format,
+		sinks:     sinks,
+		logger:    logger.WithName("SavingsReporter"),
+	}
+}
+
+// Start generates a report every interval until the context is cancelled. It implements the manager.Runnable
+// interface.
+func (r *SavingsReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Report(ctx); err != nil {
+				r.logger.Error(err, "Error generating the savings report.")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection is true so that only the leader uploads the reports.
+func (r *SavingsReporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Report generates a savings report and uploads it to all the sinks. Failing to upload to a sink is logged and
+// doesn't stop the upload to the other sinks.
+func (r *SavingsReporter) Report(ctx context.Context) error {
+	savingsReport, err := r.generator.Generate(ctx)
+	if err != nil {
+		return err
+	}
+	body, contentType, err := Encode(savingsReport, r.format)
+	if err != nil {
+		return err
+	}
+	savingsReportLastGeneratedTime.Set(float64(savingsReport.GeneratedAt.Unix()))
+
+	name := reportName(savingsReport.GeneratedAt, r.format)
+	for _, sink := range r.sinks {
+		if err := sink.Upload(ctx, name, contentType, body); err != nil {
+			r.logger.Error(err, "Error uploading the savings report.", "sink", sink.GetName(), "report", name)
+			savingsReportUploadErrorsCounter.WithLabelValues(sink.GetName()).Inc()
+			continue
+		}
+		r.logger.V(0).Info("Uploaded the savings report.", "sink", sink.GetName(), "report", name)
+	}
+	return nil
+}
+
+func reportName(generatedAt time.Time, format Format) string {
+	return fmt.Sprintf("ottoscalr-savings-report-%s.%s", generatedAt.UTC().Format("20060102T150405Z"), format)
+}
@@ -0,0 +1,88 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sink delivers the encoded savings reports.
+type Sink interface {
+	Upload(ctx context.Context, name string, contentType string, body []byte) error
+	GetName() string
+}
+
+// ObjectStorageSink PUTs every report as an object named after the report under a bucket URL, e.g. an S3 or GCS
+// compatible endpoint. The bearer token, if any, is sent in the Authorization header.
+type ObjectStorageSink struct {
+	bucketURL  string
+	authToken  string
+	httpClient *http.Client
+}
+
+func NewObjectStorageSink(bucketURL string, authToken string, timeout time.Duration) *ObjectStorageSink {
+	return &ObjectStorageSink{
+		bucketURL:  strings.TrimSuffix(bucketURL, "/"),
+		authToken:  authToken,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *ObjectStorageSink) Upload(ctx context.Context, name string, contentType string, body []byte) error {
+	headers := map[string]string{"Content-Type": contentType}
+	if len(s.authToken) > 0 {
+		headers["Authorization"] = "Bearer " + s.authToken
+	}
+	return send(ctx, s.httpClient, http.MethodPut, s.bucketURL+"/"+name, headers, body)
+}
+
+func (s *ObjectStorageSink) GetName() string {
+	return "objectStorage"
+}
+
+// WebhookSink POSTs every report to an HTTP endpoint. The name of the report is sent in the X-Report-Name header.
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Upload(ctx context.Context, name string, contentType string, body []byte) error {
+	return send(ctx, s.httpClient, http.MethodPost, s.url, map[string]string{
+		"Content-Type":  contentType,
+		"X-Report-Name": name,
+	}, body)
+}
+
+func (s *WebhookSink) GetName() string {
+	return "webhook"
+}
+
+func send(ctx context.Context, httpClient *http.Client, method string, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("report upload to %s responded with status code %d", url, resp.StatusCode)
+	}
+	return nil
+}
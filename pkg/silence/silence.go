@@ -0,0 +1,217 @@
+package silence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	silencesPath = "/api/v2/silences"
+	createdBy    = "ottoscalr"
+)
+
+var (
+	silencesCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "alert_silences_count",
+			Help: "Number of Alertmanager silences created for the changes of the autoscalers of the workloads"}, []string{"namespace"},
+	)
+
+	silenceErrorsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "alert_silence_errors_count",
+			Help: "Number of calls to Alertmanager to create a silence which failed"}, []string{"namespace"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(silencesCounter, silenceErrorsCounter)
+}
+
+// Request is a change of the autoscaler of a workload whose capacity alerts are to be silenced.
+type Request struct {
+	Namespace    string
+	WorkloadKind string
+	Workload     string
+	// Reason is what changed, for the comment of the silence.
+	Reason string
+}
+
+// Silencer silences the capacity alerts of the workloads for a while after the changes of their autoscalers, which
+// are expected to drop their replicas for a bit.
+type Silencer interface {
+	Silence(ctx context.Context, request Request) error
+}
+
+// AlertmanagerSilencer creates the silences with the v2 API of Alertmanager. A silence matches the alerts of the alert
+// names of the namespace of the workload whose workload label names the workload. It ends after the duration, when
+// Alertmanager expires it, and the later changes of the workload till then extend it rather than creating another.
+type AlertmanagerSilencer struct {
+	url           string
+	httpClient    *http.Client
+	duration      time.Duration
+	workloadLabel string
+	alertNames    []string
+	now           func() time.Time
+
+	lock     sync.Mutex
+	silenced map[types.NamespacedName]activeSilence
+}
+
+// activeSilence is the silence of a workload created by the silencer, till it ends.
+type activeSilence struct {
+	id       string
+	startsAt time.Time
+	endsAt   time.Time
+}
+
+type matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type silence struct {
+	// ID is set to update the silence of the ID.
+	ID        string    `json:"id,omitempty"`
+	Matchers  []matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+// NewAlertmanagerSilencer creates the silencer of the Alertmanager at the url. It fails unless the url is an absolute
+// http(s) url, the duration is positive and both the workload label and the alert names are set, so that the silences
+// never match more than the capacity alerts of a workload.
+func NewAlertmanagerSilencer(alertmanagerURL string, timeout, duration time.Duration, workloadLabel string,
+	alertNames ...string) (*AlertmanagerSilencer, error) {
+	parsedURL, err := url.Parse(alertmanagerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alertmanager url %q: %v", alertmanagerURL, err)
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || len(parsedURL.Host) == 0 {
+		return nil, fmt.Errorf("invalid alertmanager url %q: expected an absolute http or https url", alertmanagerURL)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid silence duration %s: expected a positive duration", duration)
+	}
+	if len(strings.TrimSpace(workloadLabel)) == 0 {
+		return nil, fmt.Errorf("the workload label of the silences is required")
+	}
+	if len(alertNames) == 0 {
+		return nil, fmt.Errorf("at least one alert name to silence is required")
+	}
+	for _, alertName := range alertNames {
+		if len(strings.TrimSpace(alertName)) == 0 {
+			return nil, fmt.Errorf("invalid empty alert name to silence")
+		}
+	}
+	return &AlertmanagerSilencer{
+		url:           alertmanagerURL,
+		httpClient:    &http.Client{Timeout: timeout},
+		duration:      duration,
+		workloadLabel: workloadLabel,
+		alertNames:    alertNames,
+		now:           time.Now,
+		silenced:      map[types.NamespacedName]activeSilence{},
+	}, nil
+}
+
+// Silence creates the silence of the capacity alerts of the workload for the duration, or extends the earlier one if
+// it's still active but ends sooner, so that the drop of the replicas after a late change, e.g. a rollback, is
+// silenced as long as the one after the first change.
+func (s *AlertmanagerSilencer) Silence(ctx context.Context, request Request) error {
+	workload := types.NamespacedName{Namespace: request.Namespace, Name: request.Workload}
+	now := s.now()
+	endsAt := now.Add(s.duration)
+	s.lock.Lock()
+	active, ok := s.silenced[workload]
+	s.lock.Unlock()
+	ok = ok && now.Before(active.endsAt)
+	if ok && !endsAt.After(active.endsAt) {
+		return nil
+	}
+
+	quoted := make([]string, 0, len(s.alertNames))
+	for _, alertName := range s.alertNames {
+		quoted = append(quoted, regexp.QuoteMeta(alertName))
+	}
+	newSilence := silence{
+		Matchers: []matcher{
+			{Name: "namespace", Value: request.Namespace, IsEqual: true},
+			{Name: s.workloadLabel, Value: request.Workload, IsEqual: true},
+			{Name: "alertname", Value: strings.Join(quoted, "|"), IsRegex: true, IsEqual: true},
+		},
+		StartsAt:  now.UTC(),
+		EndsAt:    endsAt.UTC(),
+		CreatedBy: createdBy,
+		Comment: fmt.Sprintf("The replicas of the %s %s/%s are expected to drop for a bit: %s", request.WorkloadKind,
+			request.Namespace, request.Workload, request.Reason),
+	}
+	if ok && len(active.id) > 0 {
+		// the start of an active silence is kept so that Alertmanager updates it in place
+		newSilence.ID, newSilence.StartsAt = active.id, active.startsAt.UTC()
+	}
+	id, err := s.post(ctx, newSilence)
+	if err != nil {
+		silenceErrorsCounter.WithLabelValues(request.Namespace).Inc()
+		return err
+	}
+	if len(newSilence.ID) == 0 {
+		silencesCounter.WithLabelValues(request.Namespace).Inc()
+	}
+
+	s.lock.Lock()
+	for silencedWorkload, silenced := range s.silenced {
+		if !now.Before(silenced.endsAt) {
+			delete(s.silenced, silencedWorkload)
+		}
+	}
+	s.silenced[workload] = activeSilence{id: id, startsAt: newSilence.StartsAt, endsAt: endsAt}
+	s.lock.Unlock()
+	return nil
+}
+
+// post creates the silence, or updates the silence of its ID, and returns the ID Alertmanager responds with.
+func (s *AlertmanagerSilencer) post(ctx context.Context, newSilence silence) (string, error) {
+	body, err := json.Marshal(newSilence)
+	if err != nil {
+		return "", err
+	}
+	silencesURL, err := url.JoinPath(s.url, silencesPath)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, silencesURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("alertmanager responded with status code %d", resp.StatusCode)
+	}
+	posted := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	// the silence is posted even if its ID can't be read, and the next change creates another one then
+	_ = json.NewDecoder(resp.Body).Decode(&posted)
+	return posted.SilenceID, nil
+}
@@ -0,0 +1,119 @@
+package silence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AlertmanagerSilencer", func() {
+	var (
+		server   *httptest.Server
+		silences []silence
+		status   int
+	)
+
+	BeforeEach(func() {
+		silences, status = nil, http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal(silencesPath))
+			created := silence{}
+			Expect(json.NewDecoder(r.Body).Decode(&created)).To(Succeed())
+			silences = append(silences, created)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"silenceID":"5d8e5d4b"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should silence the capacity alerts of the workload for the duration", func() {
+		now := time.Date(2023, 6, 15, 10, 0, 0, 0, time.UTC)
+		silencer, err := NewAlertmanagerSilencer(server.URL, time.Second, 30*time.Minute, "deployment",
+			"KubeDeploymentReplicasMismatch", "CheckoutCapacity.Low")
+		Expect(err).NotTo(HaveOccurred())
+		silencer.now = func() time.Time { return now }
+		request := Request{Namespace: "payments", WorkloadKind: "Deployment", Workload: "checkout", Reason: "min replicas cut"}
+
+		Expect(silencer.Silence(context.TODO(), request)).To(Succeed())
+		Expect(silences).To(HaveLen(1))
+		Expect(silences[0].Matchers).To(ConsistOf(
+			matcher{Name: "namespace", Value: "payments", IsEqual: true},
+			matcher{Name: "deployment", Value: "checkout", IsEqual: true},
+			matcher{Name: "alertname", Value: `KubeDeploymentReplicasMismatch|CheckoutCapacity\.Low`, IsRegex: true, IsEqual: true},
+		))
+		Expect(silences[0].StartsAt).To(Equal(now))
+		Expect(silences[0].EndsAt).To(Equal(now.Add(30 * time.Minute)))
+		Expect(silences[0].CreatedBy).To(Equal("ottoscalr"))
+		Expect(silences[0].ID).To(BeEmpty())
+
+		// the silence already covers a change at the same time
+		Expect(silencer.Silence(context.TODO(), request)).To(Succeed())
+		Expect(silences).To(HaveLen(1))
+	})
+
+	It("should extend the active silence of the workload on a later change", func() {
+		now := time.Date(2023, 6, 15, 10, 0, 0, 0, time.UTC)
+		silencer, err := NewAlertmanagerSilencer(server.URL, time.Second, 30*time.Minute, "deployment",
+			"KubeDeploymentReplicasMismatch")
+		Expect(err).NotTo(HaveOccurred())
+		silencer.now = func() time.Time { return now }
+		request := Request{Namespace: "payments", WorkloadKind: "Deployment", Workload: "checkout", Reason: "min replicas cut"}
+		Expect(silencer.Silence(context.TODO(), request)).To(Succeed())
+
+		// a rollback late in the silence is silenced for the whole duration too
+		now = now.Add(25 * time.Minute)
+		request.Reason = "rollback"
+		Expect(silencer.Silence(context.TODO(), request)).To(Succeed())
+		Expect(silences).To(HaveLen(2))
+		Expect(silences[1].ID).To(Equal("5d8e5d4b"))
+		Expect(silences[1].StartsAt).To(Equal(silences[0].StartsAt))
+		Expect(silences[1].EndsAt).To(Equal(now.Add(30 * time.Minute)))
+		Expect(silences[1].Comment).To(ContainSubstring("rollback"))
+
+		// another silence is created once it has ended
+		now = now.Add(30 * time.Minute)
+		Expect(silencer.Silence(context.TODO(), request)).To(Succeed())
+		Expect(silences).To(HaveLen(3))
+		Expect(silences[2].ID).To(BeEmpty())
+		Expect(silences[2].StartsAt).To(Equal(now))
+	})
+
+	It("should fail when alertmanager rejects the silence and retry it on the next change", func() {
+		silencer, err := NewAlertmanagerSilencer(server.URL, time.Second, 30*time.Minute, "deployment",
+			"KubeDeploymentReplicasMismatch")
+		Expect(err).NotTo(HaveOccurred())
+		request := Request{Namespace: "payments", WorkloadKind: "Deployment", Workload: "checkout"}
+
+		status = http.StatusBadRequest
+		Expect(silencer.Silence(context.TODO(), request)).NotTo(Succeed())
+		status = http.StatusOK
+		Expect(silencer.Silence(context.TODO(), request)).To(Succeed())
+		Expect(silences).To(HaveLen(2))
+		Expect(silences[1].Matchers).To(HaveLen(3))
+	})
+
+	It("should reject the silencers which could silence more than the capacity alerts of a workload", func() {
+		_, err := NewAlertmanagerSilencer(server.URL, time.Second, 30*time.Minute, "deployment")
+		Expect(err).To(MatchError(ContainSubstring("alert name")))
+		_, err = NewAlertmanagerSilencer(server.URL, time.Second, 30*time.Minute, "deployment", "")
+		Expect(err).To(HaveOccurred())
+		_, err = NewAlertmanagerSilencer(server.URL, time.Second, 30*time.Minute, "", "KubeDeploymentReplicasMismatch")
+		Expect(err).To(MatchError(ContainSubstring("workload label")))
+		_, err = NewAlertmanagerSilencer(server.URL, time.Second, 0, "deployment", "KubeDeploymentReplicasMismatch")
+		Expect(err).To(MatchError(ContainSubstring("duration")))
+		for _, alertmanagerURL := range []string{"", "alertmanager:9093", "ftp://alertmanager:9093", "http://"} {
+			_, err = NewAlertmanagerSilencer(alertmanagerURL, time.Second, 30*time.Minute, "deployment",
+				"KubeDeploymentReplicasMismatch")
+			Expect(err).To(MatchError(ContainSubstring("alertmanager url")))
+		}
+	})
+})
@@ -0,0 +1,13 @@
+package silence
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSilence(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Silence Suite")
+}
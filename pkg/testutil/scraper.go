@@ -0,0 +1,157 @@
+package testutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+)
+
+// Gap is a range of offsets from the start of a requested range which has no datapoints, like a metrics source
+// missing data.
+type Gap struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// ReplayWorkload is the traffic of a workload replayed by a ReplayScraper.
+type ReplayWorkload struct {
+	// Fixture is the traffic shape. Its timestamps are only used as offsets from the first one.
+	Fixture []metrics.DataPoint
+	ACL     time.Duration
+	Gaps    []Gap
+	// Capacity is the CPU available to the workload. The datapoints above the red line of it are the breaches.
+	Capacity float64
+}
+
+// ReplayScraper is a deterministic metrics.Scraper for integration tests. It replays the fixture of a workload from
+// the start of every requested range, over and over, so that a day of fixture fills a metric window of any length.
+// The value of the fixture is held until its next datapoint, so the fixture and the requested step can differ.
+type ReplayScraper struct {
+	mu              sync.RWMutex
+	workloads       map[string]ReplayWorkload
+	defaultWorkload *ReplayWorkload
+}
+
+func NewReplayScraper() *ReplayScraper {
+	return &ReplayScraper{workloads: make(map[string]ReplayWorkload)}
+}
+
+// WithWorkload replays the traffic for the workload.
+func (s *ReplayScraper) WithWorkload(namespace, workload string, replay ReplayWorkload) *ReplayScraper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workloads[namespace+"/"+workload] = replay
+	return s
+}
+
+// WithDefaultWorkload replays the traffic for all the workloads without their own.
+func (s *ReplayScraper) WithDefaultWorkload(replay ReplayWorkload) *ReplayScraper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaultWorkload = &replay
+	return s
+}
+
+func (s *ReplayScraper) getWorkload(namespace, workload string) (ReplayWorkload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if replay, ok := s.workloads[namespace+"/"+workload]; ok {
+		return replay, nil
+	}
+	if s.defaultWorkload != nil {
+		return *s.defaultWorkload, nil
+	}
+	return ReplayWorkload{}, errors.New("no traffic to replay for " + namespace + "/" + workload)
+}
+
+func (s *ReplayScraper) GetAverageCPUUtilizationByWorkload(namespace, workload string, start time.Time, end time.Time,
+	step time.Duration) ([]metrics.DataPoint, error) {
+	replay, err := s.getWorkload(namespace, workload)
+	if err != nil {
+		return nil, err
+	}
+	return replay.replay(start, end, step), nil
+}
+
+func (s *ReplayScraper) GetCPUUtilizationBreachDataPoints(namespace, workloadType, workload string,
+	redLineUtilization float64, start time.Time, end time.Time, step time.Duration) ([]metrics.DataPoint, error) {
+	replay, err := s.getWorkload(namespace, workload)
+	if err != nil {
+		return nil, err
+	}
+	var breaches []metrics.DataPoint
+	if replay.Capacity <= 0 {
+		return breaches, nil
+	}
+	for _, dp := range replay.replay(start, end, step) {
+		if dp.Value > replay.Capacity*redLineUtilization {
+			breaches = append(breaches, dp)
+		}
+	}
+	return breaches, nil
+}
+
+func (s *ReplayScraper) GetACLByWorkload(namespace, workload string) (time.Duration, error) {
+	replay, err := s.getWorkload(namespace, workload)
+	if err != nil {
+		return 0, err
+	}
+	return replay.ACL, nil
+}
+
+// replay returns the datapoints of the fixture at every step of [start, end] outside the gaps.
+func (r ReplayWorkload) replay(start, end time.Time, step time.Duration) []metrics.DataPoint {
+	if len(r.Fixture) == 0 || step <= 0 {
+		return nil
+	}
+	first := r.Fixture[0].Timestamp
+	period := r.Fixture[len(r.Fixture)-1].Timestamp.Sub(first)
+	if len(r.Fixture) > 1 {
+		// the last datapoint is held for as long as the others are, on average
+		period += period / time.Duration(len(r.Fixture)-1)
+	}
+	if period <= 0 {
+		period = step
+	}
+
+	dataPoints := make([]metrics.DataPoint, 0, int(end.Sub(start)/step)+1)
+	for t := start; !t.After(end); t = t.Add(step) {
+		offset := t.Sub(start)
+		if r.inGap(offset) {
+			continue
+		}
+		fixtureOffset := offset % period
+		i := sort.Search(len(r.Fixture), func(i int) bool {
+			return r.Fixture[i].Timestamp.Sub(first) > fixtureOffset
+		})
+		dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: t, Value: r.Fixture[i-1].Value})
+	}
+	return dataPoints
+}
+
+func (r ReplayWorkload) inGap(offset time.Duration) bool {
+	for _, gap := range r.Gaps {
+		if offset >= gap.From && offset < gap.To {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadFixture reads the datapoints of a fixture in testutil/fixtures, exported as JSON or CSV.
+func LoadFixture(name string) ([]metrics.DataPoint, error) {
+	_, file, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(file), "fixtures", name)
+	fixture, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fixture.Close()
+	return metrics.ReadDataPoints(fixture, metrics.IsCSV(path))
+}
@@ -0,0 +1,60 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReplayScraper", func() {
+	start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	fixture := []metrics.DataPoint{
+		{Timestamp: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), Value: 1},
+		{Timestamp: time.Date(2023, 6, 1, 0, 10, 0, 0, time.UTC), Value: 2},
+		{Timestamp: time.Date(2023, 6, 1, 0, 20, 0, 0, time.UTC), Value: 3},
+	}
+
+	It("should replay the fixture from the start of the range over and over", func() {
+		scraper := NewReplayScraper().WithWorkload("ns", "app", ReplayWorkload{Fixture: fixture, ACL: 3 * time.Minute})
+		dataPoints, err := scraper.GetAverageCPUUtilizationByWorkload("ns", "app", start, start.Add(time.Hour), 5*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataPoints).To(HaveLen(13))
+		values := make([]float64, 0, len(dataPoints))
+		for i, dp := range dataPoints {
+			Expect(dp.Timestamp).To(Equal(start.Add(time.Duration(i) * 5 * time.Minute)))
+			values = append(values, dp.Value)
+		}
+		Expect(values).To(Equal([]float64{1, 1, 2, 2, 3, 3, 1, 1, 2, 2, 3, 3, 1}))
+
+		acl, err := scraper.GetACLByWorkload("ns", "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(acl).To(Equal(3 * time.Minute))
+
+		_, err = scraper.GetACLByWorkload("ns", "other")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should leave out the gaps and report the breaches of the capacity", func() {
+		scraper := NewReplayScraper().WithDefaultWorkload(ReplayWorkload{Fixture: fixture, Capacity: 3,
+			Gaps: []Gap{{From: 10 * time.Minute, To: 20 * time.Minute}}})
+		dataPoints, err := scraper.GetAverageCPUUtilizationByWorkload("ns", "any", start, start.Add(30*time.Minute), 5*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataPoints).To(HaveLen(5))
+		Expect(dataPoints[2].Timestamp).To(Equal(start.Add(20 * time.Minute)))
+
+		breaches, err := scraper.GetCPUUtilizationBreachDataPoints("ns", "Deployment", "any", 0.8, start,
+			start.Add(30*time.Minute), 5*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(breaches).To(HaveLen(2))
+		Expect(breaches[0].Value).To(Equal(3.0))
+	})
+
+	It("should load the fixtures", func() {
+		dataPoints, err := LoadFixture("diurnal.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dataPoints).To(HaveLen(288))
+		Expect(dataPoints[240].Value).To(Equal(11.0))
+	})
+})
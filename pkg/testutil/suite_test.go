@@ -0,0 +1,13 @@
+package testutil
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTestUtil(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TestUtil Suite")
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/flipkart-incubator/ottoscalr"
+	serviceName = "ottoscalr"
+)
+
+// Tracer returns the tracer used to instrument ottoscalr. Until InitTracerProvider is called the global
+// tracer provider is a no-op one and the spans started from it are not recorded.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// DefaultSamplingRatio is the ratio of the traces sampled when none is configured.
+const DefaultSamplingRatio = 0.1
+
+// InitTracerProvider registers a global tracer provider which exports the spans to the OTLP gRPC endpoint
+// and samples the given ratio of the traces. The returned func flushes and shuts down the provider.
+func InitTracerProvider(ctx context.Context, endpoint string, insecure bool, samplingRatio float64) (func(context.Context) error, error) {
+	if samplingRatio <= 0 || samplingRatio > 1 {
+		return nil, fmt.Errorf("the sampling ratio %v isn't in (0, 1]", samplingRatio)
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider, err := newTracerProvider(exporter, samplingRatio)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tracerProvider.Shutdown, nil
+}
+
+// newTracerProvider returns a tracer provider which batches the spans of the sampled traces to the exporter.
+func newTracerProvider(exporter sdktrace.SpanExporter, samplingRatio float64) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRatio))),
+	), nil
+}
+
+// WorkloadAttributes returns the span attributes identifying a workload.
+func WorkloadAttributes(namespace, kind, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("ottoscalr.namespace", namespace),
+		attribute.String("ottoscalr.workload.kind", kind),
+		attribute.String("ottoscalr.workload.name", name),
+	}
+}
+
+// RecordError marks the span as failed with the error. It is a no-op for nil errors.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+var _ = Describe("Tracing", func() {
+	var exporter *tracetest.InMemoryExporter
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+	})
+
+	exportedSpans := func(samplingRatio float64, spans int) tracetest.SpanStubs {
+		tracerProvider, err := newTracerProvider(exporter, samplingRatio)
+		Expect(err).NotTo(HaveOccurred())
+		tracer := tracerProvider.Tracer(tracerName)
+		for i := 0; i < spans; i++ {
+			_, span := tracer.Start(context.TODO(), "HPAEnforcementController.Reconcile")
+			span.End()
+		}
+		Expect(tracerProvider.ForceFlush(context.TODO())).To(Succeed())
+		return exporter.GetSpans()
+	}
+
+	It("should export the spans of the sampled traces as the ottoscalr service", func() {
+		spans := exportedSpans(1, 3)
+		Expect(spans).To(HaveLen(3))
+		Expect(spans[0].Name).To(Equal("HPAEnforcementController.Reconcile"))
+		Expect(spans[0].Resource.Attributes()).To(ContainElement(semconv.ServiceName(serviceName)))
+	})
+
+	It("should sample the ratio of the traces", func() {
+		spans := exportedSpans(0.1, 1000)
+		Expect(len(spans)).To(BeNumerically("~", 100, 60))
+	})
+
+	It("should reject the sampling ratios which sample nothing or are out of range", func() {
+		for _, samplingRatio := range []float64{0, -0.5, 1.5} {
+			_, err := InitTracerProvider(context.TODO(), "localhost:4317", true, samplingRatio)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should mark the spans failed only on the errors", func() {
+		tracerProvider, err := newTracerProvider(exporter, 1)
+		Expect(err).NotTo(HaveOccurred())
+		tracer := tracerProvider.Tracer(tracerName)
+
+		_, succeeded := tracer.Start(context.TODO(), "succeeded")
+		RecordError(succeeded, nil)
+		succeeded.End()
+		_, failed := tracer.Start(context.TODO(), "failed")
+		RecordError(failed, errors.New("conflict updating the HPA"))
+		failed.End()
+		Expect(tracerProvider.ForceFlush(context.TODO())).To(Succeed())
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Status.Code).To(Equal(codes.Unset))
+		Expect(spans[0].Events).To(BeEmpty())
+		Expect(spans[1].Status.Code).To(Equal(codes.Error))
+		Expect(spans[1].Status.Description).To(Equal("conflict updating the HPA"))
+		Expect(spans[1].Events).To(HaveLen(1))
+		Expect(spans[1].Events[0].Name).To(Equal("exception"))
+	})
+
+	It("should identify the workloads by their attributes", func() {
+		attributes := WorkloadAttributes("default", "Deployment", "checkout")
+		Expect(attributes).To(HaveLen(3))
+		Expect(attributes[2].Value.AsString()).To(Equal("checkout"))
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
 	"github.com/go-logr/logr"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -28,18 +29,42 @@ func NewOutlierInterpolatorTransformer(eventIntegration []integration.EventInteg
 }
 
 func (ot *OutlierInterpolatorTransformer) Transform(startTime time.Time, endTime time.Time, dataPoints []metrics.DataPoint) ([]metrics.DataPoint, error) {
+	newDataPoints, _, err := ot.TransformExcluding(startTime, endTime, dataPoints)
+	return newDataPoints, err
+}
+
+// TransformExcluding transforms the datapoints like Transform and returns the outlier intervals of the events
+// interpolated over or dropped, along with the names of their events.
+func (ot *OutlierInterpolatorTransformer) TransformExcluding(startTime time.Time, endTime time.Time,
+	dataPoints []metrics.DataPoint) ([]metrics.DataPoint, []metrics.ExcludedInterval, error) {
 	var eventDetails []integration.EventDetails
 	for _, ei := range ot.EventIntegration {
 		events, err := ei.GetDesiredEvents(startTime, endTime)
 		if err != nil {
-			return nil, fmt.Errorf("error in getting events from event integration: %v", err)
+			return nil, nil, fmt.Errorf("error in getting events from event integration: %v", err)
 		}
 		eventDetails = append(eventDetails, events...)
 	}
 	intervals := getOutlierIntervals(eventDetails)
 	intervals = filterIntervals(intervals, startTime, endTime)
 	newDataPoints := ot.cleanOutliersAndInterpolate(dataPoints, intervals)
-	return newDataPoints, nil
+	return newDataPoints, excludedIntervals(intervals, eventDetails), nil
+}
+
+// excludedIntervals returns the outlier intervals as the excluded intervals, naming the events overlapping each of them.
+func excludedIntervals(intervals []OutlierInterval, eventDetails []integration.EventDetails) []metrics.ExcludedInterval {
+	var excluded []metrics.ExcludedInterval
+	for _, interval := range intervals {
+		var names []string
+		for _, event := range eventDetails {
+			if event.StartTime.Before(interval.EndTime) && event.EndTime.After(interval.StartTime) {
+				names = append(names, event.EventName)
+			}
+		}
+		excluded = append(excluded, metrics.ExcludedInterval{Start: interval.StartTime, End: interval.EndTime,
+			Reason: strings.Join(names, ", ")})
+	}
+	return excluded
 }
 
 func getOutlierIntervals(eventDetails []integration.EventDetails) []OutlierInterval {
@@ -88,12 +113,10 @@ func filterIntervals(intervals []OutlierInterval, start time.Time, end time.Time
 	return nonOverLappingInterval
 }
 
-// CleanOutliersAndInterpolate - Linear Interpolation for the dataPoints in interval range.
+// CleanOutliersAndInterpolate - Linear Interpolation for the dataPoints in interval range. The dataPoints are
+// interpolated in place and the outliers at either end are dropped by reslicing.
 func (ot *OutlierInterpolatorTransformer) cleanOutliersAndInterpolate(dataPoints []metrics.DataPoint, intervals []OutlierInterval) []metrics.DataPoint {
-	var newDataPoints []metrics.DataPoint
-	for _, dataPoint := range dataPoints {
-		newDataPoints = append(newDataPoints, dataPoint)
-	}
+	newDataPoints := dataPoints
 	for _, interval := range intervals {
 		ot.logger.V(2).Info("Interpolating for interval: ", "start", interval.StartTime, "end", interval.EndTime)
 		startIndex := -1
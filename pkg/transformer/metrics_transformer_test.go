@@ -157,3 +157,18 @@ var _ = Describe("Transform", func() {
 		Expect(math.Floor(newDataPoints[12].Value*100) / 100).To(Equal(51.42))
 	})
 })
+
+var _ = Describe("TransformExcluding", func() {
+	It("Should report the outlier intervals along with the names of their events", func() {
+		var dataPoints []metrics.DataPoint
+		for i := 30; i > 5; i-- {
+			dataPoints = append(dataPoints, metrics.DataPoint{Timestamp: time.Now().Add(-time.Duration(i) * time.Minute), Value: 50})
+		}
+		_, excluded, err := outlierInterpolatorTransformer.TransformExcluding(time.Now().Add(-50*time.Minute), time.Now(), dataPoints)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(excluded).To(HaveLen(2))
+		Expect(excluded[0].Reason).To(Equal("event1"))
+		Expect(excluded[0].End.Sub(excluded[0].Start)).To(BeNumerically("~", 6*time.Minute, time.Second))
+		Expect(excluded[1].Reason).To(Equal("nfr"))
+	})
+})
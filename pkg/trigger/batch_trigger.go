@@ -0,0 +1,70 @@
+package trigger
+
+import (
+	"context"
+
+	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	p8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	batchRetriggeredCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "policyreco_batch_retriggered_count",
+			Help: "Number of PolicyRecommendations queued for a fresh recommendation by a batch re-trigger"}, []string{"namespace"},
+	)
+)
+
+func init() {
+	p8smetrics.Registry.MustRegister(batchRetriggeredCounter)
+}
+
+// BatchTrigger queues the PolicyRecommendations of all the workloads matching a selector for a fresh
+// recommendation, e.g. after the policies, the redline utilization or the resource limits are changed en masse.
+type BatchTrigger struct {
+	k8sClient         client.Client
+	clientsRegistry   registry.DeploymentClientRegistry
+	queueForExecution func(types.NamespacedName)
+}
+
+func NewBatchTrigger(k8sClient client.Client, clientsRegistry registry.DeploymentClientRegistry,
+	queueForExecution func(types.NamespacedName)) *BatchTrigger {
+	return &BatchTrigger{
+		k8sClient:         k8sClient,
+		clientsRegistry:   clientsRegistry,
+		queueForExecution: queueForExecution,
+	}
+}
+
+// QueueMatchingForExecution queues the PolicyRecommendations of the workloads in the namespace matching the
+// selector and returns them. An empty namespace matches the workloads in all the namespaces. Workloads without a
+// PolicyRecommendation are skipped.
+func (b *BatchTrigger) QueueMatchingForExecution(ctx context.Context, namespace string,
+	selector labels.Selector) ([]types.NamespacedName, error) {
+	var queued []types.NamespacedName
+	for _, objectClient := range b.clientsRegistry.Clients {
+		workloads, err := objectClient.GetObjectList(namespace, selector)
+		if err != nil {
+			return queued, err
+		}
+		for _, workload := range workloads {
+			policyreco := types.NamespacedName{Namespace: workload.GetNamespace(), Name: workload.GetName()}
+			if err := b.k8sClient.Get(ctx, policyreco, &ottoscaleriov1alpha1.PolicyRecommendation{}); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return queued, err
+			}
+			b.queueForExecution(policyreco)
+			batchRetriggeredCounter.WithLabelValues(policyreco.Namespace).Inc()
+			queued = append(queued, policyreco)
+		}
+	}
+	return queued, nil
+}
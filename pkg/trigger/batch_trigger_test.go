@@ -0,0 +1,72 @@
+package trigger
+
+import (
+	"context"
+
+	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
+	"github.com/flipkart-incubator/ottoscalr/pkg/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("BatchTrigger", func() {
+	var (
+		batchTrigger *BatchTrigger
+		queued       []types.NamespacedName
+	)
+
+	newDeployment := func(namespace, name string, workloadLabels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: workloadLabels}}
+	}
+	newPolicyReco := func(namespace, name string) *ottoscaleriov1alpha1.PolicyRecommendation {
+		return &ottoscaleriov1alpha1.PolicyRecommendation{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	BeforeEach(func() {
+		queued = nil
+		fakeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(fakeScheme)).To(Succeed())
+		Expect(ottoscaleriov1alpha1.AddToScheme(fakeScheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			newDeployment("ns1", "payments-api", map[string]string{"team": "payments"}),
+			newDeployment("ns1", "payments-worker", map[string]string{"team": "payments"}),
+			newDeployment("ns1", "checkout", map[string]string{"team": "checkout"}),
+			newDeployment("ns2", "payments-api", map[string]string{"team": "payments"}),
+			newPolicyReco("ns1", "payments-api"),
+			newPolicyReco("ns1", "checkout"),
+			newPolicyReco("ns2", "payments-api"),
+		).Build()
+		clientsRegistry := registry.NewDeploymentClientRegistryBuilder().
+			WithCustomDeploymentClient(registry.NewDeploymentClient(fakeClient)).Build()
+		batchTrigger = NewBatchTrigger(fakeClient, *clientsRegistry, func(policyreco types.NamespacedName) {
+			queued = append(queued, policyreco)
+		})
+	})
+
+	It("should queue the recommendations of the workloads matching the selector in the namespace", func() {
+		selector, err := labels.Parse("team=payments")
+		Expect(err).NotTo(HaveOccurred())
+		result, err := batchTrigger.QueueMatchingForExecution(context.TODO(), "ns1", selector)
+		Expect(err).NotTo(HaveOccurred())
+		// payments-worker doesn't have a PolicyRecommendation
+		Expect(result).To(Equal([]types.NamespacedName{{Namespace: "ns1", Name: "payments-api"}}))
+		Expect(queued).To(Equal(result))
+	})
+
+	It("should queue the recommendations across the namespaces for an empty namespace", func() {
+		result, err := batchTrigger.QueueMatchingForExecution(context.TODO(), "", labels.Everything())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(ConsistOf(
+			types.NamespacedName{Namespace: "ns1", Name: "payments-api"},
+			types.NamespacedName{Namespace: "ns1", Name: "checkout"},
+			types.NamespacedName{Namespace: "ns2", Name: "payments-api"},
+		))
+	})
+})
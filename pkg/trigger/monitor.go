@@ -2,13 +2,18 @@ package trigger
 
 import (
 	"context"
+	"fmt"
 	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
 	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/robfig/cron/v3"
 	"golang.org/x/sync/semaphore"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"math/rand"
@@ -38,10 +43,21 @@ var (
 		prometheus.CounterOpts{Name: "breachmonitor_execution_rate",
 			Help: "Rate of breach monitor executions"}, []string{},
 	)
+
+	postEnforcementBreachCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "breachmonitor_post_enforcement_breach_count",
+			Help: "Number of times the workload started breaching the redline utilization after the recommendation is enforced"}, []string{"namespace", "workload", "policy"},
+	)
+
+	postEnforcementMaxReplicaSaturationCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{Name: "breachmonitor_post_enforcement_max_replica_saturation_count",
+			Help: "Number of times the workload reached the max replicas of the enforced recommendation"}, []string{"namespace", "workload", "policy"},
+	)
 )
 
 func init() {
-	p8smetrics.Registry.MustRegister(breachGauge, timeToMitigateLatency, concurrentBreachMonitorExecutions, breachMonitorExecutionRate)
+	p8smetrics.Registry.MustRegister(breachGauge, timeToMitigateLatency, concurrentBreachMonitorExecutions, breachMonitorExecutionRate,
+		postEnforcementBreachCounter, postEnforcementMaxReplicaSaturationCounter)
 }
 
 const (
@@ -67,6 +83,8 @@ type PolicyRecommendationMonitorManager struct {
 	metricStep                  time.Duration
 	cpuRedLine                  float64
 	periodicRequeueFrequency    time.Duration
+	requeueSchedule             cron.Schedule
+	location                    *time.Location
 	breachCheckFrequency        time.Duration
 	concurrencyControlSemaphore *semaphore.Weighted
 	handlerFunc                 func(workloadName types.NamespacedName)
@@ -106,6 +124,23 @@ func NewPolicyRecommendationMonitorManager(k8sClient client.Client,
 	}
 }
 
+// WithRequeueSchedule requeues the recommendations on the cron schedule instead of the periodic requeue frequency.
+func (mf *PolicyRecommendationMonitorManager) WithRequeueSchedule(schedule cron.Schedule) *PolicyRecommendationMonitorManager {
+	mf.requeueSchedule = schedule
+	return mf
+}
+
+// WithLocation evaluates the cron schedules in the location instead of the local timezone.
+func (mf *PolicyRecommendationMonitorManager) WithLocation(location *time.Location) *PolicyRecommendationMonitorManager {
+	mf.location = location
+	return mf
+}
+
+// ParseSchedule parses a standard 5 field cron expression, or a descriptor like @daily.
+func ParseSchedule(spec string) (cron.Schedule, error) {
+	return cron.ParseStandard(spec)
+}
+
 func (mf *PolicyRecommendationMonitorManager) RegisterMonitor(workloadType string,
 	workload types.NamespacedName) *Monitor {
 
@@ -129,6 +164,8 @@ func (mf *PolicyRecommendationMonitorManager) RegisterMonitor(workloadType strin
 		mf.concurrencyControlSemaphore,
 		mf.handlerFunc,
 		mf.logger)
+	monitor.requeueSchedule = mf.requeueSchedule
+	monitor.location = mf.location
 
 	mf.monitors[workload.String()] = monitor
 	monitor.Start()
@@ -165,6 +202,8 @@ type Monitor struct {
 	cpuRedLine                  float64
 	metricStep                  time.Duration
 	periodicRequeueFrequency    time.Duration
+	requeueSchedule             cron.Schedule
+	location                    *time.Location
 	breachCheckFrequency        time.Duration
 	concurrencyControlSemaphore *semaphore.Weighted
 	handlerFunc                 func(workload types.NamespacedName)
@@ -172,6 +211,9 @@ type Monitor struct {
 	cancel                      context.CancelFunc
 	wg                          sync.WaitGroup
 	logger                      logr.Logger
+	// atMaxReplicas is whether the last breach check found the workload at the max replicas of the enforced
+	// recommendation, so that only the checks reaching them are counted.
+	atMaxReplicas bool
 }
 
 func NewMonitor(k8sClient client.Client,
@@ -213,7 +255,7 @@ func (m *Monitor) Start() {
 	go m.monitorBreaches()
 
 	m.wg.Add(1)
-	go m.requeueAfterFixedInterval()
+	go m.requeuePeriodically()
 }
 
 func (m *Monitor) monitorBreaches() {
@@ -270,6 +312,7 @@ func (m *Monitor) monitorBreaches() {
 			//TODO: Handle Error
 			breached, _ := HasBreached(log.IntoContext(context.Background(), m.logger), start, end, m.workloadType, m.workload, m.metricScraper, m.cpuRedLine, m.metricStep)
 			m.concurrencyControlSemaphore.Release(1)
+			m.recordPostEnforcementSaturation(m.ctx, policyreco, breached, breachedInPast)
 			if breached {
 				m.recorder.Event(&policyreco, eventTypeWarning, "BreachDetected", "A breach has been detected for the current policy")
 				if !breachedInPast {
@@ -296,6 +339,70 @@ func (m *Monitor) monitorBreaches() {
 	}
 }
 
+// recordPostEnforcementSaturation counts the breaches and the max replica saturation observed while the recommended
+// HPA configuration is enforced on the workload, so that the safety of the policies can be quantified. Like the
+// HasBreached condition, only the checks which start a breach or reach the max replicas are counted, so that the
+// counts don't depend on how often the workload is checked.
+func (m *Monitor) recordPostEnforcementSaturation(ctx context.Context, policyreco ottoscaleriov1alpha1.PolicyRecommendation,
+	breached bool, breachedInPast bool) {
+	if !isHPAEnforced(policyreco.Status.Conditions) {
+		m.atMaxReplicas = false
+		return
+	}
+	if breached && !breachedInPast {
+		postEnforcementBreachCounter.WithLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.Policy).Inc()
+	}
+	atMaxReplicas, err := m.isAtMaxReplicas(ctx, policyreco)
+	if err != nil {
+		m.logger.Error(err, "Error while checking the replicas of the workload.", "workload", m.workload)
+		return
+	}
+	if atMaxReplicas && !m.atMaxReplicas {
+		postEnforcementMaxReplicaSaturationCounter.WithLabelValues(policyreco.Namespace, policyreco.Spec.WorkloadMeta.Name, policyreco.Spec.Policy).Inc()
+	}
+	m.atMaxReplicas = atMaxReplicas
+}
+
+// isAtMaxReplicas checks if the workload is running at the max replicas of the enforced HPA configuration. The
+// workload is read as its typed object so that it's served from the cache of the client.
+func (m *Monitor) isAtMaxReplicas(ctx context.Context, policyreco ottoscaleriov1alpha1.PolicyRecommendation) (bool, error) {
+	if policyreco.Spec.CurrentHPAConfiguration.Max <= 0 {
+		return false, nil
+	}
+	obj, err := m.k8sClient.Scheme().New(schema.FromAPIVersionAndKind(policyreco.Spec.WorkloadMeta.APIVersion, policyreco.Spec.WorkloadMeta.Kind))
+	if err != nil {
+		return false, err
+	}
+	workload, ok := obj.(client.Object)
+	if !ok {
+		return false, fmt.Errorf("the workload kind %s isn't an object", policyreco.Spec.WorkloadMeta.Kind)
+	}
+	if err := m.k8sClient.Get(ctx, types.NamespacedName{
+		Namespace: policyreco.Namespace,
+		Name:      policyreco.Spec.WorkloadMeta.Name,
+	}, workload); err != nil {
+		return false, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workload)
+	if err != nil {
+		return false, err
+	}
+	replicas, _, err := unstructured.NestedInt64(content, "status", "replicas")
+	if err != nil {
+		return false, err
+	}
+	return int(replicas) >= policyreco.Spec.CurrentHPAConfiguration.Max, nil
+}
+
+func isHPAEnforced(conditions []metav1.Condition) bool {
+	for _, condition := range conditions {
+		if condition.Type == string(ottoscaleriov1alpha1.HPAEnforced) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func HasBreached(ctx context.Context, start, end time.Time, workloadType string,
 	workload types.NamespacedName,
 	metricScraper metrics.Scraper,
@@ -322,25 +429,62 @@ func HasBreached(ctx context.Context, start, end time.Time, workloadType string,
 	return false, nil
 }
 
-func (m *Monitor) requeueAfterFixedInterval() {
+func (m *Monitor) requeuePeriodically() {
 	defer m.wg.Done()
 
 	m.logger.Info("Starting the periodic check routine.")
 	//Add a jitter of 10%
 	jitter := time.Duration(rand.Int63n(int64(m.periodicRequeueFrequency) / 10))
-	queueTicker := time.NewTicker(m.periodicRequeueFrequency + jitter)
+	queueTimer := time.NewTimer(m.nextRequeueTime(time.Now(), m.getScheduleAnnotation(), jitter).Sub(time.Now()))
 
-	defer queueTicker.Stop()
+	defer queueTimer.Stop()
 
 	for {
 		select {
 		case <-m.ctx.Done():
 			return
-		case <-queueTicker.C:
+		case <-queueTimer.C:
 			m.logger.Info("Executing the periodic check routine.")
 			m.handlerFunc(m.workload)
+			queueTimer.Reset(m.nextRequeueTime(time.Now(), m.getScheduleAnnotation(), jitter).Sub(time.Now()))
+		}
+	}
+}
+
+// nextRequeueTime returns when the recommendation is requeued next, on the schedule of the annotation if it's set, or
+// else on the default schedule, or else after the periodic requeue frequency.
+func (m *Monitor) nextRequeueTime(now time.Time, scheduleAnnotation string, jitter time.Duration) time.Time {
+	schedule := m.requeueSchedule
+	if len(scheduleAnnotation) > 0 {
+		annotatedSchedule, err := ParseSchedule(scheduleAnnotation)
+		if err != nil {
+			m.logger.Error(err, "Invalid recommendation schedule. Falling back to the default.", "workload", m.workload,
+				"schedule", scheduleAnnotation)
+		} else {
+			schedule = annotatedSchedule
+		}
+	}
+	if schedule != nil {
+		if m.location != nil {
+			now = now.In(m.location)
+		}
+		// a schedule which never fires, like the 30th of February, falls back to the periodic requeue
+		if next := schedule.Next(now); !next.IsZero() {
+			return next
+		}
+	}
+	return now.Add(m.periodicRequeueFrequency + jitter)
+}
+
+func (m *Monitor) getScheduleAnnotation() string {
+	policyreco := ottoscaleriov1alpha1.PolicyRecommendation{}
+	if err := m.k8sClient.Get(m.ctx, m.workload, &policyreco); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			m.logger.Error(err, "Error while getting policyRecommendation.", "workload", m.workload)
 		}
+		return ""
 	}
+	return policyreco.GetAnnotations()[ottoscaleriov1alpha1.RecommendationScheduleAnnotation]
 }
 
 func (m *Monitor) Stop() {
@@ -1,11 +1,17 @@
 package trigger
 
 import (
+	"context"
 	"fmt"
 	ottoscaleriov1alpha1 "github.com/flipkart-incubator/ottoscalr/api/v1alpha1"
 	"github.com/flipkart-incubator/ottoscalr/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sync/atomic"
 	"time"
@@ -154,6 +160,55 @@ var _ = Describe("PolicyRecommendationMonitorManager and Monitor", func() {
 	})
 })
 
+var _ = Describe("Monitor requeue schedule", func() {
+	var (
+		monitor *Monitor
+		now     = time.Date(2023, 6, 15, 14, 10, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		monitor = &Monitor{
+			workload:                 types.NamespacedName{Name: "test-workload", Namespace: "default"},
+			periodicRequeueFrequency: 6 * time.Hour,
+			logger:                   zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)),
+		}
+	})
+
+	It("should requeue after the periodic requeue frequency without a schedule", func() {
+		Expect(monitor.nextRequeueTime(now, "", 10*time.Minute)).To(Equal(now.Add(6*time.Hour + 10*time.Minute)))
+	})
+
+	It("should requeue on the default schedule", func() {
+		schedule, err := ParseSchedule("0 2 * * *")
+		Expect(err).NotTo(HaveOccurred())
+		monitor.requeueSchedule = schedule
+		Expect(monitor.nextRequeueTime(now, "", 10*time.Minute)).To(Equal(time.Date(2023, 6, 16, 2, 0, 0, 0, time.UTC)))
+	})
+
+	It("should prefer the schedule of the annotation over the default", func() {
+		schedule, err := ParseSchedule("0 2 * * *")
+		Expect(err).NotTo(HaveOccurred())
+		monitor.requeueSchedule = schedule
+		Expect(monitor.nextRequeueTime(now, "30 3 * * 0", 0)).To(Equal(time.Date(2023, 6, 18, 3, 30, 0, 0, time.UTC)))
+	})
+
+	It("should fall back to the default on an invalid annotation", func() {
+		Expect(monitor.nextRequeueTime(now, "every night", 0)).To(Equal(now.Add(6 * time.Hour)))
+	})
+
+	It("should evaluate the schedule in the location", func() {
+		ist := time.FixedZone("IST", 5*60*60+30*60)
+		monitor.location = ist
+		next := monitor.nextRequeueTime(now, "0 2 * * *", 0)
+		// 14:10 UTC is 19:40 IST
+		Expect(next).To(BeTemporally("==", time.Date(2023, 6, 16, 2, 0, 0, 0, ist)))
+	})
+
+	It("should fall back to the periodic requeue when the schedule never fires", func() {
+		Expect(monitor.nextRequeueTime(now, "0 0 30 2 *", 0)).To(Equal(now.Add(6 * time.Hour)))
+	})
+})
+
 func createPolicyReco(name, namespace, policy string) error {
 	now := metav1.Now()
 	return k8sClient.Create(ctx, &ottoscaleriov1alpha1.PolicyRecommendation{
@@ -170,3 +225,69 @@ func createPolicyReco(name, namespace, policy string) error {
 		},
 	})
 }
+
+var _ = Describe("Monitor max replica saturation", func() {
+	newMonitor := func(replicas, statusReplicas int32) *Monitor {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{Replicas: statusReplicas},
+		}
+		return &Monitor{k8sClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment).Build()}
+	}
+	policyreco := ottoscaleriov1alpha1.PolicyRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workload", Namespace: "default"},
+		Spec: ottoscaleriov1alpha1.PolicyRecommendationSpec{
+			WorkloadMeta: ottoscaleriov1alpha1.WorkloadMeta{Name: "test-workload",
+				TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}},
+			CurrentHPAConfiguration: ottoscaleriov1alpha1.HPAConfiguration{Min: 2, Max: 10},
+		},
+	}
+
+	It("should compare the replicas the workload runs rather than its desired replicas with the enforced max", func() {
+		atMax, err := newMonitor(10, 6).isAtMaxReplicas(context.TODO(), policyreco)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(atMax).To(BeFalse())
+
+		atMax, err = newMonitor(10, 10).isAtMaxReplicas(context.TODO(), policyreco)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(atMax).To(BeTrue())
+	})
+
+	It("should count the breaches and the saturation of the enforced workloads once per episode", func() {
+		enforced := *policyreco.DeepCopy()
+		enforced.Name, enforced.Spec.WorkloadMeta.Name, enforced.Spec.Policy = "saturated-workload", "test-workload", "saturated-policy"
+		enforced.Status.Conditions = []metav1.Condition{{Type: string(ottoscaleriov1alpha1.HPAEnforced), Status: metav1.ConditionTrue}}
+		breaches := func() float64 {
+			return testutil.ToFloat64(postEnforcementBreachCounter.WithLabelValues("default", "test-workload", "saturated-policy"))
+		}
+		saturations := func() float64 {
+			return testutil.ToFloat64(postEnforcementMaxReplicaSaturationCounter.WithLabelValues("default", "test-workload", "saturated-policy"))
+		}
+
+		monitor := newMonitor(10, 10)
+		monitor.recordPostEnforcementSaturation(context.TODO(), enforced, true, false)
+		monitor.recordPostEnforcementSaturation(context.TODO(), enforced, true, true)
+		monitor.recordPostEnforcementSaturation(context.TODO(), enforced, true, true)
+		Expect(breaches()).To(Equal(1.0))
+		Expect(saturations()).To(Equal(1.0))
+
+		// the workload scales in and reaches the max replicas again
+		monitor.k8sClient = newMonitor(10, 6).k8sClient
+		monitor.recordPostEnforcementSaturation(context.TODO(), enforced, false, true)
+		monitor.k8sClient = newMonitor(10, 10).k8sClient
+		monitor.recordPostEnforcementSaturation(context.TODO(), enforced, true, false)
+		monitor.recordPostEnforcementSaturation(context.TODO(), enforced, true, true)
+		Expect(breaches()).To(Equal(2.0))
+		Expect(saturations()).To(Equal(2.0))
+	})
+
+	It("should fail on the workloads which aren't found", func() {
+		missing := *policyreco.DeepCopy()
+		missing.Spec.WorkloadMeta.Name = "missing-workload"
+		_, err := newMonitor(10, 10).isAtMaxReplicas(context.TODO(), missing)
+		Expect(err).To(HaveOccurred())
+	})
+})
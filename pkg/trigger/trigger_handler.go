@@ -23,14 +23,20 @@ type Handler interface {
 }
 type K8sTriggerHandler struct {
 	k8sClient            client.Client
-	queuedForExecutionCh chan types.NamespacedName
+	queuedForExecutionCh chan queueRequest
 	logger               logr.Logger
 }
 
+// queueRequest is a PolicyRecommendation queued for execution, along with whether a user queued it.
+type queueRequest struct {
+	recommendation types.NamespacedName
+	interactive    bool
+}
+
 func NewK8sTriggerHandler(k8sClient client.Client, logger logr.Logger) *K8sTriggerHandler {
 	return &K8sTriggerHandler{
 		k8sClient:            k8sClient,
-		queuedForExecutionCh: make(chan types.NamespacedName),
+		queuedForExecutionCh: make(chan queueRequest),
 		logger:               logger,
 	}
 }
@@ -40,7 +46,13 @@ func (h *K8sTriggerHandler) Start() {
 }
 
 func (h *K8sTriggerHandler) QueueForExecution(recommendation types.NamespacedName) {
-	h.queuedForExecutionCh <- recommendation
+	h.queuedForExecutionCh <- queueRequest{recommendation: recommendation}
+}
+
+// QueueInteractiveForExecution queues the PolicyRecommendation for execution on behalf of a user, ahead of the
+// routine regenerations.
+func (h *K8sTriggerHandler) QueueInteractiveForExecution(recommendation types.NamespacedName) {
+	h.queuedForExecutionCh <- queueRequest{recommendation: recommendation, interactive: true}
 }
 
 // TODO: @neerajb Handle passing error back to the controllers, so that the reconcile can be run again.
@@ -51,13 +63,14 @@ func (h *K8sTriggerHandler) QueueAllForExecution() {
 	}
 	for _, reco := range allRecommendations.Items {
 		h.logger.V(0).Info("Queuing policy recommendation for execution", "name", reco.Name, "namespace", reco.Namespace)
-		h.queuedForExecutionCh <- types.NamespacedName{Name: reco.GetName(), Namespace: reco.GetNamespace()}
+		h.queuedForExecutionCh <- queueRequest{recommendation: types.NamespacedName{Name: reco.GetName(), Namespace: reco.GetNamespace()}}
 	}
 }
 
 func (h *K8sTriggerHandler) queuePolicyRecommendations() {
 	TRUE := true
-	for workload := range h.queuedForExecutionCh {
+	for request := range h.queuedForExecutionCh {
+		workload := request.recommendation
 		now := metav1.Now()
 		policyRecommendation := &ottoscaleriov1alpha1.PolicyRecommendation{}
 
@@ -72,6 +85,7 @@ func (h *K8sTriggerHandler) queuePolicyRecommendations() {
 
 		policyRecommendation.Spec.QueuedForExecution = &TRUE
 		policyRecommendation.Spec.QueuedForExecutionAt = &now
+		SetQueuePriority(policyRecommendation, request.interactive)
 
 		err = h.k8sClient.Update(context.TODO(), policyRecommendation, client.FieldOwner(TRIGGER_HANDLER_K8S))
 		if err != nil {
@@ -107,6 +121,19 @@ func (h *K8sTriggerHandler) queuePolicyRecommendations() {
 	}
 }
 
+// SetQueuePriority marks the PolicyRecommendation as queued by a user if interactive, and as queued by a routine
+// trigger otherwise.
+func SetQueuePriority(policyRecommendation *ottoscaleriov1alpha1.PolicyRecommendation, interactive bool) {
+	if !interactive {
+		delete(policyRecommendation.Annotations, ottoscaleriov1alpha1.QueuePriorityAnnotation)
+		return
+	}
+	if policyRecommendation.Annotations == nil {
+		policyRecommendation.Annotations = map[string]string{}
+	}
+	policyRecommendation.Annotations[ottoscaleriov1alpha1.QueuePriorityAnnotation] = ottoscaleriov1alpha1.QueuePriorityInteractive
+}
+
 func getSubresourcePatchOptions(fieldOwner string) *client.SubResourcePatchOptions {
 	patchOpts := client.PatchOptions{}
 	client.ForceOwnership.ApplyToPatch(&patchOpts)